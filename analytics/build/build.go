@@ -65,6 +65,7 @@ type enabledAnalytics map[string]analytics.Module
 func (ea enabledAnalytics) LogAuctionObject(ao *analytics.AuctionObject, ac privacy.ActivityControl) {
 	for name, module := range ea {
 		if isAllowed, cloneBidderReq := evaluateActivities(ao.RequestWrapper, ac, name); isAllowed {
+			ao.PrivacyAudit = privacyAudit(ao.PrivacyAudit, ac)
 			if cloneBidderReq != nil {
 				ao.RequestWrapper = cloneBidderReq
 			}
@@ -80,6 +81,7 @@ func (ea enabledAnalytics) LogAuctionObject(ao *analytics.AuctionObject, ac priv
 func (ea enabledAnalytics) LogVideoObject(vo *analytics.VideoObject, ac privacy.ActivityControl) {
 	for name, module := range ea {
 		if isAllowed, cloneBidderReq := evaluateActivities(vo.RequestWrapper, ac, name); isAllowed {
+			vo.PrivacyAudit = privacyAudit(vo.PrivacyAudit, ac)
 			if cloneBidderReq != nil {
 				vo.RequestWrapper = cloneBidderReq
 			}
@@ -108,6 +110,7 @@ func (ea enabledAnalytics) LogSetUIDObject(so *analytics.SetUIDObject) {
 func (ea enabledAnalytics) LogAmpObject(ao *analytics.AmpObject, ac privacy.ActivityControl) {
 	for name, module := range ea {
 		if isAllowed, cloneBidderReq := evaluateActivities(ao.RequestWrapper, ac, name); isAllowed {
+			ao.PrivacyAudit = privacyAudit(ao.PrivacyAudit, ac)
 			if cloneBidderReq != nil {
 				ao.RequestWrapper = cloneBidderReq
 			}
@@ -156,16 +159,27 @@ func evaluateActivities(rw *openrtb_ext.RequestWrapper, ac privacy.ActivityContr
 
 	if blockUserFPD {
 		privacy.ScrubUserFPD(cloneReq)
+		ac.Scrubbed(privacy.ActivityTransmitUserFPD, component, privacy.ScrubberUserFPD, privacy.ScrubReasonActivity)
 	}
 	if blockPreciseGeo {
 		ipConf := privacy.IPConf{IPV6: ac.IPv6Config, IPV4: ac.IPv4Config}
 		privacy.ScrubGeoAndDeviceIP(cloneReq, ipConf)
+		ac.Scrubbed(privacy.ActivityTransmitPreciseGeo, component, privacy.ScrubberGeoAndDeviceIP, privacy.ScrubReasonActivity)
 	}
 
 	cloneReq.RebuildRequest()
 	return true, cloneReq
 }
 
+// privacyAudit returns the evaluations recorded by the activity control, so the audit given to a module holds
+// the evaluations and scrubs made for it, or the audit unchanged when the activity control doesn't record them.
+func privacyAudit(audit []privacy.ActivityEvaluation, ac privacy.ActivityControl) []privacy.ActivityEvaluation {
+	if ac.Log() == nil {
+		return audit
+	}
+	return ac.Log().Evaluations()
+}
+
 func updateReqWrapperForAnalytics(rw *openrtb_ext.RequestWrapper, adapterName string, isCloned bool) *openrtb_ext.RequestWrapper {
 	if rw == nil {
 		return nil
@@ -349,6 +349,49 @@ func TestEvaluateActivities(t *testing.T) {
 
 }
 
+func TestLogObjectPrivacyAudit(t *testing.T) {
+	var count int
+	am := initAnalytics(&count)
+	ac := privacy.NewActivityControl(getActivityConfig("sampleModule", true, false, false)).WithLog(&privacy.ActivityLog{})
+	ao := &analytics.AuctionObject{RequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: getDefaultBidRequest()}}
+
+	am.LogAuctionObject(ao, ac)
+
+	component := privacy.Component{Type: privacy.ComponentTypeAnalytics, Name: "sampleModule"}
+	scrubbers := make(map[string][]privacy.Scrubber)
+	for _, evaluation := range ao.PrivacyAudit {
+		assert.Equal(t, component.Type, evaluation.ComponentType)
+		assert.Equal(t, component.Name, evaluation.ComponentName)
+		scrubbers[evaluation.Activity] = evaluation.Scrubbers
+	}
+	assert.Equal(t, map[string][]privacy.Scrubber{
+		privacy.ActivityReportAnalytics.String():    nil,
+		privacy.ActivityTransmitUserFPD.String():    {{Name: privacy.ScrubberUserFPD, Reason: privacy.ScrubReasonActivity}},
+		privacy.ActivityTransmitPreciseGeo.String(): {{Name: privacy.ScrubberGeoAndDeviceIP, Reason: privacy.ScrubReasonActivity}},
+	}, scrubbers)
+}
+
+func TestLogAmpObjectPrivacyAudit(t *testing.T) {
+	var count int
+	am := initAnalytics(&count)
+	ac := privacy.NewActivityControl(getActivityConfig("sampleModule", true, true, true)).WithLog(&privacy.ActivityLog{})
+	ao := &analytics.AmpObject{RequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: getDefaultBidRequest()}}
+
+	am.LogAmpObject(ao, ac)
+	assert.Equal(t, ac.Log().Evaluations(), ao.PrivacyAudit)
+	assert.NotEmpty(t, ao.PrivacyAudit)
+}
+
+func TestLogObjectPrivacyAuditWithoutLog(t *testing.T) {
+	var count int
+	am := initAnalytics(&count)
+	audit := []privacy.ActivityEvaluation{{Activity: "fetchBids"}}
+	vo := &analytics.VideoObject{RequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: getDefaultBidRequest()}, PrivacyAudit: audit}
+
+	am.LogVideoObject(vo, privacy.NewActivityControl(getActivityConfig("sampleModule", true, false, false)))
+	assert.Equal(t, audit, vo.PrivacyAudit, "the audit is kept when the activity control doesn't record evaluations")
+}
+
 func getDefaultBidRequest() *openrtb2.BidRequest {
 	return &openrtb2.BidRequest{
 		ID:     "test_request",
@@ -7,6 +7,7 @@ import (
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/privacy"
 )
 
 // Module must be implemented by analytics modules to extract the required information and logging
@@ -32,6 +33,7 @@ type AuctionObject struct {
 	HookExecutionOutcome []hookexecution.StageOutcome
 	SeatNonBid           []openrtb_ext.SeatNonBid
 	RequestWrapper       *openrtb_ext.RequestWrapper
+	PrivacyAudit         []privacy.ActivityEvaluation
 }
 
 // Loggable object of a transaction at /openrtb2/amp endpoint
@@ -45,6 +47,7 @@ type AmpObject struct {
 	HookExecutionOutcome []hookexecution.StageOutcome
 	SeatNonBid           []openrtb_ext.SeatNonBid
 	RequestWrapper       *openrtb_ext.RequestWrapper
+	PrivacyAudit         []privacy.ActivityEvaluation
 }
 
 // Loggable object of a transaction at /openrtb2/video endpoint
@@ -57,6 +60,7 @@ type VideoObject struct {
 	StartTime      time.Time
 	SeatNonBid     []openrtb_ext.SeatNonBid
 	RequestWrapper *openrtb_ext.RequestWrapper
+	PrivacyAudit   []privacy.ActivityEvaluation
 }
 
 // Loggable object of a transaction at /setuid
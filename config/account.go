@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math"
 	"strings"
+	"time"
 
 	"github.com/prebid/go-gdpr/consentconstants"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
@@ -54,6 +55,91 @@ type Account struct {
 	Privacy                 AccountPrivacy                              `mapstructure:"privacy" json:"privacy"`
 	PreferredMediaType      openrtb_ext.PreferredMediaType              `mapstructure:"preferredmediatype" json:"preferredmediatype"`
 	TargetingPrefix         string                                      `mapstructure:"targeting_prefix" json:"targeting_prefix"`
+	LineItems               []AccountLineItem                           `mapstructure:"line_items" json:"line_items,omitempty"`
+	FrequencyCaps           []AccountFrequencyCap                       `mapstructure:"frequency_caps" json:"frequency_caps,omitempty"`
+	Native                  AccountNative                               `mapstructure:"native" json:"native"`
+	CompetitiveSeparation   AccountCompetitiveSeparation                `mapstructure:"competitive_separation" json:"competitive_separation"`
+}
+
+// AccountCompetitiveSeparation keeps the winners of the imps of a request from sharing an advertiser domain or an
+// IAB category. Sharing is allowed for every domain or category when the corresponding flag is set, or for the
+// listed ones only. The separation only runs along with targeting, when the request asks for it, since the winners
+// are only picked by the exchange then.
+type AccountCompetitiveSeparation struct {
+	Enabled               bool     `mapstructure:"enabled" json:"enabled"`
+	AllowSharedAdomains   bool     `mapstructure:"allow_shared_adomains" json:"allow_shared_adomains"`
+	AllowSharedCategories bool     `mapstructure:"allow_shared_categories" json:"allow_shared_categories"`
+	AllowedAdomains       []string `mapstructure:"allowed_adomains" json:"allowed_adomains,omitempty"`
+	AllowedCategories     []string `mapstructure:"allowed_categories" json:"allowed_categories,omitempty"`
+}
+
+// AccountNative configures how native bids are served for an account.
+type AccountNative struct {
+	// RenderTemplate is the HTML native bids are rendered into for environments which can't render native, such
+	// as AMP. Assets are inserted with the Prebid.js native macros, e.g. ##hb_native_title##.
+	RenderTemplate string `mapstructure:"render_template" json:"render_template,omitempty"`
+}
+
+// Frequency cap types supported by AccountFrequencyCap
+const (
+	FrequencyCapTypeADomain = "adomain"
+	FrequencyCapTypeDeal    = "deal"
+)
+
+// AccountFrequencyCap limits how many impressions of the same advertiser domain or deal a single user sees
+// within a period. Without values the cap applies to each advertiser domain or deal on its own.
+type AccountFrequencyCap struct {
+	Type          string   `mapstructure:"type" json:"type"`
+	Values        []string `mapstructure:"values" json:"values,omitempty"`
+	Count         int      `mapstructure:"count" json:"count"`
+	PeriodSeconds int      `mapstructure:"period_sec" json:"period_sec"`
+}
+
+// AccountLineItem is a publisher managed line item delivered by Prebid Server. Line items with a creative are
+// bid on by Prebid Server itself under the source seat, all others pass their deal to the source bidder.
+type AccountLineItem struct {
+	ID            string                 `mapstructure:"id" json:"id"`
+	Source        string                 `mapstructure:"source" json:"source"`
+	DealID        string                 `mapstructure:"deal_id" json:"deal_id"`
+	Price         float64                `mapstructure:"price" json:"price"`
+	Currency      string                 `mapstructure:"currency" json:"currency"`
+	StartTime     time.Time              `mapstructure:"start_time" json:"start_time"`
+	EndTime       time.Time              `mapstructure:"end_time" json:"end_time"`
+	Goal          int64                  `mapstructure:"impression_goal" json:"impression_goal"`
+	FrequencyCaps []LineItemFrequencyCap `mapstructure:"frequency_caps" json:"frequency_caps"`
+	Targeting     LineItemTargeting      `mapstructure:"targeting" json:"targeting"`
+	Creative      *LineItemCreative      `mapstructure:"creative" json:"creative"`
+}
+
+// LineItemFrequencyCap limits how many impressions of a line item a single user sees within a period.
+type LineItemFrequencyCap struct {
+	Count         int `mapstructure:"count" json:"count"`
+	PeriodSeconds int `mapstructure:"period_sec" json:"period_sec"`
+}
+
+// LineItemTargeting restricts a line item to matching requests. Empty lists match everything.
+type LineItemTargeting struct {
+	MediaTypes  []string       `mapstructure:"media_types" json:"media_types"`
+	Sizes       []LineItemSize `mapstructure:"sizes" json:"sizes"`
+	AdUnitCodes []string       `mapstructure:"ad_unit_codes" json:"ad_unit_codes"`
+	SiteDomains []string       `mapstructure:"site_domains" json:"site_domains"`
+	AppBundles  []string       `mapstructure:"app_bundles" json:"app_bundles"`
+	Countries   []string       `mapstructure:"countries" json:"countries"`
+}
+
+type LineItemSize struct {
+	W int64 `mapstructure:"w" json:"w"`
+	H int64 `mapstructure:"h" json:"h"`
+}
+
+// LineItemCreative is the creative Prebid Server bids with on behalf of a line item.
+type LineItemCreative struct {
+	ID        string   `mapstructure:"id" json:"id"`
+	AdM       string   `mapstructure:"adm" json:"adm"`
+	W         int64    `mapstructure:"w" json:"w"`
+	H         int64    `mapstructure:"h" json:"h"`
+	ADomain   []string `mapstructure:"adomain" json:"adomain"`
+	MediaType string   `mapstructure:"media_type" json:"media_type"`
 }
 
 // CookieSync represents the account-level defaults for the cookie sync endpoint.
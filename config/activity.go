@@ -21,7 +21,19 @@ type ActivityRule struct {
 	Allow     bool              `mapstructure:"allow" json:"allow"`
 }
 
+// ActivityCondition holds the clauses a rule matches on. Every clause defined must match, and a clause matches
+// when any of its values does. The clauses on the request only match the activities evaluated for a bid request.
 type ActivityCondition struct {
 	ComponentName []string `mapstructure:"componentName" json:"componentName"`
 	ComponentType []string `mapstructure:"componentType" json:"componentType"`
+	GPPSID        []int8   `mapstructure:"gppSid" json:"gppSid,omitempty"`
+	// Geo holds device.geo countries, such as "USA", or countries and regions, such as "USA.VA".
+	Geo        []string `mapstructure:"geo" json:"geo,omitempty"`
+	Channel    []string `mapstructure:"channel" json:"channel,omitempty"`
+	Domain     []string `mapstructure:"domain" json:"domain,omitempty"`
+	Bundle     []string `mapstructure:"bundle" json:"bundle,omitempty"`
+	DeviceType []int    `mapstructure:"deviceType" json:"deviceType,omitempty"`
+	// TCFInScope and GPPSIDAvailable match on whether the request is in scope of GDPR and carries GPP section IDs.
+	TCFInScope      *bool `mapstructure:"tcfInScope" json:"tcfInScope,omitempty"`
+	GPPSIDAvailable *bool `mapstructure:"gppSidAvailable" json:"gppSidAvailable,omitempty"`
 }
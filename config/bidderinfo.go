@@ -1,6 +1,7 @@
 package config
 
 import (
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"log"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/prebid/prebid-server/v3/macros"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/httputil"
 
 	validator "github.com/asaskevich/govalidator"
 	"gopkg.in/yaml.v3"
@@ -47,6 +49,18 @@ type BidderInfo struct {
 	AppSecret  string `yaml:"app_secret" mapstructure:"app_secret"`
 	// EndpointCompression determines, if set, the type of compression the bid request will undergo before being sent to the corresponding bid server
 	EndpointCompression string `yaml:"endpointCompression" mapstructure:"endpointCompression"`
+	// EndpointCompressionDictionary is the path to a zstd dictionary shared with the bid server, used when EndpointCompression is zstd
+	EndpointCompressionDictionary string `yaml:"endpointCompressionDictionary" mapstructure:"endpointCompressionDictionary"`
+	// ResponseCompression lists, in order of preference, the encodings Prebid Server will advertise in Accept-Encoding
+	// and transparently decode when reading the bid server response
+	ResponseCompression []string `yaml:"responseCompression" mapstructure:"responseCompression"`
+	// MaxResponseBodyBytes caps the size of the bid server response body, both as read and once decoded. Zero
+	// applies the default of 32MB.
+	MaxResponseBodyBytes int `yaml:"maxResponseBodyBytes" mapstructure:"maxResponseBodyBytes"`
+
+	// Transport, if set, gives the bidder a dedicated connection pool configured with these settings
+	// instead of sharing the host http_client with all other bidders
+	Transport *BidderTransport `yaml:"transport" mapstructure:"transport"`
 }
 
 type aliasNillableFields struct {
@@ -103,6 +117,37 @@ type OpenRTBInfo struct {
 	MultiformatSupported *bool  `yaml:"multiformat-supported" mapstructure:"multiformat-supported"`
 }
 
+// BidderTransport specifies the settings of a dedicated HTTP transport for a bidder. Settings left at
+// their zero value inherit the host http_client configuration.
+type BidderTransport struct {
+	MaxConnsPerHost        int                 `yaml:"maxConnsPerHost" mapstructure:"maxConnsPerHost"`
+	MaxIdleConns           int                 `yaml:"maxIdleConns" mapstructure:"maxIdleConns"`
+	MaxIdleConnsPerHost    int                 `yaml:"maxIdleConnsPerHost" mapstructure:"maxIdleConnsPerHost"`
+	IdleConnTimeoutSeconds int                 `yaml:"idleConnTimeoutSeconds" mapstructure:"idleConnTimeoutSeconds"`
+	DisableKeepAlives      bool                `yaml:"disableKeepAlives" mapstructure:"disableKeepAlives"`
+	KeepAliveSeconds       int                 `yaml:"keepAliveSeconds" mapstructure:"keepAliveSeconds"`
+	DialTimeoutSeconds     int                 `yaml:"dialTimeoutSeconds" mapstructure:"dialTimeoutSeconds"`
+	DisableCompression     bool                `yaml:"disableCompression" mapstructure:"disableCompression"`
+	HTTP2                  *bool               `yaml:"http2" mapstructure:"http2"`
+	TLS                    *BidderTransportTLS `yaml:"tls" mapstructure:"tls"`
+}
+
+// BidderTransportTLS specifies the TLS settings of a dedicated bidder transport.
+type BidderTransportTLS struct {
+	MinVersion         string `yaml:"minVersion" mapstructure:"minVersion"`
+	ServerName         string `yaml:"serverName" mapstructure:"serverName"`
+	SessionCacheSize   int    `yaml:"sessionCacheSize" mapstructure:"sessionCacheSize"`
+	HandshakeTimeoutMS int    `yaml:"handshakeTimeoutMs" mapstructure:"handshakeTimeoutMs"`
+}
+
+// TLSVersions maps the supported tls.minVersion values to their crypto/tls constants.
+var TLSVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
 // Syncer specifies the user sync settings for a bidder. This struct is shared by the account config,
 // so it needs to have both yaml and mapstructure mappings.
 type Syncer struct {
@@ -338,6 +383,15 @@ func processBidderAliases(aliasNillableFieldsByBidder map[string]aliasNillableFi
 		if aliasBidderInfo.EndpointCompression == "" {
 			aliasBidderInfo.EndpointCompression = parentBidderInfo.EndpointCompression
 		}
+		if aliasBidderInfo.EndpointCompressionDictionary == "" {
+			aliasBidderInfo.EndpointCompressionDictionary = parentBidderInfo.EndpointCompressionDictionary
+		}
+		if aliasBidderInfo.ResponseCompression == nil {
+			aliasBidderInfo.ResponseCompression = parentBidderInfo.ResponseCompression
+		}
+		if aliasBidderInfo.MaxResponseBodyBytes == 0 {
+			aliasBidderInfo.MaxResponseBodyBytes = parentBidderInfo.MaxResponseBodyBytes
+		}
 		if aliasBidderInfo.ExtraAdapterInfo == "" {
 			aliasBidderInfo.ExtraAdapterInfo = parentBidderInfo.ExtraAdapterInfo
 		}
@@ -353,6 +407,9 @@ func processBidderAliases(aliasNillableFieldsByBidder map[string]aliasNillableFi
 		if aliasBidderInfo.PlatformID == "" {
 			aliasBidderInfo.PlatformID = parentBidderInfo.PlatformID
 		}
+		if aliasBidderInfo.Transport == nil {
+			aliasBidderInfo.Transport = parentBidderInfo.Transport
+		}
 		if aliasBidderInfo.Syncer == nil && parentBidderInfo.Syncer.Defined() {
 			syncerKey := aliasBidderInfo.AliasOf
 			if parentBidderInfo.Syncer.Key != "" {
@@ -484,6 +541,12 @@ func validateInfo(bidder BidderInfo, infos BidderInfos, bidderName string) error
 	if err := validateCapabilities(bidder.Capabilities, bidderName); err != nil {
 		return err
 	}
+	if err := validateTransport(bidder.Transport, bidderName); err != nil {
+		return err
+	}
+	if err := validateCompression(bidder, bidderName); err != nil {
+		return err
+	}
 	if len(bidder.AliasOf) > 0 {
 		if err := validateAliasCapabilities(bidder, infos, bidderName); err != nil {
 			return err
@@ -597,6 +660,59 @@ func validatePlatformInfo(info *PlatformInfo) error {
 	return nil
 }
 
+func validateCompression(bidder BidderInfo, bidderName string) error {
+	endpointCompression := httputil.ContentEncoding(bidder.EndpointCompression).Normalize()
+	if endpointCompression != "" && endpointCompression != httputil.ContentEncodingGZIP && endpointCompression != httputil.ContentEncodingZSTD {
+		return fmt.Errorf("endpointCompression %s is not supported for adapter: %s", bidder.EndpointCompression, bidderName)
+	}
+
+	if bidder.EndpointCompressionDictionary != "" && endpointCompression != httputil.ContentEncodingZSTD {
+		return fmt.Errorf("endpointCompressionDictionary requires zstd endpointCompression for adapter: %s", bidderName)
+	}
+
+	for _, encoding := range bidder.ResponseCompression {
+		normalized := httputil.ContentEncoding(encoding).Normalize()
+		if normalized != httputil.ContentEncodingGZIP && normalized != httputil.ContentEncodingZSTD {
+			return fmt.Errorf("responseCompression %s is not supported for adapter: %s", encoding, bidderName)
+		}
+	}
+
+	if bidder.MaxResponseBodyBytes < 0 {
+		return fmt.Errorf("maxResponseBodyBytes must be non-negative for adapter: %s", bidderName)
+	}
+
+	return nil
+}
+
+func validateTransport(transport *BidderTransport, bidderName string) error {
+	if transport == nil {
+		return nil
+	}
+
+	if transport.MaxConnsPerHost < 0 || transport.MaxIdleConns < 0 || transport.MaxIdleConnsPerHost < 0 {
+		return fmt.Errorf("transport connection limits must be non-negative for adapter: %s", bidderName)
+	}
+
+	if transport.IdleConnTimeoutSeconds < 0 || transport.KeepAliveSeconds < 0 || transport.DialTimeoutSeconds < 0 {
+		return fmt.Errorf("transport timeouts must be non-negative for adapter: %s", bidderName)
+	}
+
+	if transport.MaxConnsPerHost > 0 && transport.MaxIdleConnsPerHost > transport.MaxConnsPerHost {
+		return fmt.Errorf("transport.maxIdleConnsPerHost (%d) cannot exceed transport.maxConnsPerHost (%d) for adapter: %s", transport.MaxIdleConnsPerHost, transport.MaxConnsPerHost, bidderName)
+	}
+
+	if transport.TLS != nil {
+		if _, ok := TLSVersions[transport.TLS.MinVersion]; transport.TLS.MinVersion != "" && !ok {
+			return fmt.Errorf("transport.tls.minVersion %s is not supported for adapter: %s", transport.TLS.MinVersion, bidderName)
+		}
+		if transport.TLS.SessionCacheSize < 0 || transport.TLS.HandshakeTimeoutMS < 0 {
+			return fmt.Errorf("transport.tls settings must be non-negative for adapter: %s", bidderName)
+		}
+	}
+
+	return nil
+}
+
 func validateGeoscope(geoscope []string, bidderName string) error {
 	// ISO 3166-1 alpha-3 country codes are uppercase 3-letter codes
 	for i, code := range geoscope {
@@ -707,9 +823,21 @@ func applyBidderInfoConfigOverrides(configBidderInfos nillableFieldBidderInfos,
 		if configBidderInfo.bidderInfo.EndpointCompression != "" {
 			mergedBidderInfo.EndpointCompression = configBidderInfo.bidderInfo.EndpointCompression
 		}
+		if configBidderInfo.bidderInfo.EndpointCompressionDictionary != "" {
+			mergedBidderInfo.EndpointCompressionDictionary = configBidderInfo.bidderInfo.EndpointCompressionDictionary
+		}
+		if configBidderInfo.bidderInfo.ResponseCompression != nil {
+			mergedBidderInfo.ResponseCompression = configBidderInfo.bidderInfo.ResponseCompression
+		}
+		if configBidderInfo.bidderInfo.MaxResponseBodyBytes != 0 {
+			mergedBidderInfo.MaxResponseBodyBytes = configBidderInfo.bidderInfo.MaxResponseBodyBytes
+		}
 		if configBidderInfo.bidderInfo.OpenRTB != nil {
 			mergedBidderInfo.OpenRTB = configBidderInfo.bidderInfo.OpenRTB
 		}
+		if configBidderInfo.bidderInfo.Transport != nil {
+			mergedBidderInfo.Transport = configBidderInfo.bidderInfo.Transport
+		}
 
 		mergedBidderInfos[string(normalizedBidderName)] = mergedBidderInfo
 	}
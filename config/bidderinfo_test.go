@@ -2102,3 +2102,170 @@ func TestValidateGeoscope(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateTransport(t *testing.T) {
+	testCases := []struct {
+		name      string
+		transport *BidderTransport
+		expectErr string
+	}{
+		{
+			name:      "nil",
+			transport: nil,
+		},
+		{
+			name: "valid",
+			transport: &BidderTransport{
+				MaxConnsPerHost:     10,
+				MaxIdleConnsPerHost: 5,
+				HTTP2:               ptrutil.ToPtr(true),
+				TLS:                 &BidderTransportTLS{MinVersion: "1.2"},
+			},
+		},
+		{
+			name:      "negative-connection-limit",
+			transport: &BidderTransport{MaxIdleConns: -1},
+			expectErr: "transport connection limits must be non-negative for adapter: testBidder",
+		},
+		{
+			name:      "negative-timeout",
+			transport: &BidderTransport{DialTimeoutSeconds: -1},
+			expectErr: "transport timeouts must be non-negative for adapter: testBidder",
+		},
+		{
+			name:      "idle-exceeds-max",
+			transport: &BidderTransport{MaxConnsPerHost: 2, MaxIdleConnsPerHost: 3},
+			expectErr: "transport.maxIdleConnsPerHost (3) cannot exceed transport.maxConnsPerHost (2) for adapter: testBidder",
+		},
+		{
+			name:      "unknown-tls-version",
+			transport: &BidderTransport{TLS: &BidderTransportTLS{MinVersion: "2.0"}},
+			expectErr: "transport.tls.minVersion 2.0 is not supported for adapter: testBidder",
+		},
+		{
+			name:      "negative-tls-setting",
+			transport: &BidderTransport{TLS: &BidderTransportTLS{SessionCacheSize: -1}},
+			expectErr: "transport.tls settings must be non-negative for adapter: testBidder",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateTransport(tc.transport, "testBidder")
+
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReadBidderTransportYAML(t *testing.T) {
+	transportYAML := `
+transport:
+  maxConnsPerHost: 20
+  maxIdleConnsPerHost: 10
+  idleConnTimeoutSeconds: 30
+  disableKeepAlives: true
+  http2: false
+  tls:
+    minVersion: "1.3"
+    serverName: bidder.com
+`
+	var info BidderInfo
+	require.NoError(t, yaml.Unmarshal([]byte(transportYAML), &info))
+
+	expected := &BidderTransport{
+		MaxConnsPerHost:        20,
+		MaxIdleConnsPerHost:    10,
+		IdleConnTimeoutSeconds: 30,
+		DisableKeepAlives:      true,
+		HTTP2:                  ptrutil.ToPtr(false),
+		TLS:                    &BidderTransportTLS{MinVersion: "1.3", ServerName: "bidder.com"},
+	}
+	assert.Equal(t, expected, info.Transport)
+}
+
+func TestTransportAliasAndOverride(t *testing.T) {
+	parentTransport := &BidderTransport{MaxConnsPerHost: 5}
+	overrideTransport := &BidderTransport{MaxConnsPerHost: 50}
+
+	t.Run("alias-inherits-parent", func(t *testing.T) {
+		infos := BidderInfos{
+			"bidderA": BidderInfo{Transport: parentTransport},
+			"bidderB": BidderInfo{AliasOf: "bidderA"},
+		}
+		result, err := processBidderAliases(map[string]aliasNillableFields{"bidderB": {}}, infos)
+		require.NoError(t, err)
+		assert.Same(t, parentTransport, result["bidderB"].Transport)
+	})
+
+	t.Run("host-config-overrides-file", func(t *testing.T) {
+		fsInfos := BidderInfos{"bidderA": BidderInfo{Transport: parentTransport}}
+		configInfos := nillableFieldBidderInfos{"bidderA": {bidderInfo: BidderInfo{Transport: overrideTransport}}}
+		normalizer := func(name string) (openrtb_ext.BidderName, bool) { return openrtb_ext.BidderName(name), true }
+
+		result, err := applyBidderInfoConfigOverrides(configInfos, fsInfos, normalizer)
+		require.NoError(t, err)
+		assert.Same(t, overrideTransport, result["bidderA"].Transport)
+	})
+}
+
+func TestValidateCompression(t *testing.T) {
+	testCases := []struct {
+		name      string
+		info      BidderInfo
+		expectErr string
+	}{
+		{
+			name: "none",
+			info: BidderInfo{},
+		},
+		{
+			name: "gzip",
+			info: BidderInfo{EndpointCompression: "GZIP"},
+		},
+		{
+			name: "zstd-with-dictionary-and-response-compression",
+			info: BidderInfo{EndpointCompression: "zstd", EndpointCompressionDictionary: "/etc/dict", ResponseCompression: []string{"zstd", "GZIP"}},
+		},
+		{
+			name:      "unsupported-endpoint-compression",
+			info:      BidderInfo{EndpointCompression: "br"},
+			expectErr: "endpointCompression br is not supported for adapter: testBidder",
+		},
+		{
+			name:      "dictionary-without-zstd",
+			info:      BidderInfo{EndpointCompression: "gzip", EndpointCompressionDictionary: "/etc/dict"},
+			expectErr: "endpointCompressionDictionary requires zstd endpointCompression for adapter: testBidder",
+		},
+		{
+			name:      "unsupported-response-compression",
+			info:      BidderInfo{ResponseCompression: []string{"deflate"}},
+			expectErr: "responseCompression deflate is not supported for adapter: testBidder",
+		},
+		{
+			name: "max-response-body-bytes",
+			info: BidderInfo{MaxResponseBodyBytes: 1 << 20},
+		},
+		{
+			name:      "negative-max-response-body-bytes",
+			info:      BidderInfo{MaxResponseBodyBytes: -1},
+			expectErr: "maxResponseBodyBytes must be non-negative for adapter: testBidder",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateCompression(tc.info, "testBidder")
+
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -45,6 +45,7 @@ type Configuration struct {
 	RecaptchaSecret   string          `mapstructure:"recaptcha_secret"`
 	HostCookie        HostCookie      `mapstructure:"host_cookie"`
 	Metrics           Metrics         `mapstructure:"metrics"`
+	Tracing           Tracing         `mapstructure:"tracing"`
 	StoredRequests    StoredRequests  `mapstructure:"stored_requests"`
 	StoredRequestsAMP StoredRequests  `mapstructure:"stored_amp_req"`
 	CategoryMapping   StoredRequests  `mapstructure:"category_mapping"`
@@ -55,6 +56,8 @@ type Configuration struct {
 	// Note that StoredVideo refers to stored video requests, and has nothing to do with caching video creatives.
 	StoredVideo     StoredRequests `mapstructure:"stored_video_req"`
 	StoredResponses StoredRequests `mapstructure:"stored_responses"`
+	// StoredResponseRecording configures where auctions flagged for recording write their stored responses.
+	StoredResponseRecording StoredResponseRecording `mapstructure:"stored_response_recording"`
 	// StoredRequestsTimeout defines the number of milliseconds before a timeout occurs with stored requests fetch
 	StoredRequestsTimeout int `mapstructure:"stored_requests_timeout_ms"`
 
@@ -106,6 +109,16 @@ type Configuration struct {
 	Hooks       Hooks       `mapstructure:"hooks"`
 	Validations Validations `mapstructure:"validations"`
 	PriceFloors PriceFloors `mapstructure:"price_floors"`
+	// FrequencyCapping enables the account frequency caps, counted from impression events
+	FrequencyCapping FrequencyCapping `mapstructure:"frequency_capping"`
+	// LineItems configures the delivery of the account line items
+	LineItems LineItems `mapstructure:"line_items"`
+	// ConfigReload allows the host configuration to be reloaded without restarting Prebid Server
+	ConfigReload ConfigReload `mapstructure:"config_reload"`
+	// Readiness configures when the /status endpoint reports Prebid Server ready to serve traffic
+	Readiness Readiness `mapstructure:"readiness"`
+	// Shutdown configures how Prebid Server stops on SIGTERM or SIGINT
+	Shutdown Shutdown `mapstructure:"shutdown"`
 }
 
 type Admin struct {
@@ -124,6 +137,90 @@ type PriceFloorFetcher struct {
 	MaxRetries int        `mapstructure:"max_retries"`
 }
 
+// FrequencyCapStoreMemory keeps frequency capping counters within the Prebid Server instance
+const FrequencyCapStoreMemory = "memory"
+
+type FrequencyCapping struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Store selects where impression counters are kept
+	Store string `mapstructure:"store"`
+	// ServedTTLSeconds is how long an impression event for a served bid is counted
+	ServedTTLSeconds int `mapstructure:"served_ttl_seconds"`
+}
+
+type LineItems struct {
+	// WinTTLSeconds is how long the impression event of a winning line item bid is counted as a delivery
+	WinTTLSeconds int `mapstructure:"win_ttl_seconds"`
+}
+
+func (cfg *LineItems) validate(errs []error) []error {
+	if cfg.WinTTLSeconds <= 0 {
+		errs = append(errs, fmt.Errorf("line_items.win_ttl_seconds must be > 0. Got %d", cfg.WinTTLSeconds))
+	}
+	return errs
+}
+
+func (cfg *FrequencyCapping) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.Store != FrequencyCapStoreMemory {
+		errs = append(errs, fmt.Errorf("frequency_capping.store %q is not supported", cfg.Store))
+	}
+	if cfg.ServedTTLSeconds <= 0 {
+		errs = append(errs, fmt.Errorf("frequency_capping.served_ttl_seconds must be > 0. Got %d", cfg.ServedTTLSeconds))
+	}
+	return errs
+}
+
+type ConfigReload struct {
+	// Enabled reloads the configuration on SIGHUP and on a POST to the admin /config/reload endpoint
+	Enabled bool `mapstructure:"enabled"`
+	// WatchFiles additionally reloads the configuration when the host config file or the bidder-info files change
+	WatchFiles bool `mapstructure:"watch_files"`
+	// WatchDelayMS is how long file changes must settle before the configuration is reloaded
+	WatchDelayMS int `mapstructure:"watch_delay_ms"`
+}
+
+func (cfg *ConfigReload) validate(errs []error) []error {
+	if cfg.Enabled && cfg.WatchFiles && cfg.WatchDelayMS <= 0 {
+		errs = append(errs, fmt.Errorf("config_reload.watch_delay_ms must be > 0 when config_reload.watch_files is enabled. Got %d", cfg.WatchDelayMS))
+	}
+	return errs
+}
+
+type Readiness struct {
+	// WarmupTimeoutMS is how long the stored request caches, GDPR vendor lists, currency rates and floors fetcher
+	// may take to warm up before Prebid Server reports ready regardless. Zero waits for them indefinitely.
+	WarmupTimeoutMS int `mapstructure:"warmup_timeout_ms"`
+}
+
+func (cfg *Readiness) validate(errs []error) []error {
+	if cfg.WarmupTimeoutMS < 0 {
+		errs = append(errs, fmt.Errorf("readiness.warmup_timeout_ms must be >= 0. Got %d", cfg.WarmupTimeoutMS))
+	}
+	return errs
+}
+
+type Shutdown struct {
+	// NotReadyDelayMS is how long Prebid Server keeps serving requests after the /status endpoint turned not ready,
+	// giving load balancers time to route traffic elsewhere
+	NotReadyDelayMS int `mapstructure:"not_ready_delay_ms"`
+	// DrainTimeoutMS bounds the wait for in-flight requests to complete, and then for the analytics modules and
+	// hook modules to shut down.
+	DrainTimeoutMS int `mapstructure:"drain_timeout_ms"`
+}
+
+func (cfg *Shutdown) validate(errs []error) []error {
+	if cfg.NotReadyDelayMS < 0 {
+		errs = append(errs, fmt.Errorf("shutdown.not_ready_delay_ms must be >= 0. Got %d", cfg.NotReadyDelayMS))
+	}
+	if cfg.DrainTimeoutMS <= 0 {
+		errs = append(errs, fmt.Errorf("shutdown.drain_timeout_ms must be > 0. Got %d", cfg.DrainTimeoutMS))
+	}
+	return errs
+}
+
 const MIN_COOKIE_SIZE_BYTES = 500
 
 type HTTPClient struct {
@@ -166,7 +263,9 @@ func (cfg *Configuration) validate(v *viper.Viper) []error {
 	errs = cfg.Accounts.validate(errs)
 	errs = cfg.CategoryMapping.validate(errs)
 	errs = cfg.StoredVideo.validate(errs)
+	errs = cfg.StoredResponseRecording.validate(cfg.Debug.OverrideToken, errs)
 	errs = cfg.Metrics.validate(errs)
+	errs = cfg.Tracing.validate(errs)
 	if cfg.MaxRequestSize < 0 {
 		errs = append(errs, fmt.Errorf("cfg.max_request_size must be >= 0. Got %d", cfg.MaxRequestSize))
 	}
@@ -175,6 +274,11 @@ func (cfg *Configuration) validate(v *viper.Viper) []error {
 	errs = cfg.Debug.validate(errs)
 	errs = cfg.ExtCacheURL.validate(errs)
 	errs = cfg.AccountDefaults.PriceFloors.validate(errs)
+	errs = cfg.FrequencyCapping.validate(errs)
+	errs = cfg.LineItems.validate(errs)
+	errs = cfg.ConfigReload.validate(errs)
+	errs = cfg.Readiness.validate(errs)
+	errs = cfg.Shutdown.validate(errs)
 	if cfg.AccountDefaults.Disabled {
 		glog.Warning(`With account_defaults.disabled=true, host-defined accounts must exist and have "disabled":false. All other requests will be rejected.`)
 	}
@@ -266,11 +370,12 @@ type Privacy struct {
 }
 
 type GDPR struct {
-	Enabled                 bool         `mapstructure:"enabled"`
-	HostVendorID            int          `mapstructure:"host_vendor_id"`
-	DefaultValue            string       `mapstructure:"default_value"`
-	Timeouts                GDPRTimeouts `mapstructure:"timeouts_ms"`
-	NonStandardPublishers   []string     `mapstructure:"non_standard_publishers,flow"`
+	Enabled                 bool           `mapstructure:"enabled"`
+	HostVendorID            int            `mapstructure:"host_vendor_id"`
+	DefaultValue            string         `mapstructure:"default_value"`
+	Timeouts                GDPRTimeouts   `mapstructure:"timeouts_ms"`
+	VendorList              GDPRVendorList `mapstructure:"vendor_list"`
+	NonStandardPublishers   []string       `mapstructure:"non_standard_publishers,flow"`
 	NonStandardPublisherMap map[string]struct{}
 	TCF2                    TCF2 `mapstructure:"tcf2"`
 	AMPException            bool `mapstructure:"amp_exception"` // Deprecated: Use account-level GDPR settings (gdpr.integration_enabled.amp) instead
@@ -297,6 +402,7 @@ func (cfg *GDPR) validate(v *viper.Viper, errs []error) []error {
 	if cfg.AMPException {
 		errs = append(errs, fmt.Errorf("gdpr.amp_exception has been discontinued and must be removed from your config. If you need to disable GDPR for AMP, you may do so per-account (gdpr.integration_enabled.amp) or at the host level for the default account (account_defaults.gdpr.integration_enabled.amp)"))
 	}
+	errs = cfg.VendorList.validate(errs)
 	return cfg.validatePurposes(errs)
 }
 
@@ -338,6 +444,31 @@ func (t *GDPRTimeouts) ActiveTimeout() time.Duration {
 	return time.Duration(t.ActiveVendorlistFetch) * time.Millisecond
 }
 
+// GDPRVendorList configures where the Global Vendor Lists are loaded from.
+type GDPRVendorList struct {
+	// Directory holds vendor lists to load at startup, such as the ones written by the gvl-download subcommand.
+	Directory string `mapstructure:"directory"`
+	// Embedded loads the vendor lists bundled into the binary at build time at startup.
+	Embedded bool `mapstructure:"embedded"`
+	// URL is the base URL vendor lists are fetched from, laid out like https://vendor-list.consensu.org.
+	// It may be a file:// URL. Vendor lists are never fetched if it's empty.
+	URL string `mapstructure:"url"`
+	// RefreshIntervalSeconds is how often the latest vendor lists are fetched in the background. Zero disables it.
+	RefreshIntervalSeconds int `mapstructure:"refresh_interval_seconds"`
+}
+
+func (cfg *GDPRVendorList) validate(errs []error) []error {
+	if cfg.URL != "" {
+		if u, err := url.Parse(cfg.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "file") {
+			errs = append(errs, fmt.Errorf("gdpr.vendor_list.url must be an http, https or file URL. Got %s", cfg.URL))
+		}
+	}
+	if cfg.RefreshIntervalSeconds < 0 {
+		errs = append(errs, fmt.Errorf("gdpr.vendor_list.refresh_interval_seconds must be >= 0. Got %d", cfg.RefreshIntervalSeconds))
+	}
+	return errs
+}
+
 const (
 	TCF2EnforceAlgoBasic = "basic"
 	TCF2EnforceAlgoFull  = "full"
@@ -576,6 +707,7 @@ type RequestTimeoutHeaders struct {
 type Metrics struct {
 	Influxdb   InfluxMetrics     `mapstructure:"influxdb"`
 	Prometheus PrometheusMetrics `mapstructure:"prometheus"`
+	StatsD     StatsDMetrics     `mapstructure:"statsd"`
 	Disabled   DisabledMetrics   `mapstructure:"disabled_metrics"`
 }
 
@@ -608,7 +740,41 @@ type DisabledMetrics struct {
 }
 
 func (cfg *Metrics) validate(errs []error) []error {
-	return cfg.Prometheus.validate(errs)
+	errs = cfg.Prometheus.validate(errs)
+	return cfg.StatsD.validate(errs)
+}
+
+// Tracing configures OpenTelemetry tracing of the auction pipeline. Spans are exported to an OTLP/HTTP collector.
+type Tracing struct {
+	Enabled     bool   `mapstructure:"enabled"`
+	ServiceName string `mapstructure:"service_name"`
+	// SamplingRate is the share of traces started by Prebid Server which are recorded. Traces continued from a
+	// caller's traceparent header follow the caller's sampling decision.
+	SamplingRate float64 `mapstructure:"sampling_rate"`
+	// PropagateToBidders adds the traceparent header to bidder requests
+	PropagateToBidders bool            `mapstructure:"propagate_to_bidders"`
+	Exporter           TracingExporter `mapstructure:"exporter"`
+}
+
+type TracingExporter struct {
+	// Endpoint is the host and port of the collector
+	Endpoint  string `mapstructure:"endpoint"`
+	URLPath   string `mapstructure:"url_path"`
+	Insecure  bool   `mapstructure:"insecure"`
+	TimeoutMS int    `mapstructure:"timeout_ms"`
+}
+
+func (cfg *Tracing) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.SamplingRate < 0 || cfg.SamplingRate > 1 {
+		errs = append(errs, fmt.Errorf("tracing.sampling_rate must be between 0 and 1. Got %f", cfg.SamplingRate))
+	}
+	if cfg.Exporter.Endpoint == "" {
+		errs = append(errs, errors.New("tracing.exporter.endpoint must be set when tracing is enabled"))
+	}
+	return errs
 }
 
 type InfluxMetrics struct {
@@ -639,6 +805,35 @@ func (m *PrometheusMetrics) Timeout() time.Duration {
 	return time.Duration(m.TimeoutMillisRaw) * time.Millisecond
 }
 
+// StatsDMetrics configures pushing metrics to a StatsD agent. Labels are sent as DogStatsD tags.
+type StatsDMetrics struct {
+	// Host is the host and port of the agent. Metrics are sent over UDP.
+	Host      string `mapstructure:"host"`
+	Namespace string `mapstructure:"namespace"`
+	// Tags are added to every metric, formatted as key:value
+	Tags []string `mapstructure:"tags"`
+	// MaxPacketSize is the most bytes sent in a single datagram
+	MaxPacketSize   int `mapstructure:"max_packet_size"`
+	FlushIntervalMS int `mapstructure:"flush_interval_ms"`
+}
+
+func (cfg *StatsDMetrics) validate(errs []error) []error {
+	if cfg.Host == "" {
+		return errs
+	}
+	if cfg.MaxPacketSize <= 0 {
+		errs = append(errs, fmt.Errorf("metrics.statsd.max_packet_size must be positive if metrics.statsd.host is defined. Got %d", cfg.MaxPacketSize))
+	}
+	if cfg.FlushIntervalMS <= 0 {
+		errs = append(errs, fmt.Errorf("metrics.statsd.flush_interval_ms must be positive if metrics.statsd.host is defined. Got %d", cfg.FlushIntervalMS))
+	}
+	return errs
+}
+
+func (m *StatsDMetrics) FlushInterval() time.Duration {
+	return time.Duration(m.FlushIntervalMS) * time.Millisecond
+}
+
 // ExternalCache configures the externally accessible cache url.
 type ExternalCache struct {
 	Scheme string `mapstructure:"scheme"`
@@ -722,6 +917,8 @@ type TimeoutNotification struct {
 type Validations struct {
 	BannerCreativeMaxSize string `mapstructure:"banner_creative_max_size" json:"banner_creative_max_size"`
 	SecureMarkup          string `mapstructure:"secure_markup" json:"secure_markup"`
+	NativeAssets          string `mapstructure:"native_assets" json:"native_assets"`
+	SecureCreatives       string `mapstructure:"secure_creatives" json:"secure_creatives"`
 	MaxCreativeWidth      int64  `mapstructure:"max_creative_width" json:"max_creative_width"`
 	MaxCreativeHeight     int64  `mapstructure:"max_creative_height" json:"max_creative_height"`
 }
@@ -730,6 +927,7 @@ const (
 	ValidationEnforce string = "enforce"
 	ValidationWarn    string = "warn"
 	ValidationSkip    string = "skip"
+	ValidationRewrite string = "rewrite"
 )
 
 func (host *Validations) SetBannerCreativeMaxSize(account Validations) {
@@ -738,6 +936,54 @@ func (host *Validations) SetBannerCreativeMaxSize(account Validations) {
 	}
 }
 
+// NativeAssetsEnforcement returns how native bids are validated against their request, preferring the account
+// setting over the host one.
+func (host Validations) NativeAssetsEnforcement(account Validations) string {
+	if len(account.NativeAssets) > 0 {
+		return account.NativeAssets
+	}
+	return host.NativeAssets
+}
+
+// SecureCreativesEnforcement returns how the URLs of the bids for secure imps are checked for HTTPS. Besides the
+// other validation types, it can be rewrite to upgrade them to HTTPS. The stricter of the host and account settings
+// applies, so an account can tighten the host policy but never loosen it.
+func (host Validations) SecureCreativesEnforcement(account Validations) string {
+	return stricterValidation(host.SecureCreatives, account.SecureCreatives)
+}
+
+// SecureMarkupEnforcement returns how the markup of the bids for secure imps is checked for HTTPS. The secure_markup
+// check is skipped when secure_creatives is at least as strict, since the latter covers the markup as well as the
+// notification URLs. Otherwise the host secure_markup applies, whatever the account secure_creatives.
+func (host Validations) SecureMarkupEnforcement(account Validations) string {
+	if validationStrictness(host.SecureCreativesEnforcement(account)) >= validationStrictness(host.SecureMarkup) {
+		return ValidationSkip
+	}
+	return host.SecureMarkup
+}
+
+// stricterValidation returns the stricter of two validation types, the first one when they're as strict.
+func stricterValidation(first, second string) string {
+	if validationStrictness(second) > validationStrictness(first) {
+		return second
+	}
+	return first
+}
+
+// validationStrictness ranks the validation types: rewrite serves the bids it fixes where enforce rejects them.
+// An empty or unknown validation type is as strict as skip.
+func validationStrictness(validation string) int {
+	switch validation {
+	case ValidationWarn:
+		return 1
+	case ValidationRewrite:
+		return 2
+	case ValidationEnforce:
+		return 3
+	}
+	return 0
+}
+
 func (cfg *TimeoutNotification) validate(errs []error) []error {
 	if cfg.SamplingRate < 0.0 || cfg.SamplingRate > 1.0 {
 		errs = append(errs, fmt.Errorf("debug.timeout_notification.sampling_rate must be positive and not greater than 1.0. Got %f", cfg.SamplingRate))
@@ -970,6 +1216,8 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("host_schain_node", nil)
 	v.SetDefault("validations.banner_creative_max_size", ValidationSkip)
 	v.SetDefault("validations.secure_markup", ValidationSkip)
+	v.SetDefault("validations.native_assets", ValidationSkip)
+	v.SetDefault("validations.secure_creatives", ValidationSkip)
 	v.SetDefault("validations.max_creative_size.height", 0)
 	v.SetDefault("validations.max_creative_size.width", 0)
 	v.SetDefault("http_client.max_connections_per_host", 0) // unlimited
@@ -1012,6 +1260,19 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("metrics.prometheus.namespace", "")
 	v.SetDefault("metrics.prometheus.subsystem", "")
 	v.SetDefault("metrics.prometheus.timeout_ms", 10000)
+	v.SetDefault("metrics.statsd.host", "")
+	v.SetDefault("metrics.statsd.namespace", "prebidserver.")
+	v.SetDefault("metrics.statsd.tags", []string{})
+	v.SetDefault("metrics.statsd.max_packet_size", 1432)
+	v.SetDefault("metrics.statsd.flush_interval_ms", 100)
+	v.SetDefault("tracing.enabled", false)
+	v.SetDefault("tracing.service_name", "prebid-server")
+	v.SetDefault("tracing.sampling_rate", 0.01)
+	v.SetDefault("tracing.propagate_to_bidders", false)
+	v.SetDefault("tracing.exporter.endpoint", "localhost:4318")
+	v.SetDefault("tracing.exporter.url_path", "/v1/traces")
+	v.SetDefault("tracing.exporter.insecure", false)
+	v.SetDefault("tracing.exporter.timeout_ms", 10000)
 	v.SetDefault("category_mapping.filesystem.enabled", true)
 	v.SetDefault("category_mapping.filesystem.directorypath", "./static/category-mapping")
 	v.SetDefault("category_mapping.http.endpoint", "")
@@ -1102,6 +1363,21 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("stored_responses.database.initialize_caches.amp_query", "")
 	v.SetDefault("stored_responses.database.poll_for_updates.refresh_rate_seconds", 0)
 	v.SetDefault("stored_responses.database.poll_for_updates.timeout_ms", 0)
+	v.SetDefault("stored_response_recording.enabled", false)
+	v.SetDefault("stored_response_recording.timeout_ms", 1000)
+	v.SetDefault("stored_response_recording.filesystem.enabled", false)
+	v.SetDefault("stored_response_recording.filesystem.directorypath", "")
+	v.SetDefault("stored_response_recording.database.connection.driver", "")
+	v.SetDefault("stored_response_recording.database.connection.dbname", "")
+	v.SetDefault("stored_response_recording.database.connection.host", "")
+	v.SetDefault("stored_response_recording.database.connection.port", 0)
+	v.SetDefault("stored_response_recording.database.connection.user", "")
+	v.SetDefault("stored_response_recording.database.connection.password", "")
+	v.SetDefault("stored_response_recording.database.connection.query_string", "")
+	v.SetDefault("stored_response_recording.database.connection.tls.root_cert", "")
+	v.SetDefault("stored_response_recording.database.connection.tls.client_cert", "")
+	v.SetDefault("stored_response_recording.database.connection.tls.client_key", "")
+	v.SetDefault("stored_response_recording.database.query", "")
 	v.SetDefault("stored_responses.database.poll_for_updates.query", "")
 	v.SetDefault("stored_responses.database.poll_for_updates.amp_query", "")
 	v.SetDefault("stored_responses.filesystem.enabled", false)
@@ -1169,6 +1445,10 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("gdpr.host_vendor_id", 0)
 	v.SetDefault("gdpr.timeouts_ms.init_vendorlist_fetches", 0)
 	v.SetDefault("gdpr.timeouts_ms.active_vendorlist_fetch", 0)
+	v.SetDefault("gdpr.vendor_list.directory", "")
+	v.SetDefault("gdpr.vendor_list.embedded", true)
+	v.SetDefault("gdpr.vendor_list.url", "https://vendor-list.consensu.org")
+	v.SetDefault("gdpr.vendor_list.refresh_interval_seconds", 0)
 	v.SetDefault("gdpr.non_standard_publishers", []string{""})
 	v.SetDefault("gdpr.tcf2.enabled", true)
 	v.SetDefault("gdpr.tcf2.purpose1.enforce_vendors", true)
@@ -1235,6 +1515,17 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("account_defaults.privacy.ipv4.anon_keep_bits", 24)
 
 	//Defaults for Price floor fetcher
+	v.SetDefault("frequency_capping.enabled", false)
+	v.SetDefault("frequency_capping.store", FrequencyCapStoreMemory)
+	v.SetDefault("frequency_capping.served_ttl_seconds", 3600)
+	v.SetDefault("line_items.win_ttl_seconds", 3600)
+	v.SetDefault("config_reload.enabled", false)
+	v.SetDefault("config_reload.watch_files", false)
+	v.SetDefault("config_reload.watch_delay_ms", 1000)
+	v.SetDefault("readiness.warmup_timeout_ms", 60000)
+	v.SetDefault("shutdown.not_ready_delay_ms", 0)
+	v.SetDefault("shutdown.drain_timeout_ms", 10000)
+
 	v.SetDefault("price_floors.fetcher.worker", 20)
 	v.SetDefault("price_floors.fetcher.capacity", 20000)
 	v.SetDefault("price_floors.fetcher.cache_size_mb", 64)
@@ -1371,8 +1662,22 @@ func setBidderDefaults(v *viper.Viper, bidder string) {
 	v.BindEnv(adapterCfgPrefix + ".xapi.password")
 	v.BindEnv(adapterCfgPrefix + ".xapi.tracker")
 	v.BindEnv(adapterCfgPrefix + ".endpointCompression")
+	v.BindEnv(adapterCfgPrefix + ".maxResponseBodyBytes")
 	v.BindEnv(adapterCfgPrefix + ".openrtb.version")
 	v.BindEnv(adapterCfgPrefix + ".openrtb.gpp-supported")
+	v.BindEnv(adapterCfgPrefix + ".transport.maxConnsPerHost")
+	v.BindEnv(adapterCfgPrefix + ".transport.maxIdleConns")
+	v.BindEnv(adapterCfgPrefix + ".transport.maxIdleConnsPerHost")
+	v.BindEnv(adapterCfgPrefix + ".transport.idleConnTimeoutSeconds")
+	v.BindEnv(adapterCfgPrefix + ".transport.disableKeepAlives")
+	v.BindEnv(adapterCfgPrefix + ".transport.keepAliveSeconds")
+	v.BindEnv(adapterCfgPrefix + ".transport.dialTimeoutSeconds")
+	v.BindEnv(adapterCfgPrefix + ".transport.disableCompression")
+	v.BindEnv(adapterCfgPrefix + ".transport.http2")
+	v.BindEnv(adapterCfgPrefix + ".transport.tls.minVersion")
+	v.BindEnv(adapterCfgPrefix + ".transport.tls.serverName")
+	v.BindEnv(adapterCfgPrefix + ".transport.tls.sessionCacheSize")
+	v.BindEnv(adapterCfgPrefix + ".transport.tls.handshakeTimeoutMs")
 
 	v.BindEnv(adapterCfgPrefix + ".usersync.key")
 	v.BindEnv(adapterCfgPrefix + ".usersync.default")
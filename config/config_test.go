@@ -231,6 +231,8 @@ func TestDefaults(t *testing.T) {
 	cmpBools(t, "hooks.enabled", false, cfg.Hooks.Enabled)
 	cmpStrings(t, "validations.banner_creative_max_size", "skip", cfg.Validations.BannerCreativeMaxSize)
 	cmpStrings(t, "validations.secure_markup", "skip", cfg.Validations.SecureMarkup)
+	cmpStrings(t, "validations.native_assets", "skip", cfg.Validations.NativeAssets)
+	cmpStrings(t, "validations.secure_creatives", "skip", cfg.Validations.SecureCreatives)
 	cmpInts(t, "validations.max_creative_width", 0, int(cfg.Validations.MaxCreativeWidth))
 	cmpInts(t, "validations.max_creative_height", 0, int(cfg.Validations.MaxCreativeHeight))
 	cmpBools(t, "account_modules_metrics", false, cfg.Metrics.Disabled.AccountModulesMetrics)
@@ -359,6 +361,7 @@ func TestDefaults(t *testing.T) {
 		10: &expectedTCF2.Purpose10,
 	}
 	assert.Equal(t, expectedTCF2, cfg.GDPR.TCF2, "gdpr.tcf2")
+	assert.Equal(t, GDPRVendorList{Embedded: true, URL: "https://vendor-list.consensu.org"}, cfg.GDPR.VendorList, "gdpr.vendor_list")
 }
 
 // When adding a new field, make sure the indentations are spaces not tabs otherwise read config may fail to parse the new field value.
@@ -998,6 +1001,8 @@ func TestValidateConfig(t *testing.T) {
 				},
 			},
 		},
+		LineItems: LineItems{WinTTLSeconds: 3600},
+		Shutdown:  Shutdown{DrainTimeoutMS: 10000},
 	}
 
 	v := viper.New()
@@ -1020,6 +1025,24 @@ func TestMigrateConfigFromEnv(t *testing.T) {
 	cmpStrings(t, "adapters.bidder1.endpoint", "http://bidder1_override.com", cfg.BidderInfos["bidder1"].Endpoint)
 }
 
+func TestBidderTransportFromEnv(t *testing.T) {
+	for _, env := range []string{"PBS_ADAPTERS_BIDDER1_TRANSPORT_MAXCONNSPERHOST", "PBS_ADAPTERS_BIDDER1_TRANSPORT_TLS_MINVERSION"} {
+		if oldval, ok := os.LookupEnv(env); ok {
+			defer os.Setenv(env, oldval)
+		} else {
+			defer os.Unsetenv(env)
+		}
+	}
+
+	os.Setenv("PBS_ADAPTERS_BIDDER1_TRANSPORT_MAXCONNSPERHOST", "25")
+	os.Setenv("PBS_ADAPTERS_BIDDER1_TRANSPORT_TLS_MINVERSION", "1.3")
+	cfg, _ := newDefaultConfig(t)
+
+	expected := &BidderTransport{MaxConnsPerHost: 25, TLS: &BidderTransportTLS{MinVersion: "1.3"}}
+	assert.Equal(t, expected, cfg.BidderInfos["bidder1"].Transport)
+	assert.Nil(t, cfg.BidderInfos["bidder2"].Transport)
+}
+
 func TestUserSyncFromEnv(t *testing.T) {
 	truePtr := true
 
@@ -1436,6 +1459,264 @@ func TestValidateDebug(t *testing.T) {
 	assert.NotNil(t, err, "cfg.debug.timeout_notification.sampling_rate should not be allowed to be greater than 1.0, but it was allowed")
 }
 
+func TestValidateFrequencyCapping(t *testing.T) {
+	testCases := []struct {
+		description    string
+		cfg            FrequencyCapping
+		expectedErrors []error
+	}{
+		{
+			description: "disabled",
+			cfg:         FrequencyCapping{Store: "redis"},
+		},
+		{
+			description: "memory",
+			cfg:         FrequencyCapping{Enabled: true, Store: FrequencyCapStoreMemory, ServedTTLSeconds: 3600},
+		},
+		{
+			description: "invalid",
+			cfg:         FrequencyCapping{Enabled: true, Store: "redis"},
+			expectedErrors: []error{
+				errors.New(`frequency_capping.store "redis" is not supported`),
+				errors.New("frequency_capping.served_ttl_seconds must be > 0. Got 0"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expectedErrors, test.cfg.validate(nil))
+		})
+	}
+}
+
+func TestValidateLineItems(t *testing.T) {
+	assert.Empty(t, (&LineItems{WinTTLSeconds: 3600}).validate(nil))
+	assert.Equal(t, []error{errors.New("line_items.win_ttl_seconds must be > 0. Got 0")}, (&LineItems{}).validate(nil))
+}
+
+func TestValidateStatsDMetrics(t *testing.T) {
+	testCases := []struct {
+		description    string
+		cfg            StatsDMetrics
+		expectedErrors []error
+	}{
+		{
+			description: "disabled",
+		},
+		{
+			description: "enabled",
+			cfg:         StatsDMetrics{Host: "localhost:8125", MaxPacketSize: 1432, FlushIntervalMS: 100},
+		},
+		{
+			description: "invalid",
+			cfg:         StatsDMetrics{Host: "localhost:8125"},
+			expectedErrors: []error{
+				errors.New("metrics.statsd.max_packet_size must be positive if metrics.statsd.host is defined. Got 0"),
+				errors.New("metrics.statsd.flush_interval_ms must be positive if metrics.statsd.host is defined. Got 0"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expectedErrors, test.cfg.validate(nil))
+		})
+	}
+}
+
+func TestValidateConfigReload(t *testing.T) {
+	testCases := []struct {
+		description    string
+		cfg            ConfigReload
+		expectedErrors []error
+	}{
+		{
+			description: "disabled",
+			cfg:         ConfigReload{WatchFiles: true},
+		},
+		{
+			description: "watch-files",
+			cfg:         ConfigReload{Enabled: true, WatchFiles: true, WatchDelayMS: 1000},
+		},
+		{
+			description: "invalid-watch-delay",
+			cfg:         ConfigReload{Enabled: true, WatchFiles: true},
+			expectedErrors: []error{
+				errors.New("config_reload.watch_delay_ms must be > 0 when config_reload.watch_files is enabled. Got 0"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expectedErrors, test.cfg.validate(nil))
+		})
+	}
+}
+
+func TestValidateReadiness(t *testing.T) {
+	assert.Nil(t, (&Readiness{}).validate(nil))
+	assert.Equal(t, []error{errors.New("readiness.warmup_timeout_ms must be >= 0. Got -1")}, (&Readiness{WarmupTimeoutMS: -1}).validate(nil))
+}
+
+func TestValidateShutdown(t *testing.T) {
+	testCases := []struct {
+		description    string
+		cfg            Shutdown
+		expectedErrors []error
+	}{
+		{
+			description: "valid",
+			cfg:         Shutdown{NotReadyDelayMS: 5000, DrainTimeoutMS: 10000},
+		},
+		{
+			description: "invalid",
+			cfg:         Shutdown{NotReadyDelayMS: -1, DrainTimeoutMS: -1},
+			expectedErrors: []error{
+				errors.New("shutdown.not_ready_delay_ms must be >= 0. Got -1"),
+				errors.New("shutdown.drain_timeout_ms must be > 0. Got -1"),
+			},
+		},
+		{
+			description: "no-drain-timeout",
+			cfg:         Shutdown{DrainTimeoutMS: 0},
+			expectedErrors: []error{
+				errors.New("shutdown.drain_timeout_ms must be > 0. Got 0"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expectedErrors, test.cfg.validate(nil))
+		})
+	}
+}
+
+func TestValidateGDPRVendorList(t *testing.T) {
+	testCases := []struct {
+		description    string
+		cfg            GDPRVendorList
+		expectedErrors []error
+	}{
+		{
+			description: "offline",
+			cfg:         GDPRVendorList{Directory: "/var/gvl", Embedded: true},
+		},
+		{
+			description: "http",
+			cfg:         GDPRVendorList{URL: "https://vendor-list.consensu.org", RefreshIntervalSeconds: 3600},
+		},
+		{
+			description: "file",
+			cfg:         GDPRVendorList{URL: "file:///var/gvl"},
+		},
+		{
+			description: "invalid",
+			cfg:         GDPRVendorList{URL: "vendor-list.consensu.org", RefreshIntervalSeconds: -1},
+			expectedErrors: []error{
+				errors.New("gdpr.vendor_list.url must be an http, https or file URL. Got vendor-list.consensu.org"),
+				errors.New("gdpr.vendor_list.refresh_interval_seconds must be >= 0. Got -1"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expectedErrors, test.cfg.validate(nil))
+		})
+	}
+}
+
+func TestValidateTracing(t *testing.T) {
+	testCases := []struct {
+		description    string
+		cfg            Tracing
+		expectedErrors []error
+	}{
+		{
+			description: "disabled",
+			cfg:         Tracing{SamplingRate: 2},
+		},
+		{
+			description: "enabled",
+			cfg:         Tracing{Enabled: true, SamplingRate: 0.5, Exporter: TracingExporter{Endpoint: "localhost:4318"}},
+		},
+		{
+			description: "invalid",
+			cfg:         Tracing{Enabled: true, SamplingRate: -1},
+			expectedErrors: []error{
+				errors.New("tracing.sampling_rate must be between 0 and 1. Got -1.000000"),
+				errors.New("tracing.exporter.endpoint must be set when tracing is enabled"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expectedErrors, test.cfg.validate(nil))
+		})
+	}
+}
+
+func TestValidateStoredResponseRecording(t *testing.T) {
+	testCases := []struct {
+		description    string
+		cfg            StoredResponseRecording
+		overrideToken  string
+		expectedErrors []error
+	}{
+		{
+			description: "disabled",
+			cfg:         StoredResponseRecording{Files: FileFetcherConfig{Enabled: true}},
+		},
+		{
+			description:   "filesystem",
+			cfg:           StoredResponseRecording{Enabled: true, Files: FileFetcherConfig{Enabled: true, Path: "/recordings"}, TimeoutMS: 1000},
+			overrideToken: "token",
+		},
+		{
+			description: "database",
+			cfg: StoredResponseRecording{
+				Enabled:   true,
+				Database:  StoredResponseRecordingDatabase{ConnectionInfo: DatabaseConnection{Database: "pbs"}, Query: "INSERT INTO stored_responses (id, responseData) VALUES ($ID, $DATA)"},
+				TimeoutMS: 1000,
+			},
+			overrideToken: "token",
+		},
+		{
+			description: "no_backend",
+			cfg:         StoredResponseRecording{Enabled: true, TimeoutMS: 1000},
+			expectedErrors: []error{
+				errors.New("stored_response_recording: exactly one of filesystem or database must be configured"),
+				errors.New("stored_response_recording requires debug.override_token, which authorizes recording requests"),
+			},
+		},
+		{
+			description: "invalid_backends",
+			cfg: StoredResponseRecording{
+				Enabled:  true,
+				Files:    FileFetcherConfig{Enabled: true},
+				Database: StoredResponseRecordingDatabase{ConnectionInfo: DatabaseConnection{Database: "pbs"}},
+			},
+			overrideToken: "token",
+			expectedErrors: []error{
+				errors.New("stored_response_recording: exactly one of filesystem or database must be configured"),
+				errors.New("stored_response_recording.filesystem.directorypath must not be empty"),
+				errors.New("stored_response_recording.database.query must not be empty"),
+				errors.New("stored_response_recording.timeout_ms must be > 0. Got 0"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expectedErrors, test.cfg.validate(test.overrideToken, nil))
+		})
+	}
+}
+
 func TestValidateAccountsConfigRestrictions(t *testing.T) {
 	cfg, v := newDefaultConfig(t)
 	cfg.Accounts.Files.Enabled = true
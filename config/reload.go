@@ -0,0 +1,77 @@
+package config
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Reload returns the configuration to continue with once the host configuration was loaded again as reloaded.
+// Only the bidder endpoints, extra info and disabled flags, the account defaults and the hook execution plans
+// are taken from reloaded, everything else is kept from cfg. The keys of the other settings which changed are
+// returned, sorted, as they require a restart to take effect.
+func (cfg *Configuration) Reload(reloaded *Configuration) (*Configuration, []string) {
+	effective := *cfg
+
+	effective.BidderInfos = make(BidderInfos, len(cfg.BidderInfos))
+	for name, info := range cfg.BidderInfos {
+		if reloadedInfo, ok := reloaded.BidderInfos[name]; ok {
+			info.Endpoint = reloadedInfo.Endpoint
+			info.ExtraAdapterInfo = reloadedInfo.ExtraAdapterInfo
+			info.Disabled = reloadedInfo.Disabled
+		}
+		effective.BidderInfos[name] = info
+	}
+
+	effective.AccountDefaults = reloaded.AccountDefaults
+	effective.accountDefaultsJSON = reloaded.accountDefaultsJSON
+
+	effective.Hooks.HostExecutionPlan = reloaded.Hooks.HostExecutionPlan
+	effective.Hooks.DefaultAccountExecutionPlan = reloaded.Hooks.DefaultAccountExecutionPlan
+
+	return &effective, changedKeys(&effective, reloaded)
+}
+
+// changedKeys lists the top level keys of the host configuration with a different value in a and b. Bidder
+// infos are compared per bidder.
+func changedKeys(a, b *Configuration) []string {
+	var keys []string
+
+	aValue := reflect.ValueOf(a).Elem()
+	bValue := reflect.ValueOf(b).Elem()
+	for i := 0; i < aValue.NumField(); i++ {
+		field := aValue.Type().Field(i)
+		key, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+		if key == "" {
+			// untagged fields are derived from the tagged ones
+			continue
+		}
+
+		if key == "adapters" {
+			keys = append(keys, changedBidderInfos(a.BidderInfos, b.BidderInfos)...)
+			continue
+		}
+
+		if !reflect.DeepEqual(aValue.Field(i).Interface(), bValue.Field(i).Interface()) {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+func changedBidderInfos(a, b BidderInfos) []string {
+	var keys []string
+	for name, info := range a {
+		if other, ok := b[name]; !ok || !reflect.DeepEqual(info, other) {
+			keys = append(keys, "adapters."+name)
+		}
+	}
+	for name := range b {
+		if _, ok := a[name]; !ok {
+			keys = append(keys, "adapters."+name)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newReloadTestConfig(t *testing.T, yaml string) *Configuration {
+	v := viper.New()
+	SetupViper(v, "", bidderInfos)
+	v.Set("gdpr.default_value", "0")
+	v.SetConfigType("yaml")
+	require.NoError(t, v.ReadConfig(bytes.NewBufferString(yaml)))
+	cfg, err := New(v, bidderInfos, mockNormalizeBidderName)
+	require.NoError(t, err)
+	return cfg
+}
+
+func TestReload(t *testing.T) {
+	current := newReloadTestConfig(t, `
+port: 8000
+account_defaults:
+  debug_allow: true
+`)
+
+	testCases := []struct {
+		description             string
+		reloaded                string
+		expectedRestartRequired []string
+		assertEffective         func(t *testing.T, effective *Configuration)
+	}{
+		{
+			description: "unchanged",
+			reloaded: `
+port: 8000
+account_defaults:
+  debug_allow: true
+`,
+			assertEffective: func(t *testing.T, effective *Configuration) {
+				assert.Equal(t, current, effective)
+			},
+		},
+		{
+			description: "reloadable",
+			reloaded: `
+port: 8000
+adapters:
+  bidder1:
+    endpoint: http://new.bidder1.com
+    extra_info: "{\"region\":\"eu\"}"
+  bidder2:
+    disabled: true
+account_defaults:
+  debug_allow: false
+hooks:
+  host_execution_plan:
+    endpoints:
+      /openrtb2/auction:
+        stages:
+          entrypoint:
+            groups:
+              - timeout: 5
+                hook_sequence:
+                  - module_code: vendor.module
+                    hook_impl_code: code
+`,
+			assertEffective: func(t *testing.T, effective *Configuration) {
+				assert.Equal(t, "http://new.bidder1.com", effective.BidderInfos["bidder1"].Endpoint)
+				assert.Equal(t, `{"region":"eu"}`, effective.BidderInfos["bidder1"].ExtraAdapterInfo)
+				assert.True(t, effective.BidderInfos["bidder2"].Disabled)
+				assert.False(t, effective.AccountDefaults.DebugAllow)
+				assert.JSONEq(t, string(effective.AccountDefaultsJSON()), string(mustMarshal(t, effective.AccountDefaults)))
+				assert.Len(t, effective.Hooks.HostExecutionPlan.Endpoints, 1)
+				assert.Equal(t, "http://bidder1.com", current.BidderInfos["bidder1"].Endpoint, "the current configuration is left untouched")
+				assert.True(t, current.AccountDefaults.DebugAllow, "the current configuration is left untouched")
+			},
+		},
+		{
+			description: "requires-restart",
+			reloaded: `
+port: 9000
+account_defaults:
+  debug_allow: true
+adapters:
+  bidder1:
+    endpoint: http://new.bidder1.com
+    platform_id: "123"
+hooks:
+  enabled: true
+`,
+			expectedRestartRequired: []string{"adapters.bidder1", "hooks", "port"},
+			assertEffective: func(t *testing.T, effective *Configuration) {
+				assert.Equal(t, 8000, effective.Port)
+				assert.Empty(t, effective.BidderInfos["bidder1"].PlatformID)
+				assert.Equal(t, "http://new.bidder1.com", effective.BidderInfos["bidder1"].Endpoint)
+				assert.False(t, effective.Hooks.Enabled)
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			reloaded := newReloadTestConfig(t, test.reloaded)
+
+			effective, restartRequired := current.Reload(reloaded)
+
+			assert.Equal(t, test.expectedRestartRequired, restartRequired)
+			test.assertEffective(t, effective)
+		})
+	}
+}
+
+func mustMarshal(t *testing.T, account Account) []byte {
+	cfg := Configuration{AccountDefaults: account}
+	require.NoError(t, cfg.MarshalAccountDefaults())
+	return cfg.AccountDefaultsJSON()
+}
@@ -112,6 +112,51 @@ type HTTPFetcherConfig struct {
 	UseRfcCompliantBuilder bool   `mapstructure:"use_rfc3986_compliant_request_builder"`
 }
 
+// StoredResponseRecording configures a stored_responses/writer used to record live auctions as stored responses.
+// Exactly one of the file system or database backends must be configured when recording is enabled.
+type StoredResponseRecording struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Files writes each stored response to "{directorypath}/stored_responses/{id}.json", the layout read by the file fetcher.
+	Files FileFetcherConfig `mapstructure:"filesystem"`
+	// Database writes each stored response with a query against the table read by the database fetcher.
+	Database StoredResponseRecordingDatabase `mapstructure:"database"`
+	// TimeoutMS bounds the time spent writing the stored responses of a single auction.
+	TimeoutMS int `mapstructure:"timeout_ms"`
+}
+
+// StoredResponseRecordingDatabase configures a stored_responses/writer database backend.
+type StoredResponseRecordingDatabase struct {
+	ConnectionInfo DatabaseConnection `mapstructure:"connection"`
+	// Query writes a single stored response. The $ID and $DATA parameters are replaced with the
+	// stored response ID and JSON, for example:
+	//   INSERT INTO stored_responses (id, responseData) VALUES ($ID, $DATA)
+	Query string `mapstructure:"query"`
+}
+
+func (cfg *StoredResponseRecording) validate(debugOverrideToken string, errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+
+	databaseEnabled := cfg.Database.ConnectionInfo.Database != ""
+	if cfg.Files.Enabled == databaseEnabled {
+		errs = append(errs, fmt.Errorf("stored_response_recording: exactly one of filesystem or database must be configured"))
+	}
+	if cfg.Files.Enabled && cfg.Files.Path == "" {
+		errs = append(errs, fmt.Errorf("stored_response_recording.filesystem.directorypath must not be empty"))
+	}
+	if databaseEnabled && cfg.Database.Query == "" {
+		errs = append(errs, fmt.Errorf("stored_response_recording.database.query must not be empty"))
+	}
+	if cfg.TimeoutMS <= 0 {
+		errs = append(errs, fmt.Errorf("stored_response_recording.timeout_ms must be > 0. Got %d", cfg.TimeoutMS))
+	}
+	if debugOverrideToken == "" {
+		errs = append(errs, fmt.Errorf("stored_response_recording requires debug.override_token, which authorizes recording requests"))
+	}
+	return errs
+}
+
 // Migrate combined stored_requests+amp configuration to separate simple config sections
 func resolvedStoredRequestsConfig(cfg *Configuration) {
 	sr := &cfg.StoredRequests
@@ -0,0 +1,49 @@
+package endpoints
+
+import (
+	"net/http"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// ConfigReloader reloads the host configuration, returning the changed keys which require a restart.
+type ConfigReloader interface {
+	Reload() ([]string, error)
+}
+
+type configReloadResponse struct {
+	RestartRequired []string `json:"restart_required"`
+}
+
+// NewConfigReloadEndpoint reloads the host configuration on POST. The configuration in effect is kept if the
+// reloaded one fails validation.
+func NewConfigReloadEndpoint(reloader ConfigReloader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		restartRequired, err := reloader.Reload()
+		if err != nil {
+			glog.Errorf("/config/reload failed, the current configuration is kept: %v", err)
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		if restartRequired == nil {
+			restartRequired = []string{}
+		}
+		jsonOutput, err := jsonutil.Marshal(configReloadResponse{RestartRequired: restartRequired})
+		if err != nil {
+			glog.Errorf("/config/reload Critical error when trying to marshal the response: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(jsonOutput)
+	}
+}
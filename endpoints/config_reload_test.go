@@ -0,0 +1,74 @@
+package endpoints
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeConfigReloader struct {
+	restartRequired []string
+	err             error
+	calls           int
+}
+
+func (r *fakeConfigReloader) Reload() ([]string, error) {
+	r.calls++
+	return r.restartRequired, r.err
+}
+
+func TestConfigReloadEndpoint(t *testing.T) {
+	testCases := []struct {
+		description   string
+		method        string
+		reloader      *fakeConfigReloader
+		expectedCode  int
+		expectedBody  string
+		expectedCalls int
+	}{
+		{
+			description:   "reloaded",
+			method:        http.MethodPost,
+			reloader:      &fakeConfigReloader{},
+			expectedCode:  http.StatusOK,
+			expectedBody:  `{"restart_required":[]}`,
+			expectedCalls: 1,
+		},
+		{
+			description:   "restart-required",
+			method:        http.MethodPost,
+			reloader:      &fakeConfigReloader{restartRequired: []string{"gdpr", "port"}},
+			expectedCode:  http.StatusOK,
+			expectedBody:  `{"restart_required":["gdpr","port"]}`,
+			expectedCalls: 1,
+		},
+		{
+			description:   "invalid-config",
+			method:        http.MethodPost,
+			reloader:      &fakeConfigReloader{err: errors.New("validation errors")},
+			expectedCode:  http.StatusUnprocessableEntity,
+			expectedBody:  "validation errors",
+			expectedCalls: 1,
+		},
+		{
+			description:  "get",
+			method:       http.MethodGet,
+			reloader:     &fakeConfigReloader{},
+			expectedCode: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			NewConfigReloadEndpoint(test.reloader)(w, httptest.NewRequest(test.method, "/config/reload", nil))
+
+			assert.Equal(t, test.expectedCode, w.Code)
+			assert.Equal(t, test.expectedBody, w.Body.String())
+			assert.Equal(t, test.expectedCalls, test.reloader.calls)
+		})
+	}
+}
@@ -136,7 +136,7 @@ func event(cfg *config.Configuration, fetcher stored_requests.AccountFetcher, ac
 		r    *http.Request
 	}{
 		name: "event",
-		h:    NewEventEndpoint(cfg, fetcher, nil, &metrics.MetricsEngineMock{}),
+		h:    NewEventEndpoint(cfg, fetcher, nil, &metrics.MetricsEngineMock{}, nil, nil),
 		r:    httptest.NewRequest("GET", "/event?t=win&b=test&ts=1234&f=b&x=1&a="+accountID, strings.NewReader("")),
 	}
 }
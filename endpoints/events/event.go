@@ -12,11 +12,14 @@ import (
 
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 
+	"github.com/golang/glog"
 	"github.com/julienschmidt/httprouter"
 	accountService "github.com/prebid/prebid-server/v3/account"
 	"github.com/prebid/prebid-server/v3/analytics"
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/frequencycap"
+	"github.com/prebid/prebid-server/v3/lineitems"
 	"github.com/prebid/prebid-server/v3/metrics"
 	"github.com/prebid/prebid-server/v3/privacy"
 	"github.com/prebid/prebid-server/v3/stored_requests"
@@ -47,15 +50,19 @@ type eventEndpoint struct {
 	Cfg           *config.Configuration
 	TrackingPixel *httputil.Pixel
 	MetricsEngine metrics.MetricsEngine
+	FrequencyCaps *frequencycap.Service
+	LineItems     lineitems.Service
 }
 
-func NewEventEndpoint(cfg *config.Configuration, accounts stored_requests.AccountFetcher, analytics analytics.Runner, me metrics.MetricsEngine) httprouter.Handle {
+func NewEventEndpoint(cfg *config.Configuration, accounts stored_requests.AccountFetcher, analytics analytics.Runner, me metrics.MetricsEngine, frequencyCaps *frequencycap.Service, lineItems lineitems.Service) httprouter.Handle {
 	ee := &eventEndpoint{
 		Accounts:      accounts,
 		Analytics:     analytics,
 		Cfg:           cfg,
 		TrackingPixel: &httputil.Pixel1x1PNG,
 		MetricsEngine: me,
+		FrequencyCaps: frequencyCaps,
+		LineItems:     lineItems,
 	}
 
 	return ee.Handle
@@ -86,7 +93,9 @@ func (e *eventEndpoint) Handle(w http.ResponseWriter, r *http.Request, _ httprou
 	}
 	eventRequest.AccountID = accountId
 
-	if eventRequest.Analytics != analytics.Enabled {
+	// impressions count against frequency caps and line item goals even if analytics are disabled
+	countImpression := (e.FrequencyCaps != nil || e.LineItems != nil) && eventRequest.Type == analytics.Imp
+	if eventRequest.Analytics != analytics.Enabled && !countImpression {
 		w.WriteHeader(http.StatusNoContent)
 		return
 	}
@@ -100,6 +109,13 @@ func (e *eventEndpoint) Handle(w http.ResponseWriter, r *http.Request, _ httprou
 
 	// get account details
 	account, errs := accountService.GetAccount(ctx, e.Cfg, e.Accounts, eventRequest.AccountID, e.MetricsEngine)
+
+	// without analytics, the event only matters to the accounts counting impressions
+	if eventRequest.Analytics != analytics.Enabled && (len(errs) > 0 || !account.Events.Enabled || !e.countsImpressions(account)) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
 	if len(errs) > 0 {
 		status, messages := HandleAccountServiceErrors(errs)
 		w.WriteHeader(status)
@@ -117,6 +133,21 @@ func (e *eventEndpoint) Handle(w http.ResponseWriter, r *http.Request, _ httprou
 		return
 	}
 
+	if countImpression && e.LineItems != nil && len(account.LineItems) > 0 {
+		e.LineItems.RecordImpression(eventRequest.AccountID, eventRequest.BidID, e.hostCookieUID(r))
+	}
+
+	if countImpression && e.FrequencyCaps != nil && len(account.FrequencyCaps) > 0 {
+		if err := e.FrequencyCaps.RecordImpression(ctx, eventRequest.AccountID, eventRequest.BidID, e.hostCookieUID(r)); err != nil {
+			glog.Warningf("Unable to count impression of bid %s for frequency capping: %v", eventRequest.BidID, err)
+		}
+	}
+
+	if eventRequest.Analytics != analytics.Enabled {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
 	activities := privacy.NewActivityControl(&account.Privacy)
 
 	// handle notification event
@@ -137,6 +168,23 @@ func (e *eventEndpoint) Handle(w http.ResponseWriter, r *http.Request, _ httprou
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// countsImpressions returns true if the impressions of the account count against its line item goals or
+// frequency caps.
+func (e *eventEndpoint) countsImpressions(account *config.Account) bool {
+	return (e.LineItems != nil && len(account.LineItems) > 0) || (e.FrequencyCaps != nil && len(account.FrequencyCaps) > 0)
+}
+
+// hostCookieUID returns the user id of the host cookie, if the event request carries one.
+func (e *eventEndpoint) hostCookieUID(r *http.Request) string {
+	if e.Cfg.HostCookie.CookieName == "" {
+		return ""
+	}
+	if cookie, err := r.Cookie(e.Cfg.HostCookie.CookieName); err == nil {
+		return cookie.Value
+	}
+	return ""
+}
+
 // EventRequestToUrl converts an analytics.EventRequest to an URL
 func EventRequestToUrl(externalUrl string, request *analytics.EventRequest) string {
 	s := fmt.Sprintf(TemplateUrl, externalUrl, request.Type, request.BidID, request.AccountID)
@@ -12,13 +12,19 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v3/analytics"
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/frequencycap"
+	"github.com/prebid/prebid-server/v3/lineitems"
 	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/privacy"
 	"github.com/prebid/prebid-server/v3/stored_requests"
+	"github.com/prebid/prebid-server/v3/util/timeutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type eventsMockAnalyticsModule struct {
@@ -71,6 +77,8 @@ var mockAccountData = map[string]json.RawMessage{
 	"events_disabled": json.RawMessage(`{"events": {"enabled":false}}`),
 	"malformed_acct":  json.RawMessage(`{"events": {"enabled":"invalid type"}}`),
 	"disabled_acct":   json.RawMessage(`{"disabled": true}`),
+	"frequency_caps":  json.RawMessage(`{"events": {"enabled":true}, "frequency_caps": [{"type":"adomain","count":1,"period_sec":60}]}`),
+	"line_items":      json.RawMessage(`{"events": {"enabled":true}, "line_items": [{"id":"li","source":"appnexus","deal_id":"deal"}]}`),
 }
 
 type mockAccountsFetcher struct {
@@ -123,7 +131,7 @@ func TestShouldReturnBadRequestWhenTypeIsMissing(t *testing.T) {
 	req := httptest.NewRequest("GET", "/event?b=test", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, nil, nil)
 
 	// execute
 	e(recorder, req, nil)
@@ -159,7 +167,7 @@ func TestShouldReturnBadRequestWhenTypeIsInvalid(t *testing.T) {
 	req := httptest.NewRequest("GET", "/event?t=test&b=t", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccounts, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccounts, mockAnalyticsModule, &metrics.MetricsEngineMock{}, nil, nil)
 
 	// execute
 	e(recorder, req, nil)
@@ -195,7 +203,7 @@ func TestShouldReturnBadRequestWhenBidIdIsMissing(t *testing.T) {
 	req := httptest.NewRequest("GET", "/event?t=win", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, nil, nil)
 
 	// execute
 	e(recorder, req, nil)
@@ -231,7 +239,7 @@ func TestShouldReturnBadRequestWhenTimestampIsInvalid(t *testing.T) {
 	req := httptest.NewRequest("GET", "/event?t=win&b=test&ts=q", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, nil, nil)
 
 	// execute
 	e(recorder, req, nil)
@@ -267,7 +275,7 @@ func TestShouldReturnUnauthorizedWhenAccountIsMissing(t *testing.T) {
 	req := httptest.NewRequest("GET", "/event?t=win&b=test&ts=1234", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, nil, nil)
 
 	// execute
 	e(recorder, req, nil)
@@ -303,7 +311,7 @@ func TestShouldReturnBadRequestWhenFormatValueIsInvalid(t *testing.T) {
 	req := httptest.NewRequest("GET", "/event?t=win&b=test&ts=1234&f=q", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, nil, nil)
 
 	// execute
 	e(recorder, req, nil)
@@ -339,7 +347,7 @@ func TestShouldReturnBadRequestWhenAnalyticsValueIsInvalid(t *testing.T) {
 	req := httptest.NewRequest("GET", "/event?t=win&b=test&ts=1234&f=b&x=4", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, nil, nil)
 
 	// execute
 	e(recorder, req, nil)
@@ -378,7 +386,7 @@ func TestShouldNotPassEventToAnalyticsReporterWhenAccountNotFoundAndDefaultIsFal
 	req := httptest.NewRequest("GET", "/event?t=win&b=test&ts=1234&f=b&x=1&a=testacc", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, nil, nil)
 
 	// execute
 	e(recorder, req, nil)
@@ -412,7 +420,7 @@ func TestShouldReturnBadRequestWhenIntegrationValueIsInvalid(t *testing.T) {
 	req := httptest.NewRequest("GET", "/event?t=win&b=bidId&f=b&ts=1000&x=1&a=accountId&bidder=bidder&int=Te$tIntegrationType", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, nil, nil)
 
 	// execute
 	e(recorder, req, nil)
@@ -451,7 +459,7 @@ func TestShouldNotPassEventToAnalyticsReporterWhenAccountEventNotEnabled(t *test
 	req := httptest.NewRequest("GET", "/event?t=win&b=test&ts=1234&f=b&x=1&a=events_disabled", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, nil, nil)
 
 	// execute
 	e(recorder, req, nil)
@@ -489,7 +497,7 @@ func TestShouldPassEventToAnalyticsReporterWhenAccountEventEnabled(t *testing.T)
 	req := httptest.NewRequest("GET", "/event?t=win&b=test&ts=1234&f=b&x=1&a=events_enabled", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, nil, nil)
 
 	// execute
 	e(recorder, req, nil)
@@ -523,7 +531,7 @@ func TestShouldNotPassEventToAnalyticsReporterWhenAnalyticsValueIsZero(t *testin
 	req := httptest.NewRequest("GET", "/event?t=win&b=test&ts=1234&f=b&x=0&a=events_enabled", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, nil, nil)
 
 	// execute
 	e(recorder, req, nil)
@@ -533,6 +541,152 @@ func TestShouldNotPassEventToAnalyticsReporterWhenAnalyticsValueIsZero(t *testin
 	assert.Equal(t, true, mockAnalyticsModule.Invoked != true)
 }
 
+func TestShouldReturnNoContentWhenAnalyticsValueIsZeroWithServices(t *testing.T) {
+	testCases := []struct {
+		name    string
+		account string
+	}{
+		{name: "events-enabled", account: "events_enabled"},
+		{name: "events-disabled", account: "events_disabled"},
+		{name: "malformed-account", account: "malformed_acct"},
+		{name: "disabled-account", account: "disabled_acct"},
+		{name: "unknown-account", account: "unknown"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := &config.Configuration{AccountDefaults: config.Account{}}
+			cfg.MarshalAccountDefaults()
+			mockAnalyticsModule := &eventsMockAnalyticsModule{}
+			frequencyCaps := frequencycap.NewService(frequencycap.NewMemoryStore(&timeutil.RealTime{}), &timeutil.RealTime{}, time.Minute)
+			lineItems := lineitems.NewService(&timeutil.RealTime{}, time.Minute)
+
+			req := httptest.NewRequest("GET", "/event?t=imp&b=bidId&x=0&a="+test.account, strings.NewReader(""))
+			recorder := httptest.NewRecorder()
+
+			e := NewEventEndpoint(cfg, &mockAccountsFetcher{}, mockAnalyticsModule, &metrics.MetricsEngineMock{}, frequencyCaps, lineItems)
+			e(recorder, req, nil)
+
+			assert.Equal(t, 204, recorder.Result().StatusCode)
+			assert.False(t, mockAnalyticsModule.Invoked)
+		})
+	}
+}
+
+func TestShouldCountImpressionForFrequencyCaps(t *testing.T) {
+	caps := []config.AccountFrequencyCap{{Type: config.FrequencyCapTypeADomain, Count: 1, PeriodSeconds: 60}}
+	bid := &openrtb2.Bid{ADomain: []string{"advertiser.com"}}
+
+	testCases := []struct {
+		name           string
+		url            string
+		hostCookie     string
+		expectedCapped map[string]bool
+	}{
+		{
+			name:           "auction-user",
+			url:            "/event?t=imp&b=bidId&x=0&a=frequency_caps",
+			expectedCapped: map[string]bool{"auction-user": true, "cookie-user": false},
+		},
+		{
+			name:           "host-cookie-user",
+			url:            "/event?t=imp&b=bidId&a=frequency_caps",
+			hostCookie:     "cookie-user",
+			expectedCapped: map[string]bool{"auction-user": true, "cookie-user": false},
+		},
+		{
+			name:           "win-event",
+			url:            "/event?t=win&b=bidId&a=frequency_caps",
+			expectedCapped: map[string]bool{"auction-user": false, "cookie-user": false},
+		},
+		{
+			name:           "unknown-bid",
+			url:            "/event?t=imp&b=otherBidId&a=frequency_caps",
+			expectedCapped: map[string]bool{"auction-user": false, "cookie-user": false},
+		},
+		{
+			name:           "account-without-caps",
+			url:            "/event?t=imp&b=bidId&a=events_enabled",
+			expectedCapped: map[string]bool{"auction-user": false, "cookie-user": false},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := &config.Configuration{
+				AccountDefaults: config.Account{},
+				HostCookie:      config.HostCookie{CookieName: "host"},
+			}
+			cfg.MarshalAccountDefaults()
+
+			service := frequencycap.NewService(frequencycap.NewMemoryStore(&timeutil.RealTime{}), &timeutil.RealTime{}, time.Minute)
+			require.NoError(t, service.RecordServed(context.Background(), "frequency_caps", "bidId", "auction-user", caps, bid))
+			require.NoError(t, service.RecordServed(context.Background(), "events_enabled", "bidId", "auction-user", caps, bid))
+
+			req := httptest.NewRequest("GET", test.url, strings.NewReader(""))
+			if test.hostCookie != "" {
+				req.AddCookie(&http.Cookie{Name: "host", Value: test.hostCookie})
+			}
+			recorder := httptest.NewRecorder()
+
+			e := NewEventEndpoint(cfg, &mockAccountsFetcher{}, &eventsMockAnalyticsModule{}, &metrics.MetricsEngineMock{}, service, nil)
+			e(recorder, req, nil)
+
+			assert.Equal(t, 204, recorder.Result().StatusCode)
+			for userID, expectedCapped := range test.expectedCapped {
+				capped, err := service.Capped(context.Background(), "frequency_caps", caps, userID, bid)
+				assert.NoError(t, err)
+				assert.Equal(t, expectedCapped, capped, userID)
+			}
+		})
+	}
+}
+
+func TestShouldCountImpressionForLineItems(t *testing.T) {
+	testCases := []struct {
+		name              string
+		url               string
+		expectedDelivered int64
+	}{
+		{
+			name:              "impression",
+			url:               "/event?t=imp&b=bidId&x=0&a=line_items",
+			expectedDelivered: 1,
+		},
+		{
+			name: "win-event",
+			url:  "/event?t=win&b=bidId&a=line_items",
+		},
+		{
+			name: "unknown-bid",
+			url:  "/event?t=imp&b=otherBidId&a=line_items",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := &config.Configuration{AccountDefaults: config.Account{}}
+			cfg.MarshalAccountDefaults()
+
+			account := &config.Account{ID: "line_items", LineItems: []config.AccountLineItem{{ID: "li", Source: "appnexus", DealID: "deal"}}}
+			service := lineitems.NewService(&timeutil.RealTime{}, time.Minute)
+			service.Match(account, &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp"}}}}, "")
+			service.RecordWin("line_items", "li", "bidId", "user")
+
+			req := httptest.NewRequest("GET", test.url, strings.NewReader(""))
+			recorder := httptest.NewRecorder()
+
+			e := NewEventEndpoint(cfg, &mockAccountsFetcher{}, &eventsMockAnalyticsModule{}, &metrics.MetricsEngineMock{}, nil, service)
+			e(recorder, req, nil)
+
+			assert.Equal(t, 204, recorder.Result().StatusCode)
+			reports := service.Report("line_items")
+			require.Len(t, reports, 1)
+			assert.Equal(t, test.expectedDelivered, reports[0].Delivered)
+		})
+	}
+}
+
 func TestShouldRespondWithPixelAndContentTypeWhenRequestFormatIsImage(t *testing.T) {
 
 	// mock AccountsFetcher
@@ -557,7 +711,7 @@ func TestShouldRespondWithPixelAndContentTypeWhenRequestFormatIsImage(t *testing
 	req := httptest.NewRequest("GET", "/event?t=win&b=test&ts=1234&f=i&x=1&a=events_enabled", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, nil, nil)
 
 	// execute
 	e(recorder, req, nil)
@@ -598,7 +752,7 @@ func TestShouldRespondWithNoContentWhenRequestFormatIsNotDefined(t *testing.T) {
 	req := httptest.NewRequest("GET", "/event?t=imp&b=test&ts=1234&x=1&a=events_enabled", strings.NewReader(reqData))
 	recorder := httptest.NewRecorder()
 
-	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+	e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, nil, nil)
 
 	// execute
 	e(recorder, req, nil)
@@ -815,7 +969,7 @@ func TestShouldReturnBadRequestWhenVTypeIsInvalid(t *testing.T) {
 
 		recorder := httptest.NewRecorder()
 
-		e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{})
+		e := NewEventEndpoint(cfg, mockAccountsFetcher, mockAnalyticsModule, &metrics.MetricsEngineMock{}, nil, nil)
 		e(recorder, test.req, nil)
 
 		d, err := io.ReadAll(recorder.Result().Body)
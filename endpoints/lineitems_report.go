@@ -0,0 +1,32 @@
+package endpoints
+
+import (
+	"net/http"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v3/lineitems"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// NewLineItemsReportEndpoint returns the delivery of the line items of the account given by the account query
+// parameter.
+func NewLineItemsReportEndpoint(service lineitems.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		accountID := r.URL.Query().Get("account")
+		if accountID == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("Missing account query parameter"))
+			return
+		}
+
+		jsonOutput, err := jsonutil.Marshal(service.Report(accountID))
+		if err != nil {
+			glog.Errorf("/lineitems/report Critical error when trying to marshal line item report: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(jsonOutput)
+	}
+}
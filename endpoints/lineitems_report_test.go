@@ -0,0 +1,58 @@
+package endpoints
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/lineitems"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLineItemsReportEndpoint(t *testing.T) {
+	service := lineitems.NewService(&fakeTime{time: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)}, time.Hour)
+	account := &config.Account{ID: "account", LineItems: []config.AccountLineItem{{ID: "li", Source: "appnexus", DealID: "deal"}}}
+	req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp"}}}}
+	service.Match(account, req, "")
+
+	testCases := []struct {
+		description  string
+		url          string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			description:  "missing-account",
+			url:          "/lineitems/report",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Missing account query parameter",
+		},
+		{
+			description:  "unknown-account",
+			url:          "/lineitems/report?account=other",
+			expectedCode: http.StatusOK,
+			expectedBody: `[]`,
+		},
+		{
+			description:  "account",
+			url:          "/lineitems/report?account=account",
+			expectedCode: http.StatusOK,
+			expectedBody: `[{"id":"li","source":"appnexus","deal_id":"deal","matched":1,"ready_to_serve":1,"delivered":0,"paced":0,"frequency_capped":0}]`,
+		},
+	}
+
+	handler := NewLineItemsReportEndpoint(service)
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			handler(recorder, httptest.NewRequest(http.MethodGet, test.url, nil))
+
+			assert.Equal(t, test.expectedCode, recorder.Code)
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+		})
+	}
+}
@@ -137,6 +137,7 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 	defer func() {
 		deps.metricsEngine.RecordRequest(labels)
 		deps.metricsEngine.RecordRequestTime(labels, time.Since(start))
+		ao.PrivacyAudit = activityControl.Log().Evaluations()
 		deps.analytics.LogAmpObject(&ao, activityControl)
 	}()
 
@@ -177,7 +178,7 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 
 	ao.RequestWrapper = reqWrapper
 
-	ctx := context.Background()
+	ctx := context.WithoutCancel(r.Context())
 	var cancel context.CancelFunc
 	if reqWrapper.TMax > 0 {
 		ctx, cancel = context.WithDeadline(ctx, start.Add(time.Duration(reqWrapper.TMax)*time.Millisecond))
@@ -246,7 +247,7 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 		return
 	}
 
-	activityControl = privacy.NewActivityControl(&account.Privacy)
+	activityControl = privacy.NewActivityControl(&account.Privacy).WithLog(&privacy.ActivityLog{})
 
 	hookExecutor.SetActivityControl(activityControl)
 	hookExecutor.SetAccount(account)
@@ -545,7 +546,7 @@ func (deps *endpointDeps) loadRequestJSONForAmp(httpRequest *http.Request, label
 		return nil, nil, nil, nil, []error{err}
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(deps.cfg.StoredRequestsTimeout)*time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(httpRequest.Context()), time.Duration(deps.cfg.StoredRequestsTimeout)*time.Millisecond)
 	defer cancel()
 
 	storedRequests, _, errs := deps.storedReqFetcher.FetchRequests(ctx, []string{ampParams.StoredRequestID}, nil)
@@ -185,6 +185,7 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 	defer func() {
 		deps.metricsEngine.RecordRequest(labels)
 		deps.metricsEngine.RecordRequestTime(labels, time.Since(start))
+		ao.PrivacyAudit = activityControl.Log().Evaluations()
 		deps.analytics.LogAuctionObject(&ao, activityControl)
 	}()
 
@@ -202,12 +203,12 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 		return
 	}
 
-	activityControl = privacy.NewActivityControl(&account.Privacy)
+	activityControl = privacy.NewActivityControl(&account.Privacy).WithLog(&privacy.ActivityLog{})
 
 	hookExecutor.SetActivityControl(activityControl)
 	hookExecutor.SetAccount(account)
 
-	ctx := context.Background()
+	ctx := context.WithoutCancel(r.Context())
 
 	timeout := deps.cfg.AuctionTimeouts.LimitAuctionTimeout(time.Duration(req.TMax) * time.Millisecond)
 	if timeout > 0 {
@@ -472,7 +473,7 @@ func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metric
 	}
 
 	timeout := parseTimeout(requestJson, time.Duration(deps.cfg.StoredRequestsTimeout)*time.Millisecond)
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(httpRequest.Context()), timeout)
 	defer cancel()
 
 	impInfo, errs := parseImpInfo(requestJson)
@@ -1147,24 +1148,24 @@ func validateTargeting(t *openrtb_ext.ExtRequestTargeting) error {
 	}
 
 	if t.PriceGranularity != nil {
-		if err := validatePriceGranularity(t.PriceGranularity); err != nil {
+		if err := openrtb_ext.ValidatePriceGranularity(t.PriceGranularity); err != nil {
 			return err
 		}
 	}
 
 	if t.MediaTypePriceGranularity != nil {
 		if t.MediaTypePriceGranularity.Video != nil {
-			if err := validatePriceGranularity(t.MediaTypePriceGranularity.Video); err != nil {
+			if err := openrtb_ext.ValidatePriceGranularity(t.MediaTypePriceGranularity.Video); err != nil {
 				return err
 			}
 		}
 		if t.MediaTypePriceGranularity.Banner != nil {
-			if err := validatePriceGranularity(t.MediaTypePriceGranularity.Banner); err != nil {
+			if err := openrtb_ext.ValidatePriceGranularity(t.MediaTypePriceGranularity.Banner); err != nil {
 				return err
 			}
 		}
 		if t.MediaTypePriceGranularity.Native != nil {
-			if err := validatePriceGranularity(t.MediaTypePriceGranularity.Native); err != nil {
+			if err := openrtb_ext.ValidatePriceGranularity(t.MediaTypePriceGranularity.Native); err != nil {
 				return err
 			}
 		}
@@ -1173,29 +1174,6 @@ func validateTargeting(t *openrtb_ext.ExtRequestTargeting) error {
 	return nil
 }
 
-func validatePriceGranularity(pg *openrtb_ext.PriceGranularity) error {
-	if pg.Precision == nil {
-		return errors.New("Price granularity error: precision is required")
-	} else if *pg.Precision < 0 {
-		return errors.New("Price granularity error: precision must be non-negative")
-	} else if *pg.Precision > openrtb_ext.MaxDecimalFigures {
-		return fmt.Errorf("Price granularity error: precision of more than %d significant figures is not supported", openrtb_ext.MaxDecimalFigures)
-	}
-
-	var prevMax float64 = 0
-	for _, gr := range pg.Ranges {
-		if gr.Max <= prevMax {
-			return errors.New(`Price granularity error: range list must be ordered with increasing "max"`)
-		}
-
-		if gr.Increment <= 0.0 {
-			return errors.New("Price granularity error: increment must be a nonzero positive number")
-		}
-		prevMax = gr.Max
-	}
-	return nil
-}
-
 func (deps *endpointDeps) validateSite(req *openrtb_ext.RequestWrapper) error {
 	if req.Site == nil {
 		return nil
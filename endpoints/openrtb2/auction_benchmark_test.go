@@ -100,6 +100,9 @@ func BenchmarkOpenrtbEndpoint(b *testing.B) {
 		macros.NewStringIndexBasedReplacer(),
 		nil,
 		singleFormatBidders,
+		nil,
+		nil,
+		nil,
 	)
 
 	endpoint, _ := NewEndpoint(
@@ -2163,80 +2163,6 @@ func TestValidateTargeting(t *testing.T) {
 	}
 }
 
-func TestValidatePriceGranularity(t *testing.T) {
-	testCases := []struct {
-		description           string
-		givenPriceGranularity *openrtb_ext.PriceGranularity
-		expectedError         error
-	}{
-		{
-			description: "Precision is nil",
-			givenPriceGranularity: &openrtb_ext.PriceGranularity{
-				Precision: nil,
-			},
-			expectedError: errors.New("Price granularity error: precision is required"),
-		},
-		{
-			description: "Precision is negative",
-			givenPriceGranularity: &openrtb_ext.PriceGranularity{
-				Precision: ptrutil.ToPtr(-1),
-			},
-			expectedError: errors.New("Price granularity error: precision must be non-negative"),
-		},
-		{
-			description: "Precision is too big",
-			givenPriceGranularity: &openrtb_ext.PriceGranularity{
-				Precision: ptrutil.ToPtr(20),
-			},
-			expectedError: errors.New("Price granularity error: precision of more than 15 significant figures is not supported"),
-		},
-		{
-			description: "price granularity ranges out of order",
-			givenPriceGranularity: &openrtb_ext.PriceGranularity{
-				Precision: ptrutil.ToPtr(2),
-				Ranges: []openrtb_ext.GranularityRange{
-					{Min: 1.0, Max: 2.0, Increment: 0.2},
-					{Min: 0.0, Max: 1.0, Increment: 0.5},
-				},
-			},
-			expectedError: errors.New(`Price granularity error: range list must be ordered with increasing "max"`),
-		},
-		{
-			description: "price granularity negative increment",
-			givenPriceGranularity: &openrtb_ext.PriceGranularity{
-				Precision: ptrutil.ToPtr(2),
-				Ranges: []openrtb_ext.GranularityRange{
-					{Min: 0.0, Max: 1.0, Increment: -0.1},
-				},
-			},
-			expectedError: errors.New("Price granularity error: increment must be a nonzero positive number"),
-		},
-		{
-			description: "price granularity correct",
-			givenPriceGranularity: &openrtb_ext.PriceGranularity{
-				Precision: ptrutil.ToPtr(2),
-				Ranges: []openrtb_ext.GranularityRange{
-					{Min: 0.0, Max: 10.0, Increment: 1},
-				},
-			},
-			expectedError: nil,
-		},
-		{
-			description: "price granularity with correct precision and ranges not specified",
-			givenPriceGranularity: &openrtb_ext.PriceGranularity{
-				Precision: ptrutil.ToPtr(2),
-			},
-			expectedError: nil,
-		},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.description, func(t *testing.T) {
-			assert.Equal(t, tc.expectedError, validatePriceGranularity(tc.givenPriceGranularity))
-		})
-	}
-}
-
 func TestValidateOrFillChannel(t *testing.T) {
 	testCases := []struct {
 		description           string
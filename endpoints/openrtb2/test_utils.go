@@ -1276,6 +1276,9 @@ func buildTestExchange(testCfg *testConfigValues, adapterMap map[openrtb_ext.Bid
 		macros.NewStringIndexBasedReplacer(),
 		nil,
 		singleFormatBidders,
+		nil,
+		nil,
+		nil,
 	)
 
 	testExchange = &exchangeTestWrapper{
@@ -161,6 +161,7 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 		}
 		deps.metricsEngine.RecordRequest(labels)
 		deps.metricsEngine.RecordRequestTime(labels, time.Since(start))
+		vo.PrivacyAudit = activityControl.Log().Evaluations()
 		deps.analytics.LogVideoObject(&vo, activityControl)
 	}()
 
@@ -197,7 +198,7 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 			return
 		}
 	} else {
-		storedRequest, errs := deps.loadStoredVideoRequest(context.Background(), storedRequestId)
+		storedRequest, errs := deps.loadStoredVideoRequest(context.WithoutCancel(r.Context()), storedRequestId)
 		if len(errs) > 0 {
 			handleError(&labels, w, errs, &vo, &debugLog)
 			return
@@ -271,7 +272,7 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	ctx := context.Background()
+	ctx := context.WithoutCancel(r.Context())
 	timeout := deps.cfg.AuctionTimeouts.LimitAuctionTimeout(time.Duration(bidReqWrapper.TMax) * time.Millisecond)
 	if timeout > 0 {
 		var cancel context.CancelFunc
@@ -319,7 +320,7 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	activityControl = privacy.NewActivityControl(&account.Privacy)
+	activityControl = privacy.NewActivityControl(&account.Privacy).WithLog(&privacy.ActivityLog{})
 
 	warnings := errortypes.WarningOnly(errL)
 
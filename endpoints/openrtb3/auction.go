@@ -0,0 +1,142 @@
+package openrtb3
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// NewEndpoint returns the /openrtb3/auction handler. Requests are translated into OpenRTB 2.6 and run
+// through the given /openrtb2/auction handler, so they are validated, enriched and auctioned exactly like
+// native OpenRTB 2 traffic. Successful responses are translated back into OpenRTB 3.0.
+func NewEndpoint(auction httprouter.Handle, maxRequestSize int64) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		limitedReader := &io.LimitedReader{R: r.Body, N: maxRequestSize + 1}
+		requestJson, err := io.ReadAll(limitedReader)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if int64(len(requestJson)) > maxRequestSize {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("request size exceeded max size of %d bytes.", maxRequestSize))
+			return
+		}
+
+		var body openrtb3.Body
+		if err := jsonutil.UnmarshalValid(requestJson, &body); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		bidRequest, warnings, err := openrtb_ext.ConvertRequestFrom30(body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		bidRequestJson, err := jsonutil.Marshal(bidRequest)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		auctionRequest := r.Clone(r.Context())
+		auctionRequest.Body = io.NopCloser(bytes.NewReader(bidRequestJson))
+		auctionRequest.ContentLength = int64(len(bidRequestJson))
+		auctionRequest.Header.Del("Content-Encoding")
+		auctionRequest.Header.Set("Content-Length", strconv.Itoa(len(bidRequestJson)))
+
+		recorder := newResponseRecorder()
+		auction(recorder, auctionRequest, params)
+
+		// errors are plain text and carry no OpenRTB payload, so they are passed through untouched
+		if recorder.status != http.StatusOK {
+			recorder.writeTo(w)
+			return
+		}
+
+		var bidResponse openrtb2.BidResponse
+		if err := jsonutil.Unmarshal(recorder.body.Bytes(), &bidResponse); err != nil {
+			writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to read auction response: %v", err))
+			return
+		}
+
+		responseBody, err := openrtb_ext.ConvertResponseTo30(&bidResponse, warnings)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		responseJson, err := jsonutil.Marshal(responseBody)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		copyHeaders(w.Header(), recorder.header)
+		w.Header().Del("Content-Length")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(responseJson)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.WriteHeader(status)
+	if status == http.StatusBadRequest {
+		fmt.Fprintf(w, "Invalid request: %s\n", err.Error())
+	} else {
+		fmt.Fprintf(w, "Critical error while running the auction: %v", err)
+	}
+}
+
+func copyHeaders(dst, src http.Header) {
+	for name, values := range src {
+		dst[name] = append([]string(nil), values...)
+	}
+}
+
+// responseRecorder buffers the response of the wrapped auction handler so it can be translated before
+// anything is written to the client.
+type responseRecorder struct {
+	header http.Header
+	body   bytes.Buffer
+	status int
+}
+
+func newResponseRecorder() *responseRecorder {
+	return &responseRecorder{header: make(http.Header)}
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.body.Write(b)
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+func (r *responseRecorder) writeTo(w http.ResponseWriter) {
+	copyHeaders(w.Header(), r.header)
+	if r.status != 0 {
+		w.WriteHeader(r.status)
+	}
+	w.Write(r.body.Bytes())
+}
@@ -0,0 +1,94 @@
+package openrtb3
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/stretchr/testify/assert"
+)
+
+const validRequest = `{"openrtb":{"ver":"3.0","domainspec":"adcom","domainver":"1.0","request":{"id":"req1","cdata":"x","item":[{"id":"item1","spec":{"placement":{"video":{"mime":["video/mp4"]}}}}],"context":{"app":{"bundle":"com.tv"}}}}}`
+
+func TestAuction(t *testing.T) {
+	testCases := []struct {
+		name               string
+		givenBody          string
+		givenAuction       httprouter.Handle
+		expectedStatus     int
+		expectedBody       string
+		expectedBodyPrefix string
+	}{
+		{
+			name:      "translated-round-trip",
+			givenBody: validRequest,
+			givenAuction: func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+				var req openrtb2.BidRequest
+				body, _ := io.ReadAll(r.Body)
+				if err := jsonutil.Unmarshal(body, &req); err != nil || req.App == nil || len(req.Imp) != 1 || req.Imp[0].Video == nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				w.Header().Set("X-Prebid", "pbs-go")
+				w.Write([]byte(`{"id":"req1","seatbid":[{"seat":"appnexus","bid":[{"id":"bid1","impid":"item1","price":1,"adm":"<VAST/>","mtype":2}]}],"cur":"USD"}`))
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"openrtb":{"ver":"3.0","domainspec":"adcom","domainver":"1.0","response":{"id":"req1","cur":"USD","seatbid":[{"seat":"appnexus","bid":[{"id":"bid1","item":"item1","price":1,"media":{"id":"","video":{"adm":"<VAST/>"}}}]}],"ext":{"warnings":{"general":[{"code":10017,"message":"request.cdata has no OpenRTB 2 equivalent and was ignored"}]}}}}}`,
+		},
+		{
+			name:      "auction-error-passed-through",
+			givenBody: validRequest,
+			givenAuction: func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte("Invalid request: request.imp[0] bad\n"))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid request: request.imp[0] bad\n",
+		},
+		{
+			name:               "malformed-json",
+			givenBody:          `{`,
+			expectedStatus:     http.StatusBadRequest,
+			expectedBodyPrefix: "Invalid request: ",
+		},
+		{
+			name:           "untranslatable-request",
+			givenBody:      `{"openrtb":{"domainspec":"other","request":{"id":"req1"}}}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid request: openrtb.domainspec \"other\" is not supported, only \"adcom\" can be translated\n",
+		},
+		{
+			name:           "request-too-large",
+			givenBody:      validRequest + strings.Repeat(" ", 1000),
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid request: request size exceeded max size of 1000 bytes.\n",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			endpoint := NewEndpoint(test.givenAuction, 1000)
+
+			req := httptest.NewRequest(http.MethodPost, "/openrtb3/auction", strings.NewReader(test.givenBody))
+			recorder := httptest.NewRecorder()
+			endpoint(recorder, req, nil)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			switch {
+			case test.expectedBodyPrefix != "":
+				assert.True(t, strings.HasPrefix(recorder.Body.String(), test.expectedBodyPrefix), recorder.Body.String())
+			case test.expectedStatus == http.StatusOK:
+				assert.JSONEq(t, test.expectedBody, recorder.Body.String())
+				assert.Equal(t, "pbs-go", recorder.Header().Get("X-Prebid"))
+				assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+			default:
+				assert.Equal(t, test.expectedBody, recorder.Body.String())
+			}
+		})
+	}
+}
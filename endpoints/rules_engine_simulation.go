@@ -0,0 +1,127 @@
+package endpoints
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine"
+	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// maxRulesEngineSimulationSize bounds the size of the ruleset and sample requests posted for a simulation.
+const maxRulesEngineSimulationSize = 32 << 20
+
+type rulesEngineSimulationRequest struct {
+	Config   json.RawMessage   `json:"config"`
+	Requests []json.RawMessage `json:"requests"`
+}
+
+// NewRulesEngineSimulationEndpoint runs a batch of sample requests through the rulesets of a rules engine
+// configuration on POST, without running any auction, and reports the rules fired, the schema function values
+// seen and the bidders kept by each model group. The body is either a JSON object with the configuration and an
+// array of requests, or a multipart form with a config field and a requests file of captured requests, given
+// as a JSON array or one request per line.
+func NewRulesEngineSimulationEndpoint(schemaValidator *gojsonschema.Schema) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxRulesEngineSimulationSize)
+		simulationRequest, err := readRulesEngineSimulationRequest(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		if len(simulationRequest.Config) == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("Missing rules engine config"))
+			return
+		}
+
+		cfg, err := config.NewConfig(simulationRequest.Config, schemaValidator)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		jsonOutput, err := jsonutil.Marshal(rulesengine.Simulate(cfg, simulationRequest.Requests))
+		if err != nil {
+			glog.Errorf("/rules/simulate Critical error when trying to marshal the response: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(jsonOutput)
+	}
+}
+
+func readRulesEngineSimulationRequest(r *http.Request) (rulesEngineSimulationRequest, error) {
+	var simulationRequest rulesEngineSimulationRequest
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return simulationRequest, err
+		}
+		if err := jsonutil.UnmarshalValid(body, &simulationRequest); err != nil {
+			return simulationRequest, fmt.Errorf("Invalid simulation request: %v", err)
+		}
+		return simulationRequest, nil
+	}
+
+	if err := r.ParseMultipartForm(maxRulesEngineSimulationSize); err != nil {
+		return simulationRequest, err
+	}
+	simulationRequest.Config = json.RawMessage(r.FormValue("config"))
+
+	file, _, err := r.FormFile("requests")
+	if err != nil {
+		return simulationRequest, fmt.Errorf("Missing requests file: %v", err)
+	}
+	defer file.Close()
+
+	simulationRequest.Requests, err = readCapturedRequests(file)
+	return simulationRequest, err
+}
+
+// readCapturedRequests reads requests given either as a JSON array or as one request per line.
+func readCapturedRequests(file io.Reader) ([]json.RawMessage, error) {
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var requests []json.RawMessage
+	if trimmed := bytes.TrimSpace(content); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := jsonutil.UnmarshalValid(trimmed, &requests); err != nil {
+			return nil, fmt.Errorf("Invalid requests file: %v", err)
+		}
+		return requests, nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRulesEngineSimulationSize)
+	for scanner.Scan() {
+		if line := bytes.TrimSpace(scanner.Bytes()); len(line) > 0 {
+			requests = append(requests, json.RawMessage(bytes.Clone(line)))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Invalid requests file: %v", err)
+	}
+	return requests, nil
+}
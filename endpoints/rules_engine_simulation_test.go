@@ -0,0 +1,126 @@
+package endpoints
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const rulesEngineSimulationConfig = `{
+  "enabled": true,
+  "rulesets": [{
+    "stage": "processed_auction_request",
+    "name": "country-rules",
+    "modelgroups": [{
+      "schema": [{"function": "deviceCountry"}],
+      "rules": [{
+        "conditions": ["USA"],
+        "results": [{"function": "excludeBidders", "args": {"bidders": ["bidderA"]}}]
+      }]
+    }]
+  }]
+}`
+
+func TestRulesEngineSimulationEndpoint(t *testing.T) {
+	schemaValidator, err := config.CreateSchemaValidator("../modules/prebid/rulesengine/config/" + config.RulesEngineSchemaFile)
+	require.NoError(t, err)
+	endpoint := NewRulesEngineSimulationEndpoint(schemaValidator)
+
+	testCases := []struct {
+		description          string
+		method               string
+		body                 string
+		expectedCode         int
+		expectedBodyContains string
+	}{
+		{
+			description:  "get",
+			method:       http.MethodGet,
+			expectedCode: http.StatusMethodNotAllowed,
+		},
+		{
+			description:          "malformed-body",
+			method:               http.MethodPost,
+			body:                 `malformed`,
+			expectedCode:         http.StatusBadRequest,
+			expectedBodyContains: "Invalid simulation request",
+		},
+		{
+			description:          "missing-config",
+			method:               http.MethodPost,
+			body:                 `{"requests":[{"id":"req-1"}]}`,
+			expectedCode:         http.StatusBadRequest,
+			expectedBodyContains: "Missing rules engine config",
+		},
+		{
+			description:          "invalid-config",
+			method:               http.MethodPost,
+			body:                 `{"config":{"enabled":true},"requests":[{"id":"req-1"}]}`,
+			expectedCode:         http.StatusBadRequest,
+			expectedBodyContains: "JSON schema validation",
+		},
+		{
+			description:          "simulated",
+			method:               http.MethodPost,
+			body:                 `{"config":` + rulesEngineSimulationConfig + `,"requests":[{"id":"req-1","device":{"geo":{"country":"USA"}},"imp":[{"id":"imp-1","ext":{"prebid":{"bidder":{"bidderA":{},"bidderB":{}}}}}]}]}`,
+			expectedCode:         http.StatusOK,
+			expectedBodyContains: `"requestid":"req-1","rulefired":"USA","schemafunctionresults":[{"function":"deviceCountry","value":"USA"}],"bidders":["bidderB"]`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "/rules/simulate", strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+
+			endpoint(w, req)
+
+			assert.Equal(t, tc.expectedCode, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedBodyContains)
+		})
+	}
+}
+
+func TestRulesEngineSimulationEndpointCapturedRequests(t *testing.T) {
+	schemaValidator, err := config.CreateSchemaValidator("../modules/prebid/rulesengine/config/" + config.RulesEngineSchemaFile)
+	require.NoError(t, err)
+
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+	require.NoError(t, form.WriteField("config", rulesEngineSimulationConfig))
+	file, err := form.CreateFormFile("requests", "captured.jsonl")
+	require.NoError(t, err)
+	file.Write([]byte("{\"id\":\"req-1\",\"device\":{\"geo\":{\"country\":\"USA\"}}}\n\n{\"id\":\"req-2\",\"device\":{\"geo\":{\"country\":\"CAN\"}}}\n"))
+	require.NoError(t, form.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/rules/simulate", body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	w := httptest.NewRecorder()
+
+	NewRulesEngineSimulationEndpoint(schemaValidator)(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `"requests":2`)
+	assert.Contains(t, w.Body.String(), `"rulesfired":{"USA":1,"default":1}`)
+}
+
+func TestReadCapturedRequests(t *testing.T) {
+	requests, err := readCapturedRequests(strings.NewReader(` [{"id":"req-1"},{"id":"req-2"}]`))
+	assert.NoError(t, err)
+	assert.Len(t, requests, 2)
+
+	requests, err = readCapturedRequests(strings.NewReader("{\"id\":\"req-1\"}\n{\"id\":\"req-2\"}\n"))
+	assert.NoError(t, err)
+	assert.Len(t, requests, 2)
+
+	_, err = readCapturedRequests(strings.NewReader(`[malformed`))
+	assert.ErrorContains(t, err, "Invalid requests file")
+}
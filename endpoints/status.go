@@ -2,21 +2,28 @@ package endpoints
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/julienschmidt/httprouter"
+	"github.com/prebid/prebid-server/v3/readiness"
 )
 
 // NewStatusEndpoint returns a handler which writes the given response when the app is ready to serve requests.
-func NewStatusEndpoint(response string) httprouter.Handle {
-	// Today, the app always considers itself ready to serve requests.
-	if response == "" {
-		return func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
-			w.WriteHeader(http.StatusNoContent)
-		}
-	}
-
+// Until then, while caches and other dependencies warm up or once it is shutting down, it responds with a 503
+// listing what it is waiting for.
+func NewStatusEndpoint(response string, tracker *readiness.Tracker) httprouter.Handle {
 	responseBytes := []byte(response)
 	return func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+		if ready, pending := tracker.Status(); !ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("not ready: " + strings.Join(pending, ", ")))
+			return
+		}
+
+		if len(responseBytes) == 0 {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
 		w.Write(responseBytes)
 	}
 }
@@ -4,10 +4,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/prebid/prebid-server/v3/readiness"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestStatusNoContent(t *testing.T) {
-	handler := NewStatusEndpoint("")
+	handler := NewStatusEndpoint("", nil)
 	w := httptest.NewRecorder()
 	handler(w, nil, nil)
 	if w.Code != http.StatusNoContent {
@@ -16,7 +19,7 @@ func TestStatusNoContent(t *testing.T) {
 }
 
 func TestStatusWithContent(t *testing.T) {
-	handler := NewStatusEndpoint("ready")
+	handler := NewStatusEndpoint("ready", nil)
 	w := httptest.NewRecorder()
 	handler(w, nil, nil)
 	if w.Code != http.StatusOK {
@@ -26,3 +29,27 @@ func TestStatusWithContent(t *testing.T) {
 		t.Errorf("Bad status body. Expected %s, got %s", "ready", w.Body.String())
 	}
 }
+
+func TestStatusReadiness(t *testing.T) {
+	tracker := readiness.NewTracker()
+	currencyWarm := tracker.Register("currency_rates")
+	tracker.Register("gdpr_vendor_list")()
+	handler := NewStatusEndpoint("ready", tracker)
+
+	w := httptest.NewRecorder()
+	handler(w, nil, nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "not ready: currency_rates", w.Body.String())
+
+	currencyWarm()
+	w = httptest.NewRecorder()
+	handler(w, nil, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "ready", w.Body.String())
+
+	tracker.Drain()
+	w = httptest.NewRecorder()
+	handler(w, nil, nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "not ready: shutdown", w.Body.String())
+}
@@ -0,0 +1,123 @@
+package endpoints
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/julienschmidt/httprouter"
+	accountService "github.com/prebid/prebid-server/v3/account"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/gdpr"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/stored_requests"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// tcfDebugResponse is the response of the /debug/tcf endpoint.
+type tcfDebugResponse struct {
+	Account string                                          `json:"account"`
+	Consent string                                          `json:"consent"`
+	Bidders map[openrtb_ext.BidderName]gdpr.AuctionDecision `json:"bidders"`
+}
+
+// NewTCFDebugEndpoint returns how the TCF consent string given by the consent query parameter is enforced for
+// each bidder in an auction of the account given by the account query parameter. The bidders evaluated may be
+// narrowed down with a comma separated bidders query parameter, and default to every enabled bidder. The
+// account must allow debug.
+func NewTCFDebugEndpoint(cfg *config.Configuration, gdprPermsBuilder gdpr.PermissionsBuilder, tcf2CfgBuilder gdpr.TCF2ConfigBuilder, accountsFetcher stored_requests.AccountFetcher, metricsEngine metrics.MetricsEngine) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		query := r.URL.Query()
+
+		accountID := query.Get("account")
+		if accountID == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("Missing account query parameter"))
+			return
+		}
+		consent := query.Get("consent")
+		if consent == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("Missing consent query parameter"))
+			return
+		}
+
+		bidders, err := tcfDebugBidders(cfg.BidderInfos, query.Get("bidders"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		account, fetchErrs := accountService.GetAccount(r.Context(), cfg, accountsFetcher, accountID, metricsEngine)
+		if len(fetchErrs) > 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(combineErrors(fetchErrs).Error()))
+			return
+		}
+		if !account.DebugAllow {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("Debug is not allowed for the account"))
+			return
+		}
+
+		tcf2Cfg := tcf2CfgBuilder(cfg.GDPR.TCF2, account.GDPR)
+		perms := gdprPermsBuilder(tcf2Cfg, gdpr.RequestInfo{
+			Consent:     consent,
+			GDPRSignal:  gdpr.SignalYes,
+			PublisherID: accountID,
+		})
+
+		response := tcfDebugResponse{
+			Account: accountID,
+			Consent: consent,
+			Bidders: make(map[openrtb_ext.BidderName]gdpr.AuctionDecision, len(bidders)),
+		}
+		for _, bidder := range bidders {
+			coreBidder := bidder
+			if aliasOf := cfg.BidderInfos[string(bidder)].AliasOf; aliasOf != "" {
+				coreBidder = openrtb_ext.BidderName(aliasOf)
+			}
+			response.Bidders[bidder] = gdpr.ExplainAuctionActivities(context.Background(), perms, coreBidder, bidder)
+		}
+
+		jsonOutput, err := jsonutil.Marshal(response)
+		if err != nil {
+			glog.Errorf("/debug/tcf Critical error when trying to marshal the TCF decisions: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(jsonOutput)
+	}
+}
+
+// tcfDebugBidders returns the bidders of a comma separated list, or every enabled bidder if the list is empty.
+func tcfDebugBidders(bidderInfos config.BidderInfos, list string) ([]openrtb_ext.BidderName, error) {
+	var bidders []openrtb_ext.BidderName
+
+	if list == "" {
+		for name, info := range bidderInfos {
+			if info.IsEnabled() {
+				bidders = append(bidders, openrtb_ext.BidderName(name))
+			}
+		}
+		sort.Slice(bidders, func(i, j int) bool { return bidders[i] < bidders[j] })
+		return bidders, nil
+	}
+
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		info, ok := bidderInfos[name]
+		if !ok || !info.IsEnabled() {
+			return nil, fmt.Errorf("Unknown or disabled bidder %s", name)
+		}
+		bidders = append(bidders, openrtb_ext.BidderName(name))
+	}
+	return bidders, nil
+}
@@ -0,0 +1,105 @@
+package endpoints
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/gdpr"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTCFDebugEndpoint(t *testing.T) {
+	testCases := []struct {
+		description  string
+		url          string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			description:  "missing-account",
+			url:          "/debug/tcf?consent=consent",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Missing account query parameter",
+		},
+		{
+			description:  "missing-consent",
+			url:          "/debug/tcf?account=debug_acct",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Missing consent query parameter",
+		},
+		{
+			description:  "unknown-bidder",
+			url:          "/debug/tcf?account=debug_acct&consent=consent&bidders=unknown",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Unknown or disabled bidder unknown",
+		},
+		{
+			description:  "disabled-bidder",
+			url:          "/debug/tcf?account=debug_acct&consent=consent&bidders=disabled",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Unknown or disabled bidder disabled",
+		},
+		{
+			description:  "unknown-account-uses-account-defaults",
+			url:          "/debug/tcf?account=unknown&consent=consent",
+			expectedCode: http.StatusForbidden,
+			expectedBody: "Debug is not allowed for the account",
+		},
+		{
+			description:  "debug-not-allowed",
+			url:          "/debug/tcf?account=valid_acct&consent=consent",
+			expectedCode: http.StatusForbidden,
+			expectedBody: "Debug is not allowed for the account",
+		},
+		{
+			description:  "all-enabled-bidders",
+			url:          "/debug/tcf?account=debug_acct&consent=consent",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"account":"debug_acct","consent":"consent","bidders":{` +
+				`"alias":{"reason":"GDPR is not enforced","vendoringvl":false,"permissions":{"allowbidrequest":true,"passgeo":true,"passid":true}},` +
+				`"appnexus":{"reason":"GDPR is not enforced","vendoringvl":false,"permissions":{"allowbidrequest":true,"passgeo":true,"passid":true}}}}`,
+		},
+		{
+			description:  "bidders",
+			url:          "/debug/tcf?account=debug_acct&consent=consent&bidders=appnexus",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"account":"debug_acct","consent":"consent","bidders":{` +
+				`"appnexus":{"reason":"GDPR is not enforced","vendoringvl":false,"permissions":{"allowbidrequest":true,"passgeo":true,"passid":true}}}}`,
+		},
+	}
+
+	cfg := &config.Configuration{
+		BidderInfos: config.BidderInfos{
+			"appnexus": {Disabled: false},
+			"alias":    {Disabled: false, AliasOf: "appnexus"},
+			"disabled": {Disabled: true},
+		},
+	}
+	cfg.MarshalAccountDefaults()
+
+	gdprPermsBuilder := fakePermissionsBuilder{
+		permissions: &gdpr.AlwaysAllow{},
+	}.Builder
+	tcf2ConfigBuilder := fakeTCF2ConfigBuilder{
+		cfg: gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{}),
+	}.Builder
+	accountsFetcher := FakeAccountsFetcher{AccountData: map[string]json.RawMessage{
+		"valid_acct": json.RawMessage(`{"disabled":false}`),
+		"debug_acct": json.RawMessage(`{"debug_allow":true}`),
+	}}
+
+	handler := NewTCFDebugEndpoint(cfg, gdprPermsBuilder, tcf2ConfigBuilder, accountsFetcher, &metrics.MetricsEngineMock{})
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			handler(recorder, httptest.NewRequest(http.MethodGet, test.url, nil), nil)
+
+			assert.Equal(t, test.expectedCode, recorder.Code)
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+		})
+	}
+}
@@ -40,6 +40,14 @@ const (
 	InvalidUserUIDsWarningCode
 	TooLongTargetingPrefixWarningCode
 	TooShortTargetingPrefixWarningCode
+	OpenRTB3ConversionWarningCode
+	LineItemWarningCode
+	FrequencyCapWarningCode
+	StoredResponseRecordingWarningCode
+	InvalidNativeBidWarningCode
+	NativeRenderWarningCode
+	InsecureCreativeWarningCode
+	CompetitiveSeparationWarningCode
 )
 
 // Coder provides an error or warning code with severity.
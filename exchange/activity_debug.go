@@ -0,0 +1,30 @@
+package exchange
+
+import (
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/privacy"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// setActivityEvaluations adds the privacy audit trail of the request, which holds the activity control evaluations
+// along with the rules which decided them and the scrubbers which ran, within bidResponse.Ext.Prebid.Debug.Privacy
+func setActivityEvaluations(bidResponseExt *openrtb_ext.ExtBidResponse, log *privacy.ActivityLog) error {
+	evaluations := log.Evaluations()
+	if len(evaluations) == 0 || bidResponseExt == nil {
+		return nil
+	}
+
+	activities, err := jsonutil.Marshal(evaluations)
+	if err != nil {
+		return err
+	}
+
+	if bidResponseExt.Prebid == nil {
+		bidResponseExt.Prebid = &openrtb_ext.ExtResponsePrebid{}
+	}
+	if bidResponseExt.Prebid.Debug == nil {
+		bidResponseExt.Prebid.Debug = &openrtb_ext.ExtResponsePrebidDebug{}
+	}
+	bidResponseExt.Prebid.Debug.Privacy = activities
+	return nil
+}
@@ -0,0 +1,31 @@
+package exchange
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/privacy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetActivityEvaluations(t *testing.T) {
+	bidResponseExt := &openrtb_ext.ExtBidResponse{}
+
+	assert.NoError(t, setActivityEvaluations(bidResponseExt, nil))
+	assert.Nil(t, bidResponseExt.Prebid, "no evaluations are recorded when debug isn't allowed")
+
+	log := &privacy.ActivityLog{}
+	activities := privacy.NewActivityControl(&config.AccountPrivacy{
+		AllowActivities: &config.AllowActivities{
+			FetchBids: config.Activity{Rules: []config.ActivityRule{{Condition: config.ActivityCondition{ComponentName: []string{"appnexus"}}}}},
+		},
+	}).WithLog(log)
+	activities.Allow(privacy.ActivityFetchBids, privacy.Component{Type: privacy.ComponentTypeBidder, Name: "appnexus"}, privacy.ActivityRequest{})
+
+	assert.NoError(t, setActivityEvaluations(bidResponseExt, log))
+	assert.Equal(t, &openrtb_ext.ExtResponsePrebid{Debug: &openrtb_ext.ExtResponsePrebidDebug{
+		Privacy: json.RawMessage(`[{"activity":"fetchBids","componenttype":"bidder","componentname":"appnexus","allowed":false,"ruleindex":0,"rule":{"condition":{"componentName":["appnexus"],"componentType":null},"allow":false}}]`),
+	}}, bidResponseExt.Prebid)
+}
@@ -22,10 +22,19 @@ func BuildAdapters(client *http.Client, cfg *config.Configuration, infos config.
 	exchangeBidders := make(map[openrtb_ext.BidderName]AdaptedBidder, len(bidders))
 	for bidderName, bidder := range bidders {
 		info := infos[string(bidderName)]
-		exchangeBidder := AdaptBidder(bidder, client, cfg, me, bidderName, info.Debug, info.EndpointCompression)
-		exchangeBidder = addValidatedBidderMiddleware(exchangeBidder)
+		compression, err := newBidderCompression(info)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%v: %v", bidderName, err))
+			continue
+		}
+		bidderClient := buildBidderHttpClient(client, info.Transport, bidderName, me)
+		exchangeBidder := adaptBidder(bidder, bidderClient, cfg, me, bidderName, info.Debug, compression)
+		exchangeBidder = addValidatedBidderMiddleware(exchangeBidder, me)
 		exchangeBidders[bidderName] = exchangeBidder
 	}
+	if len(errs) > 0 {
+		return nil, nil, errs
+	}
 	return exchangeBidders, singleFormatBidders, nil
 }
 
@@ -36,12 +36,12 @@ func TestBuildAdapters(t *testing.T) {
 	appnexusBidder, _ := appnexus.Builder(openrtb_ext.BidderAppnexus, config.Adapter{}, config.Server{})
 	appnexusBidderWithInfo := adapters.BuildInfoAwareBidder(appnexusBidder, infoEnabled)
 	appnexusBidderAdapted := AdaptBidder(appnexusBidderWithInfo, client, &config.Configuration{}, metricEngine, openrtb_ext.BidderAppnexus, nil, "")
-	appnexusValidated := addValidatedBidderMiddleware(appnexusBidderAdapted)
+	appnexusValidated := addValidatedBidderMiddleware(appnexusBidderAdapted, metricEngine)
 
 	rubiconBidder, _ := rubicon.Builder(openrtb_ext.BidderRubicon, config.Adapter{}, config.Server{})
 	rubiconBidderWithInfo := adapters.BuildInfoAwareBidder(rubiconBidder, infoEnabled)
 	rubiconBidderAdapted := AdaptBidder(rubiconBidderWithInfo, client, &config.Configuration{}, metricEngine, openrtb_ext.BidderRubicon, nil, "")
-	rubiconBidderValidated := addValidatedBidderMiddleware(rubiconBidderAdapted)
+	rubiconBidderValidated := addValidatedBidderMiddleware(rubiconBidderAdapted, metricEngine)
 
 	testCases := []struct {
 		description                 string
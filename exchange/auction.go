@@ -13,6 +13,7 @@ import (
 	uuid "github.com/gofrs/uuid"
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/errortypes"
 	"github.com/prebid/prebid-server/v3/exchange/entities"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/prebid_cache_client"
@@ -135,6 +136,31 @@ func newAuction(seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, nu
 	}
 }
 
+// impWinner is the bid which won an imp and the seat it was placed by.
+type impWinner struct {
+	seat string
+	bid  *entities.PbsOrtbBid
+}
+
+// impWinners returns the winning bid of each imp. Without targeting there is no auction, so the highest bid of
+// each imp is considered the winner.
+func impWinners(auc *auction, adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid) map[string]impWinner {
+	winners := make(map[string]impWinner)
+	for seatName, seatBid := range adapterBids {
+		for _, bid := range seatBid.Bids {
+			impID := bid.Bid.ImpID
+			if auc != nil {
+				if auc.winningBids[impID] == bid {
+					winners[impID] = impWinner{seat: seatName.String(), bid: bid}
+				}
+			} else if current, ok := winners[impID]; !ok || bid.Bid.Price > current.bid.Bid.Price {
+				winners[impID] = impWinner{seat: seatName.String(), bid: bid}
+			}
+		}
+	}
+	return winners
+}
+
 // isNewWinningBid calculates if the new bid (nbid) will win against the current winning bid (wbid) given preferDeals.
 func isNewWinningBid(bid, wbid *openrtb2.Bid, preferDeals bool) bool {
 	if preferDeals {
@@ -185,6 +211,330 @@ func (a *auction) validateAndUpdateMultiBid(adapterBids map[openrtb_ext.BidderNa
 	}
 }
 
+// maxSeparationSearchNodes bounds the search for the winners of the competitive separation. Past it, the best
+// winners found so far are kept, which are at least those picked greedily imp by imp, but may not bring the most
+// revenue.
+const maxSeparationSearchNodes = 10000
+
+// separationCandidate is a bid which can win its imp, along with the advertiser domains and categories it can't
+// share with the winners of other imps
+type separationCandidate struct {
+	bid    *entities.PbsOrtbBid
+	bidder openrtb_ext.BidderName
+	keys   []string
+}
+
+// separationScore ranks sets of winners by the number of deals won when deals are preferred, then by revenue
+type separationScore struct {
+	deals   int
+	revenue float64
+}
+
+func (s separationScore) better(other separationScore) bool {
+	if s.deals != other.deals {
+		return s.deals > other.deals
+	}
+	return s.revenue > other.revenue
+}
+
+// separationSearch looks for the winners of the imps, one candidate or none per imp, with the best score it can
+// find within maxSeparationSearchNodes, without any two winners sharing a key
+type separationSearch struct {
+	candidates  [][]separationCandidate
+	preferDeals bool
+	used        map[string]int
+	choice      []int
+	best        []int
+	bestScore   separationScore
+	nodes       int
+	exhausted   bool
+}
+
+// applyCompetitiveSeparation picks the winners of the imps so that no two of them share an advertiser domain or an
+// IAB category the account doesn't allow, looking for the set of winners bringing the most revenue within
+// maxSeparationSearchNodes, and returning a warning when the search stops short of it. The bids which can't serve
+// along with the winners of the other imps are removed from the auction and the seat bids, and rejected as seat
+// non-bids.
+func (a *auction) applyCompetitiveSeparation(adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, separation config.AccountCompetitiveSeparation, preferDeals bool, seatNonBidBuilder *SeatNonBidBuilder) []error {
+	if !separation.Enabled || len(a.allBidsByBidder) < 2 {
+		return nil
+	}
+	keysOf := newSeparationKeys(separation)
+
+	// the candidates of each imp are ranked as in the auction, and the imps by their best candidate
+	ranksAbove := func(x, y *openrtb2.Bid) bool {
+		if isNewWinningBid(x, y, preferDeals) {
+			return true
+		}
+		if isNewWinningBid(y, x, preferDeals) {
+			return false
+		}
+		return x.ID < y.ID
+	}
+	impIDs := make([]string, 0, len(a.allBidsByBidder))
+	candidatesByImp := make(map[string][]separationCandidate, len(a.allBidsByBidder))
+	for impID, bidsByBidder := range a.allBidsByBidder {
+		var candidates []separationCandidate
+		for bidder, bids := range bidsByBidder {
+			for _, bid := range bids {
+				candidates = append(candidates, separationCandidate{bid: bid, bidder: bidder, keys: keysOf(bid.Bid)})
+			}
+		}
+		if len(candidates) == 0 {
+			continue
+		}
+		sort.Slice(candidates, func(x, y int) bool {
+			return ranksAbove(candidates[x].bid.Bid, candidates[y].bid.Bid)
+		})
+		impIDs = append(impIDs, impID)
+		candidatesByImp[impID] = candidates
+	}
+	sort.Slice(impIDs, func(x, y int) bool {
+		return ranksAbove(candidatesByImp[impIDs[x]][0].bid.Bid, candidatesByImp[impIDs[y]][0].bid.Bid)
+	})
+
+	search := &separationSearch{
+		candidates:  make([][]separationCandidate, len(impIDs)),
+		preferDeals: preferDeals,
+		used:        make(map[string]int),
+		choice:      make([]int, len(impIDs)),
+	}
+	for i, impID := range impIDs {
+		search.candidates[i] = candidatesByImp[impID]
+	}
+	winners := search.run()
+	var errs []error
+	if search.exhausted {
+		errs = append(errs, &errortypes.Warning{
+			Message:     fmt.Sprintf("competitive separation search stopped after %d nodes, the winners of the %d imps may not bring the most revenue", maxSeparationSearchNodes, len(impIDs)),
+			WarningCode: errortypes.CompetitiveSeparationWarningCode,
+		})
+	}
+
+	winnerKeys := make([]map[string]struct{}, len(impIDs))
+	for i, w := range winners {
+		if w < 0 {
+			delete(a.winningBids, impIDs[i])
+			continue
+		}
+		winner := search.candidates[i][w]
+		a.winningBids[impIDs[i]] = winner.bid
+		winnerKeys[i] = make(map[string]struct{}, len(winner.keys))
+		for _, key := range winner.keys {
+			winnerKeys[i][key] = struct{}{}
+		}
+	}
+
+	displaced := make(map[*entities.PbsOrtbBid]struct{})
+	for i, impID := range impIDs {
+		for _, candidate := range search.candidates[i] {
+			if conflictsWithOtherWinners(candidate.keys, winnerKeys, i) {
+				displaced[candidate.bid] = struct{}{}
+				seatNonBidBuilder.rejectBid(candidate.bid, int(ResponseRejectedCompetitiveSeparation), candidate.bidder.String())
+			}
+		}
+		a.allBidsByBidder[impID] = removeDisplacedBids(a.allBidsByBidder[impID], displaced)
+		if len(a.allBidsByBidder[impID]) == 0 {
+			delete(a.allBidsByBidder, impID)
+		}
+	}
+	if len(displaced) == 0 {
+		return errs
+	}
+	for _, seatBid := range adapterBids {
+		if seatBid == nil {
+			continue
+		}
+		bids := seatBid.Bids[:0]
+		for _, bid := range seatBid.Bids {
+			if _, ok := displaced[bid]; !ok {
+				bids = append(bids, bid)
+			}
+		}
+		seatBid.Bids = bids
+	}
+	return errs
+}
+
+// newSeparationKeys returns a function listing the advertiser domains and IAB categories of a bid the winners
+// of other imps can't share, leaving out the ones the account allows to be shared
+func newSeparationKeys(separation config.AccountCompetitiveSeparation) func(bid *openrtb2.Bid) []string {
+	allowedAdomains := make(map[string]struct{}, len(separation.AllowedAdomains))
+	for _, adomain := range separation.AllowedAdomains {
+		allowedAdomains[strings.ToLower(adomain)] = struct{}{}
+	}
+	allowedCategories := make(map[string]struct{}, len(separation.AllowedCategories))
+	for _, category := range separation.AllowedCategories {
+		allowedCategories[category] = struct{}{}
+	}
+
+	return func(bid *openrtb2.Bid) []string {
+		var keys []string
+		if !separation.AllowSharedAdomains {
+			for _, adomain := range bid.ADomain {
+				adomain = strings.ToLower(adomain)
+				if _, allowed := allowedAdomains[adomain]; !allowed && adomain != "" {
+					keys = append(keys, "adomain:"+adomain)
+				}
+			}
+		}
+		if !separation.AllowSharedCategories {
+			for _, category := range bid.Cat {
+				if _, allowed := allowedCategories[category]; !allowed && category != "" {
+					keys = append(keys, "cat:"+category)
+				}
+			}
+		}
+		return keys
+	}
+}
+
+func conflictsWithOtherWinners(keys []string, winnerKeys []map[string]struct{}, imp int) bool {
+	for other, otherKeys := range winnerKeys {
+		if other == imp {
+			continue
+		}
+		for _, key := range keys {
+			if _, ok := otherKeys[key]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func removeDisplacedBids(bidsByBidder map[openrtb_ext.BidderName][]*entities.PbsOrtbBid, displaced map[*entities.PbsOrtbBid]struct{}) map[openrtb_ext.BidderName][]*entities.PbsOrtbBid {
+	for bidder, bids := range bidsByBidder {
+		remaining := bids[:0]
+		for _, bid := range bids {
+			if _, ok := displaced[bid]; !ok {
+				remaining = append(remaining, bid)
+			}
+		}
+		if len(remaining) == 0 {
+			delete(bidsByBidder, bidder)
+			continue
+		}
+		bidsByBidder[bidder] = remaining
+	}
+	return bidsByBidder
+}
+
+// run returns the index of the winning candidate of each imp, or -1 for the imps left without a winner. The
+// greedy winners, taking the best candidate of each imp in turn, are the first best set which the search then
+// tries to beat with the other candidates, falling back to the next best bids of the imps.
+func (s *separationSearch) run() []int {
+	s.best = s.greedy()
+	s.bestScore = s.score(s.best)
+	s.visit(0, separationScore{})
+	return s.best
+}
+
+func (s *separationSearch) greedy() []int {
+	used := make(map[string]struct{})
+	winners := make([]int, len(s.candidates))
+	for i, candidates := range s.candidates {
+		winners[i] = -1
+		for c, candidate := range candidates {
+			if !containsAnyKey(used, candidate.keys) {
+				winners[i] = c
+				for _, key := range candidate.keys {
+					used[key] = struct{}{}
+				}
+				break
+			}
+		}
+	}
+	return winners
+}
+
+func (s *separationSearch) visit(imp int, score separationScore) {
+	s.nodes++
+	if s.nodes > maxSeparationSearchNodes {
+		s.exhausted = true
+		return
+	}
+	if imp == len(s.candidates) {
+		if score.better(s.bestScore) {
+			s.bestScore = score
+			s.best = append(s.best[:0], s.choice...)
+		}
+		return
+	}
+	if !s.bound(imp, score).better(s.bestScore) {
+		return
+	}
+
+	for c, candidate := range s.candidates[imp] {
+		if s.conflicts(candidate.keys) {
+			continue
+		}
+		for _, key := range candidate.keys {
+			s.used[key]++
+		}
+		s.choice[imp] = c
+		s.visit(imp+1, s.add(score, candidate))
+		for _, key := range candidate.keys {
+			s.used[key]--
+		}
+	}
+	s.choice[imp] = -1
+	s.visit(imp+1, score)
+}
+
+// bound is the best score the imps left can reach, as if each could take its best candidate
+func (s *separationSearch) bound(imp int, score separationScore) separationScore {
+	for _, candidates := range s.candidates[imp:] {
+		var deals int
+		var revenue float64
+		for _, candidate := range candidates {
+			if s.preferDeals && candidate.bid.Bid.DealID != "" {
+				deals = 1
+			}
+			revenue = max(revenue, candidate.bid.Bid.Price)
+		}
+		score.deals += deals
+		score.revenue += revenue
+	}
+	return score
+}
+
+func (s *separationSearch) score(winners []int) separationScore {
+	var score separationScore
+	for i, w := range winners {
+		if w >= 0 {
+			score = s.add(score, s.candidates[i][w])
+		}
+	}
+	return score
+}
+
+func (s *separationSearch) add(score separationScore, candidate separationCandidate) separationScore {
+	if s.preferDeals && candidate.bid.Bid.DealID != "" {
+		score.deals++
+	}
+	score.revenue += candidate.bid.Bid.Price
+	return score
+}
+
+func (s *separationSearch) conflicts(keys []string) bool {
+	for _, key := range keys {
+		if s.used[key] > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAnyKey(set map[string]struct{}, keys []string) bool {
+	for _, key := range keys {
+		if _, ok := set[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 func (a *auction) setRoundedPrices(targetingData targetData, account config.Account) {
 	roundedPrices := make(map[*entities.PbsOrtbBid]string, 5*len(a.winningBids))
 	for _, topBidsPerImp := range a.allBidsByBidder {
@@ -13,6 +13,7 @@ import (
 
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/errortypes"
 	"github.com/prebid/prebid-server/v3/exchange/entities"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/prebid_cache_client"
@@ -20,6 +21,7 @@ import (
 	"github.com/prebid/prebid-server/v3/util/ptrutil"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMakeVASTGiven(t *testing.T) {
@@ -856,6 +858,209 @@ type pbsBid struct {
 	Bidder  openrtb_ext.BidderName `json:"bidder"`
 }
 
+func TestApplyCompetitiveSeparation(t *testing.T) {
+	type testBid struct {
+		id      string
+		impID   string
+		bidder  openrtb_ext.BidderName
+		price   float64
+		adomain string
+		cat     string
+		dealID  string
+	}
+	enabled := config.AccountCompetitiveSeparation{Enabled: true}
+
+	testCases := []struct {
+		description       string
+		bids              []testBid
+		separation        config.AccountCompetitiveSeparation
+		preferDeals       bool
+		expectedWinners   map[string]string
+		expectedDisplaced []string
+	}{
+		{
+			description: "disabled",
+			bids: []testBid{
+				{id: "a", impID: "imp1", bidder: "appnexus", price: 3, adomain: "a.com"},
+				{id: "c", impID: "imp2", bidder: "pubmatic", price: 2, adomain: "a.com"},
+			},
+			expectedWinners: map[string]string{"imp1": "a", "imp2": "c"},
+		},
+		{
+			description: "no-conflict",
+			separation:  enabled,
+			bids: []testBid{
+				{id: "a", impID: "imp1", bidder: "appnexus", price: 3, adomain: "a.com", cat: "IAB1"},
+				{id: "c", impID: "imp2", bidder: "pubmatic", price: 2, adomain: "c.com", cat: "IAB2"},
+			},
+			expectedWinners: map[string]string{"imp1": "a", "imp2": "c"},
+		},
+		{
+			description: "shared-adomain-falls-back-to-next-best-bid",
+			separation:  enabled,
+			bids: []testBid{
+				{id: "a", impID: "imp1", bidder: "appnexus", price: 3, adomain: "a.com"},
+				{id: "b", impID: "imp1", bidder: "pubmatic", price: 1, adomain: "b.com"},
+				{id: "c", impID: "imp2", bidder: "appnexus", price: 2.5, adomain: "A.com"},
+				{id: "d", impID: "imp2", bidder: "pubmatic", price: 2, adomain: "d.com"},
+			},
+			expectedWinners:   map[string]string{"imp1": "a", "imp2": "d"},
+			expectedDisplaced: []string{"c"},
+		},
+		{
+			description: "revenue-maximized-over-greedy-winners",
+			separation:  enabled,
+			bids: []testBid{
+				{id: "a", impID: "imp1", bidder: "appnexus", price: 3, adomain: "a.com"},
+				{id: "b", impID: "imp1", bidder: "pubmatic", price: 2.9, adomain: "b.com"},
+				{id: "c", impID: "imp2", bidder: "appnexus", price: 2.95, adomain: "a.com"},
+				{id: "d", impID: "imp2", bidder: "pubmatic", price: 0.5, adomain: "d.com"},
+			},
+			expectedWinners:   map[string]string{"imp1": "b", "imp2": "c"},
+			expectedDisplaced: []string{"a"},
+		},
+		{
+			description: "shared-category",
+			separation:  enabled,
+			bids: []testBid{
+				{id: "a", impID: "imp1", bidder: "appnexus", price: 3, adomain: "a.com", cat: "IAB2"},
+				{id: "c", impID: "imp2", bidder: "pubmatic", price: 2, adomain: "c.com", cat: "IAB2"},
+				{id: "d", impID: "imp2", bidder: "pubmatic", price: 1, adomain: "d.com", cat: "IAB3"},
+			},
+			expectedWinners:   map[string]string{"imp1": "a", "imp2": "d"},
+			expectedDisplaced: []string{"c"},
+		},
+		{
+			description: "allowed-adomain-and-category",
+			separation:  config.AccountCompetitiveSeparation{Enabled: true, AllowedAdomains: []string{"A.com"}, AllowedCategories: []string{"IAB1"}},
+			bids: []testBid{
+				{id: "a", impID: "imp1", bidder: "appnexus", price: 3, adomain: "a.com", cat: "IAB1"},
+				{id: "c", impID: "imp2", bidder: "pubmatic", price: 2, adomain: "a.com", cat: "IAB1"},
+			},
+			expectedWinners: map[string]string{"imp1": "a", "imp2": "c"},
+		},
+		{
+			description: "shared-adomains-allowed",
+			separation:  config.AccountCompetitiveSeparation{Enabled: true, AllowSharedAdomains: true},
+			bids: []testBid{
+				{id: "a", impID: "imp1", bidder: "appnexus", price: 3, adomain: "a.com", cat: "IAB1"},
+				{id: "c", impID: "imp2", bidder: "pubmatic", price: 2, adomain: "a.com", cat: "IAB1"},
+			},
+			expectedWinners:   map[string]string{"imp1": "a"},
+			expectedDisplaced: []string{"c"},
+		},
+		{
+			description: "imp-left-without-winner",
+			separation:  enabled,
+			bids: []testBid{
+				{id: "a", impID: "imp1", bidder: "appnexus", price: 3, adomain: "a.com"},
+				{id: "c", impID: "imp2", bidder: "pubmatic", price: 1, adomain: "a.com"},
+			},
+			expectedWinners:   map[string]string{"imp1": "a"},
+			expectedDisplaced: []string{"c"},
+		},
+		{
+			description: "deals-preferred",
+			separation:  enabled,
+			preferDeals: true,
+			bids: []testBid{
+				{id: "a", impID: "imp1", bidder: "appnexus", price: 3, adomain: "a.com"},
+				{id: "c", impID: "imp2", bidder: "pubmatic", price: 1, adomain: "a.com", dealID: "deal"},
+			},
+			expectedWinners:   map[string]string{"imp2": "c"},
+			expectedDisplaced: []string{"a"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			seatBids := make(map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid)
+			for _, b := range test.bids {
+				if seatBids[b.bidder] == nil {
+					seatBids[b.bidder] = &entities.PbsOrtbSeatBid{Seat: b.bidder.String()}
+				}
+				bid := &openrtb2.Bid{ID: b.id, ImpID: b.impID, Price: b.price, DealID: b.dealID}
+				if b.adomain != "" {
+					bid.ADomain = []string{b.adomain}
+				}
+				if b.cat != "" {
+					bid.Cat = []string{b.cat}
+				}
+				seatBids[b.bidder].Bids = append(seatBids[b.bidder].Bids, &entities.PbsOrtbBid{Bid: bid})
+			}
+			seatNonBidBuilder := SeatNonBidBuilder{}
+
+			auc := newAuction(seatBids, 2, test.preferDeals)
+			errs := auc.applyCompetitiveSeparation(seatBids, test.separation, test.preferDeals, &seatNonBidBuilder)
+			assert.Empty(t, errs)
+
+			winners := make(map[string]string)
+			for impID, bid := range auc.winningBids {
+				winners[impID] = bid.Bid.ID
+			}
+			assert.Equal(t, test.expectedWinners, winners)
+
+			var remaining []string
+			for _, seatBid := range seatBids {
+				for _, bid := range seatBid.Bids {
+					remaining = append(remaining, bid.Bid.ID)
+				}
+			}
+			var auctionBids []string
+			for _, bidsByBidder := range auc.allBidsByBidder {
+				for _, bids := range bidsByBidder {
+					for _, bid := range bids {
+						auctionBids = append(auctionBids, bid.Bid.ID)
+					}
+				}
+			}
+			assert.ElementsMatch(t, remaining, auctionBids, "the auction and the seat bids hold the same bids")
+			for _, displaced := range test.expectedDisplaced {
+				assert.NotContains(t, remaining, displaced)
+			}
+			assert.Len(t, remaining, len(test.bids)-len(test.expectedDisplaced))
+
+			var nonBids int
+			for _, seatNonBids := range seatNonBidBuilder {
+				for _, nonBid := range seatNonBids {
+					assert.Equal(t, int(ResponseRejectedCompetitiveSeparation), nonBid.StatusCode)
+					nonBids++
+				}
+			}
+			assert.Equal(t, len(test.expectedDisplaced), nonBids)
+		})
+	}
+}
+
+func TestApplyCompetitiveSeparationSearchBudget(t *testing.T) {
+	// every imp has bids from the same advertisers, so only one imp can take each of them
+	seatBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{"appnexus": {Seat: "appnexus"}}
+	const imps, advertisers = 12, 12
+	for i := 0; i < imps; i++ {
+		for a := 0; a < advertisers; a++ {
+			seatBids["appnexus"].Bids = append(seatBids["appnexus"].Bids, &entities.PbsOrtbBid{Bid: &openrtb2.Bid{
+				ID:      fmt.Sprintf("%d-%d", i, a),
+				ImpID:   fmt.Sprintf("imp%d", i),
+				Price:   float64(advertisers - a),
+				ADomain: []string{fmt.Sprintf("adv%d.com", a)},
+			}})
+		}
+	}
+
+	auc := newAuction(seatBids, imps, false)
+	errs := auc.applyCompetitiveSeparation(seatBids, config.AccountCompetitiveSeparation{Enabled: true}, false, &SeatNonBidBuilder{})
+
+	require.Len(t, errs, 1, "running out of budget is reported")
+	assert.Equal(t, errortypes.CompetitiveSeparationWarningCode, errortypes.ReadCode(errs[0]))
+
+	assert.Len(t, auc.winningBids, imps, "every imp keeps a winner when the search runs out of budget")
+	adomains := make(map[string]struct{})
+	for _, bid := range auc.winningBids {
+		adomains[bid.Bid.ADomain[0]] = struct{}{}
+	}
+	assert.Len(t, adomains, imps, "no two winners share an advertiser domain")
+}
+
 type mockCache struct {
 	scheme string
 	host   string
@@ -13,6 +13,7 @@ import (
 	"math/rand"
 	"net/http"
 	"net/http/httptrace"
+	"net/url"
 	"regexp"
 	"strings"
 	"sync"
@@ -37,7 +38,9 @@ import (
 	"github.com/prebid/prebid-server/v3/errortypes"
 	"github.com/prebid/prebid-server/v3/metrics"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/tracing"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/net/context/ctxhttp"
 )
 
@@ -79,6 +82,8 @@ type bidRequestOptions struct {
 	tmaxAdjustments        *TmaxAdjustmentsPreprocessed
 	bidderRequestStartTime time.Time
 	responseDebugAllowed   bool
+	secureCreatives        string
+	pubID                  string
 }
 
 type extraBidderRespInfo struct {
@@ -98,6 +103,7 @@ const ImpIdReqBody = "Stored bid response for impression id: "
 // Possible values of compression types Prebid Server can support for bidder compression
 const (
 	Gzip string = "GZIP"
+	Zstd string = "ZSTD"
 )
 
 // AdaptBidder converts an adapters.Bidder into an exchange.AdaptedBidder.
@@ -105,6 +111,10 @@ const (
 // The name refers to the "Adapter" architecture pattern, and should not be confused with a Prebid "Adapter"
 // (which is being phased out and replaced by Bidder for OpenRTB auctions)
 func AdaptBidder(bidder adapters.Bidder, client *http.Client, cfg *config.Configuration, me metrics.MetricsEngine, name openrtb_ext.BidderName, debugInfo *config.DebugInfo, endpointCompression string) AdaptedBidder {
+	return adaptBidder(bidder, client, cfg, me, name, debugInfo, bidderCompression{endpoint: strings.ToUpper(endpointCompression)})
+}
+
+func adaptBidder(bidder adapters.Bidder, client *http.Client, cfg *config.Configuration, me metrics.MetricsEngine, name openrtb_ext.BidderName, debugInfo *config.DebugInfo, compression bidderCompression) AdaptedBidder {
 	ba := &BidderAdapter{
 		Bidder:     bidder,
 		BidderName: name,
@@ -115,7 +125,8 @@ func AdaptBidder(bidder adapters.Bidder, client *http.Client, cfg *config.Config
 			DisableConnMetrics:     cfg.Metrics.Disabled.AdapterConnectionMetrics,
 			DisableConnDialMetrics: cfg.Metrics.Disabled.AdapterConnectionDialMetrics,
 			DebugInfo:              config.DebugInfo{Allow: parseDebugInfo(debugInfo)},
-			EndpointCompression:    endpointCompression,
+			Compression:            compression,
+			PropagateTrace:         cfg.Tracing.PropagateToBidders,
 			ThrottleConfig: bidderAdapterThrottleConfig{
 				enabled:                 cfg.Client.Throttle.EnableThrottling,
 				simulateOnly:            cfg.Client.Throttle.SimulateThrottlingOnly,
@@ -157,8 +168,9 @@ type bidderAdapterConfig struct {
 	DisableConnMetrics     bool
 	DisableConnDialMetrics bool
 	DebugInfo              config.DebugInfo
-	EndpointCompression    string
+	Compression            bidderCompression
 	ThrottleConfig         bidderAdapterThrottleConfig
+	PropagateTrace         bool
 }
 
 type bidderAdapterThrottleConfig struct {
@@ -566,20 +578,44 @@ func makeExt(httpInfo *httpCallInfo) *openrtb_ext.ExtHttpCall {
 	return ext
 }
 
+// redactedURL returns the scheme, host and path of a bidder endpoint, leaving out the credentials, query and
+// fragment which may hold the secrets of the bidder account, so it can be exported to the tracing backend.
+func redactedURL(uri string) string {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return ""
+	}
+	redacted := url.URL{Scheme: parsed.Scheme, Host: parsed.Host, Path: parsed.Path, RawPath: parsed.RawPath}
+	return redacted.String()
+}
+
 // doRequest makes a request, handles the response, and returns the data needed by the
 // Bidder interface.
 func (bidder *BidderAdapter) doRequest(ctx context.Context, req *adapters.RequestData, bidderRequestStartTime time.Time, tmaxAdjustments *TmaxAdjustmentsPreprocessed) *httpCallInfo {
+	ctx, span := tracing.Start(ctx, "bidder.request",
+		attribute.String("bidder", bidder.BidderName.String()),
+		attribute.String("http.request.method", req.Method),
+		attribute.String("url.full", redactedURL(req.Uri)))
+
+	var httpInfo *httpCallInfo
 	if bidder.shouldRequest() {
-		return bidder.doRequestImpl(ctx, req, glog.Warningf, bidderRequestStartTime, tmaxAdjustments)
+		httpInfo = bidder.doRequestImpl(ctx, req, glog.Warningf, bidderRequestStartTime, tmaxAdjustments)
+	} else {
+		httpInfo = &httpCallInfo{
+			request: req,
+			err:     &errortypes.BidderThrottled{Message: fmt.Sprintf("Bidder %s is temporarily throttled", bidder.BidderName)},
+		}
 	}
-	return &httpCallInfo{
-		request: req,
-		err:     &errortypes.BidderThrottled{Message: fmt.Sprintf("Bidder %s is temporarily throttled", bidder.BidderName)},
+
+	if httpInfo.response != nil {
+		span.SetAttributes(attribute.Int("http.response.status_code", httpInfo.response.StatusCode))
 	}
+	tracing.End(span, httpInfo.err)
+	return httpInfo
 }
 
 func (bidder *BidderAdapter) doRequestImpl(ctx context.Context, req *adapters.RequestData, logger util.LogMsg, bidderRequestStartTime time.Time, tmaxAdjustments *TmaxAdjustmentsPreprocessed) *httpCallInfo {
-	requestBody, err := getRequestBody(req, bidder.config.EndpointCompression)
+	requestBody, err := getRequestBody(req, bidder.config.Compression)
 	if err != nil {
 		return &httpCallInfo{
 			request: req,
@@ -594,6 +630,21 @@ func (bidder *BidderAdapter) doRequestImpl(ctx context.Context, req *adapters.Re
 		}
 	}
 	httpReq.Header = req.Headers
+	negotiateEncoding := bidder.config.Compression.acceptEncoding != "" && req.Headers.Get("Accept-Encoding") == ""
+	if bidder.config.PropagateTrace || negotiateEncoding {
+		// the adapter headers are reported in the debug output, so they are left untouched
+		httpReq.Header = req.Headers.Clone()
+		if httpReq.Header == nil {
+			httpReq.Header = http.Header{}
+		}
+		if bidder.config.PropagateTrace {
+			tracing.Inject(ctx, httpReq.Header)
+		}
+		if negotiateEncoding {
+			httpReq.Header.Set("Accept-Encoding", bidder.config.Compression.acceptEncoding)
+		}
+	}
+	bidder.me.RecordAdapterPayloadSize(bidder.BidderName, metrics.PayloadDirectionRequest, len(req.Body), requestBody.Len())
 
 	// If adapter connection metrics are not disabled, add the client trace
 	// to get complete connection info into our metrics
@@ -640,7 +691,7 @@ func (bidder *BidderAdapter) doRequestImpl(ctx context.Context, req *adapters.Re
 	}
 	defer httpResp.Body.Close()
 
-	respBody, err := io.ReadAll(httpResp.Body)
+	respBody, err := readResponseBody(httpResp.Body, bidder.config.Compression.maxBodyBytes())
 	if err != nil {
 		return &httpCallInfo{
 			request: req,
@@ -648,6 +699,17 @@ func (bidder *BidderAdapter) doRequestImpl(ctx context.Context, req *adapters.Re
 		}
 	}
 
+	// the wire size is unknown when the http client transparently decompressed the response itself
+	wireBytes := len(respBody)
+	respBody, err = bidder.config.Compression.decodeResponseBody(httpResp.Header, respBody)
+	if err != nil {
+		return &httpCallInfo{
+			request: req,
+			err:     &errortypes.BadServerResponse{Message: fmt.Sprintf("Failed to decode %s response: %v", httpResp.Header.Get("Content-Encoding"), err)},
+		}
+	}
+	bidder.me.RecordAdapterPayloadSize(bidder.BidderName, metrics.PayloadDirectionResponse, len(respBody), wireBytes)
+
 	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 400 {
 		if httpResp.StatusCode >= 500 {
 			bidder.logHealthCheck(false)
@@ -846,8 +908,8 @@ func hasShorterDurationThanTmax(ctx bidderTmaxContext, tmaxAdjustments TmaxAdjus
 	return false
 }
 
-func getRequestBody(req *adapters.RequestData, endpointCompression string) (*bytes.Buffer, error) {
-	switch strings.ToUpper(endpointCompression) {
+func getRequestBody(req *adapters.RequestData, compression bidderCompression) (*bytes.Buffer, error) {
+	switch compression.endpoint {
 	case Gzip:
 		// Compress to GZIP
 		b := bytes.NewBuffer(make([]byte, 0, len(req.Body)))
@@ -869,6 +931,12 @@ func getRequestBody(req *adapters.RequestData, endpointCompression string) (*byt
 		req.Headers.Set("Content-Encoding", "gzip")
 
 		return b, nil
+	case Zstd:
+		b := compression.encoder().EncodeAll(req.Body, make([]byte, 0, len(req.Body)/2))
+
+		req.Headers.Set("Content-Encoding", "zstd")
+
+		return bytes.NewBuffer(b), nil
 	default:
 		return bytes.NewBuffer(req.Body), nil
 	}
@@ -0,0 +1,151 @@
+package exchange
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/util/httputil"
+)
+
+// defaultMaxResponseBodyBytes caps the size of a bidder response body, both as read from the wire and once
+// decoded, so a small compressed payload can't expand into an unbounded allocation. Bidders may set their own
+// cap with maxResponseBodyBytes.
+const defaultMaxResponseBodyBytes = 32 << 20
+
+// defaultZstdEncoder and defaultZstdDecoder are used by bidders which compress with zstd without a shared
+// dictionary. Both are safe for concurrent use through EncodeAll and DecodeAll, running up to GOMAXPROCS
+// encodes and decodes at once.
+var (
+	defaultZstdEncoder, _ = zstd.NewWriter(nil)
+	defaultZstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderConcurrency(0), zstd.WithDecoderMaxMemory(defaultMaxResponseBodyBytes))
+)
+
+// bidderCompression holds the compression settings used when exchanging payloads with a bidder.
+type bidderCompression struct {
+	// endpoint is the upper cased request body encoding, either GZIP, ZSTD or empty for none.
+	endpoint string
+	// zstdEncoder and zstdDecoder are set when the bidder shares a zstd dictionary with Prebid Server.
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+	// acceptEncoding is the Accept-Encoding header value negotiated with the bidder. When empty, response
+	// decoding is left to the HTTP client.
+	acceptEncoding string
+	// maxResponseBodyBytes is set when the bidder caps its response bodies at other than the default size.
+	maxResponseBodyBytes int
+}
+
+func newBidderCompression(info config.BidderInfo) (bidderCompression, error) {
+	compression := bidderCompression{
+		endpoint: strings.ToUpper(info.EndpointCompression),
+	}
+
+	if len(info.ResponseCompression) > 0 {
+		encodings := make([]string, 0, len(info.ResponseCompression))
+		for _, encoding := range info.ResponseCompression {
+			encodings = append(encodings, string(httputil.ContentEncoding(encoding).Normalize()))
+		}
+		compression.acceptEncoding = strings.Join(encodings, ", ")
+	}
+
+	if info.MaxResponseBodyBytes != 0 && info.MaxResponseBodyBytes != defaultMaxResponseBodyBytes {
+		compression.maxResponseBodyBytes = info.MaxResponseBodyBytes
+	}
+
+	// the zstd decoders are bounded by the larger of the default and bidder caps, the decoded body being held
+	// to the bidder cap afterwards
+	decoderOptions := []zstd.DOption{zstd.WithDecoderConcurrency(0), zstd.WithDecoderMaxMemory(uint64(max(compression.maxBodyBytes(), defaultMaxResponseBodyBytes)))}
+	if info.EndpointCompressionDictionary != "" {
+		dictionary, err := os.ReadFile(info.EndpointCompressionDictionary)
+		if err != nil {
+			return compression, fmt.Errorf("failed to read zstd dictionary: %v", err)
+		}
+		if compression.zstdEncoder, err = zstd.NewWriter(nil, zstd.WithEncoderDict(dictionary)); err != nil {
+			return compression, fmt.Errorf("invalid zstd dictionary: %v", err)
+		}
+		if compression.zstdDecoder, err = zstd.NewReader(nil, append(decoderOptions, zstd.WithDecoderDicts(dictionary))...); err != nil {
+			return compression, fmt.Errorf("invalid zstd dictionary: %v", err)
+		}
+	} else if compression.maxBodyBytes() > defaultMaxResponseBodyBytes && compression.acceptEncoding != "" {
+		var err error
+		if compression.zstdDecoder, err = zstd.NewReader(nil, decoderOptions...); err != nil {
+			return compression, fmt.Errorf("failed to create zstd decoder: %v", err)
+		}
+	}
+
+	return compression, nil
+}
+
+func (c bidderCompression) encoder() *zstd.Encoder {
+	if c.zstdEncoder != nil {
+		return c.zstdEncoder
+	}
+	return defaultZstdEncoder
+}
+
+func (c bidderCompression) maxBodyBytes() int {
+	if c.maxResponseBodyBytes != 0 {
+		return c.maxResponseBodyBytes
+	}
+	return defaultMaxResponseBodyBytes
+}
+
+func (c bidderCompression) decoder() *zstd.Decoder {
+	if c.zstdDecoder != nil {
+		return c.zstdDecoder
+	}
+	return defaultZstdDecoder
+}
+
+// decodeResponseBody decodes a bidder response body which Prebid Server negotiated itself. Bodies with
+// an unknown encoding are returned unchanged so the adapter can still inspect them.
+func (c bidderCompression) decodeResponseBody(headers http.Header, body []byte) ([]byte, error) {
+	if c.acceptEncoding == "" {
+		return body, nil
+	}
+
+	switch httputil.ContentEncoding(headers.Get("Content-Encoding")).Normalize() {
+	case httputil.ContentEncodingGZIP:
+		r, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		decoded, err := readResponseBody(r, c.maxBodyBytes())
+		if err != nil {
+			return nil, err
+		}
+		headers.Del("Content-Encoding")
+		return decoded, nil
+	case httputil.ContentEncodingZSTD:
+		decoded, err := c.decoder().DecodeAll(body, nil)
+		if err != nil {
+			return nil, err
+		}
+		if len(decoded) > c.maxBodyBytes() {
+			return nil, fmt.Errorf("response body exceeds %d bytes", c.maxBodyBytes())
+		}
+		headers.Del("Content-Encoding")
+		return decoded, nil
+	default:
+		return body, nil
+	}
+}
+
+// readResponseBody reads a response body up to maxBytes, failing on longer bodies.
+func readResponseBody(r io.Reader, maxBytes int) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(r, int64(maxBytes)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxBytes {
+		return nil, fmt.Errorf("response body exceeds %d bytes", maxBytes)
+	}
+	return body, nil
+}
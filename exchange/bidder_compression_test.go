@@ -0,0 +1,281 @@
+package exchange
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleOpenRTBPayloads(n int) [][]byte {
+	samples := make([][]byte, 0, n)
+	for i := 0; i < n; i++ {
+		samples = append(samples, []byte(fmt.Sprintf(`{"id":"req-%d","imp":[{"id":"imp-%d","banner":{"format":[{"w":300,"h":250},{"w":728,"h":90}]},"ext":{"prebid":{"bidder":{"appnexus":{"placementId":%d}}}}}],"site":{"page":"https://www.example.com/article/%d","publisher":{"id":"pub-1"}},"device":{"ua":"Mozilla/5.0","ip":"10.0.0.%d"},"tmax":500}`, i, i, 1000+i, i, i%255)))
+	}
+	return samples
+}
+
+// buildTestDictionary builds a zstd dictionary from sample payloads, half of them seeding its history
+func buildTestDictionary(t *testing.T, id uint32, samples [][]byte) []byte {
+	t.Helper()
+	var history []byte
+	var contents [][]byte
+	for i, sample := range samples {
+		if i%2 == 0 {
+			contents = append(contents, sample)
+		} else {
+			history = append(history, sample...)
+		}
+	}
+	dictionary, err := zstd.BuildDict(zstd.BuildDictOptions{ID: id, Contents: contents, History: history, Offsets: [3]int{1, 4, 8}})
+	require.NoError(t, err)
+	return dictionary
+}
+
+func TestNewBidderCompression(t *testing.T) {
+	dictionary := buildTestDictionary(t, 7, sampleOpenRTBPayloads(50))
+	dictionaryPath := filepath.Join(t.TempDir(), "openrtb.dict")
+	require.NoError(t, os.WriteFile(dictionaryPath, dictionary, 0644))
+
+	t.Run("defaults", func(t *testing.T) {
+		compression, err := newBidderCompression(config.BidderInfo{EndpointCompression: "gzip"})
+		require.NoError(t, err)
+		assert.Equal(t, Gzip, compression.endpoint)
+		assert.Empty(t, compression.acceptEncoding)
+		assert.Same(t, defaultZstdEncoder, compression.encoder())
+		assert.Same(t, defaultZstdDecoder, compression.decoder())
+	})
+
+	t.Run("dictionary-and-response-compression", func(t *testing.T) {
+		compression, err := newBidderCompression(config.BidderInfo{
+			EndpointCompression:           "zstd",
+			EndpointCompressionDictionary: dictionaryPath,
+			ResponseCompression:           []string{"ZSTD", "gzip"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, Zstd, compression.endpoint)
+		assert.Equal(t, "zstd, gzip", compression.acceptEncoding)
+		assert.NotSame(t, defaultZstdEncoder, compression.encoder())
+
+		req := &adapters.RequestData{Body: []byte(`{"id":"some-request"}`), Headers: http.Header{}}
+		body, err := getRequestBody(req, compression)
+		require.NoError(t, err)
+
+		decoded, err := compression.decoder().DecodeAll(body.Bytes(), nil)
+		require.NoError(t, err)
+		assert.Equal(t, req.Body, decoded)
+	})
+
+	t.Run("max-response-body-bytes", func(t *testing.T) {
+		compression, err := newBidderCompression(config.BidderInfo{ResponseCompression: []string{"zstd"}, MaxResponseBodyBytes: 1 << 10})
+		require.NoError(t, err)
+		assert.Equal(t, 1<<10, compression.maxBodyBytes())
+		assert.Same(t, defaultZstdDecoder, compression.decoder(), "the default decoder decodes bodies within a lower cap")
+	})
+
+	t.Run("max-response-body-bytes-above-default", func(t *testing.T) {
+		compression, err := newBidderCompression(config.BidderInfo{ResponseCompression: []string{"zstd"}, MaxResponseBodyBytes: defaultMaxResponseBodyBytes * 2})
+		require.NoError(t, err)
+		assert.Equal(t, defaultMaxResponseBodyBytes*2, compression.maxBodyBytes())
+		assert.NotSame(t, defaultZstdDecoder, compression.decoder(), "the default decoder is bounded by the default cap")
+
+		decoded, err := compression.decoder().DecodeAll(defaultZstdEncoder.EncodeAll(make([]byte, defaultMaxResponseBodyBytes+1), nil), nil)
+		require.NoError(t, err)
+		assert.Len(t, decoded, defaultMaxResponseBodyBytes+1)
+	})
+
+	t.Run("missing-dictionary", func(t *testing.T) {
+		_, err := newBidderCompression(config.BidderInfo{EndpointCompression: "zstd", EndpointCompressionDictionary: filepath.Join(t.TempDir(), "missing")})
+		assert.ErrorContains(t, err, "failed to read zstd dictionary")
+	})
+}
+
+func TestDecodeResponseBody(t *testing.T) {
+	body := []byte(`{"id":"some-response"}`)
+
+	var gzipBody bytes.Buffer
+	w := gzip.NewWriter(&gzipBody)
+	w.Write(body)
+	w.Close()
+
+	zstdBody := defaultZstdEncoder.EncodeAll(body, nil)
+
+	oversized := make([]byte, defaultMaxResponseBodyBytes+1)
+	var oversizedGzipBody bytes.Buffer
+	w = gzip.NewWriter(&oversizedGzipBody)
+	w.Write(oversized)
+	w.Close()
+	oversizedZstdBody := defaultZstdEncoder.EncodeAll(oversized, nil)
+
+	testCases := []struct {
+		name            string
+		acceptEncoding  string
+		maxBodyBytes    int
+		contentEncoding string
+		givenBody       []byte
+		expectedBody    []byte
+		expectedHeader  string
+		expectErr       bool
+	}{
+		{
+			name:            "not-negotiated",
+			contentEncoding: "gzip",
+			givenBody:       gzipBody.Bytes(),
+			expectedBody:    gzipBody.Bytes(),
+			expectedHeader:  "gzip",
+		},
+		{
+			name:            "gzip",
+			acceptEncoding:  "gzip",
+			contentEncoding: "gzip",
+			givenBody:       gzipBody.Bytes(),
+			expectedBody:    body,
+		},
+		{
+			name:            "zstd",
+			acceptEncoding:  "zstd",
+			contentEncoding: "ZSTD",
+			givenBody:       zstdBody,
+			expectedBody:    body,
+		},
+		{
+			name:           "identity",
+			acceptEncoding: "zstd, gzip",
+			givenBody:      body,
+			expectedBody:   body,
+		},
+		{
+			name:            "gzip-too-large",
+			acceptEncoding:  "gzip",
+			contentEncoding: "gzip",
+			givenBody:       oversizedGzipBody.Bytes(),
+			expectErr:       true,
+		},
+		{
+			name:            "gzip-over-bidder-cap",
+			acceptEncoding:  "gzip",
+			maxBodyBytes:    len(body) - 1,
+			contentEncoding: "gzip",
+			givenBody:       gzipBody.Bytes(),
+			expectErr:       true,
+		},
+		{
+			name:            "zstd-over-bidder-cap",
+			acceptEncoding:  "zstd",
+			maxBodyBytes:    len(body) - 1,
+			contentEncoding: "zstd",
+			givenBody:       zstdBody,
+			expectErr:       true,
+		},
+		{
+			name:            "zstd-too-large",
+			acceptEncoding:  "zstd",
+			contentEncoding: "zstd",
+			givenBody:       oversizedZstdBody,
+			expectErr:       true,
+		},
+		{
+			name:            "corrupt",
+			acceptEncoding:  "zstd",
+			contentEncoding: "zstd",
+			givenBody:       body,
+			expectErr:       true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			headers := http.Header{}
+			if tc.contentEncoding != "" {
+				headers.Set("Content-Encoding", tc.contentEncoding)
+			}
+
+			decoded, err := bidderCompression{acceptEncoding: tc.acceptEncoding, maxResponseBodyBytes: tc.maxBodyBytes}.decodeResponseBody(headers, tc.givenBody)
+
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedBody, decoded)
+			assert.Equal(t, tc.expectedHeader, headers.Get("Content-Encoding"))
+		})
+	}
+}
+
+func TestDoRequestImplCompression(t *testing.T) {
+	requestBody := []byte(`{"id":"some-request","imp":[{"id":"imp-1"}]}`)
+	responseBody := []byte(`{"id":"some-response","seatbid":[]}`)
+	compressedResponse := defaultZstdEncoder.EncodeAll(responseBody, nil)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		decoded, err := defaultZstdDecoder.DecodeAll(body, nil)
+		if r.Header.Get("Content-Encoding") != "zstd" || err != nil || !bytes.Equal(decoded, requestBody) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.Header.Get("Accept-Encoding") != "zstd" {
+			w.WriteHeader(http.StatusNotAcceptable)
+			return
+		}
+		w.Header().Set("Content-Encoding", "zstd")
+		w.Write(compressedResponse)
+	}))
+	defer server.Close()
+
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordOverheadTime", metrics.PreBidder, mock.Anything).Return()
+	me.On("RecordBidderServerResponseTime", mock.Anything).Return()
+	me.On("RecordAdapterPayloadSize", openrtb_ext.BidderAppnexus, metrics.PayloadDirectionRequest, len(requestBody), mock.Anything).Return()
+	me.On("RecordAdapterPayloadSize", openrtb_ext.BidderAppnexus, metrics.PayloadDirectionResponse, len(responseBody), len(compressedResponse)).Return()
+
+	bidder := adaptBidder(nil, server.Client(), &config.Configuration{}, me, openrtb_ext.BidderAppnexus, nil, bidderCompression{endpoint: Zstd, acceptEncoding: "zstd"}).(*BidderAdapter)
+	bidder.config.DisableConnMetrics = true
+
+	req := &adapters.RequestData{Method: http.MethodPost, Uri: server.URL, Body: requestBody, Headers: http.Header{}}
+	httpInfo := bidder.doRequestImpl(context.Background(), req, func(string, ...interface{}) {}, time.Now(), nil)
+
+	require.NoError(t, httpInfo.err)
+	assert.Equal(t, http.StatusOK, httpInfo.response.StatusCode)
+	assert.Equal(t, responseBody, httpInfo.response.Body)
+	assert.Empty(t, httpInfo.response.Headers.Get("Content-Encoding"))
+	assert.Empty(t, req.Headers.Get("Accept-Encoding"), "the adapter headers are left untouched")
+	me.AssertExpectations(t)
+}
+
+func TestDoRequestImplMaxResponseBodyBytes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"some-response","seatbid":[]}`))
+	}))
+	defer server.Close()
+
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordOverheadTime", metrics.PreBidder, mock.Anything).Return()
+	me.On("RecordBidderServerResponseTime", mock.Anything).Return()
+	me.On("RecordAdapterPayloadSize", openrtb_ext.BidderAppnexus, metrics.PayloadDirectionRequest, mock.Anything, mock.Anything).Return()
+
+	bidder := adaptBidder(nil, server.Client(), &config.Configuration{}, me, openrtb_ext.BidderAppnexus, nil, bidderCompression{maxResponseBodyBytes: 16}).(*BidderAdapter)
+	bidder.config.DisableConnMetrics = true
+
+	req := &adapters.RequestData{Method: http.MethodPost, Uri: server.URL, Body: []byte(`{"id":"some-request"}`), Headers: http.Header{}}
+	httpInfo := bidder.doRequestImpl(context.Background(), req, func(string, ...interface{}) {}, time.Now(), nil)
+
+	assert.EqualError(t, httpInfo.err, "response body exceeds 16 bytes")
+}
@@ -0,0 +1,173 @@
+package exchange
+
+import (
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+)
+
+// buildBidderHttpClient returns the client a bidder should use to call its endpoint. Bidders without a
+// transport configuration share the host client. Bidders with one get a dedicated connection pool cloned
+// from the host transport, so the proxy, root certificates and unspecified limits are inherited.
+func buildBidderHttpClient(client *http.Client, transport *config.BidderTransport, bidderName openrtb_ext.BidderName, me metrics.MetricsEngine) *http.Client {
+	if transport == nil || client == nil {
+		return client
+	}
+
+	var base *http.Transport
+	if t, ok := client.Transport.(*http.Transport); ok {
+		base = t.Clone()
+	} else {
+		base = http.DefaultTransport.(*http.Transport).Clone()
+	}
+	applyBidderTransport(base, transport)
+
+	return &http.Client{
+		Transport: &poolTrackingTransport{
+			next:            base,
+			bidderName:      bidderName,
+			maxConnsPerHost: int64(transport.MaxConnsPerHost),
+			me:              me,
+		},
+		CheckRedirect: client.CheckRedirect,
+		Jar:           client.Jar,
+		Timeout:       client.Timeout,
+	}
+}
+
+// CloseIdleBidderConnections closes the idle connections of the dedicated bidder transports, so the connection
+// pools of the bidders replaced by a configuration reload are let go. The host client shared by the other
+// bidders is left alone.
+func CloseIdleBidderConnections(bidders map[openrtb_ext.BidderName]AdaptedBidder) {
+	for _, bidder := range bidders {
+		if validated, ok := bidder.(*validatedBidder); ok {
+			bidder = validated.bidder
+		}
+		adapter, ok := bidder.(*BidderAdapter)
+		if !ok || adapter.Client == nil {
+			continue
+		}
+		if _, dedicated := adapter.Client.Transport.(*poolTrackingTransport); dedicated {
+			adapter.Client.CloseIdleConnections()
+		}
+	}
+}
+
+func applyBidderTransport(t *http.Transport, cfg *config.BidderTransport) {
+	if cfg.MaxConnsPerHost > 0 {
+		t.MaxConnsPerHost = cfg.MaxConnsPerHost
+	}
+	if cfg.MaxIdleConns > 0 {
+		t.MaxIdleConns = cfg.MaxIdleConns
+	}
+	if cfg.MaxIdleConnsPerHost > 0 {
+		t.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	}
+	if cfg.IdleConnTimeoutSeconds > 0 {
+		t.IdleConnTimeout = time.Duration(cfg.IdleConnTimeoutSeconds) * time.Second
+	}
+	t.DisableKeepAlives = cfg.DisableKeepAlives
+	t.DisableCompression = cfg.DisableCompression
+
+	if cfg.DialTimeoutSeconds > 0 || cfg.KeepAliveSeconds > 0 {
+		dialer := &net.Dialer{
+			Timeout:   time.Duration(cfg.DialTimeoutSeconds) * time.Second,
+			KeepAlive: time.Duration(cfg.KeepAliveSeconds) * time.Second,
+		}
+		t.DialContext = dialer.DialContext
+	}
+
+	if cfg.HTTP2 != nil {
+		if *cfg.HTTP2 {
+			t.ForceAttemptHTTP2 = true
+		} else {
+			// a non-nil empty map disables the automatic HTTP/2 upgrade
+			t.ForceAttemptHTTP2 = false
+			t.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+		}
+	}
+
+	if cfg.TLS != nil {
+		tlsConfig := &tls.Config{}
+		if t.TLSClientConfig != nil {
+			tlsConfig = t.TLSClientConfig.Clone()
+		}
+		if version, ok := config.TLSVersions[cfg.TLS.MinVersion]; ok {
+			tlsConfig.MinVersion = version
+		}
+		if cfg.TLS.ServerName != "" {
+			tlsConfig.ServerName = cfg.TLS.ServerName
+		}
+		if cfg.TLS.SessionCacheSize > 0 {
+			tlsConfig.ClientSessionCache = tls.NewLRUClientSessionCache(cfg.TLS.SessionCacheSize)
+		}
+		t.TLSClientConfig = tlsConfig
+
+		if cfg.TLS.HandshakeTimeoutMS > 0 {
+			t.TLSHandshakeTimeout = time.Duration(cfg.TLS.HandshakeTimeoutMS) * time.Millisecond
+		}
+	}
+}
+
+// poolTrackingTransport counts the requests in flight to each host on a dedicated bidder transport and records
+// a metric whenever a request has to wait because the bidder's connection limit for the host has been reached.
+type poolTrackingTransport struct {
+	next            http.RoundTripper
+	bidderName      openrtb_ext.BidderName
+	maxConnsPerHost int64
+	// inFlight holds an *atomic.Int64 per host. A bidder only calls a handful of hosts, so it is never pruned.
+	inFlight sync.Map
+	me       metrics.MetricsEngine
+}
+
+func (t *poolTrackingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	hostInFlight := t.hostInFlight(req.URL.Host)
+	if inFlight := hostInFlight.Add(1); t.maxConnsPerHost > 0 && inFlight > t.maxConnsPerHost {
+		t.me.RecordAdapterConnectionPoolExhausted(t.bidderName)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp == nil || resp.Body == nil {
+		hostInFlight.Add(-1)
+		return resp, err
+	}
+
+	// the connection is held until the caller is finished with the body
+	resp.Body = &releaseOnCloseBody{ReadCloser: resp.Body, release: func() { hostInFlight.Add(-1) }}
+	return resp, nil
+}
+
+func (t *poolTrackingTransport) hostInFlight(host string) *atomic.Int64 {
+	if inFlight, ok := t.inFlight.Load(host); ok {
+		return inFlight.(*atomic.Int64)
+	}
+	inFlight, _ := t.inFlight.LoadOrStore(host, &atomic.Int64{})
+	return inFlight.(*atomic.Int64)
+}
+
+// CloseIdleConnections lets http.Client.CloseIdleConnections reach the wrapped transport.
+func (t *poolTrackingTransport) CloseIdleConnections() {
+	if closer, ok := t.next.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
+type releaseOnCloseBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releaseOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
@@ -0,0 +1,170 @@
+package exchange
+
+import (
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildBidderHttpClient(t *testing.T) {
+	hostClient := &http.Client{
+		Transport: &http.Transport{
+			MaxConnsPerHost:     100,
+			MaxIdleConnsPerHost: 10,
+			TLSClientConfig:     &tls.Config{ServerName: "host"},
+		},
+	}
+
+	t.Run("no-transport-config-shares-client", func(t *testing.T) {
+		client := buildBidderHttpClient(hostClient, nil, openrtb_ext.BidderAppnexus, &metrics.MetricsEngineMock{})
+		assert.Same(t, hostClient, client)
+	})
+
+	t.Run("transport-config-builds-dedicated-pool", func(t *testing.T) {
+		transport := &config.BidderTransport{
+			MaxConnsPerHost:        5,
+			IdleConnTimeoutSeconds: 30,
+			DisableKeepAlives:      true,
+			HTTP2:                  ptrutil.ToPtr(false),
+			TLS:                    &config.BidderTransportTLS{MinVersion: "1.3", HandshakeTimeoutMS: 500},
+		}
+		client := buildBidderHttpClient(hostClient, transport, openrtb_ext.BidderAppnexus, &metrics.MetricsEngineMock{})
+		require.NotSame(t, hostClient, client)
+
+		tracking, ok := client.Transport.(*poolTrackingTransport)
+		require.True(t, ok)
+		assert.Equal(t, int64(5), tracking.maxConnsPerHost)
+
+		httpTransport := tracking.next.(*http.Transport)
+		assert.NotSame(t, hostClient.Transport, httpTransport)
+		assert.Equal(t, 5, httpTransport.MaxConnsPerHost)
+		assert.Equal(t, 10, httpTransport.MaxIdleConnsPerHost, "unset values are inherited from the host transport")
+		assert.Equal(t, 30*time.Second, httpTransport.IdleConnTimeout)
+		assert.True(t, httpTransport.DisableKeepAlives)
+		assert.False(t, httpTransport.ForceAttemptHTTP2)
+		assert.NotNil(t, httpTransport.TLSNextProto)
+		assert.Equal(t, uint16(tls.VersionTLS13), httpTransport.TLSClientConfig.MinVersion)
+		assert.Equal(t, "host", httpTransport.TLSClientConfig.ServerName)
+		assert.Equal(t, 500*time.Millisecond, httpTransport.TLSHandshakeTimeout)
+		assert.Equal(t, uint16(0), hostClient.Transport.(*http.Transport).TLSClientConfig.MinVersion, "host transport must not be modified")
+	})
+}
+
+func TestPoolTrackingTransport(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordAdapterConnectionPoolExhausted", openrtb_ext.BidderAppnexus).Return()
+
+	client := buildBidderHttpClient(server.Client(), &config.BidderTransport{MaxConnsPerHost: 1}, openrtb_ext.BidderAppnexus, me)
+	tracking := client.Transport.(*poolTrackingTransport)
+
+	done := make(chan struct{}, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			resp, err := client.Get(server.URL)
+			if err == nil {
+				io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+			}
+			done <- struct{}{}
+		}()
+	}
+
+	host := server.Listener.Addr().String()
+	assert.Eventually(t, func() bool { return tracking.hostInFlight(host).Load() == 2 }, time.Second, time.Millisecond)
+	close(release)
+	<-done
+	<-done
+
+	assert.Equal(t, int64(0), tracking.hostInFlight(host).Load())
+	me.AssertNumberOfCalls(t, "RecordAdapterConnectionPoolExhausted", 1)
+}
+
+func TestPoolTrackingTransportPerHost(t *testing.T) {
+	release := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte("ok"))
+	})
+	first := httptest.NewServer(handler)
+	defer first.Close()
+	second := httptest.NewServer(handler)
+	defer second.Close()
+
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordAdapterConnectionPoolExhausted", openrtb_ext.BidderAppnexus).Return()
+
+	client := buildBidderHttpClient(first.Client(), &config.BidderTransport{MaxConnsPerHost: 1}, openrtb_ext.BidderAppnexus, me)
+	tracking := client.Transport.(*poolTrackingTransport)
+
+	done := make(chan struct{}, 2)
+	for _, url := range []string{first.URL, second.URL} {
+		go func() {
+			resp, err := client.Get(url)
+			if err == nil {
+				io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+			}
+			done <- struct{}{}
+		}()
+	}
+
+	assert.Eventually(t, func() bool {
+		return tracking.hostInFlight(first.Listener.Addr().String()).Load() == 1 &&
+			tracking.hostInFlight(second.Listener.Addr().String()).Load() == 1
+	}, time.Second, time.Millisecond)
+	close(release)
+	<-done
+	<-done
+
+	me.AssertNotCalled(t, "RecordAdapterConnectionPoolExhausted", openrtb_ext.BidderAppnexus)
+}
+
+func TestCloseIdleBidderConnections(t *testing.T) {
+	closed := make(chan struct{}, 1)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateClosed {
+			closed <- struct{}{}
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	me := &metrics.MetricsEngineMock{}
+	client := buildBidderHttpClient(server.Client(), &config.BidderTransport{MaxIdleConnsPerHost: 1}, openrtb_ext.BidderAppnexus, me)
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	CloseIdleBidderConnections(map[openrtb_ext.BidderName]AdaptedBidder{
+		openrtb_ext.BidderAppnexus: &validatedBidder{bidder: &BidderAdapter{Client: client}},
+		openrtb_ext.BidderRubicon:  &validatedBidder{bidder: &BidderAdapter{Client: server.Client()}},
+	})
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		assert.Fail(t, "the idle connection of the dedicated transport was not closed")
+	}
+}
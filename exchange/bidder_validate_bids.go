@@ -4,14 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/currency"
+	"github.com/prebid/prebid-server/v3/errortypes"
 	"github.com/prebid/prebid-server/v3/exchange/entities"
 	"github.com/prebid/prebid-server/v3/experiment/adscert"
 	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v3/metrics"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	goCurrency "golang.org/x/text/currency"
 )
@@ -21,14 +25,16 @@ import (
 //
 // The goal here is to make sure that the response contains Bids which are valid given the initial Request,
 // so that Publishers can trust the Bids they get from Prebid Server.
-func addValidatedBidderMiddleware(bidder AdaptedBidder) AdaptedBidder {
+func addValidatedBidderMiddleware(bidder AdaptedBidder, me metrics.MetricsEngine) AdaptedBidder {
 	return &validatedBidder{
 		bidder: bidder,
+		me:     me,
 	}
 }
 
 type validatedBidder struct {
 	bidder AdaptedBidder
+	me     metrics.MetricsEngine
 }
 
 func (v *validatedBidder) requestBid(ctx context.Context, bidderRequest BidderRequest, conversions currency.Conversions, reqInfo *adapters.ExtraRequestInfo, adsCertSigner adscert.Signer, bidRequestOptions bidRequestOptions, alternateBidderCodes openrtb_ext.ExtAlternateBidderCodes, hookExecutor hookexecution.StageExecutor, ruleToAdjustments openrtb_ext.AdjustmentsByDealID) ([]*entities.PbsOrtbSeatBid, extraBidderRespInfo, []error) {
@@ -38,6 +44,9 @@ func (v *validatedBidder) requestBid(ctx context.Context, bidderRequest BidderRe
 			errs = append(errs, validationErrors...)
 		}
 	}
+	if secureErrs := v.validateSecureCreatives(bidderRequest, seatBids, bidRequestOptions, &extraBidderRespInfo.seatNonBidBuilder); len(secureErrs) > 0 {
+		errs = append(errs, secureErrs...)
+	}
 	return seatBids, extraBidderRespInfo, errs
 }
 
@@ -141,3 +150,116 @@ func validateBid(bid *entities.PbsOrtbBid, debug bool) (bool, error) {
 
 	return true, nil
 }
+
+// insecureURLPattern matches the scheme of the HTTP URLs, including the JSON escaped and URL encoded ones
+var insecureURLPattern = regexp.MustCompile(`(?i)\bhttp(:|%3A)(//|\\/\\/|%2F%2F)`)
+
+// insecureResourcePatterns match the markup around the HTTP URLs a creative loads when rendered, as opposed to the
+// links it navigates to on click and the XML namespaces and DTDs it declares: the resources of the HTML tags and
+// CSS, the VAST elements a player loads, and the image assets and trackers of a native response. The markup may be
+// JSON escaped, as the HTML and VAST of the native assets are.
+var insecureResourcePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\b(?:src|srcset|poster|background|data)\s*=\s*\\?["']?\s*http(?::|%3A)(?://|\\/\\/|%2F%2F)`),
+	regexp.MustCompile(`(?i)<link\b[^>]*\bhref\s*=\s*\\?["']?\s*http(?::|%3A)(?://|\\/\\/|%2F%2F)`),
+	regexp.MustCompile(`(?i)url\(\s*\\?["']?\s*http(?::|%3A)(?://|\\/\\/|%2F%2F)`),
+	regexp.MustCompile(`(?is)<(?:MediaFile|Impression|Tracking|StaticResource|IFrameResource|JavaScriptResource|VASTAdTagURI)\b[^>]*>\s*(?:<!\[CDATA\[)?\s*http(?::|%3A)(?://|\\/\\/|%2F%2F)`),
+	regexp.MustCompile(`(?i)"img"\s*:\s*\{[^}]*`),
+	regexp.MustCompile(`(?i)"(?:imptrackers|eventtrackers)"\s*:\s*\[[^\]]*`),
+}
+
+// validateSecureCreatives checks the markup and notification URLs of the bids for secure imps for HTTP URLs.
+// Depending on the secure_creatives validation of the account, such bids are rejected as seat non-bids, only
+// reported as warnings, or have their URLs rewritten to HTTPS.
+func (v *validatedBidder) validateSecureCreatives(bidderRequest BidderRequest, seatBids []*entities.PbsOrtbSeatBid, options bidRequestOptions, seatNonBidBuilder *SeatNonBidBuilder) []error {
+	enforcement := options.secureCreatives
+	if enforcement != config.ValidationEnforce && enforcement != config.ValidationWarn && enforcement != config.ValidationRewrite {
+		return nil
+	}
+	secureImps := secureImpIDs(bidderRequest.BidRequest)
+	if len(secureImps) == 0 {
+		return nil
+	}
+
+	var errs []error
+	for _, seatBid := range seatBids {
+		if seatBid == nil {
+			continue
+		}
+		bids := seatBid.Bids[:0]
+		for _, bid := range seatBid.Bids {
+			if _, secure := secureImps[bid.Bid.ImpID]; !secure || !hasInsecureURL(bid.Bid) {
+				bids = append(bids, bid)
+				continue
+			}
+
+			switch enforcement {
+			case config.ValidationRewrite:
+				v.me.RecordBidValidationSecureCreativeRewrite(bidderRequest.BidderCoreName, options.pubID)
+				rewriteInsecureURLs(bid.Bid)
+				errs = append(errs, &errortypes.Warning{
+					Message:     fmt.Sprintf("%s bid id %s - insecure URLs rewritten to HTTPS for secure imp %s", seatBid.Seat, bid.Bid.ID, bid.Bid.ImpID),
+					WarningCode: errortypes.InsecureCreativeWarningCode,
+				})
+				bids = append(bids, bid)
+			case config.ValidationWarn:
+				v.me.RecordBidValidationSecureCreativeWarn(bidderRequest.BidderCoreName, options.pubID)
+				errs = append(errs, &errortypes.Warning{
+					Message:     fmt.Sprintf("%s bid id %s - insecure URLs for secure imp %s", seatBid.Seat, bid.Bid.ID, bid.Bid.ImpID),
+					WarningCode: errortypes.InsecureCreativeWarningCode,
+				})
+				bids = append(bids, bid)
+			default:
+				v.me.RecordBidValidationSecureCreativeError(bidderRequest.BidderCoreName, options.pubID)
+				errs = append(errs, &errortypes.Warning{
+					Message:     fmt.Sprintf("%s bid id %s rejected - insecure URLs for secure imp %s", seatBid.Seat, bid.Bid.ID, bid.Bid.ImpID),
+					WarningCode: errortypes.InsecureCreativeWarningCode,
+				})
+				if *seatNonBidBuilder == nil {
+					*seatNonBidBuilder = SeatNonBidBuilder{}
+				}
+				seatNonBidBuilder.rejectBid(bid, int(ResponseRejectedCreativeInsecureURL), seatBid.Seat)
+			}
+		}
+		seatBid.Bids = bids
+	}
+	return errs
+}
+
+// secureImpIDs returns the IDs of the imps requiring secure creatives
+func secureImpIDs(request *openrtb2.BidRequest) map[string]struct{} {
+	if request == nil {
+		return nil
+	}
+	secureImps := make(map[string]struct{})
+	for _, imp := range request.Imp {
+		if imp.Secure != nil && *imp.Secure == 1 {
+			secureImps[imp.ID] = struct{}{}
+		}
+	}
+	return secureImps
+}
+
+// hasInsecureURL returns true if the bid loads an HTTP URL, either as a resource of its markup or as one of its
+// notification URLs
+func hasInsecureURL(bid *openrtb2.Bid) bool {
+	for _, re := range insecureResourcePatterns {
+		for _, match := range re.FindAllString(bid.AdM, -1) {
+			if insecureURLPattern.MatchString(match) {
+				return true
+			}
+		}
+	}
+	return insecureURLPattern.MatchString(bid.NURL) || insecureURLPattern.MatchString(bid.BURL)
+}
+
+// rewriteInsecureURLs upgrades the HTTP URLs of the resources of the markup and the notification URLs of the bid
+// to HTTPS
+func rewriteInsecureURLs(bid *openrtb2.Bid) {
+	for _, re := range insecureResourcePatterns {
+		bid.AdM = re.ReplaceAllStringFunc(bid.AdM, func(match string) string {
+			return insecureURLPattern.ReplaceAllString(match, "https${1}${2}")
+		})
+	}
+	bid.NURL = insecureURLPattern.ReplaceAllString(bid.NURL, "https${1}${2}")
+	bid.BURL = insecureURLPattern.ReplaceAllString(bid.BURL, "https${1}${2}")
+}
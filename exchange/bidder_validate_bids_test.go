@@ -2,15 +2,20 @@ package exchange
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/currency"
 	"github.com/prebid/prebid-server/v3/exchange/entities"
 	"github.com/prebid/prebid-server/v3/experiment/adscert"
 	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v3/metrics"
+	metricsConfig "github.com/prebid/prebid-server/v3/metrics/config"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -53,7 +58,7 @@ func TestAllValidBids(t *testing.T) {
 				},
 			},
 		},
-		}})
+		}}, &metricsConfig.NilMetricsEngine{})
 	bidderReq := BidderRequest{
 		BidRequest: &openrtb2.BidRequest{},
 		BidderName: openrtb_ext.BidderAppnexus,
@@ -123,7 +128,7 @@ func TestAllBadBids(t *testing.T) {
 				{},
 			},
 		},
-		}})
+		}}, &metricsConfig.NilMetricsEngine{})
 	bidderReq := BidderRequest{
 		BidRequest: &openrtb2.BidRequest{},
 		BidderName: openrtb_ext.BidderAppnexus,
@@ -205,7 +210,7 @@ func TestMixedBids(t *testing.T) {
 				{},
 			},
 		},
-		}})
+		}}, &metricsConfig.NilMetricsEngine{})
 	bidderReq := BidderRequest{
 		BidRequest: &openrtb2.BidRequest{},
 		BidderName: openrtb_ext.BidderAppnexus,
@@ -324,7 +329,7 @@ func TestCurrencyBids(t *testing.T) {
 				Currency: tc.brpCur,
 				Bids:     bids,
 			},
-			}})
+			}}, &metricsConfig.NilMetricsEngine{})
 
 		expectedValidBids := len(bids)
 		expectedErrs := 0
@@ -354,6 +359,183 @@ func TestCurrencyBids(t *testing.T) {
 	}
 }
 
+func TestValidateSecureCreatives(t *testing.T) {
+	request := &openrtb2.BidRequest{Imp: []openrtb2.Imp{
+		{ID: "secureImp", Secure: ptrutil.ToPtr[int8](1)},
+		{ID: "insecureImp", Secure: ptrutil.ToPtr[int8](0)},
+	}}
+	newBids := func() []*entities.PbsOrtbBid {
+		return []*entities.PbsOrtbBid{
+			{Bid: &openrtb2.Bid{ID: "secureBid", ImpID: "secureImp", Price: 1, CrID: "1", AdM: `<img src="https://cdn.com/ad.png">`, NURL: "https://win.com"}},
+			{Bid: &openrtb2.Bid{ID: "insecureAdm", ImpID: "secureImp", Price: 1, CrID: "2", AdM: `<img src="http://cdn.com/ad.png">`}},
+			{Bid: &openrtb2.Bid{ID: "encodedNurl", ImpID: "secureImp", Price: 1, CrID: "3", NURL: "https://win.com?redirect=HTTP%3A%2F%2Fbidder.com"}},
+			{Bid: &openrtb2.Bid{ID: "escapedBurl", ImpID: "secureImp", Price: 1, CrID: "4", BURL: `http:\/\/bill.com`}},
+			{Bid: &openrtb2.Bid{ID: "insecureImpBid", ImpID: "insecureImp", Price: 1, CrID: "5", AdM: `<img src="http://cdn.com/ad.png">`}},
+			{Bid: &openrtb2.Bid{ID: "secureVast", ImpID: "secureImp", Price: 1, CrID: "6", AdM: fmt.Sprintf(testVAST, "https")}},
+			{Bid: &openrtb2.Bid{ID: "insecureVast", ImpID: "secureImp", Price: 1, CrID: "7", AdM: fmt.Sprintf(testVAST, "http")}},
+		}
+	}
+
+	testCases := []struct {
+		description           string
+		secureCreatives       string
+		expectedBidIDs        []string
+		expectedNonBids       int
+		expectedErrs          int
+		expectedErrorMetric   int
+		expectedWarnMetric    int
+		expectedRewriteMetric int
+		expectedRewrite       bool
+	}{
+		{
+			description:     "skip",
+			secureCreatives: config.ValidationSkip,
+			expectedBidIDs:  []string{"secureBid", "insecureAdm", "encodedNurl", "escapedBurl", "insecureImpBid", "secureVast", "insecureVast"},
+		},
+		{
+			description:        "warn",
+			secureCreatives:    config.ValidationWarn,
+			expectedBidIDs:     []string{"secureBid", "insecureAdm", "encodedNurl", "escapedBurl", "insecureImpBid", "secureVast", "insecureVast"},
+			expectedErrs:       4,
+			expectedWarnMetric: 4,
+		},
+		{
+			description:         "enforce",
+			secureCreatives:     config.ValidationEnforce,
+			expectedBidIDs:      []string{"secureBid", "insecureImpBid", "secureVast"},
+			expectedNonBids:     4,
+			expectedErrs:        4,
+			expectedErrorMetric: 4,
+		},
+		{
+			description:           "rewrite",
+			secureCreatives:       config.ValidationRewrite,
+			expectedBidIDs:        []string{"secureBid", "insecureAdm", "encodedNurl", "escapedBurl", "insecureImpBid", "secureVast", "insecureVast"},
+			expectedErrs:          4,
+			expectedRewriteMetric: 4,
+			expectedRewrite:       true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			me := &metrics.MetricsEngineMock{}
+			me.On("RecordBidValidationSecureCreativeError", openrtb_ext.BidderAppnexus, "pub").Return()
+			me.On("RecordBidValidationSecureCreativeWarn", openrtb_ext.BidderAppnexus, "pub").Return()
+			me.On("RecordBidValidationSecureCreativeRewrite", openrtb_ext.BidderAppnexus, "pub").Return()
+
+			bids := newBids()
+			bidder := addValidatedBidderMiddleware(&mockAdaptedBidder{
+				bidResponse: []*entities.PbsOrtbSeatBid{{Seat: "appnexus", Bids: bids}},
+			}, me)
+			bidderRequest := BidderRequest{BidRequest: request, BidderName: openrtb_ext.BidderAppnexus, BidderCoreName: openrtb_ext.BidderAppnexus}
+			bidReqOptions := bidRequestOptions{secureCreatives: test.secureCreatives, pubID: "pub"}
+
+			seatBids, extraRespInfo, errs := bidder.requestBid(context.Background(), bidderRequest, currency.NewConstantRates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{}, nil)
+
+			var bidIDs []string
+			for _, bid := range seatBids[0].Bids {
+				bidIDs = append(bidIDs, bid.Bid.ID)
+			}
+			assert.Equal(t, test.expectedBidIDs, bidIDs)
+			assert.Len(t, errs, test.expectedErrs)
+
+			nonBids := extraRespInfo.seatNonBidBuilder["appnexus"]
+			assert.Len(t, nonBids, test.expectedNonBids)
+			for _, nonBid := range nonBids {
+				assert.Equal(t, int(ResponseRejectedCreativeInsecureURL), nonBid.StatusCode)
+			}
+
+			me.AssertNumberOfCalls(t, "RecordBidValidationSecureCreativeError", test.expectedErrorMetric)
+			me.AssertNumberOfCalls(t, "RecordBidValidationSecureCreativeWarn", test.expectedWarnMetric)
+			me.AssertNumberOfCalls(t, "RecordBidValidationSecureCreativeRewrite", test.expectedRewriteMetric)
+
+			if test.expectedRewrite {
+				assert.Equal(t, `<img src="https://cdn.com/ad.png">`, bids[1].Bid.AdM)
+				assert.Equal(t, "https://win.com?redirect=https%3A%2F%2Fbidder.com", bids[2].Bid.NURL)
+				assert.Equal(t, `https:\/\/bill.com`, bids[3].Bid.BURL)
+				assert.Equal(t, `<img src="http://cdn.com/ad.png">`, bids[4].Bid.AdM, "bids for imps which are not secure are left untouched")
+				assert.Equal(t, fmt.Sprintf(testVAST, "https"), bids[6].Bid.AdM, "only the URLs the VAST loads are rewritten")
+			}
+		})
+	}
+}
+
+// testVAST is a VAST declaring XML namespaces, whose media file and trackers use the scheme it's formatted with
+const testVAST = `<?xml version="1.0" encoding="UTF-8"?>
+<VAST version="4.0" xmlns="http://www.iab.com/VAST" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+<Ad id="ad"><InLine>
+<Impression><![CDATA[%[1]s://tracker.com/imp]]></Impression>
+<Creatives><Creative><Linear>
+<TrackingEvents><Tracking event="start">%[1]s://tracker.com/start</Tracking></TrackingEvents>
+<VideoClicks><ClickThrough>http://advertiser.com</ClickThrough></VideoClicks>
+<MediaFiles><MediaFile delivery="progressive" type="video/mp4"><![CDATA[%[1]s://cdn.com/ad.mp4]]></MediaFile></MediaFiles>
+</Linear></Creative></Creatives>
+</InLine></Ad>
+</VAST>`
+
+func TestHasInsecureURL(t *testing.T) {
+	testCases := []struct {
+		description string
+		bid         openrtb2.Bid
+		expected    bool
+	}{
+		{
+			description: "html-secure-resources-insecure-link",
+			bid:         openrtb2.Bid{AdM: `<a href="http://advertiser.com"><img src="https://cdn.com/ad.png"></a>`},
+		},
+		{
+			description: "html-insecure-stylesheet",
+			bid:         openrtb2.Bid{AdM: `<link rel="stylesheet" href="http://cdn.com/ad.css">`},
+			expected:    true,
+		},
+		{
+			description: "html-insecure-css-url",
+			bid:         openrtb2.Bid{AdM: `<div style="background: url('http://cdn.com/bg.png')"></div>`},
+			expected:    true,
+		},
+		{
+			description: "html-json-escaped",
+			bid:         openrtb2.Bid{AdM: `<script src=\"http:\/\/cdn.com\/ad.js\"></script>`},
+			expected:    true,
+		},
+		{
+			description: "vast-namespaces",
+			bid:         openrtb2.Bid{AdM: fmt.Sprintf(testVAST, "https")},
+		},
+		{
+			description: "vast-insecure-media-file",
+			bid:         openrtb2.Bid{AdM: fmt.Sprintf(testVAST, "http")},
+			expected:    true,
+		},
+		{
+			description: "native-insecure-link",
+			bid:         openrtb2.Bid{AdM: `{"assets":[{"id":1,"img":{"url":"https://cdn.com/img.png"}}],"link":{"url":"http://advertiser.com"}}`},
+		},
+		{
+			description: "native-insecure-image",
+			bid:         openrtb2.Bid{AdM: `{"native":{"assets":[{"id":1,"img":{"url":"http:\/\/cdn.com\/img.png","w":300}}]}}`},
+			expected:    true,
+		},
+		{
+			description: "native-insecure-event-tracker",
+			bid:         openrtb2.Bid{AdM: `{"eventtrackers":[{"event":1,"method":1,"url":"https://tracker.com"},{"event":2,"method":1,"url":"http://tracker.com"}]}`},
+			expected:    true,
+		},
+		{
+			description: "insecure-nurl",
+			bid:         openrtb2.Bid{NURL: "http://win.com"},
+			expected:    true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expected, hasInsecureURL(&test.bid))
+		})
+	}
+}
+
 type mockAdaptedBidder struct {
 	bidResponse   []*entities.PbsOrtbSeatBid
 	extraRespInfo extraBidderRespInfo
@@ -0,0 +1,85 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+)
+
+// cappingUserID identifies the user for frequency capping, preferring the host cookie over the publisher
+// provided user id.
+func (e *exchange) cappingUserID(r *AuctionRequest) string {
+	if r.UserSyncs != nil && e.hostCookieFamily != "" {
+		if uid, exists, _ := r.UserSyncs.GetUID(e.hostCookieFamily); exists && uid != "" {
+			return uid
+		}
+	}
+	if r.BidRequestWrapper.User != nil {
+		return r.BidRequestWrapper.User.ID
+	}
+	return ""
+}
+
+// applyFrequencyCaps rejects the bids whose advertiser domain or deal the user has seen as often as the account
+// frequency caps allow. Bids are kept if the counters can't be read.
+func (e *exchange) applyFrequencyCaps(ctx context.Context, r *AuctionRequest, adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, seatNonBidBuilder *SeatNonBidBuilder) []error {
+	if e.frequencyCaps == nil || len(r.Account.FrequencyCaps) == 0 {
+		return nil
+	}
+	userID := e.cappingUserID(r)
+	if userID == "" {
+		return nil
+	}
+
+	var errs []error
+	for _, seatBid := range adapterBids {
+		bids := seatBid.Bids[:0]
+		for _, bid := range seatBid.Bids {
+			capped, err := e.frequencyCaps.Capped(ctx, r.Account.ID, r.Account.FrequencyCaps, userID, bid.Bid)
+			if err != nil {
+				errs = append(errs, &errortypes.Warning{
+					WarningCode: errortypes.FrequencyCapWarningCode,
+					Message:     fmt.Sprintf("frequency caps not applied to %s bid id %s: %v", seatBid.Seat, bid.Bid.ID, err),
+				})
+			}
+			if capped {
+				seatNonBidBuilder.rejectBid(bid, int(ResponseRejectedFrequencyCapped), seatBid.Seat)
+				continue
+			}
+			bids = append(bids, bid)
+		}
+		seatBid.Bids = bids
+	}
+	return errs
+}
+
+// recordServedBids remembers the bids which won their imp, so their impression events count against the account
+// frequency caps. Bids are identified the same way as in their event urls.
+func (e *exchange) recordServedBids(ctx context.Context, r *AuctionRequest, auc *auction, adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid) []error {
+	if e.frequencyCaps == nil || len(r.Account.FrequencyCaps) == 0 {
+		return nil
+	}
+	userID := e.cappingUserID(r)
+	if userID == "" {
+		return nil
+	}
+
+	var errs []error
+	for _, winner := range impWinners(auc, adapterBids) {
+		bid := winner.bid
+		bidID := bid.Bid.ID
+		if bid.GeneratedBidID != "" {
+			bidID = bid.GeneratedBidID
+		}
+		if err := e.frequencyCaps.RecordServed(ctx, r.Account.ID, bidID, userID, r.Account.FrequencyCaps, bid.Bid); err != nil {
+			errs = append(errs, &errortypes.Warning{
+				WarningCode: errortypes.FrequencyCapWarningCode,
+				Message:     fmt.Sprintf("%s bid id %s not recorded for frequency capping: %v", winner.seat, bidID, err),
+			})
+		}
+	}
+	return errs
+}
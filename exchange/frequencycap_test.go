@@ -0,0 +1,135 @@
+package exchange
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/frequencycap"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyFrequencyCaps(t *testing.T) {
+	caps := []config.AccountFrequencyCap{{Type: config.FrequencyCapTypeADomain, Count: 1, PeriodSeconds: 3600}}
+	cappedBid := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "capped", ImpID: "imp", Price: 2, ADomain: []string{"seen.com"}}}
+	otherBid := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "other", ImpID: "imp", Price: 1, ADomain: []string{"new.com"}}}
+
+	testCases := []struct {
+		name               string
+		caps               []config.AccountFrequencyCap
+		user               *openrtb2.User
+		expectedBids       []*entities.PbsOrtbBid
+		expectedSeatNonBid []openrtb_ext.SeatNonBid
+	}{
+		{
+			name:               "no-caps",
+			user:               &openrtb2.User{ID: "user"},
+			expectedBids:       []*entities.PbsOrtbBid{cappedBid, otherBid},
+			expectedSeatNonBid: []openrtb_ext.SeatNonBid{},
+		},
+		{
+			name:               "unknown-user",
+			caps:               caps,
+			expectedBids:       []*entities.PbsOrtbBid{cappedBid, otherBid},
+			expectedSeatNonBid: []openrtb_ext.SeatNonBid{},
+		},
+		{
+			name:         "capped",
+			caps:         caps,
+			user:         &openrtb2.User{ID: "user"},
+			expectedBids: []*entities.PbsOrtbBid{otherBid},
+			expectedSeatNonBid: []openrtb_ext.SeatNonBid{{
+				Seat: "appnexus",
+				NonBid: []openrtb_ext.NonBid{{
+					ImpId:      "imp",
+					StatusCode: int(ResponseRejectedFrequencyCapped),
+					Ext: &openrtb_ext.NonBidExt{Prebid: openrtb_ext.ExtResponseNonBidPrebid{Bid: openrtb_ext.NonBidObject{
+						Price:   2,
+						ADomain: []string{"seen.com"},
+					}}},
+				}},
+			}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			service := frequencycap.NewService(frequencycap.NewMemoryStore(&timeutil.RealTime{}), &timeutil.RealTime{}, time.Minute)
+			require.NoError(t, service.RecordServed(ctx, "account", "seen", "user", caps, &openrtb2.Bid{ADomain: []string{"seen.com"}}))
+			require.NoError(t, service.RecordImpression(ctx, "account", "seen", ""))
+
+			e := &exchange{frequencyCaps: service}
+			r := &AuctionRequest{
+				Account:           config.Account{ID: "account", FrequencyCaps: test.caps},
+				BidRequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{User: test.user}},
+			}
+			adapterBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+				"appnexus": {Seat: "appnexus", Bids: []*entities.PbsOrtbBid{cappedBid, otherBid}},
+			}
+			seatNonBidBuilder := SeatNonBidBuilder{}
+
+			errs := e.applyFrequencyCaps(ctx, r, adapterBids, &seatNonBidBuilder)
+
+			assert.Empty(t, errs)
+			assert.Equal(t, test.expectedBids, adapterBids["appnexus"].Bids)
+			assert.Equal(t, test.expectedSeatNonBid, seatNonBidBuilder.Slice())
+		})
+	}
+}
+
+func TestRecordServedBids(t *testing.T) {
+	ctx := context.Background()
+	caps := []config.AccountFrequencyCap{{Type: config.FrequencyCapTypeDeal, Count: 1, PeriodSeconds: 3600}}
+	r := &AuctionRequest{
+		Account:           config.Account{ID: "account", FrequencyCaps: caps},
+		BidRequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{User: &openrtb2.User{ID: "user"}}},
+	}
+	winner := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "bid-1", ImpID: "imp-1", Price: 2, DealID: "deal-1"}, GeneratedBidID: "generated"}
+	adapterBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		"appnexus": {Seat: "appnexus", Bids: []*entities.PbsOrtbBid{
+			winner,
+			{Bid: &openrtb2.Bid{ID: "bid-2", ImpID: "imp-1", Price: 1, DealID: "deal-2"}},
+			{Bid: &openrtb2.Bid{ID: "bid-3", ImpID: "imp-2", Price: 1, DealID: "deal-3"}},
+		}},
+	}
+
+	testCases := []struct {
+		name           string
+		auc            *auction
+		expectedCapped map[string]bool
+	}{
+		{
+			name:           "auction",
+			auc:            &auction{winningBids: map[string]*entities.PbsOrtbBid{"imp-1": winner}},
+			expectedCapped: map[string]bool{"deal-1": true, "deal-2": false, "deal-3": false},
+		},
+		{
+			name:           "no-auction",
+			expectedCapped: map[string]bool{"deal-1": true, "deal-2": false, "deal-3": true},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			service := frequencycap.NewService(frequencycap.NewMemoryStore(&timeutil.RealTime{}), &timeutil.RealTime{}, time.Minute)
+			e := &exchange{frequencyCaps: service}
+			assert.Empty(t, e.recordServedBids(ctx, r, test.auc, adapterBids))
+
+			for _, bidID := range []string{"generated", "bid-2", "bid-3"} {
+				require.NoError(t, service.RecordImpression(ctx, "account", bidID, ""))
+			}
+			for dealID, expectedCapped := range test.expectedCapped {
+				capped, err := service.Capped(ctx, "account", caps, "user", &openrtb2.Bid{DealID: dealID})
+				assert.NoError(t, err)
+				assert.Equal(t, expectedCapped, capped, dealID)
+			}
+		})
+	}
+}
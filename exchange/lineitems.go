@@ -0,0 +1,138 @@
+package exchange
+
+import (
+	"fmt"
+
+	"github.com/prebid/prebid-server/v3/currency"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/lineitems"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+)
+
+// matchLineItems finds the account line items targeting the request and passes the deals of those ready to
+// serve on to their source bidders. It must run before the request is split per bidder.
+func (e *exchange) matchLineItems(r *AuctionRequest) ([]lineitems.Match, []error) {
+	if e.lineItems == nil || len(r.Account.LineItems) == 0 {
+		return nil, nil
+	}
+
+	matches, errs := e.lineItems.Match(&r.Account, r.BidRequestWrapper, e.cappingUserID(r))
+	lineitems.InjectDeals(r.BidRequestWrapper, matches)
+	return matches, errs
+}
+
+// addLineItemBids enters the bids of ready line items with a creative into the auction under their source
+// seat, converted to the auction currency. It returns true if any bid was added.
+func addLineItemBids(matches []lineitems.Match, adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, conversions currency.Conversions, requestCurrencies []string) (bool, []error) {
+	var (
+		added bool
+		errs  []error
+	)
+
+	if len(requestCurrencies) == 0 {
+		requestCurrencies = []string{"USD"}
+	}
+
+	for _, match := range matches {
+		if match.Status != lineitems.StatusBid {
+			continue
+		}
+
+		seatName := openrtb_ext.BidderName(match.LineItem.Source)
+		seatBid, ok := adapterBids[seatName]
+		if !ok {
+			seatBid = &entities.PbsOrtbSeatBid{Seat: match.LineItem.Source}
+		}
+
+		bid, bidType := match.Bid()
+		lineItemCurrency := match.LineItem.Currency
+		if lineItemCurrency == "" {
+			lineItemCurrency = "USD"
+		}
+
+		// a seat which already holds bids has settled on a currency
+		targetCurrencies := requestCurrencies
+		if seatBid.Currency != "" {
+			targetCurrencies = []string{seatBid.Currency}
+		}
+
+		var (
+			rate float64
+			err  error
+		)
+		for _, targetCurrency := range targetCurrencies {
+			if rate, err = conversions.GetRate(lineItemCurrency, targetCurrency); err == nil {
+				seatBid.Currency = targetCurrency
+				break
+			}
+		}
+		if err != nil {
+			errs = append(errs, &errortypes.Warning{
+				WarningCode: errortypes.LineItemWarningCode,
+				Message:     fmt.Sprintf("line item %q not bid: %v", match.LineItem.ID, err),
+			})
+			continue
+		}
+
+		originalPrice := bid.Price
+		bid.Price = originalPrice * rate
+		seatBid.Bids = append(seatBid.Bids, &entities.PbsOrtbBid{
+			Bid:            bid,
+			BidType:        bidType,
+			OriginalBidCPM: originalPrice,
+			OriginalBidCur: lineItemCurrency,
+			AdapterCode:    seatName,
+		})
+		adapterBids[seatName] = seatBid
+		added = true
+	}
+
+	return added, errs
+}
+
+// recordLineItemWins remembers each ready line item whose bid won its imp, so its delivery is counted once the
+// impression event of the bid arrives. Bids are identified the same way as in their event urls.
+func (e *exchange) recordLineItemWins(r *AuctionRequest, matches []lineitems.Match, auc *auction, adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid) {
+	if len(matches) == 0 {
+		return
+	}
+
+	winners := impWinners(auc, adapterBids)
+	userID := e.cappingUserID(r)
+	for i, match := range matches {
+		if !match.Ready() {
+			continue
+		}
+		w, ok := winners[match.ImpID]
+		if !ok || w.seat != match.LineItem.Source || w.bid.Bid.DealID != match.LineItem.DealID {
+			continue
+		}
+		bidID := w.bid.Bid.ID
+		if w.bid.GeneratedBidID != "" {
+			bidID = w.bid.GeneratedBidID
+		}
+		e.lineItems.RecordWin(r.Account.ID, match.LineItem.ID, bidID, userID)
+		matches[i].Status = lineitems.StatusWon
+	}
+}
+
+// setLineItems adds the line items which targeted the request within bidResponse.Ext.Prebid.LineItems
+func setLineItems(bidResponseExt *openrtb_ext.ExtBidResponse, matches []lineitems.Match) {
+	if len(matches) == 0 || bidResponseExt == nil {
+		return
+	}
+	if bidResponseExt.Prebid == nil {
+		bidResponseExt.Prebid = &openrtb_ext.ExtResponsePrebid{}
+	}
+
+	for _, match := range matches {
+		bidResponseExt.Prebid.LineItems = append(bidResponseExt.Prebid.LineItems, openrtb_ext.ExtResponseLineItem{
+			ID:     match.LineItem.ID,
+			Source: match.LineItem.Source,
+			DealID: match.LineItem.DealID,
+			ImpID:  match.ImpID,
+			Status: string(match.Status),
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/currency"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/lineitems"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockLineItemService struct {
+	wins []string
+}
+
+func (m *mockLineItemService) Match(account *config.Account, req *openrtb_ext.RequestWrapper, userID string) ([]lineitems.Match, []error) {
+	return nil, nil
+}
+
+func (m *mockLineItemService) RecordWin(accountID, lineItemID, bidID, userID string) {
+	m.wins = append(m.wins, accountID+"|"+lineItemID+"|"+bidID+"|"+userID)
+}
+
+func (m *mockLineItemService) RecordImpression(accountID, bidID, userID string) {}
+
+func (m *mockLineItemService) Report(accountID string) []lineitems.Report {
+	return nil
+}
+
+func TestAddLineItemBids(t *testing.T) {
+	conversions := currency.NewRates(map[string]map[string]float64{"USD": {"EUR": 0.5}})
+	creative := &config.LineItemCreative{ID: "creative", AdM: "<div/>"}
+
+	testCases := []struct {
+		name              string
+		matches           []lineitems.Match
+		adapterBids       map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid
+		requestCurrencies []string
+		expectedAdded     bool
+		expectedBids      map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid
+		expectedErrs      []error
+	}{
+		{
+			name: "not-bidding",
+			matches: []lineitems.Match{
+				{LineItem: config.AccountLineItem{ID: "li", Source: "appnexus", DealID: "deal"}, ImpID: "imp", Status: lineitems.StatusInjected},
+			},
+			adapterBids:  map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{},
+			expectedBids: map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{},
+		},
+		{
+			name: "new-seat-in-request-currency",
+			matches: []lineitems.Match{
+				{LineItem: config.AccountLineItem{ID: "li", Source: "appnexus", DealID: "deal", Price: 2, Currency: "USD", Creative: creative}, ImpID: "imp", Status: lineitems.StatusBid},
+			},
+			adapterBids:       map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{},
+			requestCurrencies: []string{"GBP", "EUR"},
+			expectedAdded:     true,
+			expectedBids: map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+				"appnexus": {
+					Seat:     "appnexus",
+					Currency: "EUR",
+					Bids: []*entities.PbsOrtbBid{{
+						Bid:            &openrtb2.Bid{ID: "li-imp", ImpID: "imp", Price: 1, AdM: "<div/>", CrID: "creative", DealID: "deal"},
+						BidType:        openrtb_ext.BidTypeBanner,
+						OriginalBidCPM: 2,
+						OriginalBidCur: "USD",
+						AdapterCode:    "appnexus",
+					}},
+				},
+			},
+		},
+		{
+			name: "existing-seat-currency",
+			matches: []lineitems.Match{
+				{LineItem: config.AccountLineItem{ID: "li", Source: "appnexus", DealID: "deal", Price: 2, Creative: creative}, ImpID: "imp", Status: lineitems.StatusBid},
+			},
+			adapterBids: map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+				"appnexus": {Seat: "appnexus", Currency: "USD"},
+			},
+			requestCurrencies: []string{"EUR"},
+			expectedAdded:     true,
+			expectedBids: map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+				"appnexus": {
+					Seat:     "appnexus",
+					Currency: "USD",
+					Bids: []*entities.PbsOrtbBid{{
+						Bid:            &openrtb2.Bid{ID: "li-imp", ImpID: "imp", Price: 2, AdM: "<div/>", CrID: "creative", DealID: "deal"},
+						BidType:        openrtb_ext.BidTypeBanner,
+						OriginalBidCPM: 2,
+						OriginalBidCur: "USD",
+						AdapterCode:    "appnexus",
+					}},
+				},
+			},
+		},
+		{
+			name: "no-conversion",
+			matches: []lineitems.Match{
+				{LineItem: config.AccountLineItem{ID: "li", Source: "appnexus", DealID: "deal", Price: 2, Currency: "JPY", Creative: creative}, ImpID: "imp", Status: lineitems.StatusBid},
+			},
+			adapterBids:  map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{},
+			expectedBids: map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{},
+			expectedErrs: []error{&errortypes.Warning{
+				WarningCode: errortypes.LineItemWarningCode,
+				Message:     `line item "li" not bid: Currency conversion rate not found: 'JPY' => 'USD'`,
+			}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			added, errs := addLineItemBids(test.matches, test.adapterBids, conversions, test.requestCurrencies)
+
+			assert.Equal(t, test.expectedAdded, added)
+			assert.Equal(t, test.expectedBids, test.adapterBids)
+			assert.Equal(t, test.expectedErrs, errs)
+		})
+	}
+}
+
+func TestRecordLineItemWins(t *testing.T) {
+	lineItemBid := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "bid", ImpID: "imp-1", Price: 2, DealID: "deal"}, GeneratedBidID: "generated"}
+	otherBid := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ImpID: "imp-1", Price: 3}}
+	adapterBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		"appnexus": {Bids: []*entities.PbsOrtbBid{lineItemBid}},
+		"rubicon":  {Bids: []*entities.PbsOrtbBid{otherBid}},
+	}
+	newMatches := func() []lineitems.Match {
+		return []lineitems.Match{
+			{LineItem: config.AccountLineItem{ID: "li-1", Source: "appnexus", DealID: "deal"}, ImpID: "imp-1", Status: lineitems.StatusInjected},
+			{LineItem: config.AccountLineItem{ID: "li-2", Source: "appnexus", DealID: "deal"}, ImpID: "imp-1", Status: lineitems.StatusPaced},
+		}
+	}
+	r := &AuctionRequest{
+		Account:           config.Account{ID: "account"},
+		BidRequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{User: &openrtb2.User{ID: "user"}}},
+	}
+
+	testCases := []struct {
+		name           string
+		auc            *auction
+		expectedWins   []string
+		expectedStatus lineitems.Status
+	}{
+		{
+			name:           "won-auction",
+			auc:            &auction{winningBids: map[string]*entities.PbsOrtbBid{"imp-1": lineItemBid}},
+			expectedWins:   []string{"account|li-1|generated|user"},
+			expectedStatus: lineitems.StatusWon,
+		},
+		{
+			name:           "lost-auction",
+			auc:            &auction{winningBids: map[string]*entities.PbsOrtbBid{"imp-1": otherBid}},
+			expectedStatus: lineitems.StatusInjected,
+		},
+		{
+			name:           "no-auction-highest-bid-wins",
+			expectedStatus: lineitems.StatusInjected,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			service := &mockLineItemService{}
+			e := &exchange{lineItems: service}
+			matches := newMatches()
+
+			e.recordLineItemWins(r, matches, test.auc, adapterBids)
+
+			assert.Equal(t, test.expectedWins, service.wins)
+			assert.Equal(t, test.expectedStatus, matches[0].Status)
+			assert.Equal(t, lineitems.StatusPaced, matches[1].Status)
+		})
+	}
+}
+
+func TestSetLineItems(t *testing.T) {
+	bidResponseExt := &openrtb_ext.ExtBidResponse{}
+
+	setLineItems(bidResponseExt, []lineitems.Match{
+		{LineItem: config.AccountLineItem{ID: "li", Source: "appnexus", DealID: "deal"}, ImpID: "imp", Status: lineitems.StatusWon},
+	})
+
+	assert.Equal(t, &openrtb_ext.ExtResponsePrebid{LineItems: []openrtb_ext.ExtResponseLineItem{
+		{ID: "li", Source: "appnexus", DealID: "deal", ImpID: "imp", Status: "won"},
+	}}, bidResponseExt.Prebid)
+}
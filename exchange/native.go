@@ -0,0 +1,260 @@
+package exchange
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/native1"
+	nativeRequests "github.com/prebid/openrtb/v20/native1/request"
+	nativeResponse "github.com/prebid/openrtb/v20/native1/response"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/ortb"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// validateNativeBids checks native bids against the native request of their imp. Depending on the account or host
+// native_assets validation, invalid bids are rejected as seat non-bids or only reported as warnings.
+func (e *exchange) validateNativeBids(r *AuctionRequest, adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, seatNonBidBuilder *SeatNonBidBuilder) []error {
+	enforcement := e.bidValidationEnforcement.NativeAssetsEnforcement(r.Account.Validations)
+	if enforcement != config.ValidationEnforce && enforcement != config.ValidationWarn {
+		return nil
+	}
+
+	var errs []error
+	for _, seatBid := range adapterBids {
+		bids := seatBid.Bids[:0]
+		for _, bid := range seatBid.Bids {
+			if bid.BidType != openrtb_ext.BidTypeNative {
+				bids = append(bids, bid)
+				continue
+			}
+
+			err := validateNativeBid(bid.Bid, r.BidRequestWrapper.BidRequest)
+			if err == nil {
+				bids = append(bids, bid)
+				continue
+			}
+
+			if enforcement == config.ValidationEnforce {
+				errs = append(errs, &errortypes.Warning{
+					Message:     fmt.Sprintf("%s bid id %s rejected - %v", seatBid.Seat, bid.Bid.ID, err),
+					WarningCode: errortypes.InvalidNativeBidWarningCode,
+				})
+				seatNonBidBuilder.rejectBid(bid, int(ResponseRejectedCreativeInvalidNative), seatBid.Seat)
+				continue
+			}
+			errs = append(errs, &errortypes.Warning{
+				Message:     fmt.Sprintf("%s bid id %s - %v", seatBid.Seat, bid.Bid.ID, err),
+				WarningCode: errortypes.InvalidNativeBidWarningCode,
+			})
+			bids = append(bids, bid)
+		}
+		seatBid.Bids = bids
+	}
+	return errs
+}
+
+func validateNativeBid(bid *openrtb2.Bid, request *openrtb2.BidRequest) error {
+	nativeRequest, err := parseNativeRequest(bid.ImpID, request)
+	if err != nil {
+		return err
+	}
+
+	nativeMarkup, err := parseNativeMarkup(bid.AdM)
+	if err != nil {
+		return err
+	}
+	return ortb.ValidateNativeResponse(nativeRequest, nativeMarkup)
+}
+
+// parseNativeMarkup reads the native response of a bid, either at the root of its markup or under a native key as
+// in native 1.0
+func parseNativeMarkup(adm string) (nativeResponse.Response, error) {
+	var wrapped struct {
+		Native *nativeResponse.Response `json:"native"`
+	}
+	if err := jsonutil.UnmarshalValid(json.RawMessage(adm), &wrapped); err != nil {
+		return nativeResponse.Response{}, fmt.Errorf("native markup can't be parsed: %v", err)
+	}
+	if wrapped.Native != nil {
+		return *wrapped.Native, nil
+	}
+
+	var nativeMarkup nativeResponse.Response
+	if err := jsonutil.UnmarshalValid(json.RawMessage(adm), &nativeMarkup); err != nil {
+		return nativeResponse.Response{}, fmt.Errorf("native markup can't be parsed: %v", err)
+	}
+	return nativeMarkup, nil
+}
+
+func parseNativeRequest(impID string, request *openrtb2.BidRequest) (nativeRequests.Request, error) {
+	var nativeRequest nativeRequests.Request
+
+	nativeImp, err := getNativeImpByImpID(impID, request)
+	if err != nil {
+		return nativeRequest, fmt.Errorf("imp %s has no native request", impID)
+	}
+	if err := jsonutil.UnmarshalValid(json.RawMessage(nativeImp.Request), &nativeRequest); err != nil {
+		return nativeRequest, fmt.Errorf("native request of imp %s can't be parsed: %v", impID, err)
+	}
+	return nativeRequest, nil
+}
+
+// renderNativeBids renders native bids to HTML with the account native template, for AMP requests and requests
+// setting ext.prebid.nativerender, so they can be served where the page can't render native markup itself.
+func (e *exchange) renderNativeBids(r *AuctionRequest, requestExtPrebid *openrtb_ext.ExtRequestPrebid, adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid) []error {
+	if r.RequestType != metrics.ReqTypeAMP && !requestExtPrebid.NativeRender {
+		return nil
+	}
+
+	template := r.Account.Native.RenderTemplate
+	if template == "" {
+		if requestExtPrebid.NativeRender {
+			return []error{&errortypes.Warning{
+				Message:     "native bids can't be rendered without an account native render template",
+				WarningCode: errortypes.NativeRenderWarningCode,
+			}}
+		}
+		return nil
+	}
+
+	var errs []error
+	for _, seatBid := range adapterBids {
+		for _, bid := range seatBid.Bids {
+			if bid.BidType != openrtb_ext.BidTypeNative {
+				continue
+			}
+
+			markup, err := renderNative(template, bid.Bid, r.BidRequestWrapper.BidRequest)
+			if err != nil {
+				errs = append(errs, &errortypes.Warning{
+					Message:     fmt.Sprintf("%s bid id %s not rendered - %v", seatBid.Seat, bid.Bid.ID, err),
+					WarningCode: errortypes.NativeRenderWarningCode,
+				})
+				continue
+			}
+			bid.Bid.AdM = markup
+			bid.Bid.MType = openrtb2.MarkupBanner
+			bid.BidType = openrtb_ext.BidTypeBanner
+		}
+	}
+	return errs
+}
+
+// nativeMacro matches the Prebid.js native template macros, such as ##hb_native_title##.
+var nativeMacro = regexp.MustCompile(`##hb_native_([A-Za-z0-9]+)##`)
+
+// nativeDataKeys are the Prebid.js native keys of the data asset types.
+var nativeDataKeys = map[native1.DataAssetType]string{
+	native1.DataAssetTypeSponsored: "sponsoredBy",
+	native1.DataAssetTypeDesc:      "body",
+	native1.DataAssetTypeRating:    "rating",
+	native1.DataAssetTypeLikes:     "likes",
+	native1.DataAssetTypeDownloads: "downloads",
+	native1.DataAssetTypePrice:     "price",
+	native1.DataAssetTypeSalePrice: "salePrice",
+	native1.DataAssetTypePhone:     "phone",
+	native1.DataAssetTypeAddress:   "address",
+	native1.DataAssetTypeDesc2:     "body2",
+	native1.DataAssetTypeDispayURL: "displayUrl",
+	native1.DataAssetTypeCTAText:   "cta",
+}
+
+// renderNative replaces the template macros with the escaped native assets, then appends the impression trackers.
+// Macros without a matching asset are removed.
+func renderNative(template string, bid *openrtb2.Bid, request *openrtb2.BidRequest) (string, error) {
+	nativeRequest, err := parseNativeRequest(bid.ImpID, request)
+	if err != nil {
+		return "", err
+	}
+	nativeMarkup, err := parseNativeMarkup(bid.AdM)
+	if err != nil {
+		return "", err
+	}
+
+	values := nativeValues(nativeRequest, nativeMarkup)
+	markup := nativeMacro.ReplaceAllStringFunc(template, func(macro string) string {
+		key := nativeMacro.FindStringSubmatch(macro)[1]
+		return html.EscapeString(values[key])
+	})
+
+	var trackers strings.Builder
+	for _, url := range nativeMarkup.ImpTrackers {
+		trackers.WriteString(nativeImageTracker(url))
+	}
+	for _, tracker := range nativeMarkup.EventTrackers {
+		if tracker.Event != native1.EventTypeImpression || tracker.URL == "" {
+			continue
+		}
+		switch tracker.Method {
+		case native1.EventTrackingMethodImage:
+			trackers.WriteString(nativeImageTracker(tracker.URL))
+		case native1.EventTrackingMethodJS:
+			trackers.WriteString(`<script async src="` + html.EscapeString(tracker.URL) + `"></script>`)
+		}
+	}
+	return markup + trackers.String(), nil
+}
+
+func nativeImageTracker(url string) string {
+	return `<img src="` + html.EscapeString(url) + `" width="1" height="1" style="display:none" alt="">`
+}
+
+// nativeValues maps the Prebid.js native keys to the response assets. Image and data types are taken from the
+// response when set, or else from the requested asset with the same id.
+func nativeValues(request nativeRequests.Request, response nativeResponse.Response) map[string]string {
+	requestAssets := make(map[int64]nativeRequests.Asset, len(request.Assets))
+	for _, asset := range request.Assets {
+		requestAssets[asset.ID] = asset
+	}
+
+	values := map[string]string{
+		"privacyLink": response.Privacy,
+	}
+	if response.Link.URL != "" {
+		values["linkurl"] = response.Link.URL
+	}
+
+	for _, asset := range response.Assets {
+		var requestAsset nativeRequests.Asset
+		if asset.ID != nil {
+			requestAsset = requestAssets[*asset.ID]
+		}
+
+		switch {
+		case asset.Title != nil:
+			values["title"] = asset.Title.Text
+		case asset.Img != nil:
+			imageType := asset.Img.Type
+			if imageType == 0 && requestAsset.Img != nil {
+				imageType = requestAsset.Img.Type
+			}
+			switch imageType {
+			case native1.ImageAssetTypeIcon:
+				values["icon"] = asset.Img.URL
+			case native1.ImageAssetTypeMain:
+				values["image"] = asset.Img.URL
+			}
+		case asset.Data != nil:
+			dataType := asset.Data.Type
+			if dataType == 0 && requestAsset.Data != nil {
+				dataType = requestAsset.Data.Type
+			}
+			if key, ok := nativeDataKeys[dataType]; ok {
+				values[key] = asset.Data.Value
+			}
+		}
+		if asset.Link != nil && asset.Link.URL != "" && values["linkurl"] == "" {
+			values["linkurl"] = asset.Link.URL
+		}
+	}
+	return values
+}
@@ -0,0 +1,233 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testNativeRequest = `{"ver":"1.2","assets":[{"id":1,"required":1,"title":{"len":20}},{"id":2,"required":1,"img":{"type":3,"wmin":100,"hmin":100}},{"id":3,"data":{"type":1}}]}`
+	testNativeMarkup  = `{"assets":[{"id":1,"title":{"text":"Buy <now>"}},{"id":2,"img":{"url":"https://img","w":300,"h":300}},{"id":3,"data":{"value":"Brand"}}],"link":{"url":"https://click"},"imptrackers":["https://imp"],"eventtrackers":[{"event":1,"method":2,"url":"https://imp.js"}]}`
+)
+
+func newNativeAuctionRequest(account config.Account) *AuctionRequest {
+	return &AuctionRequest{
+		BidRequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+			Imp: []openrtb2.Imp{
+				{ID: "imp-1", Native: &openrtb2.Native{Request: testNativeRequest}},
+				{ID: "imp-2", Banner: &openrtb2.Banner{}},
+			},
+		}},
+		Account: account,
+	}
+}
+
+func TestValidateNativeBids(t *testing.T) {
+	invalidMarkup := `{"assets":[{"id":1,"title":{"text":"Buy now"}}]}`
+
+	testCases := []struct {
+		name             string
+		hostEnforcement  string
+		account          config.Account
+		expectedBidIDs   []string
+		expectedWarnings []string
+		expectedNonBids  int
+	}{
+		{
+			name:            "skip",
+			hostEnforcement: config.ValidationSkip,
+			expectedBidIDs:  []string{"valid", "wrapped", "invalid", "banner"},
+		},
+		{
+			name:             "warn",
+			hostEnforcement:  config.ValidationWarn,
+			expectedBidIDs:   []string{"valid", "wrapped", "invalid", "banner"},
+			expectedWarnings: []string{"appnexus bid id invalid - native response is missing required asset 2"},
+		},
+		{
+			name:             "enforce",
+			hostEnforcement:  config.ValidationEnforce,
+			expectedBidIDs:   []string{"valid", "wrapped", "banner"},
+			expectedWarnings: []string{"appnexus bid id invalid rejected - native response is missing required asset 2"},
+			expectedNonBids:  1,
+		},
+		{
+			name:             "account-overrides-host",
+			hostEnforcement:  config.ValidationSkip,
+			account:          config.Account{Validations: config.Validations{NativeAssets: config.ValidationEnforce}},
+			expectedBidIDs:   []string{"valid", "wrapped", "banner"},
+			expectedWarnings: []string{"appnexus bid id invalid rejected - native response is missing required asset 2"},
+			expectedNonBids:  1,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			e := &exchange{bidValidationEnforcement: config.Validations{NativeAssets: test.hostEnforcement}}
+			adapterBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+				"appnexus": {Seat: "appnexus", Bids: []*entities.PbsOrtbBid{
+					{Bid: &openrtb2.Bid{ID: "valid", ImpID: "imp-1", AdM: testNativeMarkup}, BidType: openrtb_ext.BidTypeNative},
+					{Bid: &openrtb2.Bid{ID: "wrapped", ImpID: "imp-1", AdM: `{"native":` + testNativeMarkup + `}`}, BidType: openrtb_ext.BidTypeNative},
+					{Bid: &openrtb2.Bid{ID: "invalid", ImpID: "imp-1", AdM: invalidMarkup}, BidType: openrtb_ext.BidTypeNative},
+					{Bid: &openrtb2.Bid{ID: "banner", ImpID: "imp-2"}, BidType: openrtb_ext.BidTypeBanner},
+				}},
+			}
+			seatNonBidBuilder := SeatNonBidBuilder{}
+
+			errs := e.validateNativeBids(newNativeAuctionRequest(test.account), adapterBids, &seatNonBidBuilder)
+
+			var bidIDs []string
+			for _, bid := range adapterBids["appnexus"].Bids {
+				bidIDs = append(bidIDs, bid.Bid.ID)
+			}
+			assert.Equal(t, test.expectedBidIDs, bidIDs)
+
+			var warnings []string
+			for _, err := range errs {
+				assert.Equal(t, errortypes.InvalidNativeBidWarningCode, errortypes.ReadCode(err))
+				warnings = append(warnings, err.Error())
+			}
+			assert.Equal(t, test.expectedWarnings, warnings)
+
+			nonBids := seatNonBidBuilder.Slice()
+			if test.expectedNonBids == 0 {
+				assert.Empty(t, nonBids)
+			} else {
+				assert.Len(t, nonBids, 1)
+				assert.Len(t, nonBids[0].NonBid, test.expectedNonBids)
+				assert.Equal(t, int(ResponseRejectedCreativeInvalidNative), nonBids[0].NonBid[0].StatusCode)
+			}
+		})
+	}
+}
+
+func TestValidateNativeBidsUnparsableMarkup(t *testing.T) {
+	e := &exchange{bidValidationEnforcement: config.Validations{NativeAssets: config.ValidationEnforce}}
+	adapterBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		"appnexus": {Seat: "appnexus", Bids: []*entities.PbsOrtbBid{
+			{Bid: &openrtb2.Bid{ID: "bid-1", ImpID: "imp-1", AdM: "<div>"}, BidType: openrtb_ext.BidTypeNative},
+			{Bid: &openrtb2.Bid{ID: "bid-2", ImpID: "imp-2", AdM: testNativeMarkup}, BidType: openrtb_ext.BidTypeNative},
+		}},
+	}
+	seatNonBidBuilder := SeatNonBidBuilder{}
+
+	errs := e.validateNativeBids(newNativeAuctionRequest(config.Account{}), adapterBids, &seatNonBidBuilder)
+
+	assert.Empty(t, adapterBids["appnexus"].Bids)
+	if assert.Len(t, errs, 2) {
+		assert.Contains(t, errs[0].Error(), "appnexus bid id bid-1 rejected - native markup can't be parsed")
+		assert.EqualError(t, errs[1], "appnexus bid id bid-2 rejected - imp imp-2 has no native request")
+	}
+}
+
+func TestRenderNativeBids(t *testing.T) {
+	template := `<a href="##hb_native_linkurl##"><img src="##hb_native_image##">##hb_native_title## by ##hb_native_sponsoredBy####hb_native_body##</a>`
+	rendered := `<a href="https://click"><img src="https://img">Buy &lt;now&gt; by Brand</a>` +
+		`<img src="https://imp" width="1" height="1" style="display:none" alt=""><script async src="https://imp.js"></script>`
+
+	testCases := []struct {
+		name             string
+		requestType      metrics.RequestType
+		requestExtPrebid *openrtb_ext.ExtRequestPrebid
+		template         string
+		expectedAdM      string
+		expectedType     openrtb_ext.BidType
+		expectedWarning  string
+	}{
+		{
+			name:             "amp",
+			requestType:      metrics.ReqTypeAMP,
+			requestExtPrebid: &openrtb_ext.ExtRequestPrebid{},
+			template:         template,
+			expectedAdM:      rendered,
+			expectedType:     openrtb_ext.BidTypeBanner,
+		},
+		{
+			name:             "requested",
+			requestType:      metrics.ReqTypeORTB2Web,
+			requestExtPrebid: &openrtb_ext.ExtRequestPrebid{NativeRender: true},
+			template:         template,
+			expectedAdM:      rendered,
+			expectedType:     openrtb_ext.BidTypeBanner,
+		},
+		{
+			name:             "not-requested",
+			requestType:      metrics.ReqTypeORTB2Web,
+			requestExtPrebid: &openrtb_ext.ExtRequestPrebid{},
+			template:         template,
+			expectedAdM:      testNativeMarkup,
+			expectedType:     openrtb_ext.BidTypeNative,
+		},
+		{
+			name:             "amp-without-template",
+			requestType:      metrics.ReqTypeAMP,
+			requestExtPrebid: &openrtb_ext.ExtRequestPrebid{},
+			expectedAdM:      testNativeMarkup,
+			expectedType:     openrtb_ext.BidTypeNative,
+		},
+		{
+			name:             "requested-without-template",
+			requestType:      metrics.ReqTypeORTB2Web,
+			requestExtPrebid: &openrtb_ext.ExtRequestPrebid{NativeRender: true},
+			expectedAdM:      testNativeMarkup,
+			expectedType:     openrtb_ext.BidTypeNative,
+			expectedWarning:  "native bids can't be rendered without an account native render template",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			bid := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "bid-1", ImpID: "imp-1", AdM: testNativeMarkup, MType: openrtb2.MarkupNative}, BidType: openrtb_ext.BidTypeNative}
+			adapterBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+				"appnexus": {Seat: "appnexus", Bids: []*entities.PbsOrtbBid{bid}},
+			}
+			r := newNativeAuctionRequest(config.Account{Native: config.AccountNative{RenderTemplate: test.template}})
+			r.RequestType = test.requestType
+
+			errs := (&exchange{}).renderNativeBids(r, test.requestExtPrebid, adapterBids)
+
+			assert.Equal(t, test.expectedAdM, bid.Bid.AdM)
+			assert.Equal(t, test.expectedType, bid.BidType)
+			if test.expectedWarning == "" {
+				assert.Empty(t, errs)
+			} else if assert.Len(t, errs, 1) {
+				assert.EqualError(t, errs[0], test.expectedWarning)
+				assert.Equal(t, errortypes.NativeRenderWarningCode, errortypes.ReadCode(errs[0]))
+			}
+		})
+	}
+}
+
+func TestRenderNativeWrappedMarkup(t *testing.T) {
+	template := `<img src="##hb_native_image##">##hb_native_title##`
+	bid := &openrtb2.Bid{ImpID: "imp-1", AdM: `{"native":` + testNativeMarkup + `}`}
+
+	markup, err := renderNative(template, bid, newNativeAuctionRequest(config.Account{}).BidRequestWrapper.BidRequest)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `<img src="https://img">Buy &lt;now&gt;`+
+		`<img src="https://imp" width="1" height="1" style="display:none" alt=""><script async src="https://imp.js"></script>`, markup)
+}
+
+func TestRenderNativeBidsUnparsableMarkup(t *testing.T) {
+	bid := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "bid-1", ImpID: "imp-1", AdM: "<div>"}, BidType: openrtb_ext.BidTypeNative}
+	adapterBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		"appnexus": {Seat: "appnexus", Bids: []*entities.PbsOrtbBid{bid}},
+	}
+	r := newNativeAuctionRequest(config.Account{Native: config.AccountNative{RenderTemplate: "##hb_native_title##"}})
+	r.RequestType = metrics.ReqTypeAMP
+
+	errs := (&exchange{}).renderNativeBids(r, &openrtb_ext.ExtRequestPrebid{}, adapterBids)
+
+	assert.Equal(t, "<div>", bid.Bid.AdM)
+	if assert.Len(t, errs, 1) {
+		assert.Contains(t, errs[0].Error(), "appnexus bid id bid-1 not rendered - native markup can't be parsed")
+	}
+}
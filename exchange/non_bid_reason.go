@@ -23,6 +23,10 @@ const (
 	ResponseRejectedBelowDealFloor         NonBidReason = 304 // Response Rejected - Bid was Below Deal Floor
 	ResponseRejectedCreativeSizeNotAllowed NonBidReason = 351 // Response Rejected - Invalid Creative (Size Not Allowed)
 	ResponseRejectedCreativeNotSecure      NonBidReason = 352 // Response Rejected - Invalid Creative (Not Secure)
+	ResponseRejectedCreativeInvalidNative  NonBidReason = 353 // Response Rejected - Invalid Creative (Incorrect Format)
+	ResponseRejectedFrequencyCapped        NonBidReason = 500 // Exchange Specific - User reached an account frequency cap
+	ResponseRejectedCompetitiveSeparation  NonBidReason = 501 // Exchange Specific - Bid conflicts with the winner of another imp
+	ResponseRejectedCreativeInsecureURL    NonBidReason = 502 // Exchange Specific - Creative loads HTTP URLs for a secure imp
 )
 
 func errorToNonBidReason(err error) NonBidReason {
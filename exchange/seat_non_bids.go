@@ -75,3 +75,13 @@ func (b SeatNonBidBuilder) append(nonBids ...SeatNonBidBuilder) {
 		}
 	}
 }
+
+// appendSeatNonBid adds the non bids reported by other components, such as hooks, to the builder
+func (b SeatNonBidBuilder) appendSeatNonBid(seatNonBids []openrtb_ext.SeatNonBid) {
+	if b == nil {
+		return
+	}
+	for _, seatNonBid := range seatNonBids {
+		b[seatNonBid.Seat] = append(b[seatNonBid.Seat], seatNonBid.NonBid...)
+	}
+}
@@ -0,0 +1,187 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/golang/glog"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// storedResponseRecording holds the bids of a live auction recorded as stored responses.
+type storedResponseRecording struct {
+	id string
+	// seatBids are the bids of each seat as they entered the auction, captured before any later auction stage
+	// alters them. They are priced as replayed by stored auction responses, adjusted and converted to the
+	// auction currency.
+	seatBids []openrtb2.SeatBid
+	// bidderResponses are the raw bodies the bidders returned, by imp ID and bidder, replayed by stored bid
+	// responses which go through the adapter MakeBids again.
+	bidderResponses map[string]map[string]json.RawMessage
+}
+
+// newStoredResponseRecording returns the recording requested by ext.prebid.storedresponserecording, or a warning
+// if the auction can't be recorded. Recording is restricted to requests carrying the debug override header.
+func (e *exchange) newStoredResponseRecording(r *AuctionRequest, requestExtPrebid *openrtb_ext.ExtRequestPrebid, debugLog *DebugLog) (*storedResponseRecording, error) {
+	if requestExtPrebid.StoredResponseRecording == nil {
+		return nil, nil
+	}
+
+	var message string
+	switch {
+	case e.storedResponseWriter == nil:
+		message = "stored response recording is not enabled"
+	case debugLog == nil || !debugLog.DebugOverride:
+		message = "stored response recording requires the " + DebugOverrideHeader + " header"
+	case requestExtPrebid.StoredResponseRecording.ID == "":
+		message = "request.ext.prebid.storedresponserecording.id must not be empty"
+	case len(r.StoredAuctionResponses) > 0:
+		message = "stored response recording is not available for auctions replaying stored auction responses"
+	default:
+		return &storedResponseRecording{id: requestExtPrebid.StoredResponseRecording.ID}, nil
+	}
+	return nil, &errortypes.Warning{
+		Message:     message,
+		WarningCode: errortypes.StoredResponseRecordingWarningCode,
+	}
+}
+
+var markupTypes = map[openrtb_ext.BidType]openrtb2.MarkupType{
+	openrtb_ext.BidTypeBanner: openrtb2.MarkupBanner,
+	openrtb_ext.BidTypeVideo:  openrtb2.MarkupVideo,
+	openrtb_ext.BidTypeAudio:  openrtb2.MarkupAudio,
+	openrtb_ext.BidTypeNative: openrtb2.MarkupNative,
+}
+
+// capture copies the bids of each seat, so later stages modifying the bids don't alter the recording, along
+// with the bodies of the bidder responses.
+func (recording *storedResponseRecording) capture(adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid) {
+	seats := make([]openrtb_ext.BidderName, 0, len(adapterBids))
+	for seat := range adapterBids {
+		seats = append(seats, seat)
+	}
+	sort.Slice(seats, func(i, j int) bool { return seats[i] < seats[j] })
+
+	for _, seat := range seats {
+		seatBid := openrtb2.SeatBid{Seat: string(seat)}
+		for _, pbsBid := range adapterBids[seat].Bids {
+			if pbsBid == nil || pbsBid.Bid == nil {
+				continue
+			}
+			bid := *pbsBid.Bid
+			if bid.MType == 0 {
+				// stored auction responses need the media type on the bid
+				bid.MType = markupTypes[pbsBid.BidType]
+			}
+			seatBid.Bid = append(seatBid.Bid, bid)
+		}
+		if len(seatBid.Bid) > 0 {
+			recording.seatBids = append(recording.seatBids, seatBid)
+		}
+		recording.captureBidderResponses(string(seat), adapterBids[seat].HttpCalls)
+	}
+}
+
+// captureBidderResponses records the body of each successful bidder call made for a single imp. The debug http
+// calls are always there, as recording requires the debug override header. A body answering several imps
+// can't be replayed for one of them, and neither can the one of a call whose imp isn't found in an OpenRTB
+// request body, so those are left out of the stored bid responses.
+func (recording *storedResponseRecording) captureBidderResponses(bidder string, httpCalls []*openrtb_ext.ExtHttpCall) {
+	for _, httpCall := range httpCalls {
+		if httpCall == nil || httpCall.Status != http.StatusOK || httpCall.ResponseBody == "" {
+			continue
+		}
+		var request struct {
+			Imp []struct {
+				ID string `json:"id"`
+			} `json:"imp"`
+		}
+		if err := jsonutil.Unmarshal([]byte(httpCall.RequestBody), &request); err != nil || len(request.Imp) != 1 || request.Imp[0].ID == "" {
+			continue
+		}
+		impID := request.Imp[0].ID
+		if recording.bidderResponses == nil {
+			recording.bidderResponses = make(map[string]map[string]json.RawMessage)
+		}
+		if recording.bidderResponses[impID] == nil {
+			recording.bidderResponses[impID] = make(map[string]json.RawMessage)
+		}
+		recording.bidderResponses[impID][bidder] = json.RawMessage(httpCall.ResponseBody)
+	}
+}
+
+// storedResponses returns the stored responses of the recording:
+//   - "{id}": the full auction response.
+//   - "{id}-{impid}": a stored auction response with the bids of every seat for the imp, priced as in the auction.
+//   - "{id}-{impid}-{bidder}": a stored bid response with the body the bidder returned for the imp.
+func (recording *storedResponseRecording) storedResponses(bidResponse *openrtb2.BidResponse) (map[string]json.RawMessage, error) {
+	responses := make(map[string]json.RawMessage)
+
+	auctionResponse, err := jsonutil.Marshal(bidResponse)
+	if err != nil {
+		return nil, err
+	}
+	responses[recording.id] = auctionResponse
+
+	impSeatBids := make(map[string][]openrtb2.SeatBid)
+	for _, seatBid := range recording.seatBids {
+		impBids := make(map[string][]openrtb2.Bid)
+		var impIDs []string
+		for _, bid := range seatBid.Bid {
+			if _, ok := impBids[bid.ImpID]; !ok {
+				impIDs = append(impIDs, bid.ImpID)
+			}
+			impBids[bid.ImpID] = append(impBids[bid.ImpID], bid)
+		}
+		for _, impID := range impIDs {
+			impSeatBids[impID] = append(impSeatBids[impID], openrtb2.SeatBid{Seat: seatBid.Seat, Bid: impBids[impID]})
+		}
+	}
+
+	for impID, seatBids := range impSeatBids {
+		storedAuctionResponse, err := jsonutil.Marshal(seatBids)
+		if err != nil {
+			return nil, err
+		}
+		responses[recording.id+"-"+impID] = storedAuctionResponse
+	}
+
+	for impID, bidderResponses := range recording.bidderResponses {
+		for bidder, body := range bidderResponses {
+			responses[recording.id+"-"+impID+"-"+bidder] = body
+		}
+	}
+	return responses, nil
+}
+
+// writeStoredResponseRecording builds the stored responses of the recording and writes them in the background,
+// so the auction response isn't held up by the writer. The write has its own timeout, as the auction deadline
+// may have already passed once the response is built. The returned channel is closed once the write is done.
+func (e *exchange) writeStoredResponseRecording(ctx context.Context, recording *storedResponseRecording, bidResponse *openrtb2.BidResponse) (<-chan struct{}, error) {
+	responses, err := recording.storedResponses(bidResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	writeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), e.recordingTimeout)
+	go func() {
+		defer close(done)
+		defer cancel()
+		defer func() {
+			if r := recover(); r != nil {
+				glog.Errorf("Panic recording stored responses %s: %v", recording.id, r)
+			}
+		}()
+		if err := e.storedResponseWriter.WriteResponses(writeCtx, responses); err != nil {
+			glog.Errorf("Failed to record stored responses %s: %v", recording.id, err)
+		}
+	}()
+	return done, nil
+}
@@ -0,0 +1,283 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/currency"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/experiment/adscert"
+	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
+	metricsConfig "github.com/prebid/prebid-server/v3/metrics/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/stored_responses"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStoredResponseWriter struct {
+	responses map[string]json.RawMessage
+	err       error
+	// release holds the write until it's closed, if set
+	release chan struct{}
+}
+
+func (w *fakeStoredResponseWriter) WriteResponses(ctx context.Context, responses map[string]json.RawMessage) error {
+	if w.release != nil {
+		<-w.release
+	}
+	w.responses = responses
+	return w.err
+}
+
+func TestNewStoredResponseRecording(t *testing.T) {
+	recordingExt := &openrtb_ext.ExtRequestPrebid{StoredResponseRecording: &openrtb_ext.ExtStoredResponseRecording{ID: "rec-1"}}
+
+	testCases := []struct {
+		name              string
+		writer            *fakeStoredResponseWriter
+		requestExtPrebid  *openrtb_ext.ExtRequestPrebid
+		debugLog          *DebugLog
+		storedAuctionResp stored_responses.ImpsWithBidResponses
+		expectedRecording *storedResponseRecording
+		expectedWarning   string
+	}{
+		{
+			name:             "not-requested",
+			writer:           &fakeStoredResponseWriter{},
+			requestExtPrebid: &openrtb_ext.ExtRequestPrebid{},
+			debugLog:         &DebugLog{DebugOverride: true},
+		},
+		{
+			name:              "recorded",
+			writer:            &fakeStoredResponseWriter{},
+			requestExtPrebid:  recordingExt,
+			debugLog:          &DebugLog{DebugOverride: true},
+			expectedRecording: &storedResponseRecording{id: "rec-1"},
+		},
+		{
+			name:             "recording-disabled",
+			requestExtPrebid: recordingExt,
+			debugLog:         &DebugLog{DebugOverride: true},
+			expectedWarning:  "stored response recording is not enabled",
+		},
+		{
+			name:             "no-debug-override",
+			writer:           &fakeStoredResponseWriter{},
+			requestExtPrebid: recordingExt,
+			debugLog:         &DebugLog{},
+			expectedWarning:  "stored response recording requires the x-pbs-debug-override header",
+		},
+		{
+			name:             "empty-id",
+			writer:           &fakeStoredResponseWriter{},
+			requestExtPrebid: &openrtb_ext.ExtRequestPrebid{StoredResponseRecording: &openrtb_ext.ExtStoredResponseRecording{}},
+			debugLog:         &DebugLog{DebugOverride: true},
+			expectedWarning:  "request.ext.prebid.storedresponserecording.id must not be empty",
+		},
+		{
+			name:              "replaying-stored-auction-responses",
+			writer:            &fakeStoredResponseWriter{},
+			requestExtPrebid:  recordingExt,
+			debugLog:          &DebugLog{DebugOverride: true},
+			storedAuctionResp: stored_responses.ImpsWithBidResponses{"imp-1": json.RawMessage(`[]`)},
+			expectedWarning:   "stored response recording is not available for auctions replaying stored auction responses",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			e := &exchange{}
+			if test.writer != nil {
+				e.storedResponseWriter = test.writer
+			}
+			r := &AuctionRequest{StoredAuctionResponses: test.storedAuctionResp}
+
+			recording, warning := e.newStoredResponseRecording(r, test.requestExtPrebid, test.debugLog)
+
+			assert.Equal(t, test.expectedRecording, recording)
+			if test.expectedWarning == "" {
+				assert.NoError(t, warning)
+			} else {
+				assert.EqualError(t, warning, test.expectedWarning)
+				assert.Equal(t, errortypes.StoredResponseRecordingWarningCode, errortypes.ReadCode(warning))
+			}
+		})
+	}
+}
+
+func TestStoredResponseRecordingCapture(t *testing.T) {
+	bid := &openrtb2.Bid{ID: "bid-1", ImpID: "imp-1", Price: 1}
+	adapterBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		"rubicon": {Seat: "rubicon", Bids: []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ID: "bid-2", ImpID: "imp-1", Price: 2, MType: openrtb2.MarkupBanner}, BidType: openrtb_ext.BidTypeBanner}}},
+		"appnexus": {
+			Seat: "appnexus",
+			Bids: []*entities.PbsOrtbBid{{Bid: bid, BidType: openrtb_ext.BidTypeVideo}},
+			HttpCalls: []*openrtb_ext.ExtHttpCall{
+				{RequestBody: `{"imp":[{"id":"imp-1"}]}`, ResponseBody: `{"raw":"imp-1"}`, Status: http.StatusOK},
+				{RequestBody: `{"imp":[{"id":"imp-2"},{"id":"imp-3"}]}`, ResponseBody: `{"raw":"imp-2-3"}`, Status: http.StatusOK},
+				{RequestBody: `{"imp":[{"id":"imp-4"}]}`, Status: http.StatusNoContent},
+				{RequestBody: `imp=imp-5`, ResponseBody: `{"raw":"imp-5"}`, Status: http.StatusOK},
+			},
+		},
+		"openx": {Seat: "openx"},
+	}
+
+	recording := &storedResponseRecording{id: "rec-1"}
+	recording.capture(adapterBids)
+	bid.Price = 10
+
+	expectedSeatBids := []openrtb2.SeatBid{
+		{Seat: "appnexus", Bid: []openrtb2.Bid{{ID: "bid-1", ImpID: "imp-1", Price: 1, MType: openrtb2.MarkupVideo}}},
+		{Seat: "rubicon", Bid: []openrtb2.Bid{{ID: "bid-2", ImpID: "imp-1", Price: 2, MType: openrtb2.MarkupBanner}}},
+	}
+	assert.Equal(t, expectedSeatBids, recording.seatBids)
+	expectedBidderResponses := map[string]map[string]json.RawMessage{
+		"imp-1": {"appnexus": json.RawMessage(`{"raw":"imp-1"}`)},
+	}
+	assert.Equal(t, expectedBidderResponses, recording.bidderResponses)
+}
+
+func TestStoredResponseRecordingStoredResponses(t *testing.T) {
+	recording := &storedResponseRecording{
+		id: "rec-1",
+		seatBids: []openrtb2.SeatBid{
+			{Seat: "appnexus", Bid: []openrtb2.Bid{
+				{ID: "bid-1", ImpID: "imp-1", Price: 1, MType: openrtb2.MarkupBanner},
+				{ID: "bid-2", ImpID: "imp-2", Price: 2, MType: openrtb2.MarkupBanner},
+			}},
+			{Seat: "rubicon", Bid: []openrtb2.Bid{{ID: "bid-3", ImpID: "imp-1", Price: 3, MType: openrtb2.MarkupVideo}}},
+		},
+		bidderResponses: map[string]map[string]json.RawMessage{
+			"imp-1": {"appnexus": json.RawMessage(`{"raw":"appnexus-imp-1"}`), "rubicon": json.RawMessage(`{"raw":"rubicon-imp-1"}`)},
+		},
+	}
+	bidResponse := &openrtb2.BidResponse{ID: "response", Cur: "USD"}
+
+	responses, err := recording.storedResponses(bidResponse)
+	require.NoError(t, err)
+
+	expected := map[string]string{
+		"rec-1":                `{"id":"response","cur":"USD"}`,
+		"rec-1-imp-1":          `[{"bid":[{"id":"bid-1","impid":"imp-1","price":1,"mtype":1}],"seat":"appnexus"},{"bid":[{"id":"bid-3","impid":"imp-1","price":3,"mtype":2}],"seat":"rubicon"}]`,
+		"rec-1-imp-2":          `[{"bid":[{"id":"bid-2","impid":"imp-2","price":2,"mtype":1}],"seat":"appnexus"}]`,
+		"rec-1-imp-1-appnexus": `{"raw":"appnexus-imp-1"}`,
+		"rec-1-imp-1-rubicon":  `{"raw":"rubicon-imp-1"}`,
+	}
+	require.Len(t, responses, len(expected))
+	for id, data := range expected {
+		assert.JSONEq(t, data, string(responses[id]), id)
+	}
+
+	// the recorded stored auction response replays the recorded bids
+	adapterBids, _, liveAdapters, err := buildStoredAuctionResponse(stored_responses.ImpsWithBidResponses{"imp-1": responses["rec-1-imp-1"]})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []openrtb_ext.BidderName{"appnexus", "rubicon"}, liveAdapters)
+	assert.Equal(t, "bid-1", adapterBids["appnexus"].Bids[0].Bid.ID)
+	assert.Equal(t, "bid-3", adapterBids["rubicon"].Bids[0].Bid.ID)
+}
+
+// rawResponseBidder parses a bidder specific response format, so replaying anything but the raw body fails
+type rawResponseBidder struct {
+	uri string
+}
+
+func (bidder *rawResponseBidder) MakeRequests(request *openrtb2.BidRequest, reqInfo *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
+	return []*adapters.RequestData{{Method: http.MethodPost, Uri: bidder.uri, Body: []byte(`{"imp":[{"id":"imp-1"}]}`)}}, nil
+}
+
+func (bidder *rawResponseBidder) MakeBids(internalRequest *openrtb2.BidRequest, externalRequest *adapters.RequestData, response *adapters.ResponseData) (*adapters.BidderResponse, []error) {
+	var body struct {
+		Ads []struct {
+			Imp   string  `json:"imp"`
+			CPM   float64 `json:"cpm"`
+			Cur   string  `json:"cur"`
+			Image string  `json:"image"`
+		} `json:"ads"`
+	}
+	if err := jsonutil.UnmarshalValid(response.Body, &body); err != nil {
+		return nil, []error{err}
+	}
+	if len(body.Ads) == 0 {
+		return nil, []error{errors.New("no ads")}
+	}
+	bidResponse := adapters.NewBidderResponse()
+	for _, ad := range body.Ads {
+		bidResponse.Currency = ad.Cur
+		bidResponse.Bids = append(bidResponse.Bids, &adapters.TypedBid{
+			Bid:     &openrtb2.Bid{ID: "bid-" + ad.Imp, ImpID: ad.Imp, Price: ad.CPM, AdM: ad.Image},
+			BidType: openrtb_ext.BidTypeBanner,
+		})
+	}
+	return bidResponse, nil
+}
+
+func TestStoredResponseRecordingReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ads":[{"imp":"imp-1","cpm":1,"cur":"EUR","image":"<img>"}]}`))
+	}))
+	defer server.Close()
+
+	bidder := AdaptBidder(&rawResponseBidder{uri: server.URL}, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, nil, "")
+	conversions := currency.NewRates(map[string]map[string]float64{"EUR": {"USD": 1.5}})
+	requestBid := func(imps []openrtb2.Imp, storedBidResponses map[string]json.RawMessage) []*entities.PbsOrtbSeatBid {
+		t.Helper()
+		bidderReq := BidderRequest{
+			BidRequest:            &openrtb2.BidRequest{ID: "request", Imp: imps},
+			BidderName:            openrtb_ext.BidderAppnexus,
+			BidderStoredResponses: storedBidResponses,
+			ImpReplaceImpId:       map[string]bool{"imp-1": true},
+		}
+		seatBids, _, _ := bidder.requestBid(context.Background(), bidderReq, conversions, &adapters.ExtraRequestInfo{}, &adscert.NilSigner{},
+			bidRequestOptions{bidAdjustments: map[string]float64{"appnexus": 2}, headerDebugAllowed: true}, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{}, nil)
+		require.Len(t, seatBids, 1)
+		require.Len(t, seatBids[0].Bids, 1)
+		return seatBids
+	}
+
+	live := requestBid([]openrtb2.Imp{{ID: "imp-1"}}, nil)
+	require.Equal(t, 3.0, live[0].Bids[0].Bid.Price, "the price is adjusted and converted")
+
+	recording := &storedResponseRecording{id: "rec-1"}
+	recording.capture(map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{"appnexus": live[0]})
+	responses, err := recording.storedResponses(&openrtb2.BidResponse{ID: "response", Cur: "USD"})
+	require.NoError(t, err)
+
+	replayed := requestBid(nil, map[string]json.RawMessage{"imp-1": responses["rec-1-imp-1-appnexus"]})
+	assert.Equal(t, live[0].Bids[0].Bid, replayed[0].Bids[0].Bid, "the stored bid response goes through MakeBids, the adjustments and the conversion once")
+	assert.Equal(t, 1.0, replayed[0].Bids[0].OriginalBidCPM)
+	assert.Equal(t, "EUR", replayed[0].Bids[0].OriginalBidCur)
+
+	adapterBids, _, _, err := buildStoredAuctionResponse(stored_responses.ImpsWithBidResponses{"imp-1": responses["rec-1-imp-1"]})
+	require.NoError(t, err)
+	assert.Equal(t, 3.0, adapterBids["appnexus"].Bids[0].Bid.Price, "the stored auction response holds the auction price")
+}
+
+func TestWriteStoredResponseRecording(t *testing.T) {
+	release := make(chan struct{})
+	writer := &fakeStoredResponseWriter{err: errors.New("disk full"), release: release}
+	e := &exchange{storedResponseWriter: writer, recordingTimeout: time.Second}
+	recording := &storedResponseRecording{id: "rec-1"}
+
+	done, err := e.writeStoredResponseRecording(context.Background(), recording, &openrtb2.BidResponse{ID: "response"})
+	require.NoError(t, err, "the write errors are logged, the auction doesn't wait for them")
+
+	select {
+	case <-done:
+		t.Fatal("the write is expected to run in the background")
+	default:
+	}
+	close(release)
+	<-done
+	assert.JSONEq(t, `{"id":"response"}`, string(writer.responses["rec-1"]))
+}
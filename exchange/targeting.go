@@ -2,6 +2,7 @@ package exchange
 
 import (
 	"fmt"
+	"maps"
 	"strconv"
 
 	"github.com/prebid/openrtb/v20/openrtb2"
@@ -67,7 +68,9 @@ func (targData *targetData) setTargeting(auc *auction, env string, categoryMappi
 
 				bidHasDeal := len(topBid.Bid.DealID) > 0
 
-				targets := make(map[string]string, 10)
+				// keys set by hooks are kept unless the exchange sets the same key
+				targets := make(map[string]string, 10+len(topBid.BidTargets))
+				maps.Copy(targets, topBid.BidTargets)
 				if cpm, ok := auc.roundedPrices[topBid]; ok {
 					targData.addKeys(targets, openrtb_ext.PbKey, cpm, targetingBidderCode, isOverallWinner, truncateTargetAttr, bidHasDeal)
 				}
@@ -1208,6 +1208,39 @@ var TargetingTests []TargetingTestData = []TargetingTestData{
 		},
 		TruncateTargetAttr: nil,
 	},
+	{
+		Description: "Targeting keys set by hooks are kept unless overwritten",
+		TargetData: targetData{
+			priceGranularity: lookupPriceGranularity("med"),
+			includeWinners:   true,
+			prefix:           DefaultKeyPrefix,
+		},
+		Auction: auction{
+			allBidsByBidder: map[string]map[openrtb_ext.BidderName][]*entities.PbsOrtbBid{
+				"ImpId-1": {
+					openrtb_ext.BidderAppnexus: {{
+						Bid:        bid123,
+						BidType:    openrtb_ext.BidTypeBanner,
+						BidTargets: map[string]string{"hb_rule": "banner", "hb_bidder": "hook"},
+					}},
+				},
+			},
+		},
+		ExpectedPbsBids: map[string]map[openrtb_ext.BidderName][]ExpectedPbsBid{
+			"ImpId-1": {
+				openrtb_ext.BidderAppnexus: []ExpectedPbsBid{
+					{
+						BidTargets: map[string]string{
+							"hb_bidder": "appnexus",
+							"hb_pb":     "1.20",
+							"hb_rule":   "banner",
+						},
+					},
+				},
+			},
+		},
+		TruncateTargetAttr: nil,
+	},
 }
 
 func TestSetTargeting(t *testing.T) {
@@ -0,0 +1,53 @@
+package exchange
+
+import (
+	"context"
+
+	"github.com/prebid/prebid-server/v3/gdpr"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// tcfDecisionRecorder is a gdpr.Permissions recording how the auction permissions of each bidder were reached.
+type tcfDecisionRecorder struct {
+	gdpr.Permissions
+	decisions map[openrtb_ext.BidderName]gdpr.AuctionDecision
+}
+
+func (r *tcfDecisionRecorder) AuctionActivitiesAllowed(ctx context.Context, bidderCoreName openrtb_ext.BidderName, bidder openrtb_ext.BidderName) gdpr.AuctionPermissions {
+	decision := gdpr.ExplainAuctionActivities(ctx, r.Permissions, bidderCoreName, bidder)
+	r.decisions[bidder] = decision
+	return decision.Permissions
+}
+
+// recordTCFDecisions wraps builder so that the auction permissions decisions of the permissions it builds are
+// recorded into decisions.
+func recordTCFDecisions(builder gdpr.PermissionsBuilder, decisions map[openrtb_ext.BidderName]gdpr.AuctionDecision) gdpr.PermissionsBuilder {
+	return func(tcf2Config gdpr.TCF2ConfigReader, requestInfo gdpr.RequestInfo) gdpr.Permissions {
+		return &tcfDecisionRecorder{
+			Permissions: builder(tcf2Config, requestInfo),
+			decisions:   decisions,
+		}
+	}
+}
+
+// setTCFDecisions adds the TCF enforcement decisions of each bidder within bidResponse.Ext.Prebid.Debug.TCF
+func setTCFDecisions(bidResponseExt *openrtb_ext.ExtBidResponse, decisions map[openrtb_ext.BidderName]gdpr.AuctionDecision) error {
+	if len(decisions) == 0 || bidResponseExt == nil {
+		return nil
+	}
+
+	tcf, err := jsonutil.Marshal(decisions)
+	if err != nil {
+		return err
+	}
+
+	if bidResponseExt.Prebid == nil {
+		bidResponseExt.Prebid = &openrtb_ext.ExtResponsePrebid{}
+	}
+	if bidResponseExt.Prebid.Debug == nil {
+		bidResponseExt.Prebid.Debug = &openrtb_ext.ExtResponsePrebidDebug{}
+	}
+	bidResponseExt.Prebid.Debug.TCF = tcf
+	return nil
+}
@@ -0,0 +1,41 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/gdpr"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordTCFDecisions(t *testing.T) {
+	decisions := make(map[openrtb_ext.BidderName]gdpr.AuctionDecision)
+	builder := func(gdpr.TCF2ConfigReader, gdpr.RequestInfo) gdpr.Permissions {
+		return &permissionsMock{allowedBidders: []openrtb_ext.BidderName{"appnexus"}, passGeo: true}
+	}
+
+	perms := recordTCFDecisions(builder, decisions)(nil, gdpr.RequestInfo{})
+
+	assert.Equal(t, gdpr.AuctionPermissions{AllowBidRequest: true, PassGeo: true}, perms.AuctionActivitiesAllowed(context.Background(), "appnexus", "appnexus"))
+	assert.Equal(t, gdpr.AuctionPermissions{PassGeo: true}, perms.AuctionActivitiesAllowed(context.Background(), "appnexus", "alias"))
+	assert.Equal(t, map[openrtb_ext.BidderName]gdpr.AuctionDecision{
+		"appnexus": {Permissions: gdpr.AuctionPermissions{AllowBidRequest: true, PassGeo: true}},
+		"alias":    {Permissions: gdpr.AuctionPermissions{PassGeo: true}},
+	}, decisions)
+}
+
+func TestSetTCFDecisions(t *testing.T) {
+	bidResponseExt := &openrtb_ext.ExtBidResponse{}
+
+	assert.NoError(t, setTCFDecisions(bidResponseExt, nil))
+	assert.Nil(t, bidResponseExt.Prebid, "no decisions are recorded when debug isn't allowed")
+
+	assert.NoError(t, setTCFDecisions(bidResponseExt, map[openrtb_ext.BidderName]gdpr.AuctionDecision{
+		"appnexus": {Reason: "GDPR does not apply", Permissions: gdpr.AllowAll},
+	}))
+	assert.Equal(t, &openrtb_ext.ExtResponsePrebid{Debug: &openrtb_ext.ExtResponsePrebidDebug{
+		TCF: json.RawMessage(`{"appnexus":{"reason":"GDPR does not apply","vendoringvl":false,"permissions":{"allowbidrequest":true,"passgeo":true,"passid":true}}}`),
+	}}, bidResponseExt.Prebid)
+}
@@ -371,15 +371,18 @@ func (rs *requestSplitter) applyPrivacy(reqWrapper *openrtb_ext.RequestWrapper,
 	buyerUIDRemoved := false
 	if !passIDActivityAllowed {
 		privacy.ScrubUserFPD(reqWrapper)
+		auctionReq.Activities.Scrubbed(privacy.ActivityTransmitUserFPD, scope, privacy.ScrubberUserFPD, privacy.ScrubReasonActivity)
 		buyerUIDRemoved = true
 	} else {
 		if !auctionPermissions.PassID {
 			privacy.ScrubGdprID(reqWrapper)
+			auctionReq.Activities.Scrubbed(privacy.ActivityTransmitUserFPD, scope, privacy.ScrubberGdprID, privacy.ScrubReasonGDPR)
 			buyerUIDRemoved = true
 		}
 
 		if ccpaEnforcer.ShouldEnforce(bidderName) {
 			privacy.ScrubDeviceIDsIPsUserDemoExt(reqWrapper, ipConf, "eids", false)
+			auctionReq.Activities.Scrubbed(privacy.ActivityTransmitUserFPD, scope, privacy.ScrubberDeviceIDsIPsUserDemo, privacy.ScrubReasonCCPA)
 			buyerUIDRemoved = true
 		}
 	}
@@ -390,22 +393,32 @@ func (rs *requestSplitter) applyPrivacy(reqWrapper *openrtb_ext.RequestWrapper,
 	passGeoActivityAllowed := auctionReq.Activities.Allow(privacy.ActivityTransmitPreciseGeo, scope, privacy.NewRequestFromBidRequest(*reqWrapper))
 	if !passGeoActivityAllowed {
 		privacy.ScrubGeoAndDeviceIP(reqWrapper, ipConf)
+		auctionReq.Activities.Scrubbed(privacy.ActivityTransmitPreciseGeo, scope, privacy.ScrubberGeoAndDeviceIP, privacy.ScrubReasonActivity)
 	} else {
 		if !auctionPermissions.PassGeo {
 			privacy.ScrubGeoAndDeviceIP(reqWrapper, ipConf)
+			auctionReq.Activities.Scrubbed(privacy.ActivityTransmitPreciseGeo, scope, privacy.ScrubberGeoAndDeviceIP, privacy.ScrubReasonGDPR)
 		}
 		if ccpaEnforcer.ShouldEnforce(bidderName) {
 			privacy.ScrubDeviceIDsIPsUserDemoExt(reqWrapper, ipConf, "eids", false)
+			auctionReq.Activities.Scrubbed(privacy.ActivityTransmitPreciseGeo, scope, privacy.ScrubberDeviceIDsIPsUserDemo, privacy.ScrubReasonCCPA)
 		}
 	}
 
+	// LMT and COPPA aren't activities, so their scrubbing is recorded against transmitUfpd whose data they scrub.
 	if lmt || coppa {
 		privacy.ScrubDeviceIDsIPsUserDemoExt(reqWrapper, ipConf, "eids", coppa)
+		reason := privacy.ScrubReasonLMT
+		if coppa {
+			reason = privacy.ScrubReasonCOPPA
+		}
+		auctionReq.Activities.Scrubbed(privacy.ActivityTransmitUserFPD, scope, privacy.ScrubberDeviceIDsIPsUserDemo, reason)
 	}
 
 	passTIDAllowed := auctionReq.Activities.Allow(privacy.ActivityTransmitTIDs, scope, privacy.NewRequestFromBidRequest(*reqWrapper))
 	if !passTIDAllowed {
 		privacy.ScrubTID(reqWrapper)
+		auctionReq.Activities.Scrubbed(privacy.ActivityTransmitTIDs, scope, privacy.ScrubberTID, privacy.ScrubReasonActivity)
 	}
 
 	if err := reqWrapper.RebuildRequest(); err != nil {
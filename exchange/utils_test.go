@@ -5313,6 +5313,41 @@ func TestCleanOpenRTBRequestsActivities(t *testing.T) {
 	}
 }
 
+func TestCleanOpenRTBRequestsPrivacyAudit(t *testing.T) {
+	privacyConfig := getTransmitUFPDActivityConfig("appnexus", false)
+	privacyConfig.AllowActivities.TransmitTids = buildDefaultActivityConfig("appnexus", false)
+	log := &privacy.ActivityLog{}
+
+	auctionReq := AuctionRequest{
+		BidRequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: newBidRequest()},
+		UserSyncs:         &emptyUsersync{},
+		Activities:        privacy.NewActivityControl(&privacyConfig).WithLog(log),
+		TCF2Config:        gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{}),
+	}
+
+	metricsMock := metrics.MetricsEngineMock{}
+	metricsMock.Mock.On("RecordAdapterBuyerUIDScrubbed", mock.Anything).Return()
+	reqSplitter := &requestSplitter{
+		bidderToSyncerKey: map[string]string{},
+		me:                &metricsMock,
+		bidderInfo:        config.BidderInfos{"appnexus": config.BidderInfo{OpenRTB: &config.OpenRTBInfo{Version: "2.6"}}},
+	}
+
+	_, _, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, nil, map[string]float64{})
+	assert.Empty(t, errs)
+
+	denied := privacy.ActivityDecision{Allowed: false, RuleIndex: 0, Rule: &privacyConfig.AllowActivities.TransmitTids.Rules[0]}
+	allowed := privacy.ActivityDecision{Allowed: true, RuleIndex: -1}
+	assert.Equal(t, []privacy.ActivityEvaluation{
+		{Activity: "fetchBids", ComponentType: "bidder", ComponentName: "appnexus", ActivityDecision: allowed},
+		{Activity: "transmitUfpd", ComponentType: "bidder", ComponentName: "appnexus", ActivityDecision: denied,
+			Scrubbers: []privacy.Scrubber{{Name: privacy.ScrubberUserFPD, Reason: privacy.ScrubReasonActivity}}},
+		{Activity: "transmitPreciseGeo", ComponentType: "bidder", ComponentName: "appnexus", ActivityDecision: allowed},
+		{Activity: "transmitTid", ComponentType: "bidder", ComponentName: "appnexus", ActivityDecision: denied,
+			Scrubbers: []privacy.Scrubber{{Name: privacy.ScrubberTID, Reason: privacy.ScrubReasonActivity}}},
+	}, log.Evaluations())
+}
+
 func buildDefaultActivityConfig(componentName string, allow bool) config.Activity {
 	return config.Activity{
 		Default: ptrutil.ToPtr(true),
@@ -51,6 +51,7 @@ type PriceFloorFetcher struct {
 	time            timeutil.Time         // time interface to record request timings
 	metricEngine    metrics.MetricsEngine // Records malfunctions in dynamic fetch
 	maxRetries      int                   // Max number of retries for failing URLs
+	running         chan struct{}         // Channel closed once the fetcher accepts fetches
 }
 
 type FetchQueue []*fetchInfo
@@ -109,6 +110,7 @@ func NewPriceFloorFetcher(config config.PriceFloors, httpClient *http.Client, me
 		time:            &timeutil.RealTime{},
 		metricEngine:    metricEngine,
 		maxRetries:      config.Fetcher.MaxRetries,
+		running:         make(chan struct{}),
 	}
 
 	go floorFetcher.Fetcher()
@@ -181,6 +183,11 @@ func (f *PriceFloorFetcher) worker(fetchConfig fetchInfo) {
 	}
 }
 
+// Running is closed once the fetcher accepts floors fetches.
+func (f *PriceFloorFetcher) Running() <-chan struct{} {
+	return f.running
+}
+
 // Stop terminates price floor fetcher
 func (f *PriceFloorFetcher) Stop() {
 	if f == nil {
@@ -205,6 +212,10 @@ func (f *PriceFloorFetcher) Fetcher() {
 	//Create Ticker of 5 minutes
 	ticker := time.NewTicker(time.Duration(refetchCheckInterval) * time.Second)
 
+	if f.running != nil {
+		close(f.running)
+	}
+
 	for {
 		select {
 		case fetchConfig := <-f.configReceiver:
@@ -943,6 +943,26 @@ func TestFetcherEntryNotPresentInCache(t *testing.T) {
 	assert.Equal(t, openrtb_ext.FetchInprogress, status, "Floor fetch should be error")
 }
 
+func TestFetcherRunning(t *testing.T) {
+	floorConfig := config.PriceFloors{
+		Enabled: true,
+		Fetcher: config.PriceFloorFetcher{
+			CacheSize: 1,
+			Worker:    2,
+			Capacity:  5,
+		},
+	}
+
+	fetcherInstance := NewPriceFloorFetcher(floorConfig, http.DefaultClient, &metricsConf.NilMetricsEngine{})
+	defer fetcherInstance.Stop()
+
+	select {
+	case <-fetcherInstance.Running():
+	case <-time.After(time.Second):
+		t.Error("The fetcher should be running")
+	}
+}
+
 func TestFetcherDynamicFetchDisable(t *testing.T) {
 	floorConfig := config.PriceFloors{
 		Enabled: true,
@@ -1,7 +1,9 @@
 package floors
 
 import (
+	"errors"
 	"fmt"
+	"maps"
 	"strings"
 
 	"github.com/prebid/prebid-server/v3/config"
@@ -117,3 +119,36 @@ func selectValidFloorModelGroups(modelGroups []openrtb_ext.PriceFloorModelGroup,
 	}
 	return validModelGroups, errs
 }
+
+// ValidateFloorRules validates floors provided outside of a request or a floors fetch, where invalid model groups
+// and rules are reported instead of being dropped. Account limits on schema dimensions and rules are left to the
+// auction.
+func ValidateFloorRules(floorRules *openrtb_ext.PriceFloorRules) error {
+	if floorRules == nil {
+		return nil
+	}
+
+	if err := validateFloorParams(floorRules); err != nil {
+		return err
+	}
+
+	if floorRules.Data == nil {
+		return nil
+	}
+
+	validModelGroups, errs := selectValidFloorModelGroups(floorRules.Data.ModelGroups, config.Account{})
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	for _, modelGroup := range validModelGroups {
+		delimiter := modelGroup.Schema.Delimiter
+		if delimiter == "" {
+			delimiter = defaultDelimiter
+		}
+		if errs := validateFloorRulesAndLowerValidRuleKey(modelGroup.Schema, delimiter, maps.Clone(modelGroup.Values)); len(errs) > 0 {
+			return errors.Join(errs...)
+		}
+	}
+	return nil
+}
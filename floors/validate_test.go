@@ -428,3 +428,68 @@ func TestValidateSchemaDimensions(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateFloorRules(t *testing.T) {
+	tt := []struct {
+		name       string
+		floorRules *openrtb_ext.PriceFloorRules
+		err        string
+	}{
+		{
+			name: "nil floors",
+		},
+		{
+			name:       "floors without data",
+			floorRules: &openrtb_ext.PriceFloorRules{FloorMin: 1},
+		},
+		{
+			name: "valid floors",
+			floorRules: &openrtb_ext.PriceFloorRules{Data: &openrtb_ext.PriceFloorData{
+				ModelGroups: []openrtb_ext.PriceFloorModelGroup{{
+					Schema: openrtb_ext.PriceFloorSchema{Fields: []string{"mediaType", "size"}},
+					Values: map[string]float64{"banner|300x250": 1.01, "*|*": 0.5},
+				}},
+			}},
+		},
+		{
+			name:       "invalid floor params",
+			floorRules: &openrtb_ext.PriceFloorRules{FloorMin: -1},
+			err:        "Invalid FloorMin = '-1', value should be >= 0",
+		},
+		{
+			name:       "no model groups",
+			floorRules: &openrtb_ext.PriceFloorRules{Data: &openrtb_ext.PriceFloorData{}},
+			err:        "No model group present in floors.data",
+		},
+		{
+			name: "invalid schema dimension",
+			floorRules: &openrtb_ext.PriceFloorRules{Data: &openrtb_ext.PriceFloorData{
+				ModelGroups: []openrtb_ext.PriceFloorModelGroup{{
+					Schema: openrtb_ext.PriceFloorSchema{Fields: []string{"unknown"}},
+				}},
+			}},
+			err: "Invalid schema dimension provided = 'unknown' in Schema Fields = '[unknown]'",
+		},
+		{
+			name: "rule not matching the schema",
+			floorRules: &openrtb_ext.PriceFloorRules{Data: &openrtb_ext.PriceFloorData{
+				ModelGroups: []openrtb_ext.PriceFloorModelGroup{{
+					Schema: openrtb_ext.PriceFloorSchema{Fields: []string{"mediaType", "size"}, Delimiter: "|"},
+					Values: map[string]float64{"banner": 1.01},
+				}},
+			}},
+			err: "Invalid Floor Rule = 'banner' for Schema Fields = '[mediaType size]'",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateFloorRules(tc.floorRules)
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package frequencycap
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/util/timeutil"
+)
+
+// Service enforces the account frequency caps. Bids served to a user are remembered until their impression
+// event arrives, which is when the impression is counted against the caps.
+type Service struct {
+	store     Store
+	clock     timeutil.Time
+	servedTTL time.Duration
+}
+
+// NewService returns a Service which counts impression events arriving up to servedTTL after the auction.
+func NewService(store Store, clock timeutil.Time, servedTTL time.Duration) *Service {
+	return &Service{
+		store:     store,
+		clock:     clock,
+		servedTTL: servedTTL,
+	}
+}
+
+// Capped returns true if the user has already seen the advertiser domain or deal of the bid as often as one of
+// the caps of the account allows. Users who can't be recognized are never capped.
+func (s *Service) Capped(ctx context.Context, accountID string, caps []config.AccountFrequencyCap, userID string, bid *openrtb2.Bid) (bool, error) {
+	if userID == "" {
+		return false, nil
+	}
+
+	now := s.clock.Now()
+	for _, frequencyCap := range caps {
+		if !valid(frequencyCap) {
+			continue
+		}
+		since := now.Add(-period(frequencyCap))
+		for _, key := range capKeys(frequencyCap, bid) {
+			count, err := s.store.Count(ctx, accountID, userID, key, since)
+			if err != nil {
+				return false, err
+			}
+			if count >= frequencyCap.Count {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// RecordServed remembers a bid delivered to the user, so its impression event can be counted against the caps.
+func (s *Service) RecordServed(ctx context.Context, accountID, bidID, userID string, caps []config.AccountFrequencyCap, bid *openrtb2.Bid) error {
+	if userID == "" {
+		return nil
+	}
+
+	var (
+		keys      []string
+		retention time.Duration
+	)
+	for _, frequencyCap := range caps {
+		if !valid(frequencyCap) {
+			continue
+		}
+		for _, key := range capKeys(frequencyCap, bid) {
+			if !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+			retention = max(retention, period(frequencyCap))
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	served := Served{UserID: userID, Keys: keys, Retention: retention}
+	return s.store.SaveServed(ctx, accountID, bidID, served, s.clock.Now().Add(s.servedTTL))
+}
+
+// RecordImpression counts the impression of a served bid for each of its keys, against the user the caps were
+// read for in the auction. The user id of the impression event is only used if none was recorded. Impressions
+// of unknown bids are ignored.
+func (s *Service) RecordImpression(ctx context.Context, accountID, bidID, userID string) error {
+	served, ok, err := s.store.TakeServed(ctx, accountID, bidID)
+	if err != nil || !ok {
+		return err
+	}
+	if served.UserID != "" {
+		userID = served.UserID
+	}
+	if userID == "" {
+		return nil
+	}
+
+	now := s.clock.Now()
+	for _, key := range served.Keys {
+		if err := s.store.Increment(ctx, accountID, userID, key, now, served.Retention); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// capKeys returns the counter keys of the bid which the cap applies to.
+func capKeys(frequencyCap config.AccountFrequencyCap, bid *openrtb2.Bid) []string {
+	var keys []string
+	switch frequencyCap.Type {
+	case config.FrequencyCapTypeADomain:
+		for _, adomain := range bid.ADomain {
+			if appliesTo(frequencyCap, adomain) {
+				keys = append(keys, config.FrequencyCapTypeADomain+":"+strings.ToLower(adomain))
+			}
+		}
+	case config.FrequencyCapTypeDeal:
+		if bid.DealID != "" && appliesTo(frequencyCap, bid.DealID) {
+			keys = append(keys, config.FrequencyCapTypeDeal+":"+bid.DealID)
+		}
+	}
+	return keys
+}
+
+func appliesTo(frequencyCap config.AccountFrequencyCap, value string) bool {
+	if len(frequencyCap.Values) == 0 {
+		return true
+	}
+	for _, v := range frequencyCap.Values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func valid(frequencyCap config.AccountFrequencyCap) bool {
+	return frequencyCap.Count > 0 && frequencyCap.PeriodSeconds > 0
+}
+
+func period(frequencyCap config.AccountFrequencyCap) time.Duration {
+	return time.Duration(frequencyCap.PeriodSeconds) * time.Second
+}
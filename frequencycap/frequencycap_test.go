@@ -0,0 +1,163 @@
+package frequencycap
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapKeys(t *testing.T) {
+	bid := &openrtb2.Bid{ADomain: []string{"A.com", "b.com"}, DealID: "deal"}
+
+	testCases := []struct {
+		name         string
+		frequencyCap config.AccountFrequencyCap
+		expectedKeys []string
+	}{
+		{
+			name:         "adomain",
+			frequencyCap: config.AccountFrequencyCap{Type: config.FrequencyCapTypeADomain},
+			expectedKeys: []string{"adomain:a.com", "adomain:b.com"},
+		},
+		{
+			name:         "adomain-values",
+			frequencyCap: config.AccountFrequencyCap{Type: config.FrequencyCapTypeADomain, Values: []string{"b.COM"}},
+			expectedKeys: []string{"adomain:b.com"},
+		},
+		{
+			name:         "deal",
+			frequencyCap: config.AccountFrequencyCap{Type: config.FrequencyCapTypeDeal},
+			expectedKeys: []string{"deal:deal"},
+		},
+		{
+			name:         "deal-values",
+			frequencyCap: config.AccountFrequencyCap{Type: config.FrequencyCapTypeDeal, Values: []string{"other"}},
+		},
+		{
+			name:         "unknown-type",
+			frequencyCap: config.AccountFrequencyCap{Type: "seat"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expectedKeys, capKeys(test.frequencyCap, bid))
+		})
+	}
+}
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeTime{time: testStart}
+	service := NewService(NewMemoryStore(clock), clock, time.Minute)
+	caps := []config.AccountFrequencyCap{
+		{Type: config.FrequencyCapTypeADomain, Count: 2, PeriodSeconds: 3600},
+		{Type: config.FrequencyCapTypeDeal, Count: 1, PeriodSeconds: 60},
+		{Type: config.FrequencyCapTypeDeal, Count: 0, PeriodSeconds: 60},
+	}
+	bid := &openrtb2.Bid{ADomain: []string{"a.com"}}
+	dealBid := &openrtb2.Bid{ADomain: []string{"b.com"}, DealID: "deal"}
+
+	serve := func(bidID string, bid *openrtb2.Bid) {
+		require.NoError(t, service.RecordServed(ctx, "account", bidID, "user", caps, bid))
+		require.NoError(t, service.RecordImpression(ctx, "account", bidID, ""))
+	}
+	capped := func(userID string, bid *openrtb2.Bid) bool {
+		capped, err := service.Capped(ctx, "account", caps, userID, bid)
+		require.NoError(t, err)
+		return capped
+	}
+
+	serve("bid-1", bid)
+	assert.False(t, capped("user", bid))
+	serve("bid-2", bid)
+	assert.True(t, capped("user", bid))
+	assert.False(t, capped("other-user", bid))
+	assert.False(t, capped("", bid), "unknown users are never capped")
+
+	serve("bid-3", dealBid)
+	assert.True(t, capped("user", dealBid))
+
+	clock.time = testStart.Add(2 * time.Minute)
+	assert.False(t, capped("user", dealBid), "the deal cap period passed")
+	assert.True(t, capped("user", bid))
+
+	clock.time = testStart.Add(2 * time.Hour)
+	assert.False(t, capped("user", bid), "the adomain cap period passed")
+}
+
+func TestRecordImpressionCountsAgainstAuctionUser(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeTime{time: testStart}
+	service := NewService(NewMemoryStore(clock), clock, time.Minute)
+	caps := []config.AccountFrequencyCap{{Type: config.FrequencyCapTypeADomain, Count: 1, PeriodSeconds: 3600}}
+	bid := &openrtb2.Bid{ADomain: []string{"a.com"}}
+
+	require.NoError(t, service.RecordServed(ctx, "account", "bid", "auction-user", caps, bid))
+	require.NoError(t, service.RecordImpression(ctx, "account", "bid", "event-user"))
+
+	capped, err := service.Capped(ctx, "account", caps, "auction-user", bid)
+	assert.NoError(t, err)
+	assert.True(t, capped)
+	capped, err = service.Capped(ctx, "account", caps, "event-user", bid)
+	assert.NoError(t, err)
+	assert.False(t, capped)
+}
+
+func TestRecordImpressionCountsPerAccount(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeTime{time: testStart}
+	service := NewService(NewMemoryStore(clock), clock, time.Minute)
+	caps := []config.AccountFrequencyCap{{Type: config.FrequencyCapTypeADomain, Count: 1, PeriodSeconds: 3600}}
+	bid := &openrtb2.Bid{ADomain: []string{"a.com"}}
+
+	require.NoError(t, service.RecordServed(ctx, "account", "bid", "user", caps, bid))
+	require.NoError(t, service.RecordImpression(ctx, "account", "bid", ""))
+
+	capped, err := service.Capped(ctx, "account", caps, "user", bid)
+	assert.NoError(t, err)
+	assert.True(t, capped)
+	capped, err = service.Capped(ctx, "other-account", caps, "user", bid)
+	assert.NoError(t, err)
+	assert.False(t, capped, "the impressions of an account don't count against the caps of another")
+}
+
+func TestRecordImpressionAfterServedTTL(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeTime{time: testStart}
+	service := NewService(NewMemoryStore(clock), clock, time.Minute)
+	caps := []config.AccountFrequencyCap{{Type: config.FrequencyCapTypeADomain, Count: 1, PeriodSeconds: 3600}}
+	bid := &openrtb2.Bid{ADomain: []string{"a.com"}}
+
+	require.NoError(t, service.RecordServed(ctx, "account", "bid", "user", caps, bid))
+	clock.time = testStart.Add(time.Minute)
+	require.NoError(t, service.RecordImpression(ctx, "account", "bid", ""))
+
+	capped, err := service.Capped(ctx, "account", caps, "user", bid)
+	assert.NoError(t, err)
+	assert.False(t, capped)
+}
+
+type failingStore struct {
+	Store
+}
+
+func (failingStore) Count(_ context.Context, _, _, _ string, _ time.Time) (int, error) {
+	return 0, errors.New("store unavailable")
+}
+
+func TestCappedStoreError(t *testing.T) {
+	service := NewService(failingStore{}, &fakeTime{time: testStart}, time.Minute)
+	caps := []config.AccountFrequencyCap{{Type: config.FrequencyCapTypeADomain, Count: 1, PeriodSeconds: 3600}}
+
+	capped, err := service.Capped(context.Background(), "account", caps, "user", &openrtb2.Bid{ADomain: []string{"a.com"}})
+
+	assert.EqualError(t, err, "store unavailable")
+	assert.False(t, capped)
+}
@@ -0,0 +1,145 @@
+package frequencycap
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/util/timeutil"
+)
+
+// Served is a bid delivered to a user whose impression event has not arrived yet.
+type Served struct {
+	UserID string
+	// Keys identify the advertiser domains and deal of the bid which are subject to frequency caps
+	Keys []string
+	// Retention is how long an impression of the bid is relevant to the account frequency caps
+	Retention time.Duration
+}
+
+// Store keeps the impression counters per account, user and key as well as the bids served to users until their
+// impression event arrives. Implementations must be safe for concurrent use.
+type Store interface {
+	// Increment counts an impression of key for the user of the account. The impression can be forgotten once
+	// retention passed.
+	Increment(ctx context.Context, accountID, userID, key string, at time.Time, retention time.Duration) error
+	// Count returns the number of impressions of key for the user of the account since the given time.
+	Count(ctx context.Context, accountID, userID, key string, since time.Time) (int, error)
+	// SaveServed remembers a served bid until expiry.
+	SaveServed(ctx context.Context, accountID, bidID string, served Served, expiry time.Time) error
+	// TakeServed returns a served bid and forgets it, so each impression is counted once.
+	TakeServed(ctx context.Context, accountID, bidID string) (Served, bool, error)
+}
+
+// sweepInterval is how often the memory store drops expired entries
+const sweepInterval = time.Minute
+
+// NewMemoryStore returns a Store which keeps everything in memory. Counters are neither shared between Prebid
+// Server instances nor kept across restarts.
+func NewMemoryStore(clock timeutil.Time) Store {
+	return &memoryStore{
+		clock:       clock,
+		impressions: make(map[counterKey][]time.Time),
+		expiries:    make(map[counterKey]time.Time),
+		served:      make(map[servedKey]servedEntry),
+	}
+}
+
+type counterKey struct {
+	accountID string
+	userID    string
+	key       string
+}
+
+type servedKey struct {
+	accountID string
+	bidID     string
+}
+
+type servedEntry struct {
+	served Served
+	expiry time.Time
+}
+
+type memoryStore struct {
+	clock timeutil.Time
+	mu    sync.Mutex
+	// impressions holds the impression times per account, user and key in ascending order
+	impressions map[counterKey][]time.Time
+	expiries    map[counterKey]time.Time
+	served      map[servedKey]servedEntry
+	lastSweep   time.Time
+}
+
+func (s *memoryStore) Increment(_ context.Context, accountID, userID, key string, at time.Time, retention time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := counterKey{accountID: accountID, userID: userID, key: key}
+	s.impressions[k] = append(pruneBefore(s.impressions[k], at.Add(-retention)), at)
+	if expiry := at.Add(retention); expiry.After(s.expiries[k]) {
+		s.expiries[k] = expiry
+	}
+	s.sweep()
+	return nil
+}
+
+func (s *memoryStore) Count(_ context.Context, accountID, userID, key string, since time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(pruneBefore(s.impressions[counterKey{accountID: accountID, userID: userID, key: key}], since)), nil
+}
+
+func (s *memoryStore) SaveServed(_ context.Context, accountID, bidID string, served Served, expiry time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.served[servedKey{accountID: accountID, bidID: bidID}] = servedEntry{served: served, expiry: expiry}
+	s.sweep()
+	return nil
+}
+
+func (s *memoryStore) TakeServed(_ context.Context, accountID, bidID string) (Served, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := servedKey{accountID: accountID, bidID: bidID}
+	entry, ok := s.served[k]
+	if !ok {
+		return Served{}, false, nil
+	}
+	delete(s.served, k)
+	if !s.clock.Now().Before(entry.expiry) {
+		return Served{}, false, nil
+	}
+	return entry.served, true, nil
+}
+
+// sweep drops the counters and served bids which expired, at most once per sweepInterval.
+func (s *memoryStore) sweep() {
+	now := s.clock.Now()
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for k, expiry := range s.expiries {
+		if !now.Before(expiry) {
+			delete(s.expiries, k)
+			delete(s.impressions, k)
+		}
+	}
+	for k, entry := range s.served {
+		if !now.Before(entry.expiry) {
+			delete(s.served, k)
+		}
+	}
+}
+
+// pruneBefore drops the times before since from a slice in ascending order.
+func pruneBefore(times []time.Time, since time.Time) []time.Time {
+	i := sort.Search(len(times), func(i int) bool { return !times[i].Before(since) })
+	return times[i:]
+}
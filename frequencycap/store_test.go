@@ -0,0 +1,94 @@
+package frequencycap
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTime struct {
+	time time.Time
+}
+
+func (ft *fakeTime) Now() time.Time {
+	return ft.time
+}
+
+var testStart = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func TestMemoryStoreCount(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(&fakeTime{time: testStart})
+
+	require.NoError(t, store.Increment(ctx, "account", "user", "adomain:a.com", testStart, time.Hour))
+	require.NoError(t, store.Increment(ctx, "account", "user", "adomain:a.com", testStart.Add(30*time.Minute), time.Hour))
+	require.NoError(t, store.Increment(ctx, "account", "other-user", "adomain:a.com", testStart, time.Hour))
+	require.NoError(t, store.Increment(ctx, "other-account", "user", "adomain:a.com", testStart, time.Hour))
+
+	testCases := []struct {
+		name          string
+		accountID     string
+		userID        string
+		key           string
+		since         time.Time
+		expectedCount int
+	}{
+		{name: "all", accountID: "account", userID: "user", key: "adomain:a.com", since: testStart, expectedCount: 2},
+		{name: "since", accountID: "account", userID: "user", key: "adomain:a.com", since: testStart.Add(time.Minute), expectedCount: 1},
+		{name: "other-user", accountID: "account", userID: "other-user", key: "adomain:a.com", since: testStart, expectedCount: 1},
+		{name: "other-key", accountID: "account", userID: "user", key: "deal:d", since: testStart, expectedCount: 0},
+		{name: "other-account", accountID: "other-account", userID: "user", key: "adomain:a.com", since: testStart, expectedCount: 1},
+		{name: "unknown-account", accountID: "unknown-account", userID: "user", key: "adomain:a.com", since: testStart, expectedCount: 0},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			count, err := store.Count(ctx, test.accountID, test.userID, test.key, test.since)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedCount, count)
+		})
+	}
+}
+
+func TestMemoryStoreServed(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeTime{time: testStart}
+	store := NewMemoryStore(clock)
+	served := Served{UserID: "user", Keys: []string{"deal:d"}, Retention: time.Hour}
+
+	require.NoError(t, store.SaveServed(ctx, "account", "bid-1", served, testStart.Add(time.Minute)))
+	require.NoError(t, store.SaveServed(ctx, "account", "bid-2", served, testStart.Add(time.Minute)))
+
+	taken, ok, err := store.TakeServed(ctx, "account", "bid-1")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, served, taken)
+
+	_, ok, _ = store.TakeServed(ctx, "account", "bid-1")
+	assert.False(t, ok, "served bids are taken once")
+
+	_, ok, _ = store.TakeServed(ctx, "other-account", "bid-2")
+	assert.False(t, ok)
+
+	clock.time = testStart.Add(time.Minute)
+	_, ok, _ = store.TakeServed(ctx, "account", "bid-2")
+	assert.False(t, ok, "expired served bids are ignored")
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeTime{time: testStart}
+	store := NewMemoryStore(clock).(*memoryStore)
+
+	require.NoError(t, store.Increment(ctx, "account", "user", "deal:d", testStart, time.Minute))
+	require.NoError(t, store.SaveServed(ctx, "account", "bid", Served{}, testStart.Add(time.Minute)))
+
+	clock.time = testStart.Add(2 * time.Minute)
+	require.NoError(t, store.Increment(ctx, "account", "user", "deal:other", clock.time, time.Hour))
+
+	assert.Equal(t, map[counterKey][]time.Time{{accountID: "account", userID: "user", key: "deal:other"}: {clock.time}}, store.impressions)
+	assert.Empty(t, store.served)
+}
@@ -2,6 +2,7 @@ package gdpr
 
 import (
 	tcf2 "github.com/prebid/go-gdpr/vendorconsent/tcf2"
+	"github.com/prebid/prebid-server/v3/config"
 )
 
 // BasicEnforcement determines if legal basis is satisfied for a given purpose and bidder/analytics adapter using
@@ -16,30 +17,45 @@ type BasicEnforcement struct {
 // LegalBasis determines if legal basis is satisfied for a given purpose and bidder/analytics adapter based on user consent
 // and legal basis signals.
 func (be *BasicEnforcement) LegalBasis(vendorInfo VendorInfo, name string, consent tcf2.ConsentMetadata, overrides Overrides) bool {
-	enforcePurpose, enforceVendors := be.applyEnforceOverrides(overrides)
+	return be.explainLegalBasis(vendorInfo, name, consent, overrides).LegalBasis
+}
 
-	if !enforcePurpose && !enforceVendors {
-		return true
-	}
-	if be.cfg.vendorException(name) && !overrides.blockVendorExceptions {
-		return true
-	}
-	if !enforcePurpose && be.cfg.basicEnforcementVendor(name) {
-		return true
-	}
-	if enforcePurpose && consent.PurposeAllowed(be.cfg.PurposeID) && be.cfg.basicEnforcementVendor(name) {
-		return true
-	}
-	if enforcePurpose && consent.PurposeLITransparency(be.cfg.PurposeID) && overrides.allowLITransparency {
-		return true
-	}
-	if enforcePurpose && !consent.PurposeAllowed(be.cfg.PurposeID) {
-		return false
+// explainLegalBasis is LegalBasis explaining how the decision was reached.
+func (be *BasicEnforcement) explainLegalBasis(vendorInfo VendorInfo, name string, consent tcf2.ConsentMetadata, overrides Overrides) PurposeDecision {
+	enforcePurpose, enforceVendors := be.applyEnforceOverrides(overrides)
+	decision := PurposeDecision{
+		Purpose:         int(be.cfg.PurposeID),
+		EnforceAlgo:     config.TCF2EnforceAlgoBasic,
+		Downgraded:      be.cfg.EnforceAlgo == config.TCF2FullEnforcement,
+		EnforcePurpose:  enforcePurpose,
+		EnforceVendors:  enforceVendors,
+		VendorException: be.cfg.vendorException(name) && !overrides.blockVendorExceptions,
+		LegalBasis:      true,
 	}
-	if !enforceVendors {
-		return true
+
+	switch {
+	case !enforcePurpose && !enforceVendors:
+		decision.Reason = "neither the purpose nor vendors are enforced"
+	case decision.VendorException:
+		decision.Reason = "vendor exception"
+	case !enforcePurpose && be.cfg.basicEnforcementVendor(name):
+		decision.Reason = "basic enforcement vendor and the purpose is not enforced"
+	case enforcePurpose && consent.PurposeAllowed(be.cfg.PurposeID) && be.cfg.basicEnforcementVendor(name):
+		decision.Reason = "user consented to the purpose and basic enforcement vendor"
+	case enforcePurpose && consent.PurposeLITransparency(be.cfg.PurposeID) && overrides.allowLITransparency:
+		decision.Reason = "user was given legitimate interest transparency for the purpose"
+	case enforcePurpose && !consent.PurposeAllowed(be.cfg.PurposeID):
+		decision.LegalBasis = false
+		decision.Reason = "user did not consent to the purpose"
+	case !enforceVendors:
+		decision.Reason = "user consented to the purpose and vendors are not enforced"
+	case consent.VendorConsent(vendorInfo.vendorID):
+		decision.Reason = "user consented to the vendor"
+	default:
+		decision.LegalBasis = false
+		decision.Reason = "user did not consent to the vendor"
 	}
-	return consent.VendorConsent(vendorInfo.vendorID)
+	return decision
 }
 
 // applyEnforceOverrides returns the enforce purpose and enforce vendor configuration values unless
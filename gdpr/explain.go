@@ -0,0 +1,88 @@
+package gdpr
+
+import (
+	"context"
+
+	tcf2 "github.com/prebid/go-gdpr/vendorconsent/tcf2"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+)
+
+// AuctionDecision explains how the AuctionPermissions of a bidder were reached.
+type AuctionDecision struct {
+	// Reason is set when the permissions didn't depend on the legal basis of the bidder's vendor, such as
+	// when the consent string is missing and the default permissions apply.
+	Reason         string             `json:"reason,omitempty"`
+	VendorID       uint16             `json:"vendorid,omitempty"`
+	VendorIDSource string             `json:"vendoridsource,omitempty"`
+	VendorInGVL    bool               `json:"vendoringvl"`
+	Permissions    AuctionPermissions `json:"permissions"`
+	// BidRequest is the purpose 2 legal basis deciding AllowBidRequest.
+	BidRequest *PurposeDecision `json:"bidrequest,omitempty"`
+	// Geo is the special feature 1 decision deciding PassGeo.
+	Geo *FeatureDecision `json:"geo,omitempty"`
+	// ID are the legal bases of purposes 2 through 10 evaluated, in order, until one allowed PassID.
+	ID []PurposeDecision `json:"id,omitempty"`
+}
+
+// Sources of the vendor ID of a bidder.
+const (
+	VendorIDSourceRequest    = "request"
+	VendorIDSourceBidderInfo = "bidderinfo"
+	VendorIDSourceUnknown    = "unknown"
+)
+
+// PurposeDecision explains the legal basis decision of a purpose enforcer.
+type PurposeDecision struct {
+	Purpose     int    `json:"purpose"`
+	EnforceAlgo string `json:"enforcealgo"`
+	// Downgraded is set when the purpose is configured for full enforcement but the bidder is a basic
+	// enforcement vendor.
+	Downgraded      bool   `json:"downgraded,omitempty"`
+	EnforcePurpose  bool   `json:"enforcepurpose"`
+	EnforceVendors  bool   `json:"enforcevendors"`
+	VendorException bool   `json:"vendorexception"`
+	LegalBasis      bool   `json:"legalbasis"`
+	Reason          string `json:"reason"`
+	// ConsentReason and LegitimateInterestReason explain the consent and legitimate interest checks of
+	// full enforcement, when they were made.
+	ConsentReason            string `json:"consentreason,omitempty"`
+	LegitimateInterestReason string `json:"legitimateinterestreason,omitempty"`
+}
+
+// FeatureDecision explains the special feature 1 decision allowing geo to be passed to a bidder.
+type FeatureDecision struct {
+	Enforced               bool   `json:"enforced"`
+	VendorException        bool   `json:"vendorexception"`
+	BasicEnforcementVendor bool   `json:"basicenforcementvendor"`
+	UserOptIn              bool   `json:"useroptin"`
+	VendorDeclared         bool   `json:"vendordeclared"`
+	Reason                 string `json:"reason"`
+}
+
+// auctionActivitiesExplainer is implemented by the Permissions able to explain their AuctionPermissions.
+type auctionActivitiesExplainer interface {
+	explainAuctionActivities(ctx context.Context, bidderCoreName openrtb_ext.BidderName, bidder openrtb_ext.BidderName) AuctionDecision
+}
+
+// ExplainAuctionActivities returns the AuctionActivitiesAllowed of perms for a given bidder along with how
+// they were reached. Only the permissions are set if perms can't explain them.
+func ExplainAuctionActivities(ctx context.Context, perms Permissions, bidderCoreName openrtb_ext.BidderName, bidder openrtb_ext.BidderName) AuctionDecision {
+	if explainer, ok := perms.(auctionActivitiesExplainer); ok {
+		return explainer.explainAuctionActivities(ctx, bidderCoreName, bidder)
+	}
+	return AuctionDecision{Permissions: perms.AuctionActivitiesAllowed(ctx, bidderCoreName, bidder)}
+}
+
+// legalBasisExplainer is implemented by the PurposeEnforcers able to explain their legal basis decision.
+type legalBasisExplainer interface {
+	explainLegalBasis(vendorInfo VendorInfo, name string, consent tcf2.ConsentMetadata, overrides Overrides) PurposeDecision
+}
+
+// explainLegalBasis returns the LegalBasis of enforcer along with how it was reached, when enforcer can
+// explain it.
+func explainLegalBasis(enforcer PurposeEnforcer, vendorInfo VendorInfo, name string, consent tcf2.ConsentMetadata, overrides Overrides) PurposeDecision {
+	if explainer, ok := enforcer.(legalBasisExplainer); ok {
+		return explainer.explainLegalBasis(vendorInfo, name, consent, overrides)
+	}
+	return PurposeDecision{LegalBasis: enforcer.LegalBasis(vendorInfo, name, consent, overrides)}
+}
@@ -0,0 +1,161 @@
+package gdpr
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prebid/go-gdpr/consentconstants"
+	"github.com/prebid/go-gdpr/vendorlist"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplainAuctionActivities(t *testing.T) {
+	purpose2Consent := "CPuDXznPuDXznMOAAAENCZCAAEAAAAAAAAAAAAAAAAAA"
+
+	tests := []struct {
+		description             string
+		consent                 string
+		gdprSignal              Signal
+		bidder                  openrtb_ext.BidderName
+		aliasGVLIDs             map[string]uint16
+		basicEnforcementVendors map[string]struct{}
+		assertDecision          func(t *testing.T, decision AuctionDecision)
+	}{
+		{
+			description: "gdpr-does-not-apply",
+			consent:     purpose2Consent,
+			gdprSignal:  SignalNo,
+			bidder:      openrtb_ext.BidderAppnexus,
+			assertDecision: func(t *testing.T, decision AuctionDecision) {
+				assert.Equal(t, AuctionDecision{Reason: "GDPR does not apply", Permissions: AllowAll}, decision)
+			},
+		},
+		{
+			description: "consent-missing",
+			gdprSignal:  SignalYes,
+			bidder:      openrtb_ext.BidderAppnexus,
+			assertDecision: func(t *testing.T, decision AuctionDecision) {
+				assert.Equal(t, "consent string is missing, the default permissions apply", decision.Reason)
+				assert.Nil(t, decision.BidRequest)
+			},
+		},
+		{
+			description: "consent-malformed",
+			consent:     "malformed",
+			gdprSignal:  SignalYes,
+			bidder:      openrtb_ext.BidderAppnexus,
+			assertDecision: func(t *testing.T, decision AuctionDecision) {
+				assert.Contains(t, decision.Reason, "malformed consent string malformed")
+			},
+		},
+		{
+			description: "full-enforcement",
+			consent:     purpose2Consent,
+			gdprSignal:  SignalYes,
+			bidder:      openrtb_ext.BidderAppnexus,
+			assertDecision: func(t *testing.T, decision AuctionDecision) {
+				assert.Empty(t, decision.Reason)
+				assert.Equal(t, uint16(32), decision.VendorID)
+				assert.Equal(t, VendorIDSourceBidderInfo, decision.VendorIDSource)
+				assert.True(t, decision.VendorInGVL)
+				assert.Equal(t, &PurposeDecision{
+					Purpose:                  2,
+					EnforceAlgo:              config.TCF2EnforceAlgoFull,
+					EnforcePurpose:           true,
+					EnforceVendors:           true,
+					Reason:                   "neither consent nor legitimate interest established",
+					ConsentReason:            "user did not consent to the vendor",
+					LegitimateInterestReason: "vendor does not declare legitimate interest for the purpose",
+				}, decision.BidRequest)
+				assert.Len(t, decision.ID, 9, "every purpose is evaluated when none allows passing IDs")
+				require.NotNil(t, decision.Geo)
+			},
+		},
+		{
+			description:             "basic-enforcement-vendor",
+			consent:                 purpose2Consent,
+			gdprSignal:              SignalYes,
+			bidder:                  openrtb_ext.BidderAppnexus,
+			basicEnforcementVendors: map[string]struct{}{string(openrtb_ext.BidderAppnexus): {}},
+			assertDecision: func(t *testing.T, decision AuctionDecision) {
+				assert.Equal(t, &PurposeDecision{
+					Purpose:        2,
+					EnforceAlgo:    config.TCF2EnforceAlgoBasic,
+					Downgraded:     true,
+					EnforcePurpose: true,
+					EnforceVendors: true,
+					LegalBasis:     true,
+					Reason:         "user consented to the purpose and basic enforcement vendor",
+				}, decision.BidRequest)
+				assert.Len(t, decision.ID, 1, "purposes after the first one allowing to pass IDs aren't evaluated")
+			},
+		},
+		{
+			description: "request-alias",
+			consent:     purpose2Consent,
+			gdprSignal:  SignalYes,
+			bidder:      openrtb_ext.BidderName("alias"),
+			aliasGVLIDs: map[string]uint16{"alias": 6},
+			assertDecision: func(t *testing.T, decision AuctionDecision) {
+				assert.Equal(t, uint16(6), decision.VendorID)
+				assert.Equal(t, VendorIDSourceRequest, decision.VendorIDSource)
+				assert.False(t, decision.VendorInGVL)
+				assert.Equal(t, "vendor is not in the GVL", decision.BidRequest.ConsentReason)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			tcf2AggConfig := allPurposesEnabledTCF2Config()
+			tcf2AggConfig.AccountConfig.BasicEnforcementVendorsMap = tt.basicEnforcementVendors
+			tcf2AggConfig.HostConfig.PurposeConfigs[consentconstants.Purpose(2)] = &tcf2AggConfig.HostConfig.Purpose2
+
+			perms := &permissionsImpl{
+				aliasGVLIDs:           tt.aliasGVLIDs,
+				cfg:                   &tcf2AggConfig,
+				consent:               tt.consent,
+				gdprSignal:            tt.gdprSignal,
+				nonStandardPublishers: map[string]struct{}{},
+				vendorIDs:             map[openrtb_ext.BidderName]uint16{openrtb_ext.BidderAppnexus: 32},
+				fetchVendorList: listFetcher(map[uint16]map[uint16]vendorlist.VendorList{
+					2: {
+						153: parseVendorListDataV2(t, MarshalVendorList(vendorList{
+							GVLSpecificationVersion: 2,
+							VendorListVersion:       153,
+							Vendors:                 map[string]*vendor{"32": {ID: 32, Purposes: []int{2}}},
+						})),
+					},
+				}),
+				purposeEnforcerBuilder: NewPurposeEnforcerBuilder(&tcf2AggConfig),
+			}
+
+			decision := ExplainAuctionActivities(context.Background(), perms, openrtb_ext.BidderAppnexus, tt.bidder)
+
+			assert.Equal(t, perms.AuctionActivitiesAllowed(context.Background(), openrtb_ext.BidderAppnexus, tt.bidder), decision.Permissions, "explaining doesn't change the permissions")
+			tt.assertDecision(t, decision)
+		})
+	}
+}
+
+type unexplainedPermissions struct{}
+
+func (p unexplainedPermissions) HostCookiesAllowed(ctx context.Context) (bool, error) {
+	return true, nil
+}
+
+func (p unexplainedPermissions) BidderSyncAllowed(ctx context.Context, bidder openrtb_ext.BidderName) (bool, error) {
+	return true, nil
+}
+
+func (p unexplainedPermissions) AuctionActivitiesAllowed(ctx context.Context, bidderCoreName openrtb_ext.BidderName, bidder openrtb_ext.BidderName) AuctionPermissions {
+	return AllowBidRequestOnly
+}
+
+func TestExplainAuctionActivitiesUnexplained(t *testing.T) {
+	assert.Equal(t, AuctionDecision{Reason: "GDPR is not enforced", Permissions: AllowAll}, ExplainAuctionActivities(context.Background(), &AlwaysAllow{}, "appnexus", "appnexus"))
+	assert.Equal(t, AuctionDecision{Permissions: AllowBidRequestOnly}, ExplainAuctionActivities(context.Background(), unexplainedPermissions{}, "appnexus", "appnexus"))
+}
@@ -2,6 +2,7 @@ package gdpr
 
 import (
 	tcf2 "github.com/prebid/go-gdpr/vendorconsent/tcf2"
+	"github.com/prebid/prebid-server/v3/config"
 )
 
 const (
@@ -22,29 +23,61 @@ type FullEnforcement struct {
 // LegalBasis determines if legal basis is satisfied for a given purpose and bidder/analytics adapter based on the
 // vendor claims in the GVL, publisher restrictions and user consent.
 func (fe *FullEnforcement) LegalBasis(vendorInfo VendorInfo, name string, consent tcf2.ConsentMetadata, overrides Overrides) bool {
+	return fe.explainLegalBasis(vendorInfo, name, consent, overrides).LegalBasis
+}
+
+// explainLegalBasis is LegalBasis explaining how the decision was reached.
+func (fe *FullEnforcement) explainLegalBasis(vendorInfo VendorInfo, name string, consent tcf2.ConsentMetadata, overrides Overrides) PurposeDecision {
 	enforcePurpose, enforceVendors := fe.applyEnforceOverrides(overrides)
+	decision := PurposeDecision{
+		Purpose:         int(fe.cfg.PurposeID),
+		EnforceAlgo:     config.TCF2EnforceAlgoFull,
+		EnforcePurpose:  enforcePurpose,
+		EnforceVendors:  enforceVendors,
+		VendorException: fe.cfg.vendorException(name) && !overrides.blockVendorExceptions,
+	}
 
 	if consent.CheckPubRestriction(uint8(fe.cfg.PurposeID), pubRestrictNotAllowed, vendorInfo.vendorID) {
-		return false
+		decision.Reason = "publisher restricts the purpose for the vendor"
+		return decision
 	}
 	if !enforcePurpose && !enforceVendors {
-		return true
+		decision.LegalBasis = true
+		decision.Reason = "neither the purpose nor vendors are enforced"
+		return decision
 	}
-	if fe.cfg.vendorException(name) && !overrides.blockVendorExceptions {
-		return true
+	if decision.VendorException {
+		decision.LegalBasis = true
+		decision.Reason = "vendor exception"
+		return decision
 	}
 
-	purposeAllowed := fe.consentEstablished(consent, vendorInfo, enforcePurpose, enforceVendors)
-	legitInterest := fe.legitInterestEstablished(consent, vendorInfo, enforcePurpose, enforceVendors)
+	purposeAllowed, consentReason := fe.consentEstablished(consent, vendorInfo, enforcePurpose, enforceVendors)
+	legitInterest, legitInterestReason := fe.legitInterestEstablished(consent, vendorInfo, enforcePurpose, enforceVendors)
+	decision.ConsentReason = consentReason
+	decision.LegitimateInterestReason = legitInterestReason
 
 	if consent.CheckPubRestriction(uint8(fe.cfg.PurposeID), pubRestrictRequireConsent, vendorInfo.vendorID) {
-		return purposeAllowed
+		decision.LegalBasis = purposeAllowed
+		decision.Reason = "publisher requires consent for the purpose"
+		return decision
 	}
 	if consent.CheckPubRestriction(uint8(fe.cfg.PurposeID), pubRestrictRequireLegitInterest, vendorInfo.vendorID) {
-		return legitInterest
+		decision.LegalBasis = legitInterest
+		decision.Reason = "publisher requires legitimate interest for the purpose"
+		return decision
 	}
 
-	return purposeAllowed || legitInterest
+	decision.LegalBasis = purposeAllowed || legitInterest
+	switch {
+	case purposeAllowed:
+		decision.Reason = "consent established"
+	case legitInterest:
+		decision.Reason = "legitimate interest established"
+	default:
+		decision.Reason = "neither consent nor legitimate interest established"
+	}
+	return decision
 }
 
 // applyEnforceOverrides returns the enforce purpose and enforce vendor configuration values unless
@@ -64,39 +97,40 @@ func (fe *FullEnforcement) applyEnforceOverrides(overrides Overrides) (enforcePu
 // consentEstablished determines if consent has been established for a given purpose and bidder
 // based on the purpose config, user consent and the GVL. For consent to be established, the vendor
 // must declare the purpose as either consent or flex and the user must consent in accordance with
-// the purpose configs.
-func (fe *FullEnforcement) consentEstablished(consent tcf2.ConsentMetadata, vi VendorInfo, enforcePurpose bool, enforceVendors bool) bool {
+// the purpose configs. The reason consent was or wasn't established is returned along.
+func (fe *FullEnforcement) consentEstablished(consent tcf2.ConsentMetadata, vi VendorInfo, enforcePurpose bool, enforceVendors bool) (bool, string) {
 	if vi.vendor == nil {
-		return false
+		return false, "vendor is not in the GVL"
 	}
 	if !vi.vendor.Purpose(fe.cfg.PurposeID) {
-		return false
+		return false, "vendor does not declare consent for the purpose"
 	}
 	if enforcePurpose && !consent.PurposeAllowed(fe.cfg.PurposeID) {
-		return false
+		return false, "user did not consent to the purpose"
 	}
 	if enforceVendors && !consent.VendorConsent(vi.vendorID) {
-		return false
+		return false, "user did not consent to the vendor"
 	}
-	return true
+	return true, "consent established"
 }
 
 // legitInterestEstablished determines if legitimate interest has been established for a given
 // purpose and bidder based on the purpose config, user consent and the GVL. For consent to be
 // established, the vendor must declare the purpose as either legit interest or flex and the user
 // must have been provided notice for the legit interest basis in accordance with the purpose configs.
-func (fe *FullEnforcement) legitInterestEstablished(consent tcf2.ConsentMetadata, vi VendorInfo, enforcePurpose bool, enforceVendors bool) bool {
+// The reason legitimate interest was or wasn't established is returned along.
+func (fe *FullEnforcement) legitInterestEstablished(consent tcf2.ConsentMetadata, vi VendorInfo, enforcePurpose bool, enforceVendors bool) (bool, string) {
 	if vi.vendor == nil {
-		return false
+		return false, "vendor is not in the GVL"
 	}
 	if !vi.vendor.LegitimateInterest(fe.cfg.PurposeID) {
-		return false
+		return false, "vendor does not declare legitimate interest for the purpose"
 	}
 	if enforcePurpose && !consent.PurposeLITransparency(fe.cfg.PurposeID) {
-		return false
+		return false, "user was not given legitimate interest transparency for the purpose"
 	}
 	if enforceVendors && !consent.VendorLegitInterest(vi.vendorID) {
-		return false
+		return false, "user objected to the vendor's legitimate interest"
 	}
-	return true
+	return true, "legitimate interest established"
 }
@@ -59,34 +59,68 @@ func (p *permissionsImpl) BidderSyncAllowed(ctx context.Context, bidder openrtb_
 
 // AuctionActivitiesAllowed determines whether auction activities are permitted for a given bidder
 func (p *permissionsImpl) AuctionActivitiesAllowed(ctx context.Context, bidderCoreName openrtb_ext.BidderName, bidder openrtb_ext.BidderName) AuctionPermissions {
+	return p.auctionActivities(ctx, bidderCoreName, bidder, nil)
+}
+
+// explainAuctionActivities is AuctionActivitiesAllowed explaining how the permissions were reached
+func (p *permissionsImpl) explainAuctionActivities(ctx context.Context, bidderCoreName openrtb_ext.BidderName, bidder openrtb_ext.BidderName) AuctionDecision {
+	decision := AuctionDecision{}
+	decision.Permissions = p.auctionActivities(ctx, bidderCoreName, bidder, &decision)
+	return decision
+}
+
+// auctionActivities computes the auction permissions for a given bidder, explaining them in decision
+// unless it's nil
+func (p *permissionsImpl) auctionActivities(ctx context.Context, bidderCoreName openrtb_ext.BidderName, bidder openrtb_ext.BidderName, decision *AuctionDecision) AuctionPermissions {
 	if _, ok := p.nonStandardPublishers[p.publisherID]; ok {
+		decision.explain("publisher is a non standard publisher")
 		return AllowAll
 	}
 
 	if p.gdprSignal != SignalYes {
+		decision.explain("GDPR does not apply")
 		return AllowAll
 	}
 
 	if p.consent == "" {
+		decision.explain("consent string is missing, the default permissions apply")
 		return p.defaultPermissions()
 	}
 
 	pc, err := parseConsent(p.consent)
 	if err != nil {
+		if decision != nil {
+			decision.explain(err.Error() + ", the default permissions apply")
+		}
 		return p.defaultPermissions()
 	}
 
 	vendorID, _ := p.resolveVendorID(bidderCoreName, bidder)
 	vendor, err := p.getVendor(ctx, vendorID, *pc)
+	if decision != nil {
+		decision.VendorID = vendorID
+		decision.VendorIDSource = p.vendorIDSource(bidderCoreName, bidder)
+		decision.VendorInGVL = vendor != nil
+	}
 	if err != nil {
+		if decision != nil {
+			decision.explain(err.Error() + ", the default permissions apply")
+		}
 		return p.defaultPermissions()
 	}
 
 	vendorInfo := VendorInfo{vendorID: vendorID, vendor: vendor}
 	return AuctionPermissions{
-		AllowBidRequest: p.allowBidRequest(bidderCoreName, pc.consentMeta, vendorInfo),
-		PassGeo:         p.allowGeo(bidderCoreName, pc.consentMeta, vendor),
-		PassID:          p.allowID(bidderCoreName, pc.consentMeta, vendorInfo),
+		AllowBidRequest: p.allowBidRequest(bidderCoreName, pc.consentMeta, vendorInfo, decision),
+		PassGeo:         p.allowGeo(bidderCoreName, pc.consentMeta, vendor, decision),
+		PassID:          p.allowID(bidderCoreName, pc.consentMeta, vendorInfo, decision),
+	}
+}
+
+// explain sets the reason of decision, unless it's nil
+func (decision *AuctionDecision) explain(reason string) {
+	if decision != nil {
+		decision.Reason = reason
 	}
 }
 
@@ -118,6 +152,17 @@ func (p *permissionsImpl) resolveVendorID(bidderCoreName openrtb_ext.BidderName,
 	return id, ok
 }
 
+// vendorIDSource tells where resolveVendorID gets the vendor ID of the specified bidder from
+func (p *permissionsImpl) vendorIDSource(bidderCoreName openrtb_ext.BidderName, bidder openrtb_ext.BidderName) string {
+	if _, ok := p.aliasGVLIDs[string(bidder)]; ok {
+		return VendorIDSourceRequest
+	}
+	if _, ok := p.vendorIDs[bidderCoreName]; ok {
+		return VendorIDSourceBidderInfo
+	}
+	return VendorIDSourceUnknown
+}
+
 // allowSync computes cookie sync activity legal basis for a given bidder using the enforcement
 // algorithms selected by the purpose enforcer builder
 func (p *permissionsImpl) allowSync(ctx context.Context, vendorID uint16, bidder openrtb_ext.BidderName, vendorException bool) (bool, error) {
@@ -153,19 +198,28 @@ func (p *permissionsImpl) allowSync(ctx context.Context, vendorID uint16, bidder
 
 // allowBidRequest computes legal basis for a given bidder using the enforcement algorithms selected
 // by the purpose enforcer builder
-func (p *permissionsImpl) allowBidRequest(bidder openrtb_ext.BidderName, consentMeta tcf2.ConsentMetadata, vendorInfo VendorInfo) bool {
+func (p *permissionsImpl) allowBidRequest(bidder openrtb_ext.BidderName, consentMeta tcf2.ConsentMetadata, vendorInfo VendorInfo, decision *AuctionDecision) bool {
 	enforcer := p.purposeEnforcerBuilder(consentconstants.Purpose(2), string(bidder))
 
 	overrides := Overrides{}
 	if _, ok := enforcer.(*BasicEnforcement); ok {
 		overrides.allowLITransparency = true
 	}
+	if decision != nil {
+		purposeDecision := explainLegalBasis(enforcer, vendorInfo, string(bidder), consentMeta, overrides)
+		decision.BidRequest = &purposeDecision
+		return purposeDecision.LegalBasis
+	}
 	return enforcer.LegalBasis(vendorInfo, string(bidder), consentMeta, overrides)
 }
 
 // allowGeo computes legal basis for a given bidder using the configs, consent and GVL pertaining to
 // feature one
-func (p *permissionsImpl) allowGeo(bidder openrtb_ext.BidderName, consentMeta tcf2.ConsentMetadata, vendor api.Vendor) bool {
+func (p *permissionsImpl) allowGeo(bidder openrtb_ext.BidderName, consentMeta tcf2.ConsentMetadata, vendor api.Vendor, decision *AuctionDecision) bool {
+	if decision != nil {
+		decision.Geo = p.explainGeo(bidder, consentMeta, vendor)
+	}
+
 	if !p.cfg.FeatureOneEnforced() {
 		return true
 	}
@@ -178,11 +232,39 @@ func (p *permissionsImpl) allowGeo(bidder openrtb_ext.BidderName, consentMeta tc
 	return consentMeta.SpecialFeatureOptIn(1) && ((vendor != nil && vendor.SpecialFeature(1)) || weakVendorEnforcement)
 }
 
+// explainGeo explains the allowGeo decision for a given bidder
+func (p *permissionsImpl) explainGeo(bidder openrtb_ext.BidderName, consentMeta tcf2.ConsentMetadata, vendor api.Vendor) *FeatureDecision {
+	_, basicEnforcementVendor := p.cfg.BasicEnforcementVendors()[string(bidder)]
+	geo := &FeatureDecision{
+		Enforced:               p.cfg.FeatureOneEnforced(),
+		VendorException:        p.cfg.FeatureOneVendorException(bidder),
+		BasicEnforcementVendor: basicEnforcementVendor,
+		UserOptIn:              consentMeta.SpecialFeatureOptIn(1),
+		VendorDeclared:         vendor != nil && vendor.SpecialFeature(1),
+	}
+
+	switch {
+	case !geo.Enforced:
+		geo.Reason = "special feature 1 is not enforced"
+	case geo.VendorException:
+		geo.Reason = "vendor exception"
+	case !geo.UserOptIn:
+		geo.Reason = "user did not opt in to special feature 1"
+	case geo.VendorDeclared:
+		geo.Reason = "user opted in to special feature 1 and the vendor declares it"
+	case geo.BasicEnforcementVendor:
+		geo.Reason = "user opted in to special feature 1 and basic enforcement vendor"
+	default:
+		geo.Reason = "vendor does not declare special feature 1"
+	}
+	return geo
+}
+
 // allowID computes the pass user ID activity legal basis for a given bidder using the enforcement algorithms
 // selected by the purpose enforcer builder. For the user ID activity, the selected enforcement algorithm must
 // always assume we are enforcing the purpose.
 // If the purpose for which we are computing legal basis is purpose 2, the algorithm should allow LI transparency.
-func (p *permissionsImpl) allowID(bidder openrtb_ext.BidderName, consentMeta tcf2.ConsentMetadata, vendorInfo VendorInfo) bool {
+func (p *permissionsImpl) allowID(bidder openrtb_ext.BidderName, consentMeta tcf2.ConsentMetadata, vendorInfo VendorInfo, decision *AuctionDecision) bool {
 	for i := 2; i <= 10; i++ {
 		purpose := consentconstants.Purpose(i)
 		enforcer := p.purposeEnforcerBuilder(purpose, string(bidder))
@@ -191,6 +273,14 @@ func (p *permissionsImpl) allowID(bidder openrtb_ext.BidderName, consentMeta tcf
 		if _, ok := enforcer.(*BasicEnforcement); ok && purpose == consentconstants.Purpose(2) {
 			overrides.allowLITransparency = true
 		}
+		if decision != nil {
+			purposeDecision := explainLegalBasis(enforcer, vendorInfo, string(bidder), consentMeta, overrides)
+			decision.ID = append(decision.ID, purposeDecision)
+			if purposeDecision.LegalBasis {
+				return true
+			}
+			continue
+		}
 		if enforcer.LegalBasis(vendorInfo, string(bidder), consentMeta, overrides) {
 			return true
 		}
@@ -230,3 +320,6 @@ func (a AlwaysAllow) BidderSyncAllowed(ctx context.Context, bidder openrtb_ext.B
 func (a AlwaysAllow) AuctionActivitiesAllowed(ctx context.Context, bidderCoreName openrtb_ext.BidderName, bidder openrtb_ext.BidderName) AuctionPermissions {
 	return AllowAll
 }
+func (a AlwaysAllow) explainAuctionActivities(ctx context.Context, bidderCoreName openrtb_ext.BidderName, bidder openrtb_ext.BidderName) AuctionDecision {
+	return AuctionDecision{Reason: "GDPR is not enforced", Permissions: AllowAll}
+}
@@ -1,9 +1,9 @@
 package gdpr
 
 type AuctionPermissions struct {
-	AllowBidRequest bool
-	PassGeo         bool
-	PassID          bool
+	AllowBidRequest bool `json:"allowbidrequest"`
+	PassGeo         bool `json:"passgeo"`
+	PassID          bool `json:"passid"`
 }
 
 var AllowAll = AuctionPermissions{
@@ -0,0 +1,161 @@
+package gdpr
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/golang/glog"
+	"github.com/prebid/go-gdpr/api"
+	"github.com/prebid/go-gdpr/vendorlist2"
+	"golang.org/x/net/context/ctxhttp"
+)
+
+// embeddedVendorLists are the vendor lists bundled at build time. See vendorlists/README.md.
+//
+//go:embed vendorlists
+var embeddedVendorLists embed.FS
+
+// loadVendorListBundle saves the vendor lists of every JSON file in bundle. Lists are saved under the
+// specification and list versions they declare, so the file names don't matter. Malformed files are
+// logged and skipped.
+func loadVendorListBundle(bundle fs.FS, name string, saver saveVendors) {
+	loaded := 0
+	err := fs.WalkDir(bundle, ".", func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || path.Ext(filePath) != ".json" {
+			return nil
+		}
+
+		data, err := fs.ReadFile(bundle, filePath)
+		if err != nil {
+			return err
+		}
+		list, err := vendorlist2.ParseEagerly(data)
+		if err != nil {
+			glog.Errorf("Vendor list %s of %s is malformed and was skipped: %v", filePath, name, err)
+			return nil
+		}
+
+		saver(list.SpecVersion(), list.Version(), list)
+		loaded++
+		return nil
+	})
+	if err != nil {
+		glog.Errorf("Failed to load the vendor lists of %s. Cookie syncs may be affected: %v", name, err)
+	}
+	glog.Infof("Loaded %d vendor lists from %s", loaded, name)
+}
+
+// DownloadVendorLists writes the latest vendor lists, and the archived ones missing, into dir using the
+// layout of the URLs they are fetched from. Each list is verified to parse and to declare the versions
+// it was fetched as before it's written. The paths of the files written are returned, including when
+// a later download fails, so that running it again only fetches what is still missing.
+func DownloadVendorLists(ctx context.Context, client *http.Client, urlMaker func(uint16, uint16) string, dir string) ([]string, error) {
+	var written []string
+
+	for _, v := range preloadedVersions {
+		data, latest, err := downloadVendorList(ctx, client, urlMaker, v.specVersion, 0)
+		if err != nil {
+			return written, err
+		}
+		latestVersion := latest.Version()
+
+		for listVersion := v.firstListVersion; listVersion <= latestVersion; listVersion++ {
+			filePath := filepath.Join(dir, filepath.FromSlash(vendorListPath(v.specVersion, listVersion)))
+			if _, err := os.Stat(filePath); err == nil {
+				continue
+			}
+
+			archived := data
+			if listVersion != latestVersion {
+				if archived, _, err = downloadVendorList(ctx, client, urlMaker, v.specVersion, listVersion); err != nil {
+					return written, err
+				}
+			}
+			if err := writeFileAtomically(filePath, archived); err != nil {
+				return written, err
+			}
+			written = append(written, filePath)
+		}
+
+		// The latest list is written last so that it never refers to a version missing from dir.
+		filePath := filepath.Join(dir, filepath.FromSlash(vendorListPath(v.specVersion, 0)))
+		if err := writeFileAtomically(filePath, data); err != nil {
+			return written, err
+		}
+		written = append(written, filePath)
+	}
+
+	return written, nil
+}
+
+// downloadVendorList fetches a given version of the vendor list and verifies it declares that version.
+// If the list version is 0, the latest version is fetched and only its specification version is verified.
+func downloadVendorList(ctx context.Context, client *http.Client, urlMaker func(uint16, uint16) string, specVersion, listVersion uint16) ([]byte, api.VendorList, error) {
+	url := urlMaker(specVersion, listVersion)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := ctxhttp.Do(ctx, client, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading response body from GET %s: %v", url, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("GET %s returned %d", url, resp.StatusCode)
+	}
+
+	list, err := vendorlist2.ParseEagerly(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("GET %s returned a malformed vendor list: %v", url, err)
+	}
+	if list.SpecVersion() != specVersion {
+		return nil, nil, fmt.Errorf("GET %s returned spec version %d instead of %d", url, list.SpecVersion(), specVersion)
+	}
+	if listVersion != 0 && list.Version() != listVersion {
+		return nil, nil, fmt.Errorf("GET %s returned list version %d instead of %d", url, list.Version(), listVersion)
+	}
+	return data, list, nil
+}
+
+// writeFileAtomically writes data to filePath through a temporary file, so that a vendor list being
+// loaded concurrently is never seen partially written.
+func writeFileAtomically(filePath string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(filePath), ".vendor-list-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(file.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), filePath)
+}
@@ -0,0 +1,206 @@
+package gdpr
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadVendorListBundle(t *testing.T) {
+	bundle := fstest.MapFS{
+		"README.md":                         {Data: []byte("not a vendor list")},
+		"v3/vendor-list.json":               {Data: []byte(vendorList2)},
+		"v3/archives/vendor-list-v1.json":   {Data: []byte(vendorList1)},
+		"v3/archives/vendor-list-v2.json":   {Data: []byte(vendorList2)},
+		"v3/archives/vendor-list-v99.json":  {Data: []byte("malformed")},
+		"renamed/any-name-is-fine-too.json": {Data: []byte(MarshalVendorList(vendorList{GVLSpecificationVersion: 2, VendorListVersion: 7}))},
+	}
+
+	s := make(saver, 0, 4)
+	loadVendorListBundle(bundle, "test bundle", s.saveVendorLists)
+
+	expectedLoadedVersions := []versionInfo{
+		{specVersion: 2, listVersion: 7},
+		{specVersion: 3, listVersion: 1},
+		{specVersion: 3, listVersion: 2},
+		{specVersion: 3, listVersion: 2},
+	}
+	assert.ElementsMatch(t, expectedLoadedVersions, s)
+}
+
+func TestEmbeddedVendorListsLoad(t *testing.T) {
+	s := make(saver, 0)
+	loadVendorListBundle(embeddedVendorLists, "embedded bundle", s.saveVendorLists)
+
+	for _, loaded := range s {
+		assert.NotZero(t, loaded.listVersion, "embedded vendor lists must declare their version")
+	}
+}
+
+func TestFetcherOffline(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, writeFileAtomically(filepath.Join(dir, "v3", "archives", "vendor-list-v1.json"), []byte(vendorList1)))
+	require.NoError(t, writeFileAtomically(filepath.Join(dir, "v3", "vendor-list.json"), []byte(vendorList2)))
+
+	cfg := testConfig()
+	cfg.VendorList.Directory = dir
+
+	m := &metrics.MetricsEngineMock{}
+	m.On("RecordGvlListVersion", uint16(3), uint16(1)).Maybe()
+	m.On("RecordGvlListVersion", uint16(3), uint16(2)).Once()
+	fetcher := NewVendorListFetcher(context.Background(), cfg, nil, m, nil)
+
+	list, err := fetcher(context.Background(), 3, 1, m)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(1), list.Version())
+
+	list, err = fetcher(context.Background(), 3, 2, m)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(2), list.Version())
+
+	_, err = fetcher(context.Background(), 3, 3, m)
+	assert.EqualError(t, err, "gdpr vendor list spec version 3 list version 3 does not exist, or has not been loaded yet. Try again in a few minutes")
+	m.AssertExpectations(t)
+}
+
+func TestFetcherSkipsLoadedVersions(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, writeFileAtomically(filepath.Join(dir, "v3", "archives", "vendor-list-v1.json"), []byte(vendorList1)))
+
+	var requests atomic.Int32
+	settings := serverSettings{
+		vendorListLatestVersion: 2,
+		vendorLists:             map[int]map[int]string{3: {2: vendorList2}},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests.Add(1)
+		mockServer(settings)(w, req)
+	}))
+	defer server.Close()
+
+	cfg := testConfig()
+	cfg.VendorList.Directory = dir
+
+	m := &metrics.MetricsEngineMock{}
+	m.On("RecordGvlListRequest")
+	m.On("RecordGvlListVersion", uint16(3), uint16(1)).Once()
+	m.On("RecordGvlListVersion", uint16(3), uint16(2)).Once()
+	fetcher := NewVendorListFetcher(context.Background(), cfg, server.Client(), m, testURLMaker(server))
+
+	_, err := fetcher(context.Background(), 3, 1, m)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), requests.Load(), "only the latest list of each spec version is fetched")
+	m.AssertExpectations(t)
+}
+
+func TestRefreshCache(t *testing.T) {
+	var settings atomic.Pointer[serverSettings]
+	settings.Store(&serverSettings{
+		vendorListLatestVersion: 1,
+		vendorLists:             map[int]map[int]string{3: {1: vendorList1, 2: vendorList2}},
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mockServer(*settings.Load())(w, req)
+	}))
+	defer server.Close()
+
+	cacheSave, cacheLoad := newVendorListCache()
+	m := &metrics.MetricsEngineMock{}
+	m.On("RecordGvlListRequest")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go refreshCache(ctx, 10*time.Millisecond, time.Second, server.Client(), testURLMaker(server), cacheSave, cacheLoad, m)
+
+	assert.Eventually(t, func() bool { return cacheLoad(3, 1) != nil }, time.Second, 10*time.Millisecond)
+	assert.Nil(t, cacheLoad(3, 2))
+
+	settings.Store(&serverSettings{
+		vendorListLatestVersion: 2,
+		vendorLists:             map[int]map[int]string{3: {1: vendorList1, 2: vendorList2}},
+	})
+	assert.Eventually(t, func() bool { return cacheLoad(3, 2) != nil }, time.Second, 10*time.Millisecond, "new versions are picked up")
+}
+
+func TestDownloadVendorLists(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(mockServer(serverSettings{
+		vendorListLatestVersion: 2,
+		vendorLists: map[int]map[int]string{
+			2: {2: MarshalVendorList(vendorList{GVLSpecificationVersion: 2, VendorListVersion: 2})},
+			3: {2: vendorList2},
+		},
+	})))
+	defer server.Close()
+
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "v3", "archives", "vendor-list-v1.json")
+	require.NoError(t, writeFileAtomically(existing, []byte(vendorList1)))
+
+	written, err := DownloadVendorLists(context.Background(), server.Client(), testURLMaker(server), dir)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "v2", "archives", "vendor-list-v2.json"),
+		filepath.Join(dir, "v2", "vendor-list.json"),
+		filepath.Join(dir, "v3", "archives", "vendor-list-v2.json"),
+		filepath.Join(dir, "v3", "vendor-list.json"),
+	}, written)
+
+	s := make(saver, 0, 5)
+	loadVendorListBundle(os.DirFS(dir), dir, s.saveVendorLists)
+	assert.ElementsMatch(t, []versionInfo{
+		{specVersion: 2, listVersion: 2},
+		{specVersion: 2, listVersion: 2},
+		{specVersion: 3, listVersion: 1},
+		{specVersion: 3, listVersion: 2},
+		{specVersion: 3, listVersion: 2},
+	}, s)
+
+	// Mirror the directory from a file:// URL.
+	baseURL := "file://" + filepath.ToSlash(dir)
+	mirror := t.TempDir()
+	written, err = DownloadVendorLists(context.Background(), NewVendorListClient(http.DefaultClient, baseURL), NewVendorListURLMaker(baseURL), mirror)
+
+	require.NoError(t, err)
+	assert.Len(t, written, 5)
+}
+
+func TestDownloadVendorListsVerification(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(mockServer(serverSettings{
+		vendorListLatestVersion: 2,
+		vendorLists: map[int]map[int]string{
+			2: {2: MarshalVendorList(vendorList{GVLSpecificationVersion: 2, VendorListVersion: 2})},
+			3: {1: vendorList2, 2: vendorList2},
+		},
+	})))
+	defer server.Close()
+
+	dir := t.TempDir()
+	written, err := DownloadVendorLists(context.Background(), server.Client(), testURLMaker(server), dir)
+
+	assert.ErrorContains(t, err, "returned list version 2 instead of 1")
+	assert.Equal(t, []string{
+		filepath.Join(dir, "v2", "archives", "vendor-list-v2.json"),
+		filepath.Join(dir, "v2", "vendor-list.json"),
+	}, written)
+	assert.NoFileExists(t, filepath.Join(dir, "v3", "archives", "vendor-list-v1.json"))
+	assert.NoFileExists(t, filepath.Join(dir, "v3", "vendor-list.json"))
+}
+
+func TestNewVendorListURLMaker(t *testing.T) {
+	assert.Nil(t, NewVendorListURLMaker(""))
+
+	urlMaker := NewVendorListURLMaker("file:///var/gvl/")
+	assert.Equal(t, "file:///var/gvl/v3/vendor-list.json", urlMaker(3, 0))
+	assert.Equal(t, "file:///var/gvl/v3/archives/vendor-list-v42.json", urlMaker(3, 42))
+}
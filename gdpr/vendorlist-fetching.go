@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -20,20 +22,41 @@ import (
 )
 
 type saveVendors func(uint16, uint16, api.VendorList)
+type loadVendors func(uint16, uint16) api.VendorList
 type VendorListFetcher func(ctx context.Context, specVersion uint16, listVersion uint16, metricsEngine metrics.MetricsEngine) (vendorlist.VendorList, error)
 
 // This file provides the vendorlist-fetching function for Prebid Server.
 //
 // For more info, see https://github.com/prebid/prebid-server/issues/504
 //
-// Nothing in this file is exported. Public APIs can be found in gdpr.go
+// The fetcher, and the DefaultVendorListURL, NewVendorListURLMaker and NewVendorListClient helpers it downloads
+// the vendor lists with, are exported for the router and the vendor list download tool. The other public APIs can
+// be found in gdpr.go
 
+// NewVendorListFetcher loads the vendor lists of the local directory and embedded bundle configured in cfg,
+// then downloads the latest and missing ones using urlMaker. A nil urlMaker never downloads vendor lists.
+// The background refresh configured in cfg stops once initCtx is done, so initCtx should be cancelled on shutdown.
 func NewVendorListFetcher(initCtx context.Context, cfg config.GDPR, client *http.Client, metricsEngine metrics.MetricsEngine, urlMaker func(uint16, uint16) string) VendorListFetcher {
 	cacheSave, cacheLoad := newVendorListCache()
+	cacheSave = newVersionRecordingSaver(cacheSave, metricsEngine)
 
-	preloadContext, cancel := context.WithTimeout(initCtx, cfg.Timeouts.InitTimeout())
-	defer cancel()
-	preloadCache(preloadContext, client, urlMaker, cacheSave, metricsEngine)
+	if cfg.VendorList.Embedded {
+		loadVendorListBundle(embeddedVendorLists, "embedded bundle", cacheSave)
+	}
+	if cfg.VendorList.Directory != "" {
+		loadVendorListBundle(os.DirFS(cfg.VendorList.Directory), cfg.VendorList.Directory, cacheSave)
+	}
+
+	if urlMaker != nil {
+		preloadContext, cancel := context.WithTimeout(initCtx, cfg.Timeouts.InitTimeout())
+		defer cancel()
+		preloadCache(preloadContext, client, urlMaker, cacheSave, cacheLoad, metricsEngine)
+
+		if cfg.VendorList.RefreshIntervalSeconds > 0 {
+			interval := time.Duration(cfg.VendorList.RefreshIntervalSeconds) * time.Second
+			go refreshCache(initCtx, interval, cfg.Timeouts.InitTimeout(), client, urlMaker, cacheSave, cacheLoad, metricsEngine)
+		}
+	}
 
 	saveOneRateLimited := newOccasionalSaver(cfg.Timeouts.ActiveTimeout())
 	return func(ctx context.Context, specVersion, listVersion uint16, metricsEngine metrics.MetricsEngine) (vendorlist.VendorList, error) {
@@ -44,7 +67,9 @@ func NewVendorListFetcher(initCtx context.Context, cfg config.GDPR, client *http
 
 		// Attempt To Download
 		// - May not add to cache immediately.
-		saveOneRateLimited(ctx, client, urlMaker(specVersion, listVersion), cacheSave, metricsEngine)
+		if urlMaker != nil {
+			saveOneRateLimited(ctx, client, urlMaker(specVersion, listVersion), cacheSave, metricsEngine)
+		}
 
 		// Attempt To Load From Cache Again
 		// - May have been added by the call to saveOneRateLimited.
@@ -61,37 +86,84 @@ func makeVendorListNotFoundError(specVersion, listVersion uint16) error {
 	return fmt.Errorf("gdpr vendor list spec version %d list version %d does not exist, or has not been loaded yet. Try again in a few minutes", specVersion, listVersion)
 }
 
-// preloadCache saves all the known versions of the vendor list for future use.
-func preloadCache(ctx context.Context, client *http.Client, urlMaker func(uint16, uint16) string, saver saveVendors, metricsEngine metrics.MetricsEngine) {
-	versions := [2]struct {
-		specVersion      uint16
-		firstListVersion uint16
-	}{
-		{
-			specVersion:      2,
-			firstListVersion: 2, // The GVL for TCF2 has no vendors defined in its first version. It's very unlikely to be used, so don't preload it.
-		},
-		{
-			specVersion:      3,
-			firstListVersion: 1,
-		},
-	}
-	for _, v := range versions {
+// preloadedVersions are the versions of the vendor list kept for future use.
+var preloadedVersions = [2]struct {
+	specVersion      uint16
+	firstListVersion uint16
+}{
+	{
+		specVersion:      2,
+		firstListVersion: 2, // The GVL for TCF2 has no vendors defined in its first version. It's very unlikely to be used, so don't preload it.
+	},
+	{
+		specVersion:      3,
+		firstListVersion: 1,
+	},
+}
+
+// preloadCache saves all the known versions of the vendor list for future use. Versions already
+// in the cache aren't downloaded again.
+func preloadCache(ctx context.Context, client *http.Client, urlMaker func(uint16, uint16) string, saver saveVendors, loader loadVendors, metricsEngine metrics.MetricsEngine) {
+	for _, v := range preloadedVersions {
 		latestVersion := saveOne(ctx, client, urlMaker(v.specVersion, 0), saver, metricsEngine)
 
 		for i := v.firstListVersion; i < latestVersion; i++ {
-			saveOne(ctx, client, urlMaker(v.specVersion, i), saver, metricsEngine)
+			if loader(v.specVersion, i) == nil {
+				saveOne(ctx, client, urlMaker(v.specVersion, i), saver, metricsEngine)
+			}
 		}
 	}
 }
 
-// Make a URL which can be used to fetch a given version of the Global Vendor List. If the version is 0,
-// this will fetch the latest version.
-func VendorListURLMaker(specVersion, listVersion uint16) string {
+// refreshCache preloads the cache again every interval until ctx is done, so that new versions of the
+// vendor list are available before consent strings start using them.
+func refreshCache(ctx context.Context, interval, timeout time.Duration, client *http.Client, urlMaker func(uint16, uint16) string, saver saveVendors, loader loadVendors, metricsEngine metrics.MetricsEngine) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			refreshContext, cancel := context.WithTimeout(ctx, timeout)
+			preloadCache(refreshContext, client, urlMaker, saver, loader, metricsEngine)
+			cancel()
+		}
+	}
+}
+
+// DefaultVendorListURL is where the Global Vendor Lists are published.
+const DefaultVendorListURL = "https://vendor-list.consensu.org"
+
+// NewVendorListURLMaker returns a function making the URL of a given version of the Global Vendor List
+// published under baseURL, the latest one for version 0, or nil if baseURL is empty.
+func NewVendorListURLMaker(baseURL string) func(uint16, uint16) string {
+	if baseURL == "" {
+		return nil
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	return func(specVersion, listVersion uint16) string {
+		return baseURL + "/" + vendorListPath(specVersion, listVersion)
+	}
+}
+
+// NewVendorListClient returns the client to fetch the vendor lists published under baseURL with. Unlike
+// client, it reads file:// URLs from the local file system.
+func NewVendorListClient(client *http.Client, baseURL string) *http.Client {
+	if strings.HasPrefix(baseURL, "file://") {
+		return &http.Client{Transport: http.NewFileTransport(http.Dir("/"))}
+	}
+	return client
+}
+
+// vendorListPath is the slash separated path of a given version of the Global Vendor List, relative to
+// where the lists are published. If the version is 0, this is the path of the latest version.
+func vendorListPath(specVersion, listVersion uint16) string {
 	if listVersion == 0 {
-		return "https://vendor-list.consensu.org/v" + strconv.Itoa(int(specVersion)) + "/vendor-list.json"
+		return "v" + strconv.Itoa(int(specVersion)) + "/vendor-list.json"
 	}
-	return "https://vendor-list.consensu.org/v" + strconv.Itoa(int(specVersion)) + "/archives/vendor-list-v" + strconv.Itoa(int(listVersion)) + ".json"
+	return "v" + strconv.Itoa(int(specVersion)) + "/archives/vendor-list-v" + strconv.Itoa(int(listVersion)) + ".json"
 }
 
 // newOccasionalSaver returns a wrapped version of saveOne() which only activates every few minutes.
@@ -153,6 +225,24 @@ func saveOne(ctx context.Context, client *http.Client, url string, saver saveVen
 	return newList.Version()
 }
 
+// newVersionRecordingSaver wraps saver to record the latest version of the vendor list saved for each
+// specification version.
+func newVersionRecordingSaver(saver saveVendors, metricsEngine metrics.MetricsEngine) saveVendors {
+	var mu sync.Mutex
+	latest := make(map[uint16]uint16)
+
+	return func(specVersion, listVersion uint16, list api.VendorList) {
+		saver(specVersion, listVersion, list)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if listVersion > latest[specVersion] {
+			latest[specVersion] = listVersion
+			metricsEngine.RecordGvlListVersion(specVersion, listVersion)
+		}
+	}
+}
+
 func newVendorListCache() (save func(specVersion, listVersion uint16, list api.VendorList), load func(specVersion, listVersion uint16) api.VendorList) {
 	cache := &sync.Map{}
 
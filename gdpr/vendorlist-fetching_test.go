@@ -6,8 +6,10 @@ import (
 	"net/http/httptest"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 	"github.com/prebid/go-gdpr/api"
 	"github.com/prebid/go-gdpr/consentconstants"
@@ -98,6 +100,7 @@ func TestFetcherThrottling(t *testing.T) {
 
 	m := &metrics.MetricsEngineMock{}
 	m.On("RecordGvlListRequest").Times(3)
+	m.On("RecordGvlListVersion", mock.Anything, mock.Anything).Maybe()
 	fetcher := NewVendorListFetcher(context.Background(), testConfig(), server.Client(), m, testURLMaker(server))
 
 	// Dynamically Load List 2 Successfully
@@ -123,6 +126,7 @@ func TestMalformedVendorlist(t *testing.T) {
 
 	m := &metrics.MetricsEngineMock{}
 	m.On("RecordGvlListRequest").Times(3)
+	m.On("RecordGvlListVersion", mock.Anything, mock.Anything).Maybe()
 	fetcher := NewVendorListFetcher(context.Background(), testConfig(), server.Client(), m, testURLMaker(server))
 	_, err := fetcher(context.Background(), 3, 1, m)
 
@@ -138,6 +142,7 @@ func TestServerUrlInvalid(t *testing.T) {
 
 	m := &metrics.MetricsEngineMock{}
 	m.On("RecordGvlListRequest").Times(2)
+	m.On("RecordGvlListVersion", mock.Anything, mock.Anything).Maybe()
 	fetcher := NewVendorListFetcher(context.Background(), testConfig(), server.Client(), m, invalidURLGenerator)
 	_, err := fetcher(context.Background(), 3, 1, m)
 
@@ -150,13 +155,14 @@ func TestServerUnavailable(t *testing.T) {
 
 	m := &metrics.MetricsEngineMock{}
 	m.On("RecordGvlListRequest").Times(2)
+	m.On("RecordGvlListVersion", mock.Anything, mock.Anything).Maybe()
 	fetcher := NewVendorListFetcher(context.Background(), testConfig(), server.Client(), m, testURLMaker(server))
 	_, err := fetcher(context.Background(), 3, 1, m)
 
 	assert.EqualError(t, err, "gdpr vendor list spec version 3 list version 1 does not exist, or has not been loaded yet. Try again in a few minutes")
 }
 
-func TestVendorListURLMaker(t *testing.T) {
+func TestDefaultVendorListURLMaker(t *testing.T) {
 	testCases := []struct {
 		description string
 		specVersion uint16
@@ -189,8 +195,9 @@ func TestVendorListURLMaker(t *testing.T) {
 		},
 	}
 
+	urlMaker := NewVendorListURLMaker(DefaultVendorListURL)
 	for _, test := range testCases {
-		result := VendorListURLMaker(test.specVersion, test.listVersion)
+		result := urlMaker(test.specVersion, test.listVersion)
 		assert.Equal(t, test.expectedURL, result)
 	}
 }
@@ -209,6 +216,10 @@ func (s *saver) saveVendorLists(specVersion uint16, listVersion uint16, gvl api.
 	*s = append(*s, vi)
 }
 
+func (s *saver) loadVendorLists(specVersion uint16, listVersion uint16) api.VendorList {
+	return nil
+}
+
 func TestPreloadCache(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(mockServer(serverSettings{
 		vendorListLatestVersion: 3,
@@ -264,7 +275,7 @@ func TestPreloadCache(t *testing.T) {
 	s := make(saver, 0, 5)
 	m := &metrics.MetricsEngineMock{}
 	m.On("RecordGvlListRequest").Times(5)
-	preloadCache(context.Background(), server.Client(), testURLMaker(server), s.saveVendorLists, m)
+	preloadCache(context.Background(), server.Client(), testURLMaker(server), s.saveVendorLists, s.loadVendorLists, m)
 
 	expectedLoadedVersions := []versionInfo{
 		{specVersion: 2, listVersion: 2},
@@ -277,6 +288,25 @@ func TestPreloadCache(t *testing.T) {
 	assert.ElementsMatch(t, expectedLoadedVersions, s)
 }
 
+func TestRefreshCacheStops(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	s := make(saver, 0)
+	stopped := make(chan struct{})
+	go func() {
+		refreshCache(ctx, time.Hour, time.Second, http.DefaultClient, NewVendorListURLMaker(DefaultVendorListURL), s.saveVendorLists, s.loadVendorLists, &metrics.MetricsEngineMock{})
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("the refresh didn't stop once its context was done")
+	}
+	assert.Empty(t, s)
+}
+
 var vendorList1 = MarshalVendorList(vendorList{
 	GVLSpecificationVersion: 3,
 	VendorListVersion:       1,
@@ -393,6 +423,7 @@ func runTest(t *testing.T, test test, server *httptest.Server) {
 	config := testConfig()
 	m := &metrics.MetricsEngineMock{}
 	m.On("RecordGvlListRequest").Times(3)
+	m.On("RecordGvlListVersion", mock.Anything, mock.Anything).Maybe()
 	fetcher := NewVendorListFetcher(context.Background(), config, server.Client(), m, testURLMaker(server))
 	vendorList, err := fetcher(context.Background(), test.setup.specVersion, test.setup.listVersion, m)
 
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/gdpr"
+)
+
+const gvlDownloadCommand = "gvl-download"
+
+// gvlDownload runs the gvl-download subcommand, which downloads the Global Vendor Lists missing from a
+// directory to load at startup through gdpr.vendor_list.directory, or to bundle into the binary from
+// gdpr/vendorlists. The paths of the files written are printed to out.
+func gvlDownload(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet(gvlDownloadCommand, flag.ContinueOnError)
+	dir := flags.String("dir", "", "Directory to download the vendor lists into.")
+	url := flags.String("url", gdpr.DefaultVendorListURL, "Base URL to download the vendor lists from. May be a file:// URL.")
+	timeout := flags.Duration("timeout", 5*time.Minute, "Time allowed for all the downloads.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *dir == "" {
+		return errors.New("-dir is required")
+	}
+	if *url == "" {
+		return errors.New("-url is required")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	written, err := gdpr.DownloadVendorLists(ctx, gdpr.NewVendorListClient(&http.Client{}, *url), gdpr.NewVendorListURLMaker(*url), *dir)
+	for _, path := range written {
+		fmt.Fprintln(out, path)
+	}
+	return err
+}
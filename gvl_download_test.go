@@ -0,0 +1,36 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGVLDownload(t *testing.T) {
+	source := t.TempDir()
+	for spec, list := range map[string]string{
+		"v2": `{"gvlSpecificationVersion":2,"vendorListVersion":2,"vendors":{}}`,
+		"v3": `{"gvlSpecificationVersion":3,"vendorListVersion":1,"vendors":{}}`,
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Join(source, spec), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(source, spec, "vendor-list.json"), []byte(list), 0644))
+	}
+	dir := t.TempDir()
+	out := &bytes.Buffer{}
+
+	err := gvlDownload([]string{"-dir", dir, "-url", "file://" + filepath.ToSlash(source)}, out)
+
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "v2", "archives", "vendor-list-v2.json")+"\n"+
+		filepath.Join(dir, "v2", "vendor-list.json")+"\n"+
+		filepath.Join(dir, "v3", "archives", "vendor-list-v1.json")+"\n"+
+		filepath.Join(dir, "v3", "vendor-list.json")+"\n", out.String())
+}
+
+func TestGVLDownloadRequiresDir(t *testing.T) {
+	assert.EqualError(t, gvlDownload(nil, &bytes.Buffer{}), "-dir is required")
+}
@@ -1,6 +1,7 @@
 package hookexecution
 
 import (
+	"context"
 	"sync"
 
 	"github.com/golang/glog"
@@ -17,6 +18,8 @@ type executionContext struct {
 	account         *config.Account
 	moduleContexts  *moduleContexts
 	activityControl privacy.ActivityControl
+	// traceCtx carries the span which the hook spans of the stage belong to
+	traceCtx context.Context
 }
 
 func (ctx executionContext) getModuleContext(moduleName string) hookstage.ModuleInvocationContext {
@@ -39,6 +39,8 @@ type HookOutcomeTest struct {
 	DebugMessages []string                `json:"debug_messages"`
 	Errors        []string                `json:"errors"`
 	Warnings      []string                `json:"warnings"`
+
+	SeatNonBid []openrtb_ext.SeatNonBid `json:"-"`
 }
 
 func TestEnrichBidResponse(t *testing.T) {
@@ -16,7 +16,9 @@ import (
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/ortb"
 	"github.com/prebid/prebid-server/v3/privacy"
+	"github.com/prebid/prebid-server/v3/tracing"
 	"github.com/prebid/prebid-server/v3/util/iputil"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type hookResponse[T any] struct {
@@ -40,6 +42,13 @@ func executeStage[H any, P any](
 	hookHandler hookHandler[H, P],
 	metricEngine metrics.MetricsEngine,
 ) (StageOutcome, P, stageModuleContext, *RejectError) {
+	if executionCtx.traceCtx == nil {
+		executionCtx.traceCtx = context.Background()
+	}
+	traceCtx, span := tracing.Start(executionCtx.traceCtx, "hooks."+executionCtx.stage)
+	defer span.End()
+	executionCtx.traceCtx = traceCtx
+
 	stageOutcome := StageOutcome{}
 	stageOutcome.Groups = make([]GroupOutcome, 0, len(plan))
 	stageModuleCtx := stageModuleContext{}
@@ -74,11 +83,12 @@ func executeGroup[H any, P any](
 	for _, hook := range group.Hooks {
 		mCtx := executionCtx.getModuleContext(hook.Module)
 		mCtx.HookImplCode = hook.Code
+		mCtx.ActivityControl = executionCtx.activityControl
 		newPayload := handleModuleActivities(hook.Code, executionCtx.activityControl, payload, executionCtx.account)
 		wg.Add(1)
 		go func(hw hooks.HookWrapper[H], moduleCtx hookstage.ModuleInvocationContext) {
 			defer wg.Done()
-			executeHook(moduleCtx, hw, newPayload, hookHandler, group.Timeout, resp, rejected)
+			executeHook(executionCtx.traceCtx, moduleCtx, hw, newPayload, hookHandler, group.Timeout, resp, rejected)
 		}(hook, mCtx)
 	}
 
@@ -93,6 +103,7 @@ func executeGroup[H any, P any](
 }
 
 func executeHook[H any, P any](
+	traceCtx context.Context,
 	moduleCtx hookstage.ModuleInvocationContext,
 	hw hooks.HookWrapper[H],
 	payload P,
@@ -104,6 +115,9 @@ func executeHook[H any, P any](
 	hookRespCh := make(chan hookResponse[P], 1)
 	startTime := time.Now()
 	hookId := HookID{ModuleCode: hw.Module, HookImplCode: hw.Code}
+	traceCtx, span := tracing.Start(traceCtx, "hooks.module",
+		attribute.String("module", hw.Module),
+		attribute.String("hook", hw.Code))
 
 	go func() {
 		defer func() {
@@ -113,7 +127,7 @@ func executeHook[H any, P any](
 			}
 		}()
 
-		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		ctx, cancel := context.WithTimeout(traceCtx, timeout)
 		defer cancel()
 		result, err := hookHandler(ctx, moduleCtx, hw.Hook, payload)
 		hookRespCh <- hookResponse[P]{
@@ -124,10 +138,12 @@ func executeHook[H any, P any](
 
 	select {
 	case res := <-hookRespCh:
+		tracing.End(span, res.Err)
 		res.HookID = hookId
 		res.ExecutionTime = time.Since(startTime)
 		resp <- res
 	case <-time.After(timeout):
+		tracing.End(span, TimeoutError{})
 		resp <- hookResponse[P]{
 			Err:           TimeoutError{},
 			ExecutionTime: time.Since(startTime),
@@ -135,6 +151,7 @@ func executeHook[H any, P any](
 			Result:        hookstage.HookResult[P]{},
 		}
 	case <-rejected:
+		span.End()
 		return
 	}
 }
@@ -211,6 +228,7 @@ func handleHookResponse[P any](
 		rejectErr = handleHookReject(ctx, hr, &hookOutcome, metricEngine, labels)
 	} else {
 		payload = handleHookMutations(payload, hr, &hookOutcome, metricEngine, labels)
+		hookOutcome.SeatNonBid = hr.Result.SeatNonBid
 	}
 
 	return payload, hookOutcome, rejectErr
@@ -351,6 +369,7 @@ func handleModuleActivities[P any](hookCode string, activityControl privacy.Acti
 
 	if !transmitUserFPDActivityAllowed {
 		privacy.ScrubUserFPD(bidderReqCopy)
+		activityControl.Scrubbed(privacy.ActivityTransmitUserFPD, scopeGeneral, privacy.ScrubberUserFPD, privacy.ScrubReasonActivity)
 	}
 	if !transmitPreciseGeoActivityAllowed {
 		var ipConf privacy.IPConf
@@ -363,6 +382,7 @@ func handleModuleActivities[P any](hookCode string, activityControl privacy.Acti
 		}
 
 		privacy.ScrubGeoAndDeviceIP(bidderReqCopy, ipConf)
+		activityControl.Scrubbed(privacy.ActivityTransmitPreciseGeo, scopeGeneral, privacy.ScrubberGeoAndDeviceIP, privacy.ScrubReasonActivity)
 	}
 
 	var newPayload = payload
@@ -100,6 +100,26 @@ func TestHandleModuleActivitiesBidderRequestPayload(t *testing.T) {
 	}
 }
 
+func TestHandleModuleActivitiesPrivacyAudit(t *testing.T) {
+	log := &privacy.ActivityLog{}
+	activityControl := privacy.NewActivityControl(getTransmitUFPDActivityConfig("foo", false)).WithLog(log)
+	payload := hookstage.BidderRequestPayload{
+		Request: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{User: &openrtb2.User{ID: "test_user_id"}}},
+	}
+
+	handleModuleActivities("foo", activityControl, payload, nil)
+
+	evaluations := log.Evaluations()
+	if assert.Len(t, evaluations, 2) {
+		assert.Equal(t, "transmitUfpd", evaluations[0].Activity)
+		assert.Equal(t, privacy.ComponentTypeGeneral, evaluations[0].ComponentType)
+		assert.Equal(t, "foo", evaluations[0].ComponentName)
+		assert.Equal(t, []privacy.Scrubber{{Name: privacy.ScrubberUserFPD, Reason: privacy.ScrubReasonActivity}}, evaluations[0].Scrubbers)
+		assert.Equal(t, "transmitPreciseGeo", evaluations[1].Activity)
+		assert.Empty(t, evaluations[1].Scrubbers)
+	}
+}
+
 func TestHandleModuleActivitiesProcessedAuctionRequestPayload(t *testing.T) {
 
 	testCases := []struct {
@@ -41,6 +41,7 @@ type StageExecutor interface {
 	ExecuteAllProcessedBidResponsesStage(adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid)
 	ExecuteAuctionResponseStage(response *openrtb2.BidResponse)
 	ExecuteExitpointStage(response any, w http.ResponseWriter) any
+	GetSeatNonBid() []openrtb_ext.SeatNonBid
 }
 
 type HookStageExecutor interface {
@@ -59,6 +60,8 @@ type hookExecutor struct {
 	moduleContexts  *moduleContexts
 	metricEngine    metrics.MetricsEngine
 	activityControl privacy.ActivityControl
+	// traceCtx carries the trace of the http request, detached from its cancellation
+	traceCtx context.Context
 	// Mutex needed for BidderRequest and RawBidderResponse Stages as they are run in several goroutines
 	sync.Mutex
 }
@@ -91,6 +94,8 @@ func (e *hookExecutor) GetOutcomes() []StageOutcome {
 }
 
 func (e *hookExecutor) ExecuteEntrypointStage(req *http.Request, body []byte) ([]byte, *RejectError) {
+	e.traceCtx = context.WithoutCancel(req.Context())
+
 	plan := e.planBuilder.PlanForEntrypointStage(e.endpoint)
 	if len(plan) == 0 {
 		return body, nil
@@ -334,6 +339,7 @@ func (e *hookExecutor) newContext(stage string) executionContext {
 		moduleContexts:  e.moduleContexts,
 		stage:           stage,
 		activityControl: e.activityControl,
+		traceCtx:        e.traceCtx,
 	}
 }
 
@@ -345,6 +351,22 @@ func (e *hookExecutor) saveModuleContexts(ctxs stageModuleContext) {
 	}
 }
 
+// GetSeatNonBid returns the bids discarded by the hooks executed so far.
+func (e *hookExecutor) GetSeatNonBid() []openrtb_ext.SeatNonBid {
+	e.Lock()
+	defer e.Unlock()
+
+	var seatNonBid []openrtb_ext.SeatNonBid
+	for _, stageOutcome := range e.stageOutcomes {
+		for _, groupOutcome := range stageOutcome.Groups {
+			for _, hookOutcome := range groupOutcome.InvocationResults {
+				seatNonBid = append(seatNonBid, hookOutcome.SeatNonBid...)
+			}
+		}
+	}
+	return seatNonBid
+}
+
 func (e *hookExecutor) pushStageOutcome(outcome StageOutcome) {
 	e.Lock()
 	defer e.Unlock()
@@ -390,3 +412,7 @@ func (executor EmptyHookExecutor) ExecuteAuctionResponseStage(_ *openrtb2.BidRes
 func (executor EmptyHookExecutor) ExecuteExitpointStage(response any, _ http.ResponseWriter) any {
 	return response
 }
+
+func (executor EmptyHookExecutor) GetSeatNonBid() []openrtb_ext.SeatNonBid {
+	return nil
+}
@@ -2,6 +2,7 @@ package hookexecution
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -20,9 +21,13 @@ import (
 	metricsConfig "github.com/prebid/prebid-server/v3/metrics/config"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/privacy"
+	"github.com/prebid/prebid-server/v3/tracing"
+	"github.com/prebid/prebid-server/v3/tracing/tracingtest"
 	"github.com/prebid/prebid-server/v3/util/ptrutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	oteltrace "go.opentelemetry.io/otel/trace"
 )
 
 func TestEmptyHookExecutor(t *testing.T) {
@@ -45,6 +50,7 @@ func TestEmptyHookExecutor(t *testing.T) {
 	outcomes := executor.GetOutcomes()
 	assert.Equal(t, EmptyHookExecutor{}, executor, "EmptyHookExecutor shouldn't be changed.")
 	assert.Empty(t, outcomes, "EmptyHookExecutor shouldn't return stage outcomes.")
+	assert.Empty(t, executor.GetSeatNonBid(), "EmptyHookExecutor shouldn't return seat non bids.")
 
 	assert.Nil(t, entrypointRejectErr, "EmptyHookExecutor shouldn't return reject error at entrypoint stage.")
 	assert.Equal(t, body, entrypointBody, "EmptyHookExecutor shouldn't change body at entrypoint stage.")
@@ -57,6 +63,30 @@ func TestEmptyHookExecutor(t *testing.T) {
 	assert.Equal(t, expectedBidderRequest, bidderRequest, "EmptyHookExecutor shouldn't change payload at bidder-request stage.")
 }
 
+func TestGetSeatNonBid(t *testing.T) {
+	executor := hookExecutor{stageOutcomes: []StageOutcome{
+		{
+			Stage: hooks.StageRawBidderResponse.String(),
+			Groups: []GroupOutcome{{InvocationResults: []HookOutcome{
+				{SeatNonBid: []openrtb_ext.SeatNonBid{{Seat: "appnexus", NonBid: []openrtb_ext.NonBid{{ImpId: "imp1", StatusCode: 301}}}}},
+				{},
+			}}},
+		},
+		{
+			Stage: hooks.StageAllProcessedBidResponses.String(),
+			Groups: []GroupOutcome{{InvocationResults: []HookOutcome{
+				{SeatNonBid: []openrtb_ext.SeatNonBid{{Seat: "rubicon", NonBid: []openrtb_ext.NonBid{{ImpId: "imp2", StatusCode: 300}}}}},
+			}}},
+		},
+	}}
+
+	expected := []openrtb_ext.SeatNonBid{
+		{Seat: "appnexus", NonBid: []openrtb_ext.NonBid{{ImpId: "imp1", StatusCode: 301}}},
+		{Seat: "rubicon", NonBid: []openrtb_ext.NonBid{{ImpId: "imp2", StatusCode: 300}}},
+	}
+	assert.Equal(t, expected, executor.GetSeatNonBid())
+}
+
 func TestExecuteEntrypointStage(t *testing.T) {
 	const body string = `{"name": "John", "last_name": "Doe"}`
 	const urlString string = "https://prebid.com/openrtb2/auction"
@@ -2439,6 +2469,35 @@ func TestInterStageContextCommunication(t *testing.T) {
 	}}, exec.moduleContexts, "Wrong module contexts after executing auction-response hook.")
 }
 
+func TestHookExecutionIsTraced(t *testing.T) {
+	exporter := tracingtest.Install(t)
+
+	ctx, requestSpan := tracing.Start(context.Background(), "openrtb2.auction")
+	req := httptest.NewRequest(http.MethodPost, "https://prebid.com/openrtb2/auction", nil).WithContext(ctx)
+
+	exec := NewHookExecutor(TestApplyHookMutationsBuilder{}, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+	exec.ExecuteEntrypointStage(req, []byte(`{"name": "John", "last_name": "Doe"}`))
+	exec.ExecuteRawAuctionStage([]byte(`{"name": "John", "last_name": "Doe"}`))
+	requestSpan.End()
+
+	stageSpans := map[string]oteltrace.SpanID{}
+	moduleSpans := map[oteltrace.SpanID]int{}
+	for _, span := range exporter.GetSpans() {
+		assert.Equal(t, requestSpan.SpanContext().TraceID(), span.SpanContext.TraceID(), "%s is part of the request trace", span.Name)
+		switch span.Name {
+		case "hooks.entrypoint", "hooks.raw_auction_request":
+			assert.Equal(t, requestSpan.SpanContext().SpanID(), span.Parent.SpanID())
+			stageSpans[span.Name] = span.SpanContext.SpanID()
+		case "hooks.module":
+			moduleSpans[span.Parent.SpanID()]++
+		}
+	}
+
+	require.Len(t, stageSpans, 2)
+	assert.Equal(t, 5, moduleSpans[stageSpans["hooks.entrypoint"]])
+	assert.Equal(t, 3, moduleSpans[stageSpans["hooks.raw_auction_request"]])
+}
+
 type TestApplyHookMutationsBuilder struct {
 	hooks.EmptyPlanBuilder
 }
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
 )
 
 // Status indicates the result of hook execution.
@@ -85,6 +86,9 @@ type HookOutcome struct {
 	DebugMessages []string                `json:"debug_messages,omitempty"`
 	Errors        []string                `json:"-"`
 	Warnings      []string                `json:"-"`
+
+	// SeatNonBid holds the bids discarded by the hook.
+	SeatNonBid []openrtb_ext.SeatNonBid `json:"-"`
 }
 
 // HookID points to the specific hook defined by the hook execution plan.
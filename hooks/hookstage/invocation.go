@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 
 	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/privacy"
 )
 
 // HookResult represents the result of execution the concrete hook instance.
@@ -17,6 +19,8 @@ type HookResult[T any] struct {
 	DebugMessages []string
 	AnalyticsTags hookanalytics.Analytics
 	ModuleContext ModuleContext // holds values that the module wants to pass to itself at later stages
+	// SeatNonBid holds the bids the hook discarded, reported in the seatnonbid of the auction response
+	SeatNonBid []openrtb_ext.SeatNonBid
 }
 
 // ModuleInvocationContext holds data passed to the module hook during invocation.
@@ -31,6 +35,9 @@ type ModuleInvocationContext struct {
 	ModuleContext ModuleContext
 	// HookImplCode is the hook_impl_code for a module instance to differentiate between multiple hooks
 	HookImplCode string
+
+	// ActivityControl holds the activity controls of the account, for modules enforcing them on their own.
+	ActivityControl privacy.ActivityControl
 }
 
 // ModuleContext holds arbitrary data passed between module hooks at different stages.
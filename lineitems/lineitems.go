@@ -0,0 +1,490 @@
+package lineitems
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/timeutil"
+)
+
+// Status describes the outcome for a line item which targets an auction request.
+type Status string
+
+const (
+	// StatusInjected means the line item deal was passed to its source bidder.
+	StatusInjected Status = "injected"
+	// StatusBid means Prebid Server bid with the line item creative.
+	StatusBid Status = "bid"
+	// StatusWon means the line item won its imp. Its delivery is counted once its impression event arrives.
+	StatusWon Status = "won"
+	// StatusInactive means the request is outside of the line item flight dates.
+	StatusInactive Status = "inactive"
+	// StatusGoalReached means the line item delivered its impression goal.
+	StatusGoalReached Status = "goal_reached"
+	// StatusPaced means the line item is ahead of its delivery schedule.
+	StatusPaced Status = "paced"
+	// StatusFrequencyCapped means the user has seen the line item as often as its caps allow.
+	StatusFrequencyCapped Status = "frequency_capped"
+)
+
+// Match is a line item whose targeting matches an imp of the auction request.
+type Match struct {
+	LineItem config.AccountLineItem
+	ImpID    string
+	Status   Status
+}
+
+// Ready returns true if the line item takes part in the auction.
+func (m Match) Ready() bool {
+	return m.Status == StatusInjected || m.Status == StatusBid
+}
+
+// Bid builds the bid Prebid Server places on behalf of a line item with a creative. The price is in the line
+// item currency.
+func (m Match) Bid() (*openrtb2.Bid, openrtb_ext.BidType) {
+	creative := m.LineItem.Creative
+	bidType := openrtb_ext.BidTypeBanner
+	if creative.MediaType != "" {
+		bidType = openrtb_ext.BidType(creative.MediaType)
+	}
+
+	return &openrtb2.Bid{
+		ID:      fmt.Sprintf("%s-%s", m.LineItem.ID, m.ImpID),
+		ImpID:   m.ImpID,
+		Price:   m.LineItem.Price,
+		AdM:     creative.AdM,
+		ADomain: creative.ADomain,
+		CrID:    creative.ID,
+		DealID:  m.LineItem.DealID,
+		W:       creative.W,
+		H:       creative.H,
+	}, bidType
+}
+
+// Report summarizes the delivery of a line item since Prebid Server started. Line items are forgotten once their
+// flight ended or they were not matched for a day.
+type Report struct {
+	ID              string     `json:"id"`
+	Source          string     `json:"source"`
+	DealID          string     `json:"deal_id"`
+	Goal            int64      `json:"impression_goal,omitempty"`
+	ExpectedByNow   int64      `json:"expected_delivery,omitempty"`
+	Matched         int64      `json:"matched"`
+	ReadyToServe    int64      `json:"ready_to_serve"`
+	Delivered       int64      `json:"delivered"`
+	Paced           int64      `json:"paced"`
+	FrequencyCapped int64      `json:"frequency_capped"`
+	StartTime       *time.Time `json:"start_time,omitempty"`
+	EndTime         *time.Time `json:"end_time,omitempty"`
+	LastDelivery    *time.Time `json:"last_delivery,omitempty"`
+}
+
+// Service matches the line items of an account against auction requests and paces their delivery.
+type Service interface {
+	// Match returns the line items targeting the request. Line items which are not ready to serve are
+	// included with the reason. Invalid line items are skipped and reported as warnings.
+	Match(account *config.Account, req *openrtb_ext.RequestWrapper, userID string) ([]Match, []error)
+	// RecordWin remembers the bid a ready line item won its imp with, so its impression event can be counted as a
+	// delivery. The user is the one the line item frequency caps were checked for.
+	RecordWin(accountID, lineItemID, bidID, userID string)
+	// RecordImpression counts a delivery of the line item which won with the bid, for the user recorded with the
+	// win. The user id of the impression event is only used if none was recorded. Impressions of unknown bids
+	// are ignored.
+	RecordImpression(accountID, bidID, userID string)
+	// Report returns the delivery of every line item of the account seen so far, ordered by id.
+	Report(accountID string) []Report
+}
+
+// NewService returns a Service which keeps delivery stats in memory and counts impression events arriving up to
+// winTTL after the auction. Stats are lost on restart, so pacing starts over from the configured goals.
+func NewService(clock timeutil.Time, winTTL time.Duration) Service {
+	return &service{
+		clock:    clock,
+		winTTL:   winTTL,
+		accounts: make(map[string]map[string]*delivery),
+		wins:     make(map[winKey]win),
+	}
+}
+
+const (
+	// expiryInterval is how often the expired wins, impressions and line items are dropped
+	expiryInterval = time.Minute
+	// idleTimeout is how long the stats of a line item no longer matched, e.g. removed from its account, are kept
+	idleTimeout = 24 * time.Hour
+)
+
+type service struct {
+	clock      timeutil.Time
+	winTTL     time.Duration
+	mu         sync.Mutex
+	accounts   map[string]map[string]*delivery
+	wins       map[winKey]win
+	lastExpiry time.Time
+}
+
+type winKey struct {
+	accountID string
+	bidID     string
+}
+
+// win is a line item bid awaiting its impression event.
+type win struct {
+	lineItemID string
+	userID     string
+	expires    time.Time
+}
+
+type delivery struct {
+	lineItem        config.AccountLineItem
+	matched         int64
+	readyToServe    int64
+	delivered       int64
+	paced           int64
+	frequencyCapped int64
+	lastDelivery    time.Time
+	lastMatched     time.Time
+	// impressions holds the delivery times per user which are still relevant to a frequency cap
+	impressions map[string][]time.Time
+}
+
+func (s *service) Match(account *config.Account, req *openrtb_ext.RequestWrapper, userID string) ([]Match, []error) {
+	if account == nil || len(account.LineItems) == 0 {
+		return nil, nil
+	}
+
+	var (
+		matches []Match
+		errs    []error
+		now     = s.clock.Now()
+	)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire(now)
+
+	for _, lineItem := range account.LineItems {
+		if err := validate(lineItem); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !matchesRequest(lineItem.Targeting, req) {
+			continue
+		}
+		impID, ok := firstMatchingImp(lineItem.Targeting, req)
+		if !ok {
+			continue
+		}
+
+		// ended line items are not tracked, so their stats can expire
+		if !lineItem.EndTime.IsZero() && !now.Before(lineItem.EndTime) {
+			if _, ok := s.accounts[account.ID][lineItem.ID]; !ok {
+				matches = append(matches, Match{LineItem: lineItem, ImpID: impID, Status: StatusInactive})
+				continue
+			}
+		}
+
+		d := s.getDelivery(account.ID, lineItem)
+		d.matched++
+		d.lastMatched = now
+		status := d.status(now, userID)
+		switch status {
+		case StatusPaced:
+			d.paced++
+		case StatusFrequencyCapped:
+			d.frequencyCapped++
+		case StatusInjected, StatusBid:
+			d.readyToServe++
+		}
+		matches = append(matches, Match{LineItem: lineItem, ImpID: impID, Status: status})
+	}
+
+	return matches, errs
+}
+
+func (s *service) RecordWin(accountID, lineItemID, bidID, userID string) {
+	now := s.clock.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire(now)
+
+	if _, ok := s.accounts[accountID][lineItemID]; !ok {
+		return
+	}
+	s.wins[winKey{accountID: accountID, bidID: bidID}] = win{lineItemID: lineItemID, userID: userID, expires: now.Add(s.winTTL)}
+}
+
+func (s *service) RecordImpression(accountID, bidID, userID string) {
+	now := s.clock.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := winKey{accountID: accountID, bidID: bidID}
+	w, ok := s.wins[key]
+	if !ok || !now.Before(w.expires) {
+		return
+	}
+	delete(s.wins, key)
+	if w.userID != "" {
+		userID = w.userID
+	}
+
+	d, ok := s.accounts[accountID][w.lineItemID]
+	if !ok {
+		return
+	}
+	d.delivered++
+	d.lastDelivery = now
+
+	window := d.frequencyWindow()
+	if userID == "" || window == 0 {
+		return
+	}
+	d.impressions[userID] = append(pruneBefore(d.impressions[userID], now.Add(-window)), now)
+}
+
+func (s *service) Report(accountID string) []Report {
+	now := s.clock.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reports := make([]Report, 0, len(s.accounts[accountID]))
+	for _, d := range s.accounts[accountID] {
+		report := Report{
+			ID:              d.lineItem.ID,
+			Source:          d.lineItem.Source,
+			DealID:          d.lineItem.DealID,
+			Goal:            d.lineItem.Goal,
+			Matched:         d.matched,
+			ReadyToServe:    d.readyToServe,
+			Delivered:       d.delivered,
+			Paced:           d.paced,
+			FrequencyCapped: d.frequencyCapped,
+			StartTime:       timeOrNil(d.lineItem.StartTime),
+			EndTime:         timeOrNil(d.lineItem.EndTime),
+			LastDelivery:    timeOrNil(d.lastDelivery),
+		}
+		if d.lineItem.Goal > 0 {
+			report.ExpectedByNow = d.expected(now)
+		}
+		reports = append(reports, report)
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].ID < reports[j].ID })
+	return reports
+}
+
+// expire drops the wins whose impression event can no longer be counted, the impressions outside of the
+// frequency cap periods, and the line items which ended or are no longer matched. It runs at most once per
+// expiryInterval.
+func (s *service) expire(now time.Time) {
+	if now.Sub(s.lastExpiry) < expiryInterval {
+		return
+	}
+	s.lastExpiry = now
+
+	for key, w := range s.wins {
+		if !now.Before(w.expires) {
+			delete(s.wins, key)
+		}
+	}
+
+	for accountID, lineItems := range s.accounts {
+		for lineItemID, d := range lineItems {
+			// the impressions of the last wins still count towards a line item which just ended
+			ended := !d.lineItem.EndTime.IsZero() && now.Sub(d.lineItem.EndTime) > s.winTTL
+			if ended || now.Sub(d.lastMatched) > idleTimeout {
+				delete(lineItems, lineItemID)
+				continue
+			}
+			since := now.Add(-d.frequencyWindow())
+			for userID, impressions := range d.impressions {
+				if impressions = pruneBefore(impressions, since); len(impressions) > 0 {
+					d.impressions[userID] = impressions
+				} else {
+					delete(d.impressions, userID)
+				}
+			}
+		}
+		if len(lineItems) == 0 {
+			delete(s.accounts, accountID)
+		}
+	}
+}
+
+// getDelivery returns the delivery stats of a line item, refreshing the line item in case the account
+// changed since the last request.
+func (s *service) getDelivery(accountID string, lineItem config.AccountLineItem) *delivery {
+	lineItems, ok := s.accounts[accountID]
+	if !ok {
+		lineItems = make(map[string]*delivery)
+		s.accounts[accountID] = lineItems
+	}
+	d, ok := lineItems[lineItem.ID]
+	if !ok {
+		d = &delivery{impressions: make(map[string][]time.Time)}
+		lineItems[lineItem.ID] = d
+	}
+	d.lineItem = lineItem
+	return d
+}
+
+func (d *delivery) status(now time.Time, userID string) Status {
+	lineItem := d.lineItem
+	if (!lineItem.StartTime.IsZero() && now.Before(lineItem.StartTime)) || (!lineItem.EndTime.IsZero() && !now.Before(lineItem.EndTime)) {
+		return StatusInactive
+	}
+	if lineItem.Goal > 0 && d.delivered >= lineItem.Goal {
+		return StatusGoalReached
+	}
+	if d.delivered >= d.expected(now) {
+		return StatusPaced
+	}
+	if d.isFrequencyCapped(now, userID) {
+		return StatusFrequencyCapped
+	}
+	if lineItem.Creative != nil {
+		return StatusBid
+	}
+	return StatusInjected
+}
+
+// expected returns the number of impressions the line item should have delivered by now when spreading its
+// goal evenly over its flight. Line items without flight dates deliver as fast as possible.
+func (d *delivery) expected(now time.Time) int64 {
+	lineItem := d.lineItem
+	if lineItem.Goal <= 0 {
+		return math.MaxInt64
+	}
+	if lineItem.StartTime.IsZero() || lineItem.EndTime.IsZero() {
+		return lineItem.Goal
+	}
+
+	flight := lineItem.EndTime.Sub(lineItem.StartTime)
+	elapsed := now.Sub(lineItem.StartTime)
+	if flight <= 0 || elapsed >= flight {
+		return lineItem.Goal
+	}
+	if elapsed <= 0 {
+		return 0
+	}
+	return int64(math.Ceil(float64(lineItem.Goal) * float64(elapsed) / float64(flight)))
+}
+
+func (d *delivery) isFrequencyCapped(now time.Time, userID string) bool {
+	// users who can't be recognized are never capped
+	if userID == "" {
+		return false
+	}
+	impressions := d.impressions[userID]
+	for _, frequencyCap := range d.lineItem.FrequencyCaps {
+		since := now.Add(-time.Duration(frequencyCap.PeriodSeconds) * time.Second)
+		if len(pruneBefore(impressions, since)) >= frequencyCap.Count {
+			return true
+		}
+	}
+	return false
+}
+
+// frequencyWindow returns the longest period of the line item frequency caps.
+func (d *delivery) frequencyWindow() time.Duration {
+	var window time.Duration
+	for _, frequencyCap := range d.lineItem.FrequencyCaps {
+		if period := time.Duration(frequencyCap.PeriodSeconds) * time.Second; period > window {
+			window = period
+		}
+	}
+	return window
+}
+
+// pruneBefore drops the impression times before since. The times are kept in ascending order.
+func pruneBefore(impressions []time.Time, since time.Time) []time.Time {
+	i := sort.Search(len(impressions), func(i int) bool { return !impressions[i].Before(since) })
+	return impressions[i:]
+}
+
+func firstMatchingImp(targeting config.LineItemTargeting, req *openrtb_ext.RequestWrapper) (string, bool) {
+	for _, imp := range req.GetImp() {
+		if matchesImp(targeting, imp) {
+			return imp.ID, true
+		}
+	}
+	return "", false
+}
+
+func validate(lineItem config.AccountLineItem) error {
+	var problem string
+	switch {
+	case lineItem.ID == "":
+		problem = "id is required"
+	case lineItem.Source == "":
+		problem = "source is required"
+	case lineItem.DealID == "":
+		problem = "deal_id is required"
+	case lineItem.Creative != nil && lineItem.Price <= 0:
+		problem = "price must be positive for line items with a creative"
+	default:
+		for _, frequencyCap := range lineItem.FrequencyCaps {
+			if frequencyCap.Count <= 0 || frequencyCap.PeriodSeconds <= 0 {
+				problem = "frequency caps require a positive count and period_sec"
+			}
+		}
+	}
+	if problem == "" {
+		return nil
+	}
+	return &errortypes.Warning{
+		WarningCode: errortypes.LineItemWarningCode,
+		Message:     fmt.Sprintf("line item %q ignored: %s", lineItem.ID, problem),
+	}
+}
+
+// InjectDeals adds the deal of each ready line item without a creative to its imp, restricted to the line
+// item source seat.
+func InjectDeals(req *openrtb_ext.RequestWrapper, matches []Match) {
+	for _, match := range matches {
+		if match.Status != StatusInjected {
+			continue
+		}
+		for _, imp := range req.GetImp() {
+			if imp.ID != match.ImpID {
+				continue
+			}
+			if imp.PMP == nil {
+				imp.PMP = &openrtb2.PMP{}
+			}
+			if !hasDeal(imp.PMP, match.LineItem.DealID) {
+				imp.PMP.Deals = append(imp.PMP.Deals, openrtb2.Deal{
+					ID:          match.LineItem.DealID,
+					BidFloor:    match.LineItem.Price,
+					BidFloorCur: match.LineItem.Currency,
+					WSeat:       []string{match.LineItem.Source},
+				})
+			}
+		}
+	}
+}
+
+func hasDeal(pmp *openrtb2.PMP, dealID string) bool {
+	for _, deal := range pmp.Deals {
+		if deal.ID == dealID {
+			return true
+		}
+	}
+	return false
+}
+
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
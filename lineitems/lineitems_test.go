@@ -0,0 +1,382 @@
+package lineitems
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTime struct {
+	time time.Time
+}
+
+func (ft *fakeTime) Now() time.Time {
+	return ft.time
+}
+
+var flightStart = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+var deliveredBids int
+
+// deliver wins an auction with a bid of the line item and counts its impression event
+func deliver(service Service, accountID, lineItemID, userID string) {
+	deliveredBids++
+	bidID := fmt.Sprintf("bid-%d", deliveredBids)
+	service.RecordWin(accountID, lineItemID, bidID, userID)
+	service.RecordImpression(accountID, bidID, "")
+}
+
+func newRequest() *openrtb_ext.RequestWrapper {
+	return &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+		ID: "req",
+		Imp: []openrtb2.Imp{
+			{ID: "imp-video", Video: &openrtb2.Video{W: ptrutil.ToPtr[int64](640), H: ptrutil.ToPtr[int64](480)}},
+			{ID: "imp-banner", TagID: "top", Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}}}},
+		},
+		Site:   &openrtb2.Site{Publisher: &openrtb2.Publisher{Domain: "example.com"}},
+		Device: &openrtb2.Device{Geo: &openrtb2.Geo{Country: "USA"}},
+	}}
+}
+
+func TestMatch(t *testing.T) {
+	testCases := []struct {
+		name            string
+		lineItem        config.AccountLineItem
+		expectedMatches []Match
+		expectedErrs    []error
+	}{
+		{
+			name:            "no-targeting-matches-first-imp",
+			lineItem:        config.AccountLineItem{ID: "li", Source: "appnexus", DealID: "deal"},
+			expectedMatches: []Match{{ImpID: "imp-video", Status: StatusInjected}},
+		},
+		{
+			name: "imp-targeting",
+			lineItem: config.AccountLineItem{ID: "li", Source: "appnexus", DealID: "deal", Targeting: config.LineItemTargeting{
+				MediaTypes:  []string{"Banner"},
+				Sizes:       []config.LineItemSize{{W: 300, H: 250}},
+				AdUnitCodes: []string{"top"},
+			}},
+			expectedMatches: []Match{{ImpID: "imp-banner", Status: StatusInjected}},
+		},
+		{
+			name: "request-targeting",
+			lineItem: config.AccountLineItem{ID: "li", Source: "appnexus", DealID: "deal", Targeting: config.LineItemTargeting{
+				SiteDomains: []string{"EXAMPLE.com"},
+				Countries:   []string{"usa"},
+			}},
+			expectedMatches: []Match{{ImpID: "imp-video", Status: StatusInjected}},
+		},
+		{
+			name: "app-targeting-on-site-request",
+			lineItem: config.AccountLineItem{ID: "li", Source: "appnexus", DealID: "deal", Targeting: config.LineItemTargeting{
+				AppBundles: []string{"com.example"},
+			}},
+		},
+		{
+			name: "no-imp-matches-size",
+			lineItem: config.AccountLineItem{ID: "li", Source: "appnexus", DealID: "deal", Targeting: config.LineItemTargeting{
+				Sizes: []config.LineItemSize{{W: 728, H: 90}},
+			}},
+		},
+		{
+			name:            "creative-bids",
+			lineItem:        config.AccountLineItem{ID: "li", Source: "appnexus", DealID: "deal", Price: 2, Creative: &config.LineItemCreative{AdM: "<div/>"}},
+			expectedMatches: []Match{{ImpID: "imp-video", Status: StatusBid}},
+		},
+		{
+			name:            "before-flight",
+			lineItem:        config.AccountLineItem{ID: "li", Source: "appnexus", DealID: "deal", StartTime: flightStart.Add(time.Hour)},
+			expectedMatches: []Match{{ImpID: "imp-video", Status: StatusInactive}},
+		},
+		{
+			name:            "after-flight",
+			lineItem:        config.AccountLineItem{ID: "li", Source: "appnexus", DealID: "deal", EndTime: flightStart},
+			expectedMatches: []Match{{ImpID: "imp-video", Status: StatusInactive}},
+		},
+		{
+			name:     "invalid",
+			lineItem: config.AccountLineItem{ID: "li", Source: "appnexus"},
+			expectedErrs: []error{&errortypes.Warning{
+				WarningCode: errortypes.LineItemWarningCode,
+				Message:     `line item "li" ignored: deal_id is required`,
+			}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			service := NewService(&fakeTime{time: flightStart}, time.Hour)
+			account := &config.Account{ID: "account", LineItems: []config.AccountLineItem{test.lineItem}}
+
+			matches, errs := service.Match(account, newRequest(), "user")
+
+			for i := range test.expectedMatches {
+				test.expectedMatches[i].LineItem = test.lineItem
+			}
+			assert.Equal(t, test.expectedMatches, matches)
+			assert.Equal(t, test.expectedErrs, errs)
+		})
+	}
+}
+
+func TestMatchPacing(t *testing.T) {
+	clock := &fakeTime{time: flightStart}
+	service := NewService(clock, time.Hour)
+	lineItem := config.AccountLineItem{
+		ID:        "li",
+		Source:    "appnexus",
+		DealID:    "deal",
+		Goal:      10,
+		StartTime: flightStart,
+		EndTime:   flightStart.Add(10 * time.Hour),
+	}
+	account := &config.Account{ID: "account", LineItems: []config.AccountLineItem{lineItem}}
+
+	matches, _ := service.Match(account, newRequest(), "")
+	require.Len(t, matches, 1)
+	assert.Equal(t, StatusPaced, matches[0].Status, "nothing is expected at the start of the flight")
+
+	clock.time = flightStart.Add(90 * time.Minute)
+	matches, _ = service.Match(account, newRequest(), "")
+	require.Len(t, matches, 1)
+	assert.Equal(t, StatusInjected, matches[0].Status)
+
+	deliver(service, "account", "li", "")
+	deliver(service, "account", "li", "")
+	matches, _ = service.Match(account, newRequest(), "")
+	require.Len(t, matches, 1)
+	assert.Equal(t, StatusPaced, matches[0].Status, "two impressions are expected after 90 minutes")
+
+	clock.time = flightStart.Add(9 * time.Hour)
+	for i := 0; i < 8; i++ {
+		deliver(service, "account", "li", "")
+	}
+	matches, _ = service.Match(account, newRequest(), "")
+	require.Len(t, matches, 1)
+	assert.Equal(t, StatusGoalReached, matches[0].Status)
+
+	assert.Equal(t, []Report{{
+		ID:            "li",
+		Source:        "appnexus",
+		DealID:        "deal",
+		Goal:          10,
+		ExpectedByNow: 9,
+		Matched:       4,
+		ReadyToServe:  1,
+		Delivered:     10,
+		Paced:         2,
+		StartTime:     &lineItem.StartTime,
+		EndTime:       &lineItem.EndTime,
+		LastDelivery:  &clock.time,
+	}}, service.Report("account"))
+}
+
+func TestMatchFrequencyCaps(t *testing.T) {
+	clock := &fakeTime{time: flightStart}
+	service := NewService(clock, time.Hour)
+	account := &config.Account{ID: "account", LineItems: []config.AccountLineItem{{
+		ID:            "li",
+		Source:        "appnexus",
+		DealID:        "deal",
+		FrequencyCaps: []config.LineItemFrequencyCap{{Count: 2, PeriodSeconds: 3600}},
+	}}}
+
+	service.Match(account, newRequest(), "user")
+	deliver(service, "account", "li", "user")
+	deliver(service, "account", "li", "user")
+
+	matches, _ := service.Match(account, newRequest(), "user")
+	require.Len(t, matches, 1)
+	assert.Equal(t, StatusFrequencyCapped, matches[0].Status)
+
+	matches, _ = service.Match(account, newRequest(), "other-user")
+	require.Len(t, matches, 1)
+	assert.Equal(t, StatusInjected, matches[0].Status)
+
+	matches, _ = service.Match(account, newRequest(), "")
+	require.Len(t, matches, 1)
+	assert.Equal(t, StatusInjected, matches[0].Status, "unknown users are never capped")
+
+	clock.time = flightStart.Add(time.Hour + time.Second)
+	matches, _ = service.Match(account, newRequest(), "user")
+	require.Len(t, matches, 1)
+	assert.Equal(t, StatusInjected, matches[0].Status, "impressions outside the period no longer count")
+}
+
+func TestRecordImpression(t *testing.T) {
+	clock := &fakeTime{time: flightStart}
+	service := NewService(clock, time.Hour)
+	account := &config.Account{ID: "account", LineItems: []config.AccountLineItem{{
+		ID:            "li",
+		Source:        "appnexus",
+		DealID:        "deal",
+		FrequencyCaps: []config.LineItemFrequencyCap{{Count: 1, PeriodSeconds: 86400}},
+	}}}
+	service.Match(account, newRequest(), "")
+
+	delivered := func() int64 {
+		return service.Report("account")[0].Delivered
+	}
+
+	service.RecordImpression("account", "unknown", "user")
+	assert.Equal(t, int64(0), delivered(), "impressions of unknown bids are ignored")
+
+	service.RecordWin("account", "li", "bid-1", "auction-user")
+	assert.Equal(t, int64(0), delivered(), "wins are not deliveries")
+	service.RecordImpression("account", "bid-1", "event-user")
+	service.RecordImpression("account", "bid-1", "event-user")
+	assert.Equal(t, int64(1), delivered(), "the impression of a win is counted once")
+
+	matches, _ := service.Match(account, newRequest(), "auction-user")
+	assert.Equal(t, StatusFrequencyCapped, matches[0].Status, "the impression counts against the auction user")
+	matches, _ = service.Match(account, newRequest(), "event-user")
+	assert.Equal(t, StatusInjected, matches[0].Status)
+
+	service.RecordWin("account", "li", "bid-2", "")
+	service.RecordImpression("account", "bid-2", "event-user")
+	matches, _ = service.Match(account, newRequest(), "event-user")
+	assert.Equal(t, StatusFrequencyCapped, matches[0].Status, "the event user is used without an auction user")
+
+	service.RecordWin("account", "li", "bid-3", "")
+	clock.time = flightStart.Add(time.Hour)
+	service.RecordImpression("account", "bid-3", "")
+	assert.Equal(t, int64(2), delivered(), "impressions after the win ttl are ignored")
+}
+
+func TestRecordWinUnknownLineItem(t *testing.T) {
+	service := NewService(&fakeTime{time: flightStart}, time.Hour)
+	deliver(service, "account", "li", "user")
+	assert.Empty(t, service.Report("account"))
+}
+
+func TestExpire(t *testing.T) {
+	clock := &fakeTime{time: flightStart}
+	service := NewService(clock, time.Hour).(*service)
+	lineItems := []config.AccountLineItem{
+		{ID: "capped", Source: "appnexus", DealID: "deal", FrequencyCaps: []config.LineItemFrequencyCap{{Count: 5, PeriodSeconds: 600}}},
+		{ID: "ending", Source: "appnexus", DealID: "deal", EndTime: flightStart.Add(time.Hour)},
+	}
+	account := &config.Account{ID: "account", LineItems: lineItems}
+	service.Match(account, newRequest(), "")
+	deliver(service, "account", "capped", "user")
+	service.RecordWin("account", "capped", "pending", "user")
+
+	clock.time = flightStart.Add(90 * time.Minute)
+	service.Match(account, newRequest(), "")
+	assert.Empty(t, service.wins, "the win ttl passed")
+	assert.Empty(t, service.accounts["account"]["capped"].impressions, "the frequency cap period passed")
+	assert.Contains(t, service.accounts["account"], "ending", "impressions of the last wins may still arrive")
+
+	clock.time = flightStart.Add(3 * time.Hour)
+	service.Match(account, newRequest(), "")
+	assert.NotContains(t, service.accounts["account"], "ending", "the line item ended")
+	assert.Contains(t, service.accounts["account"], "capped")
+
+	clock.time = clock.time.Add(idleTimeout + time.Minute)
+	service.Match(&config.Account{ID: "other", LineItems: lineItems}, newRequest(), "")
+	assert.NotContains(t, service.accounts, "account", "the line items were not matched for a day")
+	assert.Contains(t, service.accounts, "other")
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name            string
+		lineItem        config.AccountLineItem
+		expectedProblem string
+	}{
+		{
+			name:     "valid",
+			lineItem: config.AccountLineItem{ID: "li", Source: "appnexus", DealID: "deal"},
+		},
+		{
+			name:            "missing-id",
+			lineItem:        config.AccountLineItem{Source: "appnexus", DealID: "deal"},
+			expectedProblem: `line item "" ignored: id is required`,
+		},
+		{
+			name:            "missing-source",
+			lineItem:        config.AccountLineItem{ID: "li", DealID: "deal"},
+			expectedProblem: `line item "li" ignored: source is required`,
+		},
+		{
+			name:            "creative-without-price",
+			lineItem:        config.AccountLineItem{ID: "li", Source: "appnexus", DealID: "deal", Creative: &config.LineItemCreative{}},
+			expectedProblem: `line item "li" ignored: price must be positive for line items with a creative`,
+		},
+		{
+			name:            "frequency-cap-without-period",
+			lineItem:        config.AccountLineItem{ID: "li", Source: "appnexus", DealID: "deal", FrequencyCaps: []config.LineItemFrequencyCap{{Count: 1}}},
+			expectedProblem: `line item "li" ignored: frequency caps require a positive count and period_sec`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			err := validate(test.lineItem)
+			if test.expectedProblem == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.expectedProblem)
+			}
+		})
+	}
+}
+
+func TestInjectDeals(t *testing.T) {
+	req := newRequest()
+	req.Imp[1].PMP = &openrtb2.PMP{Deals: []openrtb2.Deal{{ID: "existing"}}}
+
+	InjectDeals(req, []Match{
+		{LineItem: config.AccountLineItem{DealID: "deal-1", Source: "appnexus", Price: 1.5, Currency: "EUR"}, ImpID: "imp-video", Status: StatusInjected},
+		{LineItem: config.AccountLineItem{DealID: "existing", Source: "appnexus"}, ImpID: "imp-banner", Status: StatusInjected},
+		{LineItem: config.AccountLineItem{DealID: "deal-2", Source: "appnexus"}, ImpID: "imp-banner", Status: StatusPaced},
+		{LineItem: config.AccountLineItem{DealID: "deal-3", Source: "appnexus", Price: 2}, ImpID: "imp-banner", Status: StatusBid},
+	})
+	require.NoError(t, req.RebuildRequest())
+
+	assert.Equal(t, &openrtb2.PMP{Deals: []openrtb2.Deal{{ID: "deal-1", BidFloor: 1.5, BidFloorCur: "EUR", WSeat: []string{"appnexus"}}}}, req.Imp[0].PMP)
+	assert.Equal(t, &openrtb2.PMP{Deals: []openrtb2.Deal{{ID: "existing"}}}, req.Imp[1].PMP)
+}
+
+func TestMatchBid(t *testing.T) {
+	match := Match{
+		LineItem: config.AccountLineItem{
+			ID:     "li",
+			DealID: "deal",
+			Price:  3,
+			Creative: &config.LineItemCreative{
+				ID:        "creative",
+				AdM:       "<VAST/>",
+				W:         640,
+				H:         480,
+				ADomain:   []string{"advertiser.com"},
+				MediaType: "video",
+			},
+		},
+		ImpID: "imp",
+	}
+
+	bid, bidType := match.Bid()
+
+	assert.Equal(t, &openrtb2.Bid{
+		ID:      "li-imp",
+		ImpID:   "imp",
+		Price:   3,
+		AdM:     "<VAST/>",
+		ADomain: []string{"advertiser.com"},
+		CrID:    "creative",
+		DealID:  "deal",
+		W:       640,
+		H:       480,
+	}, bid)
+	assert.Equal(t, openrtb_ext.BidTypeVideo, bidType)
+}
@@ -0,0 +1,116 @@
+package lineitems
+
+import (
+	"strings"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+)
+
+// matchesRequest checks the request level targeting of a line item.
+func matchesRequest(targeting config.LineItemTargeting, req *openrtb_ext.RequestWrapper) bool {
+	if len(targeting.SiteDomains) > 0 {
+		if req.Site == nil || !containsFold(targeting.SiteDomains, siteDomain(req.Site)) {
+			return false
+		}
+	}
+	if len(targeting.AppBundles) > 0 {
+		if req.App == nil || !containsFold(targeting.AppBundles, req.App.Bundle) {
+			return false
+		}
+	}
+	if len(targeting.Countries) > 0 {
+		if req.Device == nil || req.Device.Geo == nil || !containsFold(targeting.Countries, req.Device.Geo.Country) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesImp checks the imp level targeting of a line item.
+func matchesImp(targeting config.LineItemTargeting, imp *openrtb_ext.ImpWrapper) bool {
+	if len(targeting.MediaTypes) > 0 && !hasMediaType(imp, targeting.MediaTypes) {
+		return false
+	}
+	if len(targeting.Sizes) > 0 && !hasSize(imp, targeting.Sizes) {
+		return false
+	}
+	if len(targeting.AdUnitCodes) > 0 && !containsFold(targeting.AdUnitCodes, imp.TagID) && !containsFold(targeting.AdUnitCodes, gpid(imp)) {
+		return false
+	}
+	return true
+}
+
+func siteDomain(site *openrtb2.Site) string {
+	if site.Domain != "" {
+		return site.Domain
+	}
+	if site.Publisher != nil {
+		return site.Publisher.Domain
+	}
+	return ""
+}
+
+func hasMediaType(imp *openrtb_ext.ImpWrapper, mediaTypes []string) bool {
+	for _, mediaType := range mediaTypes {
+		switch openrtb_ext.BidType(strings.ToLower(mediaType)) {
+		case openrtb_ext.BidTypeBanner:
+			if imp.Banner != nil {
+				return true
+			}
+		case openrtb_ext.BidTypeVideo:
+			if imp.Video != nil {
+				return true
+			}
+		case openrtb_ext.BidTypeAudio:
+			if imp.Audio != nil {
+				return true
+			}
+		case openrtb_ext.BidTypeNative:
+			if imp.Native != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasSize(imp *openrtb_ext.ImpWrapper, sizes []config.LineItemSize) bool {
+	for _, size := range sizes {
+		if imp.Banner != nil {
+			if imp.Banner.W != nil && imp.Banner.H != nil && *imp.Banner.W == size.W && *imp.Banner.H == size.H {
+				return true
+			}
+			for _, format := range imp.Banner.Format {
+				if format.W == size.W && format.H == size.H {
+					return true
+				}
+			}
+		}
+		if imp.Video != nil && imp.Video.W != nil && imp.Video.H != nil && *imp.Video.W == size.W && *imp.Video.H == size.H {
+			return true
+		}
+	}
+	return false
+}
+
+func gpid(imp *openrtb_ext.ImpWrapper) string {
+	impExt, err := imp.GetImpExt()
+	if err != nil {
+		return ""
+	}
+	return impExt.GetGpId()
+}
+
+func containsFold(values []string, value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"flag"
 	"net/http"
+	"os"
 	"path/filepath"
 	"runtime"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/currency"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/readiness"
 	"github.com/prebid/prebid-server/v3/router"
 	"github.com/prebid/prebid-server/v3/server"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
@@ -27,6 +29,13 @@ func init() {
 func main() {
 	flag.Parse() // required for glog flags and testing package flags
 
+	if flag.Arg(0) == gvlDownloadCommand {
+		if err := gvlDownload(flag.Args()[1:], os.Stdout); err != nil {
+			glog.Exitf("Unable to download the GDPR vendor lists: %v", err)
+		}
+		return
+	}
+
 	bidderInfoPath, err := filepath.Abs(infoDirectory)
 	if err != nil {
 		glog.Exitf("Unable to build configuration directory path: %v", err)
@@ -36,7 +45,7 @@ func main() {
 	if err != nil {
 		glog.Exitf("Unable to load bidder configurations: %v", err)
 	}
-	cfg, err := loadConfig(bidderInfos)
+	cfg, configFile, err := loadConfig(bidderInfos)
 	if err != nil {
 		glog.Exitf("Configuration could not be loaded or did not pass validation: %v", err)
 	}
@@ -49,7 +58,7 @@ func main() {
 	garbageCollectionThreshold := make([]byte, cfg.GarbageCollectorThreshold)
 	defer runtime.KeepAlive(garbageCollectionThreshold)
 
-	err = serve(cfg)
+	err = serve(cfg, bidderInfoPath, configFile)
 	if err != nil {
 		glog.Exitf("prebid-server failed: %v", err)
 	}
@@ -58,31 +67,56 @@ func main() {
 const configFileName = "pbs"
 const infoDirectory = "./static/bidder-info"
 
-func loadConfig(bidderInfos config.BidderInfos) (*config.Configuration, error) {
+// loadConfig returns the host configuration along with the path of the config file it was read from, if any.
+func loadConfig(bidderInfos config.BidderInfos) (*config.Configuration, string, error) {
 	v := viper.New()
 	config.SetupViper(v, configFileName, bidderInfos)
-	return config.New(v, bidderInfos, openrtb_ext.NormalizeBidderName)
+	cfg, err := config.New(v, bidderInfos, openrtb_ext.NormalizeBidderName)
+	return cfg, v.ConfigFileUsed(), err
 }
 
-func serve(cfg *config.Configuration) error {
+func serve(cfg *config.Configuration, bidderInfoPath, configFile string) error {
 	httpTimeout := time.Duration(cfg.CurrencyConverter.FetchTimeoutMilliseconds) * time.Millisecond
 	fetchingInterval := time.Duration(cfg.CurrencyConverter.FetchIntervalSeconds) * time.Second
 	staleRatesThreshold := time.Duration(cfg.CurrencyConverter.StaleRatesSeconds) * time.Second
 	currencyConverter := currency.NewRateConverter(&http.Client{}, httpTimeout, cfg.CurrencyConverter.FetchURL, staleRatesThreshold)
 
-	currencyConverterTickerTask := task.NewTickerTask(fetchingInterval, currencyConverter)
+	tracker := readiness.NewTracker()
+	tracker.WarmUpWithin(time.Duration(cfg.Readiness.WarmupTimeoutMS) * time.Millisecond)
+
+	currencyConverterTickerTask := task.NewTickerTask(fetchingInterval, tracker.RegisterRunner("currency_rates", currencyConverter))
 	currencyConverterTickerTask.Start()
 
-	r, err := router.New(cfg, currencyConverter)
+	r, err := router.New(cfg, currencyConverter, tracker)
 	if err != nil {
 		return err
 	}
 
+	var reloader *router.Reloader
+	if cfg.ConfigReload.Enabled {
+		reloader = r.NewReloader(func() (*config.Configuration, error) {
+			bidderInfos, err := config.LoadBidderInfoFromDisk(bidderInfoPath)
+			if err != nil {
+				return nil, err
+			}
+			cfg, _, err := loadConfig(bidderInfos)
+			return cfg, err
+		})
+
+		watchPaths := []string{bidderInfoPath}
+		if configFile != "" {
+			watchPaths = append(watchPaths, configFile)
+		}
+		if err := reloader.Start(watchPaths...); err != nil {
+			return err
+		}
+	}
+
 	corsRouter := router.SupportCORS(r)
-	if err := server.Listen(cfg, router.NoCache{Handler: corsRouter}, router.Admin(currencyConverter, fetchingInterval), r.MetricsEngine); err != nil {
+	if err := server.Listen(cfg, router.NoCache{Handler: corsRouter}, router.Admin(currencyConverter, fetchingInterval, r.LineItems, reloader, r.ModuleAdminEndpoints), r.MetricsEngine, tracker); err != nil {
 		glog.Fatalf("prebid-server returned an error: %v", err)
 	}
 
-	r.Shutdown()
+	r.ShutdownWithin(time.Duration(cfg.Shutdown.DrainTimeoutMS) * time.Millisecond)
 	return nil
 }
@@ -3,9 +3,11 @@ package config
 import (
 	"time"
 
+	"github.com/golang/glog"
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/metrics"
 	prometheusmetrics "github.com/prebid/prebid-server/v3/metrics/prometheus"
+	statsdmetrics "github.com/prebid/prebid-server/v3/metrics/statsd"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	gometrics "github.com/rcrowley/go-metrics"
 	influxdb "github.com/vrischmann/go-metrics-influxdb"
@@ -15,9 +17,9 @@ import (
 // for this instance.
 func NewMetricsEngine(cfg *config.Configuration, adapterList []openrtb_ext.BidderName, syncerKeys []string, moduleStageNames map[string][]string) *DetailedMetricsEngine {
 	// Create a list of metrics engines to use.
-	// Capacity of 2, as unlikely to have more than 2 metrics backends, and in the case
+	// Capacity of 3, as there are 3 metrics backends, and in the case
 	// of 1 we won't use the list so it will be garbage collected.
-	engineList := make(MultiMetricsEngine, 0, 2)
+	engineList := make(MultiMetricsEngine, 0, 3)
 	returnEngine := DetailedMetricsEngine{}
 
 	if cfg.Metrics.Influxdb.Host != "" {
@@ -43,6 +45,15 @@ func NewMetricsEngine(cfg *config.Configuration, adapterList []openrtb_ext.Bidde
 		returnEngine.PrometheusMetrics = prometheusmetrics.NewMetrics(cfg.Metrics.Prometheus, cfg.Metrics.Disabled, syncerKeys, moduleStageNames)
 		engineList = append(engineList, returnEngine.PrometheusMetrics)
 	}
+	if cfg.Metrics.StatsD.Host != "" {
+		// Set up the StatsD metrics.
+		statsdMetrics, err := statsdmetrics.NewMetrics(cfg.Metrics.StatsD, cfg.Metrics.Disabled)
+		if err != nil {
+			glog.Fatalf("Failed to connect to the StatsD agent %s: %v", cfg.Metrics.StatsD.Host, err)
+		}
+		returnEngine.StatsDMetrics = statsdMetrics
+		engineList = append(engineList, returnEngine.StatsDMetrics)
+	}
 
 	// Now return the proper metrics engine
 	if len(engineList) > 1 {
@@ -61,6 +72,14 @@ type DetailedMetricsEngine struct {
 	metrics.MetricsEngine
 	GoMetrics         *metrics.Metrics
 	PrometheusMetrics *prometheusmetrics.Metrics
+	StatsDMetrics     *statsdmetrics.Metrics
+}
+
+// Shutdown sends the metrics buffered for StatsD, if configured.
+func (me *DetailedMetricsEngine) Shutdown() {
+	if me.StatsDMetrics != nil {
+		me.StatsDMetrics.Close()
+	}
 }
 
 // MultiMetricsEngine logs metrics to multiple metrics databases The can be useful in transitioning
@@ -299,6 +318,12 @@ func (me *MultiMetricsEngine) RecordGvlListRequest() {
 	}
 }
 
+func (me *MultiMetricsEngine) RecordGvlListVersion(specVersion, listVersion uint16) {
+	for _, thisME := range *me {
+		thisME.RecordGvlListVersion(specVersion, listVersion)
+	}
+}
+
 func (me *MultiMetricsEngine) RecordAdsCertReq(success bool) {
 	for _, thisME := range *me {
 		thisME.RecordAdsCertReq(success)
@@ -335,6 +360,24 @@ func (me *MultiMetricsEngine) RecordBidValidationSecureMarkupWarn(adapter openrt
 	}
 }
 
+func (me *MultiMetricsEngine) RecordBidValidationSecureCreativeError(adapter openrtb_ext.BidderName, account string) {
+	for _, thisME := range *me {
+		thisME.RecordBidValidationSecureCreativeError(adapter, account)
+	}
+}
+
+func (me *MultiMetricsEngine) RecordBidValidationSecureCreativeWarn(adapter openrtb_ext.BidderName, account string) {
+	for _, thisME := range *me {
+		thisME.RecordBidValidationSecureCreativeWarn(adapter, account)
+	}
+}
+
+func (me *MultiMetricsEngine) RecordBidValidationSecureCreativeRewrite(adapter openrtb_ext.BidderName, account string) {
+	for _, thisME := range *me {
+		thisME.RecordBidValidationSecureCreativeRewrite(adapter, account)
+	}
+}
+
 func (me *MultiMetricsEngine) RecordModuleCalled(labels metrics.ModuleLabels, duration time.Duration) {
 	for _, thisME := range *me {
 		thisME.RecordModuleCalled(labels, duration)
@@ -396,6 +439,20 @@ func (me *MultiMetricsEngine) RecordAdapterConnectionDialTime(adapterName openrt
 	}
 }
 
+// RecordAdapterConnectionPoolExhausted across all engines
+func (me *MultiMetricsEngine) RecordAdapterConnectionPoolExhausted(adapterName openrtb_ext.BidderName) {
+	for _, thisME := range *me {
+		thisME.RecordAdapterConnectionPoolExhausted(adapterName)
+	}
+}
+
+// RecordAdapterPayloadSize across all engines
+func (me *MultiMetricsEngine) RecordAdapterPayloadSize(adapterName openrtb_ext.BidderName, direction metrics.PayloadDirection, rawBytes int, wireBytes int) {
+	for _, thisME := range *me {
+		thisME.RecordAdapterPayloadSize(adapterName, direction, rawBytes, wireBytes)
+	}
+}
+
 // NilMetricsEngine implements the MetricsEngine interface where no metrics are actually captured. This is
 // used if no metric backend is configured and also for tests.
 type NilMetricsEngine struct{}
@@ -534,6 +591,9 @@ func (me *NilMetricsEngine) RecordStoredResponse(pubId string) {
 func (me *NilMetricsEngine) RecordGvlListRequest() {
 }
 
+func (me *NilMetricsEngine) RecordGvlListVersion(specVersion, listVersion uint16) {
+}
+
 func (me *NilMetricsEngine) RecordAdsCertReq(success bool) {
 
 }
@@ -554,6 +614,15 @@ func (me *NilMetricsEngine) RecordBidValidationSecureMarkupError(adapter openrtb
 func (me *NilMetricsEngine) RecordBidValidationSecureMarkupWarn(adapter openrtb_ext.BidderName, account string) {
 }
 
+func (me *NilMetricsEngine) RecordBidValidationSecureCreativeError(adapter openrtb_ext.BidderName, account string) {
+}
+
+func (me *NilMetricsEngine) RecordBidValidationSecureCreativeWarn(adapter openrtb_ext.BidderName, account string) {
+}
+
+func (me *NilMetricsEngine) RecordBidValidationSecureCreativeRewrite(adapter openrtb_ext.BidderName, account string) {
+}
+
 func (me *NilMetricsEngine) RecordModuleCalled(labels metrics.ModuleLabels, duration time.Duration) {
 }
 
@@ -584,3 +653,11 @@ func (me *NilMetricsEngine) RecordAdapterConnectionDialError(adapterName openrtb
 
 func (me *NilMetricsEngine) RecordAdapterConnectionDialTime(adapterName openrtb_ext.BidderName, dialStartTime time.Duration) {
 }
+
+// RecordAdapterConnectionPoolExhausted as a noop
+func (me *NilMetricsEngine) RecordAdapterConnectionPoolExhausted(adapterName openrtb_ext.BidderName) {
+}
+
+// RecordAdapterPayloadSize as a noop
+func (me *NilMetricsEngine) RecordAdapterPayloadSize(adapterName openrtb_ext.BidderName, direction metrics.PayloadDirection, rawBytes int, wireBytes int) {
+}
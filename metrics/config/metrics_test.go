@@ -39,6 +39,34 @@ func TestGoMetricsEngine(t *testing.T) {
 	}
 }
 
+func TestStatsDMetricsEngine(t *testing.T) {
+	cfg := mainConfig.Configuration{}
+	cfg.Metrics.StatsD = mainConfig.StatsDMetrics{Host: "127.0.0.1:8125", MaxPacketSize: 1432, FlushIntervalMS: 100}
+	adapterList := make([]openrtb_ext.BidderName, 0, 2)
+	syncerKeys := []string{"keyA", "keyB"}
+	testEngine := NewMetricsEngine(&cfg, adapterList, syncerKeys, modulesStages)
+	defer testEngine.Shutdown()
+	if testEngine.StatsDMetrics == nil || testEngine.MetricsEngine != testEngine.StatsDMetrics {
+		t.Error("Expected StatsD Metrics as MetricsEngine, but didn't get it")
+	}
+}
+
+func TestAllMetricsEngines(t *testing.T) {
+	cfg := mainConfig.Configuration{}
+	cfg.Metrics.Influxdb.Host = "localhost"
+	cfg.Metrics.Prometheus.Port = 8080
+	cfg.Metrics.StatsD = mainConfig.StatsDMetrics{Host: "127.0.0.1:8125", MaxPacketSize: 1432, FlushIntervalMS: 100}
+	testEngine := NewMetricsEngine(&cfg, openrtb_ext.CoreBidderNames(), nil, modulesStages)
+	defer testEngine.Shutdown()
+	engines, ok := testEngine.MetricsEngine.(*MultiMetricsEngine)
+	if !ok {
+		t.Fatal("Expected a MultiMetricsEngine, but didn't get it")
+	}
+	if len(*engines) != 3 {
+		t.Errorf("Expected 3 metrics engines, got %d", len(*engines))
+	}
+}
+
 func TestMultiMetricsEngine(t *testing.T) {
 	cfg := mainConfig.Configuration{}
 	cfg.Metrics.Influxdb.Host = "localhost"
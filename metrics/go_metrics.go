@@ -36,6 +36,7 @@ type Metrics struct {
 	BidderServerResponseTimer      metrics.Timer
 	StoredResponsesMeter           metrics.Meter
 	GvlListRequestsMeter           metrics.Meter
+	GvlListVersionGauge            map[uint16]metrics.Gauge
 
 	// Metrics for OpenRTB requests specifically
 	RequestStatuses       map[RequestType]map[RequestStatus]metrics.Meter
@@ -103,6 +104,9 @@ type AdapterMetrics struct {
 	ConnWaitTime       metrics.Timer
 	ConnDialErrors     metrics.Counter
 	ConnDialTime       metrics.Timer
+	ConnPoolExhausted  metrics.Meter
+	PayloadRawBytes    map[PayloadDirection]metrics.Counter
+	PayloadWireBytes   map[PayloadDirection]metrics.Counter
 	BuyerUIDScrubbed   metrics.Meter
 	GDPRRequestBlocked metrics.Meter
 	ThrottledMeter     metrics.Meter
@@ -112,6 +116,10 @@ type AdapterMetrics struct {
 
 	BidValidationSecureMarkupErrorMeter metrics.Meter
 	BidValidationSecureMarkupWarnMeter  metrics.Meter
+
+	BidValidationSecureCreativeErrorMeter   metrics.Meter
+	BidValidationSecureCreativeWarnMeter    metrics.Meter
+	BidValidationSecureCreativeRewriteMeter metrics.Meter
 }
 
 type MarkupDeliveryMetrics struct {
@@ -133,6 +141,10 @@ type accountMetrics struct {
 	bidValidationCreativeSizeWarnMeter metrics.Meter
 	bidValidationSecureMarkupMeter     metrics.Meter
 	bidValidationSecureMarkupWarnMeter metrics.Meter
+
+	bidValidationSecureCreativeErrorMeter   metrics.Meter
+	bidValidationSecureCreativeWarnMeter    metrics.Meter
+	bidValidationSecureCreativeRewriteMeter metrics.Meter
 }
 
 type ModuleMetrics struct {
@@ -188,6 +200,7 @@ func NewBlankMetrics(registry metrics.Registry, exchanges []string, disabledMetr
 		SyncerSetsMeter:                make(map[string]map[SyncerSetUidStatus]metrics.Meter),
 		StoredResponsesMeter:           blankMeter,
 		GvlListRequestsMeter:           blankMeter,
+		GvlListVersionGauge:            make(map[uint16]metrics.Gauge),
 
 		ImpsTypeBanner: blankMeter,
 		ImpsTypeVideo:  blankMeter,
@@ -314,6 +327,9 @@ func NewMetrics(registry metrics.Registry, exchanges []openrtb_ext.BidderName, d
 	newMetrics.PrebidCacheRequestTimerError = metrics.GetOrRegisterTimer("prebid_cache_request_time.err", registry)
 	newMetrics.StoredResponsesMeter = metrics.GetOrRegisterMeter("stored_responses", registry)
 	newMetrics.GvlListRequestsMeter = metrics.GetOrRegisterMeter("gvl_requests", registry)
+	for _, specVersion := range []uint16{2, 3} {
+		newMetrics.GvlListVersionGauge[specVersion] = metrics.GetOrRegisterGauge(fmt.Sprintf("gvl_list_version.v%d", specVersion), registry)
+	}
 	newMetrics.OverheadTimer = makeOverheadTimerMetrics(registry)
 	newMetrics.BidderServerResponseTimer = metrics.GetOrRegisterTimer("bidder_server_response_time_seconds", registry)
 
@@ -420,6 +436,7 @@ func makeBlankAdapterMetrics(disabledMetrics config.DisabledMetrics) *AdapterMet
 		PanicMeter:        blankMeter,
 		MarkupMetrics:     makeBlankBidMarkupMetrics(),
 		ThrottledMeter:    blankMeter,
+		ConnPoolExhausted: blankMeter,
 	}
 	if !disabledMetrics.AdapterConnectionMetrics {
 		newAdapter.ConnCreated = metrics.NilCounter{}
@@ -437,6 +454,12 @@ func makeBlankAdapterMetrics(disabledMetrics config.DisabledMetrics) *AdapterMet
 	for _, err := range AdapterErrors() {
 		newAdapter.ErrorMeters[err] = blankMeter
 	}
+	newAdapter.PayloadRawBytes = make(map[PayloadDirection]metrics.Counter)
+	newAdapter.PayloadWireBytes = make(map[PayloadDirection]metrics.Counter)
+	for _, direction := range PayloadDirections() {
+		newAdapter.PayloadRawBytes[direction] = metrics.NilCounter{}
+		newAdapter.PayloadWireBytes[direction] = metrics.NilCounter{}
+	}
 	return newAdapter
 }
 
@@ -504,6 +527,11 @@ func registerAdapterMetrics(registry metrics.Registry, adapterOrAccount string,
 	am.ConnWaitTime = metrics.GetOrRegisterTimer(fmt.Sprintf("%[1]s.%[2]s.connection_wait_time", adapterOrAccount, exchange), registry)
 	am.ConnDialErrors = metrics.GetOrRegisterCounter(fmt.Sprintf("%[1]s.%[2]s.connection_dial_err", adapterOrAccount, exchange), registry)
 	am.ConnDialTime = metrics.GetOrRegisterTimer(fmt.Sprintf("%[1]s.%[2]s.connection_dial_time", adapterOrAccount, exchange), registry)
+	am.ConnPoolExhausted = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.connection_pool_exhausted", adapterOrAccount, exchange), registry)
+	for _, direction := range PayloadDirections() {
+		am.PayloadRawBytes[direction] = metrics.GetOrRegisterCounter(fmt.Sprintf("%[1]s.%[2]s.bytes.%[3]s.raw", adapterOrAccount, exchange, direction), registry)
+		am.PayloadWireBytes[direction] = metrics.GetOrRegisterCounter(fmt.Sprintf("%[1]s.%[2]s.bytes.%[3]s.wire", adapterOrAccount, exchange, direction), registry)
+	}
 
 	for err := range am.ErrorMeters {
 		am.ErrorMeters[err] = metrics.GetOrRegisterMeter(fmt.Sprintf("%s.%s.requests.%s", adapterOrAccount, exchange, err), registry)
@@ -521,6 +549,10 @@ func registerAdapterMetrics(registry metrics.Registry, adapterOrAccount string,
 
 	am.BidValidationSecureMarkupErrorMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.response.validation.secure.err", adapterOrAccount, exchange), registry)
 	am.BidValidationSecureMarkupWarnMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.response.validation.secure.warn", adapterOrAccount, exchange), registry)
+
+	am.BidValidationSecureCreativeErrorMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.response.validation.secure_creative.err", adapterOrAccount, exchange), registry)
+	am.BidValidationSecureCreativeWarnMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.response.validation.secure_creative.warn", adapterOrAccount, exchange), registry)
+	am.BidValidationSecureCreativeRewriteMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.response.validation.secure_creative.rewrite", adapterOrAccount, exchange), registry)
 }
 
 func registerModuleMetrics(registry metrics.Registry, module string, stages []string, mm map[string]*ModuleMetrics) {
@@ -597,6 +629,10 @@ func (me *Metrics) getAccountMetrics(id string) *accountMetrics {
 	am.bidValidationSecureMarkupMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("account.%s.response.validation.secure.err", id), me.MetricsRegistry)
 	am.bidValidationSecureMarkupWarnMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("account.%s.response.validation.secure.warn", id), me.MetricsRegistry)
 
+	am.bidValidationSecureCreativeErrorMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("account.%s.response.validation.secure_creative.err", id), me.MetricsRegistry)
+	am.bidValidationSecureCreativeWarnMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("account.%s.response.validation.secure_creative.warn", id), me.MetricsRegistry)
+	am.bidValidationSecureCreativeRewriteMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("account.%s.response.validation.secure_creative.rewrite", id), me.MetricsRegistry)
+
 	if !me.MetricsDisabled.AccountModulesMetrics {
 		for _, mod := range me.modules {
 			am.moduleMetrics[mod] = makeBlankModuleMetrics()
@@ -658,6 +694,12 @@ func (me *Metrics) RecordGvlListRequest() {
 	me.GvlListRequestsMeter.Mark(1)
 }
 
+func (me *Metrics) RecordGvlListVersion(specVersion, listVersion uint16) {
+	if gauge, ok := me.GvlListVersionGauge[specVersion]; ok {
+		gauge.Update(int64(listVersion))
+	}
+}
+
 func (me *Metrics) RecordImps(labels ImpLabels) {
 	me.ImpMeter.Mark(int64(1))
 	if labels.BannerImps {
@@ -1075,6 +1117,51 @@ func (me *Metrics) RecordBidValidationSecureMarkupWarn(adapter openrtb_ext.Bidde
 	}
 }
 
+func (me *Metrics) RecordBidValidationSecureCreativeError(adapter openrtb_ext.BidderName, pubID string) {
+	adapterStr := string(adapter)
+	am, ok := me.AdapterMetrics[strings.ToLower(adapterStr)]
+	if !ok {
+		glog.Errorf("Trying to run adapter metrics on %s: adapter metrics not found", adapterStr)
+		return
+	}
+	am.BidValidationSecureCreativeErrorMeter.Mark(1)
+
+	aam := me.getAccountMetrics(pubID)
+	if !me.MetricsDisabled.AccountAdapterDetails {
+		aam.bidValidationSecureCreativeErrorMeter.Mark(1)
+	}
+}
+
+func (me *Metrics) RecordBidValidationSecureCreativeWarn(adapter openrtb_ext.BidderName, pubID string) {
+	adapterStr := string(adapter)
+	am, ok := me.AdapterMetrics[strings.ToLower(adapterStr)]
+	if !ok {
+		glog.Errorf("Trying to run adapter metrics on %s: adapter metrics not found", adapterStr)
+		return
+	}
+	am.BidValidationSecureCreativeWarnMeter.Mark(1)
+
+	aam := me.getAccountMetrics(pubID)
+	if !me.MetricsDisabled.AccountAdapterDetails {
+		aam.bidValidationSecureCreativeWarnMeter.Mark(1)
+	}
+}
+
+func (me *Metrics) RecordBidValidationSecureCreativeRewrite(adapter openrtb_ext.BidderName, pubID string) {
+	adapterStr := string(adapter)
+	am, ok := me.AdapterMetrics[strings.ToLower(adapterStr)]
+	if !ok {
+		glog.Errorf("Trying to run adapter metrics on %s: adapter metrics not found", adapterStr)
+		return
+	}
+	am.BidValidationSecureCreativeRewriteMeter.Mark(1)
+
+	aam := me.getAccountMetrics(pubID)
+	if !me.MetricsDisabled.AccountAdapterDetails {
+		aam.bidValidationSecureCreativeRewriteMeter.Mark(1)
+	}
+}
+
 func (me *Metrics) RecordModuleCalled(labels ModuleLabels, duration time.Duration) {
 	mm, err := me.getModuleMetric(labels)
 	if err != nil {
@@ -1217,3 +1304,35 @@ func (me *Metrics) RecordAdapterThrottled(adapterName openrtb_ext.BidderName) {
 
 	am.ThrottledMeter.Mark(1)
 }
+
+func (me *Metrics) RecordAdapterConnectionPoolExhausted(adapterName openrtb_ext.BidderName) {
+	if me.MetricsDisabled.AdapterConnectionMetrics {
+		return
+	}
+
+	adapterStr := adapterName.String()
+	am, ok := me.AdapterMetrics[strings.ToLower(adapterStr)]
+	if !ok {
+		glog.Errorf("Trying to log adapter connection pool exhausted metric for %s: adapter not found", adapterStr)
+		return
+	}
+
+	am.ConnPoolExhausted.Mark(1)
+}
+
+// RecordAdapterPayloadSize tracks the uncompressed and on the wire size of the payloads exchanged with a bidder.
+func (me *Metrics) RecordAdapterPayloadSize(adapterName openrtb_ext.BidderName, direction PayloadDirection, rawBytes int, wireBytes int) {
+	adapterStr := adapterName.String()
+	am, ok := me.AdapterMetrics[strings.ToLower(adapterStr)]
+	if !ok {
+		glog.Errorf("Trying to log adapter payload size metric for %s: adapter not found", adapterStr)
+		return
+	}
+
+	if counter, ok := am.PayloadRawBytes[direction]; ok {
+		counter.Inc(int64(rawBytes))
+	}
+	if counter, ok := am.PayloadWireBytes[direction]; ok {
+		counter.Inc(int64(wireBytes))
+	}
+}
@@ -37,6 +37,8 @@ func TestNewMetrics(t *testing.T) {
 	ensureContains(t, registry, "setuid_requests.syncer_unknown", m.SetUidStatusMeter[SetUidSyncerUnknown])
 	ensureContains(t, registry, "stored_responses", m.StoredResponsesMeter)
 	ensureContains(t, registry, "gvl_requests", m.GvlListRequestsMeter)
+	ensureContains(t, registry, "gvl_list_version.v2", m.GvlListVersionGauge[2])
+	ensureContains(t, registry, "gvl_list_version.v3", m.GvlListVersionGauge[3])
 
 	ensureContains(t, registry, "prebid_cache_request_time.ok", m.PrebidCacheRequestTimerSuccess)
 	ensureContains(t, registry, "prebid_cache_request_time.err", m.PrebidCacheRequestTimerError)
@@ -148,6 +150,9 @@ func ensureContainsAdapterMetrics(t *testing.T, registry metrics.Registry, name
 	ensureContains(t, registry, name+".response.validation.size.warn", adapterMetrics.BidValidationCreativeSizeWarnMeter)
 	ensureContains(t, registry, name+".response.validation.secure.err", adapterMetrics.BidValidationSecureMarkupErrorMeter)
 	ensureContains(t, registry, name+".response.validation.secure.warn", adapterMetrics.BidValidationSecureMarkupWarnMeter)
+	ensureContains(t, registry, name+".response.validation.secure_creative.err", adapterMetrics.BidValidationSecureCreativeErrorMeter)
+	ensureContains(t, registry, name+".response.validation.secure_creative.warn", adapterMetrics.BidValidationSecureCreativeWarnMeter)
+	ensureContains(t, registry, name+".response.validation.secure_creative.rewrite", adapterMetrics.BidValidationSecureCreativeRewriteMeter)
 
 }
 
@@ -382,6 +387,42 @@ func TestRecordBidValidationSecureMarkup(t *testing.T) {
 	}
 }
 
+func TestRecordBidValidationSecureCreative(t *testing.T) {
+	testCases := []struct {
+		description          string
+		givenDisabledMetrics config.DisabledMetrics
+		expectedAccountCount int64
+	}{
+		{
+			description:          "account-metrics-enabled",
+			expectedAccountCount: 1,
+		},
+		{
+			description:          "account-metrics-disabled",
+			givenDisabledMetrics: config.DisabledMetrics{AccountAdapterDetails: true},
+			expectedAccountCount: 0,
+		},
+	}
+	adapter := openrtb_ext.BidderName("AnyName")
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			m := NewMetrics(metrics.NewRegistry(), []openrtb_ext.BidderName{adapter}, test.givenDisabledMetrics, nil, nil)
+
+			m.RecordBidValidationSecureCreativeError(adapter, "acct-id")
+			m.RecordBidValidationSecureCreativeWarn(adapter, "acct-id")
+			m.RecordBidValidationSecureCreativeRewrite(adapter, "acct-id")
+			am := m.getAccountMetrics("acct-id")
+
+			assert.Equal(t, int64(1), m.AdapterMetrics["anyname"].BidValidationSecureCreativeErrorMeter.Count())
+			assert.Equal(t, int64(1), m.AdapterMetrics["anyname"].BidValidationSecureCreativeWarnMeter.Count())
+			assert.Equal(t, int64(1), m.AdapterMetrics["anyname"].BidValidationSecureCreativeRewriteMeter.Count())
+			assert.Equal(t, test.expectedAccountCount, am.bidValidationSecureCreativeErrorMeter.Count())
+			assert.Equal(t, test.expectedAccountCount, am.bidValidationSecureCreativeWarnMeter.Count())
+			assert.Equal(t, test.expectedAccountCount, am.bidValidationSecureCreativeRewriteMeter.Count())
+		})
+	}
+}
+
 func TestRecordDNSTime(t *testing.T) {
 	testCases := []struct {
 		description         string
@@ -1001,6 +1042,17 @@ func TestStoredResponses(t *testing.T) {
 	}
 }
 
+func TestRecordGvlListVersion(t *testing.T) {
+	m := NewMetrics(metrics.NewRegistry(), nil, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordGvlListVersion(3, 42)
+	m.RecordGvlListVersion(4, 1)
+
+	assert.Equal(t, int64(42), m.GvlListVersionGauge[3].Value())
+	assert.Equal(t, int64(0), m.GvlListVersionGauge[2].Value())
+	assert.NotContains(t, m.GvlListVersionGauge, uint16(4), "unknown spec versions are ignored")
+}
+
 func TestRecordAdsCertSignTime(t *testing.T) {
 	testCases := []struct {
 		description           string
@@ -1317,3 +1369,53 @@ func TestRecordAdapterRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestRecordAdapterConnectionPoolExhausted(t *testing.T) {
+	adapter := "AnyName"
+	lowerCaseAdapterName := "anyname"
+
+	tests := []struct {
+		name            string
+		metricsDisabled bool
+		expectedCount   int64
+	}{
+		{
+			name:            "enabled",
+			metricsDisabled: false,
+			expectedCount:   1,
+		},
+		{
+			name:            "disabled",
+			metricsDisabled: true,
+			expectedCount:   0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := metrics.NewRegistry()
+			m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderName(adapter)}, config.DisabledMetrics{AdapterConnectionMetrics: tt.metricsDisabled}, nil, nil)
+
+			m.RecordAdapterConnectionPoolExhausted(openrtb_ext.BidderName(adapter))
+
+			assert.Equal(t, tt.expectedCount, m.AdapterMetrics[lowerCaseAdapterName].ConnPoolExhausted.Count())
+		})
+	}
+}
+
+func TestRecordAdapterPayloadSize(t *testing.T) {
+	adapter := "AnyName"
+	lowerCaseAdapterName := "anyname"
+
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderName(adapter)}, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordAdapterPayloadSize(openrtb_ext.BidderName(adapter), PayloadDirectionRequest, 1000, 200)
+	m.RecordAdapterPayloadSize(openrtb_ext.BidderName(adapter), PayloadDirectionResponse, 500, 100)
+	m.RecordAdapterPayloadSize(openrtb_ext.BidderName(adapter), PayloadDirectionResponse, 500, 100)
+
+	am := m.AdapterMetrics[lowerCaseAdapterName]
+	assert.Equal(t, int64(1000), am.PayloadRawBytes[PayloadDirectionRequest].Count())
+	assert.Equal(t, int64(200), am.PayloadWireBytes[PayloadDirectionRequest].Count())
+	assert.Equal(t, int64(1000), am.PayloadRawBytes[PayloadDirectionResponse].Count())
+	assert.Equal(t, int64(200), am.PayloadWireBytes[PayloadDirectionResponse].Count())
+}
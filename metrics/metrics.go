@@ -47,6 +47,18 @@ func OverheadTypes() []OverheadType {
 	return []OverheadType{PreBidder, MakeAuctionResponse, MakeBidderRequests}
 }
 
+// PayloadDirection identifies whether a payload was sent to or received from a bidder.
+type PayloadDirection string
+
+const (
+	PayloadDirectionRequest  PayloadDirection = "request"
+	PayloadDirectionResponse PayloadDirection = "response"
+)
+
+func PayloadDirections() []PayloadDirection {
+	return []PayloadDirection{PayloadDirectionRequest, PayloadDirectionResponse}
+}
+
 // ImpLabels defines metric labels describing the impression type.
 type ImpLabels struct {
 	BannerImps bool
@@ -497,12 +509,16 @@ type MetricsEngine interface {
 	RecordDebugRequest(debugEnabled bool, pubId string)
 	RecordStoredResponse(pubId string)
 	RecordGvlListRequest()
+	RecordGvlListVersion(specVersion, listVersion uint16)
 	RecordAdsCertReq(success bool)
 	RecordAdsCertSignTime(adsCertSignTime time.Duration)
 	RecordBidValidationCreativeSizeError(adapter openrtb_ext.BidderName, account string)
 	RecordBidValidationCreativeSizeWarn(adapter openrtb_ext.BidderName, account string)
 	RecordBidValidationSecureMarkupError(adapter openrtb_ext.BidderName, account string)
 	RecordBidValidationSecureMarkupWarn(adapter openrtb_ext.BidderName, account string)
+	RecordBidValidationSecureCreativeError(adapter openrtb_ext.BidderName, account string)
+	RecordBidValidationSecureCreativeWarn(adapter openrtb_ext.BidderName, account string)
+	RecordBidValidationSecureCreativeRewrite(adapter openrtb_ext.BidderName, account string)
 	RecordModuleCalled(labels ModuleLabels, duration time.Duration)
 	RecordModuleFailed(labels ModuleLabels)
 	RecordModuleSuccessNooped(labels ModuleLabels)
@@ -513,4 +529,6 @@ type MetricsEngine interface {
 	RecordAdapterThrottled(adapterName openrtb_ext.BidderName)
 	RecordAdapterConnectionDialError(adapterName openrtb_ext.BidderName)
 	RecordAdapterConnectionDialTime(adapterName openrtb_ext.BidderName, dialStartTime time.Duration)
+	RecordAdapterConnectionPoolExhausted(adapterName openrtb_ext.BidderName)
+	RecordAdapterPayloadSize(adapterName openrtb_ext.BidderName, direction PayloadDirection, rawBytes int, wireBytes int)
 }
@@ -179,6 +179,10 @@ func (me *MetricsEngineMock) RecordGvlListRequest() {
 	me.Called()
 }
 
+func (me *MetricsEngineMock) RecordGvlListVersion(specVersion, listVersion uint16) {
+	me.Called(specVersion, listVersion)
+}
+
 func (me *MetricsEngineMock) RecordAdsCertReq(success bool) {
 	me.Called(success)
 }
@@ -203,6 +207,18 @@ func (me *MetricsEngineMock) RecordBidValidationSecureMarkupWarn(adapter openrtb
 	me.Called(adapter, account)
 }
 
+func (me *MetricsEngineMock) RecordBidValidationSecureCreativeError(adapter openrtb_ext.BidderName, account string) {
+	me.Called(adapter, account)
+}
+
+func (me *MetricsEngineMock) RecordBidValidationSecureCreativeWarn(adapter openrtb_ext.BidderName, account string) {
+	me.Called(adapter, account)
+}
+
+func (me *MetricsEngineMock) RecordBidValidationSecureCreativeRewrite(adapter openrtb_ext.BidderName, account string) {
+	me.Called(adapter, account)
+}
+
 func (me *MetricsEngineMock) RecordModuleCalled(labels ModuleLabels, duration time.Duration) {
 	me.Called(labels, duration)
 }
@@ -242,3 +258,11 @@ func (me *MetricsEngineMock) RecordAdapterConnectionDialError(adapterName openrt
 func (me *MetricsEngineMock) RecordAdapterConnectionDialTime(adapterName openrtb_ext.BidderName, dialStartTime time.Duration) {
 	me.Called(adapterName, dialStartTime)
 }
+
+func (me *MetricsEngineMock) RecordAdapterConnectionPoolExhausted(adapterName openrtb_ext.BidderName) {
+	me.Called(adapterName)
+}
+
+func (me *MetricsEngineMock) RecordAdapterPayloadSize(adapterName openrtb_ext.BidderName, direction PayloadDirection, rawBytes int, wireBytes int) {
+	me.Called(adapterName, direction, rawBytes, wireBytes)
+}
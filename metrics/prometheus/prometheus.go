@@ -55,6 +55,7 @@ type Metrics struct {
 	privacyTCF                   *prometheus.CounterVec
 	storedResponses              prometheus.Counter
 	gvlListRequests              prometheus.Counter
+	gvlListVersion               *prometheus.GaugeVec
 	storedResponsesFetchTimer    *prometheus.HistogramVec
 	storedResponsesErrors        *prometheus.CounterVec
 	adsCertRequests              *prometheus.CounterVec
@@ -62,38 +63,47 @@ type Metrics struct {
 	bidderServerResponseTimer    prometheus.Histogram
 
 	// Adapter Metrics
-	adapterBids                           *prometheus.CounterVec
-	adapterErrors                         *prometheus.CounterVec
-	adapterPanics                         *prometheus.CounterVec
-	adapterPrices                         *prometheus.HistogramVec
-	adapterRequests                       *prometheus.CounterVec
-	overheadTimer                         *prometheus.HistogramVec
-	adapterRequestsTimer                  *prometheus.HistogramVec
-	adapterReusedConnections              *prometheus.CounterVec
-	adapterCreatedConnections             *prometheus.CounterVec
-	adapterConnectionWaitTime             *prometheus.HistogramVec
-	adapterScrubbedBuyerUIDs              *prometheus.CounterVec
-	adapterGDPRBlockedRequests            *prometheus.CounterVec
-	adapterBidResponseValidationSizeError *prometheus.CounterVec
-	adapterBidResponseValidationSizeWarn  *prometheus.CounterVec
-	adapterBidResponseSecureMarkupError   *prometheus.CounterVec
-	adapterBidResponseSecureMarkupWarn    *prometheus.CounterVec
-	adapterThrottled                      *prometheus.CounterVec
-	adapterConnectionDialErrors           *prometheus.CounterVec
-	adapterConnectionDialTime             *prometheus.HistogramVec
+	adapterBids                             *prometheus.CounterVec
+	adapterErrors                           *prometheus.CounterVec
+	adapterPanics                           *prometheus.CounterVec
+	adapterPrices                           *prometheus.HistogramVec
+	adapterRequests                         *prometheus.CounterVec
+	overheadTimer                           *prometheus.HistogramVec
+	adapterRequestsTimer                    *prometheus.HistogramVec
+	adapterReusedConnections                *prometheus.CounterVec
+	adapterCreatedConnections               *prometheus.CounterVec
+	adapterConnectionWaitTime               *prometheus.HistogramVec
+	adapterScrubbedBuyerUIDs                *prometheus.CounterVec
+	adapterGDPRBlockedRequests              *prometheus.CounterVec
+	adapterBidResponseValidationSizeError   *prometheus.CounterVec
+	adapterBidResponseValidationSizeWarn    *prometheus.CounterVec
+	adapterBidResponseSecureMarkupError     *prometheus.CounterVec
+	adapterBidResponseSecureMarkupWarn      *prometheus.CounterVec
+	adapterBidResponseSecureCreativeError   *prometheus.CounterVec
+	adapterBidResponseSecureCreativeWarn    *prometheus.CounterVec
+	adapterBidResponseSecureCreativeRewrite *prometheus.CounterVec
+	adapterThrottled                        *prometheus.CounterVec
+	adapterConnectionDialErrors             *prometheus.CounterVec
+	adapterConnectionDialTime               *prometheus.HistogramVec
+	adapterConnectionPoolExhausted          *prometheus.CounterVec
+	adapterPayloadRawBytes                  *prometheus.CounterVec
+	adapterPayloadWireBytes                 *prometheus.CounterVec
 
 	// Syncer Metrics
 	syncerRequests *prometheus.CounterVec
 	syncerSets     *prometheus.CounterVec
 
 	// Account Metrics
-	accountRequests                       *prometheus.CounterVec
-	accountDebugRequests                  *prometheus.CounterVec
-	accountStoredResponses                *prometheus.CounterVec
-	accountBidResponseValidationSizeError *prometheus.CounterVec
-	accountBidResponseValidationSizeWarn  *prometheus.CounterVec
-	accountBidResponseSecureMarkupError   *prometheus.CounterVec
-	accountBidResponseSecureMarkupWarn    *prometheus.CounterVec
+	accountRequests                         *prometheus.CounterVec
+	accountDebugRequests                    *prometheus.CounterVec
+	accountStoredResponses                  *prometheus.CounterVec
+	accountBidResponseValidationSizeError   *prometheus.CounterVec
+	accountBidResponseValidationSizeWarn    *prometheus.CounterVec
+	accountBidResponseSecureMarkupError     *prometheus.CounterVec
+	accountBidResponseSecureMarkupWarn      *prometheus.CounterVec
+	accountBidResponseSecureCreativeError   *prometheus.CounterVec
+	accountBidResponseSecureCreativeWarn    *prometheus.CounterVec
+	accountBidResponseSecureCreativeRewrite *prometheus.CounterVec
 
 	// Module Metrics as a map where the key is the module name
 	moduleDuration        map[string]*prometheus.HistogramVec
@@ -117,6 +127,7 @@ const (
 	cacheResultLabel     = "cache_result"
 	connectionErrorLabel = "connection_error"
 	cookieLabel          = "cookie"
+	directionLabel       = "direction"
 	hasBidsLabel         = "has_bids"
 	isAudioLabel         = "audio"
 	isBannerLabel        = "banner"
@@ -130,6 +141,7 @@ const (
 	requestTypeLabel     = "request_type"
 	requestEndpointLabel = "request_size"
 	stageLabel           = "stage"
+	specVersionLabel     = "spec_version"
 	statusLabel          = "status"
 	successLabel         = "success"
 	syncerLabel          = "syncer"
@@ -379,6 +391,11 @@ func NewMetrics(cfg config.PrometheusMetrics, disabledMetrics config.DisabledMet
 		"gvl_requests",
 		"Count number of times GVL list is fetched")
 
+	metrics.gvlListVersion = newGauge(cfg, reg,
+		"gvl_list_version",
+		"Latest GVL list version loaded labeled by GVL specification version.",
+		[]string{specVersionLabel})
+
 	metrics.adapterBids = newCounter(cfg, reg,
 		"adapter_bids",
 		"Count of bids labeled by adapter and markup delivery type (adm or nurl).",
@@ -422,6 +439,11 @@ func NewMetrics(cfg config.PrometheusMetrics, disabledMetrics config.DisabledMet
 			[]string{adapterLabel},
 			standardTimeBuckets)
 
+		metrics.adapterConnectionPoolExhausted = newCounter(cfg, reg,
+			"adapter_connection_pool_exhausted",
+			"Count of requests started while the adapter connections to a host were at the configured per-host limit.",
+			[]string{adapterLabel})
+
 		if !metrics.metricsDisabled.AdapterConnectionDialMetrics {
 			metrics.adapterConnectionDialErrors = newCounter(cfg, reg,
 				"adapter_connection_dial_errors",
@@ -456,6 +478,31 @@ func NewMetrics(cfg config.PrometheusMetrics, disabledMetrics config.DisabledMet
 		"Count that tracks number of bids removed from bid response that had a invalid bidAdm (warn)",
 		[]string{adapterLabel, successLabel})
 
+	metrics.adapterBidResponseSecureCreativeError = newCounter(cfg, reg,
+		"adapter_response_validation_secure_creative_err",
+		"Count that tracks number of bids with insecure URLs for secure imps (enforce)",
+		[]string{adapterLabel, successLabel})
+
+	metrics.adapterBidResponseSecureCreativeWarn = newCounter(cfg, reg,
+		"adapter_response_validation_secure_creative_warn",
+		"Count that tracks number of bids with insecure URLs for secure imps (warn)",
+		[]string{adapterLabel, successLabel})
+
+	metrics.adapterBidResponseSecureCreativeRewrite = newCounter(cfg, reg,
+		"adapter_response_validation_secure_creative_rewrite",
+		"Count that tracks number of bids with insecure URLs for secure imps (rewrite)",
+		[]string{adapterLabel, successLabel})
+
+	metrics.adapterPayloadRawBytes = newCounter(cfg, reg,
+		"adapter_payload_raw_bytes",
+		"Uncompressed bytes exchanged with adapter bidder endpoints labeled by adapter and direction.",
+		[]string{adapterLabel, directionLabel})
+
+	metrics.adapterPayloadWireBytes = newCounter(cfg, reg,
+		"adapter_payload_wire_bytes",
+		"Bytes exchanged on the wire with adapter bidder endpoints, after compression, labeled by adapter and direction.",
+		[]string{adapterLabel, directionLabel})
+
 	metrics.adapterThrottled = newCounter(cfg, reg,
 		"adapter_throttled",
 		"Count of requests throttled labeled by adapter.",
@@ -518,6 +565,21 @@ func NewMetrics(cfg config.PrometheusMetrics, disabledMetrics config.DisabledMet
 		"Count that tracks number of bids removed from bid response that had a invalid bidAdm labeled by account (warn)",
 		[]string{accountLabel, successLabel})
 
+	metrics.accountBidResponseSecureCreativeError = newCounter(cfg, reg,
+		"account_response_validation_secure_creative_err",
+		"Count that tracks number of bids with insecure URLs for secure imps labeled by account (enforce)",
+		[]string{accountLabel, successLabel})
+
+	metrics.accountBidResponseSecureCreativeWarn = newCounter(cfg, reg,
+		"account_response_validation_secure_creative_warn",
+		"Count that tracks number of bids with insecure URLs for secure imps labeled by account (warn)",
+		[]string{accountLabel, successLabel})
+
+	metrics.accountBidResponseSecureCreativeRewrite = newCounter(cfg, reg,
+		"account_response_validation_secure_creative_rewrite",
+		"Count that tracks number of bids with insecure URLs for secure imps labeled by account (rewrite)",
+		[]string{accountLabel, successLabel})
+
 	metrics.requestsQueueTimer = newHistogramVec(cfg, reg,
 		"request_queue_time",
 		"Seconds request was waiting in queue",
@@ -639,6 +701,18 @@ func newCounterWithoutLabels(cfg config.PrometheusMetrics, registry *prometheus.
 	return counter
 }
 
+func newGauge(cfg config.PrometheusMetrics, registry *prometheus.Registry, name, help string, labels []string) *prometheus.GaugeVec {
+	opts := prometheus.GaugeOpts{
+		Namespace: cfg.Namespace,
+		Subsystem: cfg.Subsystem,
+		Name:      name,
+		Help:      help,
+	}
+	gauge := prometheus.NewGaugeVec(opts, labels)
+	registry.MustRegister(gauge)
+	return gauge
+}
+
 func newHistogramVec(cfg config.PrometheusMetrics, registry *prometheus.Registry, name, help string, labels []string, buckets []float64) *prometheus.HistogramVec {
 	opts := prometheus.HistogramOpts{
 		Namespace: cfg.Namespace,
@@ -739,6 +813,12 @@ func (m *Metrics) RecordGvlListRequest() {
 	m.gvlListRequests.Inc()
 }
 
+func (m *Metrics) RecordGvlListVersion(specVersion, listVersion uint16) {
+	m.gvlListVersion.With(prometheus.Labels{
+		specVersionLabel: strconv.Itoa(int(specVersion)),
+	}).Set(float64(listVersion))
+}
+
 func (m *Metrics) RecordImps(labels metrics.ImpLabels) {
 	m.impressions.With(prometheus.Labels{
 		isBannerLabel: strconv.FormatBool(labels.BannerImps),
@@ -1089,6 +1169,42 @@ func (m *Metrics) RecordBidValidationSecureMarkupWarn(adapter openrtb_ext.Bidder
 	}
 }
 
+func (m *Metrics) RecordBidValidationSecureCreativeError(adapter openrtb_ext.BidderName, account string) {
+	m.adapterBidResponseSecureCreativeError.With(prometheus.Labels{
+		adapterLabel: strings.ToLower(string(adapter)), successLabel: successLabel,
+	}).Inc()
+
+	if !m.metricsDisabled.AccountAdapterDetails && account != metrics.PublisherUnknown {
+		m.accountBidResponseSecureCreativeError.With(prometheus.Labels{
+			accountLabel: account, successLabel: successLabel,
+		}).Inc()
+	}
+}
+
+func (m *Metrics) RecordBidValidationSecureCreativeWarn(adapter openrtb_ext.BidderName, account string) {
+	m.adapterBidResponseSecureCreativeWarn.With(prometheus.Labels{
+		adapterLabel: strings.ToLower(string(adapter)), successLabel: successLabel,
+	}).Inc()
+
+	if !m.metricsDisabled.AccountAdapterDetails && account != metrics.PublisherUnknown {
+		m.accountBidResponseSecureCreativeWarn.With(prometheus.Labels{
+			accountLabel: account, successLabel: successLabel,
+		}).Inc()
+	}
+}
+
+func (m *Metrics) RecordBidValidationSecureCreativeRewrite(adapter openrtb_ext.BidderName, account string) {
+	m.adapterBidResponseSecureCreativeRewrite.With(prometheus.Labels{
+		adapterLabel: strings.ToLower(string(adapter)), successLabel: successLabel,
+	}).Inc()
+
+	if !m.metricsDisabled.AccountAdapterDetails && account != metrics.PublisherUnknown {
+		m.accountBidResponseSecureCreativeRewrite.With(prometheus.Labels{
+			accountLabel: account, successLabel: successLabel,
+		}).Inc()
+	}
+}
+
 func (m *Metrics) RecordModuleCalled(labels metrics.ModuleLabels, duration time.Duration) {
 	m.moduleCalls[labels.Module].With(prometheus.Labels{
 		stageLabel: labels.Stage,
@@ -1152,3 +1268,22 @@ func (m *Metrics) RecordAdapterConnectionDialTime(adapterName openrtb_ext.Bidder
 		adapterLabel: strings.ToLower(string(adapterName)),
 	}).Observe(dialStartTime.Seconds())
 }
+
+func (m *Metrics) RecordAdapterPayloadSize(adapterName openrtb_ext.BidderName, direction metrics.PayloadDirection, rawBytes int, wireBytes int) {
+	labels := prometheus.Labels{
+		adapterLabel:   strings.ToLower(string(adapterName)),
+		directionLabel: string(direction),
+	}
+	m.adapterPayloadRawBytes.With(labels).Add(float64(rawBytes))
+	m.adapterPayloadWireBytes.With(labels).Add(float64(wireBytes))
+}
+
+func (m *Metrics) RecordAdapterConnectionPoolExhausted(adapterName openrtb_ext.BidderName) {
+	if m.metricsDisabled.AdapterConnectionMetrics {
+		return
+	}
+
+	m.adapterConnectionPoolExhausted.With(prometheus.Labels{
+		adapterLabel: strings.ToLower(string(adapterName)),
+	}).Inc()
+}
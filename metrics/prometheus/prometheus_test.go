@@ -320,6 +320,44 @@ func TestBidValidationSecureMarkupMetric(t *testing.T) {
 	}
 }
 
+func TestBidValidationSecureCreativeMetric(t *testing.T) {
+	testCases := []struct {
+		description                        string
+		givenAccountAdapterMetricsDisabled bool
+		expectedAccountCount               float64
+	}{
+		{
+			description:          "account-metrics-enabled",
+			expectedAccountCount: 1,
+		},
+		{
+			description:                        "account-metrics-disabled",
+			givenAccountAdapterMetricsDisabled: true,
+			expectedAccountCount:               0,
+		},
+	}
+
+	adapterName := openrtb_ext.BidderName("AnyName")
+	adapterLabels := prometheus.Labels{adapterLabel: "anyname", successLabel: successLabel}
+	accountLabels := prometheus.Labels{accountLabel: "acct-id", successLabel: successLabel}
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			m := createMetricsForTesting()
+			m.metricsDisabled.AccountAdapterDetails = test.givenAccountAdapterMetricsDisabled
+			m.RecordBidValidationSecureCreativeError(adapterName, "acct-id")
+			m.RecordBidValidationSecureCreativeWarn(adapterName, "acct-id")
+			m.RecordBidValidationSecureCreativeRewrite(adapterName, "acct-id")
+
+			assertCounterVecValue(t, "", "Adapter Secure Creative Error", m.adapterBidResponseSecureCreativeError, 1, adapterLabels)
+			assertCounterVecValue(t, "", "Adapter Secure Creative Warn", m.adapterBidResponseSecureCreativeWarn, 1, adapterLabels)
+			assertCounterVecValue(t, "", "Adapter Secure Creative Rewrite", m.adapterBidResponseSecureCreativeRewrite, 1, adapterLabels)
+			assertCounterVecValue(t, "", "Account Secure Creative Error", m.accountBidResponseSecureCreativeError, test.expectedAccountCount, accountLabels)
+			assertCounterVecValue(t, "", "Account Secure Creative Warn", m.accountBidResponseSecureCreativeWarn, test.expectedAccountCount, accountLabels)
+			assertCounterVecValue(t, "", "Account Secure Creative Rewrite", m.accountBidResponseSecureCreativeRewrite, test.expectedAccountCount, accountLabels)
+		})
+	}
+}
+
 func TestRequestMetricWithoutCookie(t *testing.T) {
 	requestType := metrics.ReqTypeORTB2Web
 	performTest := func(m *Metrics, cookieFlag metrics.CookieFlag) {
@@ -1953,6 +1991,17 @@ func TestRecordGvlListRequest(t *testing.T) {
 	assertCounterValue(t, "Record instance of fetched GVL list", "success", m.gvlListRequests, 1.00)
 }
 
+func TestRecordGvlListVersion(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordGvlListVersion(3, 41)
+	m.RecordGvlListVersion(3, 42)
+
+	gauge := dto.Metric{}
+	m.gvlListVersion.With(prometheus.Labels{specVersionLabel: "3"}).Write(&gauge)
+	assert.Equal(t, float64(42), gauge.GetGauge().GetValue())
+}
+
 func TestRecordAdsCertReqMetric(t *testing.T) {
 	testCases := []struct {
 		description                  string
@@ -2087,3 +2136,45 @@ func TestRecordModuleMetrics(t *testing.T) {
 		}
 	}
 }
+
+func TestRecordAdapterConnectionPoolExhausted(t *testing.T) {
+	m := createMetricsForTesting()
+	adapterName := openrtb_ext.BidderName("AnyName")
+	lowerCasedAdapterName := "anyname"
+	m.RecordAdapterConnectionPoolExhausted(adapterName)
+
+	assertCounterVecValue(t,
+		"Increment adapter connection pool exhausted counter",
+		"adapter_connection_pool_exhausted",
+		m.adapterConnectionPoolExhausted,
+		1,
+		prometheus.Labels{
+			adapterLabel: lowerCasedAdapterName,
+		})
+}
+
+func TestRecordAdapterPayloadSize(t *testing.T) {
+	m := createMetricsForTesting()
+	adapterName := openrtb_ext.BidderName("AnyName")
+	lowerCasedAdapterName := "anyname"
+	m.RecordAdapterPayloadSize(adapterName, metrics.PayloadDirectionRequest, 1000, 200)
+
+	assertCounterVecValue(t,
+		"Increment adapter raw payload bytes",
+		"adapter_payload_raw_bytes",
+		m.adapterPayloadRawBytes,
+		1000,
+		prometheus.Labels{
+			adapterLabel:   lowerCasedAdapterName,
+			directionLabel: string(metrics.PayloadDirectionRequest),
+		})
+	assertCounterVecValue(t,
+		"Increment adapter wire payload bytes",
+		"adapter_payload_wire_bytes",
+		m.adapterPayloadWireBytes,
+		200,
+		prometheus.Labels{
+			adapterLabel:   lowerCasedAdapterName,
+			directionLabel: string(metrics.PayloadDirectionRequest),
+		})
+}
@@ -0,0 +1,146 @@
+package statsdmetrics
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tag is a DogStatsD tag. Tags play the role of Prometheus labels.
+type tag struct {
+	key   string
+	value string
+}
+
+// tagReplacer strips the characters which separate the parts of a DogStatsD datagram from tag values
+var tagReplacer = strings.NewReplacer("|", "_", ",", "_", "#", "_", "\n", "_")
+
+// client buffers metrics in DogStatsD format and writes them to the agent in datagrams of at most maxPacketSize
+// bytes. Writes are fire and forget, metrics are lost if the agent isn't listening.
+type client struct {
+	conn          net.Conn
+	namespace     string
+	globalTags    string
+	maxPacketSize int
+
+	mu     sync.Mutex
+	buffer []byte
+
+	done   chan struct{}
+	closed sync.WaitGroup
+}
+
+func newClient(host, namespace string, globalTags []string, maxPacketSize int, flushInterval time.Duration) (*client, error) {
+	conn, err := net.Dial("udp", host)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &client{
+		conn:          conn,
+		namespace:     namespace,
+		globalTags:    tagReplacer.Replace(strings.Join(globalTags, ",")),
+		maxPacketSize: maxPacketSize,
+		buffer:        make([]byte, 0, maxPacketSize),
+		done:          make(chan struct{}),
+	}
+
+	c.closed.Add(1)
+	go c.flushPeriodically(flushInterval)
+	return c, nil
+}
+
+func (c *client) count(name string, value int64, tags ...tag) {
+	c.send(name, strconv.FormatInt(value, 10), "c", tags)
+}
+
+func (c *client) gauge(name string, value float64, tags ...tag) {
+	c.send(name, strconv.FormatFloat(value, 'f', -1, 64), "g", tags)
+}
+
+func (c *client) timing(name string, duration time.Duration, tags ...tag) {
+	c.send(name, strconv.FormatFloat(float64(duration)/float64(time.Millisecond), 'f', -1, 64), "ms", tags)
+}
+
+func (c *client) histogram(name string, value float64, tags ...tag) {
+	c.send(name, strconv.FormatFloat(value, 'f', -1, 64), "h", tags)
+}
+
+func (c *client) send(name, value, metricType string, tags []tag) {
+	var line strings.Builder
+	line.WriteString(c.namespace)
+	line.WriteString(name)
+	line.WriteByte(':')
+	line.WriteString(value)
+	line.WriteByte('|')
+	line.WriteString(metricType)
+
+	if len(tags) > 0 || c.globalTags != "" {
+		line.WriteString("|#")
+		for i, t := range tags {
+			if i > 0 {
+				line.WriteByte(',')
+			}
+			line.WriteString(t.key)
+			line.WriteByte(':')
+			line.WriteString(tagReplacer.Replace(t.value))
+		}
+		if c.globalTags != "" {
+			if len(tags) > 0 {
+				line.WriteByte(',')
+			}
+			line.WriteString(c.globalTags)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.buffer) > 0 && len(c.buffer)+1+line.Len() > c.maxPacketSize {
+		c.flushLocked()
+	}
+	if len(c.buffer) > 0 {
+		c.buffer = append(c.buffer, '\n')
+	}
+	c.buffer = append(c.buffer, line.String()...)
+}
+
+func (c *client) flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.flushLocked()
+}
+
+func (c *client) flushLocked() {
+	if len(c.buffer) == 0 {
+		return
+	}
+	c.conn.Write(c.buffer)
+	c.buffer = c.buffer[:0]
+}
+
+func (c *client) flushPeriodically(interval time.Duration) {
+	defer c.closed.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.flush()
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// close sends the buffered metrics and releases the connection.
+func (c *client) close() {
+	close(c.done)
+	c.closed.Wait()
+	c.flush()
+	c.conn.Close()
+}
@@ -0,0 +1,439 @@
+package statsdmetrics
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+)
+
+// Metrics pushes the MetricsEngine metrics to a StatsD agent. Metric names and tags follow the Prometheus
+// metric and label names, except that timers are reported in milliseconds without the _seconds suffix.
+type Metrics struct {
+	client          *client
+	metricsDisabled config.DisabledMetrics
+}
+
+const (
+	accountLabel         = "account"
+	adapterErrorLabel    = "adapter_error"
+	adapterLabel         = "adapter"
+	cacheResultLabel     = "cache_result"
+	connectionErrorLabel = "connection_error"
+	cookieLabel          = "cookie"
+	directionLabel       = "direction"
+	hasBidsLabel         = "has_bids"
+	isAudioLabel         = "audio"
+	isBannerLabel        = "banner"
+	isNativeLabel        = "native"
+	isVideoLabel         = "video"
+	markupDeliveryLabel  = "delivery"
+	moduleLabel          = "module"
+	optOutLabel          = "opt_out"
+	overheadTypeLabel    = "overhead_type"
+	requestStatusLabel   = "request_status"
+	requestTypeLabel     = "request_type"
+	requestEndpointLabel = "request_size"
+	sourceLabel          = "source"
+	stageLabel           = "stage"
+	specVersionLabel     = "spec_version"
+	statusLabel          = "status"
+	storedDataErrorLabel = "stored_data_error"
+	storedDataFetchLabel = "stored_data_fetch_type"
+	successLabel         = "success"
+	syncerLabel          = "syncer"
+	versionLabel         = "version"
+)
+
+const (
+	connectionAcceptError = "accept"
+	connectionCloseError  = "close"
+	markupDeliveryAdm     = "adm"
+	markupDeliveryNurl    = "nurl"
+	requestSuccessLabel   = "requestAcceptedLabel"
+	requestRejectLabel    = "requestRejectedLabel"
+	requestSuccessful     = "ok"
+	requestFailed         = "failed"
+	sourceRequest         = "request"
+)
+
+// storedDataMetricNames holds the prefix of the fetch time and error metrics per stored data type
+var storedDataMetricNames = map[metrics.StoredDataType]string{
+	metrics.AccountDataType:  "stored_account",
+	metrics.AMPDataType:      "stored_amp",
+	metrics.CategoryDataType: "stored_category",
+	metrics.RequestDataType:  "stored_request",
+	metrics.VideoDataType:    "stored_video",
+	metrics.ResponseDataType: "stored_response",
+}
+
+// NewMetrics connects to the StatsD agent. Metrics are buffered and sent every flush interval, Close sends
+// whatever is left.
+func NewMetrics(cfg config.StatsDMetrics, disabledMetrics config.DisabledMetrics) (*Metrics, error) {
+	client, err := newClient(cfg.Host, cfg.Namespace, cfg.Tags, cfg.MaxPacketSize, cfg.FlushInterval())
+	if err != nil {
+		return nil, err
+	}
+	return &Metrics{client: client, metricsDisabled: disabledMetrics}, nil
+}
+
+// Close sends the buffered metrics to the agent.
+func (m *Metrics) Close() {
+	m.client.close()
+}
+
+func (m *Metrics) RecordConnectionAccept(success bool) {
+	if success {
+		m.client.count("connections_opened", 1)
+	} else {
+		m.client.count("connections_error", 1, tag{connectionErrorLabel, connectionAcceptError})
+	}
+}
+
+func (m *Metrics) RecordTMaxTimeout() {
+	m.client.count("tmax_timeout", 1)
+}
+
+func (m *Metrics) RecordConnectionClose(success bool) {
+	if success {
+		m.client.count("connections_closed", 1)
+	} else {
+		m.client.count("connections_error", 1, tag{connectionErrorLabel, connectionCloseError})
+	}
+}
+
+func (m *Metrics) RecordRequest(labels metrics.Labels) {
+	m.client.count("requests", 1,
+		tag{requestTypeLabel, string(labels.RType)},
+		tag{requestStatusLabel, string(labels.RequestStatus)})
+
+	if labels.RequestSize > 0 && labels.RType != metrics.ReqTypeAMP {
+		endpoint := metrics.GetEndpointFromRequestType(labels.RType)
+		m.client.histogram("request_size_bytes", float64(labels.RequestSize), tag{requestEndpointLabel, string(endpoint)})
+	}
+
+	if labels.CookieFlag == metrics.CookieFlagNo {
+		m.client.count("requests_without_cookie", 1, tag{requestTypeLabel, string(labels.RType)})
+	}
+
+	if labels.PubID != metrics.PublisherUnknown {
+		m.client.count("account_requests", 1, tag{accountLabel, labels.PubID})
+	}
+}
+
+func (m *Metrics) RecordDebugRequest(debugEnabled bool, pubID string) {
+	if debugEnabled {
+		m.client.count("debug_requests", 1)
+		if !m.metricsDisabled.AccountDebug && pubID != metrics.PublisherUnknown {
+			m.client.count("account_debug_requests", 1, tag{accountLabel, pubID})
+		}
+	}
+}
+
+func (m *Metrics) RecordStoredResponse(pubId string) {
+	m.client.count("stored_responses", 1)
+	if !m.metricsDisabled.AccountStoredResponses && pubId != metrics.PublisherUnknown {
+		m.client.count("account_stored_responses", 1, tag{accountLabel, pubId})
+	}
+}
+
+func (m *Metrics) RecordGvlListRequest() {
+	m.client.count("gvl_requests", 1)
+}
+
+func (m *Metrics) RecordGvlListVersion(specVersion, listVersion uint16) {
+	m.client.gauge("gvl_list_version", float64(listVersion), tag{specVersionLabel, strconv.Itoa(int(specVersion))})
+}
+
+func (m *Metrics) RecordImps(labels metrics.ImpLabels) {
+	m.client.count("impressions_requests", 1,
+		tag{isBannerLabel, strconv.FormatBool(labels.BannerImps)},
+		tag{isVideoLabel, strconv.FormatBool(labels.VideoImps)},
+		tag{isAudioLabel, strconv.FormatBool(labels.AudioImps)},
+		tag{isNativeLabel, strconv.FormatBool(labels.NativeImps)})
+}
+
+func (m *Metrics) RecordRequestTime(labels metrics.Labels, length time.Duration) {
+	if labels.RequestStatus == metrics.RequestStatusOK {
+		m.client.timing("request_time", length, tag{requestTypeLabel, string(labels.RType)})
+	}
+}
+
+func (m *Metrics) RecordStoredDataFetchTime(labels metrics.StoredDataLabels, length time.Duration) {
+	if name, ok := storedDataMetricNames[labels.DataType]; ok {
+		m.client.timing(name+"_fetch_time", length, tag{storedDataFetchLabel, string(labels.DataFetchType)})
+	}
+}
+
+func (m *Metrics) RecordStoredDataError(labels metrics.StoredDataLabels) {
+	if name, ok := storedDataMetricNames[labels.DataType]; ok {
+		m.client.count(name+"_errors", 1, tag{storedDataErrorLabel, string(labels.Error)})
+	}
+}
+
+func (m *Metrics) RecordAdapterRequest(labels metrics.AdapterLabels) {
+	adapter := strings.ToLower(string(labels.Adapter))
+	m.client.count("adapter_requests", 1,
+		tag{adapterLabel, adapter},
+		tag{cookieLabel, string(labels.CookieFlag)},
+		tag{hasBidsLabel, strconv.FormatBool(labels.AdapterBids == metrics.AdapterBidPresent)})
+
+	for err := range labels.AdapterErrors {
+		m.client.count("adapter_errors", 1, tag{adapterLabel, adapter}, tag{adapterErrorLabel, string(err)})
+	}
+}
+
+func (m *Metrics) RecordAdapterConnections(adapterName openrtb_ext.BidderName, connWasReused bool, connWaitTime time.Duration) {
+	if m.metricsDisabled.AdapterConnectionMetrics {
+		return
+	}
+
+	adapter := tag{adapterLabel, strings.ToLower(string(adapterName))}
+	if connWasReused {
+		m.client.count("adapter_connection_reused", 1, adapter)
+	} else {
+		m.client.count("adapter_connection_created", 1, adapter)
+	}
+	m.client.timing("adapter_connection_wait", connWaitTime, adapter)
+}
+
+func (m *Metrics) RecordDNSTime(dnsLookupTime time.Duration) {
+	m.client.timing("dns_lookup_time", dnsLookupTime)
+}
+
+func (m *Metrics) RecordTLSHandshakeTime(tlsHandshakeTime time.Duration) {
+	m.client.timing("tls_handshake_time", tlsHandshakeTime)
+}
+
+func (m *Metrics) RecordBidderServerResponseTime(bidderServerResponseTime time.Duration) {
+	m.client.timing("bidder_server_response_time", bidderServerResponseTime)
+}
+
+func (m *Metrics) RecordAdapterPanic(labels metrics.AdapterLabels) {
+	m.client.count("adapter_panics", 1, tag{adapterLabel, strings.ToLower(string(labels.Adapter))})
+}
+
+func (m *Metrics) RecordAdapterBidReceived(labels metrics.AdapterLabels, bidType openrtb_ext.BidType, hasAdm bool) {
+	markupDelivery := markupDeliveryNurl
+	if hasAdm {
+		markupDelivery = markupDeliveryAdm
+	}
+	m.client.count("adapter_bids", 1,
+		tag{adapterLabel, strings.ToLower(string(labels.Adapter))},
+		tag{markupDeliveryLabel, markupDelivery})
+}
+
+func (m *Metrics) RecordAdapterPrice(labels metrics.AdapterLabels, cpm float64) {
+	m.client.histogram("adapter_prices", cpm, tag{adapterLabel, strings.ToLower(string(labels.Adapter))})
+}
+
+func (m *Metrics) RecordOverheadTime(overhead metrics.OverheadType, duration time.Duration) {
+	m.client.timing("overhead_time", duration, tag{overheadTypeLabel, overhead.String()})
+}
+
+func (m *Metrics) RecordAdapterTime(labels metrics.AdapterLabels, length time.Duration) {
+	if len(labels.AdapterErrors) == 0 {
+		m.client.timing("adapter_request_time", length, tag{adapterLabel, strings.ToLower(string(labels.Adapter))})
+	}
+}
+
+func (m *Metrics) RecordCookieSync(status metrics.CookieSyncStatus) {
+	m.client.count("cookie_sync_requests", 1, tag{statusLabel, string(status)})
+}
+
+func (m *Metrics) RecordSyncerRequest(key string, status metrics.SyncerCookieSyncStatus) {
+	m.client.count("syncer_requests", 1, tag{syncerLabel, key}, tag{statusLabel, string(status)})
+}
+
+func (m *Metrics) RecordSetUid(status metrics.SetUidStatus) {
+	m.client.count("setuid_requests", 1, tag{statusLabel, string(status)})
+}
+
+func (m *Metrics) RecordSyncerSet(key string, status metrics.SyncerSetUidStatus) {
+	m.client.count("syncer_sets", 1, tag{syncerLabel, key}, tag{statusLabel, string(status)})
+}
+
+func (m *Metrics) RecordStoredReqCacheResult(cacheResult metrics.CacheResult, inc int) {
+	m.client.count("stored_request_cache_performance", int64(inc), tag{cacheResultLabel, string(cacheResult)})
+}
+
+func (m *Metrics) RecordStoredImpCacheResult(cacheResult metrics.CacheResult, inc int) {
+	m.client.count("stored_impressions_cache_performance", int64(inc), tag{cacheResultLabel, string(cacheResult)})
+}
+
+func (m *Metrics) RecordAccountCacheResult(cacheResult metrics.CacheResult, inc int) {
+	m.client.count("account_cache_performance", int64(inc), tag{cacheResultLabel, string(cacheResult)})
+}
+
+func (m *Metrics) RecordPrebidCacheRequestTime(success bool, length time.Duration) {
+	m.client.timing("prebidcache_write_time", length, tag{successLabel, strconv.FormatBool(success)})
+}
+
+func (m *Metrics) RecordRequestQueueTime(success bool, requestType metrics.RequestType, length time.Duration) {
+	status := requestRejectLabel
+	if success {
+		status = requestSuccessLabel
+	}
+	m.client.timing("request_queue_time", length, tag{requestTypeLabel, string(requestType)}, tag{requestStatusLabel, status})
+}
+
+func (m *Metrics) RecordTimeoutNotice(success bool) {
+	if success {
+		m.client.count("timeout_notification", 1, tag{successLabel, requestSuccessful})
+	} else {
+		m.client.count("timeout_notification", 1, tag{successLabel, requestFailed})
+	}
+}
+
+func (m *Metrics) RecordRequestPrivacy(privacy metrics.PrivacyLabels) {
+	source := tag{sourceLabel, sourceRequest}
+	if privacy.CCPAProvided {
+		m.client.count("privacy_ccpa", 1, source, tag{optOutLabel, strconv.FormatBool(privacy.CCPAEnforced)})
+	}
+	if privacy.COPPAEnforced {
+		m.client.count("privacy_coppa", 1, source)
+	}
+	if privacy.GDPREnforced {
+		m.client.count("privacy_tcf", 1, tag{versionLabel, string(privacy.GDPRTCFVersion)}, source)
+	}
+	if privacy.LMTEnforced {
+		m.client.count("privacy_lmt", 1, source)
+	}
+}
+
+func (m *Metrics) RecordAdapterBuyerUIDScrubbed(adapterName openrtb_ext.BidderName) {
+	if m.metricsDisabled.AdapterBuyerUIDScrubbed {
+		return
+	}
+	m.client.count("adapter_buyeruids_scrubbed", 1, tag{adapterLabel, strings.ToLower(string(adapterName))})
+}
+
+func (m *Metrics) RecordAdapterGDPRRequestBlocked(adapterName openrtb_ext.BidderName) {
+	if m.metricsDisabled.AdapterGDPRRequestBlocked {
+		return
+	}
+	m.client.count("adapter_gdpr_requests_blocked", 1, tag{adapterLabel, strings.ToLower(string(adapterName))})
+}
+
+func (m *Metrics) RecordAdsCertReq(success bool) {
+	if success {
+		m.client.count("ads_cert_requests", 1, tag{successLabel, requestSuccessful})
+	} else {
+		m.client.count("ads_cert_requests", 1, tag{successLabel, requestFailed})
+	}
+}
+
+func (m *Metrics) RecordAdsCertSignTime(adsCertSignTime time.Duration) {
+	m.client.timing("ads_cert_sign_time", adsCertSignTime)
+}
+
+func (m *Metrics) RecordBidValidationCreativeSizeError(adapter openrtb_ext.BidderName, account string) {
+	m.recordBidValidation("response_validation_size_err", adapter, account)
+}
+
+func (m *Metrics) RecordBidValidationCreativeSizeWarn(adapter openrtb_ext.BidderName, account string) {
+	m.recordBidValidation("response_validation_size_warn", adapter, account)
+}
+
+func (m *Metrics) RecordBidValidationSecureMarkupError(adapter openrtb_ext.BidderName, account string) {
+	m.recordBidValidation("response_validation_secure_err", adapter, account)
+}
+
+func (m *Metrics) RecordBidValidationSecureMarkupWarn(adapter openrtb_ext.BidderName, account string) {
+	m.recordBidValidation("response_validation_secure_warn", adapter, account)
+}
+
+func (m *Metrics) RecordBidValidationSecureCreativeError(adapter openrtb_ext.BidderName, account string) {
+	m.recordBidValidation("response_validation_secure_creative_err", adapter, account)
+}
+
+func (m *Metrics) RecordBidValidationSecureCreativeWarn(adapter openrtb_ext.BidderName, account string) {
+	m.recordBidValidation("response_validation_secure_creative_warn", adapter, account)
+}
+
+func (m *Metrics) RecordBidValidationSecureCreativeRewrite(adapter openrtb_ext.BidderName, account string) {
+	m.recordBidValidation("response_validation_secure_creative_rewrite", adapter, account)
+}
+
+// recordBidValidation counts a bid validation failure per adapter and, unless disabled, per account.
+func (m *Metrics) recordBidValidation(name string, adapter openrtb_ext.BidderName, account string) {
+	m.client.count("adapter_"+name, 1, tag{adapterLabel, strings.ToLower(string(adapter))}, tag{successLabel, successLabel})
+
+	if !m.metricsDisabled.AccountAdapterDetails && account != metrics.PublisherUnknown {
+		m.client.count("account_"+name, 1, tag{accountLabel, account}, tag{successLabel, successLabel})
+	}
+}
+
+func (m *Metrics) RecordModuleCalled(labels metrics.ModuleLabels, duration time.Duration) {
+	tags := m.moduleTags(labels)
+	m.client.count("modules_called", 1, tags...)
+	m.client.timing("modules_duration", duration, tags...)
+}
+
+func (m *Metrics) RecordModuleFailed(labels metrics.ModuleLabels) {
+	m.client.count("modules_failed", 1, m.moduleTags(labels)...)
+}
+
+func (m *Metrics) RecordModuleSuccessNooped(labels metrics.ModuleLabels) {
+	m.client.count("modules_success_noops", 1, m.moduleTags(labels)...)
+}
+
+func (m *Metrics) RecordModuleSuccessUpdated(labels metrics.ModuleLabels) {
+	m.client.count("modules_success_updates", 1, m.moduleTags(labels)...)
+}
+
+func (m *Metrics) RecordModuleSuccessRejected(labels metrics.ModuleLabels) {
+	m.client.count("modules_success_rejects", 1, m.moduleTags(labels)...)
+}
+
+func (m *Metrics) RecordModuleExecutionError(labels metrics.ModuleLabels) {
+	m.client.count("modules_execution_errors", 1, m.moduleTags(labels)...)
+}
+
+func (m *Metrics) RecordModuleTimeout(labels metrics.ModuleLabels) {
+	m.client.count("modules_timeouts", 1, m.moduleTags(labels)...)
+}
+
+// moduleTags tags module metrics with the module and stage. Prometheus has a metric per module instead, tags
+// allow aggregating across modules. The account is added unless account module metrics are disabled.
+func (m *Metrics) moduleTags(labels metrics.ModuleLabels) []tag {
+	tags := []tag{{moduleLabel, labels.Module}, {stageLabel, labels.Stage}}
+	if !m.metricsDisabled.AccountModulesMetrics && labels.AccountID != "" && labels.AccountID != metrics.PublisherUnknown {
+		tags = append(tags, tag{accountLabel, labels.AccountID})
+	}
+	return tags
+}
+
+func (m *Metrics) RecordAdapterThrottled(adapterName openrtb_ext.BidderName) {
+	m.client.count("adapter_throttled", 1, tag{adapterLabel, strings.ToLower(string(adapterName))})
+}
+
+func (m *Metrics) RecordAdapterConnectionDialError(adapterName openrtb_ext.BidderName) {
+	if m.metricsDisabled.AdapterConnectionMetrics || m.metricsDisabled.AdapterConnectionDialMetrics {
+		return
+	}
+	m.client.count("adapter_connection_dial_errors", 1, tag{adapterLabel, strings.ToLower(string(adapterName))})
+}
+
+func (m *Metrics) RecordAdapterConnectionDialTime(adapterName openrtb_ext.BidderName, dialStartTime time.Duration) {
+	if m.metricsDisabled.AdapterConnectionMetrics || m.metricsDisabled.AdapterConnectionDialMetrics {
+		return
+	}
+	m.client.timing("adapter_connection_dial_time", dialStartTime, tag{adapterLabel, strings.ToLower(string(adapterName))})
+}
+
+func (m *Metrics) RecordAdapterConnectionPoolExhausted(adapterName openrtb_ext.BidderName) {
+	if m.metricsDisabled.AdapterConnectionMetrics {
+		return
+	}
+	m.client.count("adapter_connection_pool_exhausted", 1, tag{adapterLabel, strings.ToLower(string(adapterName))})
+}
+
+func (m *Metrics) RecordAdapterPayloadSize(adapterName openrtb_ext.BidderName, direction metrics.PayloadDirection, rawBytes int, wireBytes int) {
+	tags := []tag{{adapterLabel, strings.ToLower(string(adapterName))}, {directionLabel, string(direction)}}
+	m.client.count("adapter_payload_raw_bytes", int64(rawBytes), tags...)
+	m.client.count("adapter_payload_wire_bytes", int64(wireBytes), tags...)
+}
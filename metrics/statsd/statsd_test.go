@@ -0,0 +1,233 @@
+package statsdmetrics
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listen starts a UDP listener standing in for the StatsD agent.
+func listen(t *testing.T) net.PacketConn {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// receive returns the metric lines of the datagrams received until the agent is idle.
+func receive(t *testing.T, conn net.PacketConn) []string {
+	var lines []string
+	buffer := make([]byte, 65536)
+	for {
+		conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+		n, _, err := conn.ReadFrom(buffer)
+		if err != nil {
+			return lines
+		}
+		lines = append(lines, strings.Split(string(buffer[:n]), "\n")...)
+	}
+}
+
+func newTestMetrics(t *testing.T, conn net.PacketConn, disabled config.DisabledMetrics) *Metrics {
+	cfg := config.StatsDMetrics{
+		Host:            conn.LocalAddr().String(),
+		Namespace:       "pbs.",
+		MaxPacketSize:   1432,
+		FlushIntervalMS: 60000,
+	}
+	m, err := NewMetrics(cfg, disabled)
+	require.NoError(t, err)
+	return m
+}
+
+func TestRecordRequest(t *testing.T) {
+	conn := listen(t)
+	m := newTestMetrics(t, conn, config.DisabledMetrics{})
+
+	m.RecordRequest(metrics.Labels{
+		RType:         metrics.ReqTypeORTB2Web,
+		RequestStatus: metrics.RequestStatusOK,
+		PubID:         "acct",
+		CookieFlag:    metrics.CookieFlagNo,
+		RequestSize:   100,
+	})
+	m.RecordRequestTime(metrics.Labels{RType: metrics.ReqTypeORTB2Web, RequestStatus: metrics.RequestStatusOK}, 1500*time.Microsecond)
+	m.Close()
+
+	assert.Equal(t, []string{
+		"pbs.requests:1|c|#request_type:openrtb2-web,request_status:ok",
+		"pbs.request_size_bytes:100|h|#request_size:auction",
+		"pbs.requests_without_cookie:1|c|#request_type:openrtb2-web",
+		"pbs.account_requests:1|c|#account:acct",
+		"pbs.request_time:1.5|ms|#request_type:openrtb2-web",
+	}, receive(t, conn))
+}
+
+func TestRecordAdapterRequest(t *testing.T) {
+	conn := listen(t)
+	m := newTestMetrics(t, conn, config.DisabledMetrics{})
+
+	m.RecordAdapterRequest(metrics.AdapterLabels{
+		Adapter:       "AppNexus",
+		CookieFlag:    metrics.CookieFlagYes,
+		AdapterBids:   metrics.AdapterBidPresent,
+		AdapterErrors: map[metrics.AdapterError]struct{}{metrics.AdapterErrorTimeout: {}},
+	})
+	m.RecordAdapterPrice(metrics.AdapterLabels{Adapter: "appnexus"}, 1.25)
+	m.RecordAdapterPayloadSize("appnexus", metrics.PayloadDirectionRequest, 200, 80)
+	m.Close()
+
+	assert.Equal(t, []string{
+		"pbs.adapter_requests:1|c|#adapter:appnexus,cookie:exists,has_bids:true",
+		"pbs.adapter_errors:1|c|#adapter:appnexus,adapter_error:timeout",
+		"pbs.adapter_prices:1.25|h|#adapter:appnexus",
+		"pbs.adapter_payload_raw_bytes:200|c|#adapter:appnexus,direction:request",
+		"pbs.adapter_payload_wire_bytes:80|c|#adapter:appnexus,direction:request",
+	}, receive(t, conn))
+}
+
+func TestRecordModule(t *testing.T) {
+	testCases := []struct {
+		name          string
+		disabled      config.DisabledMetrics
+		expectedLines []string
+	}{
+		{
+			name: "account-tagged",
+			expectedLines: []string{
+				"pbs.modules_called:1|c|#module:foobar,stage:entrypoint,account:acct",
+				"pbs.modules_duration:2|ms|#module:foobar,stage:entrypoint,account:acct",
+				"pbs.modules_timeouts:1|c|#module:foobar,stage:entrypoint,account:acct",
+			},
+		},
+		{
+			name:     "account-metrics-disabled",
+			disabled: config.DisabledMetrics{AccountModulesMetrics: true},
+			expectedLines: []string{
+				"pbs.modules_called:1|c|#module:foobar,stage:entrypoint",
+				"pbs.modules_duration:2|ms|#module:foobar,stage:entrypoint",
+				"pbs.modules_timeouts:1|c|#module:foobar,stage:entrypoint",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			conn := listen(t)
+			m := newTestMetrics(t, conn, test.disabled)
+
+			labels := metrics.ModuleLabels{Module: "foobar", Stage: "entrypoint", AccountID: "acct"}
+			m.RecordModuleCalled(labels, 2*time.Millisecond)
+			m.RecordModuleTimeout(labels)
+			m.Close()
+
+			assert.Equal(t, test.expectedLines, receive(t, conn))
+		})
+	}
+}
+
+func TestDisabledMetrics(t *testing.T) {
+	conn := listen(t)
+	m := newTestMetrics(t, conn, config.DisabledMetrics{
+		AccountAdapterDetails:     true,
+		AccountDebug:              true,
+		AccountStoredResponses:    true,
+		AdapterConnectionMetrics:  true,
+		AdapterBuyerUIDScrubbed:   true,
+		AdapterGDPRRequestBlocked: true,
+	})
+
+	m.RecordDebugRequest(true, "acct")
+	m.RecordStoredResponse("acct")
+	m.RecordBidValidationSecureMarkupError("appnexus", "acct")
+	m.RecordAdapterConnections("appnexus", true, time.Millisecond)
+	m.RecordAdapterConnectionPoolExhausted("appnexus")
+	m.RecordAdapterConnectionDialError("appnexus")
+	m.RecordAdapterBuyerUIDScrubbed("appnexus")
+	m.RecordAdapterGDPRRequestBlocked("appnexus")
+	m.Close()
+
+	assert.Equal(t, []string{
+		"pbs.debug_requests:1|c",
+		"pbs.stored_responses:1|c",
+		"pbs.adapter_response_validation_secure_err:1|c|#adapter:appnexus,success:success",
+	}, receive(t, conn))
+}
+
+func TestRecordStoredData(t *testing.T) {
+	conn := listen(t)
+	m := newTestMetrics(t, conn, config.DisabledMetrics{})
+
+	m.RecordStoredDataFetchTime(metrics.StoredDataLabels{DataType: metrics.AMPDataType, DataFetchType: metrics.FetchDelta}, 3*time.Millisecond)
+	m.RecordStoredDataError(metrics.StoredDataLabels{DataType: metrics.AccountDataType, Error: metrics.StoredDataErrorNetwork})
+	m.RecordStoredReqCacheResult(metrics.CacheMiss, 3)
+	m.Close()
+
+	assert.Equal(t, []string{
+		"pbs.stored_amp_fetch_time:3|ms|#stored_data_fetch_type:delta",
+		"pbs.stored_account_errors:1|c|#stored_data_error:network",
+		"pbs.stored_request_cache_performance:3|c|#cache_result:miss",
+	}, receive(t, conn))
+}
+
+func TestRecordGvlListVersion(t *testing.T) {
+	conn := listen(t)
+	m := newTestMetrics(t, conn, config.DisabledMetrics{})
+
+	m.RecordGvlListVersion(3, 42)
+	m.Close()
+
+	assert.Equal(t, []string{"pbs.gvl_list_version:42|g|#spec_version:3"}, receive(t, conn))
+}
+
+func TestClient(t *testing.T) {
+	t.Run("global-tags-and-escaping", func(t *testing.T) {
+		conn := listen(t)
+		c, err := newClient(conn.LocalAddr().String(), "", []string{"env:test"}, 1432, time.Minute)
+		require.NoError(t, err)
+
+		c.count("account_requests", 1, tag{"account", "a|b,c#d"})
+		c.count("tmax_timeout", 1)
+		c.close()
+
+		assert.Equal(t, []string{
+			"account_requests:1|c|#account:a_b_c_d,env:test",
+			"tmax_timeout:1|c|#env:test",
+		}, receive(t, conn))
+	})
+
+	t.Run("packet-size", func(t *testing.T) {
+		conn := listen(t)
+		c, err := newClient(conn.LocalAddr().String(), "", nil, 30, time.Minute)
+		require.NoError(t, err)
+
+		c.count("connections_opened", 1)
+		c.count("connections_closed", 1)
+		c.close()
+
+		buffer := make([]byte, 100)
+		for _, expected := range []string{"connections_opened:1|c", "connections_closed:1|c"} {
+			conn.SetReadDeadline(time.Now().Add(time.Second))
+			n, _, err := conn.ReadFrom(buffer)
+			require.NoError(t, err)
+			assert.Equal(t, expected, string(buffer[:n]), "metrics exceeding the packet size are sent separately")
+		}
+	})
+
+	t.Run("flush-interval", func(t *testing.T) {
+		conn := listen(t)
+		c, err := newClient(conn.LocalAddr().String(), "", nil, 1432, 10*time.Millisecond)
+		require.NoError(t, err)
+		defer c.close()
+
+		c.count("gvl_requests", 1)
+
+		assert.Equal(t, []string{"gvl_requests:1|c"}, receive(t, conn))
+	})
+}
@@ -2,7 +2,9 @@ package modules
 
 import (
 	fiftyonedegreesDevicedetection "github.com/prebid/prebid-server/v3/modules/fiftyonedegrees/devicedetection"
+	prebidCreativescan "github.com/prebid/prebid-server/v3/modules/prebid/creativescan"
 	prebidOrtb2blocking "github.com/prebid/prebid-server/v3/modules/prebid/ortb2blocking"
+	prebidRtd "github.com/prebid/prebid-server/v3/modules/prebid/rtd"
 	prebidRulesengine "github.com/prebid/prebid-server/v3/modules/prebid/rulesengine"
 	scope3Rtd "github.com/prebid/prebid-server/v3/modules/scope3/rtd"
 )
@@ -15,7 +17,9 @@ func builders() ModuleBuilders {
 			"devicedetection": fiftyonedegreesDevicedetection.Builder,
 		},
 		"prebid": {
+			"creativescan":  prebidCreativescan.Builder,
 			"ortb2blocking": prebidOrtb2blocking.Builder,
+			"rtd":           prebidRtd.Builder,
 			"rulesengine":   prebidRulesengine.Builder,
 		},
 		"scope3": {
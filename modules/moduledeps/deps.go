@@ -12,4 +12,10 @@ type ModuleDeps struct {
 	HTTPClient    *http.Client
 	RateConvertor *currency.RateConverter
 	Geoscope      map[string][]string
+
+	// AdminEndpoints collects the handlers modules serve on the admin server, by path.
+	AdminEndpoints AdminEndpoints
 }
+
+// AdminEndpoints maps admin server paths to the handlers modules register for them.
+type AdminEndpoints map[string]http.HandlerFunc
@@ -0,0 +1,119 @@
+package creativescan
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+const defaultMaxMarkupBytes = 512 * 1024
+
+// config is the host-level configuration of the module
+type config struct {
+	// BlockedDomains rejects creatives referencing a URL on one of these domains or their subdomains
+	BlockedDomains []string `json:"blocked_domains"`
+	// RedirectPatterns are regular expressions rejecting creatives referencing a URL matching one of them
+	RedirectPatterns []string `json:"redirect_patterns"`
+	// CheckSecure rejects creatives loading resources over HTTP on secure imps (default: true)
+	CheckSecure *bool `json:"check_secure"`
+	// DetectAutoRedirect rejects creatives navigating the page away without a click (default: true)
+	DetectAutoRedirect *bool `json:"detect_auto_redirect"`
+	// SignaturesFile is the path of the file of the malware signatures, one name:pattern pair per line
+	SignaturesFile string `json:"signatures_file"`
+	// MaxMarkupBytes is the size of the markup scanned, longer markup is scanned up to this size
+	MaxMarkupBytes int `json:"max_markup_bytes"`
+}
+
+// signature is a known malware signature, matched against the markup of the creatives
+type signature struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+// scanner holds the compiled checks of the configuration
+type scanner struct {
+	blockedDomains     []string
+	redirectPatterns   []*regexp.Regexp
+	checkSecure        bool
+	detectAutoRedirect bool
+	signatures         []signature
+	maxMarkupBytes     int
+}
+
+func newScanner(data json.RawMessage) (*scanner, error) {
+	var cfg config
+	if len(data) > 0 {
+		if err := jsonutil.UnmarshalValid(data, &cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse config: %s", err)
+		}
+	}
+
+	s := &scanner{
+		checkSecure:        cfg.CheckSecure == nil || *cfg.CheckSecure,
+		detectAutoRedirect: cfg.DetectAutoRedirect == nil || *cfg.DetectAutoRedirect,
+		maxMarkupBytes:     cfg.MaxMarkupBytes,
+	}
+	if s.maxMarkupBytes <= 0 {
+		s.maxMarkupBytes = defaultMaxMarkupBytes
+	}
+
+	for _, domain := range cfg.BlockedDomains {
+		if domain = strings.ToLower(strings.TrimSpace(domain)); domain != "" {
+			s.blockedDomains = append(s.blockedDomains, domain)
+		}
+	}
+
+	for _, pattern := range cfg.RedirectPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redirect pattern %q: %s", pattern, err)
+		}
+		s.redirectPatterns = append(s.redirectPatterns, re)
+	}
+
+	if cfg.SignaturesFile != "" {
+		data, err := os.ReadFile(cfg.SignaturesFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read signatures file: %s", err)
+		}
+		if s.signatures, err = parseSignatures(data); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// parseSignatures reads signatures written one per line as name:pattern, where the pattern is a regular
+// expression. Blank lines and lines starting with # are ignored.
+func parseSignatures(data []byte) ([]signature, error) {
+	var signatures []signature
+	lines := bufio.NewScanner(bytes.NewReader(data))
+	for lineNumber := 1; lines.Scan(); lineNumber++ {
+		line := strings.TrimSpace(lines.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, pattern, found := strings.Cut(line, ":")
+		name, pattern = strings.TrimSpace(name), strings.TrimSpace(pattern)
+		if !found || name == "" || pattern == "" {
+			return nil, fmt.Errorf("invalid signature on line %d: expected name:pattern", lineNumber)
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature %s on line %d: %s", name, lineNumber, err)
+		}
+		signatures = append(signatures, signature{name: name, pattern: re})
+	}
+	if err := lines.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read signatures file: %s", err)
+	}
+	return signatures, nil
+}
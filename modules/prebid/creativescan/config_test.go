@@ -0,0 +1,144 @@
+package creativescan
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewScanner(t *testing.T) {
+	testCases := []struct {
+		description   string
+		config        string
+		expectedError string
+		assert        func(t *testing.T, s *scanner)
+	}{
+		{
+			description: "defaults",
+			config:      ``,
+			assert: func(t *testing.T, s *scanner) {
+				assert.True(t, s.checkSecure)
+				assert.True(t, s.detectAutoRedirect)
+				assert.Equal(t, defaultMaxMarkupBytes, s.maxMarkupBytes)
+				assert.Empty(t, s.blockedDomains)
+				assert.Empty(t, s.signatures)
+			},
+		},
+		{
+			description: "configured",
+			config: `{
+				"blocked_domains": [" Bad.COM ", ""],
+				"redirect_patterns": ["redirect\\?url="],
+				"check_secure": false,
+				"detect_auto_redirect": false,
+				"max_markup_bytes": 100
+			}`,
+			assert: func(t *testing.T, s *scanner) {
+				assert.False(t, s.checkSecure)
+				assert.False(t, s.detectAutoRedirect)
+				assert.Equal(t, 100, s.maxMarkupBytes)
+				assert.Equal(t, []string{"bad.com"}, s.blockedDomains)
+				require.Len(t, s.redirectPatterns, 1)
+				assert.Equal(t, `redirect\?url=`, s.redirectPatterns[0].String())
+			},
+		},
+		{
+			description:   "invalid-json",
+			config:        `{"blocked_domains": "bad.com"}`,
+			expectedError: "failed to parse config",
+		},
+		{
+			description:   "invalid-redirect-pattern",
+			config:        `{"redirect_patterns": ["("]}`,
+			expectedError: `invalid redirect pattern "("`,
+		},
+		{
+			description:   "missing-signatures-file",
+			config:        `{"signatures_file": "/nonexistent/signatures.txt"}`,
+			expectedError: "failed to read signatures file",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			s, err := newScanner(json.RawMessage(test.config))
+			if test.expectedError != "" {
+				assert.ErrorContains(t, err, test.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			test.assert(t, s)
+		})
+	}
+}
+
+func TestNewScannerLoadsSignatures(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "signatures.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# known kits\ncoinhive: CoinHive\\.Anonymous\n\nevil-eval: eval\\(atob\\("), 0644))
+
+	s, err := newScanner(json.RawMessage(`{"signatures_file": "` + path + `"}`))
+	require.NoError(t, err)
+	require.Len(t, s.signatures, 2)
+	assert.Equal(t, "coinhive", s.signatures[0].name)
+	assert.Equal(t, "evil-eval", s.signatures[1].name)
+	assert.True(t, s.signatures[1].pattern.MatchString("eval(atob('...'))"))
+}
+
+func TestParseSignatures(t *testing.T) {
+	testCases := []struct {
+		description   string
+		data          string
+		expectedNames []string
+		expectedError string
+	}{
+		{
+			description: "empty",
+			data:        "",
+		},
+		{
+			description:   "comments-and-blank-lines",
+			data:          "# comment\n\n  \nminer: coinhive\n",
+			expectedNames: []string{"miner"},
+		},
+		{
+			description:   "pattern-with-colon",
+			data:          "scheme: javascript:eval",
+			expectedNames: []string{"scheme"},
+		},
+		{
+			description:   "missing-pattern",
+			data:          "# comment\nminer",
+			expectedError: "invalid signature on line 2: expected name:pattern",
+		},
+		{
+			description:   "empty-name",
+			data:          ": coinhive",
+			expectedError: "invalid signature on line 1: expected name:pattern",
+		},
+		{
+			description:   "invalid-pattern",
+			data:          "miner: coinhive\nbroken: (",
+			expectedError: "invalid signature broken on line 2",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			signatures, err := parseSignatures([]byte(test.data))
+			if test.expectedError != "" {
+				assert.ErrorContains(t, err, test.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			var names []string
+			for _, sig := range signatures {
+				names = append(names, sig.name)
+			}
+			assert.Equal(t, test.expectedNames, names)
+		})
+	}
+}
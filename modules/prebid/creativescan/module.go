@@ -0,0 +1,183 @@
+// Package creativescan implements a module scanning the markup of the bids for blocked domains, redirects,
+// insecure resources, malware signatures and auto-redirects, rejecting the offending bids.
+package creativescan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+)
+
+const (
+	activityName = "creative_scan"
+
+	// seat non-bid codes of the rejected bids
+	nonBidInvalidCreative   = 350 // Response Rejected - Invalid Creative
+	nonBidCreativeNotSecure = 352 // Response Rejected - Invalid Creative (Not Secure)
+)
+
+var (
+	_ hookstage.BidderRequest     = Module{}
+	_ hookstage.RawBidderResponse = Module{}
+)
+
+func Builder(cfg json.RawMessage, _ moduledeps.ModuleDeps) (interface{}, error) {
+	s, err := newScanner(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return Module{scanner: s}, nil
+}
+
+type Module struct {
+	scanner *scanner
+}
+
+// secureImps holds the IDs of the imps of a bidder request requiring secure creatives
+type secureImps map[string]struct{}
+
+// HandleBidderRequestHook records the secure imps of the bidder request for the scan of the bidder response
+func (m Module) HandleBidderRequestHook(
+	_ context.Context,
+	_ hookstage.ModuleInvocationContext,
+	payload hookstage.BidderRequestPayload,
+) (hookstage.HookResult[hookstage.BidderRequestPayload], error) {
+	result := hookstage.HookResult[hookstage.BidderRequestPayload]{}
+	if payload.Request == nil || payload.Request.BidRequest == nil {
+		return result, nil
+	}
+
+	secure := secureImps{}
+	for _, imp := range payload.Request.Imp {
+		if imp.Secure != nil && *imp.Secure == 1 {
+			secure[imp.ID] = struct{}{}
+		}
+	}
+	result.ModuleContext = hookstage.ModuleContext{payload.Bidder: secure}
+	return result, nil
+}
+
+// HandleRawBidderResponseHook scans the bids of the bidder, rejecting the bids failing a check. Bids left
+// unscanned once the hook runs out of time are let through.
+func (m Module) HandleRawBidderResponseHook(
+	ctx context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.RawBidderResponsePayload,
+) (hookstage.HookResult[hookstage.RawBidderResponsePayload], error) {
+	result := hookstage.HookResult[hookstage.RawBidderResponsePayload]{}
+	if payload.BidderResponse == nil || len(payload.BidderResponse.Bids) == 0 {
+		return result, nil
+	}
+
+	// without the bidder request hook, the secure imps are unknown and the secure check does not run
+	secure, _ := miCtx.ModuleContext[payload.Bidder].(secureImps)
+
+	activity := hookanalytics.Activity{Name: activityName, Status: hookanalytics.ActivityStatusSuccess}
+	allowedBids := make([]*adapters.TypedBid, 0, len(payload.BidderResponse.Bids))
+	seatNonBids := make(map[string][]openrtb_ext.NonBid)
+	for i, bid := range payload.BidderResponse.Bids {
+		if ctx.Err() != nil {
+			allowedBids = append(allowedBids, payload.BidderResponse.Bids[i:]...)
+			activity.Status = hookanalytics.ActivityStatusError
+			activity.Results = append(activity.Results, hookanalytics.Result{
+				Status:    hookanalytics.ResultStatusError,
+				Values:    map[string]interface{}{"error": "scan timed out", "unscanned": len(payload.BidderResponse.Bids) - i},
+				AppliedTo: hookanalytics.AppliedTo{Bidder: payload.Bidder},
+			})
+			break
+		}
+		if bid == nil || bid.Bid == nil {
+			allowedBids = append(allowedBids, bid)
+			continue
+		}
+
+		_, isSecure := secure[bid.Bid.ImpID]
+		findings := m.scanner.scan(bid, isSecure)
+		if len(findings) == 0 {
+			allowedBids = append(allowedBids, bid)
+			continue
+		}
+
+		seat := payload.Bidder
+		if bid.Seat != "" {
+			seat = bid.Seat.String()
+		}
+		seatNonBids[seat] = append(seatNonBids[seat], newNonBid(bid.Bid, nonBidCode(findings)))
+		activity.Results = append(activity.Results, newBlockedResult(payload.Bidder, bid.Bid, findings))
+		result.DebugMessages = append(result.DebugMessages, fmt.Sprintf("Bid %s from bidder %s has been rejected, failed checks: %s", bid.Bid.ID, payload.Bidder, strings.Join(checkNames(findings), ", ")))
+	}
+
+	if len(seatNonBids) > 0 {
+		result.ChangeSet.RawBidderResponse().Bids().UpdateBids(allowedBids)
+		for seat, nonBids := range seatNonBids {
+			result.SeatNonBid = append(result.SeatNonBid, openrtb_ext.SeatNonBid{Seat: seat, NonBid: nonBids})
+		}
+	}
+	if len(activity.Results) > 0 {
+		result.AnalyticsTags = hookanalytics.Analytics{Activities: []hookanalytics.Activity{activity}}
+	}
+	return result, nil
+}
+
+// nonBidCode is the not secure code for bids only failing the secure check, and the invalid creative code otherwise
+func nonBidCode(findings []finding) int {
+	for _, f := range findings {
+		if f.check != checkInsecure {
+			return nonBidInvalidCreative
+		}
+	}
+	return nonBidCreativeNotSecure
+}
+
+// newBlockedResult reports the checks the bid failed, each with the values which failed it
+func newBlockedResult(bidder string, bid *openrtb2.Bid, findings []finding) hookanalytics.Result {
+	values := map[string]interface{}{"bid": bid.ID, "checks": checkNames(findings)}
+	for _, f := range findings {
+		failed, _ := values[f.check].([]string)
+		values[f.check] = append(failed, f.value)
+	}
+	return hookanalytics.Result{
+		Status:    hookanalytics.ResultStatusBlock,
+		Values:    values,
+		AppliedTo: hookanalytics.AppliedTo{Bidder: bidder, ImpIds: []string{bid.ImpID}, BidIds: []string{bid.ID}},
+	}
+}
+
+func checkNames(findings []finding) []string {
+	var names []string
+	for _, f := range findings {
+		if !slices.Contains(names, f.check) {
+			names = append(names, f.check)
+		}
+	}
+	return names
+}
+
+func newNonBid(bid *openrtb2.Bid, statusCode int) openrtb_ext.NonBid {
+	return openrtb_ext.NonBid{
+		ImpId:      bid.ImpID,
+		StatusCode: statusCode,
+		Ext: &openrtb_ext.NonBidExt{
+			Prebid: openrtb_ext.ExtResponseNonBidPrebid{Bid: openrtb_ext.NonBidObject{
+				Price:   bid.Price,
+				ADomain: bid.ADomain,
+				CatTax:  bid.CatTax,
+				Cat:     bid.Cat,
+				DealID:  bid.DealID,
+				W:       bid.W,
+				H:       bid.H,
+				Dur:     bid.Dur,
+				MType:   bid.MType,
+			}},
+		},
+	}
+}
@@ -0,0 +1,146 @@
+package creativescan
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const bidder = "appnexus"
+
+func newTestModule(t *testing.T) Module {
+	t.Helper()
+	result, err := Builder(json.RawMessage(`{"blocked_domains": ["bad.com"]}`), moduledeps.ModuleDeps{})
+	require.NoError(t, err)
+	module, ok := result.(Module)
+	require.True(t, ok)
+	return module
+}
+
+func TestBuilderInvalidConfig(t *testing.T) {
+	_, err := Builder(json.RawMessage(`{"redirect_patterns": ["("]}`), moduledeps.ModuleDeps{})
+	assert.Error(t, err)
+}
+
+func TestHandleBidderRequestHook(t *testing.T) {
+	module := newTestModule(t)
+	payload := hookstage.BidderRequestPayload{
+		Bidder: bidder,
+		Request: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{
+			{ID: "secure", Secure: ptrutil.ToPtr[int8](1)},
+			{ID: "insecure", Secure: ptrutil.ToPtr[int8](0)},
+			{ID: "unset"},
+		}}},
+	}
+
+	result, err := module.HandleBidderRequestHook(context.Background(), hookstage.ModuleInvocationContext{}, payload)
+	require.NoError(t, err)
+	assert.Equal(t, hookstage.ModuleContext{bidder: secureImps{"secure": {}}}, result.ModuleContext)
+	assert.Empty(t, result.ChangeSet.Mutations())
+}
+
+func TestHandleRawBidderResponseHook(t *testing.T) {
+	module := newTestModule(t)
+	miCtx := hookstage.ModuleInvocationContext{ModuleContext: hookstage.ModuleContext{bidder: secureImps{"imp-secure": {}}}}
+
+	clean := &adapters.TypedBid{Bid: &openrtb2.Bid{ID: "clean", ImpID: "imp-secure", AdM: `<img src="https://good.com/ad.png">`}, BidType: openrtb_ext.BidTypeBanner}
+	insecure := &adapters.TypedBid{Bid: &openrtb2.Bid{ID: "insecure", ImpID: "imp-secure", Price: 1.5, AdM: `<img src="http://good.com/ad.png">`}, BidType: openrtb_ext.BidTypeBanner}
+	insecureOnInsecureImp := &adapters.TypedBid{Bid: &openrtb2.Bid{ID: "insecure-ok", ImpID: "imp", AdM: `<img src="http://good.com/ad.png">`}, BidType: openrtb_ext.BidTypeBanner}
+	blocked := &adapters.TypedBid{Bid: &openrtb2.Bid{ID: "blocked", ImpID: "imp-secure", ADomain: []string{"good.com"}, AdM: `<img src="http://bad.com/ad.png">`}, BidType: openrtb_ext.BidTypeBanner, Seat: "alt-seat"}
+	payload := hookstage.RawBidderResponsePayload{
+		Bidder:         bidder,
+		BidderResponse: &adapters.BidderResponse{Bids: []*adapters.TypedBid{clean, insecure, insecureOnInsecureImp, blocked}},
+	}
+
+	result, err := module.HandleRawBidderResponseHook(context.Background(), miCtx, payload)
+	require.NoError(t, err)
+
+	for _, mut := range result.ChangeSet.Mutations() {
+		payload, err = mut.Apply(payload)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []*adapters.TypedBid{clean, insecureOnInsecureImp}, payload.BidderResponse.Bids)
+
+	assert.ElementsMatch(t, []openrtb_ext.SeatNonBid{
+		{Seat: bidder, NonBid: []openrtb_ext.NonBid{newNonBid(insecure.Bid, nonBidCreativeNotSecure)}},
+		{Seat: "alt-seat", NonBid: []openrtb_ext.NonBid{newNonBid(blocked.Bid, nonBidInvalidCreative)}},
+	}, result.SeatNonBid)
+
+	assert.Equal(t, hookanalytics.Analytics{Activities: []hookanalytics.Activity{{
+		Name:   activityName,
+		Status: hookanalytics.ActivityStatusSuccess,
+		Results: []hookanalytics.Result{
+			{
+				Status:    hookanalytics.ResultStatusBlock,
+				Values:    map[string]interface{}{"bid": "insecure", "checks": []string{checkInsecure}, checkInsecure: []string{"http://good.com/ad.png"}},
+				AppliedTo: hookanalytics.AppliedTo{Bidder: bidder, ImpIds: []string{"imp-secure"}, BidIds: []string{"insecure"}},
+			},
+			{
+				Status: hookanalytics.ResultStatusBlock,
+				Values: map[string]interface{}{
+					"bid":              "blocked",
+					"checks":           []string{checkBlockedDomain, checkInsecure},
+					checkBlockedDomain: []string{"bad.com"},
+					checkInsecure:      []string{"http://bad.com/ad.png"},
+				},
+				AppliedTo: hookanalytics.AppliedTo{Bidder: bidder, ImpIds: []string{"imp-secure"}, BidIds: []string{"blocked"}},
+			},
+		},
+	}}}, result.AnalyticsTags)
+	assert.Equal(t, []string{
+		"Bid insecure from bidder appnexus has been rejected, failed checks: insecure",
+		"Bid blocked from bidder appnexus has been rejected, failed checks: blocked_domain, insecure",
+	}, result.DebugMessages)
+}
+
+func TestHandleRawBidderResponseHookNoRejections(t *testing.T) {
+	module := newTestModule(t)
+	payload := hookstage.RawBidderResponsePayload{
+		Bidder: bidder,
+		BidderResponse: &adapters.BidderResponse{Bids: []*adapters.TypedBid{
+			{Bid: &openrtb2.Bid{ID: "1", ImpID: "imp", AdM: `<img src="http://good.com/ad.png">`}, BidType: openrtb_ext.BidTypeBanner},
+		}},
+	}
+
+	result, err := module.HandleRawBidderResponseHook(context.Background(), hookstage.ModuleInvocationContext{}, payload)
+	require.NoError(t, err)
+	assert.Empty(t, result.ChangeSet.Mutations())
+	assert.Empty(t, result.SeatNonBid)
+	assert.Empty(t, result.AnalyticsTags.Activities)
+}
+
+func TestHandleRawBidderResponseHookTimeout(t *testing.T) {
+	module := newTestModule(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	bids := []*adapters.TypedBid{
+		{Bid: &openrtb2.Bid{ID: "1", ImpID: "imp", AdM: `<img src="https://bad.com/ad.png">`}, BidType: openrtb_ext.BidTypeBanner},
+		{Bid: &openrtb2.Bid{ID: "2", ImpID: "imp", AdM: `<img src="https://bad.com/ad.png">`}, BidType: openrtb_ext.BidTypeBanner},
+	}
+	payload := hookstage.RawBidderResponsePayload{Bidder: bidder, BidderResponse: &adapters.BidderResponse{Bids: bids}}
+
+	result, err := module.HandleRawBidderResponseHook(ctx, hookstage.ModuleInvocationContext{}, payload)
+	require.NoError(t, err)
+	assert.Empty(t, result.ChangeSet.Mutations(), "the unscanned bids are let through")
+	assert.Empty(t, result.SeatNonBid)
+	assert.Equal(t, []hookanalytics.Activity{{
+		Name:   activityName,
+		Status: hookanalytics.ActivityStatusError,
+		Results: []hookanalytics.Result{{
+			Status:    hookanalytics.ResultStatusError,
+			Values:    map[string]interface{}{"error": "scan timed out", "unscanned": 2},
+			AppliedTo: hookanalytics.AppliedTo{Bidder: bidder},
+		}},
+	}}, result.AnalyticsTags.Activities)
+}
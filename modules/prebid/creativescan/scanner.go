@@ -0,0 +1,215 @@
+package creativescan
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/native1/response"
+	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// checks a creative can fail
+const (
+	checkBlockedDomain = "blocked_domain"
+	checkRedirect      = "redirect"
+	checkInsecure      = "insecure"
+	checkMalware       = "malware"
+	checkAutoRedirect  = "auto_redirect"
+)
+
+var (
+	// urlPattern matches the absolute and protocol-relative URLs of a markup
+	urlPattern = regexp.MustCompile(`(?i)(?:https?:)?//(?:[a-z0-9-]+\.)+[a-z]{2,}(?::\d+)?[^\s"'<>\\)\]]*`)
+
+	// htmlResourcePatterns match the HTTP URLs an HTML markup loads when rendered, as opposed to the links
+	// it navigates to on click
+	htmlResourcePatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)\b(?:src|srcset|poster|background|data)\s*=\s*["']?\s*(http://[^\s"'>]+)`),
+		regexp.MustCompile(`(?i)<link\b[^>]*\bhref\s*=\s*["']?\s*(http://[^\s"'>]+)`),
+		regexp.MustCompile(`(?i)url\(\s*["']?\s*(http://[^\s"')]+)`),
+	}
+
+	// vastResourcePattern matches the HTTP URLs of the VAST elements a player loads
+	vastResourcePattern = regexp.MustCompile(`(?is)<(?:MediaFile|Impression|Tracking|StaticResource|IFrameResource|JavaScriptResource|VASTAdTagURI)\b[^>]*>\s*(?:<!\[CDATA\[)?\s*(http://[^\s<\]]+)`)
+
+	// autoRedirectPatterns match the scripts and tags navigating the page away without a user action
+	autoRedirectPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)\b(?:window|document|top|parent|self)\.location(?:\.href)?\s*=[^=]`),
+		regexp.MustCompile(`(?i)\blocation\.(?:replace|assign)\s*\(`),
+		regexp.MustCompile(`(?i)<meta\b[^>]*http-equiv\s*=\s*["']?refresh`),
+	}
+)
+
+// finding is a check a creative failed, along with what failed it
+type finding struct {
+	check string
+	value string
+}
+
+// creative is what is scanned out of the markup of a bid
+type creative struct {
+	// markups are scanned for malware signatures and auto-redirects
+	markups []string
+	// urls are all the URLs the creative references
+	urls []string
+	// resources are the URLs loaded when the creative renders
+	resources []string
+}
+
+// scan runs the checks on the markup of the bid, returning the checks it failed. The secure check only runs on
+// bids for secure imps.
+func (s *scanner) scan(bid *adapters.TypedBid, secure bool) []finding {
+	adm := bid.Bid.AdM
+	if len(adm) > s.maxMarkupBytes {
+		adm = adm[:s.maxMarkupBytes]
+	}
+	if adm == "" {
+		return nil
+	}
+
+	c := parseCreative(adm, bid.BidType)
+	var findings []finding
+
+	for _, u := range c.urls {
+		if domain, ok := s.blockedDomain(u); ok {
+			findings = appendFinding(findings, finding{check: checkBlockedDomain, value: domain})
+		}
+		for _, re := range s.redirectPatterns {
+			if re.MatchString(u) {
+				findings = appendFinding(findings, finding{check: checkRedirect, value: u})
+			}
+		}
+	}
+
+	if secure && s.checkSecure {
+		for _, resource := range c.resources {
+			if strings.HasPrefix(strings.ToLower(resource), "http://") {
+				findings = appendFinding(findings, finding{check: checkInsecure, value: resource})
+			}
+		}
+	}
+
+	for _, markup := range c.markups {
+		for _, sig := range s.signatures {
+			if sig.pattern.MatchString(markup) {
+				findings = appendFinding(findings, finding{check: checkMalware, value: sig.name})
+			}
+		}
+		if s.detectAutoRedirect {
+			for _, re := range autoRedirectPatterns {
+				if match := re.FindString(markup); match != "" {
+					findings = appendFinding(findings, finding{check: checkAutoRedirect, value: strings.TrimSpace(match)})
+					break
+				}
+			}
+		}
+	}
+
+	return findings
+}
+
+// blockedDomain returns the blocked domain the host of the URL belongs to, if any
+func (s *scanner) blockedDomain(rawURL string) (string, bool) {
+	if len(s.blockedDomains) == 0 {
+		return "", false
+	}
+	if strings.HasPrefix(rawURL, "//") {
+		rawURL = "https:" + rawURL
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", false
+	}
+	host := strings.ToLower(parsed.Hostname())
+	for _, domain := range s.blockedDomains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return domain, true
+		}
+	}
+	return "", false
+}
+
+func appendFinding(findings []finding, f finding) []finding {
+	for _, existing := range findings {
+		if existing == f {
+			return findings
+		}
+	}
+	return append(findings, f)
+}
+
+// parseCreative reads the markup according to the type of the bid: VAST for video and audio bids, the native
+// response for native bids and HTML otherwise. A native markup which is not a native response is read as HTML.
+func parseCreative(adm string, bidType openrtb_ext.BidType) creative {
+	c := creative{urls: urlPattern.FindAllString(adm, -1)}
+	switch bidType {
+	case openrtb_ext.BidTypeVideo, openrtb_ext.BidTypeAudio:
+		c.markups = []string{adm}
+		c.resources = submatches(vastResourcePattern, adm)
+	case openrtb_ext.BidTypeNative:
+		if native, ok := parseNative(adm); ok {
+			c.addNative(native)
+			break
+		}
+		fallthrough
+	default:
+		c.markups = []string{adm}
+		for _, re := range htmlResourcePatterns {
+			c.resources = append(c.resources, submatches(re, adm)...)
+		}
+	}
+	return c
+}
+
+// addNative adds the resources of the native assets, and the markups of its JS tracker and VAST assets
+func (c *creative) addNative(native *response.Response) {
+	c.resources = append(c.resources, native.ImpTrackers...)
+	for _, tracker := range native.EventTrackers {
+		c.resources = append(c.resources, tracker.URL)
+	}
+	if native.JSTracker != "" {
+		c.markups = append(c.markups, native.JSTracker)
+		for _, re := range htmlResourcePatterns {
+			c.resources = append(c.resources, submatches(re, native.JSTracker)...)
+		}
+	}
+	for _, asset := range native.Assets {
+		if asset.Img != nil {
+			c.resources = append(c.resources, asset.Img.URL)
+		}
+		if asset.Video != nil && asset.Video.VASTTag != "" {
+			c.markups = append(c.markups, asset.Video.VASTTag)
+			c.resources = append(c.resources, submatches(vastResourcePattern, asset.Video.VASTTag)...)
+		}
+	}
+}
+
+// parseNative reads a native response, either at the root of the markup or under a native key as in native 1.0
+func parseNative(adm string) (*response.Response, bool) {
+	var wrapped struct {
+		Native *response.Response `json:"native"`
+	}
+	if err := jsonutil.Unmarshal([]byte(adm), &wrapped); err != nil {
+		return nil, false
+	}
+	if wrapped.Native != nil {
+		return wrapped.Native, true
+	}
+
+	var native response.Response
+	if err := jsonutil.Unmarshal([]byte(adm), &native); err != nil {
+		return nil, false
+	}
+	return &native, true
+}
+
+func submatches(re *regexp.Regexp, s string) []string {
+	var values []string
+	for _, match := range re.FindAllStringSubmatch(s, -1) {
+		values = append(values, match[1])
+	}
+	return values
+}
@@ -0,0 +1,164 @@
+package creativescan
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestScanner(t *testing.T, cfg string) *scanner {
+	t.Helper()
+	s, err := newScanner(json.RawMessage(cfg))
+	require.NoError(t, err)
+	return s
+}
+
+func TestScan(t *testing.T) {
+	s := newTestScanner(t, `{"blocked_domains": ["bad.com"], "redirect_patterns": ["/redirect\\?url="]}`)
+	s.signatures, _ = parseSignatures([]byte("coinhive: CoinHive\\.Anonymous"))
+
+	testCases := []struct {
+		description      string
+		adm              string
+		bidType          openrtb_ext.BidType
+		secure           bool
+		expectedFindings []finding
+	}{
+		{
+			description: "clean-banner",
+			adm:         `<a href="https://good.com/click"><img src="https://cdn.good.com/ad.png"></a>`,
+			bidType:     openrtb_ext.BidTypeBanner,
+			secure:      true,
+		},
+		{
+			description: "empty-markup",
+			adm:         ``,
+			bidType:     openrtb_ext.BidTypeBanner,
+		},
+		{
+			description:      "blocked-subdomain",
+			adm:              `<img src="https://cdn.BAD.com/ad.png"><img src="//ads.bad.com/pixel">`,
+			bidType:          openrtb_ext.BidTypeBanner,
+			expectedFindings: []finding{{check: checkBlockedDomain, value: "bad.com"}},
+		},
+		{
+			description: "lookalike-domain-not-blocked",
+			adm:         `<img src="https://notbad.com/ad.png">`,
+			bidType:     openrtb_ext.BidTypeBanner,
+		},
+		{
+			description:      "redirect",
+			adm:              `<a href="https://good.com/redirect?url=https://elsewhere.com">click</a>`,
+			bidType:          openrtb_ext.BidTypeBanner,
+			expectedFindings: []finding{{check: checkRedirect, value: "https://good.com/redirect?url=https://elsewhere.com"}},
+		},
+		{
+			description:      "insecure-resource-on-secure-imp",
+			adm:              `<a href="http://good.com/click"><img src="http://cdn.good.com/ad.png"></a>`,
+			bidType:          openrtb_ext.BidTypeBanner,
+			secure:           true,
+			expectedFindings: []finding{{check: checkInsecure, value: "http://cdn.good.com/ad.png"}},
+		},
+		{
+			description: "insecure-resource-on-non-secure-imp",
+			adm:         `<img src="http://cdn.good.com/ad.png">`,
+			bidType:     openrtb_ext.BidTypeBanner,
+		},
+		{
+			description:      "insecure-css-resource",
+			adm:              `<div style="background-image: url('http://cdn.good.com/bg.png')"></div>`,
+			bidType:          openrtb_ext.BidTypeBanner,
+			secure:           true,
+			expectedFindings: []finding{{check: checkInsecure, value: "http://cdn.good.com/bg.png"}},
+		},
+		{
+			description:      "malware",
+			adm:              `<script>var miner = new CoinHive.Anonymous('key');</script>`,
+			bidType:          openrtb_ext.BidTypeBanner,
+			expectedFindings: []finding{{check: checkMalware, value: "coinhive"}},
+		},
+		{
+			description:      "auto-redirect",
+			adm:              `<script>window.location.href = "https://elsewhere.com";</script>`,
+			bidType:          openrtb_ext.BidTypeBanner,
+			expectedFindings: []finding{{check: checkAutoRedirect, value: "window.location.href ="}},
+		},
+		{
+			description: "location-comparison-is-not-a-redirect",
+			adm:         `<script>if (window.location == "x") {}</script>`,
+			bidType:     openrtb_ext.BidTypeBanner,
+		},
+		{
+			description:      "meta-refresh",
+			adm:              `<meta http-equiv="refresh" content="0;url=https://elsewhere.com">`,
+			bidType:          openrtb_ext.BidTypeBanner,
+			expectedFindings: []finding{{check: checkAutoRedirect, value: `<meta http-equiv="refresh`}},
+		},
+		{
+			description:      "vast-insecure-media-file",
+			adm:              `<VAST><Ad><InLine><Impression><![CDATA[https://good.com/imp]]></Impression><MediaFiles><MediaFile type="video/mp4"><![CDATA[http://cdn.good.com/ad.mp4]]></MediaFile></MediaFiles></InLine></Ad></VAST>`,
+			bidType:          openrtb_ext.BidTypeVideo,
+			secure:           true,
+			expectedFindings: []finding{{check: checkInsecure, value: "http://cdn.good.com/ad.mp4"}},
+		},
+		{
+			description:      "vast-blocked-domain",
+			adm:              `<VAST><Ad><Wrapper><VASTAdTagURI>https://bad.com/vast.xml</VASTAdTagURI></Wrapper></Ad></VAST>`,
+			bidType:          openrtb_ext.BidTypeVideo,
+			expectedFindings: []finding{{check: checkBlockedDomain, value: "bad.com"}},
+		},
+		{
+			description: "native-insecure-assets",
+			adm:         `{"assets":[{"id":1,"img":{"url":"http://cdn.good.com/img.png"}}],"link":{"url":"http://good.com/click"},"imptrackers":["http://good.com/imp"]}`,
+			bidType:     openrtb_ext.BidTypeNative,
+			secure:      true,
+			expectedFindings: []finding{
+				{check: checkInsecure, value: "http://good.com/imp"},
+				{check: checkInsecure, value: "http://cdn.good.com/img.png"},
+			},
+		},
+		{
+			description:      "native-1.0-wrapper",
+			adm:              `{"native":{"assets":[],"jstracker":"<script>location.replace('https://elsewhere.com')</script>"}}`,
+			bidType:          openrtb_ext.BidTypeNative,
+			expectedFindings: []finding{{check: checkAutoRedirect, value: "location.replace("}},
+		},
+		{
+			description:      "native-html-markup",
+			adm:              `<img src="http://cdn.good.com/ad.png">`,
+			bidType:          openrtb_ext.BidTypeNative,
+			secure:           true,
+			expectedFindings: []finding{{check: checkInsecure, value: "http://cdn.good.com/ad.png"}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			bid := &adapters.TypedBid{Bid: &openrtb2.Bid{AdM: test.adm}, BidType: test.bidType}
+			assert.Equal(t, test.expectedFindings, s.scan(bid, test.secure))
+		})
+	}
+}
+
+func TestScanDisabledChecks(t *testing.T) {
+	s := newTestScanner(t, `{"check_secure": false, "detect_auto_redirect": false}`)
+	bid := &adapters.TypedBid{
+		Bid:     &openrtb2.Bid{AdM: `<img src="http://cdn.good.com/ad.png"><script>top.location = "https://elsewhere.com"</script>`},
+		BidType: openrtb_ext.BidTypeBanner,
+	}
+	assert.Empty(t, s.scan(bid, true))
+}
+
+func TestScanTruncatesMarkup(t *testing.T) {
+	s := newTestScanner(t, `{"blocked_domains": ["bad.com"], "max_markup_bytes": 40}`)
+	bid := &adapters.TypedBid{
+		Bid:     &openrtb2.Bid{AdM: `<img src="https://cdn.good.com/ad.png">  <img src="https://bad.com/pixel">`},
+		BidType: openrtb_ext.BidTypeBanner,
+	}
+	assert.Empty(t, s.scan(bid, false), "the markup past the limit is not scanned")
+}
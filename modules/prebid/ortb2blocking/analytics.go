@@ -13,6 +13,7 @@ const (
 	cattaxAnalyticKey     = "bcat"
 	bappAnalyticKey       = "bundle"
 	battrAnalyticKey      = "attr"
+	listsAnalyticKey      = "lists"
 )
 
 // ortb2blocking module has only 1 activity: `enforce_blocking` which will be used in further result processing
@@ -31,7 +32,7 @@ func addFailedStatusTag(result *hookstage.HookResult[hookstage.RawBidderResponse
 	result.AnalyticsTags.Activities[0].Status = hookanalytics.ActivityStatusError
 }
 
-func addAllowedAnalyticTag(result *hookstage.HookResult[hookstage.RawBidderResponsePayload], bidder, ImpID string) {
+func addAllowedAnalyticTag(result *hookstage.HookResult[hookstage.RawBidderResponsePayload], bidder, ImpID string, listVersions map[string]string) {
 	newAllowedResult := hookanalytics.Result{
 		Status: hookanalytics.ResultStatusAllow,
 		AppliedTo: hookanalytics.AppliedTo{
@@ -39,6 +40,9 @@ func addAllowedAnalyticTag(result *hookstage.HookResult[hookstage.RawBidderRespo
 			ImpIds: []string{ImpID},
 		},
 	}
+	if len(listVersions) > 0 {
+		newAllowedResult.Values = map[string]interface{}{listsAnalyticKey: listVersions}
+	}
 
 	result.AnalyticsTags.Activities[0].Results = append(result.AnalyticsTags.Activities[0].Results, newAllowedResult)
 }
@@ -48,6 +52,7 @@ func addBlockedAnalyticTag(
 	bidder, ImpID string,
 	failedAttributes []string,
 	data map[string]interface{},
+	listVersions map[string]string,
 ) {
 	values := make(map[string]interface{})
 
@@ -64,6 +69,10 @@ func addBlockedAnalyticTag(
 			values[analyticKey] = data[attribute]
 		}
 	}
+	// the versions of the shared lists tell which revision of a list blocked the bid
+	if len(listVersions) > 0 {
+		values[listsAnalyticKey] = listVersions
+	}
 
 	newBlockedResult := hookanalytics.Result{
 		Status: hookanalytics.ResultStatusBlock,
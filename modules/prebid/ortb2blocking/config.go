@@ -34,6 +34,9 @@ type Badv struct {
 	BlockedAdomain         []string           `json:"blocked_adomain"`
 	BlockUnknownAdomain    bool               `json:"block_unknown_adomain"`
 	EnforceBlocks          bool               `json:"enforce_blocks"`
+
+	// BlockedAdomainLists names the shared lists merged into the inline blocked values
+	BlockedAdomainLists []string `json:"blocked_adomain_lists"`
 }
 
 type BadvActionOverride struct {
@@ -50,6 +53,9 @@ type Bcat struct {
 	BlockUnknownAdvCat    bool                    `json:"block_unknown_adv_cat"`
 	CategoryTaxonomy      adcom1.CategoryTaxonomy `json:"category_taxonomy"`
 	EnforceBlocks         bool                    `json:"enforce_blocks"`
+
+	// BlockedAdvCatLists names the shared lists merged into the inline blocked values
+	BlockedAdvCatLists []string `json:"blocked_adv_cat_lists"`
 }
 
 type BcatActionOverride struct {
@@ -64,6 +70,9 @@ type Bapp struct {
 	AllowedAppForDeals []string           `json:"allowed_app_for_deals"`
 	BlockedApp         []string           `json:"blocked_app"`
 	EnforceBlocks      bool               `json:"enforce_blocks"`
+
+	// BlockedAppLists names the shared lists merged into the inline blocked values
+	BlockedAppLists []string `json:"blocked_app_lists"`
 }
 
 type BappActionOverride struct {
@@ -75,6 +84,9 @@ type BappActionOverride struct {
 type Btype struct {
 	ActionOverrides   BtypeActionOverride `json:"action_overrides"`
 	BlockedBannerType []int               `json:"blocked_banner_type"`
+
+	// BlockedBannerTypeLists names the shared lists merged into the inline blocked values
+	BlockedBannerTypeLists []string `json:"blocked_banner_type_lists"`
 }
 
 type BtypeActionOverride struct {
@@ -86,6 +98,9 @@ type Battr struct {
 	AllowedBannerAttrForDeals []int               `json:"allowed_banner_attr_for_deals"`
 	BlockedBannerAttr         []int               `json:"blocked_banner_attr"`
 	EnforceBlocks             bool                `json:"enforce_blocks"`
+
+	// BlockedBannerAttrLists names the shared lists merged into the inline blocked values
+	BlockedBannerAttrLists []string `json:"blocked_banner_attr_lists"`
 }
 
 type BattrActionOverride struct {
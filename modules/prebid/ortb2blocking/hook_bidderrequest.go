@@ -15,6 +15,7 @@ import (
 func handleBidderRequestHook(
 	cfg config,
 	payload hookstage.BidderRequestPayload,
+	listVersions map[string]string,
 ) (result hookstage.HookResult[hookstage.BidderRequestPayload], err error) {
 	if payload.Request == nil || payload.Request.BidRequest == nil {
 		return result, hookexecution.NewFailure("payload contains a nil bid request")
@@ -22,7 +23,7 @@ func handleBidderRequestHook(
 
 	mediaTypes := mediaTypesFrom(payload.Request.BidRequest)
 	changeSet := hookstage.ChangeSet[hookstage.BidderRequestPayload]{}
-	blockingAttributes := blockingAttributes{}
+	blockingAttributes := blockingAttributes{listVersions: listVersions}
 
 	if err = updateBAdv(cfg, payload, mediaTypes, &blockingAttributes, &result, &changeSet); err != nil {
 		return result, hookexecution.NewFailure("failed to update badv field: %s", err)
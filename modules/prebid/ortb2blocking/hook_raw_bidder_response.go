@@ -67,11 +67,11 @@ func handleRawBidderResponseHook(
 		}
 
 		if len(failedChecksData) == 0 {
-			addAllowedAnalyticTag(&result, bidder, bid.Bid.ImpID)
+			addAllowedAnalyticTag(&result, bidder, bid.Bid.ImpID, blockAttrs.listVersions)
 			allowedBids = append(allowedBids, bid)
 		} else {
 			failedAttributes := getFailedAttributes(failedChecksData)
-			addBlockedAnalyticTag(&result, bidder, bid.Bid.ImpID, failedAttributes, failedChecksData)
+			addBlockedAnalyticTag(&result, bidder, bid.Bid.ImpID, failedAttributes, failedChecksData, blockAttrs.listVersions)
 			addDebugMessage(&result, bid.Bid, bidder, failedAttributes)
 		}
 	}
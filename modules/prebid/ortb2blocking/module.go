@@ -4,16 +4,34 @@ import (
 	"context"
 	"encoding/json"
 
+	"github.com/golang/glog"
 	"github.com/prebid/openrtb/v20/adcom1"
 	"github.com/prebid/prebid-server/v3/hooks/hookstage"
 	"github.com/prebid/prebid-server/v3/modules/moduledeps"
 )
 
-func Builder(_ json.RawMessage, _ moduledeps.ModuleDeps) (interface{}, error) {
-	return Module{}, nil
+func Builder(cfg json.RawMessage, deps moduledeps.ModuleDeps) (interface{}, error) {
+	hostCfg, err := newHostConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if hostCfg.SharedLists == nil {
+		return Module{}, nil
+	}
+
+	lists := newSharedLists(hostCfg.SharedLists, deps.HTTPClient)
+	if err := lists.refresh(); err != nil {
+		glog.Errorf("ortb2blocking failed to load the shared block lists: %v", err)
+	}
+	go lists.Run()
+
+	return Module{sharedLists: lists}, nil
 }
 
-type Module struct{}
+type Module struct {
+	// sharedLists holds the block lists the account configs reference by name, nil when none are configured
+	sharedLists *sharedLists
+}
 
 // HandleBidderRequestHook updates blocking fields on the openrtb2.BidRequest.
 // Fields are updated only if request satisfies conditions provided by the module config.
@@ -31,8 +49,11 @@ func (m Module) HandleBidderRequestHook(
 	if err != nil {
 		return result, err
 	}
+	cfg, listVersions, warnings := cfg.withSharedLists(m.sharedLists)
 
-	return handleBidderRequestHook(cfg, payload)
+	result, err = handleBidderRequestHook(cfg, payload, listVersions)
+	result.Warnings = mergeStrings(result.Warnings, warnings...)
+	return result, err
 }
 
 // HandleRawBidderResponseHook rejects bids for a specific bidder if they fail the attribute check.
@@ -54,6 +75,14 @@ func (m Module) HandleRawBidderResponseHook(
 	return handleRawBidderResponseHook(cfg, payload, miCtx.ModuleContext)
 }
 
+// Shutdown stops reloading the shared block lists
+func (m Module) Shutdown() error {
+	if m.sharedLists != nil {
+		m.sharedLists.Shutdown()
+	}
+	return nil
+}
+
 type blockingAttributes struct {
 	bAdv   []string
 	bApp   []string
@@ -61,4 +90,7 @@ type blockingAttributes struct {
 	bType  map[string][]int
 	bAttr  map[string][]int
 	catTax adcom1.CategoryTaxonomy
+
+	// listVersions holds the versions of the shared lists merged into the blocked values, by list name
+	listVersions map[string]string
 }
@@ -0,0 +1,232 @@
+package ortb2blocking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+const (
+	defaultSharedListsRefreshRateSeconds = 300
+	defaultSharedListsTimeoutMS          = 2000
+)
+
+// hostConfig is the host-level configuration of the module
+type hostConfig struct {
+	SharedLists *SharedListsConfig `json:"shared_lists"`
+}
+
+// SharedListsConfig configures where the shared block lists are loaded from, either a file or a URL
+type SharedListsConfig struct {
+	Path               string `json:"path"`
+	URL                string `json:"url"`
+	RefreshRateSeconds int    `json:"refresh_rate_seconds"`
+	TimeoutMS          int    `json:"timeout_ms"`
+}
+
+func newHostConfig(data []byte) (hostConfig, error) {
+	var cfg hostConfig
+	if len(data) == 0 {
+		return cfg, nil
+	}
+	if err := jsonutil.UnmarshalValid(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse host config: %s", err)
+	}
+	if cfg.SharedLists == nil {
+		return cfg, nil
+	}
+
+	lists := cfg.SharedLists
+	if (lists.Path == "") == (lists.URL == "") {
+		return cfg, errors.New("shared_lists must have exactly one of path or url")
+	}
+	if lists.RefreshRateSeconds == 0 {
+		lists.RefreshRateSeconds = defaultSharedListsRefreshRateSeconds
+	}
+	if lists.TimeoutMS == 0 {
+		lists.TimeoutMS = defaultSharedListsTimeoutMS
+	}
+	if lists.RefreshRateSeconds < 0 || lists.TimeoutMS < 0 {
+		return cfg, errors.New("shared_lists refresh_rate_seconds and timeout_ms cannot be negative")
+	}
+	return cfg, nil
+}
+
+// sharedListsDocument is the document the shared lists are loaded from, holding the lists by name
+type sharedListsDocument struct {
+	Lists map[string]sharedList `json:"lists"`
+}
+
+// sharedList is a named block list shared by the accounts. Its values are read like the values of an
+// override: strings for the domains, categories and apps, numbers for the banner types and attributes.
+type sharedList struct {
+	Version string   `json:"version"`
+	Values  Override `json:"values"`
+}
+
+// sharedLists holds the shared block lists, reloaded from their source every refresh period. A failed reload
+// keeps the lists loaded last.
+type sharedLists struct {
+	load          func(ctx context.Context) ([]byte, error)
+	timeout       time.Duration
+	refreshPeriod time.Duration
+
+	lists atomic.Pointer[map[string]sharedList]
+	done  chan struct{}
+}
+
+func newSharedLists(cfg *SharedListsConfig, client *http.Client) *sharedLists {
+	l := &sharedLists{
+		timeout:       time.Duration(cfg.TimeoutMS) * time.Millisecond,
+		refreshPeriod: time.Duration(cfg.RefreshRateSeconds) * time.Second,
+		done:          make(chan struct{}),
+	}
+	if cfg.Path != "" {
+		l.load = func(context.Context) ([]byte, error) {
+			return os.ReadFile(cfg.Path)
+		}
+	} else {
+		if client == nil {
+			client = http.DefaultClient
+		}
+		l.load = func(ctx context.Context) ([]byte, error) {
+			return fetchSharedLists(ctx, client, cfg.URL)
+		}
+	}
+	return l
+}
+
+func fetchSharedLists(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// refresh reloads the lists from their source
+func (l *sharedLists) refresh() error {
+	ctx, cancel := context.WithTimeout(context.Background(), l.timeout)
+	defer cancel()
+
+	data, err := l.load(ctx)
+	if err != nil {
+		return err
+	}
+	var doc sharedListsDocument
+	if err := jsonutil.UnmarshalValid(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse shared lists: %s", err)
+	}
+	if doc.Lists == nil {
+		doc.Lists = map[string]sharedList{}
+	}
+	l.lists.Store(&doc.Lists)
+	return nil
+}
+
+// Run reloads the lists every refresh period until shut down
+func (l *sharedLists) Run() {
+	ticker := time.NewTicker(l.refreshPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := l.refresh(); err != nil {
+				glog.Errorf("ortb2blocking failed to refresh the shared block lists: %v", err)
+			}
+		case <-l.done:
+			return
+		}
+	}
+}
+
+// Shutdown stops the reloads
+func (l *sharedLists) Shutdown() {
+	close(l.done)
+}
+
+func (l *sharedLists) get(name string) (sharedList, bool) {
+	if l == nil {
+		return sharedList{}, false
+	}
+	lists := l.lists.Load()
+	if lists == nil {
+		return sharedList{}, false
+	}
+	list, ok := (*lists)[name]
+	return list, ok
+}
+
+// withSharedLists returns the config with the values of the shared lists it references merged into its inline
+// blocked values, along with the versions of the lists merged and warnings about the lists unavailable.
+func (cfg config) withSharedLists(lists *sharedLists) (config, map[string]string, []string) {
+	var versions map[string]string
+	var warnings []string
+	resolve := func(names []string) []sharedList {
+		resolved := make([]sharedList, 0, len(names))
+		for _, name := range names {
+			list, ok := lists.get(name)
+			if !ok {
+				warnings = append(warnings, fmt.Sprintf("shared list %s is not available", name))
+				continue
+			}
+			if versions == nil {
+				versions = make(map[string]string)
+			}
+			versions[name] = list.Version
+			resolved = append(resolved, list)
+		}
+		return resolved
+	}
+	names := func(inline []string, lists []sharedList) []string {
+		for _, list := range lists {
+			inline = appendMissing(inline, list.Values.Names...)
+		}
+		return inline
+	}
+	ids := func(inline []int, lists []sharedList) []int {
+		for _, list := range lists {
+			inline = appendMissing(inline, list.Values.Ids...)
+		}
+		return inline
+	}
+
+	attrs := &cfg.Attributes
+	attrs.Badv.BlockedAdomain = names(attrs.Badv.BlockedAdomain, resolve(attrs.Badv.BlockedAdomainLists))
+	attrs.Bcat.BlockedAdvCat = names(attrs.Bcat.BlockedAdvCat, resolve(attrs.Bcat.BlockedAdvCatLists))
+	attrs.Bapp.BlockedApp = names(attrs.Bapp.BlockedApp, resolve(attrs.Bapp.BlockedAppLists))
+	attrs.Btype.BlockedBannerType = ids(attrs.Btype.BlockedBannerType, resolve(attrs.Btype.BlockedBannerTypeLists))
+	attrs.Battr.BlockedBannerAttr = ids(attrs.Battr.BlockedBannerAttr, resolve(attrs.Battr.BlockedBannerAttrLists))
+
+	return cfg, versions, warnings
+}
+
+// appendMissing appends the values not in the slice yet, leaving the values of the slice given untouched
+func appendMissing[T comparable](values []T, newValues ...T) []T {
+	values = slices.Clip(values)
+	for _, v := range newValues {
+		if !slices.Contains(values, v) {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,206 @@
+package ortb2blocking
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSharedLists = `{"lists": {
+	"brand-safety": {"version": "2026-10-01", "values": ["bad.com", "worse.com"]},
+	"gambling": {"version": "7", "values": ["IAB7-39", "IAB9-30"]},
+	"risky-attrs": {"version": "2", "values": [1, 3]}
+}}`
+
+func writeSharedLists(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "lists.json")
+	require.NoError(t, os.WriteFile(path, []byte(data), 0644))
+	return path
+}
+
+func TestNewHostConfig(t *testing.T) {
+	testCases := []struct {
+		description    string
+		config         string
+		expectedConfig hostConfig
+		expectedError  string
+	}{
+		{
+			description: "empty",
+			config:      ``,
+		},
+		{
+			description: "no-shared-lists",
+			config:      `{}`,
+		},
+		{
+			description: "defaults",
+			config:      `{"shared_lists": {"url": "https://lists.example.com/blocking.json"}}`,
+			expectedConfig: hostConfig{SharedLists: &SharedListsConfig{
+				URL:                "https://lists.example.com/blocking.json",
+				RefreshRateSeconds: defaultSharedListsRefreshRateSeconds,
+				TimeoutMS:          defaultSharedListsTimeoutMS,
+			}},
+		},
+		{
+			description: "configured",
+			config:      `{"shared_lists": {"path": "/etc/pbs/lists.json", "refresh_rate_seconds": 60, "timeout_ms": 100}}`,
+			expectedConfig: hostConfig{SharedLists: &SharedListsConfig{
+				Path:               "/etc/pbs/lists.json",
+				RefreshRateSeconds: 60,
+				TimeoutMS:          100,
+			}},
+		},
+		{
+			description:   "no-source",
+			config:        `{"shared_lists": {}}`,
+			expectedError: "shared_lists must have exactly one of path or url",
+		},
+		{
+			description:   "both-sources",
+			config:        `{"shared_lists": {"path": "/etc/pbs/lists.json", "url": "https://lists.example.com/blocking.json"}}`,
+			expectedError: "shared_lists must have exactly one of path or url",
+		},
+		{
+			description:   "negative-refresh-rate",
+			config:        `{"shared_lists": {"path": "/etc/pbs/lists.json", "refresh_rate_seconds": -1}}`,
+			expectedError: "shared_lists refresh_rate_seconds and timeout_ms cannot be negative",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			cfg, err := newHostConfig([]byte(test.config))
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedConfig, cfg)
+		})
+	}
+}
+
+func TestSharedListsRefreshFromFile(t *testing.T) {
+	path := writeSharedLists(t, testSharedLists)
+	lists := newSharedLists(&SharedListsConfig{Path: path, TimeoutMS: 100, RefreshRateSeconds: 60}, nil)
+
+	_, ok := lists.get("brand-safety")
+	assert.False(t, ok, "no list is available before the first load")
+
+	require.NoError(t, lists.refresh())
+	list, ok := lists.get("brand-safety")
+	assert.True(t, ok)
+	assert.Equal(t, sharedList{Version: "2026-10-01", Values: Override{Names: []string{"bad.com", "worse.com"}}}, list)
+	list, ok = lists.get("risky-attrs")
+	assert.True(t, ok)
+	assert.Equal(t, []int{1, 3}, list.Values.Ids)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"lists": `), 0644))
+	assert.ErrorContains(t, lists.refresh(), "failed to parse shared lists")
+	_, ok = lists.get("brand-safety")
+	assert.True(t, ok, "a failed reload keeps the lists loaded last")
+}
+
+func TestSharedListsRefreshFromURL(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(testSharedLists))
+	}))
+	defer server.Close()
+	lists := newSharedLists(&SharedListsConfig{URL: server.URL, TimeoutMS: 1000, RefreshRateSeconds: 60}, server.Client())
+
+	require.NoError(t, lists.refresh())
+	list, ok := lists.get("gambling")
+	assert.True(t, ok)
+	assert.Equal(t, "7", list.Version)
+
+	status = http.StatusInternalServerError
+	assert.EqualError(t, lists.refresh(), "unexpected status code 500")
+	_, ok = lists.get("gambling")
+	assert.True(t, ok, "a failed reload keeps the lists loaded last")
+}
+
+func TestWithSharedLists(t *testing.T) {
+	lists := newSharedLists(&SharedListsConfig{Path: writeSharedLists(t, testSharedLists), TimeoutMS: 100, RefreshRateSeconds: 60}, nil)
+	require.NoError(t, lists.refresh())
+
+	cfg, err := newConfig(json.RawMessage(`{"attributes": {
+		"badv": {"blocked_adomain": ["inline.com", "bad.com"], "blocked_adomain_lists": ["brand-safety"]},
+		"bcat": {"blocked_adv_cat_lists": ["gambling", "unknown"]},
+		"battr": {"blocked_banner_attr": [2], "blocked_banner_attr_lists": ["risky-attrs"]}
+	}}`))
+	require.NoError(t, err)
+	inlineAdomains := cfg.Attributes.Badv.BlockedAdomain
+
+	merged, versions, warnings := cfg.withSharedLists(lists)
+
+	assert.Equal(t, []string{"inline.com", "bad.com", "worse.com"}, merged.Attributes.Badv.BlockedAdomain)
+	assert.Equal(t, []string{"IAB7-39", "IAB9-30"}, merged.Attributes.Bcat.BlockedAdvCat)
+	assert.Equal(t, []int{2, 1, 3}, merged.Attributes.Battr.BlockedBannerAttr)
+	assert.Empty(t, merged.Attributes.Bapp.BlockedApp)
+	assert.Equal(t, map[string]string{"brand-safety": "2026-10-01", "gambling": "7", "risky-attrs": "2"}, versions)
+	assert.Equal(t, []string{"shared list unknown is not available"}, warnings)
+	assert.Equal(t, []string{"inline.com", "bad.com"}, inlineAdomains, "the inline values are left untouched")
+
+	_, versions, warnings = cfg.withSharedLists(nil)
+	assert.Nil(t, versions)
+	assert.Len(t, warnings, 4, "no list is available without shared lists configured")
+}
+
+func TestSharedListsHooks(t *testing.T) {
+	path := writeSharedLists(t, testSharedLists)
+	result, err := Builder(json.RawMessage(`{"shared_lists": {"path": "`+path+`"}}`), moduledeps.ModuleDeps{})
+	require.NoError(t, err)
+	module, ok := result.(Module)
+	require.True(t, ok)
+	defer module.Shutdown()
+
+	accountConfig := json.RawMessage(`{"attributes": {"badv": {"enforce_blocks": true, "blocked_adomain": ["inline.com"], "blocked_adomain_lists": ["brand-safety"]}}}`)
+	payload := hookstage.BidderRequestPayload{Bidder: bidder, Request: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{}}}
+	requestResult, err := module.HandleBidderRequestHook(context.Background(), hookstage.ModuleInvocationContext{AccountConfig: accountConfig}, payload)
+	require.NoError(t, err)
+	for _, mut := range requestResult.ChangeSet.Mutations() {
+		payload, err = mut.Apply(payload)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"inline.com", "bad.com", "worse.com"}, payload.Request.BAdv)
+
+	responseResult, err := module.HandleRawBidderResponseHook(
+		context.Background(),
+		hookstage.ModuleInvocationContext{AccountConfig: accountConfig, ModuleContext: requestResult.ModuleContext},
+		hookstage.RawBidderResponsePayload{Bidder: bidder, BidderResponse: &adapters.BidderResponse{Bids: []*adapters.TypedBid{
+			{Bid: &openrtb2.Bid{ID: "1", ImpID: impID1, ADomain: []string{"worse.com"}}},
+			{Bid: &openrtb2.Bid{ID: "2", ImpID: impID2, ADomain: []string{"good.com"}}},
+		}}},
+	)
+	require.NoError(t, err)
+	versions := map[string]string{"brand-safety": "2026-10-01"}
+	assert.Equal(t, []hookanalytics.Result{
+		{
+			Status:    hookanalytics.ResultStatusBlock,
+			Values:    map[string]interface{}{attributesAnalyticKey: []string{"badv"}, badvAnalyticKey: []string{"worse.com"}, listsAnalyticKey: versions},
+			AppliedTo: hookanalytics.AppliedTo{Bidder: bidder, ImpIds: []string{impID1}},
+		},
+		{
+			Status:    hookanalytics.ResultStatusAllow,
+			Values:    map[string]interface{}{listsAnalyticKey: versions},
+			AppliedTo: hookanalytics.AppliedTo{Bidder: bidder, ImpIds: []string{impID2}},
+		},
+	}, responseResult.AnalyticsTags.Activities[0].Results)
+}
@@ -0,0 +1,74 @@
+package rtd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/coocood/freecache"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// segmentCache caches the segments of the providers for the features of the requests they were fetched for
+type segmentCache struct {
+	cache *freecache.Cache
+}
+
+func newSegmentCache(size int) *segmentCache {
+	return &segmentCache{cache: freecache.NewCache(size)}
+}
+
+func (c *segmentCache) get(key []byte) (Segments, bool) {
+	data, err := c.cache.Get(key)
+	if err != nil {
+		return Segments{}, false
+	}
+	var segments Segments
+	if err := jsonutil.Unmarshal(data, &segments); err != nil {
+		return Segments{}, false
+	}
+	return segments, true
+}
+
+func (c *segmentCache) set(key []byte, segments Segments, ttlSeconds int) error {
+	data, err := jsonutil.Marshal(segments)
+	if err != nil {
+		return err
+	}
+	return c.cache.Set(key, data, ttlSeconds)
+}
+
+// cacheKey hashes the features of the request a provider is expected to base its segments on. The key is built
+// out of the request sent to the provider, so data masked or scrubbed away never makes two requests differ.
+func cacheKey(provider string, request *openrtb2.BidRequest) []byte {
+	hasher := sha256.New()
+	write := func(feature, value string) {
+		if value != "" {
+			hasher.Write([]byte(feature + ":" + value + "\n"))
+		}
+	}
+
+	write("provider", provider)
+	if request.Site != nil {
+		write("site", request.Site.Domain)
+		write("page", request.Site.Page)
+	}
+	if request.App != nil {
+		write("app", request.App.Bundle)
+	}
+	if request.Device != nil && request.Device.Geo != nil {
+		write("country", request.Device.Geo.Country)
+		write("region", request.Device.Geo.Region)
+		write("metro", request.Device.Geo.Metro)
+	}
+	if request.User != nil {
+		write("user", request.User.ID)
+		for _, eid := range request.User.EIDs {
+			if len(eid.UIDs) > 0 {
+				write("eid:"+eid.Source, eid.UIDs[0].ID)
+			}
+		}
+	}
+
+	return []byte(hex.EncodeToString(hasher.Sum(nil)))
+}
@@ -0,0 +1,85 @@
+package rtd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+const (
+	// DestinationUser writes the segments into user.data
+	DestinationUser = "user"
+	// DestinationSite writes the segments into site.content.data, or app.content.data on app requests
+	DestinationSite = "site"
+)
+
+const (
+	defaultTimeoutMS = 100
+	defaultCacheTTL  = 60
+	defaultCacheSize = 10 * 1024 * 1024 // 10MB
+)
+
+// Config holds the module configuration
+type Config struct {
+	CacheSize int                       `json:"cache_size"` // Maximum size of the segment cache in bytes, shared by the providers
+	Providers map[string]ProviderConfig `json:"providers"`  // Configuration of the providers by the name they are built under
+}
+
+// ProviderConfig holds the configuration of a data provider run by the module
+type ProviderConfig struct {
+	Enabled        bool            `json:"enabled"`
+	TimeoutMS      int             `json:"timeout_ms"`        // Time budget of a segment fetch (default: 100)
+	CacheTTL       int             `json:"cache_ttl_seconds"` // Cache segments for this many seconds, a negative value disables caching (default: 60)
+	Destination    string          `json:"destination"`       // Where segments are written: user or site (default: user)
+	Segtax         int             `json:"segtax"`            // Segment taxonomy of the segments, set as the data.ext.segtax
+	AddToTargeting bool            `json:"add_to_targeting"`  // Add segments as targeting keys to the response
+	TargetingKey   string          `json:"targeting_key"`     // Targeting key the segment IDs are set under (default: <provider>_segs)
+	Masking        MaskingConfig   `json:"masking"`           // Privacy masking applied to the request sent to the provider
+	Params         json.RawMessage `json:"params"`            // Provider specific parameters, passed to the provider builder
+}
+
+func newConfig(data json.RawMessage) (Config, error) {
+	var cfg Config
+	if err := jsonutil.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+
+	if cfg.CacheSize == 0 {
+		cfg.CacheSize = defaultCacheSize
+	}
+
+	for name, providerCfg := range cfg.Providers {
+		if err := providerCfg.setDefaults(name); err != nil {
+			return cfg, fmt.Errorf("invalid config of provider %s: %w", name, err)
+		}
+		cfg.Providers[name] = providerCfg
+	}
+	return cfg, nil
+}
+
+func (c *ProviderConfig) setDefaults(name string) error {
+	if c.TimeoutMS == 0 {
+		c.TimeoutMS = defaultTimeoutMS
+	} else if c.TimeoutMS < 0 {
+		return errors.New("timeout_ms cannot be negative")
+	}
+	if c.CacheTTL == 0 {
+		c.CacheTTL = defaultCacheTTL
+	}
+
+	switch c.Destination {
+	case "":
+		c.Destination = DestinationUser
+	case DestinationUser, DestinationSite:
+	default:
+		return fmt.Errorf("destination must be %s or %s", DestinationUser, DestinationSite)
+	}
+
+	if c.TargetingKey == "" {
+		c.TargetingKey = name + "_segs"
+	}
+
+	return c.Masking.SetDefaults()
+}
@@ -0,0 +1,72 @@
+package rtd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewConfig(t *testing.T) {
+	testCases := []struct {
+		description    string
+		config         string
+		expectedConfig Config
+		expectedError  string
+	}{
+		{
+			description: "defaults",
+			config:      `{"providers":{"stub":{"enabled":true}}}`,
+			expectedConfig: Config{
+				CacheSize: defaultCacheSize,
+				Providers: map[string]ProviderConfig{"stub": {
+					Enabled:      true,
+					TimeoutMS:    defaultTimeoutMS,
+					CacheTTL:     defaultCacheTTL,
+					Destination:  DestinationUser,
+					TargetingKey: "stub_segs",
+				}},
+			},
+		},
+		{
+			description: "configured",
+			config: `{"cache_size":1024,"providers":{"stub":{"enabled":true,"timeout_ms":50,"cache_ttl_seconds":-1,
+				"destination":"site","segtax":4,"add_to_targeting":true,"targeting_key":"stub","params":{"key":"value"}}}}`,
+			expectedConfig: Config{
+				CacheSize: 1024,
+				Providers: map[string]ProviderConfig{"stub": {
+					Enabled:        true,
+					TimeoutMS:      50,
+					CacheTTL:       -1,
+					Destination:    DestinationSite,
+					Segtax:         4,
+					AddToTargeting: true,
+					TargetingKey:   "stub",
+					Params:         json.RawMessage(`{"key":"value"}`),
+				}},
+			},
+		},
+		{
+			description:   "negative-timeout",
+			config:        `{"providers":{"stub":{"timeout_ms":-1}}}`,
+			expectedError: "invalid config of provider stub: timeout_ms cannot be negative",
+		},
+		{
+			description:   "invalid-masking",
+			config:        `{"providers":{"stub":{"masking":{"enabled":true,"geo":{"lat_long_precision":5}}}}}`,
+			expectedError: "invalid config of provider stub: lat_long_precision cannot exceed 4 decimal places for privacy protection",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			cfg, err := newConfig(json.RawMessage(tc.config))
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedConfig, cfg)
+		})
+	}
+}
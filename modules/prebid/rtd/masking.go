@@ -0,0 +1,256 @@
+package rtd
+
+import (
+	"errors"
+	"math"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/util/iterutil"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// MaskingConfig controls what user data is masked before a request is sent to a data provider
+type MaskingConfig struct {
+	Enabled bool                `json:"enabled"`
+	Geo     GeoMaskingConfig    `json:"geo"`
+	User    UserMaskingConfig   `json:"user"`
+	Device  DeviceMaskingConfig `json:"device"`
+}
+
+// GeoMaskingConfig controls geographic data masking
+type GeoMaskingConfig struct {
+	PreserveMetro    bool `json:"preserve_metro"`     // DMA code (default: true)
+	PreserveZip      bool `json:"preserve_zip"`       // Postal code (default: true)
+	PreserveCity     bool `json:"preserve_city"`      // City name (default: false)
+	LatLongPrecision int  `json:"lat_long_precision"` // Decimal places for lat/long (0-4, default: 2)
+}
+
+// UserMaskingConfig controls user data masking
+type UserMaskingConfig struct {
+	PreserveEids []string `json:"preserve_eids"` // List of EID sources to preserve
+}
+
+// DeviceMaskingConfig controls device data masking
+type DeviceMaskingConfig struct {
+	PreserveMobileIds bool `json:"preserve_mobile_ids"` // Keep mobile advertising IDs (default: false)
+}
+
+// SetDefaults validates the masking configuration and fills in the defaults of an enabled configuration
+func (c *MaskingConfig) SetDefaults() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	// Validate and set geo precision (max 4 decimal places for privacy)
+	if c.Geo.LatLongPrecision == 0 {
+		c.Geo.LatLongPrecision = 2 // 2 decimal places default (~1.1km precision)
+	} else if c.Geo.LatLongPrecision > 4 {
+		return errors.New("lat_long_precision cannot exceed 4 decimal places for privacy protection")
+	} else if c.Geo.LatLongPrecision < 0 {
+		return errors.New("lat_long_precision cannot be negative")
+	}
+
+	// Set default EID allowlist if empty
+	if len(c.User.PreserveEids) == 0 {
+		// Default to preserving common identity providers
+		c.User.PreserveEids = []string{"liveramp.com", "uidapi.com", "id5-sync.com"}
+	}
+
+	// Set default preserve values for geo fields
+	if !c.Geo.PreserveMetro && !c.Geo.PreserveZip && !c.Geo.PreserveCity {
+		c.Geo.PreserveMetro = true
+		c.Geo.PreserveZip = true
+	}
+	return nil
+}
+
+// Mask creates a deep copy of the bid request with sensitive fields masked
+// according to the masking configuration. Returns nil if masking fails to prevent
+// accidental exposure of sensitive data.
+func (c MaskingConfig) Mask(original *openrtb2.BidRequest) *openrtb2.BidRequest {
+	if !c.Enabled {
+		return original
+	}
+
+	// Create a deep copy by marshaling and unmarshaling
+	data, err := jsonutil.Marshal(original)
+	if err != nil {
+		// Never return unmasked data - this prevents potential data leakage
+		// The calling function should handle nil gracefully
+		return nil
+	}
+
+	var masked openrtb2.BidRequest
+	if err := jsonutil.Unmarshal(data, &masked); err != nil {
+		// Never return unmasked data - this prevents potential data leakage
+		// The calling function should handle nil gracefully
+		return nil
+	}
+
+	// Apply masking to different sections
+	c.maskUser(&masked)
+	c.maskDevice(&masked)
+	c.maskGeo(&masked)
+
+	return &masked
+}
+
+// maskUser removes or filters user data according to privacy settings
+func (c MaskingConfig) maskUser(req *openrtb2.BidRequest) {
+	if req.User == nil {
+		return
+	}
+
+	// Always remove publisher's first-party user ID for privacy
+	req.User.ID = ""
+	req.User.BuyerUID = ""
+
+	// Always remove potentially sensitive demographic data
+	req.User.Yob = 0
+	req.User.Gender = ""
+
+	// Remove user data segments (first-party data)
+	req.User.Data = nil
+	req.User.Keywords = ""
+
+	// Filter user.eids to only preserve allowed identity providers
+	req.User.EIDs = c.filterEids(req.User.EIDs)
+}
+
+// filterEids filters the user.eids array to only include allowed identity providers
+func (c MaskingConfig) filterEids(eids []openrtb2.EID) []openrtb2.EID {
+	if len(c.User.PreserveEids) == 0 {
+		return []openrtb2.EID{}
+	}
+
+	// Create allowlist map for fast lookup
+	allowed := make(map[string]bool)
+	for _, source := range c.User.PreserveEids {
+		allowed[source] = true
+	}
+
+	// Filter eids to only include allowed sources
+	var filtered []openrtb2.EID
+	for eid := range iterutil.SlicePointerValues(eids) {
+		if allowed[eid.Source] {
+			// Intentionally copy the EID struct to create a new filtered slice
+			// that's independent of the original data
+			filtered = append(filtered, *eid)
+		}
+	}
+
+	return filtered
+}
+
+// maskDevice removes sensitive device information while preserving targeting-safe data
+func (c MaskingConfig) maskDevice(req *openrtb2.BidRequest) {
+	if req.Device == nil {
+		return
+	}
+
+	// Always remove IP addresses for privacy
+	req.Device.IP = ""
+	req.Device.IPv6 = ""
+
+	// Remove mobile advertising IDs unless explicitly preserved
+	if !c.Device.PreserveMobileIds {
+		req.Device.IFA = ""
+		req.Device.DPIDMD5 = ""
+		req.Device.DPIDSHA1 = ""
+		req.Device.DIDMD5 = ""
+		req.Device.DIDSHA1 = ""
+		req.Device.MACMD5 = ""
+		req.Device.MACSHA1 = ""
+	}
+
+	// Note: We preserve device characteristics like devicetype, os, browser, etc.
+	// as these are not considered personally identifiable and are useful for targeting
+}
+
+// maskGeo removes or truncates geographic data according to privacy settings
+func (c MaskingConfig) maskGeo(req *openrtb2.BidRequest) {
+	// Mask device geo
+	if req.Device != nil && req.Device.Geo != nil {
+		c.maskGeoObject(req.Device.Geo)
+	}
+
+	// Mask user geo (if different from device geo)
+	if req.User != nil && req.User.Geo != nil {
+		c.maskGeoObject(req.User.Geo)
+	}
+}
+
+// maskGeoObject applies geographic masking rules to a geo object
+func (c MaskingConfig) maskGeoObject(geo *openrtb2.Geo) {
+	// Always preserve country and region (state) as these are not considered PII
+	// geo.Country and geo.Region are preserved
+
+	// Handle optional geographic fields based on configuration
+	if !c.Geo.PreserveMetro {
+		geo.Metro = ""
+	}
+	if !c.Geo.PreserveZip {
+		geo.ZIP = ""
+	}
+	if !c.Geo.PreserveCity {
+		geo.City = ""
+	}
+
+	// Handle lat/long based on precision setting
+	if c.Geo.LatLongPrecision == 0 {
+		// Remove completely
+		geo.Lat = nil
+		geo.Lon = nil
+	} else if geo.Lat != nil && geo.Lon != nil {
+		// Truncate to specified precision
+		truncatedLat := truncateCoordinate(*geo.Lat, c.Geo.LatLongPrecision)
+		truncatedLon := truncateCoordinate(*geo.Lon, c.Geo.LatLongPrecision)
+		geo.Lat = &truncatedLat
+		geo.Lon = &truncatedLon
+	}
+
+	// Always remove high-precision location data
+	geo.Accuracy = 0 // GPS accuracy radius could reveal precision
+}
+
+// truncateCoordinate truncates a coordinate to the specified number of decimal places
+func truncateCoordinate(coord float64, precision int) float64 {
+	if precision <= 0 || precision > 4 {
+		return 0
+	}
+
+	multiplier := math.Pow(10, float64(precision))
+	// Use math.Trunc instead of math.Floor to handle negative numbers correctly
+	return math.Trunc(coord*multiplier) / multiplier
+}
+
+// Summary returns a summary of what fields would be masked for analytics/debugging
+func (c MaskingConfig) Summary() map[string]interface{} {
+	if !c.Enabled {
+		return map[string]interface{}{"enabled": false}
+	}
+
+	return map[string]interface{}{
+		"enabled": true,
+		"geo": map[string]interface{}{
+			"preserve_metro":     c.Geo.PreserveMetro,
+			"preserve_zip":       c.Geo.PreserveZip,
+			"preserve_city":      c.Geo.PreserveCity,
+			"lat_long_precision": c.Geo.LatLongPrecision,
+		},
+		"user": map[string]interface{}{
+			"preserve_eids": c.User.PreserveEids,
+		},
+		"device": map[string]interface{}{
+			"preserve_mobile_ids": c.Device.PreserveMobileIds,
+		},
+		"always_removed": []string{
+			"device.ip", "device.ipv6", "user.id", "user.buyeruid",
+			"user.yob", "user.gender", "user.data", "user.keywords", "geo.accuracy",
+		},
+		"never_removed": []string{
+			"geo.country", "geo.region", "device.devicetype", "device.os",
+			"device.browser", "device.make", "device.model", "site.*", "app.*", "imp.*",
+		},
+	}
+}
@@ -0,0 +1,356 @@
+// Package rtd implements a Prebid Server module running the real-time data providers of data vendors. The module
+// takes care of what the providers share: masking the request sent to them, enforcing activity controls, caching
+// and timing out their fetches, and writing their segments into the request and the targeting of the response.
+package rtd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/ortb"
+	"github.com/prebid/prebid-server/v3/privacy"
+	"github.com/prebid/prebid-server/v3/util/iputil"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+const (
+	activityName = "prebid-rtd"
+	// targetingKey is the module context key of the targeting keys added to the response
+	targetingKey = "rtd.targeting"
+)
+
+var (
+	_ hookstage.ProcessedAuctionRequest = (*Module)(nil)
+	_ hookstage.AuctionResponse         = (*Module)(nil)
+)
+
+// defaultIPConf is the IP masking applied to the request sent to a provider not allowed to receive the precise
+// geolocation, the account privacy config being unavailable to modules.
+var defaultIPConf = privacy.IPConf{
+	IPV6: config.IPv6{AnonKeepBits: iputil.IPv6DefaultMaskingBitSize},
+	IPV4: config.IPv4{AnonKeepBits: iputil.IPv4DefaultMaskingBitSize},
+}
+
+// Builder is the entry point for the module
+func Builder(cfg json.RawMessage, deps moduledeps.ModuleDeps) (interface{}, error) {
+	return newModule(cfg, deps, providerBuilders())
+}
+
+func newModule(data json.RawMessage, deps moduledeps.ModuleDeps, builders map[string]ProviderBuilder) (*Module, error) {
+	cfg, err := newConfig(data)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Module{cache: newSegmentCache(cfg.CacheSize)}
+	for name, providerCfg := range cfg.Providers {
+		if !providerCfg.Enabled {
+			continue
+		}
+		builder, ok := builders[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown provider %s", name)
+		}
+		provider, err := builder(providerCfg.Params, deps)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build provider %s: %w", name, err)
+		}
+		m.providers = append(m.providers, providerRunner{name: name, cfg: providerCfg, provider: provider})
+	}
+	slices.SortFunc(m.providers, func(a, b providerRunner) int {
+		return strings.Compare(a.name, b.name)
+	})
+
+	return m, nil
+}
+
+// Module runs the real-time data providers
+type Module struct {
+	providers []providerRunner
+	cache     *segmentCache
+}
+
+type providerRunner struct {
+	name     string
+	cfg      ProviderConfig
+	provider Provider
+}
+
+// providerResult is the outcome of running a provider for a request
+type providerResult struct {
+	segments Segments
+	cached   bool
+	blocked  bool
+	err      error
+}
+
+// HandleProcessedAuctionHook fetches the segments of the providers in parallel, within the budget of each, and
+// writes them into the request.
+func (m *Module) HandleProcessedAuctionHook(
+	ctx context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.ProcessedAuctionRequestPayload,
+) (hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload], error) {
+	var ret hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]
+	if len(m.providers) == 0 || payload.Request == nil || payload.Request.BidRequest == nil {
+		return ret, nil
+	}
+
+	results := make([]providerResult, len(m.providers))
+	var wg sync.WaitGroup
+	for i, runner := range m.providers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = m.run(ctx, miCtx.ActivityControl, runner, payload.Request)
+		}()
+	}
+	wg.Wait()
+
+	activity := hookanalytics.Activity{Name: activityName, Status: hookanalytics.ActivityStatusSuccess}
+	targeting := make(map[string]string)
+	var fetched []int
+	for i, result := range results {
+		runner := m.providers[i]
+		analyticsResult := hookanalytics.Result{
+			Status: hookanalytics.ResultStatusAllow,
+			Values: map[string]interface{}{"provider": runner.name},
+		}
+		switch {
+		case result.blocked:
+			analyticsResult.Status = hookanalytics.ResultStatusBlock
+			analyticsResult.Values["activity"] = privacy.ActivityEnrichUserFPD.String()
+		case result.err != nil:
+			analyticsResult.Status = hookanalytics.ResultStatusError
+			analyticsResult.Values["error"] = result.err.Error()
+			activity.Status = hookanalytics.ActivityStatusError
+		default:
+			analyticsResult.Values["segments"] = len(result.segments.IDs)
+			analyticsResult.Values["cached"] = result.cached
+			if len(result.segments.IDs) > 0 {
+				analyticsResult.Status = hookanalytics.ResultStatusModify
+				fetched = append(fetched, i)
+				if runner.cfg.AddToTargeting {
+					addTargeting(targeting, runner.cfg.TargetingKey, result.segments)
+				}
+			}
+		}
+		activity.Results = append(activity.Results, analyticsResult)
+	}
+	ret.AnalyticsTags = hookanalytics.Analytics{Activities: []hookanalytics.Activity{activity}}
+
+	if len(targeting) > 0 {
+		ret.ModuleContext = hookstage.ModuleContext{targetingKey: targeting}
+	}
+
+	for _, i := range fetched {
+		runner, segments := m.providers[i], results[i].segments
+		ret.ChangeSet.AddMutation(func(p hookstage.ProcessedAuctionRequestPayload) (hookstage.ProcessedAuctionRequestPayload, error) {
+			if p.Request == nil || p.Request.BidRequest == nil {
+				return p, nil
+			}
+			writeSegments(p.Request.BidRequest, runner, segments)
+			return p, nil
+		}, hookstage.MutationUpdate, "bidrequest", destinationPath(runner.cfg.Destination))
+	}
+
+	return ret, nil
+}
+
+// HandleAuctionResponseHook adds the targeting keys of the providers to the response
+func (m *Module) HandleAuctionResponseHook(
+	_ context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	_ hookstage.AuctionResponsePayload,
+) (hookstage.HookResult[hookstage.AuctionResponsePayload], error) {
+	var ret hookstage.HookResult[hookstage.AuctionResponsePayload]
+	targeting, ok := miCtx.ModuleContext[targetingKey].(map[string]string)
+	if !ok || len(targeting) == 0 {
+		return ret, nil
+	}
+
+	ret.ChangeSet.AddMutation(func(p hookstage.AuctionResponsePayload) (hookstage.AuctionResponsePayload, error) {
+		if p.BidResponse == nil {
+			return p, nil
+		}
+		var extMap map[string]interface{}
+		if len(p.BidResponse.Ext) == 0 || jsonutil.Unmarshal(p.BidResponse.Ext, &extMap) != nil || extMap == nil {
+			extMap = make(map[string]interface{})
+		}
+		prebidMap, ok := extMap["prebid"].(map[string]interface{})
+		if !ok {
+			prebidMap = make(map[string]interface{})
+			extMap["prebid"] = prebidMap
+		}
+		targetingMap, ok := prebidMap["targeting"].(map[string]interface{})
+		if !ok {
+			targetingMap = make(map[string]interface{})
+			prebidMap["targeting"] = targetingMap
+		}
+		for key, value := range targeting {
+			targetingMap[key] = value
+		}
+
+		ext, err := jsonutil.Marshal(extMap)
+		if err != nil {
+			return p, err
+		}
+		p.BidResponse.Ext = ext
+		return p, nil
+	}, hookstage.MutationUpdate, "ext")
+
+	return ret, nil
+}
+
+// run fetches the segments of the provider for the request, unless the activity controls of the account
+// forbid the provider from enriching the request.
+func (m *Module) run(ctx context.Context, activityControl privacy.ActivityControl, runner providerRunner, request *openrtb_ext.RequestWrapper) providerResult {
+	component := privacy.Component{Type: privacy.ComponentTypeRealTimeData, Name: runner.name}
+	activityRequest := privacy.NewRequestFromBidRequest(*request)
+	if !activityControl.Allow(privacy.ActivityEnrichUserFPD, component, activityRequest) {
+		return providerResult{blocked: true}
+	}
+
+	scrubbed := &openrtb_ext.RequestWrapper{BidRequest: ortb.CloneBidRequestPartial(request.BidRequest)}
+	if !activityControl.Allow(privacy.ActivityTransmitUserFPD, component, activityRequest) {
+		privacy.ScrubUserFPD(scrubbed)
+		activityControl.Scrubbed(privacy.ActivityTransmitUserFPD, component, privacy.ScrubberUserFPD, privacy.ScrubReasonActivity)
+	}
+	if !activityControl.Allow(privacy.ActivityTransmitPreciseGeo, component, activityRequest) {
+		privacy.ScrubGeoAndDeviceIP(scrubbed, defaultIPConf)
+		activityControl.Scrubbed(privacy.ActivityTransmitPreciseGeo, component, privacy.ScrubberGeoAndDeviceIP, privacy.ScrubReasonActivity)
+	}
+	if err := scrubbed.RebuildRequest(); err != nil {
+		return providerResult{err: err}
+	}
+
+	masked := runner.cfg.Masking.Mask(scrubbed.BidRequest)
+	if masked == nil {
+		// Masking failed - don't send request to prevent data leakage
+		return providerResult{err: errors.New("failed to mask bid request for privacy protection")}
+	}
+
+	key := cacheKey(runner.name, masked)
+	if runner.cfg.CacheTTL > 0 {
+		if segments, ok := m.cache.get(key); ok {
+			return providerResult{segments: segments, cached: true}
+		}
+	}
+
+	segments, err := fetch(ctx, runner, masked)
+	if err != nil {
+		return providerResult{err: err}
+	}
+
+	if runner.cfg.CacheTTL > 0 {
+		if err := m.cache.set(key, segments, runner.cfg.CacheTTL); err != nil {
+			glog.Infof("could not set segments of provider %s in cache: %v", runner.name, err)
+		}
+	}
+	return providerResult{segments: segments}
+}
+
+// fetch calls the provider within its time budget, returning as the budget runs out even if the provider
+// does not.
+func fetch(ctx context.Context, runner providerRunner, request *openrtb2.BidRequest) (Segments, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(runner.cfg.TimeoutMS)*time.Millisecond)
+	defer cancel()
+
+	type fetchResult struct {
+		segments Segments
+		err      error
+	}
+	done := make(chan fetchResult, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fetchResult{err: fmt.Errorf("panic in provider %s: %v", runner.name, r)}
+			}
+		}()
+		segments, err := runner.provider.FetchSegments(ctx, request)
+		done <- fetchResult{segments: segments, err: err}
+	}()
+
+	select {
+	case result := <-done:
+		return result.segments, result.err
+	case <-ctx.Done():
+		return Segments{}, fmt.Errorf("provider %s: %w", runner.name, ctx.Err())
+	}
+}
+
+func destinationPath(destination string) string {
+	if destination == DestinationSite {
+		return "site.content.data"
+	}
+	return "user.data"
+}
+
+// writeSegments sets the segments as the data of the provider in the destination of its configuration, replacing
+// any data of the provider the request already holds.
+func writeSegments(request *openrtb2.BidRequest, runner providerRunner, segments Segments) {
+	data := openrtb2.Data{Name: runner.name, Segment: make([]openrtb2.Segment, 0, len(segments.IDs))}
+	for _, id := range segments.IDs {
+		data.Segment = append(data.Segment, openrtb2.Segment{ID: id})
+	}
+	if runner.cfg.Segtax != 0 {
+		data.Ext = json.RawMessage(fmt.Sprintf(`{"segtax":%d}`, runner.cfg.Segtax))
+	}
+
+	switch runner.cfg.Destination {
+	case DestinationSite:
+		var content **openrtb2.Content
+		switch {
+		case request.Site != nil:
+			content = &request.Site.Content
+		case request.App != nil:
+			content = &request.App.Content
+		default:
+			return
+		}
+		if *content == nil {
+			*content = &openrtb2.Content{}
+		}
+		(*content).Data = replaceData((*content).Data, data)
+	default:
+		if request.User == nil {
+			request.User = &openrtb2.User{}
+		}
+		request.User.Data = replaceData(request.User.Data, data)
+	}
+}
+
+func replaceData(existing []openrtb2.Data, data openrtb2.Data) []openrtb2.Data {
+	replaced := make([]openrtb2.Data, 0, len(existing)+1)
+	for _, d := range existing {
+		if d.Name != data.Name {
+			replaced = append(replaced, d)
+		}
+	}
+	return append(replaced, data)
+}
+
+// addTargeting adds the targeting keys of the segments, or the segment IDs under the targeting key of the
+// provider when the provider sets none.
+func addTargeting(targeting map[string]string, key string, segments Segments) {
+	if len(segments.Targeting) > 0 {
+		for k, v := range segments.Targeting {
+			targeting[k] = v
+		}
+		return
+	}
+	targeting[key] = strings.Join(segments.IDs, ",")
+}
@@ -0,0 +1,349 @@
+package rtd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/privacy"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubProvider returns the same segments for every request, recording the requests it receives
+type stubProvider struct {
+	segments Segments
+	err      error
+	// block makes the provider wait for its context to be done
+	block bool
+
+	mu       sync.Mutex
+	requests []*openrtb2.BidRequest
+}
+
+func (p *stubProvider) FetchSegments(ctx context.Context, request *openrtb2.BidRequest) (Segments, error) {
+	p.mu.Lock()
+	p.requests = append(p.requests, request)
+	p.mu.Unlock()
+
+	if p.block {
+		<-ctx.Done()
+		return Segments{}, ctx.Err()
+	}
+	return p.segments, p.err
+}
+
+func stubBuilders(providers map[string]*stubProvider) map[string]ProviderBuilder {
+	builders := make(map[string]ProviderBuilder, len(providers))
+	for name, provider := range providers {
+		builders[name] = func(json.RawMessage, moduledeps.ModuleDeps) (Provider, error) {
+			return provider, nil
+		}
+	}
+	return builders
+}
+
+func newTestModule(t *testing.T, cfg string, providers map[string]*stubProvider) *Module {
+	t.Helper()
+	m, err := newModule(json.RawMessage(cfg), moduledeps.ModuleDeps{}, stubBuilders(providers))
+	require.NoError(t, err)
+	return m
+}
+
+func newTestPayload() hookstage.ProcessedAuctionRequestPayload {
+	lat, lon := 37.774929, -122.419416
+	return hookstage.ProcessedAuctionRequestPayload{Request: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+		ID:   "request-1",
+		Site: &openrtb2.Site{Domain: "example.com", Page: "https://example.com/news"},
+		User: &openrtb2.User{
+			ID:   "user-1",
+			Data: []openrtb2.Data{{Name: "publisher", Segment: []openrtb2.Segment{{ID: "p1"}}}},
+			EIDs: []openrtb2.EID{{Source: "liveramp.com", UIDs: []openrtb2.UID{{ID: "ramp-1"}}}},
+		},
+		Device: &openrtb2.Device{IP: "192.168.1.1", Geo: &openrtb2.Geo{Country: "USA", Lat: &lat, Lon: &lon}},
+	}}}
+}
+
+func applyMutations(t *testing.T, result hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload], payload hookstage.ProcessedAuctionRequestPayload) hookstage.ProcessedAuctionRequestPayload {
+	t.Helper()
+	for _, mut := range result.ChangeSet.Mutations() {
+		var err error
+		payload, err = mut.Apply(payload)
+		require.NoError(t, err)
+	}
+	return payload
+}
+
+func TestBuilder(t *testing.T) {
+	testCases := []struct {
+		description   string
+		config        string
+		expectedError string
+	}{
+		{
+			description: "no-providers",
+			config:      `{}`,
+		},
+		{
+			description: "disabled-unknown-provider",
+			config:      `{"providers":{"unknown":{"enabled":false}}}`,
+		},
+		{
+			description:   "enabled-unknown-provider",
+			config:        `{"providers":{"unknown":{"enabled":true}}}`,
+			expectedError: "unknown provider unknown",
+		},
+		{
+			description:   "invalid-provider-config",
+			config:        `{"providers":{"stub":{"enabled":true,"destination":"device"}}}`,
+			expectedError: "invalid config of provider stub: destination must be user or site",
+		},
+		{
+			description:   "malformed-config",
+			config:        `{"providers":[]}`,
+			expectedError: "failed to unmarshal config",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			m, err := newModule(json.RawMessage(tc.config), moduledeps.ModuleDeps{}, stubBuilders(map[string]*stubProvider{"stub": {}}))
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+				assert.Nil(t, m)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Empty(t, m.providers)
+		})
+	}
+}
+
+func TestHandleProcessedAuctionHook(t *testing.T) {
+	providers := map[string]*stubProvider{
+		"alpha": {segments: Segments{IDs: []string{"a1", "a2"}}},
+		"beta":  {segments: Segments{IDs: []string{"b1"}}},
+	}
+	m := newTestModule(t, `{"providers":{
+		"alpha":{"enabled":true,"segtax":4,"add_to_targeting":true},
+		"beta":{"enabled":true,"destination":"site","segtax":7,"masking":{"enabled":true}}
+	}}`, providers)
+
+	payload := newTestPayload()
+	result, err := m.HandleProcessedAuctionHook(context.Background(), hookstage.ModuleInvocationContext{}, payload)
+	require.NoError(t, err)
+	payload = applyMutations(t, result, payload)
+
+	assert.Equal(t, []openrtb2.Data{
+		{Name: "publisher", Segment: []openrtb2.Segment{{ID: "p1"}}},
+		{Name: "alpha", Segment: []openrtb2.Segment{{ID: "a1"}, {ID: "a2"}}, Ext: json.RawMessage(`{"segtax":4}`)},
+	}, payload.Request.User.Data)
+	assert.Equal(t, []openrtb2.Data{
+		{Name: "beta", Segment: []openrtb2.Segment{{ID: "b1"}}, Ext: json.RawMessage(`{"segtax":7}`)},
+	}, payload.Request.Site.Content.Data)
+	assert.Equal(t, hookstage.ModuleContext{targetingKey: map[string]string{"alpha_segs": "a1,a2"}}, result.ModuleContext)
+
+	require.Len(t, providers["alpha"].requests, 1)
+	require.Len(t, providers["beta"].requests, 1)
+	assert.Equal(t, "user-1", providers["alpha"].requests[0].User.ID, "an unmasked provider gets the user")
+	assert.Empty(t, providers["beta"].requests[0].User.ID, "a masked provider does not get the user ID")
+	assert.Empty(t, providers["beta"].requests[0].Device.IP)
+	assert.Equal(t, "user-1", payload.Request.User.ID, "masking leaves the request unchanged")
+
+	assert.Equal(t, hookanalytics.Analytics{Activities: []hookanalytics.Activity{{
+		Name:   activityName,
+		Status: hookanalytics.ActivityStatusSuccess,
+		Results: []hookanalytics.Result{
+			{Status: hookanalytics.ResultStatusModify, Values: map[string]interface{}{"provider": "alpha", "segments": 2, "cached": false}},
+			{Status: hookanalytics.ResultStatusModify, Values: map[string]interface{}{"provider": "beta", "segments": 1, "cached": false}},
+		},
+	}}}, result.AnalyticsTags)
+}
+
+func TestHandleProcessedAuctionHookCache(t *testing.T) {
+	provider := &stubProvider{segments: Segments{IDs: []string{"s1"}}}
+	m := newTestModule(t, `{"providers":{"stub":{"enabled":true}}}`, map[string]*stubProvider{"stub": provider})
+
+	for range 2 {
+		_, err := m.HandleProcessedAuctionHook(context.Background(), hookstage.ModuleInvocationContext{}, newTestPayload())
+		require.NoError(t, err)
+	}
+	assert.Len(t, provider.requests, 1, "the segments of the same request features are cached")
+
+	payload := newTestPayload()
+	payload.Request.Site.Page = "https://example.com/sports"
+	result, err := m.HandleProcessedAuctionHook(context.Background(), hookstage.ModuleInvocationContext{}, payload)
+	require.NoError(t, err)
+	assert.Len(t, provider.requests, 2, "another page is fetched")
+	assert.Equal(t, false, result.AnalyticsTags.Activities[0].Results[0].Values["cached"])
+
+	result, err = m.HandleProcessedAuctionHook(context.Background(), hookstage.ModuleInvocationContext{}, payload)
+	require.NoError(t, err)
+	assert.Len(t, provider.requests, 2)
+	assert.Equal(t, true, result.AnalyticsTags.Activities[0].Results[0].Values["cached"])
+
+	uncached := &stubProvider{segments: Segments{IDs: []string{"s1"}}}
+	m = newTestModule(t, `{"providers":{"stub":{"enabled":true,"cache_ttl_seconds":-1}}}`, map[string]*stubProvider{"stub": uncached})
+	for range 2 {
+		_, err := m.HandleProcessedAuctionHook(context.Background(), hookstage.ModuleInvocationContext{}, newTestPayload())
+		require.NoError(t, err)
+	}
+	assert.Len(t, uncached.requests, 2, "caching is disabled by a negative TTL")
+}
+
+func TestHandleProcessedAuctionHookErrors(t *testing.T) {
+	providers := map[string]*stubProvider{
+		"failing": {err: errors.New("vendor unavailable")},
+		"slow":    {block: true},
+		"working": {segments: Segments{IDs: []string{"w1"}}},
+	}
+	m := newTestModule(t, `{"providers":{
+		"failing":{"enabled":true},
+		"slow":{"enabled":true,"timeout_ms":5},
+		"working":{"enabled":true}
+	}}`, providers)
+
+	payload := newTestPayload()
+	result, err := m.HandleProcessedAuctionHook(context.Background(), hookstage.ModuleInvocationContext{}, payload)
+	require.NoError(t, err, "provider errors never fail the auction")
+	payload = applyMutations(t, result, payload)
+
+	assert.Equal(t, []string{"publisher", "working"}, dataNames(payload.Request.User.Data))
+	assert.Equal(t, hookanalytics.Analytics{Activities: []hookanalytics.Activity{{
+		Name:   activityName,
+		Status: hookanalytics.ActivityStatusError,
+		Results: []hookanalytics.Result{
+			{Status: hookanalytics.ResultStatusError, Values: map[string]interface{}{"provider": "failing", "error": "vendor unavailable"}},
+			{Status: hookanalytics.ResultStatusError, Values: map[string]interface{}{"provider": "slow", "error": "provider slow: context deadline exceeded"}},
+			{Status: hookanalytics.ResultStatusModify, Values: map[string]interface{}{"provider": "working", "segments": 1, "cached": false}},
+		},
+	}}}, result.AnalyticsTags)
+}
+
+func TestHandleProcessedAuctionHookActivityControls(t *testing.T) {
+	deny := config.Activity{Default: ptrutil.ToPtr(false)}
+	testCases := []struct {
+		description        string
+		activities         config.AllowActivities
+		expectedData       []string
+		expectedFetched    bool
+		expectedUserID     string
+		expectedPreciseGeo bool
+	}{
+		{
+			description:        "allowed",
+			expectedData:       []string{"publisher", "stub"},
+			expectedFetched:    true,
+			expectedUserID:     "user-1",
+			expectedPreciseGeo: true,
+		},
+		{
+			description:  "enrich-ufpd-denied",
+			activities:   config.AllowActivities{EnrichUserFPD: deny},
+			expectedData: []string{"publisher"},
+		},
+		{
+			description:        "transmit-ufpd-denied",
+			activities:         config.AllowActivities{TransmitUserFPD: deny},
+			expectedData:       []string{"publisher", "stub"},
+			expectedFetched:    true,
+			expectedPreciseGeo: true,
+		},
+		{
+			description:     "transmit-precise-geo-denied",
+			activities:      config.AllowActivities{TransmitPreciseGeo: deny},
+			expectedData:    []string{"publisher", "stub"},
+			expectedFetched: true,
+			expectedUserID:  "user-1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			provider := &stubProvider{segments: Segments{IDs: []string{"s1"}}}
+			m := newTestModule(t, `{"providers":{"stub":{"enabled":true,"cache_ttl_seconds":-1}}}`, map[string]*stubProvider{"stub": provider})
+			miCtx := hookstage.ModuleInvocationContext{
+				ActivityControl: privacy.NewActivityControl(&config.AccountPrivacy{AllowActivities: &tc.activities}),
+			}
+
+			payload := newTestPayload()
+			result, err := m.HandleProcessedAuctionHook(context.Background(), miCtx, payload)
+			require.NoError(t, err)
+			payload = applyMutations(t, result, payload)
+
+			assert.Equal(t, tc.expectedData, dataNames(payload.Request.User.Data))
+			if !tc.expectedFetched {
+				assert.Empty(t, provider.requests)
+				assert.Equal(t, hookanalytics.ResultStatusBlock, result.AnalyticsTags.Activities[0].Results[0].Status)
+				return
+			}
+			require.Len(t, provider.requests, 1)
+			sent := provider.requests[0]
+			assert.Equal(t, tc.expectedUserID, sent.User.ID)
+			assert.Equal(t, tc.expectedPreciseGeo, *sent.Device.Geo.Lat == 37.774929)
+			assert.Equal(t, "192.168.1.1", payload.Request.Device.IP, "scrubbing leaves the request unchanged")
+		})
+	}
+}
+
+func TestHandleProcessedAuctionHookSiteDestination(t *testing.T) {
+	provider := &stubProvider{segments: Segments{IDs: []string{"s1"}}}
+	m := newTestModule(t, `{"providers":{"stub":{"enabled":true,"destination":"site"}}}`, map[string]*stubProvider{"stub": provider})
+
+	payload := hookstage.ProcessedAuctionRequestPayload{Request: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+		App: &openrtb2.App{Bundle: "com.example", Content: &openrtb2.Content{Data: []openrtb2.Data{{Name: "stub", ID: "stale"}}}},
+	}}}
+	result, err := m.HandleProcessedAuctionHook(context.Background(), hookstage.ModuleInvocationContext{}, payload)
+	require.NoError(t, err)
+	payload = applyMutations(t, result, payload)
+
+	assert.Equal(t, []openrtb2.Data{{Name: "stub", Segment: []openrtb2.Segment{{ID: "s1"}}}}, payload.Request.App.Content.Data,
+		"app requests get the segments in app.content.data, replacing those of the provider")
+}
+
+func TestHandleAuctionResponseHook(t *testing.T) {
+	m := &Module{}
+	payload := hookstage.AuctionResponsePayload{BidResponse: &openrtb2.BidResponse{
+		Ext: json.RawMessage(`{"prebid":{"targeting":{"hb_pb":"1.00"}}}`),
+	}}
+
+	result, err := m.HandleAuctionResponseHook(context.Background(), hookstage.ModuleInvocationContext{}, payload)
+	require.NoError(t, err)
+	assert.Empty(t, result.ChangeSet.Mutations(), "no targeting is added when no provider set any")
+
+	miCtx := hookstage.ModuleInvocationContext{ModuleContext: hookstage.ModuleContext{
+		targetingKey: map[string]string{"stub_segs": "s1,s2", "vendor_tier": "gold"},
+	}}
+	result, err = m.HandleAuctionResponseHook(context.Background(), miCtx, payload)
+	require.NoError(t, err)
+	for _, mut := range result.ChangeSet.Mutations() {
+		payload, err = mut.Apply(payload)
+		require.NoError(t, err)
+	}
+	assert.JSONEq(t, `{"prebid":{"targeting":{"hb_pb":"1.00","stub_segs":"s1,s2","vendor_tier":"gold"}}}`, string(payload.BidResponse.Ext))
+}
+
+func TestAddTargeting(t *testing.T) {
+	targeting := make(map[string]string)
+	addTargeting(targeting, "stub_segs", Segments{IDs: []string{"s1", "s2"}})
+	addTargeting(targeting, "other_segs", Segments{IDs: []string{"o1"}, Targeting: map[string]string{"other_tier": "gold"}})
+
+	assert.Equal(t, map[string]string{"stub_segs": "s1,s2", "other_tier": "gold"}, targeting)
+}
+
+func dataNames(data []openrtb2.Data) []string {
+	names := make([]string, 0, len(data))
+	for _, d := range data {
+		names = append(names, d.Name)
+	}
+	return names
+}
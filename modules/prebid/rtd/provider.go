@@ -0,0 +1,35 @@
+package rtd
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+)
+
+// Provider fetches the segments a data vendor has for a bid request. The request is masked according to the
+// provider configuration and scrubbed according to the activity controls of the account beforehand. The fetch
+// must give up once the context is done.
+type Provider interface {
+	FetchSegments(ctx context.Context, request *openrtb2.BidRequest) (Segments, error)
+}
+
+// Segments are the segments a provider has for a bid request
+type Segments struct {
+	IDs []string `json:"ids"`
+	// Targeting holds the targeting keys the provider sets along with the segments. When empty, the segment IDs
+	// are set under the targeting key of the provider configuration.
+	Targeting map[string]string `json:"targeting,omitempty"`
+}
+
+// ProviderBuilder builds a provider out of the params of its configuration
+type ProviderBuilder func(params json.RawMessage, deps moduledeps.ModuleDeps) (Provider, error)
+
+// providerBuilders returns the providers the module can run by name. A data vendor integrates with the module
+// by adding its provider builder here instead of implementing its own module.
+func providerBuilders() map[string]ProviderBuilder {
+	return map[string]ProviderBuilder{
+		"scope3": newScope3Provider,
+	}
+}
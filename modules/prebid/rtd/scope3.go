@@ -0,0 +1,154 @@
+package rtd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+	"github.com/prebid/prebid-server/v3/util/iterutil"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// DefaultScope3URL is the Scope3 real-time data endpoint
+const DefaultScope3URL = "https://rtdp.scope3.com/prebid/prebid"
+
+// scope3MacroTargetingKey is the targeting key of the macro returned by Scope3
+const scope3MacroTargetingKey = "scope3_macro"
+
+// Response types for Scope3 API
+type Scope3Response struct {
+	Data []Scope3Data `json:"data"`
+}
+
+type Scope3Data struct {
+	Destination string          `json:"destination"`
+	Imp         []Scope3ImpData `json:"imp"`
+}
+
+type Scope3ImpData struct {
+	ID  string     `json:"id"`
+	Ext *Scope3Ext `json:"ext,omitempty"`
+}
+
+type Scope3Ext struct {
+	Scope3 *Scope3ExtData `json:"scope3"`
+}
+
+type Scope3ExtData struct {
+	Segments []Scope3Segment `json:"segments"`
+	Macro    string          `json:"macro"`
+}
+
+type Scope3Segment struct {
+	ID string `json:"id"`
+}
+
+// Scope3Client calls the Scope3 real-time data API
+type Scope3Client struct {
+	Endpoint   string
+	AuthKey    string
+	HTTPClient *http.Client
+}
+
+// Fetch sends the bid request to Scope3, returning the segments of its imps, sorted, and the macro, if any.
+func (c *Scope3Client) Fetch(ctx context.Context, request *openrtb2.BidRequest) ([]string, string, error) {
+	requestBody, err := jsonutil.Marshal(request)
+	if err != nil {
+		return nil, "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.Endpoint, bytes.NewReader(requestBody))
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-scope3-auth", c.AuthKey)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("scope3 returned status %d", resp.StatusCode)
+	}
+
+	var scope3Resp Scope3Response
+	if err := jsoniter.ConfigCompatibleWithStandardLibrary.NewDecoder(resp.Body).Decode(&scope3Resp); err != nil {
+		return nil, "", err
+	}
+
+	// Extract unique segments of the imps, the destinations not being segments
+	segmentMap := make(map[string]struct{})
+	var macro string
+	for data := range iterutil.SlicePointerValues(scope3Resp.Data) {
+		for imp := range iterutil.SlicePointerValues(data.Imp) {
+			if imp.Ext == nil || imp.Ext.Scope3 == nil {
+				continue
+			}
+			if imp.Ext.Scope3.Macro != "" {
+				macro = imp.Ext.Scope3.Macro
+			}
+			for segment := range iterutil.SlicePointerValues(imp.Ext.Scope3.Segments) {
+				segmentMap[segment.ID] = struct{}{}
+			}
+		}
+	}
+	segments := slices.AppendSeq(make([]string, 0, len(segmentMap)), maps.Keys(segmentMap))
+	slices.Sort(segments)
+	return segments, macro, nil
+}
+
+type scope3Params struct {
+	Endpoint string `json:"endpoint"` // Scope3 endpoint (default: DefaultScope3URL)
+	AuthKey  string `json:"auth_key"`
+}
+
+// scope3Provider fetches the segments of Scope3. Each segment is set as a targeting key with the true value, along
+// with the macro under the scope3_macro key, for ad servers like GAM.
+type scope3Provider struct {
+	client *Scope3Client
+}
+
+func newScope3Provider(params json.RawMessage, deps moduledeps.ModuleDeps) (Provider, error) {
+	var cfg scope3Params
+	if len(params) > 0 {
+		if err := jsonutil.Unmarshal(params, &cfg); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal params: %w", err)
+		}
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = DefaultScope3URL
+	}
+
+	// the fetch is bounded by the timeout of the provider configuration through its context
+	httpClient := &http.Client{}
+	if deps.HTTPClient != nil {
+		httpClient.Transport = deps.HTTPClient.Transport
+	}
+	return &scope3Provider{client: &Scope3Client{Endpoint: cfg.Endpoint, AuthKey: cfg.AuthKey, HTTPClient: httpClient}}, nil
+}
+
+func (p *scope3Provider) FetchSegments(ctx context.Context, request *openrtb2.BidRequest) (Segments, error) {
+	ids, macro, err := p.client.Fetch(ctx, request)
+	if err != nil {
+		return Segments{}, err
+	}
+
+	segments := Segments{IDs: ids, Targeting: make(map[string]string, len(ids)+1)}
+	for _, id := range ids {
+		segments.Targeting[id] = "true"
+	}
+	if macro != "" {
+		segments.Targeting[scope3MacroTargetingKey] = macro
+	}
+	return segments, nil
+}
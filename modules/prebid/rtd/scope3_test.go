@@ -0,0 +1,101 @@
+package rtd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newScope3Server(t *testing.T, status int, calls *atomic.Int32) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		assert.Equal(t, "test-auth-key", r.Header.Get("x-scope3-auth"))
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var request openrtb2.BidRequest
+		require.NoError(t, jsonutil.UnmarshalValid(body, &request))
+		assert.Empty(t, request.User.ID, "the request sent to Scope3 is masked")
+		assert.Empty(t, request.Device.IP, "the request sent to Scope3 is masked")
+
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"data":[{"destination":"triplelift.com","imp":[{"id":"imp-1","ext":{"scope3":{
+			"macro":"test-macro","segments":[{"id":"gmp_plus_eligible"},{"id":"gmp_eligible"}]}}}]}]}`))
+	}))
+}
+
+func newScope3Module(t *testing.T, endpoint string) *Module {
+	t.Helper()
+	module, err := Builder(json.RawMessage(`{"providers":{"scope3":{
+		"enabled": true,
+		"add_to_targeting": true,
+		"masking": {"enabled": true},
+		"params": {"endpoint": "`+endpoint+`", "auth_key": "test-auth-key"}
+	}}}`), moduledeps.ModuleDeps{HTTPClient: http.DefaultClient})
+	require.NoError(t, err)
+	return module.(*Module)
+}
+
+func TestScope3Provider(t *testing.T) {
+	var calls atomic.Int32
+	server := newScope3Server(t, http.StatusOK, &calls)
+	defer server.Close()
+	m := newScope3Module(t, server.URL)
+
+	payload := newTestPayload()
+	result, err := m.HandleProcessedAuctionHook(context.Background(), hookstage.ModuleInvocationContext{}, payload)
+	require.NoError(t, err)
+	payload = applyMutations(t, result, payload)
+
+	assert.Equal(t, []openrtb2.Data{
+		{Name: "publisher", Segment: []openrtb2.Segment{{ID: "p1"}}},
+		{Name: "scope3", Segment: []openrtb2.Segment{{ID: "gmp_eligible"}, {ID: "gmp_plus_eligible"}}},
+	}, payload.Request.BidRequest.User.Data)
+
+	responsePayload := hookstage.AuctionResponsePayload{BidResponse: &openrtb2.BidResponse{}}
+	responseResult, err := m.HandleAuctionResponseHook(context.Background(), hookstage.ModuleInvocationContext{ModuleContext: result.ModuleContext}, responsePayload)
+	require.NoError(t, err)
+	for _, mut := range responseResult.ChangeSet.Mutations() {
+		responsePayload, err = mut.Apply(responsePayload)
+		require.NoError(t, err)
+	}
+	assert.JSONEq(t, `{"prebid":{"targeting":{"gmp_eligible":"true","gmp_plus_eligible":"true","scope3_macro":"test-macro"}}}`,
+		string(responsePayload.BidResponse.Ext))
+
+	result, err = m.HandleProcessedAuctionHook(context.Background(), hookstage.ModuleInvocationContext{}, newTestPayload())
+	require.NoError(t, err)
+	assert.Equal(t, true, result.AnalyticsTags.Activities[0].Results[0].Values["cached"])
+	assert.Equal(t, int32(1), calls.Load(), "the segments are cached")
+}
+
+func TestScope3ProviderError(t *testing.T) {
+	var calls atomic.Int32
+	server := newScope3Server(t, http.StatusInternalServerError, &calls)
+	defer server.Close()
+	m := newScope3Module(t, server.URL)
+
+	result, err := m.HandleProcessedAuctionHook(context.Background(), hookstage.ModuleInvocationContext{}, newTestPayload())
+	require.NoError(t, err)
+	assert.Empty(t, result.ChangeSet.Mutations())
+	assert.Equal(t, []hookanalytics.Activity{{
+		Name:   activityName,
+		Status: hookanalytics.ActivityStatusError,
+		Results: []hookanalytics.Result{{
+			Status: hookanalytics.ResultStatusError,
+			Values: map[string]interface{}{"provider": "scope3", "error": "scope3 returned status 500"},
+		}},
+	}}, result.AnalyticsTags.Activities)
+}
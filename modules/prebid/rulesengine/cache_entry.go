@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/prebid/prebid-server/v3/hooks"
@@ -20,6 +21,14 @@ type cacheEntry struct {
 	timestamp                               time.Time
 	hashedConfig                            hash
 	ruleSetsForProcessedAuctionRequestStage []cacheRuleSet[openrtb_ext.RequestWrapper, ProcessedAuctionHookResult]
+
+	impRuleSetsForProcessedAuctionRequestStage []ImpRuleSet
+	ruleSetsForBidderRequestStage              []BidderRequestRuleSet
+	ruleSetsForRawBidderResponseStage          []BidRuleSet
+	ruleSetsForAllProcessedBidResponsesStage   []BidRuleSet
+
+	// version is the version of the configuration, reported in the analytics tags of the module
+	version string
 }
 type cacheRuleSet[T1 any, T2 any] struct {
 	name        string
@@ -49,6 +58,7 @@ func NewCacheEntry(cfg *config.PbRulesEngine, cfgRaw *json.RawMessage, geoscopes
 		enabled:      cfg.Enabled,
 		timestamp:    time.Now(),
 		hashedConfig: idHash,
+		version:      cfg.Version,
 	}
 
 	if cfg.GenerateRulesFromBidderConfig {
@@ -60,47 +70,89 @@ func NewCacheEntry(cfg *config.PbRulesEngine, cfgRaw *json.RawMessage, geoscopes
 	}
 
 	for _, ruleSet := range cfg.RuleSets {
-		if ruleSet.Stage != hooks.StageProcessedAuctionRequest {
-			// TODO: log error / metric --> stage not supported
-			continue
+		if err := newCacheObj.addRuleSet(&ruleSet); err != nil {
+			return cacheEntry{}, fmt.Errorf("invalid rule set %q: %w", ruleSet.Name, err)
 		}
-		crs, err := createCacheRuleSet(&ruleSet)
-		if err != nil {
-			// TODO: log error / metric -->
-			continue
-		}
-
-		newCacheObj.ruleSetsForProcessedAuctionRequestStage = append(newCacheObj.ruleSetsForProcessedAuctionRequestStage, crs)
 	}
 
 	return newCacheObj, nil
 }
 
+// addRuleSet builds the trees of the rule set for the stage it runs at. A rule set with an invalid schema or result
+// function, or for a stage the module doesn't run at, fails the whole configuration.
+func (c *cacheEntry) addRuleSet(ruleSet *config.RuleSet) error {
+	switch ruleSet.Stage {
+	case hooks.StageProcessedAuctionRequest:
+		if ruleSet.Scope == config.ScopeImp {
+			crs, err := newCacheRuleSet(ruleSet, rules.NewImpSchemaFunction, NewImpResultFunction)
+			if err != nil {
+				return err
+			}
+			c.impRuleSetsForProcessedAuctionRequestStage = append(c.impRuleSetsForProcessedAuctionRequestStage, crs)
+			return nil
+		}
+		crs, err := createCacheRuleSet(ruleSet)
+		if err != nil {
+			return err
+		}
+		c.ruleSetsForProcessedAuctionRequestStage = append(c.ruleSetsForProcessedAuctionRequestStage, crs)
+	case hooks.StageBidderRequest:
+		crs, err := newCacheRuleSet(ruleSet, rules.NewBidderRequestSchemaFunction, NewBidderRequestResultFunction)
+		if err != nil {
+			return err
+		}
+		c.ruleSetsForBidderRequestStage = append(c.ruleSetsForBidderRequestStage, crs)
+	case hooks.StageRawBidderResponse:
+		crs, err := newCacheRuleSet(ruleSet, rules.NewBidSchemaFunction, NewRawBidderResponseResultFunction)
+		if err != nil {
+			return err
+		}
+		c.ruleSetsForRawBidderResponseStage = append(c.ruleSetsForRawBidderResponseStage, crs)
+	case hooks.StageAllProcessedBidResponses:
+		crs, err := newCacheRuleSet(ruleSet, rules.NewBidSchemaFunction, NewAllProcessedBidResponsesResultFunction)
+		if err != nil {
+			return err
+		}
+		c.ruleSetsForAllProcessedBidResponsesStage = append(c.ruleSetsForAllProcessedBidResponsesStage, crs)
+	default:
+		return fmt.Errorf("stage %s not supported", ruleSet.Stage)
+	}
+	return nil
+}
+
 // createCacheRuleSet creates a new cache rule set for the given configuration
 // It builds the tree structures for the model groups and stores them in the cache rule set
 func createCacheRuleSet(cfg *config.RuleSet) (cacheRuleSet[openrtb_ext.RequestWrapper, ProcessedAuctionHookResult], error) {
+	return newCacheRuleSet(cfg, rules.NewRequestSchemaFunction, NewProcessedAuctionRequestResultFunction)
+}
+
+// newCacheRuleSet creates a new cache rule set for the given configuration, building the trees of its model groups
+// with the schema and result functions of the stage the rule set runs at
+func newCacheRuleSet[T1 any, T2 any](cfg *config.RuleSet, schemaFuncFactory rules.SchemaFuncFactory[T1], resultFuncFactory rules.ResultFuncFactory[T1, T2]) (cacheRuleSet[T1, T2], error) {
 	if cfg == nil {
-		return cacheRuleSet[openrtb_ext.RequestWrapper, ProcessedAuctionHookResult]{}, errors.New("no rules engine configuration provided")
+		return cacheRuleSet[T1, T2]{}, errors.New("no rules engine configuration provided")
 	}
 
-	crs := cacheRuleSet[openrtb_ext.RequestWrapper, ProcessedAuctionHookResult]{
+	crs := cacheRuleSet[T1, T2]{
 		name:        cfg.Name,
-		modelGroups: []cacheModelGroup[openrtb_ext.RequestWrapper, ProcessedAuctionHookResult]{},
+		modelGroups: []cacheModelGroup[T1, T2]{},
 	}
 
 	for _, modelGroup := range cfg.ModelGroups {
-		tree, err := rules.NewTree[openrtb_ext.RequestWrapper, ProcessedAuctionHookResult](
-			&treeBuilder[openrtb_ext.RequestWrapper, ProcessedAuctionHookResult]{
+		tree, err := rules.NewTree[T1, T2](
+			&treeBuilder[T1, T2]{
 				Config:            modelGroup,
-				SchemaFuncFactory: rules.NewRequestSchemaFunction,
-				ResultFuncFactory: NewProcessedAuctionRequestResultFunction,
+				SchemaFuncFactory: schemaFuncFactory,
+				ResultFuncFactory: resultFuncFactory,
 			},
 		)
 		if err != nil {
 			return crs, err
 		}
+		tree.AnalyticsKey = modelGroup.AnalyticsKey
+		tree.ModelVersion = modelGroup.Version
 
-		cmg := cacheModelGroup[openrtb_ext.RequestWrapper, ProcessedAuctionHookResult]{
+		cmg := cacheModelGroup[T1, T2]{
 			weight:       modelGroup.Weight,
 			version:      modelGroup.Version,
 			analyticsKey: modelGroup.AnalyticsKey,
@@ -10,6 +10,7 @@ import (
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/rules"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewCacheEntry(t *testing.T) {
@@ -56,6 +57,7 @@ func TestNewCacheEntry(t *testing.T) {
 			},
 			inCfgRaw:             getValidJsonConfig(),
 			expectedRulesetCount: 0,
+			expectedErr:          errors.New(`invalid rule set "": stage wrong-stage not supported`),
 		},
 		{
 			name: "createCacheRuleSet-throws-error",
@@ -77,6 +79,7 @@ func TestNewCacheEntry(t *testing.T) {
 			},
 			inCfgRaw:             getValidJsonConfig(),
 			expectedRulesetCount: 0,
+			expectedErr:          errors.New(`invalid rule set "": result function unknownResultFunction was not created`),
 		},
 		{
 			name: "dynamic-ruleset",
@@ -153,7 +156,8 @@ func TestNewCacheEntry(t *testing.T) {
 				},
 			},
 			inCfgRaw:             getValidJsonConfig(),
-			expectedRulesetCount: 1,
+			expectedRulesetCount: 0,
+			expectedErr:          errors.New(`invalid rule set "": stage wrong-stage not supported`),
 		},
 		{
 			name: "Multiple-entries-with-supported-rulesets",
@@ -197,7 +201,88 @@ func TestNewCacheEntry(t *testing.T) {
 			cacheEntry, err := NewCacheEntry(tc.inCfg, tc.inCfgRaw, map[string][]string{})
 
 			assert.Len(t, cacheEntry.ruleSetsForProcessedAuctionRequestStage, tc.expectedRulesetCount)
-			assert.Equal(t, tc.expectedErr, err)
+			if tc.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedErr.Error())
+			}
+		})
+	}
+}
+
+func TestNewCacheEntryRuleSetStages(t *testing.T) {
+	newRuleSet := func(stage hooks.Stage, scope string, resultFunc string, args string) config.RuleSet {
+		return config.RuleSet{
+			Stage: stage,
+			Scope: scope,
+			ModelGroups: []config.ModelGroup{
+				{
+					AnalyticsKey: "key",
+					Version:      "1.0",
+					Default:      []config.Result{{Func: resultFunc, Args: json.RawMessage(args)}},
+				},
+			},
+		}
+	}
+	cfg := &config.PbRulesEngine{
+		RuleSets: []config.RuleSet{
+			newRuleSet(hooks.StageProcessedAuctionRequest, "", ExcludeBiddersName, `{"bidders":["bidderA"]}`),
+			newRuleSet(hooks.StageProcessedAuctionRequest, config.ScopeRequest, IncludeBiddersName, `{"bidders":["bidderA"]}`),
+			newRuleSet(hooks.StageProcessedAuctionRequest, config.ScopeImp, ExcludeBiddersName, `{"bidders":["bidderA"]}`),
+			newRuleSet(hooks.StageBidderRequest, "", AddBcatName, `{"categories":["IAB1"]}`),
+			newRuleSet(hooks.StageRawBidderResponse, "", RejectBidName, `{}`),
+			newRuleSet(hooks.StageAllProcessedBidResponses, "", SetTargetingName, `{"targeting":{"hb_rule":"x"}}`),
+		},
+	}
+
+	cacheEntry, err := NewCacheEntry(cfg, getValidJsonConfig(), map[string][]string{})
+
+	assert.NoError(t, err)
+	assert.Len(t, cacheEntry.ruleSetsForProcessedAuctionRequestStage, 2)
+	assert.Len(t, cacheEntry.impRuleSetsForProcessedAuctionRequestStage, 1)
+	assert.Len(t, cacheEntry.ruleSetsForBidderRequestStage, 1)
+	assert.Len(t, cacheEntry.ruleSetsForRawBidderResponseStage, 1)
+	assert.Len(t, cacheEntry.ruleSetsForAllProcessedBidResponsesStage, 1)
+
+	tree := cacheEntry.impRuleSetsForProcessedAuctionRequestStage[0].modelGroups[0].tree
+	assert.Equal(t, "key", tree.AnalyticsKey)
+	assert.Equal(t, "1.0", tree.ModelVersion)
+}
+
+func TestNewCacheEntryInvalidRuleSetStages(t *testing.T) {
+	testCases := []struct {
+		name        string
+		ruleSet     config.RuleSet
+		expectedErr string
+	}{
+		{
+			name:        "imp-scope-result-function-of-request-scope",
+			ruleSet:     config.RuleSet{Name: "imp", Stage: hooks.StageProcessedAuctionRequest, Scope: config.ScopeImp, ModelGroups: []config.ModelGroup{{Default: []config.Result{{Func: AddBcatName, Args: json.RawMessage(`{"categories":["IAB1"]}`)}}}}},
+			expectedErr: `invalid rule set "imp": result function addBcat was not created`,
+		},
+		{
+			name:        "bid-result-function-of-request-stage",
+			ruleSet:     config.RuleSet{Name: "bids", Stage: hooks.StageAllProcessedBidResponses, ModelGroups: []config.ModelGroup{{Default: []config.Result{{Func: ExcludeBiddersName, Args: json.RawMessage(`{"bidders":["bidderA"]}`)}}}}},
+			expectedErr: `invalid rule set "bids": result function excludeBidders was not created`,
+		},
+		{
+			name: "unknown-schema-function",
+			ruleSet: config.RuleSet{Name: "bidder", Stage: hooks.StageBidderRequest, ModelGroups: []config.ModelGroup{{
+				Schema: []config.Schema{{Func: "unknownSchemaFunction"}},
+				Rules:  []config.Rule{{Conditions: []string{"*"}, Results: []config.Result{{Func: AddBcatName, Args: json.RawMessage(`{"categories":["IAB1"]}`)}}}},
+			}}},
+			expectedErr: `invalid rule set "bidder": Schema function unknownSchemaFunction was not created`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := &config.PbRulesEngine{RuleSets: []config.RuleSet{test.ruleSet}}
+
+			_, err := NewCacheEntry(cfg, getValidJsonConfig(), map[string][]string{})
+
+			require.Error(t, err)
+			assert.EqualError(t, err, test.expectedErr)
 		})
 	}
 }
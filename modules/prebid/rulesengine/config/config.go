@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 
 	"github.com/prebid/prebid-server/v3/hooks"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
 	"github.com/xeipuuv/gojsonschema"
 )
@@ -21,6 +22,10 @@ type PbRulesEngine struct {
 	SetDefinitions                SetDefinitions `json:"set_definitions,omitempty"`
 	Timestamp                     string         `json:"timestamp,omitempty"`
 	RuleSets                      []RuleSet      `json:"rulesets,omitempty"`
+
+	// Version identifies the configuration when it is fetched from a ruleset source, and is reported in the
+	// analytics tags of the module.
+	Version string `json:"version,omitempty"`
 }
 
 type SetDefinitions struct {
@@ -32,8 +37,16 @@ type RuleSet struct {
 	Name        string       `json:"name,omitempty"`
 	Version     string       `json:"version,omitempty"`
 	ModelGroups []ModelGroup `json:"modelgroups,omitempty"`
+
+	// Scope tells whether the rules are evaluated once on the request or once per imp of the request.
+	Scope string `json:"scope,omitempty"`
 }
 
+const (
+	ScopeRequest = "request"
+	ScopeImp     = "imp"
+)
+
 type ModelGroup struct {
 	Weight       int
 	AnalyticsKey string
@@ -67,6 +80,45 @@ type ResultFuncParams struct {
 	IfSyncedId     bool     `json:"ifsyncedid,omitempty"`
 }
 
+// BlockedAttributesParams is a struct that holds parameters for result functions adjusting the bcat and badv of a
+// bidder request.
+type BlockedAttributesParams struct {
+	Categories []string `json:"categories,omitempty"`
+	Domains    []string `json:"domains,omitempty"`
+}
+
+// TargetingParams is a struct that holds the targeting keys and values the setTargeting result function adds to a bid.
+type TargetingParams struct {
+	Targeting map[string]string `json:"targeting,omitempty"`
+}
+
+// FloorsParams is a struct that holds the floors the setFloors result function sets on the request.
+type FloorsParams struct {
+	Floors *openrtb_ext.PriceFloorRules `json:"floors,omitempty"`
+}
+
+// BidAdjustmentsParams is a struct that holds the bid adjustments the setBidAdjustments result function sets on
+// the request.
+type BidAdjustmentsParams struct {
+	BidAdjustments *openrtb_ext.ExtRequestPrebidBidAdjustments `json:"bidadjustments,omitempty"`
+}
+
+// PriceGranularityParams is a struct that holds the price granularity the setPriceGranularity result function sets
+// on the request, either as a legacy name such as "dense" or as custom ranges.
+type PriceGranularityParams struct {
+	PriceGranularity *openrtb_ext.PriceGranularity `json:"pricegranularity,omitempty"`
+}
+
+// TargetingOptionsParams is a struct that holds the targeting options the setTargetingOptions result function
+// forces on the request. Options left unset keep the value of the request.
+type TargetingOptionsParams struct {
+	IncludeWinners     *bool `json:"includewinners,omitempty"`
+	IncludeBidderKeys  *bool `json:"includebidderkeys,omitempty"`
+	IncludeFormat      *bool `json:"includeformat,omitempty"`
+	PreferDeals        *bool `json:"preferdeals,omitempty"`
+	AlwaysIncludeDeals *bool `json:"alwaysincludedeals,omitempty"`
+}
+
 func CreateSchemaValidator(jsonSchemaFile string) (*gojsonschema.Schema, error) {
 	jsonSchemaFilePath, err := filepath.Abs(jsonSchemaFile)
 	if err != nil {
@@ -94,6 +146,10 @@ func validateConfig(rawCfg json.RawMessage, schemaValidator *gojsonschema.Schema
 }
 
 func validateRuleSet(r *RuleSet) error {
+	if r.Scope == ScopeImp && r.Stage != hooks.StageProcessedAuctionRequest {
+		return fmt.Errorf("imp scope is only supported at the %s stage", hooks.StageProcessedAuctionRequest)
+	}
+
 	for i := 0; i < len(r.ModelGroups); i++ {
 		// Modelgroup weight defaults to 100
 		if r.ModelGroups[i].Weight == 0 {
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/prebid/prebid-server/v3/hooks"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -268,7 +269,7 @@ func TestValidateConfig(t *testing.T) {
 				]
 			}
 			`),
-			expectedError: "[rulesets.0.modelgroups.0.schema.0.function: rulesets.0.modelgroups.0.schema.0.function must be one of the following: \"channel\", \"dataCenter\", \"dataCenterIn\", \"deviceCountry\", \"deviceCountryIn\", \"eidAvailable\", \"eidIn\", \"fpdAvailable\", \"gppSidAvailable\", \"gppSidIn\", \"percent\", \"tcfInScope\", \"userFpdAvailable\"] ",
+			expectedError: "[rulesets.0.modelgroups.0.schema.0.function: rulesets.0.modelgroups.0.schema.0.function must be one of the following: \"adomain\", \"adomainIn\", \"adUnitCode\", \"bidder\", \"bidFloorBucket\", \"bidPriceBucket\", \"channel\", \"dataCenter\", \"dataCenterIn\", \"dealId\", \"dealIdAvailable\", \"dealsAvailable\", \"deviceCountry\", \"deviceCountryIn\", \"eidAvailable\", \"eidIn\", \"fpdAvailable\", \"gpid\", \"gppSidAvailable\", \"gppSidIn\", \"mediaType\", \"percent\", \"size\", \"sizeIn\", \"tcfInScope\", \"userFpdAvailable\"] ",
 		},
 		{
 			name: "invalid-empty-conditions",
@@ -346,7 +347,7 @@ func TestValidateConfig(t *testing.T) {
 				]
 			}
 			`),
-			expectedError: "[rulesets.0.modelgroups.0.rules.0.results.0.function: rulesets.0.modelgroups.0.rules.0.results.0.function must be one of the following: \"excludeBidders\", \"includeBidders\", \"logATag\"] ",
+			expectedError: "[rulesets.0.modelgroups.0.rules.0.results.0.function: rulesets.0.modelgroups.0.rules.0.results.0.function must be one of the following: \"addBadv\", \"addBcat\", \"excludeBidders\", \"includeBidders\", \"logATag\", \"rejectBid\", \"removeBadv\", \"removeBcat\", \"setBidAdjustments\", \"setFloors\", \"setPriceGranularity\", \"setTargeting\", \"setTargetingOptions\"] ",
 		},
 		{
 			name: "invalid-set-definitions-invalid-property",
@@ -426,6 +427,22 @@ func TestValidateRuleSet(t *testing.T) {
 		ruleSet     *RuleSet
 		expectedErr error
 	}{
+		{
+			desc: "imp-scope-at-processed-auction-request-stage",
+			ruleSet: &RuleSet{
+				Stage: hooks.StageProcessedAuctionRequest,
+				Scope: ScopeImp,
+			},
+			expectedErr: nil,
+		},
+		{
+			desc: "imp-scope-at-other-stage",
+			ruleSet: &RuleSet{
+				Stage: hooks.StageBidderRequest,
+				Scope: ScopeImp,
+			},
+			expectedErr: errors.New("imp scope is only supported at the processed_auction_request stage"),
+		},
 		{
 			desc: "no-schema-functions-and-no-rules",
 			ruleSet: &RuleSet{
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/buger/jsonparser"
+	prebidConfig "github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+const (
+	SourceTypeFile     = "file"
+	SourceTypeHTTP     = "http"
+	SourceTypeDatabase = "database"
+)
+
+const (
+	defaultSourceTimeoutMS          = 2000
+	defaultSourceRefreshRateSeconds = 300
+	defaultSourceMaxVersions        = 5
+	defaultSourceMaxRefreshes       = 100
+	defaultSourceIdleTimeoutSeconds = 86400
+	defaultSourceMaxAccounts        = 10000
+)
+
+// RulesetSource configures where the module fetches the rules engine configuration of the accounts from, in
+// place of the configuration of the account. It is set by the "rulesetsource" object of the module host config.
+type RulesetSource struct {
+	Type string `json:"type"`
+	// Path is the directory holding the configuration of each account as {account id}.json.
+	Path string `json:"path,omitempty"`
+	// URL is requested with the account query parameter set to the account id.
+	URL      string                `json:"url,omitempty"`
+	Database RulesetSourceDatabase `json:"database,omitempty"`
+
+	TimeoutMS          int `json:"timeoutms,omitempty"`
+	RefreshRateSeconds int `json:"refreshrateseconds,omitempty"`
+	// MaxVersions is the number of versions of each account configuration kept in memory to roll back to.
+	MaxVersions int `json:"maxversions,omitempty"`
+	// MaxRefreshes is the number of accounts refetched each refresh, the ones fetched the longest ago first.
+	MaxRefreshes int `json:"maxrefreshes,omitempty"`
+	// IdleTimeoutSeconds is how long an account the module has not run for is kept before being dropped.
+	IdleTimeoutSeconds int `json:"idletimeoutseconds,omitempty"`
+	// MaxAccounts is the number of accounts kept in memory. The account the module has run for the longest ago
+	// is dropped to make room for a new one.
+	MaxAccounts int `json:"maxaccounts,omitempty"`
+}
+
+// RulesetSourceDatabase configures a database ruleset source. The query selects the configuration of an account
+// with the $ACCOUNT parameter, for example:
+//
+//	SELECT config FROM rules_engine_configs WHERE account_id = $ACCOUNT
+type RulesetSourceDatabase struct {
+	Driver      string `json:"driver,omitempty"`
+	Database    string `json:"dbname,omitempty"`
+	Host        string `json:"host,omitempty"`
+	Port        int    `json:"port,omitempty"`
+	Username    string `json:"user,omitempty"`
+	Password    string `json:"password,omitempty"`
+	QueryString string `json:"query_string,omitempty"`
+	Query       string `json:"query,omitempty"`
+}
+
+// ConnectionInfo returns the database connection of the source.
+func (db RulesetSourceDatabase) ConnectionInfo() prebidConfig.DatabaseConnection {
+	return prebidConfig.DatabaseConnection{
+		Driver:      db.Driver,
+		Database:    db.Database,
+		Host:        db.Host,
+		Port:        db.Port,
+		Username:    db.Username,
+		Password:    db.Password,
+		QueryString: db.QueryString,
+	}
+}
+
+// NewRulesetSource reads the ruleset source of the module host config, returning nil when none is configured.
+func NewRulesetSource(hostCfg []byte) (*RulesetSource, error) {
+	sourceCfg, _, _, err := jsonparser.Get(hostCfg, "rulesetsource")
+	if err == jsonparser.KeyPathNotFoundError {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	source := &RulesetSource{
+		TimeoutMS:          defaultSourceTimeoutMS,
+		RefreshRateSeconds: defaultSourceRefreshRateSeconds,
+		MaxVersions:        defaultSourceMaxVersions,
+		MaxRefreshes:       defaultSourceMaxRefreshes,
+		IdleTimeoutSeconds: defaultSourceIdleTimeoutSeconds,
+		MaxAccounts:        defaultSourceMaxAccounts,
+	}
+	if err := jsonutil.UnmarshalValid(sourceCfg, source); err != nil {
+		return nil, fmt.Errorf("failed to parse rulesetsource: %w", err)
+	}
+	if err := source.validate(); err != nil {
+		return nil, err
+	}
+	return source, nil
+}
+
+func (s *RulesetSource) validate() error {
+	switch s.Type {
+	case SourceTypeFile:
+		if s.Path == "" {
+			return errors.New("rulesetsource.path is required for a file source")
+		}
+	case SourceTypeHTTP:
+		if s.URL == "" {
+			return errors.New("rulesetsource.url is required for an http source")
+		}
+	case SourceTypeDatabase:
+		if s.Database.Database == "" || s.Database.Query == "" {
+			return errors.New("rulesetsource.database.dbname and rulesetsource.database.query are required for a database source")
+		}
+	default:
+		return fmt.Errorf("rulesetsource.type %q is not one of %s, %s or %s", s.Type, SourceTypeFile, SourceTypeHTTP, SourceTypeDatabase)
+	}
+
+	if s.TimeoutMS <= 0 {
+		return fmt.Errorf("rulesetsource.timeoutms must be > 0. Got %d", s.TimeoutMS)
+	}
+	if s.RefreshRateSeconds <= 0 {
+		return fmt.Errorf("rulesetsource.refreshrateseconds must be > 0. Got %d", s.RefreshRateSeconds)
+	}
+	if s.MaxVersions <= 0 {
+		return fmt.Errorf("rulesetsource.maxversions must be > 0. Got %d", s.MaxVersions)
+	}
+	if s.MaxRefreshes <= 0 {
+		return fmt.Errorf("rulesetsource.maxrefreshes must be > 0. Got %d", s.MaxRefreshes)
+	}
+	if s.IdleTimeoutSeconds <= 0 {
+		return fmt.Errorf("rulesetsource.idletimeoutseconds must be > 0. Got %d", s.IdleTimeoutSeconds)
+	}
+	if s.MaxAccounts <= 0 {
+		return fmt.Errorf("rulesetsource.maxaccounts must be > 0. Got %d", s.MaxAccounts)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"testing"
+
+	prebidConfig "github.com/prebid/prebid-server/v3/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRulesetSource(t *testing.T) {
+	testCases := []struct {
+		name           string
+		inHostCfg      string
+		expectedSource *RulesetSource
+		expectedErr    string
+	}{
+		{
+			name:      "no-source",
+			inHostCfg: `{"refreshrateseconds": 10}`,
+		},
+		{
+			name:      "file-source-with-defaults",
+			inHostCfg: `{"rulesetsource": {"type": "file", "path": "/etc/rulesets"}}`,
+			expectedSource: &RulesetSource{
+				Type:               SourceTypeFile,
+				Path:               "/etc/rulesets",
+				TimeoutMS:          2000,
+				RefreshRateSeconds: 300,
+				MaxVersions:        5,
+				MaxRefreshes:       100,
+				IdleTimeoutSeconds: 86400,
+				MaxAccounts:        10000,
+			},
+		},
+		{
+			name:      "http-source",
+			inHostCfg: `{"rulesetsource": {"type": "http", "url": "https://rules.example.com", "timeoutms": 500, "refreshrateseconds": 60, "maxversions": 10, "maxrefreshes": 20, "idletimeoutseconds": 3600, "maxaccounts": 50}}`,
+			expectedSource: &RulesetSource{
+				Type:               SourceTypeHTTP,
+				URL:                "https://rules.example.com",
+				TimeoutMS:          500,
+				RefreshRateSeconds: 60,
+				MaxVersions:        10,
+				MaxRefreshes:       20,
+				IdleTimeoutSeconds: 3600,
+				MaxAccounts:        50,
+			},
+		},
+		{
+			name:        "unknown-type",
+			inHostCfg:   `{"rulesetsource": {"type": "ftp"}}`,
+			expectedErr: `rulesetsource.type "ftp" is not one of file, http or database`,
+		},
+		{
+			name:        "file-source-without-path",
+			inHostCfg:   `{"rulesetsource": {"type": "file"}}`,
+			expectedErr: "rulesetsource.path is required for a file source",
+		},
+		{
+			name:        "http-source-without-url",
+			inHostCfg:   `{"rulesetsource": {"type": "http"}}`,
+			expectedErr: "rulesetsource.url is required for an http source",
+		},
+		{
+			name:        "database-source-without-query",
+			inHostCfg:   `{"rulesetsource": {"type": "database", "database": {"dbname": "rules"}}}`,
+			expectedErr: "rulesetsource.database.dbname and rulesetsource.database.query are required for a database source",
+		},
+		{
+			name:        "invalid-max-versions",
+			inHostCfg:   `{"rulesetsource": {"type": "file", "path": "/etc/rulesets", "maxversions": -1}}`,
+			expectedErr: "rulesetsource.maxversions must be > 0. Got -1",
+		},
+		{
+			name:        "invalid-max-refreshes",
+			inHostCfg:   `{"rulesetsource": {"type": "file", "path": "/etc/rulesets", "maxrefreshes": -1}}`,
+			expectedErr: "rulesetsource.maxrefreshes must be > 0. Got -1",
+		},
+		{
+			name:        "invalid-idle-timeout",
+			inHostCfg:   `{"rulesetsource": {"type": "file", "path": "/etc/rulesets", "idletimeoutseconds": -1}}`,
+			expectedErr: "rulesetsource.idletimeoutseconds must be > 0. Got -1",
+		},
+		{
+			name:        "invalid-max-accounts",
+			inHostCfg:   `{"rulesetsource": {"type": "file", "path": "/etc/rulesets", "maxaccounts": -1}}`,
+			expectedErr: "rulesetsource.maxaccounts must be > 0. Got -1",
+		},
+		{
+			name:        "malformed-source",
+			inHostCfg:   `{"rulesetsource": {"type": 1}}`,
+			expectedErr: "failed to parse rulesetsource",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			source, err := NewRulesetSource([]byte(tc.inHostCfg))
+			if tc.expectedErr != "" {
+				assert.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedSource, source)
+		})
+	}
+}
+
+func TestRulesetSourceDatabaseConnectionInfo(t *testing.T) {
+	db := RulesetSourceDatabase{Driver: "postgres", Database: "rules", Host: "localhost", Port: 5432, Username: "user", Password: "pass", Query: "SELECT 1"}
+
+	assert.Equal(t, prebidConfig.DatabaseConnection{
+		Driver:   "postgres",
+		Database: "rules",
+		Host:     "localhost",
+		Port:     5432,
+		Username: "user",
+		Password: "pass",
+	}, db.ConnectionInfo())
+}
@@ -0,0 +1,192 @@
+package rulesengine
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	hs "github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/rules"
+	"github.com/prebid/prebid-server/v3/util/randomutil"
+)
+
+type BidRuleSet = cacheRuleSet[rules.Bid, BidHookResult]
+
+// BidHookResult holds the decisions of the rulesets evaluated on a single bid.
+type BidHookResult struct {
+	Rejected   bool
+	SeatNonBid int
+	Targeting  map[string]string
+}
+
+func handleRawBidderResponseHook(ruleSets []BidRuleSet, payload hs.RawBidderResponsePayload) (hs.HookResult[hs.RawBidderResponsePayload], error) {
+	result := hs.HookResult[hs.RawBidderResponsePayload]{}
+	if payload.BidderResponse == nil {
+		return result, nil
+	}
+
+	trees := selectTrees(ruleSets, &result.Errors)
+	if len(trees) == 0 {
+		return result, nil
+	}
+
+	allowedBids := make([]*adapters.TypedBid, 0, len(payload.BidderResponse.Bids))
+	seatNonBids := make(map[string][]openrtb_ext.NonBid)
+	for _, typedBid := range payload.BidderResponse.Bids {
+		if typedBid == nil || typedBid.Bid == nil {
+			continue
+		}
+
+		bidResult := runBidTrees(trees, rules.Bid{Bidder: payload.Bidder, Bid: typedBid.Bid, BidType: typedBid.BidType}, &result.Errors)
+		if !bidResult.Rejected {
+			allowedBids = append(allowedBids, typedBid)
+			continue
+		}
+
+		seat := payload.Bidder
+		if typedBid.Seat != "" {
+			seat = typedBid.Seat.String()
+		}
+		seatNonBids[seat] = append(seatNonBids[seat], newNonBid(typedBid.Bid, bidResult.SeatNonBid))
+	}
+
+	if len(seatNonBids) > 0 {
+		result.ChangeSet.RawBidderResponse().Bids().UpdateBids(allowedBids)
+		result.SeatNonBid = toSeatNonBid(seatNonBids)
+	}
+	return result, nil
+}
+
+func handleAllProcessedBidResponsesHook(ruleSets []BidRuleSet, payload hs.AllProcessedBidResponsesPayload) (hs.HookResult[hs.AllProcessedBidResponsesPayload], error) {
+	result := hs.HookResult[hs.AllProcessedBidResponsesPayload]{}
+
+	trees := selectTrees(ruleSets, &result.Errors)
+	if len(trees) == 0 {
+		return result, nil
+	}
+
+	rejectedBids := make(map[*entities.PbsOrtbBid]struct{})
+	bidTargeting := make(map[*entities.PbsOrtbBid]map[string]string)
+	seatNonBids := make(map[string][]openrtb_ext.NonBid)
+	for bidder, seatBid := range payload.Responses {
+		if seatBid == nil {
+			continue
+		}
+		seat := seatBid.Seat
+		if seat == "" {
+			seat = bidder.String()
+		}
+
+		for _, pbsBid := range seatBid.Bids {
+			if pbsBid == nil || pbsBid.Bid == nil {
+				continue
+			}
+
+			bidResult := runBidTrees(trees, rules.Bid{Bidder: bidder.String(), Bid: pbsBid.Bid, BidType: pbsBid.BidType}, &result.Errors)
+			if bidResult.Rejected {
+				rejectedBids[pbsBid] = struct{}{}
+				nonBid := newNonBid(pbsBid.Bid, bidResult.SeatNonBid)
+				nonBid.Ext.Prebid.Bid.OriginalBidCPM = pbsBid.OriginalBidCPM
+				nonBid.Ext.Prebid.Bid.OriginalBidCur = pbsBid.OriginalBidCur
+				seatNonBids[seat] = append(seatNonBids[seat], nonBid)
+				continue
+			}
+			if len(bidResult.Targeting) > 0 {
+				bidTargeting[pbsBid] = bidResult.Targeting
+			}
+		}
+	}
+
+	if len(rejectedBids) > 0 {
+		result.ChangeSet.AddMutation(func(p hs.AllProcessedBidResponsesPayload) (hs.AllProcessedBidResponsesPayload, error) {
+			for _, seatBid := range p.Responses {
+				if seatBid == nil {
+					continue
+				}
+				bids := make([]*entities.PbsOrtbBid, 0, len(seatBid.Bids))
+				for _, pbsBid := range seatBid.Bids {
+					if _, rejected := rejectedBids[pbsBid]; !rejected {
+						bids = append(bids, pbsBid)
+					}
+				}
+				seatBid.Bids = bids
+			}
+			return p, nil
+		}, hs.MutationDelete, "bids")
+		result.SeatNonBid = toSeatNonBid(seatNonBids)
+	}
+
+	if len(bidTargeting) > 0 {
+		result.ChangeSet.AddMutation(func(p hs.AllProcessedBidResponsesPayload) (hs.AllProcessedBidResponsesPayload, error) {
+			for pbsBid, targeting := range bidTargeting {
+				if pbsBid.BidTargets == nil {
+					pbsBid.BidTargets = make(map[string]string, len(targeting))
+				}
+				maps.Copy(pbsBid.BidTargets, targeting)
+			}
+			return p, nil
+		}, hs.MutationUpdate, "bids", "targeting")
+	}
+
+	return result, nil
+}
+
+// selectTrees selects the model group of each ruleset once per hook invocation, so every bid or imp of the
+// invocation is evaluated by the same model groups.
+func selectTrees[T1 any, T2 any](ruleSets []cacheRuleSet[T1, T2], errs *[]string) []rules.Tree[T1, T2] {
+	trees := make([]rules.Tree[T1, T2], 0, len(ruleSets))
+	for _, ruleSet := range ruleSets {
+		selectedGroup, err := selectModelGroup(ruleSet.modelGroups, randomutil.RandomNumberGenerator{})
+		if err != nil {
+			*errs = append(*errs, fmt.Sprintf("failed to select model group: %s", err))
+			continue
+		}
+		trees = append(trees, selectedGroup.tree)
+	}
+	return trees
+}
+
+// runBidTrees evaluates the trees on the bid, stopping once a tree rejects it.
+func runBidTrees(trees []rules.Tree[rules.Bid, BidHookResult], bid rules.Bid, errs *[]string) BidHookResult {
+	var result BidHookResult
+	for _, tree := range trees {
+		if err := tree.Run(&bid, &result); err != nil {
+			*errs = append(*errs, err.Error())
+		}
+		if result.Rejected {
+			break
+		}
+	}
+	return result
+}
+
+func newNonBid(bid *openrtb2.Bid, statusCode int) openrtb_ext.NonBid {
+	return openrtb_ext.NonBid{
+		ImpId:      bid.ImpID,
+		StatusCode: statusCode,
+		Ext: &openrtb_ext.NonBidExt{
+			Prebid: openrtb_ext.ExtResponseNonBidPrebid{Bid: openrtb_ext.NonBidObject{
+				Price:   bid.Price,
+				ADomain: bid.ADomain,
+				CatTax:  bid.CatTax,
+				Cat:     bid.Cat,
+				DealID:  bid.DealID,
+				W:       bid.W,
+				H:       bid.H,
+				Dur:     bid.Dur,
+				MType:   bid.MType,
+			}},
+		},
+	}
+}
+
+func toSeatNonBid(seatNonBids map[string][]openrtb_ext.NonBid) []openrtb_ext.SeatNonBid {
+	seatNonBid := make([]openrtb_ext.SeatNonBid, 0, len(seatNonBids))
+	for seat, nonBids := range seatNonBids {
+		seatNonBid = append(seatNonBid, openrtb_ext.SeatNonBid{Seat: seat, NonBid: nonBids})
+	}
+	return seatNonBid
+}
@@ -0,0 +1,150 @@
+package rulesengine
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	hs "github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/rules"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testBidModelGroup = `{
+	"schema": [{"function": "mediaType"}, {"function": "dealIdAvailable"}],
+	"rules": [
+		{
+			"conditions": ["video", "false"],
+			"results": [{"function": "rejectBid", "args": {"seatnonbid": 301}}]
+		},
+		{
+			"conditions": ["banner", "*"],
+			"results": [{"function": "setTargeting", "args": {"targeting": {"hb_rule": "banner"}}}]
+		}
+	]
+}`
+
+func TestHandleRawBidderResponseHook(t *testing.T) {
+	ruleSet := newTestRuleSet(t, `{
+		"schema": [{"function": "mediaType"}, {"function": "dealIdAvailable"}],
+		"rules": [{
+			"conditions": ["video", "false"],
+			"results": [{"function": "rejectBid", "args": {"seatnonbid": 301}}]
+		}]
+	}`, rules.NewBidSchemaFunction, NewRawBidderResponseResultFunction)
+
+	videoBid := &adapters.TypedBid{Bid: &openrtb2.Bid{ID: "video", ImpID: "imp-1", Price: 1.5, ADomain: []string{"a.com"}}, BidType: openrtb_ext.BidTypeVideo}
+	altSeatBid := &adapters.TypedBid{Bid: &openrtb2.Bid{ID: "video-alt", ImpID: "imp-2"}, BidType: openrtb_ext.BidTypeVideo, Seat: "altseat"}
+	dealBid := &adapters.TypedBid{Bid: &openrtb2.Bid{ID: "deal", ImpID: "imp-1", DealID: "deal-1"}, BidType: openrtb_ext.BidTypeVideo}
+	bannerBid := &adapters.TypedBid{Bid: &openrtb2.Bid{ID: "banner", ImpID: "imp-1"}, BidType: openrtb_ext.BidTypeBanner}
+	payload := hs.RawBidderResponsePayload{
+		Bidder:         "appnexus",
+		BidderResponse: &adapters.BidderResponse{Bids: []*adapters.TypedBid{videoBid, altSeatBid, dealBid, bannerBid}},
+	}
+
+	result, err := handleRawBidderResponseHook([]BidRuleSet{ruleSet}, payload)
+	require.NoError(t, err)
+	assert.Empty(t, result.Errors)
+
+	for _, mutation := range result.ChangeSet.Mutations() {
+		payload, err = mutation.Apply(payload)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []*adapters.TypedBid{dealBid, bannerBid}, payload.BidderResponse.Bids)
+
+	expectedSeatNonBid := []openrtb_ext.SeatNonBid{
+		{Seat: "altseat", NonBid: []openrtb_ext.NonBid{newNonBid(altSeatBid.Bid, 301)}},
+		{Seat: "appnexus", NonBid: []openrtb_ext.NonBid{newNonBid(videoBid.Bid, 301)}},
+	}
+	assert.ElementsMatch(t, expectedSeatNonBid, result.SeatNonBid)
+}
+
+func TestHandleRawBidderResponseHookNothingRejected(t *testing.T) {
+	ruleSet := newTestRuleSet(t, testBidModelGroup, rules.NewBidSchemaFunction, NewAllProcessedBidResponsesResultFunction)
+	payload := hs.RawBidderResponsePayload{
+		Bidder:         "appnexus",
+		BidderResponse: &adapters.BidderResponse{Bids: []*adapters.TypedBid{{Bid: &openrtb2.Bid{ID: "banner"}, BidType: openrtb_ext.BidTypeBanner}}},
+	}
+
+	result, err := handleRawBidderResponseHook([]BidRuleSet{ruleSet}, payload)
+
+	assert.NoError(t, err)
+	assert.Empty(t, result.ChangeSet.Mutations())
+	assert.Empty(t, result.SeatNonBid)
+}
+
+func TestHandleAllProcessedBidResponsesHook(t *testing.T) {
+	ruleSet := newTestRuleSet(t, testBidModelGroup, rules.NewBidSchemaFunction, NewAllProcessedBidResponsesResultFunction)
+
+	videoBid := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "video", ImpID: "imp-1", Price: 2}, BidType: openrtb_ext.BidTypeVideo, OriginalBidCPM: 2.2, OriginalBidCur: "EUR"}
+	dealBid := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "deal", ImpID: "imp-1", DealID: "deal-1"}, BidType: openrtb_ext.BidTypeVideo}
+	bannerBid := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "banner", ImpID: "imp-2"}, BidType: openrtb_ext.BidTypeBanner}
+	payload := hs.AllProcessedBidResponsesPayload{
+		Responses: map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+			"appnexus": {Seat: "appnexus", Bids: []*entities.PbsOrtbBid{videoBid, dealBid}},
+			"rubicon":  {Bids: []*entities.PbsOrtbBid{bannerBid}},
+		},
+	}
+
+	result, err := handleAllProcessedBidResponsesHook([]BidRuleSet{ruleSet}, payload)
+	require.NoError(t, err)
+	assert.Empty(t, result.Errors)
+
+	for _, mutation := range result.ChangeSet.Mutations() {
+		payload, err = mutation.Apply(payload)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []*entities.PbsOrtbBid{dealBid}, payload.Responses["appnexus"].Bids)
+	assert.Equal(t, []*entities.PbsOrtbBid{bannerBid}, payload.Responses["rubicon"].Bids)
+	assert.Equal(t, map[string]string{"hb_rule": "banner"}, bannerBid.BidTargets)
+	assert.Nil(t, dealBid.BidTargets)
+
+	require.Len(t, result.SeatNonBid, 1)
+	assert.Equal(t, "appnexus", result.SeatNonBid[0].Seat)
+	require.Len(t, result.SeatNonBid[0].NonBid, 1)
+	nonBid := result.SeatNonBid[0].NonBid[0]
+	assert.Equal(t, "imp-1", nonBid.ImpId)
+	assert.Equal(t, 301, nonBid.StatusCode)
+	assert.Equal(t, 2.2, nonBid.Ext.Prebid.Bid.OriginalBidCPM)
+	assert.Equal(t, "EUR", nonBid.Ext.Prebid.Bid.OriginalBidCur)
+}
+
+func TestHandleAllProcessedBidResponsesHookWithoutRuleSets(t *testing.T) {
+	result, err := handleAllProcessedBidResponsesHook(nil, hs.AllProcessedBidResponsesPayload{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, hs.HookResult[hs.AllProcessedBidResponsesPayload]{}, result)
+}
+
+func TestSelectTrees(t *testing.T) {
+	ruleSet := newTestRuleSet(t, testBidModelGroup, rules.NewBidSchemaFunction, NewAllProcessedBidResponsesResultFunction)
+	var errs []string
+
+	trees := selectTrees([]BidRuleSet{ruleSet, {name: "empty"}}, &errs)
+
+	assert.Len(t, trees, 1)
+	assert.Equal(t, []string{"failed to select model group: no model groups available"}, errs)
+}
+
+func TestRunBidTreesStopsOnRejection(t *testing.T) {
+	rejecting := newTestRuleSet(t, `{
+		"schema": [{"function": "bidder"}],
+		"rules": [{"conditions": ["appnexus"], "results": [{"function": "rejectBid"}]}]
+	}`, rules.NewBidSchemaFunction, NewAllProcessedBidResponsesResultFunction)
+	targeting := newTestRuleSet(t, `{
+		"schema": [{"function": "bidder"}],
+		"rules": [{"conditions": ["*"], "results": [{"function": "setTargeting", "args": {"targeting": {"hb_rule": "any"}}}]}]
+	}`, rules.NewBidSchemaFunction, NewAllProcessedBidResponsesResultFunction)
+	trees := []rules.Tree[rules.Bid, BidHookResult]{rejecting.modelGroups[0].tree, targeting.modelGroups[0].tree}
+	var errs []string
+
+	rejected := runBidTrees(trees, rules.Bid{Bidder: "appnexus", Bid: &openrtb2.Bid{}}, &errs)
+	allowed := runBidTrees(trees, rules.Bid{Bidder: "rubicon", Bid: &openrtb2.Bid{}}, &errs)
+
+	assert.Empty(t, errs)
+	assert.Equal(t, BidHookResult{Rejected: true, SeatNonBid: defaultRejectBidSeatNonBid}, rejected)
+	assert.Equal(t, BidHookResult{Targeting: map[string]string{"hb_rule": "any"}}, allowed)
+}
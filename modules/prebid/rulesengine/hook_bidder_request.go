@@ -0,0 +1,56 @@
+package rulesengine
+
+import (
+	"fmt"
+	"slices"
+
+	hs "github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/rules"
+	"github.com/prebid/prebid-server/v3/util/randomutil"
+)
+
+type BidderRequestRuleSet = cacheRuleSet[rules.BidderRequest, BidderRequestHookResult]
+
+// BidderRequestHookResult holds the bcat and badv the bidder request is sent with, as adjusted by the result
+// functions of the rulesets.
+type BidderRequestHookResult struct {
+	HookResult hs.HookResult[hs.BidderRequestPayload]
+	BCat       []string
+	BAdv       []string
+}
+
+func handleBidderRequestHook(ruleSets []BidderRequestRuleSet, payload hs.BidderRequestPayload) (hs.HookResult[hs.BidderRequestPayload], error) {
+	if payload.Request == nil || payload.Request.BidRequest == nil {
+		return hs.HookResult[hs.BidderRequestPayload]{}, nil
+	}
+
+	result := BidderRequestHookResult{
+		HookResult: hs.HookResult[hs.BidderRequestPayload]{
+			ChangeSet: hs.ChangeSet[hs.BidderRequestPayload]{},
+		},
+		BCat: payload.Request.BCat,
+		BAdv: payload.Request.BAdv,
+	}
+	bidderRequest := rules.BidderRequest{Request: payload.Request, Bidder: payload.Bidder}
+
+	for _, ruleSet := range ruleSets {
+		selectedGroup, err := selectModelGroup(ruleSet.modelGroups, randomutil.RandomNumberGenerator{})
+		if err != nil {
+			result.HookResult.Errors = append(result.HookResult.Errors, fmt.Sprintf("failed to select model group: %s", err))
+			continue
+		}
+
+		if err = selectedGroup.tree.Run(&bidderRequest, &result); err != nil {
+			result.HookResult.Errors = append(result.HookResult.Errors, err.Error())
+		}
+	}
+
+	if !slices.Equal(result.BCat, payload.Request.BCat) {
+		result.HookResult.ChangeSet.BidderRequest().BCat().Update(result.BCat)
+	}
+	if !slices.Equal(result.BAdv, payload.Request.BAdv) {
+		result.HookResult.ChangeSet.BidderRequest().BAdv().Update(result.BAdv)
+	}
+
+	return result.HookResult, nil
+}
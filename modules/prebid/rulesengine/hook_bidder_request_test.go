@@ -0,0 +1,100 @@
+package rulesengine
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	hs "github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/rules"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRuleSet[T1 any, T2 any](t *testing.T, modelGroup string, schemaFuncFactory rules.SchemaFuncFactory[T1], resultFuncFactory rules.ResultFuncFactory[T1, T2]) cacheRuleSet[T1, T2] {
+	var cfg config.ModelGroup
+	require.NoError(t, json.Unmarshal([]byte(modelGroup), &cfg))
+
+	ruleSet, err := newCacheRuleSet(&config.RuleSet{Name: "test", ModelGroups: []config.ModelGroup{cfg}}, schemaFuncFactory, resultFuncFactory)
+	require.NoError(t, err)
+	return ruleSet
+}
+
+func TestHandleBidderRequestHook(t *testing.T) {
+	ruleSet := newTestRuleSet(t, `{
+		"schema": [{"function": "bidder"}],
+		"rules": [{
+			"conditions": ["appnexus"],
+			"results": [
+				{"function": "addBcat", "args": {"categories": ["IAB7-39", "IAB1"]}},
+				{"function": "removeBadv", "args": {"domains": ["a.com"]}}
+			]
+		}]
+	}`, rules.NewBidderRequestSchemaFunction, NewBidderRequestResultFunction)
+
+	testCases := []struct {
+		name             string
+		bidder           string
+		expectedBCat     []string
+		expectedBAdv     []string
+		expectedMutation int
+	}{
+		{
+			name:             "rule-fired",
+			bidder:           "appnexus",
+			expectedBCat:     []string{"IAB1", "IAB7-39"},
+			expectedBAdv:     []string{"b.com"},
+			expectedMutation: 2,
+		},
+		{
+			name:         "no-rule-fired",
+			bidder:       "rubicon",
+			expectedBCat: []string{"IAB1"},
+			expectedBAdv: []string{"a.com", "b.com"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			payload := hs.BidderRequestPayload{
+				Bidder: tc.bidder,
+				Request: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+					BCat: []string{"IAB1"},
+					BAdv: []string{"a.com", "b.com"},
+				}},
+			}
+
+			result, err := handleBidderRequestHook([]BidderRequestRuleSet{ruleSet}, payload)
+			require.NoError(t, err)
+			assert.Empty(t, result.Errors)
+
+			mutations := result.ChangeSet.Mutations()
+			assert.Len(t, mutations, tc.expectedMutation)
+			for _, mutation := range mutations {
+				payload, err = mutation.Apply(payload)
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedBCat, payload.Request.BCat)
+			assert.Equal(t, tc.expectedBAdv, payload.Request.BAdv)
+		})
+	}
+}
+
+func TestHandleBidderRequestHookWithoutRequest(t *testing.T) {
+	result, err := handleBidderRequestHook(nil, hs.BidderRequestPayload{Bidder: "appnexus"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, hs.HookResult[hs.BidderRequestPayload]{}, result)
+}
+
+func TestHandleBidderRequestHookModelGroupError(t *testing.T) {
+	payload := hs.BidderRequestPayload{Request: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{}}}
+
+	result, err := handleBidderRequestHook([]BidderRequestRuleSet{{name: "empty"}}, payload)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"failed to select model group: no model groups available"}, result.Errors)
+	assert.Empty(t, result.ChangeSet.Mutations())
+}
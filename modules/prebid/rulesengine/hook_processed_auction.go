@@ -47,9 +47,9 @@ func handleProcessedAuctionHook(
 	return result.HookResult, nil
 }
 
-func selectModelGroup(modelGroups []ModelGroup, rg randomutil.RandomGenerator) (ModelGroup, error) {
+func selectModelGroup[T1 any, T2 any](modelGroups []cacheModelGroup[T1, T2], rg randomutil.RandomGenerator) (cacheModelGroup[T1, T2], error) {
 	if len(modelGroups) == 0 {
-		return ModelGroup{}, fmt.Errorf("no model groups available")
+		return cacheModelGroup[T1, T2]{}, fmt.Errorf("no model groups available")
 	}
 
 	if len(modelGroups) == 1 {
@@ -0,0 +1,102 @@
+package rulesengine
+
+import (
+	"encoding/json"
+
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	hs "github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/rules"
+)
+
+type ImpRuleSet = cacheRuleSet[rules.Imp, ImpHookResult]
+
+// rulesEngineActivity is the name of the analytics activity reporting the rules fired by the rulesets.
+const rulesEngineActivity = "pb-rules-engine"
+
+// ImpHookResult holds the bidders the imp rulesets allow on or exclude from a single imp, and the analytics of
+// the rules that fired for it. When bidders are allowed, every other bidder is removed from the imp.
+type ImpHookResult struct {
+	AllowedBidders   map[string]struct{}
+	ExcludedBidders  map[string]struct{}
+	AnalyticsResults []hookanalytics.Result
+}
+
+// handleProcessedAuctionImpHook evaluates the rulesets on every imp of the request, removing bidders from the
+// imps the rules fired for. The decisions are added to the result of the request scoped rulesets.
+func handleProcessedAuctionImpHook(
+	ruleSets []ImpRuleSet,
+	payload hs.ProcessedAuctionRequestPayload,
+	result hs.HookResult[hs.ProcessedAuctionRequestPayload]) hs.HookResult[hs.ProcessedAuctionRequestPayload] {
+
+	if payload.Request == nil || payload.Request.BidRequest == nil {
+		return result
+	}
+
+	trees := selectTrees(ruleSets, &result.Errors)
+	if len(trees) == 0 {
+		return result
+	}
+
+	impResults := make(map[string]ImpHookResult)
+	var analyticsResults []hookanalytics.Result
+	for _, impWrapper := range payload.Request.GetImp() {
+		imp := rules.Imp{Request: payload.Request, Imp: impWrapper}
+		var impResult ImpHookResult
+		for _, tree := range trees {
+			if err := tree.Run(&imp, &impResult); err != nil {
+				result.Errors = append(result.Errors, err.Error())
+			}
+		}
+
+		if len(impResult.AllowedBidders) > 0 || len(impResult.ExcludedBidders) > 0 {
+			impResults[impWrapper.ID] = impResult
+		}
+		analyticsResults = append(analyticsResults, impResult.AnalyticsResults...)
+	}
+
+	if len(impResults) > 0 {
+		result.ChangeSet.AddMutation(func(p hs.ProcessedAuctionRequestPayload) (hs.ProcessedAuctionRequestPayload, error) {
+			for _, impWrapper := range p.Request.GetImp() {
+				impResult, ok := impResults[impWrapper.ID]
+				if !ok {
+					continue
+				}
+				impExt, err := impWrapper.GetImpExt()
+				if err != nil {
+					return p, err
+				}
+				impPrebid := impExt.GetPrebid()
+				if impPrebid == nil {
+					continue
+				}
+				impPrebid.Bidder = filterImpBidders(impPrebid.Bidder, impResult)
+				impExt.SetPrebid(impPrebid)
+			}
+			return p, nil
+		}, hs.MutationDelete, "bidrequest", "imp", "ext", "prebid", "bidders")
+	}
+
+	if len(analyticsResults) > 0 {
+		result.AnalyticsTags.Activities = append(result.AnalyticsTags.Activities, hookanalytics.Activity{
+			Name:    rulesEngineActivity,
+			Status:  hookanalytics.ActivityStatusSuccess,
+			Results: analyticsResults,
+		})
+	}
+
+	return result
+}
+
+func filterImpBidders(bidders map[string]json.RawMessage, impResult ImpHookResult) map[string]json.RawMessage {
+	filtered := make(map[string]json.RawMessage, len(bidders))
+	for bidderName, bidderParams := range bidders {
+		if _, excluded := impResult.ExcludedBidders[bidderName]; excluded {
+			continue
+		}
+		if _, allowed := impResult.AllowedBidders[bidderName]; len(impResult.AllowedBidders) > 0 && !allowed {
+			continue
+		}
+		filtered[bidderName] = bidderParams
+	}
+	return filtered
+}
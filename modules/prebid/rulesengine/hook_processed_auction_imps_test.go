@@ -0,0 +1,133 @@
+package rulesengine
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	hs "github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/rules"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleProcessedAuctionImpHook(t *testing.T) {
+	ruleSet := newTestRuleSet(t, `{
+		"analyticsKey": "imp-rules",
+		"version": "1.0",
+		"schema": [{"function": "mediaType"}, {"function": "adUnitCode"}],
+		"rules": [
+			{
+				"conditions": ["video", "*"],
+				"results": [{"function": "excludeBidders", "args": {"bidders": ["bidderA"]}}]
+			},
+			{
+				"conditions": ["banner", "/1/home"],
+				"results": [{"function": "includeBidders", "args": {"bidders": ["bidderB"]}}]
+			}
+		]
+	}`, rules.NewImpSchemaFunction, NewImpResultFunction)
+
+	bidders := json.RawMessage(`{"prebid":{"bidder":{"bidderA":{},"bidderB":{},"bidderC":{}}}}`)
+	payload := hs.ProcessedAuctionRequestPayload{
+		Request: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{
+			{ID: "video-imp", Video: &openrtb2.Video{}, Ext: bidders},
+			{ID: "home-imp", TagID: "/1/home", Banner: &openrtb2.Banner{}, Ext: bidders},
+			{ID: "other-imp", TagID: "/1/other", Banner: &openrtb2.Banner{}, Ext: bidders},
+		}}},
+	}
+	previous := hs.HookResult[hs.ProcessedAuctionRequestPayload]{Errors: []string{"previous error"}}
+
+	result := handleProcessedAuctionImpHook([]ImpRuleSet{ruleSet}, payload, previous)
+	assert.Equal(t, []string{"previous error"}, result.Errors)
+
+	for _, mutation := range result.ChangeSet.Mutations() {
+		var err error
+		payload, err = mutation.Apply(payload)
+		require.NoError(t, err)
+	}
+	expectedBidders := map[string][]string{
+		"video-imp": {"bidderB", "bidderC"},
+		"home-imp":  {"bidderB"},
+		"other-imp": {"bidderA", "bidderB", "bidderC"},
+	}
+	for _, imp := range payload.Request.GetImp() {
+		impExt, err := imp.GetImpExt()
+		require.NoError(t, err)
+		var impBidders []string
+		for bidder := range impExt.GetPrebid().Bidder {
+			impBidders = append(impBidders, bidder)
+		}
+		assert.ElementsMatch(t, expectedBidders[imp.ID], impBidders, imp.ID)
+	}
+
+	require.Len(t, result.AnalyticsTags.Activities, 1)
+	activity := result.AnalyticsTags.Activities[0]
+	assert.Equal(t, rulesEngineActivity, activity.Name)
+	assert.Equal(t, hookanalytics.ActivityStatusSuccess, activity.Status)
+	require.Len(t, activity.Results, 2)
+	assert.Equal(t, []string{"video-imp"}, activity.Results[0].AppliedTo.ImpIds)
+	assert.Equal(t, "video|*", activity.Results[0].Values["ruleFired"])
+	assert.Equal(t, "imp-rules", activity.Results[0].Values["analyticsKey"])
+	assert.Equal(t, []string{"home-imp"}, activity.Results[1].AppliedTo.ImpIds)
+	assert.Equal(t, "banner|/1/home", activity.Results[1].Values["ruleFired"])
+}
+
+func TestHandleProcessedAuctionImpHookNoRuleFired(t *testing.T) {
+	ruleSet := newTestRuleSet(t, `{
+		"schema": [{"function": "dealsAvailable"}],
+		"rules": [{"conditions": ["true"], "results": [{"function": "excludeBidders", "args": {"bidders": ["bidderA"]}}]}]
+	}`, rules.NewImpSchemaFunction, NewImpResultFunction)
+	payload := hs.ProcessedAuctionRequestPayload{
+		Request: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp-1"}}}},
+	}
+
+	result := handleProcessedAuctionImpHook([]ImpRuleSet{ruleSet}, payload, hs.HookResult[hs.ProcessedAuctionRequestPayload]{})
+
+	assert.Empty(t, result.ChangeSet.Mutations())
+	assert.Empty(t, result.AnalyticsTags.Activities)
+	assert.Empty(t, result.Errors)
+}
+
+func TestHandleProcessedAuctionImpHookWithoutRequest(t *testing.T) {
+	result := handleProcessedAuctionImpHook(nil, hs.ProcessedAuctionRequestPayload{}, hs.HookResult[hs.ProcessedAuctionRequestPayload]{})
+
+	assert.Equal(t, hs.HookResult[hs.ProcessedAuctionRequestPayload]{}, result)
+}
+
+func TestFilterImpBidders(t *testing.T) {
+	bidders := map[string]json.RawMessage{"bidderA": json.RawMessage(`{"a":1}`), "bidderB": nil, "bidderC": nil}
+
+	testCases := []struct {
+		name      string
+		impResult ImpHookResult
+		expected  map[string]json.RawMessage
+	}{
+		{
+			name:      "excluded",
+			impResult: ImpHookResult{ExcludedBidders: map[string]struct{}{"bidderB": {}}},
+			expected:  map[string]json.RawMessage{"bidderA": json.RawMessage(`{"a":1}`), "bidderC": nil},
+		},
+		{
+			name:      "allowed",
+			impResult: ImpHookResult{AllowedBidders: map[string]struct{}{"bidderA": {}, "bidderD": {}}},
+			expected:  map[string]json.RawMessage{"bidderA": json.RawMessage(`{"a":1}`)},
+		},
+		{
+			name: "allowed-and-excluded",
+			impResult: ImpHookResult{
+				AllowedBidders:  map[string]struct{}{"bidderA": {}, "bidderB": {}},
+				ExcludedBidders: map[string]struct{}{"bidderA": {}},
+			},
+			expected: map[string]json.RawMessage{"bidderB": nil},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, filterImpBidders(bidders, tc.impResult))
+		})
+	}
+}
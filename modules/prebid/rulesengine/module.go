@@ -8,6 +8,7 @@ import (
 
 	"github.com/buger/jsonparser"
 
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
 	hs "github.com/prebid/prebid-server/v3/hooks/hookstage"
 	"github.com/prebid/prebid-server/v3/modules/moduledeps"
 	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
@@ -39,9 +40,24 @@ func Builder(cfg json.RawMessage, deps moduledeps.ModuleDeps) (interface{}, erro
 
 	go tm.Run(c)
 
+	sourceCfg, err := config.NewRulesetSource(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var fetcher *rulesetFetcher
+	if sourceCfg != nil {
+		fetcher = newRulesetFetcher(sourceCfg, newRulesetSource(sourceCfg, deps.HTTPClient), tm.monitor)
+		go fetcher.Run()
+
+		if deps.AdminEndpoints != nil {
+			deps.AdminEndpoints[rulesetVersionsPath] = newRulesetVersionsEndpoint(fetcher)
+		}
+	}
+
 	return Module{
-		Cache:       c,
-		TreeManager: &tm,
+		Cache:          c,
+		TreeManager:    &tm,
+		RulesetFetcher: fetcher,
 	}, nil
 }
 
@@ -49,6 +65,9 @@ func Builder(cfg json.RawMessage, deps moduledeps.ModuleDeps) (interface{}, erro
 type Module struct {
 	Cache       cacher
 	TreeManager *treeManager
+
+	// RulesetFetcher fetches the account configurations from the ruleset source, if one is configured
+	RulesetFetcher *rulesetFetcher
 }
 
 // HandleProcessedAuctionHook updates field on openrtb2.BidRequest.
@@ -58,52 +77,147 @@ func (m Module) HandleProcessedAuctionHook(
 	miCtx hs.ModuleInvocationContext,
 	payload hs.ProcessedAuctionRequestPayload,
 ) (hs.HookResult[hs.ProcessedAuctionRequestPayload], error) {
-	result := hs.HookResult[hs.ProcessedAuctionRequestPayload]{}
+	co, message := m.accountCacheEntry(miCtx)
+	if co == nil {
+		return hs.HookResult[hs.ProcessedAuctionRequestPayload]{Message: message}, nil
+	}
+
+	result, err := handleProcessedAuctionHook(co.ruleSetsForProcessedAuctionRequestStage, payload)
+	if err != nil {
+		return result, err
+	}
+	result = handleProcessedAuctionImpHook(co.impRuleSetsForProcessedAuctionRequestStage, payload, result)
+	result.AnalyticsTags = recordRulesetVersion(result.AnalyticsTags, co.version)
+	return result, nil
+}
+
+// HandleBidderRequestHook adjusts the request sent to a bidder according to the rulesets of the bidder_request stage.
+func (m Module) HandleBidderRequestHook(
+	_ context.Context,
+	miCtx hs.ModuleInvocationContext,
+	payload hs.BidderRequestPayload,
+) (hs.HookResult[hs.BidderRequestPayload], error) {
+	co, message := m.accountCacheEntry(miCtx)
+	if co == nil {
+		return hs.HookResult[hs.BidderRequestPayload]{Message: message}, nil
+	}
+
+	return handleBidderRequestHook(co.ruleSetsForBidderRequestStage, payload)
+}
+
+// HandleRawBidderResponseHook rejects bids of a bidder response according to the rulesets of the
+// raw_bidder_response stage.
+func (m Module) HandleRawBidderResponseHook(
+	_ context.Context,
+	miCtx hs.ModuleInvocationContext,
+	payload hs.RawBidderResponsePayload,
+) (hs.HookResult[hs.RawBidderResponsePayload], error) {
+	co, message := m.accountCacheEntry(miCtx)
+	if co == nil {
+		return hs.HookResult[hs.RawBidderResponsePayload]{Message: message}, nil
+	}
+
+	return handleRawBidderResponseHook(co.ruleSetsForRawBidderResponseStage, payload)
+}
+
+// HandleAllProcessedBidResponsesHook rejects bids or sets their targeting according to the rulesets of the
+// all_processed_bid_responses stage.
+func (m Module) HandleAllProcessedBidResponsesHook(
+	_ context.Context,
+	miCtx hs.ModuleInvocationContext,
+	payload hs.AllProcessedBidResponsesPayload,
+) (hs.HookResult[hs.AllProcessedBidResponsesPayload], error) {
+	co, message := m.accountCacheEntry(miCtx)
+	if co == nil {
+		return hs.HookResult[hs.AllProcessedBidResponsesPayload]{Message: message}, nil
+	}
 
+	return handleAllProcessedBidResponsesHook(co.ruleSetsForAllProcessedBidResponsesStage, payload)
+}
+
+// accountCacheEntry returns the cached rule sets of the account, asking the tree manager to build them when they
+// are missing or outdated. When the rule sets can't be used, it returns the message the hook is skipped with.
+// The active version fetched from the ruleset source takes precedence over the account configuration.
+func (m Module) accountCacheEntry(miCtx hs.ModuleInvocationContext) (*cacheEntry, string) {
 	// AccountConfig will either be an account-specific config or the default account config
 	// AccountConfig only contains the config block for this module
-	if len(miCtx.AccountConfig) == 0 {
-		return result, nil
+	accountConfig := miCtx.AccountConfig
+	var fetched *rulesetVersion
+	if m.RulesetFetcher != nil {
+		if fetched = m.RulesetFetcher.Active(miCtx.AccountID); fetched != nil {
+			accountConfig = fetched.config
+		}
+	}
+	if len(accountConfig) == 0 {
+		return nil, ""
 	}
 
+	bi := buildInstruction{
+		accountID: miCtx.AccountID,
+		config:    &accountConfig,
+		versioned: fetched != nil,
+	}
 	co := m.Cache.Get(miCtx.AccountID)
 
 	// cache miss
 	if co == nil {
-		bi := buildInstruction{
-			accountID: miCtx.AccountID,
-			config:    &miCtx.AccountConfig,
-		}
 		m.TreeManager.requests <- bi
 
 		// TODO: return with reject or no reject, possible config option
-		return hs.HookResult[hs.ProcessedAuctionRequestPayload]{
-			Message: "skipped, loading rules engine account configuration for future requests",
-		}, nil
+		return nil, "skipped, loading rules engine account configuration for future requests"
 	}
 	// cache hit
-	if rebuildTrees(co, &miCtx.AccountConfig, m.Cache) {
-		bi := buildInstruction{
-			accountID: miCtx.AccountID,
-			config:    &miCtx.AccountConfig,
-		}
+	if fetched != nil && fetched.hash != co.hashedConfig || fetched == nil && rebuildTrees(co, &accountConfig, m.Cache) {
 		m.TreeManager.requests <- bi
 	}
 
 	if !co.enabled {
-		return hs.HookResult[hs.ProcessedAuctionRequestPayload]{
-			Message: "skipped, rules engine is disabled for this account",
-		}, nil
+		return nil, "skipped, rules engine is disabled for this account"
+	}
+	return co, ""
+}
+
+// recordRulesetVersion reports the version of the account configuration in the rules engine activity, adding it
+// to the results of the rules that fired or, when none fired, as a result of its own.
+func recordRulesetVersion(tags hookanalytics.Analytics, version string) hookanalytics.Analytics {
+	if version == "" {
+		return tags
+	}
+
+	for i := range tags.Activities {
+		if tags.Activities[i].Name != rulesEngineActivity {
+			continue
+		}
+		for j := range tags.Activities[i].Results {
+			if tags.Activities[i].Results[j].Values == nil {
+				tags.Activities[i].Results[j].Values = make(map[string]interface{})
+			}
+			tags.Activities[i].Results[j].Values["rulesetVersion"] = version
+		}
+		return tags
 	}
 
-	return handleProcessedAuctionHook(co.ruleSetsForProcessedAuctionRequestStage, payload)
+	tags.Activities = append(tags.Activities, hookanalytics.Activity{
+		Name:   rulesEngineActivity,
+		Status: hookanalytics.ActivityStatusSuccess,
+		Results: []hookanalytics.Result{{
+			Status:    hookanalytics.ResultStatusAllow,
+			Values:    map[string]interface{}{"rulesetVersion": version},
+			AppliedTo: hookanalytics.AppliedTo{Request: true},
+		}},
+	})
+	return tags
 }
 
 // Shutdown signals the module to stop processing and waits for the tree manager to finish
 // processing any remaining build instructions in the channel.
-func (m Module) Shutdown() {
+func (m Module) Shutdown() error {
+	if m.RulesetFetcher != nil {
+		m.RulesetFetcher.Shutdown()
+	}
 	m.TreeManager.Shutdown()
 	<-m.TreeManager.done
+	return nil
 }
 
 // rebuildTrees returns true if the trees need to be rebuilt; false otherwise
@@ -7,6 +7,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	hs "github.com/prebid/prebid-server/v3/hooks/hookstage"
 	"github.com/prebid/prebid-server/v3/modules/moduledeps"
 	"github.com/stretchr/testify/assert"
 )
@@ -74,6 +76,20 @@ func TestBuilderWithWorkingDir(t *testing.T) {
 			expectError:      false,
 			expectedModuleOK: true,
 		},
+		{
+			name:             "invalid-ruleset-source",
+			config:           json.RawMessage(`{"rulesetsource": {"type": "ftp"}}`),
+			deps:             moduledeps.ModuleDeps{},
+			expectError:      true,
+			expectedModuleOK: false,
+		},
+		{
+			name:             "file-ruleset-source",
+			config:           json.RawMessage(`{"rulesetsource": {"type": "file", "path": "rulesets"}}`),
+			deps:             moduledeps.ModuleDeps{AdminEndpoints: moduledeps.AdminEndpoints{}},
+			expectError:      false,
+			expectedModuleOK: true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -100,6 +116,12 @@ func TestBuilderWithWorkingDir(t *testing.T) {
 				// Verify module components are initialized
 				assert.NotNil(t, m.Cache)
 				assert.NotNil(t, m.TreeManager)
+
+				// Verify the ruleset versions endpoint is registered along with the ruleset source
+				if tc.deps.AdminEndpoints != nil {
+					assert.NotNil(t, m.RulesetFetcher)
+					assert.Contains(t, tc.deps.AdminEndpoints, rulesetVersionsPath)
+				}
 			}
 		})
 	}
@@ -259,3 +281,100 @@ func TestGetRefreshRate(t *testing.T) {
 		})
 	}
 }
+
+func TestAccountCacheEntryWithRulesetFetcher(t *testing.T) {
+	accountConfig := json.RawMessage(`{"enabled": true, "rulesets": []}`)
+	source := &fakeRulesetSource{rulesets: map[string]json.RawMessage{"account-1": json.RawMessage(`{"enabled": true, "version": "v1", "rulesets": []}`)}}
+	fetcher := newTestRulesetFetcher(source, 3)
+	fetcher.fetch("account-1")
+	v1 := fetcher.Active("account-1")
+
+	m := Module{
+		Cache:          NewCache(0),
+		TreeManager:    &treeManager{requests: make(chan buildInstruction, 10)},
+		RulesetFetcher: fetcher,
+	}
+
+	// the fetched version is built in place of the account config
+	co, message := m.accountCacheEntry(hs.ModuleInvocationContext{AccountID: "account-1", AccountConfig: accountConfig})
+	assert.Nil(t, co)
+	assert.Equal(t, "skipped, loading rules engine account configuration for future requests", message)
+	bi := <-m.TreeManager.requests
+	assert.Equal(t, v1.config, *bi.config)
+	assert.True(t, bi.versioned)
+
+	// the cached entry of the active version is used
+	m.Cache.Set("account-1", &cacheEntry{enabled: true, hashedConfig: v1.hash, version: "v1"})
+	co, _ = m.accountCacheEntry(hs.ModuleInvocationContext{AccountID: "account-1", AccountConfig: accountConfig})
+	assert.Equal(t, "v1", co.version)
+	assert.Empty(t, m.TreeManager.requests)
+
+	// a new active version is rebuilt right away, even though the cache entry never expires
+	source.rulesets["account-1"] = json.RawMessage(`{"enabled": true, "version": "v2", "rulesets": []}`)
+	fetcher.fetch("account-1")
+	co, _ = m.accountCacheEntry(hs.ModuleInvocationContext{AccountID: "account-1", AccountConfig: accountConfig})
+	assert.Equal(t, "v1", co.version)
+	bi = <-m.TreeManager.requests
+	assert.JSONEq(t, `{"enabled": true, "version": "v2", "rulesets": []}`, string(*bi.config))
+
+	// accounts missing from the source use their account config
+	co, _ = m.accountCacheEntry(hs.ModuleInvocationContext{AccountID: "account-2", AccountConfig: accountConfig})
+	assert.Nil(t, co)
+	bi = <-m.TreeManager.requests
+	assert.Equal(t, accountConfig, *bi.config)
+	assert.False(t, bi.versioned)
+}
+
+func TestRecordRulesetVersion(t *testing.T) {
+	testCases := []struct {
+		name         string
+		inTags       hookanalytics.Analytics
+		inVersion    string
+		expectedTags hookanalytics.Analytics
+	}{
+		{
+			name:         "no-version",
+			inTags:       hookanalytics.Analytics{},
+			expectedTags: hookanalytics.Analytics{},
+		},
+		{
+			name:      "no-rules-fired",
+			inVersion: "v1",
+			expectedTags: hookanalytics.Analytics{Activities: []hookanalytics.Activity{{
+				Name:   rulesEngineActivity,
+				Status: hookanalytics.ActivityStatusSuccess,
+				Results: []hookanalytics.Result{{
+					Status:    hookanalytics.ResultStatusAllow,
+					Values:    map[string]interface{}{"rulesetVersion": "v1"},
+					AppliedTo: hookanalytics.AppliedTo{Request: true},
+				}},
+			}}},
+		},
+		{
+			name: "rules-fired",
+			inTags: hookanalytics.Analytics{Activities: []hookanalytics.Activity{{
+				Name:   rulesEngineActivity,
+				Status: hookanalytics.ActivityStatusSuccess,
+				Results: []hookanalytics.Result{
+					{Status: hookanalytics.ResultStatusModify, Values: map[string]interface{}{"ruleFired": "banner"}},
+					{Status: hookanalytics.ResultStatusModify},
+				},
+			}}},
+			inVersion: "v1",
+			expectedTags: hookanalytics.Analytics{Activities: []hookanalytics.Activity{{
+				Name:   rulesEngineActivity,
+				Status: hookanalytics.ActivityStatusSuccess,
+				Results: []hookanalytics.Result{
+					{Status: hookanalytics.ResultStatusModify, Values: map[string]interface{}{"ruleFired": "banner", "rulesetVersion": "v1"}},
+					{Status: hookanalytics.ResultStatusModify, Values: map[string]interface{}{"rulesetVersion": "v1"}},
+				},
+			}}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedTags, recordRulesetVersion(tc.inTags, tc.inVersion))
+		})
+	}
+}
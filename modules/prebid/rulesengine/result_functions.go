@@ -4,19 +4,31 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 
+	"github.com/prebid/prebid-server/v3/bidadjustment"
+	"github.com/prebid/prebid-server/v3/floors"
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	hs "github.com/prebid/prebid-server/v3/hooks/hookstage"
 	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/ortb"
 	"github.com/prebid/prebid-server/v3/rules"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
 )
 
 // ProcessedAuctionResultFunc is a type alias for a result function that runs in the processed auction request stage.
 type ProcessedAuctionResultFunc = rules.ResultFunction[openrtb_ext.RequestWrapper, ProcessedAuctionHookResult]
 
 const (
-	ExcludeBiddersName = "excludeBidders"
-	IncludeBiddersName = "includeBidders"
+	ExcludeBiddersName      = "excludeBidders"
+	IncludeBiddersName      = "includeBidders"
+	SetFloorsName           = "setFloors"
+	SetBidAdjustmentsName   = "setBidAdjustments"
+	SetPriceGranularityName = "setPriceGranularity"
+	SetTargetingOptionsName = "setTargetingOptions"
 )
 
 // NewProcessedAuctionRequestResultFunction is a factory function that creates a new result function based on the provided name and parameters.
@@ -32,6 +44,14 @@ func NewProcessedAuctionRequestResultFunction(name string, params json.RawMessag
 		return NewExcludeBidders(params)
 	case IncludeBiddersName:
 		return NewIncludeBidders(params)
+	case SetFloorsName:
+		return NewSetFloors(params)
+	case SetBidAdjustmentsName:
+		return NewSetBidAdjustments(params)
+	case SetPriceGranularityName:
+		return NewSetPriceGranularity(params)
+	case SetTargetingOptionsName:
+		return NewSetTargetingOptions(params)
 	default:
 		return nil, fmt.Errorf("result function %s was not created", name)
 	}
@@ -106,3 +126,438 @@ func (ib *IncludeBidders) Call(req *openrtb_ext.RequestWrapper, result *Processe
 func (ib *IncludeBidders) Name() string {
 	return IncludeBiddersName
 }
+
+// NewSetFloors is a factory function that creates a new SetFloors result function. The floors are validated
+// like floors provided in the request, failing on invalid floors instead of dropping them.
+func NewSetFloors(params json.RawMessage) (ProcessedAuctionResultFunc, error) {
+	var setFloorsParams config.FloorsParams
+	if err := jsonutil.Unmarshal(params, &setFloorsParams); err != nil {
+		return nil, err
+	}
+	if setFloorsParams.Floors == nil {
+		return nil, errors.New("setFloors requires floors to be specified")
+	}
+	if err := floors.ValidateFloorRules(setFloorsParams.Floors); err != nil {
+		return nil, fmt.Errorf("setFloors floors are invalid: %w", err)
+	}
+	return &SetFloors{Args: setFloorsParams}, nil
+}
+
+// SetFloors is a struct that holds the floors the rules engine sets on the request.
+type SetFloors struct {
+	Args config.FloorsParams
+}
+
+// Call replaces the floors of the request with a copy of the floors, as the floors are updated during the auction.
+func (sf *SetFloors) Call(req *openrtb_ext.RequestWrapper, result *ProcessedAuctionHookResult, meta rules.ResultFunctionMeta) error {
+	updateRequestPrebid(result, func(prebid *openrtb_ext.ExtRequestPrebid) {
+		prebid.Floors = sf.Args.Floors.DeepCopy()
+	}, "floors")
+	return nil
+}
+
+func (sf *SetFloors) Name() string {
+	return SetFloorsName
+}
+
+// NewSetBidAdjustments is a factory function that creates a new SetBidAdjustments result function.
+func NewSetBidAdjustments(params json.RawMessage) (ProcessedAuctionResultFunc, error) {
+	var setBidAdjustmentsParams config.BidAdjustmentsParams
+	if err := jsonutil.Unmarshal(params, &setBidAdjustmentsParams); err != nil {
+		return nil, err
+	}
+	if setBidAdjustmentsParams.BidAdjustments == nil {
+		return nil, errors.New("setBidAdjustments requires bidadjustments to be specified")
+	}
+	if !bidadjustment.Validate(setBidAdjustmentsParams.BidAdjustments) {
+		return nil, errors.New("setBidAdjustments bidadjustments are invalid")
+	}
+	return &SetBidAdjustments{Args: setBidAdjustmentsParams}, nil
+}
+
+// SetBidAdjustments is a struct that holds the bid adjustments the rules engine sets on the request.
+type SetBidAdjustments struct {
+	Args config.BidAdjustmentsParams
+}
+
+// Call replaces the bid adjustments of the request with a copy of the bid adjustments, which are still merged
+// with the account bid adjustments by the auction.
+func (sba *SetBidAdjustments) Call(req *openrtb_ext.RequestWrapper, result *ProcessedAuctionHookResult, meta rules.ResultFunctionMeta) error {
+	updateRequestPrebid(result, func(prebid *openrtb_ext.ExtRequestPrebid) {
+		prebid.BidAdjustments = sba.Args.BidAdjustments.DeepCopy()
+	}, "bidadjustments")
+	return nil
+}
+
+func (sba *SetBidAdjustments) Name() string {
+	return SetBidAdjustmentsName
+}
+
+// NewSetPriceGranularity is a factory function that creates a new SetPriceGranularity result function.
+func NewSetPriceGranularity(params json.RawMessage) (ProcessedAuctionResultFunc, error) {
+	var setPriceGranularityParams config.PriceGranularityParams
+	if err := jsonutil.Unmarshal(params, &setPriceGranularityParams); err != nil {
+		return nil, err
+	}
+	if setPriceGranularityParams.PriceGranularity == nil {
+		return nil, errors.New("setPriceGranularity requires pricegranularity to be specified")
+	}
+	// min and precision default as they do for the price granularity of a request
+	setPriceGranularityParams.PriceGranularity = ortb.SetDefaultsPriceGranularity(setPriceGranularityParams.PriceGranularity)
+	if err := openrtb_ext.ValidatePriceGranularity(setPriceGranularityParams.PriceGranularity); err != nil {
+		return nil, err
+	}
+	return &SetPriceGranularity{Args: setPriceGranularityParams}, nil
+}
+
+// SetPriceGranularity is a struct that holds the price granularity the rules engine sets on the request.
+type SetPriceGranularity struct {
+	Args config.PriceGranularityParams
+}
+
+// Call replaces the price granularity of requests asking for targeting. Targeting is not added to requests
+// without it.
+func (spg *SetPriceGranularity) Call(req *openrtb_ext.RequestWrapper, result *ProcessedAuctionHookResult, meta rules.ResultFunctionMeta) error {
+	updateRequestPrebid(result, func(prebid *openrtb_ext.ExtRequestPrebid) {
+		if prebid.Targeting == nil {
+			return
+		}
+		targeting := *prebid.Targeting
+		targeting.PriceGranularity = &openrtb_ext.PriceGranularity{
+			Precision: ptrutil.Clone(spg.Args.PriceGranularity.Precision),
+			Ranges:    slices.Clone(spg.Args.PriceGranularity.Ranges),
+		}
+		prebid.Targeting = &targeting
+	}, "targeting", "pricegranularity")
+	return nil
+}
+
+func (spg *SetPriceGranularity) Name() string {
+	return SetPriceGranularityName
+}
+
+// NewSetTargetingOptions is a factory function that creates a new SetTargetingOptions result function.
+func NewSetTargetingOptions(params json.RawMessage) (ProcessedAuctionResultFunc, error) {
+	var setTargetingOptionsParams config.TargetingOptionsParams
+	if err := jsonutil.Unmarshal(params, &setTargetingOptionsParams); err != nil {
+		return nil, err
+	}
+	if setTargetingOptionsParams == (config.TargetingOptionsParams{}) {
+		return nil, errors.New("setTargetingOptions requires at least one targeting option to be specified")
+	}
+	return &SetTargetingOptions{Args: setTargetingOptionsParams}, nil
+}
+
+// SetTargetingOptions is a struct that holds the targeting options the rules engine forces on the request.
+type SetTargetingOptions struct {
+	Args config.TargetingOptionsParams
+}
+
+// Call overrides the targeting options of requests asking for targeting. Targeting is not added to requests
+// without it.
+func (sto *SetTargetingOptions) Call(req *openrtb_ext.RequestWrapper, result *ProcessedAuctionHookResult, meta rules.ResultFunctionMeta) error {
+	updateRequestPrebid(result, func(prebid *openrtb_ext.ExtRequestPrebid) {
+		if prebid.Targeting == nil {
+			return
+		}
+		targeting := *prebid.Targeting
+		if sto.Args.IncludeWinners != nil {
+			targeting.IncludeWinners = ptrutil.ToPtr(*sto.Args.IncludeWinners)
+		}
+		if sto.Args.IncludeBidderKeys != nil {
+			targeting.IncludeBidderKeys = ptrutil.ToPtr(*sto.Args.IncludeBidderKeys)
+		}
+		if sto.Args.IncludeFormat != nil {
+			targeting.IncludeFormat = *sto.Args.IncludeFormat
+		}
+		if sto.Args.PreferDeals != nil {
+			targeting.PreferDeals = *sto.Args.PreferDeals
+		}
+		if sto.Args.AlwaysIncludeDeals != nil {
+			targeting.AlwaysIncludeDeals = *sto.Args.AlwaysIncludeDeals
+		}
+		prebid.Targeting = &targeting
+	}, "targeting")
+	return nil
+}
+
+func (sto *SetTargetingOptions) Name() string {
+	return SetTargetingOptionsName
+}
+
+// updateRequestPrebid adds a mutation updating the ext.prebid of the request.
+func updateRequestPrebid(result *ProcessedAuctionHookResult, update func(*openrtb_ext.ExtRequestPrebid), keys ...string) {
+	result.HookResult.ChangeSet.AddMutation(func(p hs.ProcessedAuctionRequestPayload) (hs.ProcessedAuctionRequestPayload, error) {
+		if p.Request == nil || p.Request.BidRequest == nil {
+			return p, errors.New("payload contains a nil bid request")
+		}
+		reqExt, err := p.Request.GetRequestExt()
+		if err != nil {
+			return p, err
+		}
+		prebid := reqExt.GetPrebid()
+		if prebid == nil {
+			prebid = &openrtb_ext.ExtRequestPrebid{}
+		}
+		update(prebid)
+		reqExt.SetPrebid(prebid)
+		return p, nil
+	}, hs.MutationUpdate, append([]string{"bidrequest", "ext", "prebid"}, keys...)...)
+}
+
+// BidderRequestResultFunc is a type alias for a result function that runs in the bidder request stage.
+type BidderRequestResultFunc = rules.ResultFunction[rules.BidderRequest, BidderRequestHookResult]
+
+const (
+	AddBcatName    = "addBcat"
+	RemoveBcatName = "removeBcat"
+	AddBadvName    = "addBadv"
+	RemoveBadvName = "removeBadv"
+)
+
+// NewBidderRequestResultFunction is a factory function that creates a new result function for the bidder request
+// stage based on the provided name and parameters. The result functions adjust the bcat and badv of the request sent
+// to the bidder, leaving the requests of the other bidders unchanged.
+func NewBidderRequestResultFunction(name string, params json.RawMessage) (BidderRequestResultFunc, error) {
+	switch name {
+	case AddBcatName, RemoveBcatName:
+		return newBlockedAttributesFunc(name, params, "categories", func(p config.BlockedAttributesParams) []string { return p.Categories })
+	case AddBadvName, RemoveBadvName:
+		return newBlockedAttributesFunc(name, params, "domains", func(p config.BlockedAttributesParams) []string { return p.Domains })
+	default:
+		return nil, fmt.Errorf("result function %s was not created", name)
+	}
+}
+
+func newBlockedAttributesFunc(name string, params json.RawMessage, field string, values func(config.BlockedAttributesParams) []string) (BidderRequestResultFunc, error) {
+	var blockedAttributesParams config.BlockedAttributesParams
+	if err := jsonutil.Unmarshal(params, &blockedAttributesParams); err != nil {
+		return nil, err
+	}
+	if len(values(blockedAttributesParams)) == 0 {
+		return nil, fmt.Errorf("%s requires at least one of %s to be specified", name, field)
+	}
+	return &BlockedAttributes{name: name, Args: blockedAttributesParams}, nil
+}
+
+// BlockedAttributes is a struct that holds parameters for adding or removing blocked categories and advertiser
+// domains in the request of a bidder.
+type BlockedAttributes struct {
+	name string
+	Args config.BlockedAttributesParams
+}
+
+// Call adds or removes the categories or domains from the bcat or badv the bidder request will be sent with.
+func (ba *BlockedAttributes) Call(req *rules.BidderRequest, result *BidderRequestHookResult, meta rules.ResultFunctionMeta) error {
+	switch ba.name {
+	case AddBcatName:
+		result.BCat = addValues(result.BCat, ba.Args.Categories)
+	case RemoveBcatName:
+		result.BCat = removeValues(result.BCat, ba.Args.Categories)
+	case AddBadvName:
+		result.BAdv = addValues(result.BAdv, ba.Args.Domains)
+	case RemoveBadvName:
+		result.BAdv = removeValues(result.BAdv, ba.Args.Domains)
+	}
+	return nil
+}
+
+func (ba *BlockedAttributes) Name() string {
+	return ba.name
+}
+
+func addValues(current, values []string) []string {
+	updated := slices.Clone(current)
+	for _, value := range values {
+		if !slices.Contains(updated, value) {
+			updated = append(updated, value)
+		}
+	}
+	return updated
+}
+
+func removeValues(current, values []string) []string {
+	return slices.DeleteFunc(slices.Clone(current), func(value string) bool {
+		return slices.Contains(values, value)
+	})
+}
+
+// BidResultFunc is a type alias for a result function that runs on each bid in the raw bidder response and all
+// processed bid responses stages.
+type BidResultFunc = rules.ResultFunction[rules.Bid, BidHookResult]
+
+const (
+	RejectBidName    = "rejectBid"
+	SetTargetingName = "setTargeting"
+)
+
+// defaultRejectBidSeatNonBid is the seat non-bid code of bids rejected without one: response rejected - general.
+const defaultRejectBidSeatNonBid = 300
+
+// NewRawBidderResponseResultFunction is a factory function that creates a new result function for the raw bidder
+// response stage based on the provided name and parameters.
+func NewRawBidderResponseResultFunction(name string, params json.RawMessage) (BidResultFunc, error) {
+	switch name {
+	case RejectBidName:
+		return NewRejectBid(params)
+	default:
+		return nil, fmt.Errorf("result function %s was not created", name)
+	}
+}
+
+// NewAllProcessedBidResponsesResultFunction is a factory function that creates a new result function for the all
+// processed bid responses stage based on the provided name and parameters. Targeting can only be set once bids are
+// processed, as raw bids don't carry targeting.
+func NewAllProcessedBidResponsesResultFunction(name string, params json.RawMessage) (BidResultFunc, error) {
+	switch name {
+	case RejectBidName:
+		return NewRejectBid(params)
+	case SetTargetingName:
+		return NewSetTargeting(params)
+	default:
+		return nil, fmt.Errorf("result function %s was not created", name)
+	}
+}
+
+// NewRejectBid is a factory function that creates a new RejectBid result function. The seat non-bid code
+// defaults to 300 (response rejected - general).
+func NewRejectBid(params json.RawMessage) (BidResultFunc, error) {
+	var rejectBidParams config.ResultFuncParams
+	if len(params) > 0 {
+		if err := jsonutil.Unmarshal(params, &rejectBidParams); err != nil {
+			return nil, err
+		}
+	}
+	if rejectBidParams.SeatNonBid == 0 {
+		rejectBidParams.SeatNonBid = defaultRejectBidSeatNonBid
+	}
+	return &RejectBid{Args: rejectBidParams}, nil
+}
+
+// RejectBid is a struct that holds parameters for rejecting bids in the rules engine.
+type RejectBid struct {
+	Args config.ResultFuncParams
+}
+
+// Call marks the bid as rejected with the seat non-bid code.
+func (rb *RejectBid) Call(bid *rules.Bid, result *BidHookResult, meta rules.ResultFunctionMeta) error {
+	result.Rejected = true
+	result.SeatNonBid = rb.Args.SeatNonBid
+	return nil
+}
+
+func (rb *RejectBid) Name() string {
+	return RejectBidName
+}
+
+// NewSetTargeting is a factory function that creates a new SetTargeting result function.
+func NewSetTargeting(params json.RawMessage) (BidResultFunc, error) {
+	var setTargetingParams config.TargetingParams
+	if err := jsonutil.Unmarshal(params, &setTargetingParams); err != nil {
+		return nil, err
+	}
+	if len(setTargetingParams.Targeting) == 0 {
+		return nil, errors.New("setTargeting requires at least one targeting key to be specified")
+	}
+	for key := range setTargetingParams.Targeting {
+		if key == "" {
+			return nil, errors.New("setTargeting requires non-empty targeting keys")
+		}
+	}
+	return &SetTargeting{Args: setTargetingParams}, nil
+}
+
+// SetTargeting is a struct that holds the targeting keys the rules engine adds to a bid.
+type SetTargeting struct {
+	Args config.TargetingParams
+}
+
+// Call adds the targeting keys to the bid. Keys set by the exchange take precedence.
+func (st *SetTargeting) Call(bid *rules.Bid, result *BidHookResult, meta rules.ResultFunctionMeta) error {
+	if result.Targeting == nil {
+		result.Targeting = make(map[string]string, len(st.Args.Targeting))
+	}
+	maps.Copy(result.Targeting, st.Args.Targeting)
+	return nil
+}
+
+func (st *SetTargeting) Name() string {
+	return SetTargetingName
+}
+
+// ImpResultFunc is a type alias for a result function that runs on each imp in the processed auction request stage.
+type ImpResultFunc = rules.ResultFunction[rules.Imp, ImpHookResult]
+
+// NewImpResultFunction is a factory function that creates a new result function for the rulesets evaluated on
+// each imp of the request. The bidders are allowed or excluded on the imp only, the other imps are unchanged.
+func NewImpResultFunction(name string, params json.RawMessage) (ImpResultFunc, error) {
+	switch name {
+	case ExcludeBiddersName, IncludeBiddersName:
+		return NewImpBidders(name, params)
+	default:
+		return nil, fmt.Errorf("result function %s was not created", name)
+	}
+}
+
+// NewImpBidders is a factory function that creates a new ImpBidders result function.
+func NewImpBidders(name string, params json.RawMessage) (ImpResultFunc, error) {
+	var impBiddersParams config.ResultFuncParams
+	if err := jsonutil.Unmarshal(params, &impBiddersParams); err != nil {
+		return nil, err
+	}
+	if len(impBiddersParams.Bidders) == 0 {
+		return nil, fmt.Errorf("%s requires at least one bidder to be specified", name)
+	}
+	return &ImpBidders{name: name, Args: impBiddersParams}, nil
+}
+
+// ImpBidders is a struct that holds parameters for excluding bidders from an imp, or restricting the imp to
+// the bidders, in the rules engine.
+type ImpBidders struct {
+	name string
+	Args config.ResultFuncParams
+}
+
+// Call records the bidders to exclude from or allow on the imp, along with the analytics of the rule that fired.
+func (ib *ImpBidders) Call(imp *rules.Imp, result *ImpHookResult, meta rules.ResultFunctionMeta) error {
+	if ib.name == IncludeBiddersName {
+		result.AllowedBidders = addBidders(result.AllowedBidders, ib.Args.Bidders)
+	} else {
+		result.ExcludedBidders = addBidders(result.ExcludedBidders, ib.Args.Bidders)
+	}
+
+	values := map[string]interface{}{
+		"analyticsKey":   meta.AnalyticsKey,
+		"modelVersion":   meta.ModelVersion,
+		"ruleFired":      meta.RuleFired,
+		"resultFunction": ib.name,
+		"bidders":        ib.Args.Bidders,
+	}
+	if ib.Args.AnalyticsValue != "" {
+		values["analyticsValue"] = ib.Args.AnalyticsValue
+	}
+	var impIDs []string
+	if imp != nil && imp.Imp != nil && imp.Imp.Imp != nil {
+		impIDs = []string{imp.Imp.ID}
+	}
+	result.AnalyticsResults = append(result.AnalyticsResults, hookanalytics.Result{
+		Status:    hookanalytics.ResultStatusModify,
+		Values:    values,
+		AppliedTo: hookanalytics.AppliedTo{ImpIds: impIDs},
+	})
+	return nil
+}
+
+func (ib *ImpBidders) Name() string {
+	return ib.name
+}
+
+func addBidders(bidders map[string]struct{}, bidderNames []string) map[string]struct{} {
+	if bidders == nil {
+		bidders = make(map[string]struct{}, len(bidderNames))
+	}
+	for _, bidderName := range bidderNames {
+		bidders[bidderName] = struct{}{}
+	}
+	return bidders
+}
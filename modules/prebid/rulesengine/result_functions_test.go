@@ -5,11 +5,15 @@ import (
 	"testing"
 
 	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
 	hs "github.com/prebid/prebid-server/v3/hooks/hookstage"
 	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/ortb"
 	"github.com/prebid/prebid-server/v3/rules"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewProcessedAuctionRequestResultFunction(t *testing.T) {
@@ -207,3 +211,447 @@ func mockRequestWrapperWithBidders(t *testing.T, bidders []string) *openrtb_ext.
 
 	return rw
 }
+
+func TestNewBidderRequestResultFunction(t *testing.T) {
+	tests := []struct {
+		name        string
+		funcName    string
+		params      json.RawMessage
+		expectError string
+	}{
+		{name: "addBcat", funcName: AddBcatName, params: json.RawMessage(`{"categories":["IAB1"]}`)},
+		{name: "removeBcat", funcName: RemoveBcatName, params: json.RawMessage(`{"categories":["IAB1"]}`)},
+		{name: "addBadv", funcName: AddBadvName, params: json.RawMessage(`{"domains":["a.com"]}`)},
+		{name: "removeBadv", funcName: RemoveBadvName, params: json.RawMessage(`{"domains":["a.com"]}`)},
+		{name: "addBcat-without-categories", funcName: AddBcatName, params: json.RawMessage(`{"domains":["a.com"]}`), expectError: "addBcat requires at least one of categories to be specified"},
+		{name: "removeBadv-without-domains", funcName: RemoveBadvName, params: json.RawMessage(`{}`), expectError: "removeBadv requires at least one of domains to be specified"},
+		{name: "invalid-params", funcName: AddBadvName, params: json.RawMessage(`invalid-json`), expectError: "expect { or n"},
+		{name: "not-available-at-stage", funcName: ExcludeBiddersName, params: json.RawMessage(`{"bidders":["appnexus"]}`), expectError: "result function excludeBidders was not created"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resultFunc, err := NewBidderRequestResultFunction(tt.funcName, tt.params)
+			if tt.expectError != "" {
+				assert.ErrorContains(t, err, tt.expectError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.funcName, resultFunc.Name())
+		})
+	}
+}
+
+func TestBlockedAttributesCall(t *testing.T) {
+	tests := []struct {
+		name         string
+		funcName     string
+		args         config.BlockedAttributesParams
+		expectedBCat []string
+		expectedBAdv []string
+	}{
+		{
+			name:         "addBcat",
+			funcName:     AddBcatName,
+			args:         config.BlockedAttributesParams{Categories: []string{"IAB1", "IAB2"}},
+			expectedBCat: []string{"IAB1", "IAB3", "IAB2"},
+			expectedBAdv: []string{"a.com"},
+		},
+		{
+			name:         "removeBcat",
+			funcName:     RemoveBcatName,
+			args:         config.BlockedAttributesParams{Categories: []string{"IAB1"}},
+			expectedBCat: []string{"IAB3"},
+			expectedBAdv: []string{"a.com"},
+		},
+		{
+			name:         "addBadv",
+			funcName:     AddBadvName,
+			args:         config.BlockedAttributesParams{Domains: []string{"b.com"}},
+			expectedBCat: []string{"IAB1", "IAB3"},
+			expectedBAdv: []string{"a.com", "b.com"},
+		},
+		{
+			name:         "removeBadv",
+			funcName:     RemoveBadvName,
+			args:         config.BlockedAttributesParams{Domains: []string{"a.com"}},
+			expectedBCat: []string{"IAB1", "IAB3"},
+			expectedBAdv: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bcat := []string{"IAB1", "IAB3"}
+			result := &BidderRequestHookResult{BCat: bcat, BAdv: []string{"a.com"}}
+
+			blockedAttributes := &BlockedAttributes{name: tt.funcName, Args: tt.args}
+			err := blockedAttributes.Call(&rules.BidderRequest{}, result, rules.ResultFunctionMeta{})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedBCat, result.BCat)
+			assert.Equal(t, tt.expectedBAdv, result.BAdv)
+			assert.Equal(t, []string{"IAB1", "IAB3"}, bcat, "the request values must not be modified")
+		})
+	}
+}
+
+func TestNewBidResultFunctions(t *testing.T) {
+	tests := []struct {
+		name        string
+		factory     func(string, json.RawMessage) (BidResultFunc, error)
+		funcName    string
+		params      json.RawMessage
+		expected    BidResultFunc
+		expectError string
+	}{
+		{
+			name:     "raw-rejectBid",
+			factory:  NewRawBidderResponseResultFunction,
+			funcName: RejectBidName,
+			params:   json.RawMessage(`{"seatnonbid":301,"analyticsvalue":"low-price"}`),
+			expected: &RejectBid{Args: config.ResultFuncParams{SeatNonBid: 301, AnalyticsValue: "low-price"}},
+		},
+		{
+			name:     "raw-rejectBid-default-seatnonbid",
+			factory:  NewRawBidderResponseResultFunction,
+			funcName: RejectBidName,
+			expected: &RejectBid{Args: config.ResultFuncParams{SeatNonBid: 300}},
+		},
+		{
+			name:        "raw-setTargeting-not-available",
+			factory:     NewRawBidderResponseResultFunction,
+			funcName:    SetTargetingName,
+			params:      json.RawMessage(`{"targeting":{"hb_rule":"x"}}`),
+			expectError: "result function setTargeting was not created",
+		},
+		{
+			name:     "processed-rejectBid",
+			factory:  NewAllProcessedBidResponsesResultFunction,
+			funcName: RejectBidName,
+			params:   json.RawMessage(`{}`),
+			expected: &RejectBid{Args: config.ResultFuncParams{SeatNonBid: 300}},
+		},
+		{
+			name:     "processed-setTargeting",
+			factory:  NewAllProcessedBidResponsesResultFunction,
+			funcName: SetTargetingName,
+			params:   json.RawMessage(`{"targeting":{"hb_rule":"x"}}`),
+			expected: &SetTargeting{Args: config.TargetingParams{Targeting: map[string]string{"hb_rule": "x"}}},
+		},
+		{
+			name:        "processed-setTargeting-empty",
+			factory:     NewAllProcessedBidResponsesResultFunction,
+			funcName:    SetTargetingName,
+			params:      json.RawMessage(`{"targeting":{}}`),
+			expectError: "setTargeting requires at least one targeting key to be specified",
+		},
+		{
+			name:        "processed-setTargeting-empty-key",
+			factory:     NewAllProcessedBidResponsesResultFunction,
+			funcName:    SetTargetingName,
+			params:      json.RawMessage(`{"targeting":{"":"x"}}`),
+			expectError: "setTargeting requires non-empty targeting keys",
+		},
+		{
+			name:        "processed-rejectBid-invalid-params",
+			factory:     NewAllProcessedBidResponsesResultFunction,
+			funcName:    RejectBidName,
+			params:      json.RawMessage(`{"seatnonbid":"301"}`),
+			expectError: "cannot unmarshal",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resultFunc, err := tt.factory(tt.funcName, tt.params)
+			if tt.expectError != "" {
+				assert.ErrorContains(t, err, tt.expectError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, resultFunc)
+			assert.Equal(t, tt.funcName, resultFunc.Name())
+		})
+	}
+}
+
+func TestSetTargetingCall(t *testing.T) {
+	result := &BidHookResult{Targeting: map[string]string{"hb_a": "1", "hb_b": "1"}}
+	setTargeting := &SetTargeting{Args: config.TargetingParams{Targeting: map[string]string{"hb_b": "2", "hb_c": "2"}}}
+
+	err := setTargeting.Call(&rules.Bid{}, result, rules.ResultFunctionMeta{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"hb_a": "1", "hb_b": "2", "hb_c": "2"}, result.Targeting)
+}
+
+func TestNewImpResultFunction(t *testing.T) {
+	tests := []struct {
+		name        string
+		funcName    string
+		params      json.RawMessage
+		expected    ImpResultFunc
+		expectError string
+	}{
+		{
+			name:     "excludeBidders",
+			funcName: ExcludeBiddersName,
+			params:   json.RawMessage(`{"bidders":["bidderA"],"analyticsvalue":"video-only"}`),
+			expected: &ImpBidders{name: ExcludeBiddersName, Args: config.ResultFuncParams{Bidders: []string{"bidderA"}, AnalyticsValue: "video-only"}},
+		},
+		{
+			name:     "includeBidders",
+			funcName: IncludeBiddersName,
+			params:   json.RawMessage(`{"bidders":["bidderA"]}`),
+			expected: &ImpBidders{name: IncludeBiddersName, Args: config.ResultFuncParams{Bidders: []string{"bidderA"}}},
+		},
+		{
+			name:        "no-bidders",
+			funcName:    ExcludeBiddersName,
+			params:      json.RawMessage(`{}`),
+			expectError: "excludeBidders requires at least one bidder to be specified",
+		},
+		{
+			name:        "invalid-params",
+			funcName:    IncludeBiddersName,
+			params:      json.RawMessage(`invalid-json`),
+			expectError: "expect { or n",
+		},
+		{
+			name:        "not-available-on-imps",
+			funcName:    AddBcatName,
+			params:      json.RawMessage(`{"categories":["IAB1"]}`),
+			expectError: "result function addBcat was not created",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resultFunc, err := NewImpResultFunction(tt.funcName, tt.params)
+			if tt.expectError != "" {
+				assert.ErrorContains(t, err, tt.expectError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, resultFunc)
+			assert.Equal(t, tt.funcName, resultFunc.Name())
+		})
+	}
+}
+
+func TestImpBiddersCall(t *testing.T) {
+	imp := &rules.Imp{Imp: &openrtb_ext.ImpWrapper{Imp: &openrtb2.Imp{ID: "imp-1"}}}
+	meta := rules.ResultFunctionMeta{AnalyticsKey: "key", ModelVersion: "1.0", RuleFired: "video|*"}
+	result := &ImpHookResult{}
+
+	exclude := &ImpBidders{name: ExcludeBiddersName, Args: config.ResultFuncParams{Bidders: []string{"bidderA"}, AnalyticsValue: "video-only"}}
+	include := &ImpBidders{name: IncludeBiddersName, Args: config.ResultFuncParams{Bidders: []string{"bidderB", "bidderC"}}}
+
+	assert.NoError(t, exclude.Call(imp, result, meta))
+	assert.NoError(t, include.Call(imp, result, meta))
+
+	assert.Equal(t, map[string]struct{}{"bidderA": {}}, result.ExcludedBidders)
+	assert.Equal(t, map[string]struct{}{"bidderB": {}, "bidderC": {}}, result.AllowedBidders)
+	expectedAnalytics := []hookanalytics.Result{
+		{
+			Status: hookanalytics.ResultStatusModify,
+			Values: map[string]interface{}{
+				"analyticsKey":   "key",
+				"modelVersion":   "1.0",
+				"ruleFired":      "video|*",
+				"resultFunction": ExcludeBiddersName,
+				"bidders":        []string{"bidderA"},
+				"analyticsValue": "video-only",
+			},
+			AppliedTo: hookanalytics.AppliedTo{ImpIds: []string{"imp-1"}},
+		},
+		{
+			Status: hookanalytics.ResultStatusModify,
+			Values: map[string]interface{}{
+				"analyticsKey":   "key",
+				"modelVersion":   "1.0",
+				"ruleFired":      "video|*",
+				"resultFunction": IncludeBiddersName,
+				"bidders":        []string{"bidderB", "bidderC"},
+			},
+			AppliedTo: hookanalytics.AppliedTo{ImpIds: []string{"imp-1"}},
+		},
+	}
+	assert.Equal(t, expectedAnalytics, result.AnalyticsResults)
+}
+
+func TestNewRequestExtResultFunctions(t *testing.T) {
+	tests := []struct {
+		name        string
+		funcName    string
+		params      json.RawMessage
+		expectError string
+	}{
+		{
+			name:     "setFloors",
+			funcName: SetFloorsName,
+			params:   json.RawMessage(`{"floors":{"floormin":1,"data":{"modelgroups":[{"schema":{"fields":["mediaType"]},"values":{"banner":1.5}}]}}}`),
+		},
+		{
+			name:        "setFloors-missing-floors",
+			funcName:    SetFloorsName,
+			params:      json.RawMessage(`{}`),
+			expectError: "setFloors requires floors to be specified",
+		},
+		{
+			name:        "setFloors-invalid-floors",
+			funcName:    SetFloorsName,
+			params:      json.RawMessage(`{"floors":{"data":{"modelgroups":[{"schema":{"fields":["unknown"]}}]}}}`),
+			expectError: "setFloors floors are invalid: Invalid schema dimension provided = 'unknown' in Schema Fields = '[unknown]'",
+		},
+		{
+			name:     "setBidAdjustments",
+			funcName: SetBidAdjustmentsName,
+			params:   json.RawMessage(`{"bidadjustments":{"mediatype":{"banner":{"bidderA":{"*":[{"adjtype":"multiplier","value":0.9}]}}}}}`),
+		},
+		{
+			name:        "setBidAdjustments-missing-bidadjustments",
+			funcName:    SetBidAdjustmentsName,
+			params:      json.RawMessage(`{}`),
+			expectError: "setBidAdjustments requires bidadjustments to be specified",
+		},
+		{
+			name:        "setBidAdjustments-invalid-bidadjustments",
+			funcName:    SetBidAdjustmentsName,
+			params:      json.RawMessage(`{"bidadjustments":{"mediatype":{"banner":{"bidderA":{"*":[{"adjtype":"cpm","value":1}]}}}}}`),
+			expectError: "setBidAdjustments bidadjustments are invalid",
+		},
+		{
+			name:     "setPriceGranularity-legacy",
+			funcName: SetPriceGranularityName,
+			params:   json.RawMessage(`{"pricegranularity":"dense"}`),
+		},
+		{
+			name:     "setPriceGranularity-custom",
+			funcName: SetPriceGranularityName,
+			params:   json.RawMessage(`{"pricegranularity":{"precision":2,"ranges":[{"min":0,"max":10,"increment":0.5}]}}`),
+		},
+		{
+			name:        "setPriceGranularity-missing-pricegranularity",
+			funcName:    SetPriceGranularityName,
+			params:      json.RawMessage(`{}`),
+			expectError: "setPriceGranularity requires pricegranularity to be specified",
+		},
+		{
+			name:        "setPriceGranularity-invalid-pricegranularity",
+			funcName:    SetPriceGranularityName,
+			params:      json.RawMessage(`{"pricegranularity":{"precision":2,"ranges":[{"min":0,"max":10,"increment":0}]}}`),
+			expectError: "Price granularity error: increment must be a nonzero positive number",
+		},
+		{
+			name:     "setTargetingOptions",
+			funcName: SetTargetingOptionsName,
+			params:   json.RawMessage(`{"includewinners":true}`),
+		},
+		{
+			name:        "setTargetingOptions-no-options",
+			funcName:    SetTargetingOptionsName,
+			params:      json.RawMessage(`{}`),
+			expectError: "setTargetingOptions requires at least one targeting option to be specified",
+		},
+		{
+			name:        "setTargetingOptions-invalid-params",
+			funcName:    SetTargetingOptionsName,
+			params:      json.RawMessage(`{"includewinners":"yes"}`),
+			expectError: "cannot unmarshal",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resultFunc, err := NewProcessedAuctionRequestResultFunction(tt.funcName, tt.params)
+			if tt.expectError != "" {
+				assert.ErrorContains(t, err, tt.expectError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.funcName, resultFunc.Name())
+		})
+	}
+}
+
+func TestNewSetPriceGranularityDefaults(t *testing.T) {
+	resultFunc, err := NewSetPriceGranularity(json.RawMessage(`{"pricegranularity":{"ranges":[{"max":5,"increment":0.1},{"max":20,"increment":0.5}]}}`))
+	require.NoError(t, err)
+
+	expected := &openrtb_ext.PriceGranularity{
+		Precision: ptrutil.ToPtr(ortb.DefaultPriceGranularityPrecision),
+		Ranges: []openrtb_ext.GranularityRange{
+			{Min: 0, Max: 5, Increment: 0.1},
+			{Min: 5, Max: 20, Increment: 0.5},
+		},
+	}
+	assert.Equal(t, expected, resultFunc.(*SetPriceGranularity).Args.PriceGranularity)
+}
+
+func TestRequestExtResultFunctionsCall(t *testing.T) {
+	floorsFunc, err := NewSetFloors(json.RawMessage(`{"floors":{"floormin":1,"data":{"modelgroups":[{"schema":{"fields":["mediaType"]},"values":{"banner":1.5}}]}}}`))
+	require.NoError(t, err)
+	bidAdjustmentsFunc, err := NewSetBidAdjustments(json.RawMessage(`{"bidadjustments":{"mediatype":{"banner":{"bidderA":{"*":[{"adjtype":"multiplier","value":0.9}]}}}}}`))
+	require.NoError(t, err)
+	priceGranularityFunc, err := NewSetPriceGranularity(json.RawMessage(`{"pricegranularity":"dense"}`))
+	require.NoError(t, err)
+	targetingOptionsFunc, err := NewSetTargetingOptions(json.RawMessage(`{"includewinners":false,"preferdeals":true}`))
+	require.NoError(t, err)
+	resultFuncs := []ProcessedAuctionResultFunc{floorsFunc, bidAdjustmentsFunc, priceGranularityFunc, targetingOptionsFunc}
+
+	testCases := []struct {
+		name              string
+		requestExt        json.RawMessage
+		expectedTargeting *openrtb_ext.ExtRequestTargeting
+	}{
+		{
+			name:       "request-with-targeting",
+			requestExt: json.RawMessage(`{"prebid":{"debug":true,"targeting":{"includewinners":true,"includebidderkeys":true,"pricegranularity":"low"}}}`),
+			expectedTargeting: &openrtb_ext.ExtRequestTargeting{
+				IncludeWinners:    ptrutil.ToPtr(false),
+				IncludeBidderKeys: ptrutil.ToPtr(true),
+				PriceGranularity:  ptrutil.ToPtr(denseGranularity(t)),
+				PreferDeals:       true,
+			},
+		},
+		{
+			name:              "request-without-targeting",
+			requestExt:        nil,
+			expectedTargeting: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			payload := hs.ProcessedAuctionRequestPayload{
+				Request: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Ext: tc.requestExt}},
+			}
+			result := &ProcessedAuctionHookResult{}
+			for _, resultFunc := range resultFuncs {
+				assert.NoError(t, resultFunc.Call(payload.Request, result, rules.ResultFunctionMeta{}))
+			}
+
+			for _, mutation := range result.HookResult.ChangeSet.Mutations() {
+				payload, err = mutation.Apply(payload)
+				require.NoError(t, err)
+			}
+
+			reqExt, err := payload.Request.GetRequestExt()
+			require.NoError(t, err)
+			prebid := reqExt.GetPrebid()
+			assert.Equal(t, floorsFunc.(*SetFloors).Args.Floors, prebid.Floors)
+			assert.NotSame(t, floorsFunc.(*SetFloors).Args.Floors, prebid.Floors)
+			assert.Equal(t, bidAdjustmentsFunc.(*SetBidAdjustments).Args.BidAdjustments, prebid.BidAdjustments)
+			assert.NotSame(t, bidAdjustmentsFunc.(*SetBidAdjustments).Args.BidAdjustments, prebid.BidAdjustments)
+			assert.Equal(t, tc.expectedTargeting, prebid.Targeting)
+		})
+	}
+}
+
+func denseGranularity(t *testing.T) openrtb_ext.PriceGranularity {
+	priceGranularity, ok := openrtb_ext.NewPriceGranularityFromLegacyID("dense")
+	require.True(t, ok)
+	return priceGranularity
+}
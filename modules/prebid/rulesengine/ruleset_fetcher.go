@@ -0,0 +1,363 @@
+package rulesengine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/buger/jsonparser"
+	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
+	"github.com/prebid/prebid-server/v3/util/timeutil"
+)
+
+// rulesetVersion is a configuration fetched from the ruleset source. Its version is the version set in the
+// configuration, or the start of its hash when none is set.
+type rulesetVersion struct {
+	version   string
+	hash      hash
+	fetchedAt time.Time
+	config    json.RawMessage
+}
+
+// rulesetHistory holds the last versions fetched for an account, oldest first. The pinned version, if any, is
+// the active one in place of the latest.
+type rulesetHistory struct {
+	versions []rulesetVersion
+	pinned   string
+
+	// lastUsed is the unix time in nanoseconds the module last ran for the account
+	lastUsed    atomic.Int64
+	lastFetched time.Time
+	notFound    bool
+}
+
+func newRulesetHistory(now time.Time) *rulesetHistory {
+	history := &rulesetHistory{}
+	history.lastUsed.Store(now.UnixNano())
+	return history
+}
+
+func (h *rulesetHistory) active() *rulesetVersion {
+	if len(h.versions) == 0 {
+		return nil
+	}
+	if h.pinned != "" {
+		for i := range h.versions {
+			if h.versions[i].version == h.pinned {
+				return &h.versions[i]
+			}
+		}
+	}
+	return &h.versions[len(h.versions)-1]
+}
+
+// add appends the version unless it is the latest one already, dropping the oldest versions other than the
+// pinned one beyond maxVersions. A version fetched again after a newer one is moved to the end. A version named
+// like another one kept with a different configuration is rejected, as it can't be told apart when pinned.
+func (h *rulesetHistory) add(v rulesetVersion, maxVersions int) error {
+	if n := len(h.versions); n > 0 && h.versions[n-1].hash == v.hash {
+		return nil
+	}
+	for i, kept := range h.versions {
+		if kept.version != v.version {
+			continue
+		}
+		if kept.hash != v.hash {
+			return fmt.Errorf("ruleset version %s is already in use by another configuration", v.version)
+		}
+		h.versions = append(h.versions[:i], h.versions[i+1:]...)
+		break
+	}
+	h.versions = append(h.versions, v)
+
+	for i := 0; len(h.versions) > maxVersions && i < len(h.versions); {
+		if h.versions[i].version == h.pinned {
+			i++
+			continue
+		}
+		h.versions = append(h.versions[:i], h.versions[i+1:]...)
+	}
+	return nil
+}
+
+func (h *rulesetHistory) info() []RulesetVersionInfo {
+	active := h.active()
+	info := make([]RulesetVersionInfo, 0, len(h.versions))
+	for i, v := range h.versions {
+		info = append(info, RulesetVersionInfo{
+			Version:   v.version,
+			FetchedAt: v.fetchedAt,
+			Active:    &h.versions[i] == active,
+			Pinned:    v.version == h.pinned,
+		})
+	}
+	return info
+}
+
+// RulesetVersionInfo describes a version of the rules engine configuration of an account.
+type RulesetVersionInfo struct {
+	Version   string    `json:"version"`
+	FetchedAt time.Time `json:"fetchedat"`
+	Active    bool      `json:"active"`
+	Pinned    bool      `json:"pinned"`
+}
+
+// rulesetFetcher fetches the rules engine configurations of the accounts from a ruleset source in the background,
+// like the price floors fetcher does. An account is fetched once the module first runs for it, and refetched
+// periodically afterwards, at most maxRefreshes accounts at a time. The accounts the module has not run for
+// within the idle timeout and the accounts without a ruleset are dropped on refresh, the latter being fetched
+// again the next time the module runs for them. At most maxAccounts accounts are kept, the one the module has
+// run for the longest ago being dropped to make room for a new one.
+type rulesetFetcher struct {
+	source        rulesetSource
+	timeout       time.Duration
+	refreshPeriod time.Duration
+	idleTimeout   time.Duration
+	maxVersions   int
+	maxRefreshes  int
+	maxAccounts   int
+	monitor       RulesEngineObserver
+	time          timeutil.Time
+
+	mu       sync.RWMutex
+	accounts map[accountID]*rulesetHistory
+
+	requests chan accountID
+	done     chan struct{}
+}
+
+func newRulesetFetcher(cfg *config.RulesetSource, source rulesetSource, monitor RulesEngineObserver) *rulesetFetcher {
+	return &rulesetFetcher{
+		source:        source,
+		timeout:       time.Duration(cfg.TimeoutMS) * time.Millisecond,
+		refreshPeriod: time.Duration(cfg.RefreshRateSeconds) * time.Second,
+		idleTimeout:   time.Duration(cfg.IdleTimeoutSeconds) * time.Second,
+		maxVersions:   cfg.MaxVersions,
+		maxRefreshes:  cfg.MaxRefreshes,
+		maxAccounts:   cfg.MaxAccounts,
+		monitor:       monitor,
+		time:          &timeutil.RealTime{},
+		accounts:      make(map[accountID]*rulesetHistory),
+		requests:      make(chan accountID, 100),
+		done:          make(chan struct{}),
+	}
+}
+
+// Active returns the active version of the account configuration, or nil while none has been fetched. The first
+// call for an account queues its fetch.
+func (f *rulesetFetcher) Active(id accountID) *rulesetVersion {
+	f.mu.RLock()
+	history, known := f.accounts[id]
+	var active *rulesetVersion
+	if known {
+		history.lastUsed.Store(f.time.Now().UnixNano())
+		if v := history.active(); v != nil {
+			activeCopy := *v
+			active = &activeCopy
+		}
+	}
+	f.mu.RUnlock()
+
+	if !known {
+		f.mu.Lock()
+		if _, known = f.accounts[id]; !known && f.makeRoom() {
+			f.accounts[id] = newRulesetHistory(f.time.Now())
+			// accounts not queued when the channel is full are fetched with the next refresh
+			select {
+			case f.requests <- id:
+			default:
+			}
+		}
+		f.mu.Unlock()
+	}
+	return active
+}
+
+// makeRoom drops the unpinned account the module has run for the longest ago when maxAccounts are kept,
+// returning false when they are all pinned. It must be called with the lock held.
+func (f *rulesetFetcher) makeRoom() bool {
+	if len(f.accounts) < f.maxAccounts {
+		return true
+	}
+
+	var evicted accountID
+	var evictedLastUsed int64
+	found := false
+	for id, history := range f.accounts {
+		if history.pinned != "" {
+			continue
+		}
+		if lastUsed := history.lastUsed.Load(); !found || lastUsed < evictedLastUsed {
+			evicted, evictedLastUsed, found = id, lastUsed, true
+		}
+	}
+	if found {
+		delete(f.accounts, evicted)
+	}
+	return found
+}
+
+// Run fetches the queued accounts as they come and refreshes the known accounts each refresh period.
+func (f *rulesetFetcher) Run() {
+	ticker := time.NewTicker(f.refreshPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case id := <-f.requests:
+			f.fetch(id)
+		case <-ticker.C:
+			for _, id := range f.refreshable() {
+				f.fetch(id)
+			}
+		case <-f.done:
+			f.monitor.logInfo("Rules engine ruleset fetcher shutting down")
+			return
+		}
+	}
+}
+
+// Shutdown signals the fetcher to stop fetching
+func (f *rulesetFetcher) Shutdown() {
+	close(f.done)
+}
+
+// refreshable drops the idle and not found accounts, returning the accounts to refetch, the ones fetched the
+// longest ago first. Pinned accounts are kept until unpinned.
+func (f *rulesetFetcher) refreshable() []accountID {
+	now := f.time.Now()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ids := make([]accountID, 0, len(f.accounts))
+	for id, history := range f.accounts {
+		idle := now.Sub(time.Unix(0, history.lastUsed.Load())) > f.idleTimeout
+		if history.pinned == "" && (idle || history.notFound) {
+			delete(f.accounts, id)
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		return f.accounts[ids[i]].lastFetched.Before(f.accounts[ids[j]].lastFetched)
+	})
+	if len(ids) > f.maxRefreshes {
+		ids = ids[:f.maxRefreshes]
+	}
+	return ids
+}
+
+func (f *rulesetFetcher) fetch(id accountID) {
+	ctx, cancel := context.WithTimeout(context.Background(), f.timeout)
+	defer cancel()
+
+	data, err := f.source.Fetch(ctx, id)
+	if err != nil {
+		notFound := errors.Is(err, errRulesetNotFound)
+		if !notFound {
+			f.monitor.logError(fmt.Sprintf("Rules engine error fetching the ruleset of account %s: %v", id, err))
+		}
+		f.mu.Lock()
+		if history, ok := f.accounts[id]; ok {
+			history.lastFetched = f.time.Now()
+			history.notFound = notFound && len(history.versions) == 0
+		}
+		f.mu.Unlock()
+		return
+	}
+
+	fetched := rulesetVersion{
+		hash:      hashConfig(&data),
+		fetchedAt: f.time.Now(),
+		config:    data,
+	}
+	fetched.version, _ = jsonparser.GetString(data, "version")
+	if fetched.version == "" {
+		fetched.version = fetched.hash[:12]
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	history, ok := f.accounts[id]
+	if !ok {
+		if !f.makeRoom() {
+			return
+		}
+		history = newRulesetHistory(fetched.fetchedAt)
+		f.accounts[id] = history
+	}
+	history.lastFetched = fetched.fetchedAt
+	history.notFound = false
+	if err := history.add(fetched, f.maxVersions); err != nil {
+		f.monitor.logError(fmt.Sprintf("Rules engine error loading the ruleset of account %s: %v", id, err))
+	}
+}
+
+// Versions returns the versions kept for the account, oldest first.
+func (f *rulesetFetcher) Versions(id accountID) []RulesetVersionInfo {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	history, ok := f.accounts[id]
+	if !ok {
+		return []RulesetVersionInfo{}
+	}
+	return history.info()
+}
+
+// Pin makes the version the active one until unpinned, whatever the versions fetched afterwards.
+func (f *rulesetFetcher) Pin(id accountID, version string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	history, ok := f.accounts[id]
+	if !ok {
+		return fmt.Errorf("no ruleset versions for account %s", id)
+	}
+	for _, v := range history.versions {
+		if v.version == version {
+			history.pinned = version
+			return nil
+		}
+	}
+	return fmt.Errorf("ruleset version %s of account %s is not available", version, id)
+}
+
+// Unpin makes the latest version fetched the active one again.
+func (f *rulesetFetcher) Unpin(id accountID) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if history, ok := f.accounts[id]; ok {
+		history.pinned = ""
+	}
+}
+
+// Rollback pins the version preceding the active one, returning it.
+func (f *rulesetFetcher) Rollback(id accountID) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	history, ok := f.accounts[id]
+	if !ok || len(history.versions) == 0 {
+		return "", fmt.Errorf("no ruleset versions for account %s", id)
+	}
+	active := history.active()
+	for i := range history.versions {
+		if &history.versions[i] == active {
+			if i == 0 {
+				return "", fmt.Errorf("no ruleset version of account %s precedes version %s", id, active.version)
+			}
+			history.pinned = history.versions[i-1].version
+			return history.pinned, nil
+		}
+	}
+	return "", fmt.Errorf("no ruleset versions for account %s", id)
+}
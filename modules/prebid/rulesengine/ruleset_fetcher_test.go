@@ -0,0 +1,247 @@
+package rulesengine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeRulesetSource struct {
+	rulesets map[string]json.RawMessage
+	err      error
+}
+
+func (s *fakeRulesetSource) Fetch(_ context.Context, accountID string) (json.RawMessage, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	ruleset, ok := s.rulesets[accountID]
+	if !ok {
+		return nil, errRulesetNotFound
+	}
+	return ruleset, nil
+}
+
+type fakeRulesEngineObserver struct {
+	mu     sync.Mutex
+	errors []string
+}
+
+func (o *fakeRulesEngineObserver) logError(msg string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.errors = append(o.errors, msg)
+}
+
+func (o *fakeRulesEngineObserver) logInfo(msg string) {}
+
+type fakeTime struct {
+	time time.Time
+}
+
+func (t *fakeTime) Now() time.Time {
+	return t.time
+}
+
+func newTestRulesetFetcher(source rulesetSource, maxVersions int) *rulesetFetcher {
+	fetcher := newRulesetFetcher(&config.RulesetSource{TimeoutMS: 100, RefreshRateSeconds: 60, MaxVersions: maxVersions, MaxRefreshes: 2, IdleTimeoutSeconds: 3600, MaxAccounts: 10}, source, &fakeRulesEngineObserver{})
+	fetcher.time = &fakeTime{time: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	return fetcher
+}
+
+func versionNames(versions []RulesetVersionInfo) []string {
+	names := make([]string, 0, len(versions))
+	for _, v := range versions {
+		names = append(names, v.Version)
+	}
+	return names
+}
+
+func TestRulesetFetcherActive(t *testing.T) {
+	ruleset := json.RawMessage(`{"enabled":true,"version":"v1"}`)
+	source := &fakeRulesetSource{rulesets: map[string]json.RawMessage{"account-1": ruleset}}
+	fetcher := newTestRulesetFetcher(source, 3)
+
+	assert.Nil(t, fetcher.Active("account-1"), "nothing is active before the first fetch")
+	assert.Equal(t, "account-1", <-fetcher.requests, "the first call queues the fetch")
+	assert.Nil(t, fetcher.Active("account-1"))
+	assert.Empty(t, fetcher.requests, "the fetch is queued once")
+
+	fetcher.fetch("account-1")
+
+	active := fetcher.Active("account-1")
+	if assert.NotNil(t, active) {
+		assert.Equal(t, "v1", active.version)
+		assert.Equal(t, hashConfig(&ruleset), active.hash)
+		assert.JSONEq(t, `{"enabled":true,"version":"v1"}`, string(active.config))
+	}
+}
+
+func TestRulesetFetcherFetch(t *testing.T) {
+	source := &fakeRulesetSource{rulesets: map[string]json.RawMessage{}}
+	fetcher := newTestRulesetFetcher(source, 3)
+
+	for _, ruleset := range []string{
+		`{"enabled":true,"version":"v1"}`,
+		`{"enabled":true,"version":"v1"}`,
+		`{"enabled":true,"version":"v2"}`,
+		`{"enabled":true}`,
+		`{"enabled":false,"version":"v4"}`,
+	} {
+		source.rulesets["account-1"] = json.RawMessage(ruleset)
+		fetcher.fetch("account-1")
+	}
+
+	unversioned := json.RawMessage(`{"enabled":true}`)
+	assert.Equal(t, []string{"v2", hashConfig(&unversioned)[:12], "v4"}, versionNames(fetcher.Versions("account-1")),
+		"an unchanged ruleset is kept once and only the last versions are kept")
+
+	fetcher.fetch("account-2")
+	assert.Empty(t, fetcher.Versions("account-2"), "an account missing from the source has no versions")
+
+	source.err = errors.New("source unavailable")
+	fetcher.fetch("account-1")
+	assert.Equal(t, "v4", fetcher.Active("account-1").version, "a failed fetch keeps the active version")
+	assert.Equal(t, []string{"Rules engine error fetching the ruleset of account account-1: source unavailable"},
+		fetcher.monitor.(*fakeRulesEngineObserver).errors)
+}
+
+func TestRulesetFetcherDuplicateVersions(t *testing.T) {
+	source := &fakeRulesetSource{rulesets: map[string]json.RawMessage{}}
+	fetcher := newTestRulesetFetcher(source, 3)
+
+	for _, ruleset := range []string{
+		`{"enabled":true,"version":"v1"}`,
+		`{"enabled":true,"version":"v2"}`,
+		`{"enabled":false,"version":"v1"}`,
+	} {
+		source.rulesets["account-1"] = json.RawMessage(ruleset)
+		fetcher.fetch("account-1")
+	}
+	assert.Equal(t, []string{"v1", "v2"}, versionNames(fetcher.Versions("account-1")), "a version named like another one is rejected")
+	assert.Equal(t, "v2", fetcher.Active("account-1").version)
+	assert.Equal(t, []string{"Rules engine error loading the ruleset of account account-1: ruleset version v1 is already in use by another configuration"},
+		fetcher.monitor.(*fakeRulesEngineObserver).errors)
+
+	source.rulesets["account-1"] = json.RawMessage(`{"enabled":true,"version":"v1"}`)
+	fetcher.fetch("account-1")
+	assert.Equal(t, []string{"v2", "v1"}, versionNames(fetcher.Versions("account-1")), "a version fetched again becomes the latest")
+	assert.Equal(t, "v1", fetcher.Active("account-1").version)
+}
+
+func TestRulesetFetcherMaxAccounts(t *testing.T) {
+	ruleset := json.RawMessage(`{"enabled":true,"version":"v1"}`)
+	source := &fakeRulesetSource{rulesets: map[string]json.RawMessage{"account-1": ruleset, "account-2": ruleset, "account-3": ruleset}}
+	fetcher := newTestRulesetFetcher(source, 3)
+	fetcher.maxAccounts = 2
+	clock := fetcher.time.(*fakeTime)
+
+	for _, id := range []accountID{"account-1", "account-2"} {
+		fetcher.Active(id)
+		fetcher.fetch(id)
+		clock.time = clock.time.Add(time.Second)
+	}
+	assert.NoError(t, fetcher.Pin("account-1", "v1"))
+
+	fetcher.Active("account-3")
+	assert.Len(t, fetcher.accounts, 2)
+	assert.Contains(t, fetcher.accounts, "account-1", "pinned accounts are kept")
+	assert.NotContains(t, fetcher.accounts, "account-2", "the account the module ran for the longest ago is dropped")
+	assert.Contains(t, fetcher.accounts, "account-3")
+
+	fetcher.fetch("account-3")
+	assert.NoError(t, fetcher.Pin("account-3", "v1"))
+	assert.Nil(t, fetcher.Active("account-4"))
+	assert.NotContains(t, fetcher.accounts, "account-4", "no account is dropped while they are all pinned")
+}
+
+func TestRulesetFetcherRefreshable(t *testing.T) {
+	ruleset := json.RawMessage(`{"enabled":true,"version":"v1"}`)
+	source := &fakeRulesetSource{rulesets: map[string]json.RawMessage{
+		"account-1": ruleset, "account-2": ruleset, "account-3": ruleset, "idle": ruleset, "pinned": ruleset,
+	}}
+	fetcher := newTestRulesetFetcher(source, 3)
+	clock := fetcher.time.(*fakeTime)
+	advance := func(d time.Duration) {
+		clock.time = clock.time.Add(d)
+	}
+
+	for _, id := range []string{"account-3", "account-1", "account-2", "missing", "idle", "pinned"} {
+		fetcher.Active(id)
+		fetcher.fetch(id)
+		advance(time.Second)
+	}
+	assert.NoError(t, fetcher.Pin("pinned", "v1"))
+
+	advance(30 * time.Minute)
+	for _, id := range []string{"account-1", "account-2", "account-3", "missing"} {
+		fetcher.Active(id)
+	}
+	advance(31 * time.Minute)
+
+	assert.Equal(t, []accountID{"account-3", "account-1"}, fetcher.refreshable(), "the accounts fetched the longest ago are refreshed first")
+	assert.NotContains(t, fetcher.accounts, "missing", "accounts without a ruleset are dropped")
+	assert.NotContains(t, fetcher.accounts, "idle", "idle accounts are dropped")
+	assert.Contains(t, fetcher.accounts, "pinned", "pinned accounts are kept")
+
+	fetcher.fetch("account-3")
+	fetcher.fetch("account-1")
+	assert.Equal(t, []accountID{"account-2", "pinned"}, fetcher.refreshable(), "the other accounts are refreshed next")
+}
+
+func TestRulesetFetcherPinAndRollback(t *testing.T) {
+	source := &fakeRulesetSource{rulesets: map[string]json.RawMessage{}}
+	fetcher := newTestRulesetFetcher(source, 2)
+	fetchVersion := func(version string) {
+		source.rulesets["account-1"] = json.RawMessage(`{"enabled":true,"version":"` + version + `"}`)
+		fetcher.fetch("account-1")
+	}
+
+	_, err := fetcher.Rollback("account-1")
+	assert.EqualError(t, err, "no ruleset versions for account account-1")
+
+	fetchVersion("v1")
+	_, err = fetcher.Rollback("account-1")
+	assert.EqualError(t, err, "no ruleset version of account account-1 precedes version v1")
+
+	fetchVersion("v2")
+	version, err := fetcher.Rollback("account-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1", version)
+	assert.Equal(t, "v1", fetcher.Active("account-1").version)
+
+	fetchVersion("v3")
+	assert.Equal(t, "v1", fetcher.Active("account-1").version, "the pinned version stays active")
+	assert.Equal(t, []string{"v1", "v3"}, versionNames(fetcher.Versions("account-1")), "the pinned version is kept")
+
+	assert.EqualError(t, fetcher.Pin("account-1", "v2"), "ruleset version v2 of account account-1 is not available")
+	assert.EqualError(t, fetcher.Pin("account-2", "v2"), "no ruleset versions for account account-2")
+	assert.NoError(t, fetcher.Pin("account-1", "v3"))
+	assert.Equal(t, []RulesetVersionInfo{
+		{Version: "v1", FetchedAt: fetcher.time.Now()},
+		{Version: "v3", FetchedAt: fetcher.time.Now(), Active: true, Pinned: true},
+	}, fetcher.Versions("account-1"))
+
+	fetcher.Unpin("account-1")
+	fetchVersion("v4")
+	assert.Equal(t, "v4", fetcher.Active("account-1").version)
+	assert.Equal(t, []string{"v3", "v4"}, versionNames(fetcher.Versions("account-1")))
+}
+
+func TestRulesetFetcherRun(t *testing.T) {
+	source := &fakeRulesetSource{rulesets: map[string]json.RawMessage{"account-1": json.RawMessage(`{"enabled":true,"version":"v1"}`)}}
+	fetcher := newTestRulesetFetcher(source, 3)
+	go fetcher.Run()
+	defer fetcher.Shutdown()
+
+	fetcher.Active("account-1")
+	assert.Eventually(t, func() bool {
+		return fetcher.Active("account-1") != nil
+	}, time.Second, 10*time.Millisecond)
+}
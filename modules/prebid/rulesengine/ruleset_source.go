@@ -0,0 +1,123 @@
+package rulesengine
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	prebidConfig "github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
+	"github.com/prebid/prebid-server/v3/stored_requests/backends/db_provider"
+)
+
+// errRulesetNotFound is returned by a ruleset source holding no configuration for the account, in which case the
+// configuration of the account is used.
+var errRulesetNotFound = errors.New("no rules engine configuration found for the account")
+
+// rulesetSource fetches the rules engine configuration of an account from outside the account configuration.
+type rulesetSource interface {
+	Fetch(ctx context.Context, accountID string) (json.RawMessage, error)
+}
+
+func newRulesetSource(cfg *config.RulesetSource, httpClient *http.Client) rulesetSource {
+	switch cfg.Type {
+	case config.SourceTypeFile:
+		return &fileRulesetSource{path: cfg.Path}
+	case config.SourceTypeHTTP:
+		if httpClient == nil {
+			httpClient = http.DefaultClient
+		}
+		return &httpRulesetSource{url: cfg.URL, client: httpClient}
+	default:
+		provider := db_provider.NewDbProvider(prebidConfig.AccountDataType, cfg.Database.ConnectionInfo())
+		return &dbRulesetSource{provider: provider, query: cfg.Database.Query}
+	}
+}
+
+// fileRulesetSource reads the configuration of an account from {path}/{account id}.json.
+type fileRulesetSource struct {
+	path string
+}
+
+func (s *fileRulesetSource) Fetch(_ context.Context, accountID string) (json.RawMessage, error) {
+	if accountID == "" || filepath.Base(accountID) != accountID {
+		return nil, errRulesetNotFound
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.path, accountID+".json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, errRulesetNotFound
+	}
+	return data, err
+}
+
+// httpRulesetSource requests the configuration of an account from the url with the account query parameter.
+type httpRulesetSource struct {
+	url    string
+	client *http.Client
+}
+
+func (s *httpRulesetSource) Fetch(ctx context.Context, accountID string) (json.RawMessage, error) {
+	sourceURL, err := url.Parse(s.url)
+	if err != nil {
+		return nil, err
+	}
+	query := sourceURL.Query()
+	query.Set("account", accountID)
+	sourceURL.RawQuery = query.Encode()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, sourceURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	httpResp, err := s.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	switch httpResp.StatusCode {
+	case http.StatusOK:
+		return io.ReadAll(httpResp.Body)
+	case http.StatusNotFound:
+		return nil, errRulesetNotFound
+	default:
+		return nil, fmt.Errorf("unexpected status code %d from %s", httpResp.StatusCode, s.url)
+	}
+}
+
+// dbRulesetSource selects the configuration of an account with the query, whose $ACCOUNT parameter is replaced
+// with the account id.
+type dbRulesetSource struct {
+	provider db_provider.DbProvider
+	query    string
+}
+
+func (s *dbRulesetSource) Fetch(ctx context.Context, accountID string) (json.RawMessage, error) {
+	rows, err := s.provider.QueryContext(ctx, s.query, db_provider.QueryParam{Name: "ACCOUNT", Value: accountID})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errRulesetNotFound
+	}
+
+	var data sql.RawBytes
+	if err := rows.Scan(&data); err != nil {
+		return nil, err
+	}
+	return json.RawMessage(append([]byte(nil), data...)), nil
+}
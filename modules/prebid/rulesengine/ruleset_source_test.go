@@ -0,0 +1,78 @@
+package rulesengine
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prebid/prebid-server/v3/stored_requests/backends/db_provider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileRulesetSource(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "account-1.json"), []byte(`{"enabled":true}`), 0644))
+	source := &fileRulesetSource{path: dir}
+
+	data, err := source.Fetch(context.Background(), "account-1")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"enabled":true}`, string(data))
+
+	_, err = source.Fetch(context.Background(), "account-2")
+	assert.ErrorIs(t, err, errRulesetNotFound)
+
+	_, err = source.Fetch(context.Background(), "../account-1")
+	assert.ErrorIs(t, err, errRulesetNotFound)
+}
+
+func TestHTTPRulesetSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("account") {
+		case "account-1":
+			w.Write([]byte(`{"enabled":true}`))
+		case "account-2":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+	source := &httpRulesetSource{url: server.URL + "/rulesets?env=prod", client: server.Client()}
+
+	data, err := source.Fetch(context.Background(), "account-1")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"enabled":true}`, string(data))
+
+	_, err = source.Fetch(context.Background(), "account-2")
+	assert.ErrorIs(t, err, errRulesetNotFound)
+
+	_, err = source.Fetch(context.Background(), "account-3")
+	assert.ErrorContains(t, err, "unexpected status code 500")
+}
+
+func TestDbRulesetSource(t *testing.T) {
+	const query = "SELECT config FROM rules_engine_configs WHERE account_id = $ACCOUNT"
+
+	provider, mock, err := db_provider.NewDbProviderMock()
+	require.NoError(t, err)
+	source := &dbRulesetSource{provider: provider, query: query}
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("account-1").
+		WillReturnRows(sqlmock.NewRows([]string{"config"}).AddRow(`{"enabled":true}`))
+	data, err := source.Fetch(context.Background(), "account-1")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"enabled":true}`, string(data))
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("account-2").
+		WillReturnRows(sqlmock.NewRows([]string{"config"}))
+	_, err = source.Fetch(context.Background(), "account-2")
+	assert.ErrorIs(t, err, errRulesetNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,67 @@
+package rulesengine
+
+import (
+	"net/http"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// rulesetVersionsPath is the admin endpoint listing, pinning and rolling back the ruleset versions of an account.
+const rulesetVersionsPath = "/rules/versions"
+
+type rulesetVersionsResponse struct {
+	Account  string               `json:"account"`
+	Versions []RulesetVersionInfo `json:"versions"`
+}
+
+// newRulesetVersionsEndpoint lists the ruleset versions kept for the account given by the account query parameter.
+// On POST, the action query parameter pins the version given by the version query parameter, rolls back to the
+// version preceding the active one, or unpins to follow the latest version again.
+func newRulesetVersionsEndpoint(fetcher *rulesetFetcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		account := query.Get("account")
+		if account == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("Missing account query parameter"))
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPost:
+			var err error
+			switch action := query.Get("action"); action {
+			case "pin":
+				err = fetcher.Pin(account, query.Get("version"))
+			case "rollback":
+				_, err = fetcher.Rollback(account)
+			case "unpin":
+				fetcher.Unpin(account)
+			default:
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte("The action query parameter must be one of pin, rollback or unpin"))
+				return
+			}
+			if err != nil {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				w.Write([]byte(err.Error()))
+				return
+			}
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		jsonOutput, err := jsonutil.Marshal(rulesetVersionsResponse{Account: account, Versions: fetcher.Versions(account)})
+		if err != nil {
+			glog.Errorf("%s Critical error when trying to marshal the response: %v", rulesetVersionsPath, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(jsonOutput)
+	}
+}
@@ -0,0 +1,95 @@
+package rulesengine
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRulesetVersionsEndpoint(t *testing.T) {
+	testCases := []struct {
+		description  string
+		method       string
+		query        string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			description:  "missing-account",
+			method:       http.MethodGet,
+			query:        "",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Missing account query parameter",
+		},
+		{
+			description:  "list",
+			method:       http.MethodGet,
+			query:        "account=account-1",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"account":"account-1","versions":[{"version":"v1","fetchedat":"2026-01-01T00:00:00Z","active":false,"pinned":false},{"version":"v2","fetchedat":"2026-01-01T00:00:00Z","active":true,"pinned":false}]}`,
+		},
+		{
+			description:  "pin",
+			method:       http.MethodPost,
+			query:        "account=account-1&action=pin&version=v1",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"account":"account-1","versions":[{"version":"v1","fetchedat":"2026-01-01T00:00:00Z","active":true,"pinned":true},{"version":"v2","fetchedat":"2026-01-01T00:00:00Z","active":false,"pinned":false}]}`,
+		},
+		{
+			description:  "pin-unknown-version",
+			method:       http.MethodPost,
+			query:        "account=account-1&action=pin&version=v3",
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: "ruleset version v3 of account account-1 is not available",
+		},
+		{
+			description:  "rollback",
+			method:       http.MethodPost,
+			query:        "account=account-1&action=rollback",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"account":"account-1","versions":[{"version":"v1","fetchedat":"2026-01-01T00:00:00Z","active":true,"pinned":true},{"version":"v2","fetchedat":"2026-01-01T00:00:00Z","active":false,"pinned":false}]}`,
+		},
+		{
+			description:  "unpin",
+			method:       http.MethodPost,
+			query:        "account=account-1&action=unpin",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"account":"account-1","versions":[{"version":"v1","fetchedat":"2026-01-01T00:00:00Z","active":false,"pinned":false},{"version":"v2","fetchedat":"2026-01-01T00:00:00Z","active":true,"pinned":false}]}`,
+		},
+		{
+			description:  "unknown-action",
+			method:       http.MethodPost,
+			query:        "account=account-1&action=delete",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "The action query parameter must be one of pin, rollback or unpin",
+		},
+		{
+			description:  "delete",
+			method:       http.MethodDelete,
+			query:        "account=account-1",
+			expectedCode: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			source := &fakeRulesetSource{rulesets: map[string]json.RawMessage{}}
+			fetcher := newTestRulesetFetcher(source, 3)
+			for _, ruleset := range []string{`{"version":"v1"}`, `{"version":"v2"}`} {
+				source.rulesets["account-1"] = json.RawMessage(ruleset)
+				fetcher.fetch("account-1")
+			}
+
+			req := httptest.NewRequest(tc.method, rulesetVersionsPath+"?"+tc.query, nil)
+			w := httptest.NewRecorder()
+
+			newRulesetVersionsEndpoint(fetcher)(w, req)
+
+			assert.Equal(t, tc.expectedCode, w.Code)
+			assert.Equal(t, tc.expectedBody, w.Body.String())
+		})
+	}
+}
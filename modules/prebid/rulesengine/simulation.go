@@ -0,0 +1,259 @@
+package rulesengine
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/hooks"
+	hs "github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/rules"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// SimulationReport describes how the rulesets of a rules engine configuration evaluate a batch of sample requests.
+type SimulationReport struct {
+	Requests int                 `json:"requests"`
+	RuleSets []RuleSetSimulation `json:"rulesets"`
+	Errors   []string            `json:"errors,omitempty"`
+}
+
+// RuleSetSimulation holds the evaluations of every model group of a ruleset. Rulesets of the stages that need
+// bidder requests or bids are not simulated and carry the reason they were skipped instead.
+type RuleSetSimulation struct {
+	Name        string                 `json:"name"`
+	Stage       hooks.Stage            `json:"stage"`
+	Scope       string                 `json:"scope,omitempty"`
+	ModelGroups []ModelGroupSimulation `json:"modelgroups,omitempty"`
+	Skipped     string                 `json:"skipped,omitempty"`
+}
+
+// ModelGroupSimulation holds the evaluations of a model group. Every model group is evaluated on every sample
+// request regardless of its weight, so model groups can be compared against each other.
+type ModelGroupSimulation struct {
+	Version      string             `json:"version,omitempty"`
+	AnalyticsKey string             `json:"analyticskey,omitempty"`
+	Weight       int                `json:"weight,omitempty"`
+	Results      []SimulationResult `json:"results"`
+	Summary      SimulationSummary  `json:"summary"`
+}
+
+// SimulationResult is the evaluation of a model group on a sample request, or on one of its imps for imp scoped
+// rulesets. Bidders are the bidders left on the request or imp once the result functions have been applied.
+type SimulationResult struct {
+	RequestID             string                 `json:"requestid"`
+	ImpID                 string                 `json:"impid,omitempty"`
+	RuleFired             string                 `json:"rulefired,omitempty"`
+	SchemaFunctionResults []SchemaFunctionResult `json:"schemafunctionresults,omitempty"`
+	Bidders               []string               `json:"bidders"`
+	Error                 string                 `json:"error,omitempty"`
+}
+
+// SchemaFunctionResult is the value a schema function returned while walking the tree.
+type SchemaFunctionResult struct {
+	Function string `json:"function"`
+	Value    string `json:"value"`
+}
+
+// SimulationSummary counts how often each rule fired and each bidder was kept across the evaluations of a
+// model group. Evaluations matching no rule are counted under the "default" rule.
+type SimulationSummary struct {
+	Evaluations int            `json:"evaluations"`
+	RulesFired  map[string]int `json:"rulesfired"`
+	Bidders     map[string]int `json:"bidders"`
+	Errors      int            `json:"errors"`
+}
+
+// Simulate runs the sample requests through the processed auction request rulesets of the configuration without
+// running any auction. The bidders of the sample requests are read from imp.ext.prebid.bidder, which is where the
+// processed auction request carries them.
+func Simulate(cfg *config.PbRulesEngine, requests []json.RawMessage) SimulationReport {
+	report := SimulationReport{RuleSets: []RuleSetSimulation{}}
+
+	samples := make([]json.RawMessage, 0, len(requests))
+	for i, request := range requests {
+		var bidRequest openrtb2.BidRequest
+		if err := jsonutil.Unmarshal(request, &bidRequest); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("request %d is invalid: %s", i, err))
+			continue
+		}
+		samples = append(samples, request)
+	}
+	report.Requests = len(samples)
+
+	if cfg == nil {
+		return report
+	}
+
+	for i := range cfg.RuleSets {
+		ruleSet := &cfg.RuleSets[i]
+		simulation := RuleSetSimulation{
+			Name:  ruleSet.Name,
+			Stage: ruleSet.Stage,
+			Scope: ruleSet.Scope,
+		}
+
+		switch {
+		case ruleSet.Stage != hooks.StageProcessedAuctionRequest:
+			simulation.Skipped = fmt.Sprintf("only %s rulesets can be simulated", hooks.StageProcessedAuctionRequest)
+		case ruleSet.Scope == config.ScopeImp:
+			crs, err := newCacheRuleSet(ruleSet, rules.NewImpSchemaFunction, NewImpResultFunction)
+			if err != nil {
+				simulation.Skipped = err.Error()
+				break
+			}
+			for _, modelGroup := range crs.modelGroups {
+				simulation.ModelGroups = append(simulation.ModelGroups, simulateModelGroup(modelGroup, samples, simulateImps))
+			}
+		default:
+			crs, err := createCacheRuleSet(ruleSet)
+			if err != nil {
+				simulation.Skipped = err.Error()
+				break
+			}
+			for _, modelGroup := range crs.modelGroups {
+				simulation.ModelGroups = append(simulation.ModelGroups, simulateModelGroup(modelGroup, samples, simulateRequest))
+			}
+		}
+
+		report.RuleSets = append(report.RuleSets, simulation)
+	}
+
+	return report
+}
+
+// simulateModelGroup evaluates the model group on every sample, each sample being parsed again so that the
+// changes made by one evaluation are not seen by the next.
+func simulateModelGroup[T1 any, T2 any](
+	modelGroup cacheModelGroup[T1, T2],
+	samples []json.RawMessage,
+	simulate func(*rules.Tree[T1, T2], *openrtb_ext.RequestWrapper) []SimulationResult) ModelGroupSimulation {
+
+	simulation := ModelGroupSimulation{
+		Version:      modelGroup.version,
+		AnalyticsKey: modelGroup.analyticsKey,
+		Weight:       modelGroup.weight,
+		Results:      []SimulationResult{},
+		Summary: SimulationSummary{
+			RulesFired: make(map[string]int),
+			Bidders:    make(map[string]int),
+		},
+	}
+
+	for _, sample := range samples {
+		var bidRequest openrtb2.BidRequest
+		if err := jsonutil.Unmarshal(sample, &bidRequest); err != nil {
+			continue
+		}
+
+		for _, result := range simulate(&modelGroup.tree, &openrtb_ext.RequestWrapper{BidRequest: &bidRequest}) {
+			simulation.Summary.Evaluations++
+			simulation.Summary.RulesFired[result.RuleFired]++
+			for _, bidder := range result.Bidders {
+				simulation.Summary.Bidders[bidder]++
+			}
+			if result.Error != "" {
+				simulation.Summary.Errors++
+			}
+			simulation.Results = append(simulation.Results, result)
+		}
+	}
+
+	return simulation
+}
+
+// simulateRequest evaluates a request scoped tree on the request, applying the bidders it allows and excludes
+// the way the processed auction request hook does.
+func simulateRequest(tree *rules.Tree[RequestWrapper, ProcessedAuctionHookResult], request *openrtb_ext.RequestWrapper) []SimulationResult {
+	result := ProcessedAuctionHookResult{
+		HookResult: hs.HookResult[hs.ProcessedAuctionRequestPayload]{
+			ChangeSet: hs.ChangeSet[hs.ProcessedAuctionRequestPayload]{},
+		},
+		AllowedBidders: make(map[string]struct{}),
+	}
+
+	meta, err := tree.Trace(request, &result)
+	if len(result.AllowedBidders) > 0 {
+		result.HookResult.ChangeSet.ProcessedAuctionRequest().Bidders().Add(result.AllowedBidders)
+	}
+	if err == nil {
+		err = applyMutations(result.HookResult.ChangeSet, request)
+	}
+
+	simulationResult := newSimulationResult(request.ID, "", meta, err)
+	for _, imp := range request.GetImp() {
+		for bidder := range impBidders(imp) {
+			simulationResult.Bidders = append(simulationResult.Bidders, bidder)
+		}
+	}
+	slices.Sort(simulationResult.Bidders)
+	simulationResult.Bidders = slices.Compact(simulationResult.Bidders)
+	return []SimulationResult{simulationResult}
+}
+
+// simulateImps evaluates an imp scoped tree on every imp of the request.
+func simulateImps(tree *rules.Tree[rules.Imp, ImpHookResult], request *openrtb_ext.RequestWrapper) []SimulationResult {
+	imps := request.GetImp()
+	results := make([]SimulationResult, 0, len(imps))
+	for _, impWrapper := range imps {
+		var impResult ImpHookResult
+		meta, err := tree.Trace(&rules.Imp{Request: request, Imp: impWrapper}, &impResult)
+
+		simulationResult := newSimulationResult(request.ID, impWrapper.ID, meta, err)
+		bidders := impBidders(impWrapper)
+		if err == nil {
+			bidders = filterImpBidders(bidders, impResult)
+		}
+		for bidder := range bidders {
+			simulationResult.Bidders = append(simulationResult.Bidders, bidder)
+		}
+		slices.Sort(simulationResult.Bidders)
+		results = append(results, simulationResult)
+	}
+	return results
+}
+
+func newSimulationResult(requestID, impID string, meta rules.ResultFunctionMeta, err error) SimulationResult {
+	result := SimulationResult{
+		RequestID: requestID,
+		ImpID:     impID,
+		RuleFired: meta.RuleFired,
+		Bidders:   []string{},
+	}
+	for _, step := range meta.SchemaFunctionResults {
+		result.SchemaFunctionResults = append(result.SchemaFunctionResults, SchemaFunctionResult{
+			Function: step.FuncName,
+			Value:    step.FuncResult,
+		})
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+func applyMutations(changeSet hs.ChangeSet[hs.ProcessedAuctionRequestPayload], request *openrtb_ext.RequestWrapper) error {
+	payload := hs.ProcessedAuctionRequestPayload{Request: request}
+	for _, mutation := range changeSet.Mutations() {
+		var err error
+		if payload, err = mutation.Apply(payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// impBidders returns the bidders of imp.ext.prebid.bidder, ignoring imps with a malformed ext.
+func impBidders(imp *openrtb_ext.ImpWrapper) map[string]json.RawMessage {
+	impExt, err := imp.GetImpExt()
+	if err != nil {
+		return nil
+	}
+	if impPrebid := impExt.GetPrebid(); impPrebid != nil {
+		return impPrebid.Bidder
+	}
+	return nil
+}
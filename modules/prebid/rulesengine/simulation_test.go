@@ -0,0 +1,197 @@
+package rulesengine
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/hooks"
+	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimulate(t *testing.T) {
+	cfg := &config.PbRulesEngine{
+		Enabled: true,
+		RuleSets: []config.RuleSet{
+			{
+				Stage: hooks.StageProcessedAuctionRequest,
+				Name:  "request-rules",
+				ModelGroups: []config.ModelGroup{
+					{
+						Weight:       90,
+						AnalyticsKey: "control",
+						Version:      "1.0",
+						Schema:       []config.Schema{{Func: "deviceCountry"}},
+						Rules: []config.Rule{
+							{
+								Conditions: []string{"USA"},
+								Results:    []config.Result{{Func: ExcludeBiddersName, Args: json.RawMessage(`{"bidders":["bidderA"]}`)}},
+							},
+						},
+						Default: []config.Result{{Func: IncludeBiddersName, Args: json.RawMessage(`{"bidders":["bidderB"]}`)}},
+					},
+					{
+						Weight:       10,
+						AnalyticsKey: "experiment",
+						Version:      "2.0",
+						Schema:       []config.Schema{{Func: "deviceCountry"}},
+						Rules: []config.Rule{
+							{
+								Conditions: []string{"*"},
+								Results:    []config.Result{{Func: IncludeBiddersName, Args: json.RawMessage(`{"bidders":["bidderA"]}`)}},
+							},
+						},
+					},
+				},
+			},
+			{
+				Stage: hooks.StageProcessedAuctionRequest,
+				Name:  "imp-rules",
+				Scope: config.ScopeImp,
+				ModelGroups: []config.ModelGroup{
+					{
+						Weight: 100,
+						Schema: []config.Schema{{Func: "mediaType"}},
+						Rules: []config.Rule{
+							{
+								Conditions: []string{"video"},
+								Results:    []config.Result{{Func: ExcludeBiddersName, Args: json.RawMessage(`{"bidders":["bidderB"]}`)}},
+							},
+						},
+					},
+				},
+			},
+			{
+				Stage: hooks.StageBidderRequest,
+				Name:  "bidder-rules",
+			},
+		},
+	}
+	requests := []json.RawMessage{
+		json.RawMessage(`{"id":"req-1","device":{"geo":{"country":"USA"}},"imp":[{"id":"imp-1","banner":{},"ext":{"prebid":{"bidder":{"bidderA":{},"bidderB":{}}}}}]}`),
+		json.RawMessage(`{"id":"req-2","device":{"geo":{"country":"CAN"}},"imp":[{"id":"imp-1","video":{},"ext":{"prebid":{"bidder":{"bidderA":{},"bidderB":{},"bidderC":{}}}}}]}`),
+		json.RawMessage(`malformed`),
+	}
+
+	report := Simulate(cfg, requests)
+
+	assert.Equal(t, 2, report.Requests)
+	assert.Len(t, report.Errors, 1)
+	assert.Equal(t, []RuleSetSimulation{
+		{
+			Name:  "request-rules",
+			Stage: hooks.StageProcessedAuctionRequest,
+			ModelGroups: []ModelGroupSimulation{
+				{
+					Version:      "1.0",
+					AnalyticsKey: "control",
+					Weight:       90,
+					Results: []SimulationResult{
+						{
+							RequestID:             "req-1",
+							RuleFired:             "USA",
+							SchemaFunctionResults: []SchemaFunctionResult{{Function: "deviceCountry", Value: "USA"}},
+							Bidders:               []string{"bidderB"},
+						},
+						{
+							RequestID:             "req-2",
+							RuleFired:             "default",
+							SchemaFunctionResults: []SchemaFunctionResult{{Function: "deviceCountry", Value: "CAN"}},
+							Bidders:               []string{"bidderB"},
+						},
+					},
+					Summary: SimulationSummary{
+						Evaluations: 2,
+						RulesFired:  map[string]int{"USA": 1, "default": 1},
+						Bidders:     map[string]int{"bidderB": 2},
+					},
+				},
+				{
+					Version:      "2.0",
+					AnalyticsKey: "experiment",
+					Weight:       10,
+					Results: []SimulationResult{
+						{
+							RequestID:             "req-1",
+							RuleFired:             "*",
+							SchemaFunctionResults: []SchemaFunctionResult{{Function: "deviceCountry", Value: "USA"}},
+							Bidders:               []string{"bidderA"},
+						},
+						{
+							RequestID:             "req-2",
+							RuleFired:             "*",
+							SchemaFunctionResults: []SchemaFunctionResult{{Function: "deviceCountry", Value: "CAN"}},
+							Bidders:               []string{"bidderA"},
+						},
+					},
+					Summary: SimulationSummary{
+						Evaluations: 2,
+						RulesFired:  map[string]int{"*": 2},
+						Bidders:     map[string]int{"bidderA": 2},
+					},
+				},
+			},
+		},
+		{
+			Name:  "imp-rules",
+			Stage: hooks.StageProcessedAuctionRequest,
+			Scope: config.ScopeImp,
+			ModelGroups: []ModelGroupSimulation{
+				{
+					Weight: 100,
+					Results: []SimulationResult{
+						{
+							RequestID:             "req-1",
+							ImpID:                 "imp-1",
+							RuleFired:             "default",
+							SchemaFunctionResults: []SchemaFunctionResult{{Function: "mediaType", Value: "banner"}},
+							Bidders:               []string{"bidderA", "bidderB"},
+						},
+						{
+							RequestID:             "req-2",
+							ImpID:                 "imp-1",
+							RuleFired:             "video",
+							SchemaFunctionResults: []SchemaFunctionResult{{Function: "mediaType", Value: "video"}},
+							Bidders:               []string{"bidderA", "bidderC"},
+						},
+					},
+					Summary: SimulationSummary{
+						Evaluations: 2,
+						RulesFired:  map[string]int{"default": 1, "video": 1},
+						Bidders:     map[string]int{"bidderA": 2, "bidderB": 1, "bidderC": 1},
+					},
+				},
+			},
+		},
+		{
+			Name:    "bidder-rules",
+			Stage:   hooks.StageBidderRequest,
+			Skipped: "only processed_auction_request rulesets can be simulated",
+		},
+	}, report.RuleSets)
+}
+
+func TestSimulateInvalidRuleSet(t *testing.T) {
+	cfg := &config.PbRulesEngine{
+		RuleSets: []config.RuleSet{
+			{
+				Stage: hooks.StageProcessedAuctionRequest,
+				Name:  "invalid-rules",
+				ModelGroups: []config.ModelGroup{
+					{
+						Schema: []config.Schema{{Func: "unknownFunction"}},
+						Rules:  []config.Rule{{Conditions: []string{"*"}}},
+					},
+				},
+			},
+		},
+	}
+
+	report := Simulate(cfg, []json.RawMessage{json.RawMessage(`{"id":"req-1"}`)})
+
+	assert.Equal(t, 1, report.Requests)
+	if assert.Len(t, report.RuleSets, 1) {
+		assert.NotEmpty(t, report.RuleSets[0].Skipped)
+		assert.Empty(t, report.RuleSets[0].ModelGroups)
+	}
+}
@@ -12,6 +12,10 @@ import (
 type buildInstruction struct {
 	accountID string
 	config    *json.RawMessage
+
+	// versioned is set for configurations fetched from a ruleset source, which are rebuilt as soon as the active
+	// version changes rather than once the cache entry expires
+	versioned bool
 }
 
 // treeManager represents the component that generates trees
@@ -34,7 +38,10 @@ func (tm *treeManager) Run(c cacher) error {
 			}
 
 			cacheObj := c.Get(req.accountID)
-			if cacheObj != nil && !rebuildTrees(cacheObj, req.config, c) {
+			if cacheObj != nil && req.versioned && !configChanged(cacheObj.hashedConfig, req.config) {
+				break
+			}
+			if cacheObj != nil && !req.versioned && !rebuildTrees(cacheObj, req.config, c) {
 				break
 			}
 
@@ -2,7 +2,6 @@
 package scope3
 
 import (
-	"bytes"
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
@@ -10,20 +9,18 @@ import (
 	"errors"
 	"fmt"
 	"hash"
-	"maps"
 	"net/http"
-	"slices"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/coocood/freecache"
 	"github.com/golang/glog"
-	jsoniter "github.com/json-iterator/go"
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
 	"github.com/prebid/prebid-server/v3/hooks/hookstage"
 	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+	"github.com/prebid/prebid-server/v3/modules/prebid/rtd"
 	"github.com/prebid/prebid-server/v3/util/iterutil"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
 )
@@ -50,34 +47,19 @@ func Builder(config json.RawMessage, deps moduledeps.ModuleDeps) (interface{}, e
 	}
 
 	// Set masking defaults and validate configuration
-	if cfg.Masking.Enabled {
-		// Validate and set geo precision (max 4 decimal places for privacy)
-		if cfg.Masking.Geo.LatLongPrecision == 0 {
-			cfg.Masking.Geo.LatLongPrecision = 2 // 2 decimal places default (~1.1km precision)
-		} else if cfg.Masking.Geo.LatLongPrecision > 4 {
-			return nil, errors.New("lat_long_precision cannot exceed 4 decimal places for privacy protection")
-		} else if cfg.Masking.Geo.LatLongPrecision < 0 {
-			return nil, errors.New("lat_long_precision cannot be negative")
-		}
-
-		// Set default EID allowlist if empty
-		if len(cfg.Masking.User.PreserveEids) == 0 {
-			// Default to preserving common identity providers
-			cfg.Masking.User.PreserveEids = []string{"liveramp.com", "uidapi.com", "id5-sync.com"}
-		}
-
-		// Set default preserve values for geo fields
-		if !cfg.Masking.Geo.PreserveMetro && !cfg.Masking.Geo.PreserveZip && !cfg.Masking.Geo.PreserveCity {
-			cfg.Masking.Geo.PreserveMetro = true
-			cfg.Masking.Geo.PreserveZip = true
-		}
+	if err := cfg.Masking.SetDefaults(); err != nil {
+		return nil, err
 	}
 
 	return &Module{
 		cfg: cfg,
-		httpClient: &http.Client{
-			Timeout:   time.Duration(cfg.Timeout) * time.Millisecond,
-			Transport: deps.HTTPClient.Transport,
+		client: &rtd.Scope3Client{
+			Endpoint: cfg.Endpoint,
+			AuthKey:  cfg.AuthKey,
+			HTTPClient: &http.Client{
+				Timeout:   time.Duration(cfg.Timeout) * time.Millisecond,
+				Transport: deps.HTTPClient.Transport,
+			},
 		},
 		cache: freecache.NewCache(cfg.CacheSize),
 		sha256Pool: &sync.Pool{
@@ -97,7 +79,7 @@ const (
 
 var scope3MacroKeyPlusSeparator = scope3MacroKey + scope3MacroSeparator
 
-const DefaultScope3RTDURL = "https://rtdp.scope3.com/prebid/prebid"
+const DefaultScope3RTDURL = rtd.DefaultScope3URL
 
 var (
 	// Declare hooks
@@ -118,30 +100,16 @@ type Config struct {
 }
 
 // MaskingConfig controls what user data is masked before sending to Scope3
-type MaskingConfig struct {
-	Enabled bool                `json:"enabled"`
-	Geo     GeoMaskingConfig    `json:"geo"`
-	User    UserMaskingConfig   `json:"user"`
-	Device  DeviceMaskingConfig `json:"device"`
-}
+type MaskingConfig = rtd.MaskingConfig
 
 // GeoMaskingConfig controls geographic data masking
-type GeoMaskingConfig struct {
-	PreserveMetro    bool `json:"preserve_metro"`     // DMA code (default: true)
-	PreserveZip      bool `json:"preserve_zip"`       // Postal code (default: true)
-	PreserveCity     bool `json:"preserve_city"`      // City name (default: false)
-	LatLongPrecision int  `json:"lat_long_precision"` // Decimal places for lat/long (0-4, default: 2)
-}
+type GeoMaskingConfig = rtd.GeoMaskingConfig
 
 // UserMaskingConfig controls user data masking
-type UserMaskingConfig struct {
-	PreserveEids []string `json:"preserve_eids"` // List of EID sources to preserve
-}
+type UserMaskingConfig = rtd.UserMaskingConfig
 
 // DeviceMaskingConfig controls device data masking
-type DeviceMaskingConfig struct {
-	PreserveMobileIds bool `json:"preserve_mobile_ids"` // Keep mobile advertising IDs (default: false)
-}
+type DeviceMaskingConfig = rtd.DeviceMaskingConfig
 
 type userExt struct {
 	Eids           []openrtb2.EID `json:"eids"`
@@ -151,39 +119,21 @@ type userExt struct {
 	RampIDEnvelope string         `json:"rampId_envelope"`
 }
 
-// Response types for Scope3 API
-type Scope3Response struct {
-	Data []Scope3Data `json:"data"`
-}
-
-type Scope3Data struct {
-	Destination string          `json:"destination"`
-	Imp         []Scope3ImpData `json:"imp"`
-}
-
-type Scope3ImpData struct {
-	ID  string     `json:"id"`
-	Ext *Scope3Ext `json:"ext,omitempty"`
-}
-
-type Scope3Ext struct {
-	Scope3 *Scope3ExtData `json:"scope3"`
-}
-
-type Scope3ExtData struct {
-	Segments []Scope3Segment `json:"segments"`
-	Macro    string          `json:"macro"`
-}
-
-type Scope3Segment struct {
-	ID string `json:"id"`
-}
+// Response types for Scope3 API, shared with the Scope3 provider of the RTD module
+type (
+	Scope3Response = rtd.Scope3Response
+	Scope3Data     = rtd.Scope3Data
+	Scope3ImpData  = rtd.Scope3ImpData
+	Scope3Ext      = rtd.Scope3Ext
+	Scope3ExtData  = rtd.Scope3ExtData
+	Scope3Segment  = rtd.Scope3Segment
+)
 
 // Module implements the Scope3 RTD module
 type Module struct {
-	cfg        Config
-	httpClient *http.Client
-	cache      *freecache.Cache
+	cfg    Config
+	client *rtd.Scope3Client
+	cache  *freecache.Cache
 	// sha256Pool provides a pool of reusable SHA-256 hash instances for performance
 	sha256Pool *sync.Pool
 }
@@ -389,57 +339,12 @@ func (m *Module) fetchScope3Segments(ctx context.Context, bidRequest *openrtb2.B
 		requestToSend = maskedRequest
 	}
 
-	// Marshal the (potentially masked) bid request
-	requestBody, err := jsonutil.Marshal(requestToSend)
-	if err != nil {
-		return nil, err
-	}
-
-	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, "POST", m.cfg.Endpoint, bytes.NewReader(requestBody))
+	ids, macro, err := m.client.Fetch(ctx, requestToSend)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("x-scope3-auth", m.cfg.AuthKey)
-
-	// Make the request
-	resp, err := m.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("scope3 returned status %d", resp.StatusCode)
-	}
-
-	// Parse response
-	var scope3Resp Scope3Response
-	if err = jsoniter.ConfigCompatibleWithStandardLibrary.NewDecoder(resp.Body).Decode(&scope3Resp); err != nil {
-		return nil, err
-	}
-
-	// Extract unique segments (exclude destination)
-	segmentMap := make(map[string]bool)
-	var macro string
-	for data := range iterutil.SlicePointerValues(scope3Resp.Data) {
-		// Extract actual segments from impression-level data
-		for imp := range iterutil.SlicePointerValues(data.Imp) {
-			if imp.Ext != nil && imp.Ext.Scope3 != nil {
-				if imp.Ext.Scope3.Macro != "" {
-					macro = imp.Ext.Scope3.Macro
-				}
-				for segment := range iterutil.SlicePointerValues(imp.Ext.Scope3.Segments) {
-					segmentMap[segment.ID] = true
-				}
-			}
-		}
-	}
-
-	// Convert to slice
-	segments := slices.AppendSeq(make([]string, 0, len(segmentMap)), maps.Keys(segmentMap))
+	segments := ids
 	if macro != "" {
 		segments = append(segments, scope3MacroKeyPlusSeparator+macro)
 	}
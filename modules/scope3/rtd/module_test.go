@@ -5,7 +5,6 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"sync"
@@ -17,6 +16,7 @@ import (
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v3/hooks/hookstage"
 	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+	"github.com/prebid/prebid-server/v3/modules/prebid/rtd"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -684,302 +684,6 @@ func TestMaskBidRequest_Disabled(t *testing.T) {
 	assert.Equal(t, original, result)
 }
 
-func TestMaskUser(t *testing.T) {
-	module := &Module{
-		cfg: Config{
-			Masking: MaskingConfig{
-				Enabled: true,
-				User: UserMaskingConfig{
-					PreserveEids: []string{"liveramp.com", "uidapi.com"},
-				},
-			},
-		},
-	}
-
-	original := &openrtb2.BidRequest{
-		User: &openrtb2.User{
-			ID:       "publisher-user-123",
-			BuyerUID: "exchange-user-456",
-			Yob:      1990,
-			Gender:   "M",
-			Keywords: "sports,automotive",
-			Data: []openrtb2.Data{{
-				ID:   "segment1",
-				Name: "sports_fans",
-			}},
-			EIDs: []openrtb2.EID{
-				{Source: "liveramp.com", UIDs: []openrtb2.UID{{ID: "ramp123"}}},
-				{Source: "blocked.com", UIDs: []openrtb2.UID{{ID: "blocked456"}}},
-				{Source: "uidapi.com", UIDs: []openrtb2.UID{{ID: "uid789"}}},
-			},
-		},
-	}
-
-	module.maskUser(original)
-
-	// Check that sensitive fields are removed
-	assert.Equal(t, "", original.User.ID)
-	assert.Equal(t, "", original.User.BuyerUID)
-	assert.Equal(t, int64(0), original.User.Yob)
-	assert.Equal(t, "", original.User.Gender)
-	assert.Equal(t, "", original.User.Keywords)
-	assert.Nil(t, original.User.Data)
-
-	// Check that eids are filtered correctly
-	assert.Len(t, original.User.EIDs, 2)
-	assert.Equal(t, "liveramp.com", original.User.EIDs[0].Source)
-	assert.Equal(t, "uidapi.com", original.User.EIDs[1].Source)
-}
-
-func TestFilterEids(t *testing.T) {
-	module := &Module{
-		cfg: Config{
-			Masking: MaskingConfig{
-				User: UserMaskingConfig{
-					PreserveEids: []string{"liveramp.com", "id5-sync.com"},
-				},
-			},
-		},
-	}
-
-	eids := []openrtb2.EID{
-		{Source: "liveramp.com", UIDs: []openrtb2.UID{{ID: "ramp123"}}},
-		{Source: "blocked.com", UIDs: []openrtb2.UID{{ID: "blocked456"}}},
-		{Source: "id5-sync.com", UIDs: []openrtb2.UID{{ID: "id5789"}}},
-		{Source: "another-blocked.com", UIDs: []openrtb2.UID{{ID: "blocked999"}}},
-	}
-
-	result := module.filterEids(eids)
-
-	assert.Len(t, result, 2)
-	assert.Equal(t, "liveramp.com", result[0].Source)
-	assert.Equal(t, "id5-sync.com", result[1].Source)
-}
-
-func TestFilterEids_EmptyAllowlist(t *testing.T) {
-	module := &Module{
-		cfg: Config{
-			Masking: MaskingConfig{
-				User: UserMaskingConfig{
-					PreserveEids: []string{},
-				},
-			},
-		},
-	}
-
-	eids := []openrtb2.EID{
-		{Source: "liveramp.com", UIDs: []openrtb2.UID{{ID: "ramp123"}}},
-		{Source: "blocked.com", UIDs: []openrtb2.UID{{ID: "blocked456"}}},
-	}
-
-	result := module.filterEids(eids)
-	assert.Len(t, result, 0)
-}
-
-func TestMaskDevice(t *testing.T) {
-	module := &Module{
-		cfg: Config{
-			Masking: MaskingConfig{
-				Enabled: true,
-				Device: DeviceMaskingConfig{
-					PreserveMobileIds: false,
-				},
-			},
-		},
-	}
-
-	original := &openrtb2.BidRequest{
-		Device: &openrtb2.Device{
-			IP:         "192.168.1.1",
-			IPv6:       "2001:db8::1",
-			IFA:        "12345-67890",
-			DPIDMD5:    "abc123",
-			DPIDSHA1:   "def456",
-			DIDMD5:     "ghi789",
-			DIDSHA1:    "jkl012",
-			MACMD5:     "mno345",
-			MACSHA1:    "pqr678",
-			DeviceType: 1,
-			OS:         "iOS",
-			Make:       "Apple",
-			Model:      "iPhone",
-		},
-	}
-
-	module.maskDevice(original)
-
-	// Check sensitive fields are removed
-	assert.Equal(t, "", original.Device.IP)
-	assert.Equal(t, "", original.Device.IPv6)
-	assert.Equal(t, "", original.Device.IFA)
-	assert.Equal(t, "", original.Device.DPIDMD5)
-	assert.Equal(t, "", original.Device.DPIDSHA1)
-	assert.Equal(t, "", original.Device.DIDMD5)
-	assert.Equal(t, "", original.Device.DIDSHA1)
-	assert.Equal(t, "", original.Device.MACMD5)
-	assert.Equal(t, "", original.Device.MACSHA1)
-
-	// Check targeting-safe fields are preserved
-	assert.Equal(t, adcom1.DeviceType(1), original.Device.DeviceType)
-	assert.Equal(t, "iOS", original.Device.OS)
-	assert.Equal(t, "Apple", original.Device.Make)
-	assert.Equal(t, "iPhone", original.Device.Model)
-}
-
-func TestMaskDevice_PreserveMobileIds(t *testing.T) {
-	module := &Module{
-		cfg: Config{
-			Masking: MaskingConfig{
-				Enabled: true,
-				Device: DeviceMaskingConfig{
-					PreserveMobileIds: true,
-				},
-			},
-		},
-	}
-
-	original := &openrtb2.BidRequest{
-		Device: &openrtb2.Device{
-			IP:       "192.168.1.1",
-			IFA:      "12345-67890",
-			DPIDMD5:  "abc123",
-			DPIDSHA1: "def456",
-		},
-	}
-
-	module.maskDevice(original)
-
-	// IP should still be removed
-	assert.Equal(t, "", original.Device.IP)
-
-	// But mobile IDs should be preserved
-	assert.Equal(t, "12345-67890", original.Device.IFA)
-	assert.Equal(t, "abc123", original.Device.DPIDMD5)
-	assert.Equal(t, "def456", original.Device.DPIDSHA1)
-}
-
-func TestMaskGeoObject(t *testing.T) {
-	lat := 37.774929
-	lon := -122.419416
-	accuracy := int64(10)
-
-	tests := []struct {
-		name             string
-		config           GeoMaskingConfig
-		expectedLat      *float64
-		expectedLon      *float64
-		expectedMetro    string
-		expectedZip      string
-		expectedCity     string
-		expectedAccuracy int64
-	}{
-		{
-			name: "precision_0_removes_coordinates",
-			config: GeoMaskingConfig{
-				LatLongPrecision: 0,
-				PreserveMetro:    true,
-				PreserveZip:      true,
-				PreserveCity:     true,
-			},
-			expectedLat:      nil,
-			expectedLon:      nil,
-			expectedMetro:    "807",           // preserved
-			expectedZip:      "94102",         // preserved
-			expectedCity:     "San Francisco", // preserved
-			expectedAccuracy: 0,               // always removed
-		},
-		{
-			name: "precision_2_truncates_coordinates",
-			config: GeoMaskingConfig{
-				LatLongPrecision: 2,
-				PreserveMetro:    false,
-				PreserveZip:      false,
-				PreserveCity:     false,
-			},
-			expectedLat:      &[]float64{37.77}[0],   // truncated to 2 decimals
-			expectedLon:      &[]float64{-122.41}[0], // truncated to 2 decimals
-			expectedMetro:    "",                     // not preserved
-			expectedZip:      "",                     // not preserved
-			expectedCity:     "",                     // not preserved
-			expectedAccuracy: 0,                      // always removed
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			module := &Module{
-				cfg: Config{
-					Masking: MaskingConfig{
-						Enabled: true,
-						Geo:     tt.config,
-					},
-				},
-			}
-
-			geo := &openrtb2.Geo{
-				Lat:      &lat,
-				Lon:      &lon,
-				Country:  "USA", // always preserved
-				Region:   "CA",  // always preserved
-				Metro:    "807",
-				ZIP:      "94102",
-				City:     "San Francisco",
-				Accuracy: accuracy,
-			}
-
-			module.maskGeoObject(geo)
-
-			// Check coordinates
-			if tt.expectedLat == nil {
-				assert.Nil(t, geo.Lat)
-			} else {
-				require.NotNil(t, geo.Lat)
-				assert.InDelta(t, *tt.expectedLat, *geo.Lat, 0.001)
-			}
-
-			if tt.expectedLon == nil {
-				assert.Nil(t, geo.Lon)
-			} else {
-				require.NotNil(t, geo.Lon)
-				assert.InDelta(t, *tt.expectedLon, *geo.Lon, 0.001)
-			}
-
-			// Check other fields
-			assert.Equal(t, "USA", geo.Country) // always preserved
-			assert.Equal(t, "CA", geo.Region)   // always preserved
-			assert.Equal(t, tt.expectedMetro, geo.Metro)
-			assert.Equal(t, tt.expectedZip, geo.ZIP)
-			assert.Equal(t, tt.expectedCity, geo.City)
-			assert.Equal(t, int64(0), geo.Accuracy) // always removed
-		})
-	}
-}
-
-func TestTruncateCoordinate(t *testing.T) {
-	module := &Module{}
-
-	tests := []struct {
-		coord     float64
-		precision int
-		expected  float64
-	}{
-		{37.774929, 0, 0},
-		{37.774929, 1, 37.7},
-		{37.774929, 2, 37.77},
-		{37.774929, 3, 37.774},
-		{37.774929, 4, 37.7749},
-		{-122.419416, 2, -122.41},
-		{-122.419416, 3, -122.419},
-	}
-
-	for _, tt := range tests {
-		t.Run(fmt.Sprintf("coord_%.6f_precision_%d", tt.coord, tt.precision), func(t *testing.T) {
-			result := module.truncateCoordinate(tt.coord, tt.precision)
-			assert.InDelta(t, tt.expected, result, 0.0001)
-		})
-	}
-}
-
 func TestMaskBidRequest_FullIntegration(t *testing.T) {
 	module := &Module{
 		cfg: Config{
@@ -1072,150 +776,6 @@ func TestMaskBidRequest_FullIntegration(t *testing.T) {
 	assert.InDelta(t, 37.774929, *original.Device.Geo.Lat, 0.000001)
 }
 
-func TestGetMaskingSummary(t *testing.T) {
-	module := &Module{
-		cfg: Config{
-			Masking: MaskingConfig{
-				Enabled: true,
-				Geo: GeoMaskingConfig{
-					PreserveMetro:    true,
-					PreserveZip:      false,
-					LatLongPrecision: 3,
-				},
-				User: UserMaskingConfig{
-					PreserveEids: []string{"liveramp.com", "uidapi.com"},
-				},
-				Device: DeviceMaskingConfig{
-					PreserveMobileIds: true,
-				},
-			},
-		},
-	}
-
-	summary := module.getMaskingSummary()
-
-	assert.Equal(t, true, summary["enabled"])
-
-	geoConfig, ok := summary["geo"].(map[string]interface{})
-	require.True(t, ok)
-	assert.Equal(t, true, geoConfig["preserve_metro"])
-	assert.Equal(t, false, geoConfig["preserve_zip"])
-	assert.Equal(t, 3, geoConfig["lat_long_precision"])
-
-	userConfig, ok := summary["user"].(map[string]interface{})
-	require.True(t, ok)
-	assert.Equal(t, []string{"liveramp.com", "uidapi.com"}, userConfig["preserve_eids"])
-
-	deviceConfig, ok := summary["device"].(map[string]interface{})
-	require.True(t, ok)
-	assert.Equal(t, true, deviceConfig["preserve_mobile_ids"])
-}
-
-func TestGetMaskingSummary_Disabled(t *testing.T) {
-	module := &Module{
-		cfg: Config{
-			Masking: MaskingConfig{Enabled: false},
-		},
-	}
-
-	summary := module.getMaskingSummary()
-	assert.Equal(t, map[string]interface{}{"enabled": false}, summary)
-}
-
-func TestMaskGeo_UserGeoOnly(t *testing.T) {
-	module := &Module{
-		cfg: Config{
-			Masking: MaskingConfig{
-				Enabled: true,
-				Geo: GeoMaskingConfig{
-					PreserveMetro:    false,
-					PreserveZip:      false,
-					LatLongPrecision: 2,
-				},
-			},
-		},
-	}
-
-	lat := 40.7128
-	lon := -74.0060
-	original := &openrtb2.BidRequest{
-		User: &openrtb2.User{
-			Geo: &openrtb2.Geo{
-				Lat:     &lat,
-				Lon:     &lon,
-				Country: "USA",
-				Region:  "NY",
-				Metro:   "501",
-				ZIP:     "10001",
-			},
-		},
-		// No device geo
-		Device: &openrtb2.Device{
-			OS: "iOS",
-		},
-	}
-
-	module.maskGeo(original)
-
-	// Check user geo was masked
-	assert.Equal(t, "USA", original.User.Geo.Country)        // preserved
-	assert.Equal(t, "NY", original.User.Geo.Region)          // preserved
-	assert.Equal(t, "", original.User.Geo.Metro)             // removed
-	assert.Equal(t, "", original.User.Geo.ZIP)               // removed
-	assert.InDelta(t, 40.71, *original.User.Geo.Lat, 0.001)  // truncated
-	assert.InDelta(t, -74.00, *original.User.Geo.Lon, 0.001) // truncated
-}
-
-func TestTruncateCoordinate_EdgeCases(t *testing.T) {
-	module := &Module{}
-
-	// Test precision out of range
-	assert.Equal(t, float64(0), module.truncateCoordinate(37.774929, -1))
-	assert.Equal(t, float64(0), module.truncateCoordinate(37.774929, 5))
-	assert.Equal(t, float64(0), module.truncateCoordinate(37.774929, 0))
-
-	// Test zero coordinate
-	assert.Equal(t, float64(0), module.truncateCoordinate(0.0, 2))
-}
-
-func TestMaskDevice_NoDevice(t *testing.T) {
-	module := &Module{
-		cfg: Config{
-			Masking: MaskingConfig{Enabled: true},
-		},
-	}
-
-	original := &openrtb2.BidRequest{
-		ID:   "test",
-		User: &openrtb2.User{ID: "user-123"},
-		// No device
-	}
-
-	module.maskDevice(original)
-
-	// Should not crash when device is nil
-	assert.Nil(t, original.Device)
-}
-
-func TestMaskUser_NoUser(t *testing.T) {
-	module := &Module{
-		cfg: Config{
-			Masking: MaskingConfig{Enabled: true},
-		},
-	}
-
-	original := &openrtb2.BidRequest{
-		ID: "test",
-		// No user
-		Device: &openrtb2.Device{IP: "192.168.1.1"},
-	}
-
-	module.maskUser(original)
-
-	// Should not crash when user is nil
-	assert.Nil(t, original.User)
-}
-
 func TestBuilderConfigValidation_GeoPrecisionTooHigh(t *testing.T) {
 	config := json.RawMessage(`{
 		"enabled": true,
@@ -1398,8 +958,10 @@ func TestFetchScope3Segments_MaskingFailure(t *testing.T) {
 			Timeout:  1000,
 			Masking:  MaskingConfig{Enabled: true},
 		},
-		httpClient: &http.Client{
-			Timeout: 1 * time.Second,
+		client: &rtd.Scope3Client{
+			Endpoint:   mockServer.URL,
+			AuthKey:    "test-key",
+			HTTPClient: &http.Client{Timeout: 1 * time.Second},
 		},
 		cache: freecache.NewCache(10),
 		sha256Pool: &sync.Pool{
@@ -0,0 +1,751 @@
+package openrtb_ext
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// AdCOMDomainSpec is the only OpenRTB 3.0 domain specification Prebid Server can translate.
+const AdCOMDomainSpec = "adcom"
+
+var adcomOperatingSystems = map[adcom1.OperatingSystem]string{
+	adcom1.OSAndroid:  "android",
+	adcom1.OSAppleTV:  "tvos",
+	adcom1.OSChromeOS: "chromeos",
+	adcom1.OSFireOS:   "fireos",
+	adcom1.OSIOS:      "ios",
+	adcom1.OSLinux:    "linux",
+	adcom1.OSMacOS:    "macos",
+	adcom1.OSPS4:      "ps4",
+	adcom1.OSTizen:    "tizen",
+	adcom1.OSWatchOS:  "watchos",
+	adcom1.OSWebOS:    "webos",
+	adcom1.OSWindows:  "windows",
+}
+
+// ConvertRequestFrom30 translates an OpenRTB 3.0 request described with AdCOM 1.0 into an OpenRTB 2.6 bid
+// request. Each item becomes an imp with the item id as the imp id. Fields without an OpenRTB 2.6
+// counterpart are dropped and reported as warnings. An error is returned if the request can't be
+// translated at all.
+func ConvertRequestFrom30(body openrtb3.Body) (*openrtb2.BidRequest, []error, error) {
+	ortb3 := body.OpenRTB
+	if ortb3.Request == nil {
+		return nil, nil, errors.New("openrtb.request is required")
+	}
+	if ortb3.DomainSpec != "" && !strings.EqualFold(ortb3.DomainSpec, AdCOMDomainSpec) {
+		return nil, nil, fmt.Errorf("openrtb.domainspec %q is not supported, only %q can be translated", ortb3.DomainSpec, AdCOMDomainSpec)
+	}
+
+	c := &converter30{}
+	req := ortb3.Request
+
+	bidRequest := &openrtb2.BidRequest{
+		ID:   req.ID,
+		Test: req.Test,
+		TMax: req.TMax,
+		AT:   int64(req.AT),
+		Cur:  req.Cur,
+		Ext:  req.Ext,
+	}
+
+	if len(req.Seat) > 0 {
+		if req.WSeat == 0 {
+			bidRequest.BSeat = req.Seat
+		} else {
+			bidRequest.WSeat = req.Seat
+		}
+	}
+	c.warnIfSet(req.CData != "", "request.cdata")
+	c.warnIfSet(req.Package != 0, "request.package")
+
+	if req.Source != nil {
+		bidRequest.Source = c.convertSource(req.Source)
+	}
+
+	var battr []adcom1.CreativeAttribute
+	if len(req.Context) > 0 {
+		var context adcom1.RequestContext
+		if err := jsonutil.Unmarshal(req.Context, &context); err != nil {
+			return nil, nil, fmt.Errorf("request.context is invalid: %v", err)
+		}
+		battr = c.convertContext(&context, bidRequest)
+	}
+
+	if len(req.Item) == 0 {
+		return nil, nil, errors.New("request.item must contain at least one item")
+	}
+	bidRequest.Imp = make([]openrtb2.Imp, 0, len(req.Item))
+	for i := range req.Item {
+		imp, err := c.convertItem(&req.Item[i], i, battr)
+		if err != nil {
+			return nil, nil, err
+		}
+		bidRequest.Imp = append(bidRequest.Imp, imp)
+	}
+
+	return bidRequest, c.warnings, nil
+}
+
+// ConvertResponseTo30 translates an OpenRTB 2.6 bid response into an OpenRTB 3.0 response with AdCOM ad
+// objects as bid media. The warnings are merged into the general warnings of the response ext, so
+// publishers can see which parts of their request were not translated.
+func ConvertResponseTo30(resp *openrtb2.BidResponse, warnings []error) (openrtb3.Body, error) {
+	response := &openrtb3.Response{
+		ID:    resp.ID,
+		BidID: resp.BidID,
+		Cur:   resp.Cur,
+		CData: resp.CustomData,
+	}
+	if resp.NBR != nil {
+		response.NBR = *resp.NBR
+	}
+
+	for _, seatBid := range resp.SeatBid {
+		seat := openrtb3.SeatBid{
+			Seat: seatBid.Seat,
+			Ext:  seatBid.Ext,
+			Bid:  make([]openrtb3.Bid, 0, len(seatBid.Bid)),
+		}
+		for i := range seatBid.Bid {
+			bid, err := convertBidTo30(&seatBid.Bid[i])
+			if err != nil {
+				return openrtb3.Body{}, err
+			}
+			seat.Bid = append(seat.Bid, bid)
+		}
+		response.SeatBid = append(response.SeatBid, seat)
+	}
+
+	ext, err := addGeneralWarnings(resp.Ext, warnings)
+	if err != nil {
+		return openrtb3.Body{}, err
+	}
+	response.Ext = ext
+
+	return openrtb3.Body{
+		OpenRTB: openrtb3.OpenRTB{
+			Ver:        "3.0",
+			DomainSpec: AdCOMDomainSpec,
+			DomainVer:  "1.0",
+			Response:   response,
+		},
+	}, nil
+}
+
+type converter30 struct {
+	warnings []error
+}
+
+func (c *converter30) warnIfSet(set bool, field string) {
+	if set {
+		c.warnings = append(c.warnings, &errortypes.Warning{
+			WarningCode: errortypes.OpenRTB3ConversionWarningCode,
+			Message:     fmt.Sprintf("%s has no OpenRTB 2 equivalent and was ignored", field),
+		})
+	}
+}
+
+func (c *converter30) convertSource(source *openrtb3.Source) *openrtb2.Source {
+	c.warnIfSet(source.TS != 0, "request.source.ts")
+	c.warnIfSet(source.DS != "", "request.source.ds")
+	c.warnIfSet(source.DSMap != "", "request.source.dsmap")
+	c.warnIfSet(source.Cert != "", "request.source.cert")
+	c.warnIfSet(source.Digest != "", "request.source.digest")
+
+	return &openrtb2.Source{
+		TID:    source.TID,
+		PChain: source.PChain,
+		Ext:    source.Ext,
+	}
+}
+
+// convertContext copies the AdCOM request context onto the bid request and returns the blocked creative
+// attributes, which OpenRTB 2 expresses on each media object instead of the request.
+func (c *converter30) convertContext(context *adcom1.RequestContext, bidRequest *openrtb2.BidRequest) []adcom1.CreativeAttribute {
+	if context.Site != nil {
+		bidRequest.Site = c.convertSite(context.Site)
+	}
+	if context.App != nil {
+		bidRequest.App = c.convertApp(context.App)
+	}
+	if context.DOOH != nil {
+		bidRequest.DOOH = c.convertDOOH(context.DOOH)
+	}
+	if context.User != nil {
+		bidRequest.User = c.convertUser(context.User)
+	}
+	if context.Device != nil {
+		bidRequest.Device = c.convertDevice(context.Device)
+	}
+	if context.Regs != nil {
+		bidRequest.Regs = &openrtb2.Regs{
+			COPPA: context.Regs.COPPA,
+			GDPR:  ptrIfSet(context.Regs.GDPR),
+			Ext:   context.Regs.Ext,
+		}
+	}
+
+	if r := context.Restrictions; r != nil {
+		bidRequest.BCat = r.BCat
+		bidRequest.CatTax = r.CatTax
+		bidRequest.BAdv = r.BAdv
+		bidRequest.BApp = r.BApp
+		c.warnIfSet(len(r.Ext) > 0, "context.restrictions.ext")
+		return r.BAttr
+	}
+	return nil
+}
+
+func (c *converter30) convertSite(site *adcom1.Site) *openrtb2.Site {
+	c.warnIfSet(site.AMP != 0, "context.site.amp")
+
+	return &openrtb2.Site{
+		ID:            site.ID,
+		Name:          site.Name,
+		Domain:        site.Domain,
+		CatTax:        site.CatTax,
+		Cat:           site.Cat,
+		SectionCat:    site.SectCat,
+		PageCat:       site.PageCat,
+		Page:          site.Page,
+		Ref:           site.Ref,
+		Search:        site.Search,
+		Mobile:        ptrIfSet(site.Mobile),
+		PrivacyPolicy: ptrIfSet(site.PrivPolicy),
+		Publisher:     convertPublisher(site.Pub),
+		Content:       c.convertContent(site.Content),
+		Keywords:      site.Keywords,
+		KwArray:       site.KwArray,
+		Ext:           site.Ext,
+	}
+}
+
+func (c *converter30) convertApp(app *adcom1.App) *openrtb2.App {
+	c.warnIfSet(app.StoreID != "", "context.app.storeid")
+
+	return &openrtb2.App{
+		ID:            app.ID,
+		Name:          app.Name,
+		Bundle:        app.Bundle,
+		Domain:        app.Domain,
+		StoreURL:      app.StoreURL,
+		CatTax:        app.CatTax,
+		Cat:           app.Cat,
+		SectionCat:    app.SectCat,
+		PageCat:       app.PageCat,
+		Ver:           app.Ver,
+		PrivacyPolicy: ptrIfSet(app.PrivPolicy),
+		Paid:          ptrIfSet(app.Paid),
+		Publisher:     convertPublisher(app.Pub),
+		Content:       c.convertContent(app.Content),
+		Keywords:      app.Keywords,
+		KwArray:       app.KwArray,
+		Ext:           app.Ext,
+	}
+}
+
+func (c *converter30) convertDOOH(dooh *adcom1.DOOH) *openrtb2.DOOH {
+	c.warnIfSet(dooh.Fixed != 0, "context.dooh.fixed")
+	c.warnIfSet(dooh.ETime != 0, "context.dooh.etime")
+	c.warnIfSet(dooh.DPI != 0, "context.dooh.dpi")
+
+	converted := &openrtb2.DOOH{
+		ID:        dooh.ID,
+		Name:      dooh.Name,
+		Publisher: convertPublisher(dooh.Pub),
+		Content:   c.convertContent(dooh.Content),
+		Ext:       dooh.Ext,
+	}
+	if dooh.Venue != 0 {
+		venueTaxonomy := adcom1.VenueTaxonomyAdCom
+		converted.VenueType = []string{strconv.Itoa(int(dooh.Venue))}
+		converted.VenueTypeTax = &venueTaxonomy
+	}
+	return converted
+}
+
+func convertPublisher(pub *adcom1.Publisher) *openrtb2.Publisher {
+	if pub == nil {
+		return nil
+	}
+	return &openrtb2.Publisher{
+		ID:     pub.ID,
+		Name:   pub.Name,
+		CatTax: pub.CatTax,
+		Cat:    pub.Cat,
+		Domain: pub.Domain,
+		Ext:    pub.Ext,
+	}
+}
+
+func (c *converter30) convertContent(content *adcom1.Content) *openrtb2.Content {
+	if content == nil {
+		return nil
+	}
+
+	converted := &openrtb2.Content{
+		ID:                 content.ID,
+		Episode:            content.Episode,
+		Title:              content.Title,
+		Series:             content.Series,
+		Season:             content.Season,
+		Artist:             content.Artist,
+		Genre:              content.Genre,
+		Album:              content.Album,
+		ISRC:               content.ISRC,
+		URL:                content.URL,
+		CatTax:             content.CatTax,
+		Cat:                content.Cat,
+		Context:            content.Context,
+		ContentRating:      content.Rating,
+		UserRating:         content.URating,
+		QAGMediaRating:     content.MRating,
+		Keywords:           content.Keywords,
+		KwArray:            content.KwArray,
+		LiveStream:         ptrIfSet(content.Live),
+		SourceRelationship: ptrIfSet(content.SrcRel),
+		Len:                content.Len,
+		Language:           content.Lang,
+		Embeddable:         ptrIfSet(content.Embed),
+		Ext:                content.Ext,
+	}
+	converted.ProdQ = ptrIfSet(content.ProdQ)
+
+	// the remaining objects share their json layout between both specifications
+	c.remarshal(content.Producer, &converted.Producer, "context.content.producer")
+	c.remarshal(content.Network, &converted.Network, "context.content.network")
+	c.remarshal(content.Channel, &converted.Channel, "context.content.channel")
+	c.remarshal(content.Data, &converted.Data, "context.content.data")
+
+	return converted
+}
+
+func (c *converter30) convertUser(user *adcom1.User) *openrtb2.User {
+	converted := &openrtb2.User{
+		ID:       user.ID,
+		BuyerUID: user.BuyerUID,
+		Yob:      user.YOB,
+		Gender:   user.Gender,
+		Keywords: user.Keywords,
+		KwArray:  user.KwArray,
+		Consent:  user.Consent,
+		Geo:      convertGeo(user.Geo),
+		Ext:      user.Ext,
+	}
+	c.remarshal(user.Data, &converted.Data, "context.user.data")
+	c.remarshal(user.EIDs, &converted.EIDs, "context.user.eids")
+	return converted
+}
+
+func (c *converter30) convertDevice(device *adcom1.Device) *openrtb2.Device {
+	c.warnIfSet(device.XFF != "", "context.device.xff")
+	c.warnIfSet(device.IPTr != 0, "context.device.iptr")
+	c.warnIfSet(device.MCCMNCSIM != "", "context.device.mccmncsim")
+
+	converted := &openrtb2.Device{
+		Geo:        convertGeo(device.Geo),
+		DNT:        ptrIfSet(device.DNT),
+		Lmt:        ptrIfSet(device.Lmt),
+		UA:         device.UA,
+		IP:         device.IP,
+		IPv6:       device.IPv6,
+		DeviceType: device.Type,
+		Make:       device.Make,
+		Model:      device.Model,
+		OSV:        device.OSV,
+		HWV:        device.HWV,
+		H:          device.H,
+		W:          device.W,
+		PPI:        device.PPI,
+		PxRatio:    device.PxRatio,
+		JS:         ptrIfSet(device.JS),
+		GeoFetch:   ptrIfSet(device.GeoFetch),
+		Language:   device.Lang,
+		LangB:      device.LangB,
+		Carrier:    device.Carrier,
+		MCCMNC:     device.MCCMNC,
+		IFA:        device.IFA,
+		Ext:        device.Ext,
+	}
+	converted.ConnectionType = ptrIfSet(device.ConType)
+	if device.OS != adcom1.OSNotListed {
+		if os, ok := adcomOperatingSystems[device.OS]; ok {
+			converted.OS = os
+		} else {
+			c.warnIfSet(true, fmt.Sprintf("context.device.os value %d", device.OS))
+		}
+	}
+	c.remarshal(device.SUA, &converted.SUA, "context.device.sua")
+	return converted
+}
+
+func convertGeo(geo *adcom1.Geo) *openrtb2.Geo {
+	if geo == nil {
+		return nil
+	}
+	converted := &openrtb2.Geo{
+		Type:      geo.Type,
+		Accuracy:  geo.Accur,
+		LastFix:   geo.LastFix,
+		IPService: geo.IPServ,
+		Country:   geo.Country,
+		Region:    geo.Region,
+		Metro:     geo.Metro,
+		City:      geo.City,
+		ZIP:       geo.ZIP,
+		UTCOffset: geo.UTCOffset,
+		Ext:       geo.Ext,
+	}
+	if geo.Lat != 0 || geo.Lon != 0 {
+		converted.Lat = &geo.Lat
+		converted.Lon = &geo.Lon
+	}
+	return converted
+}
+
+// remarshal copies objects which have the same json representation in AdCOM and OpenRTB 2 but are
+// declared as distinct types.
+func (c *converter30) remarshal(from, to interface{}, field string) {
+	raw, err := jsonutil.Marshal(from)
+	if err != nil || string(raw) == "null" {
+		return
+	}
+	if err := jsonutil.Unmarshal(raw, to); err != nil {
+		c.warnIfSet(true, field)
+	}
+}
+
+func (c *converter30) convertItem(item *openrtb3.Item, index int, battr []adcom1.CreativeAttribute) (openrtb2.Imp, error) {
+	field := fmt.Sprintf("request.item[%d]", index)
+	c.warnIfSet(item.Qty > 1, field+".qty")
+	c.warnIfSet(item.Seq != 0, field+".seq")
+	c.warnIfSet(item.DT != 0, field+".dt")
+	c.warnIfSet(item.Dlvy != 0, field+".dlvy")
+
+	imp := openrtb2.Imp{
+		ID:          item.ID,
+		BidFloor:    item.Flr,
+		BidFloorCur: item.FlrCur,
+		Exp:         item.Exp,
+		Ext:         item.Ext,
+	}
+
+	for _, metric := range item.Metric {
+		imp.Metric = append(imp.Metric, openrtb2.Metric{
+			Type:   metric.Type,
+			Value:  metric.Value,
+			Vendor: metric.Vendor,
+			Ext:    metric.Ext,
+		})
+	}
+
+	if len(item.Deal) > 0 || item.Private != 0 {
+		imp.PMP = &openrtb2.PMP{PrivateAuction: item.Private}
+		for i, deal := range item.Deal {
+			c.warnIfSet(len(deal.WADomain) > 0, fmt.Sprintf("%s.deal[%d].wadomain", field, i))
+			imp.PMP.Deals = append(imp.PMP.Deals, openrtb2.Deal{
+				ID:          deal.ID,
+				BidFloor:    deal.Flr,
+				BidFloorCur: deal.FlrCur,
+				AT:          int64(deal.AT),
+				WSeat:       deal.WSeat,
+				Ext:         deal.Ext,
+			})
+		}
+	}
+
+	if len(item.Spec) == 0 {
+		return imp, fmt.Errorf("%s.spec is required", field)
+	}
+	var spec adcom1.ItemSpec
+	if err := jsonutil.Unmarshal(item.Spec, &spec); err != nil {
+		return imp, fmt.Errorf("%s.spec is invalid: %v", field, err)
+	}
+	if spec.Placement == nil {
+		return imp, fmt.Errorf("%s.spec.placement is required", field)
+	}
+
+	placement := spec.Placement
+	field += ".spec.placement"
+	c.warnIfSet(placement.SDK != "", field+".sdk")
+	c.warnIfSet(placement.SDKVer != "", field+".sdkver")
+	c.warnIfSet(len(placement.WLang) > 0, field+".wlang")
+	c.warnIfSet(placement.AdMX != 0, field+".admx")
+	c.warnIfSet(placement.CURLX != 0, field+".curlx")
+	c.warnIfSet(len(placement.Ext) > 0, field+".ext")
+
+	imp.TagID = placement.TagID
+	imp.Rwdd = placement.Reward
+	imp.SSAI = openrtb2.AdInsertion(placement.SSAI)
+	if placement.Secure != 0 {
+		imp.Secure = ptrIfSet(placement.Secure)
+	}
+
+	if placement.Display != nil {
+		imp.Banner = c.convertDisplayPlacement(placement.Display, battr, field+".display")
+	}
+	if placement.Video != nil {
+		imp.Video = c.convertVideoPlacement(placement.Video, battr, field+".video")
+	}
+	if placement.Audio != nil {
+		imp.Audio = c.convertAudioPlacement(placement.Audio, battr, field+".audio")
+	}
+	if imp.Banner == nil && imp.Video == nil && imp.Audio == nil {
+		return imp, fmt.Errorf("%s must contain a supported display, video or audio placement", field)
+	}
+
+	return imp, nil
+}
+
+func (c *converter30) convertDisplayPlacement(display *adcom1.DisplayPlacement, battr []adcom1.CreativeAttribute, field string) *openrtb2.Banner {
+	c.warnIfSet(len(display.IfrBust) > 0, field+".ifrbust")
+	c.warnIfSet(display.ClkType != 0, field+".clktype")
+	c.warnIfSet(display.AMPRen != 0, field+".ampren")
+	c.warnIfSet(display.PType != 0, field+".ptype")
+	c.warnIfSet(display.Context != 0, field+".context")
+	c.warnIfSet(len(display.CType) > 0, field+".ctype")
+	c.warnIfSet(display.Unit > adcom1.SizeDIP, field+".unit")
+	c.warnIfSet(display.Priv != 0, field+".priv")
+	c.warnIfSet(display.NativeFmt != nil, field+".nativefmt")
+	c.warnIfSet(len(display.Event) > 0, field+".event")
+
+	if display.NativeFmt != nil && display.W == 0 && display.H == 0 && len(display.DisplayFmt) == 0 {
+		return nil
+	}
+
+	banner := &openrtb2.Banner{
+		BAttr:    battr,
+		Pos:      ptrIfSet(display.Pos),
+		MIMEs:    display.MIME,
+		TopFrame: display.TopFrame,
+		API:      display.API,
+		Ext:      display.Ext,
+	}
+	if display.W != 0 || display.H != 0 {
+		banner.W = ptrIfSet(display.W)
+		banner.H = ptrIfSet(display.H)
+	}
+	for i, format := range display.DisplayFmt {
+		c.warnIfSet(len(format.ExpDir) > 0, fmt.Sprintf("%s.displayfmt[%d].expdir", field, i))
+		banner.Format = append(banner.Format, openrtb2.Format{
+			W:      format.W,
+			H:      format.H,
+			WRatio: int64(format.WRatio),
+			HRatio: int64(format.HRatio),
+			Ext:    format.Ext,
+		})
+	}
+	return banner
+}
+
+func (c *converter30) convertVideoPlacement(video *adcom1.VideoPlacement, battr []adcom1.CreativeAttribute, field string) *openrtb2.Video {
+	c.warnIfSet(video.ClkType != 0, field+".clktype")
+	c.warnIfSet(video.Unit > adcom1.SizeDIP, field+".unit")
+	c.warnIfSet(len(video.Comp) > 0, field+".comp")
+
+	converted := &openrtb2.Video{
+		MIMEs:         video.MIME,
+		MinDuration:   video.MinDur,
+		MaxDuration:   video.MaxDur,
+		Protocols:     video.CType,
+		W:             ptrIfSet(video.W),
+		H:             ptrIfSet(video.H),
+		StartDelay:    ptrIfSet(video.Delay),
+		RqdDurs:       video.RqdDurs,
+		PodDur:        video.PodDur,
+		PodSeq:        video.PodSeq,
+		SlotInPod:     video.SlotInPod,
+		MinCPMPerSec:  video.MinCPMPerSec,
+		Placement:     video.PType,
+		Linearity:     video.Linear,
+		Skip:          ptrIfSet(video.Skip),
+		SkipMin:       video.SkipMin,
+		SkipAfter:     video.SkipAfter,
+		BAttr:         battr,
+		MaxExtended:   video.MaxExt,
+		MinBitRate:    video.MinBitR,
+		MaxBitRate:    video.MaxBitR,
+		BoxingAllowed: ptrIfSet(video.Boxing),
+		Delivery:      video.Delivery,
+		Pos:           ptrIfSet(video.Pos),
+		CompanionType: video.CompType,
+		API:           video.API,
+		Ext:           video.Ext,
+		PlaybackEnd:   video.PlayEnd,
+		MaxSeq:        video.MaxSeq,
+	}
+	if video.PodID != 0 {
+		converted.PodID = strconv.FormatInt(video.PodID, 10)
+	}
+	if video.PlayMethod != 0 {
+		converted.PlaybackMethod = []adcom1.PlaybackMethod{video.PlayMethod}
+	}
+	return converted
+}
+
+func (c *converter30) convertAudioPlacement(audio *adcom1.AudioPlacement, battr []adcom1.CreativeAttribute, field string) *openrtb2.Audio {
+	c.warnIfSet(audio.Skip != 0, field+".skip")
+	c.warnIfSet(audio.SkipMin != 0, field+".skipmin")
+	c.warnIfSet(audio.SkipAfter != 0, field+".skipafter")
+	c.warnIfSet(audio.PlayMethod != 0, field+".playmethod")
+	c.warnIfSet(audio.PlayEnd != 0, field+".playend")
+	c.warnIfSet(len(audio.Comp) > 0, field+".comp")
+	c.warnIfSet(len(audio.OverlayExpDir) > 0, field+".overlayexpdir")
+
+	converted := &openrtb2.Audio{
+		MIMEs:         audio.MIME,
+		MinDuration:   audio.MinDur,
+		MaxDuration:   audio.MaxDur,
+		PodDur:        audio.PodDur,
+		Protocols:     audio.CType,
+		StartDelay:    ptrIfSet(audio.Delay),
+		RqdDurs:       audio.RqdDurs,
+		PodSeq:        audio.PodSeq,
+		SlotInPod:     audio.SlotInPod,
+		MinCPMPerSec:  audio.MinCPMPerSec,
+		BAttr:         battr,
+		MaxExtended:   audio.MaxExt,
+		MinBitrate:    audio.MinBitR,
+		MaxBitrate:    audio.MaxBitR,
+		Delivery:      audio.Delivery,
+		CompanionType: audio.CompType,
+		API:           audio.API,
+		Feed:          audio.Feed,
+		NVol:          ptrIfSet(audio.NVol),
+		MaxSeq:        audio.MaxSeq,
+		Ext:           audio.Ext,
+	}
+	if audio.PodID != 0 {
+		converted.PodID = strconv.FormatInt(audio.PodID, 10)
+	}
+	return converted
+}
+
+func convertBidTo30(bid *openrtb2.Bid) (openrtb3.Bid, error) {
+	ad := adcom1.Ad{
+		ID:      bid.CrID,
+		ADomain: bid.ADomain,
+		IURL:    bid.IURL,
+		Cat:     bid.Cat,
+		CatTax:  bid.CatTax,
+		Lang:    bid.Language,
+		Attr:    bid.Attr,
+		MRating: bid.QAGMediaRating,
+	}
+	if ad.ID == "" {
+		ad.ID = bid.AdID
+	}
+	if bid.Bundle != "" {
+		ad.Bundle = []string{bid.Bundle}
+	}
+
+	switch bidTypeOf(bid) {
+	case BidTypeVideo:
+		ad.Video = &adcom1.Video{CType: bid.Protocol, Dur: bid.Dur, AdM: bid.AdM}
+	case BidTypeAudio:
+		ad.Audio = &adcom1.Audio{CType: bid.Protocol, Dur: bid.Dur, AdM: bid.AdM}
+	default:
+		ad.Display = &adcom1.Display{
+			W:      bid.W,
+			H:      bid.H,
+			WRatio: int8(bid.WRatio),
+			HRatio: int8(bid.HRatio),
+			AdM:    bid.AdM,
+		}
+		if bid.API != 0 {
+			ad.Display.API = []adcom1.APIFramework{bid.API}
+		}
+	}
+
+	media, err := jsonutil.Marshal(ad)
+	if err != nil {
+		return openrtb3.Bid{}, fmt.Errorf("failed to marshal media for bid %s: %v", bid.ID, err)
+	}
+
+	return openrtb3.Bid{
+		ID:     bid.ID,
+		Item:   bid.ImpID,
+		Price:  bid.Price,
+		Deal:   bid.DealID,
+		CID:    bid.CID,
+		Tactic: bid.Tactic,
+		PURL:   bid.NURL,
+		BURL:   bid.BURL,
+		LURL:   bid.LURL,
+		Exp:    bid.Exp,
+		Media:  media,
+		Ext:    bid.Ext,
+	}, nil
+}
+
+// bidTypeOf prefers the OpenRTB 2.6 markup type and falls back to the type Prebid Server adds to the bid ext.
+func bidTypeOf(bid *openrtb2.Bid) BidType {
+	switch bid.MType {
+	case openrtb2.MarkupBanner:
+		return BidTypeBanner
+	case openrtb2.MarkupVideo:
+		return BidTypeVideo
+	case openrtb2.MarkupAudio:
+		return BidTypeAudio
+	case openrtb2.MarkupNative:
+		return BidTypeNative
+	}
+
+	var ext ExtBid
+	if len(bid.Ext) > 0 && jsonutil.Unmarshal(bid.Ext, &ext) == nil && ext.Prebid != nil {
+		return ext.Prebid.Type
+	}
+	return BidTypeBanner
+}
+
+func addGeneralWarnings(ext json.RawMessage, warnings []error) (json.RawMessage, error) {
+	if len(warnings) == 0 {
+		return ext, nil
+	}
+
+	// the ext is kept as raw json so fields unknown to ExtBidResponse survive the round trip
+	responseExt := make(map[string]json.RawMessage)
+	if len(ext) > 0 {
+		if err := jsonutil.Unmarshal(ext, &responseExt); err != nil {
+			return nil, fmt.Errorf("failed to read response ext: %v", err)
+		}
+	}
+
+	responseWarnings := make(map[BidderName][]ExtBidderMessage)
+	if raw, ok := responseExt["warnings"]; ok {
+		if err := jsonutil.Unmarshal(raw, &responseWarnings); err != nil {
+			return nil, fmt.Errorf("failed to read response ext warnings: %v", err)
+		}
+	}
+	for _, warning := range warnings {
+		responseWarnings[BidderReservedGeneral] = append(responseWarnings[BidderReservedGeneral], ExtBidderMessage{
+			Code:    errortypes.ReadCode(warning),
+			Message: warning.Error(),
+		})
+	}
+
+	raw, err := jsonutil.Marshal(responseWarnings)
+	if err != nil {
+		return nil, err
+	}
+	responseExt["warnings"] = raw
+	return jsonutil.Marshal(responseExt)
+}
+
+// ptrIfSet returns nil for zero values, which AdCOM can't distinguish from absent fields.
+func ptrIfSet[T comparable](v T) *T {
+	var zero T
+	if v == zero {
+		return nil
+	}
+	return &v
+}
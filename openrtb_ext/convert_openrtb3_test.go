@@ -0,0 +1,203 @@
+package openrtb_ext
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertRequestFrom30(t *testing.T) {
+	testCases := []struct {
+		name             string
+		givenBody        openrtb3.Body
+		expectedRequest  *openrtb2.BidRequest
+		expectedWarnings []string
+		expectedErr      string
+	}{
+		{
+			name: "video-item-with-context",
+			givenBody: openrtb3.Body{OpenRTB: openrtb3.OpenRTB{
+				Ver:        "3.0",
+				DomainSpec: "adcom",
+				Request: &openrtb3.Request{
+					ID:    "req1",
+					TMax:  500,
+					Cur:   []string{"USD"},
+					Seat:  []string{"seat1"},
+					WSeat: 1,
+					Item: []openrtb3.Item{{
+						ID:     "item1",
+						Flr:    1.5,
+						FlrCur: "USD",
+						Deal:   []openrtb3.Deal{{ID: "deal1", Flr: 2}},
+						Spec:   json.RawMessage(`{"placement":{"tagid":"tag1","secure":1,"video":{"mime":["video/mp4"],"mindur":5,"maxdur":30,"w":1920,"h":1080,"podid":7}}}`),
+						Ext:    json.RawMessage(`{"prebid":{"bidder":{"appnexus":{"placementId":1}}}}`),
+					}},
+					Context: json.RawMessage(`{"app":{"id":"app1","bundle":"com.tv","pub":{"id":"pub1"}},"device":{"type":3,"os":2,"ip":"1.2.3.4"},"regs":{"gdpr":1},"restrictions":{"badv":["bad.com"],"battr":[1]}}`),
+				},
+			}},
+			expectedRequest: &openrtb2.BidRequest{
+				ID:    "req1",
+				TMax:  500,
+				Cur:   []string{"USD"},
+				WSeat: []string{"seat1"},
+				Imp: []openrtb2.Imp{{
+					ID:          "item1",
+					TagID:       "tag1",
+					Secure:      ptrutil.ToPtr[int8](1),
+					BidFloor:    1.5,
+					BidFloorCur: "USD",
+					PMP:         &openrtb2.PMP{Deals: []openrtb2.Deal{{ID: "deal1", BidFloor: 2}}},
+					Video: &openrtb2.Video{
+						MIMEs:       []string{"video/mp4"},
+						MinDuration: 5,
+						MaxDuration: 30,
+						W:           ptrutil.ToPtr[int64](1920),
+						H:           ptrutil.ToPtr[int64](1080),
+						PodID:       "7",
+						BAttr:       []adcom1.CreativeAttribute{1},
+					},
+					Ext: json.RawMessage(`{"prebid":{"bidder":{"appnexus":{"placementId":1}}}}`),
+				}},
+				App:    &openrtb2.App{ID: "app1", Bundle: "com.tv", Publisher: &openrtb2.Publisher{ID: "pub1"}},
+				Device: &openrtb2.Device{DeviceType: adcom1.DeviceTV, OS: "android", IP: "1.2.3.4"},
+				Regs:   &openrtb2.Regs{GDPR: ptrutil.ToPtr[int8](1)},
+				BAdv:   []string{"bad.com"},
+			},
+		},
+		{
+			name: "display-item-with-unmappable-fields",
+			givenBody: openrtb3.Body{OpenRTB: openrtb3.OpenRTB{
+				Request: &openrtb3.Request{
+					ID:    "req1",
+					Seat:  []string{"seat1"},
+					CData: "data",
+					Item: []openrtb3.Item{{
+						ID:   "item1",
+						Qty:  2,
+						Spec: json.RawMessage(`{"placement":{"sdk":"sdk1","display":{"w":300,"h":250,"displayfmt":[{"w":300,"h":250},{"w":320,"h":50}]}}}`),
+					}},
+				},
+			}},
+			expectedRequest: &openrtb2.BidRequest{
+				ID:    "req1",
+				BSeat: []string{"seat1"},
+				Imp: []openrtb2.Imp{{
+					ID: "item1",
+					Banner: &openrtb2.Banner{
+						W:      ptrutil.ToPtr[int64](300),
+						H:      ptrutil.ToPtr[int64](250),
+						Format: []openrtb2.Format{{W: 300, H: 250}, {W: 320, H: 50}},
+					},
+				}},
+			},
+			expectedWarnings: []string{
+				"request.cdata has no OpenRTB 2 equivalent and was ignored",
+				"request.item[0].qty has no OpenRTB 2 equivalent and was ignored",
+				"request.item[0].spec.placement.sdk has no OpenRTB 2 equivalent and was ignored",
+			},
+		},
+		{
+			name:        "missing-request",
+			givenBody:   openrtb3.Body{},
+			expectedErr: "openrtb.request is required",
+		},
+		{
+			name: "unsupported-domain-spec",
+			givenBody: openrtb3.Body{OpenRTB: openrtb3.OpenRTB{
+				DomainSpec: "other",
+				Request:    &openrtb3.Request{ID: "req1"},
+			}},
+			expectedErr: `openrtb.domainspec "other" is not supported, only "adcom" can be translated`,
+		},
+		{
+			name: "no-items",
+			givenBody: openrtb3.Body{OpenRTB: openrtb3.OpenRTB{
+				Request: &openrtb3.Request{ID: "req1"},
+			}},
+			expectedErr: "request.item must contain at least one item",
+		},
+		{
+			name: "item-without-placement",
+			givenBody: openrtb3.Body{OpenRTB: openrtb3.OpenRTB{
+				Request: &openrtb3.Request{ID: "req1", Item: []openrtb3.Item{{ID: "item1", Spec: json.RawMessage(`{}`)}}},
+			}},
+			expectedErr: "request.item[0].spec.placement is required",
+		},
+		{
+			name: "native-only-placement",
+			givenBody: openrtb3.Body{OpenRTB: openrtb3.OpenRTB{
+				Request: &openrtb3.Request{ID: "req1", Item: []openrtb3.Item{{ID: "item1", Spec: json.RawMessage(`{"placement":{"display":{"nativefmt":{}}}}`)}}},
+			}},
+			expectedErr: "request.item[0].spec.placement must contain a supported display, video or audio placement",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			request, warnings, err := ConvertRequestFrom30(test.givenBody)
+			if len(test.expectedErr) > 0 {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedRequest, request)
+
+			messages := make([]string, 0, len(warnings))
+			for _, warning := range warnings {
+				assert.Equal(t, errortypes.OpenRTB3ConversionWarningCode, errortypes.ReadCode(warning))
+				messages = append(messages, warning.Error())
+			}
+			assert.ElementsMatch(t, test.expectedWarnings, messages)
+		})
+	}
+}
+
+func TestConvertResponseTo30(t *testing.T) {
+	givenResponse := &openrtb2.BidResponse{
+		ID:  "req1",
+		Cur: "USD",
+		SeatBid: []openrtb2.SeatBid{{
+			Seat: "appnexus",
+			Bid: []openrtb2.Bid{
+				{ID: "bid1", ImpID: "item1", Price: 2, NURL: "https://win", CrID: "cr1", ADomain: []string{"adv.com"}, AdM: "<VAST/>", Dur: 30, MType: openrtb2.MarkupVideo},
+				{ID: "bid2", ImpID: "item2", Price: 1, AdM: "<div/>", W: 300, H: 250, Ext: json.RawMessage(`{"prebid":{"type":"banner"}}`)},
+			},
+		}},
+		Ext: json.RawMessage(`{"responsetimemillis":{"appnexus":10},"warnings":{"appnexus":[{"code":1,"message":"bidder warning"}]}}`),
+	}
+	givenWarnings := []error{&errortypes.Warning{WarningCode: errortypes.OpenRTB3ConversionWarningCode, Message: "request.cdata has no OpenRTB 2 equivalent and was ignored"}}
+
+	body, err := ConvertResponseTo30(givenResponse, givenWarnings)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "3.0", body.OpenRTB.Ver)
+	assert.Equal(t, AdCOMDomainSpec, body.OpenRTB.DomainSpec)
+	response := body.OpenRTB.Response
+	if !assert.NotNil(t, response) {
+		return
+	}
+	assert.Equal(t, "req1", response.ID)
+	assert.Equal(t, "USD", response.Cur)
+	if !assert.Len(t, response.SeatBid, 1) || !assert.Len(t, response.SeatBid[0].Bid, 2) {
+		return
+	}
+
+	videoBid := response.SeatBid[0].Bid[0]
+	assert.Equal(t, "item1", videoBid.Item)
+	assert.Equal(t, "https://win", videoBid.PURL)
+	assert.JSONEq(t, `{"id":"cr1","adomain":["adv.com"],"video":{"dur":30,"adm":"<VAST/>"}}`, string(videoBid.Media))
+
+	bannerBid := response.SeatBid[0].Bid[1]
+	assert.Equal(t, "item2", bannerBid.Item)
+	assert.JSONEq(t, `{"id":"","display":{"w":300,"h":250,"adm":"<div/>"}}`, string(bannerBid.Media))
+
+	expectedCode, _ := json.Marshal(errortypes.OpenRTB3ConversionWarningCode)
+	assert.JSONEq(t, `{"responsetimemillis":{"appnexus":10},"warnings":{"appnexus":[{"code":1,"message":"bidder warning"}],"general":[{"code":`+string(expectedCode)+`,"message":"request.cdata has no OpenRTB 2 equivalent and was ignored"}]}}`, string(response.Ext))
+}
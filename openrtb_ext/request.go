@@ -2,6 +2,7 @@ package openrtb_ext
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"maps"
 	"slices"
@@ -66,8 +67,11 @@ type ExtRequestPrebid struct {
 	Sdk                  *ExtRequestSdk                  `json:"sdk,omitempty"`
 	Server               *ExtRequestPrebidServer         `json:"server,omitempty"`
 	StoredRequest        *ExtStoredRequest               `json:"storedrequest,omitempty"`
-	SupportDeals         bool                            `json:"supportdeals,omitempty"`
-	Targeting            *ExtRequestTargeting            `json:"targeting,omitempty"`
+	// StoredResponseRecording asks to record the bids of the auction as stored responses. It is only honored when
+	// Prebid Server is configured for recording and the request carries the debug override header.
+	StoredResponseRecording *ExtStoredResponseRecording `json:"storedresponserecording,omitempty"`
+	SupportDeals            bool                        `json:"supportdeals,omitempty"`
+	Targeting               *ExtRequestTargeting        `json:"targeting,omitempty"`
 
 	//AlternateBidderCodes is populated with host's AlternateBidderCodes config if not defined in request
 	AlternateBidderCodes *ExtAlternateBidderCodes `json:"alternatebiddercodes,omitempty"`
@@ -85,6 +89,10 @@ type ExtRequestPrebid struct {
 	// either rejected, nobid, input error
 	ReturnAllBidStatus bool `json:"returnallbidstatus,omitempty"`
 
+	// NativeRender asks to render native bids to HTML with the account native render template, for environments
+	// which can't render native. AMP requests are rendered whenever the account has a template.
+	NativeRender bool `json:"nativerender,omitempty"`
+
 	// Trace controls the level of detail in the output information returned from executing hooks.
 	// There are two options:
 	// - verbose: sets maximum level of output information
@@ -95,6 +103,13 @@ type ExtRequestPrebid struct {
 	BidderControls map[BidderName]BidderControl `json:"biddercontrols,omitempty"`
 }
 
+// ExtStoredResponseRecording defines the contract for bidrequest.ext.prebid.storedresponserecording
+type ExtStoredResponseRecording struct {
+	// ID is the stored response ID of the recorded auction response, and prefixes the IDs of the stored
+	// auction and stored bid responses recorded for each imp.
+	ID string `json:"id"`
+}
+
 type AdServerTarget struct {
 	Key    string `json:"key,omitempty"`
 	Source string `json:"source,omitempty"`
@@ -171,6 +186,40 @@ type ExtRequestPrebidBidAdjustments struct {
 	MediaType MediaType `mapstructure:"mediatype" json:"mediatype,omitempty"`
 }
 
+// DeepCopy returns a copy of the bid adjustments that shares no maps or slices with the original.
+func (ba *ExtRequestPrebidBidAdjustments) DeepCopy() *ExtRequestPrebidBidAdjustments {
+	if ba == nil {
+		return nil
+	}
+
+	return &ExtRequestPrebidBidAdjustments{
+		MediaType: MediaType{
+			Banner:         deepCopyAdjustments(ba.MediaType.Banner),
+			VideoInstream:  deepCopyAdjustments(ba.MediaType.VideoInstream),
+			VideoOutstream: deepCopyAdjustments(ba.MediaType.VideoOutstream),
+			Audio:          deepCopyAdjustments(ba.MediaType.Audio),
+			Native:         deepCopyAdjustments(ba.MediaType.Native),
+			WildCard:       deepCopyAdjustments(ba.MediaType.WildCard),
+		},
+	}
+}
+
+func deepCopyAdjustments(adjustments map[BidderName]AdjustmentsByDealID) map[BidderName]AdjustmentsByDealID {
+	if adjustments == nil {
+		return nil
+	}
+
+	newAdjustments := make(map[BidderName]AdjustmentsByDealID, len(adjustments))
+	for bidder, adjustmentsByDealID := range adjustments {
+		newAdjustmentsByDealID := make(AdjustmentsByDealID, len(adjustmentsByDealID))
+		for dealID, dealAdjustments := range adjustmentsByDealID {
+			newAdjustmentsByDealID[dealID] = slices.Clone(dealAdjustments)
+		}
+		newAdjustments[bidder] = newAdjustmentsByDealID
+	}
+	return newAdjustments
+}
+
 // AdjustmentsByDealID maps a dealID to a slice of bid adjustments
 type AdjustmentsByDealID map[string][]Adjustment
 
@@ -257,6 +306,30 @@ func (pg *PriceGranularity) UnmarshalJSON(b []byte) error {
 	return err
 }
 
+// ValidatePriceGranularity checks the precision and that the ranges are ordered with positive increments.
+func ValidatePriceGranularity(pg *PriceGranularity) error {
+	if pg.Precision == nil {
+		return errors.New("Price granularity error: precision is required")
+	} else if *pg.Precision < 0 {
+		return errors.New("Price granularity error: precision must be non-negative")
+	} else if *pg.Precision > MaxDecimalFigures {
+		return fmt.Errorf("Price granularity error: precision of more than %d significant figures is not supported", MaxDecimalFigures)
+	}
+
+	var prevMax float64 = 0
+	for _, gr := range pg.Ranges {
+		if gr.Max <= prevMax {
+			return errors.New(`Price granularity error: range list must be ordered with increasing "max"`)
+		}
+
+		if gr.Increment <= 0.0 {
+			return errors.New("Price granularity error: increment must be a nonzero positive number")
+		}
+		prevMax = gr.Max
+	}
+	return nil
+}
+
 func NewPriceGranularityDefault() PriceGranularity {
 	pg, _ := NewPriceGranularityFromLegacyID("medium")
 	return pg
@@ -488,6 +561,8 @@ func (erp *ExtRequestPrebid) Clone() *ExtRequestPrebid {
 
 	clone.StoredRequest = ptrutil.Clone(erp.StoredRequest)
 
+	clone.StoredResponseRecording = ptrutil.Clone(erp.StoredResponseRecording)
+
 	if erp.Targeting != nil {
 		newTargeting := &ExtRequestTargeting{
 			IncludeFormat:     erp.Targeting.IncludeFormat,
@@ -2,6 +2,7 @@ package openrtb_ext
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -502,6 +503,22 @@ func TestCloneExtRequestPrebid(t *testing.T) {
 				prebid.StoredRequest = &ExtStoredRequest{ID: "ID"}
 			},
 		},
+		{
+			name: "StoredResponseRecording",
+			prebid: &ExtRequestPrebid{
+				StoredResponseRecording: &ExtStoredResponseRecording{
+					ID: "rec-1",
+				},
+			},
+			prebidCopy: &ExtRequestPrebid{
+				StoredResponseRecording: &ExtStoredResponseRecording{
+					ID: "rec-1",
+				},
+			},
+			mutator: func(t *testing.T, prebid *ExtRequestPrebid) {
+				prebid.StoredResponseRecording.ID = "rec-2"
+			},
+		},
 		{
 			name: "Targeting",
 			prebid: &ExtRequestPrebid{
@@ -777,3 +794,99 @@ func TestCloneExtRequestPrebid(t *testing.T) {
 	}
 
 }
+
+func TestValidatePriceGranularity(t *testing.T) {
+	testCases := []struct {
+		description           string
+		givenPriceGranularity *PriceGranularity
+		expectedError         error
+	}{
+		{
+			description: "Precision is nil",
+			givenPriceGranularity: &PriceGranularity{
+				Precision: nil,
+			},
+			expectedError: errors.New("Price granularity error: precision is required"),
+		},
+		{
+			description: "Precision is negative",
+			givenPriceGranularity: &PriceGranularity{
+				Precision: ptrutil.ToPtr(-1),
+			},
+			expectedError: errors.New("Price granularity error: precision must be non-negative"),
+		},
+		{
+			description: "Precision is too big",
+			givenPriceGranularity: &PriceGranularity{
+				Precision: ptrutil.ToPtr(20),
+			},
+			expectedError: errors.New("Price granularity error: precision of more than 15 significant figures is not supported"),
+		},
+		{
+			description: "price granularity ranges out of order",
+			givenPriceGranularity: &PriceGranularity{
+				Precision: ptrutil.ToPtr(2),
+				Ranges: []GranularityRange{
+					{Min: 1.0, Max: 2.0, Increment: 0.2},
+					{Min: 0.0, Max: 1.0, Increment: 0.5},
+				},
+			},
+			expectedError: errors.New(`Price granularity error: range list must be ordered with increasing "max"`),
+		},
+		{
+			description: "price granularity negative increment",
+			givenPriceGranularity: &PriceGranularity{
+				Precision: ptrutil.ToPtr(2),
+				Ranges: []GranularityRange{
+					{Min: 0.0, Max: 1.0, Increment: -0.1},
+				},
+			},
+			expectedError: errors.New("Price granularity error: increment must be a nonzero positive number"),
+		},
+		{
+			description: "price granularity correct",
+			givenPriceGranularity: &PriceGranularity{
+				Precision: ptrutil.ToPtr(2),
+				Ranges: []GranularityRange{
+					{Min: 0.0, Max: 10.0, Increment: 1},
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			description: "price granularity with correct precision and ranges not specified",
+			givenPriceGranularity: &PriceGranularity{
+				Precision: ptrutil.ToPtr(2),
+			},
+			expectedError: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			assert.Equal(t, tc.expectedError, ValidatePriceGranularity(tc.givenPriceGranularity))
+		})
+	}
+}
+
+func TestBidAdjustmentsDeepCopy(t *testing.T) {
+	var nilBidAdjustments *ExtRequestPrebidBidAdjustments
+	assert.Nil(t, nilBidAdjustments.DeepCopy())
+
+	original := &ExtRequestPrebidBidAdjustments{
+		MediaType: MediaType{
+			Banner:   map[BidderName]AdjustmentsByDealID{"bidderA": {"*": []Adjustment{{Type: "multiplier", Value: 1.1}}}},
+			WildCard: map[BidderName]AdjustmentsByDealID{"*": {"deal": []Adjustment{{Type: "cpm", Value: 0.1, Currency: "USD"}}}},
+		},
+	}
+
+	copied := original.DeepCopy()
+	assert.Equal(t, original, copied)
+
+	copied.MediaType.Banner["bidderA"]["*"][0].Value = 2
+	copied.MediaType.Banner["bidderB"] = AdjustmentsByDealID{}
+	copied.MediaType.WildCard["*"]["other"] = nil
+	assert.Equal(t, 1.1, original.MediaType.Banner["bidderA"]["*"][0].Value)
+	assert.NotContains(t, original.MediaType.Banner, BidderName("bidderB"))
+	assert.NotContains(t, original.MediaType.WildCard["*"], "other")
+}
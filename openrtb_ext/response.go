@@ -49,6 +49,27 @@ type ExtResponsePrebid struct {
 	Targeting        map[string]string `json:"targeting,omitempty"`
 	// SeatNonBid holds the array of Bids which are either rejected, no bids inside bidresponse.ext.prebid.seatnonbid
 	SeatNonBid []SeatNonBid `json:"seatnonbid,omitempty"`
+	// LineItems holds the account line items which targeted the request and what happened to them
+	LineItems []ExtResponseLineItem `json:"lineitems,omitempty"`
+	// Debug holds the explanations of the auction decisions, when debug is allowed
+	Debug *ExtResponsePrebidDebug `json:"debug,omitempty"`
+}
+
+// ExtResponsePrebidDebug defines the contract for bidresponse.ext.prebid.debug
+type ExtResponsePrebidDebug struct {
+	// TCF explains the TCF enforcement decision of each bidder
+	TCF json.RawMessage `json:"tcf,omitempty"`
+	// Privacy holds the activity control evaluations and the rules which decided them
+	Privacy json.RawMessage `json:"privacy,omitempty"`
+}
+
+// ExtResponseLineItem defines the contract for bidresponse.ext.prebid.lineitems[i]
+type ExtResponseLineItem struct {
+	ID     string `json:"id"`
+	Source string `json:"source"`
+	DealID string `json:"dealid"`
+	ImpID  string `json:"impid"`
+	Status string `json:"status"`
 }
 
 // FledgeResponse defines the contract for bidresponse.ext.fledge
@@ -93,6 +93,14 @@ func setDefaultsTargeting(targeting *openrtb_ext.ExtRequestTargeting) bool {
 	return modified
 }
 
+// SetDefaultsPriceGranularity returns the price granularity with the defaults of the request ones: the default
+// precision and the minimum of each range set to the maximum of the previous one. A price granularity without
+// ranges is replaced with the default one.
+func SetDefaultsPriceGranularity(pg *openrtb_ext.PriceGranularity) *openrtb_ext.PriceGranularity {
+	pg, _ = setDefaultsPriceGranularity(pg)
+	return pg
+}
+
 func setDefaultsPriceGranularity(pg *openrtb_ext.PriceGranularity) (*openrtb_ext.PriceGranularity, bool) {
 	if pg == nil || len(pg.Ranges) == 0 {
 		pg = ptrutil.ToPtr(openrtb_ext.NewPriceGranularityDefault())
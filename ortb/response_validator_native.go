@@ -0,0 +1,166 @@
+package ortb
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	nativeRequests "github.com/prebid/openrtb/v20/native1/request"
+	nativeResponse "github.com/prebid/openrtb/v20/native1/response"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// nativeAspectRatioTolerance is the relative difference allowed between an image's aspect ratio and the requested one,
+// so images scaled to whole pixels still match.
+const nativeAspectRatioTolerance = 0.01
+
+// nativeImageExt is the request image extension Prebid.js sets when the ad unit asks for aspect ratios.
+type nativeImageExt struct {
+	AspectRatios []string `json:"aspectratios"`
+}
+
+// ValidateNativeResponse checks a native response against the native request of its imp: required assets must be
+// present, titles and data must fit the requested length, images must meet the requested minimum size and aspect
+// ratio, and event trackers must use an event and method listed by the request. Assets served through assetsurl
+// or dcourl can't be checked, so only their event trackers are.
+func ValidateNativeResponse(request nativeRequests.Request, response nativeResponse.Response) error {
+	inlineAssets := response.AssetsURL == "" && response.DCOURL == ""
+
+	responseAssets := make(map[int64]nativeResponse.Asset, len(response.Assets))
+	for _, asset := range response.Assets {
+		if asset.ID == nil {
+			return errors.New("native response asset is missing an id")
+		}
+		responseAssets[*asset.ID] = asset
+	}
+
+	requestAssets := make(map[int64]struct{}, len(request.Assets))
+	for _, requestAsset := range request.Assets {
+		requestAssets[requestAsset.ID] = struct{}{}
+
+		responseAsset, found := responseAssets[requestAsset.ID]
+		if !found {
+			if requestAsset.Required == 1 && inlineAssets {
+				return fmt.Errorf("native response is missing required asset %d", requestAsset.ID)
+			}
+			continue
+		}
+		if err := validateNativeResponseAsset(requestAsset, responseAsset); err != nil {
+			return err
+		}
+	}
+
+	for _, asset := range response.Assets {
+		if _, found := requestAssets[*asset.ID]; !found {
+			return fmt.Errorf("native response asset %d was not requested", *asset.ID)
+		}
+	}
+
+	return validateNativeResponseEventTrackers(request.EventTrackers, response.EventTrackers)
+}
+
+func validateNativeResponseAsset(requestAsset nativeRequests.Asset, responseAsset nativeResponse.Asset) error {
+	switch {
+	case requestAsset.Title != nil:
+		if responseAsset.Title == nil {
+			return fmt.Errorf("native response asset %d must be a title", requestAsset.ID)
+		}
+		if requestAsset.Title.Len > 0 && int64(utf8.RuneCountInString(responseAsset.Title.Text)) > requestAsset.Title.Len {
+			return fmt.Errorf("native response asset %d title is longer than %d characters", requestAsset.ID, requestAsset.Title.Len)
+		}
+	case requestAsset.Img != nil:
+		if responseAsset.Img == nil {
+			return fmt.Errorf("native response asset %d must be an image", requestAsset.ID)
+		}
+		return validateNativeResponseImage(requestAsset.ID, requestAsset.Img, responseAsset.Img)
+	case requestAsset.Video != nil:
+		if responseAsset.Video == nil {
+			return fmt.Errorf("native response asset %d must be a video", requestAsset.ID)
+		}
+	case requestAsset.Data != nil:
+		if responseAsset.Data == nil {
+			return fmt.Errorf("native response asset %d must be data", requestAsset.ID)
+		}
+		if requestAsset.Data.Len > 0 && int64(utf8.RuneCountInString(responseAsset.Data.Value)) > requestAsset.Data.Len {
+			return fmt.Errorf("native response asset %d data is longer than %d characters", requestAsset.ID, requestAsset.Data.Len)
+		}
+	}
+	return nil
+}
+
+// validateNativeResponseImage checks the image size when the response states it. Requested minimums are enforced,
+// while an exact requested size, or the aspect ratios in the image extension, constrain the aspect ratio so images
+// may be scaled.
+func validateNativeResponseImage(id int64, requestImage *nativeRequests.Image, responseImage *nativeResponse.Image) error {
+	if responseImage.W == 0 || responseImage.H == 0 {
+		return nil
+	}
+
+	if responseImage.W < requestImage.WMin || responseImage.H < requestImage.HMin {
+		return fmt.Errorf("native response asset %d image %dx%d is smaller than the minimum %dx%d", id, responseImage.W, responseImage.H, requestImage.WMin, requestImage.HMin)
+	}
+
+	var ratios []string
+	if len(requestImage.Ext) > 0 {
+		var imageExt nativeImageExt
+		if err := jsonutil.Unmarshal(requestImage.Ext, &imageExt); err == nil {
+			ratios = imageExt.AspectRatios
+		}
+	}
+	if len(ratios) == 0 && requestImage.W > 0 && requestImage.H > 0 {
+		ratios = []string{fmt.Sprintf("%d:%d", requestImage.W, requestImage.H)}
+	}
+	if len(ratios) == 0 {
+		return nil
+	}
+
+	for _, ratio := range ratios {
+		if matchesAspectRatio(ratio, responseImage.W, responseImage.H) {
+			return nil
+		}
+	}
+	return fmt.Errorf("native response asset %d image %dx%d doesn't match the aspect ratios %s", id, responseImage.W, responseImage.H, strings.Join(ratios, ", "))
+}
+
+// matchesAspectRatio reports if the size matches a ratio formatted as "{width}:{height}". Malformed ratios match
+// nothing.
+func matchesAspectRatio(ratio string, w, h int64) bool {
+	ratioW, ratioH, found := strings.Cut(ratio, ":")
+	if !found {
+		return false
+	}
+	rw, errW := strconv.ParseFloat(ratioW, 64)
+	rh, errH := strconv.ParseFloat(ratioH, 64)
+	if errW != nil || errH != nil || rw <= 0 || rh <= 0 {
+		return false
+	}
+
+	expected := rw / rh
+	actual := float64(w) / float64(h)
+	return math.Abs(actual-expected) <= expected*nativeAspectRatioTolerance
+}
+
+// validateNativeResponseEventTrackers checks the event trackers when the request lists the ones it supports.
+func validateNativeResponseEventTrackers(requestTrackers []nativeRequests.EventTracker, responseTrackers []nativeResponse.EventTracker) error {
+	if len(requestTrackers) == 0 {
+		return nil
+	}
+
+	for _, responseTracker := range responseTrackers {
+		supported := false
+		for _, requestTracker := range requestTrackers {
+			if requestTracker.Event == responseTracker.Event && slices.Contains(requestTracker.Methods, responseTracker.Method) {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return fmt.Errorf("native response event tracker with event %d and method %d was not requested", responseTracker.Event, responseTracker.Method)
+		}
+	}
+	return nil
+}
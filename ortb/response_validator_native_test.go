@@ -0,0 +1,153 @@
+package ortb
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/native1"
+	nativeRequests "github.com/prebid/openrtb/v20/native1/request"
+	nativeResponse "github.com/prebid/openrtb/v20/native1/response"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateNativeResponse(t *testing.T) {
+	request := nativeRequests.Request{
+		Assets: []nativeRequests.Asset{
+			{ID: 1, Required: 1, Title: &nativeRequests.Title{Len: 10}},
+			{ID: 2, Required: 1, Img: &nativeRequests.Image{Type: native1.ImageAssetTypeMain, WMin: 100, HMin: 50, Ext: json.RawMessage(`{"aspectratios":["2:1","1:1"]}`)}},
+			{ID: 3, Img: &nativeRequests.Image{Type: native1.ImageAssetTypeIcon, W: 50, H: 50}},
+			{ID: 4, Data: &nativeRequests.Data{Type: native1.DataAssetTypeDesc, Len: 5}},
+		},
+		EventTrackers: []nativeRequests.EventTracker{
+			{Event: native1.EventTypeImpression, Methods: []native1.EventTrackingMethod{native1.EventTrackingMethodImage}},
+		},
+	}
+	title := nativeResponse.Asset{ID: ptrutil.ToPtr[int64](1), Title: &nativeResponse.Title{Text: "Buy now"}}
+	image := nativeResponse.Asset{ID: ptrutil.ToPtr[int64](2), Img: &nativeResponse.Image{URL: "https://img", W: 200, H: 100}}
+
+	testCases := []struct {
+		description   string
+		response      nativeResponse.Response
+		expectedError string
+	}{
+		{
+			description: "valid",
+			response: nativeResponse.Response{
+				Assets: []nativeResponse.Asset{
+					title,
+					image,
+					{ID: ptrutil.ToPtr[int64](3), Img: &nativeResponse.Image{URL: "https://icon", W: 100, H: 100}},
+					{ID: ptrutil.ToPtr[int64](4), Data: &nativeResponse.Data{Value: "Short"}},
+				},
+				EventTrackers: []nativeResponse.EventTracker{{Event: native1.EventTypeImpression, Method: native1.EventTrackingMethodImage, URL: "https://imp"}},
+			},
+		},
+		{
+			description: "image_size_unknown",
+			response: nativeResponse.Response{Assets: []nativeResponse.Asset{
+				title,
+				{ID: ptrutil.ToPtr[int64](2), Img: &nativeResponse.Image{URL: "https://img"}},
+			}},
+		},
+		{
+			description: "assets_url",
+			response:    nativeResponse.Response{AssetsURL: "https://assets"},
+		},
+		{
+			description:   "missing_required_asset",
+			response:      nativeResponse.Response{Assets: []nativeResponse.Asset{title}},
+			expectedError: "native response is missing required asset 2",
+		},
+		{
+			description:   "missing_asset_id",
+			response:      nativeResponse.Response{Assets: []nativeResponse.Asset{{Title: &nativeResponse.Title{Text: "Buy now"}}}},
+			expectedError: "native response asset is missing an id",
+		},
+		{
+			description: "unrequested_asset",
+			response: nativeResponse.Response{Assets: []nativeResponse.Asset{
+				title,
+				image,
+				{ID: ptrutil.ToPtr[int64](9), Data: &nativeResponse.Data{Value: "Extra"}},
+			}},
+			expectedError: "native response asset 9 was not requested",
+		},
+		{
+			description:   "wrong_asset_type",
+			response:      nativeResponse.Response{Assets: []nativeResponse.Asset{{ID: ptrutil.ToPtr[int64](1), Data: &nativeResponse.Data{Value: "Buy now"}}, image}},
+			expectedError: "native response asset 1 must be a title",
+		},
+		{
+			description:   "title_too_long",
+			response:      nativeResponse.Response{Assets: []nativeResponse.Asset{{ID: ptrutil.ToPtr[int64](1), Title: &nativeResponse.Title{Text: "Buy now, today"}}, image}},
+			expectedError: "native response asset 1 title is longer than 10 characters",
+		},
+		{
+			description:   "data_too_long",
+			response:      nativeResponse.Response{Assets: []nativeResponse.Asset{title, image, {ID: ptrutil.ToPtr[int64](4), Data: &nativeResponse.Data{Value: "Too long"}}}},
+			expectedError: "native response asset 4 data is longer than 5 characters",
+		},
+		{
+			description:   "image_below_minimum",
+			response:      nativeResponse.Response{Assets: []nativeResponse.Asset{title, {ID: ptrutil.ToPtr[int64](2), Img: &nativeResponse.Image{URL: "https://img", W: 80, H: 40}}}},
+			expectedError: "native response asset 2 image 80x40 is smaller than the minimum 100x50",
+		},
+		{
+			description:   "image_aspect_ratio",
+			response:      nativeResponse.Response{Assets: []nativeResponse.Asset{title, {ID: ptrutil.ToPtr[int64](2), Img: &nativeResponse.Image{URL: "https://img", W: 300, H: 100}}}},
+			expectedError: "native response asset 2 image 300x100 doesn't match the aspect ratios 2:1, 1:1",
+		},
+		{
+			description:   "image_exact_size_aspect_ratio",
+			response:      nativeResponse.Response{Assets: []nativeResponse.Asset{title, image, {ID: ptrutil.ToPtr[int64](3), Img: &nativeResponse.Image{URL: "https://icon", W: 100, H: 50}}}},
+			expectedError: "native response asset 3 image 100x50 doesn't match the aspect ratios 50:50",
+		},
+		{
+			description: "unrequested_event_tracker",
+			response: nativeResponse.Response{
+				Assets:        []nativeResponse.Asset{title, image},
+				EventTrackers: []nativeResponse.EventTracker{{Event: native1.EventTypeImpression, Method: native1.EventTrackingMethodJS, URL: "https://imp.js"}},
+			},
+			expectedError: "native response event tracker with event 1 and method 2 was not requested",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			err := ValidateNativeResponse(request, test.response)
+			if test.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestValidateNativeResponseEventTrackersNotRequested(t *testing.T) {
+	response := nativeResponse.Response{
+		EventTrackers: []nativeResponse.EventTracker{{Event: native1.EventTypeViewableMRC50, Method: native1.EventTrackingMethodJS}},
+	}
+
+	assert.NoError(t, ValidateNativeResponse(nativeRequests.Request{}, response))
+}
+
+func TestMatchesAspectRatio(t *testing.T) {
+	testCases := []struct {
+		ratio    string
+		w, h     int64
+		expected bool
+	}{
+		{ratio: "16:9", w: 1280, h: 720, expected: true},
+		{ratio: "16:9", w: 1281, h: 720, expected: true},
+		{ratio: "16:9", w: 1000, h: 720, expected: false},
+		{ratio: "1.91:1", w: 1200, h: 628, expected: true},
+		{ratio: "16x9", w: 1280, h: 720, expected: false},
+		{ratio: "0:9", w: 1280, h: 720, expected: false},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expected, matchesAspectRatio(test.ratio, test.w, test.h), test.ratio)
+	}
+}
@@ -14,9 +14,11 @@ import (
 
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/tracing"
 
 	"github.com/buger/jsonparser"
 	"github.com/golang/glog"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/net/context/ctxhttp"
 )
 
@@ -90,6 +92,9 @@ func (c *clientImpl) PutJson(ctx context.Context, values []Cacheable) (uuids []s
 		return nil, errs
 	}
 
+	ctx, span := tracing.Start(ctx, "prebid_cache.put", attribute.Int("items", len(values)))
+	defer func() { tracing.End(span, errs...) }()
+
 	uuidsToReturn := make([]string, len(values))
 
 	postBody, err := encodeValues(values)
@@ -13,10 +13,14 @@ import (
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/metrics"
 	metricsConf "github.com/prebid/prebid-server/v3/metrics/config"
+	"github.com/prebid/prebid-server/v3/tracing/tracingtest"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 )
 
 func TestEmptyPut(t *testing.T) {
@@ -169,6 +173,48 @@ func TestSuccessfulPut(t *testing.T) {
 	metricsMock.AssertExpectations(t)
 }
 
+func TestPutIsTraced(t *testing.T) {
+	testCases := []struct {
+		name               string
+		handler            http.Handler
+		expectedStatusCode codes.Code
+	}{
+		{
+			name:               "success",
+			handler:            newHandler(1),
+			expectedStatusCode: codes.Unset,
+		},
+		{
+			name: "failure",
+			handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			}),
+			expectedStatusCode: codes.Error,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			exporter := tracingtest.Install(t)
+			server := httptest.NewServer(test.handler)
+			defer server.Close()
+
+			client := &clientImpl{
+				httpClient: server.Client(),
+				putUrl:     server.URL,
+				metrics:    &metricsConf.NilMetricsEngine{},
+			}
+			client.PutJson(context.Background(), []Cacheable{{Type: TypeJSON, Data: json.RawMessage("true")}})
+
+			spans := exporter.GetSpans()
+			require.Len(t, spans, 1)
+			assert.Equal(t, "prebid_cache.put", spans[0].Name)
+			assert.Contains(t, spans[0].Attributes, attribute.Int("items", 1))
+			assert.Equal(t, test.expectedStatusCode, spans[0].Status.Code)
+		})
+	}
+}
+
 func TestEncodeValueToBuffer(t *testing.T) {
 	buf := new(bytes.Buffer)
 	testCache := Cacheable{
@@ -1,8 +1,11 @@
 package privacy
 
 import (
+	"strconv"
+
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/rules"
 )
 
 type ActivityResult int
@@ -40,6 +43,7 @@ type ActivityControl struct {
 	plans      map[Activity]ActivityPlan
 	IPv6Config config.IPv6
 	IPv4Config config.IPv4
+	log        *ActivityLog
 }
 
 func NewActivityControl(cfg *config.AccountPrivacy) ActivityControl {
@@ -69,6 +73,7 @@ func NewActivityControl(cfg *config.AccountPrivacy) ActivityControl {
 func buildPlan(activity config.Activity) ActivityPlan {
 	return ActivityPlan{
 		rules:         cfgToRules(activity.Rules),
+		ruleConfigs:   activity.Rules,
 		defaultResult: cfgToDefaultResult(activity.Default),
 	}
 }
@@ -86,12 +91,46 @@ func cfgToRules(rules []config.ActivityRule) []Rule {
 			result:        result,
 			componentName: r.Condition.ComponentName,
 			componentType: r.Condition.ComponentType,
+			gppSID:        r.Condition.GPPSID,
+			request:       cfgToRequestConditions(r.Condition),
 		}
 		enfRules = append(enfRules, er)
 	}
 	return enfRules
 }
 
+func cfgToRequestConditions(condition config.ActivityCondition) []requestCondition {
+	var conditions []requestCondition
+
+	if len(condition.Geo) > 0 {
+		conditions = append(conditions, newRequestCondition(condition.Geo, rules.DeviceCountry, rules.DeviceRegion))
+	}
+	if len(condition.Channel) > 0 {
+		conditions = append(conditions, newRequestCondition(condition.Channel, rules.Channel))
+	}
+	if len(condition.Domain) > 0 {
+		conditions = append(conditions, newRequestCondition(condition.Domain, rules.Domain))
+	}
+	if len(condition.Bundle) > 0 {
+		conditions = append(conditions, newRequestCondition(condition.Bundle, rules.Bundle))
+	}
+	if len(condition.DeviceType) > 0 {
+		deviceTypes := make([]string, 0, len(condition.DeviceType))
+		for _, t := range condition.DeviceType {
+			deviceTypes = append(deviceTypes, strconv.Itoa(t))
+		}
+		conditions = append(conditions, newRequestCondition(deviceTypes, rules.DeviceType))
+	}
+	if condition.TCFInScope != nil {
+		conditions = append(conditions, newRequestCondition([]string{strconv.FormatBool(*condition.TCFInScope)}, rules.TcfInScope))
+	}
+	if condition.GPPSIDAvailable != nil {
+		conditions = append(conditions, newRequestCondition([]string{strconv.FormatBool(*condition.GPPSIDAvailable)}, rules.GppSidAvailable))
+	}
+
+	return conditions
+}
+
 func cfgToDefaultResult(activityDefault *bool) bool {
 	if activityDefault == nil {
 		return defaultActivityResult
@@ -100,26 +139,71 @@ func cfgToDefaultResult(activityDefault *bool) bool {
 }
 
 func (e ActivityControl) Allow(activity Activity, target Component, request ActivityRequest) bool {
-	plan, planDefined := e.plans[activity]
+	return e.Evaluate(activity, target, request).Allowed
+}
 
-	if !planDefined {
-		return defaultActivityResult
+// ActivityDecision is the result of an activity control evaluation along with the rule which decided it.
+type ActivityDecision struct {
+	Allowed bool `json:"allowed"`
+	// RuleIndex is the position of Rule within the rules of the activity. Rule is nil when no rule matched
+	// and the default of the activity applied.
+	RuleIndex int                  `json:"ruleindex"`
+	Rule      *config.ActivityRule `json:"rule,omitempty"`
+}
+
+// Evaluate returns whether the target may perform the activity, along with the rule which decided it.
+func (e ActivityControl) Evaluate(activity Activity, target Component, request ActivityRequest) ActivityDecision {
+	decision := ActivityDecision{Allowed: defaultActivityResult, RuleIndex: -1}
+	if plan, planDefined := e.plans[activity]; planDefined {
+		decision = plan.decide(target, request)
 	}
 
-	return plan.Evaluate(target, request)
+	e.log.add(ActivityEvaluation{
+		Activity:         activity.String(),
+		ComponentType:    target.Type,
+		ComponentName:    target.Name,
+		ActivityDecision: decision,
+	})
+	return decision
+}
+
+// WithLog returns a copy of the activity control recording its evaluations into log.
+func (e ActivityControl) WithLog(log *ActivityLog) ActivityControl {
+	e.log = log
+	return e
+}
+
+// Log returns the log the evaluations are recorded into, if any.
+func (e ActivityControl) Log() *ActivityLog {
+	return e.log
+}
+
+// Scrubbed records that a scrubber ran on the data sent to the target following the last evaluation of the
+// activity for it, for the reason given.
+func (e ActivityControl) Scrubbed(activity Activity, target Component, scrubber, reason string) {
+	e.log.addScrubber(activity.String(), target, Scrubber{Name: scrubber, Reason: reason})
 }
 
 type ActivityPlan struct {
 	defaultResult bool
 	rules         []Rule
+	ruleConfigs   []config.ActivityRule
 }
 
 func (p ActivityPlan) Evaluate(target Component, request ActivityRequest) bool {
-	for _, rule := range p.rules {
+	return p.decide(target, request).Allowed
+}
+
+func (p ActivityPlan) decide(target Component, request ActivityRequest) ActivityDecision {
+	for i, rule := range p.rules {
 		result := rule.Evaluate(target, request)
 		if result == ActivityDeny || result == ActivityAllow {
-			return result == ActivityAllow
+			decision := ActivityDecision{Allowed: result == ActivityAllow, RuleIndex: i}
+			if i < len(p.ruleConfigs) {
+				decision.Rule = &p.ruleConfigs[i]
+			}
+			return decision
 		}
 	}
-	return p.defaultResult
+	return ActivityDecision{Allowed: p.defaultResult, RuleIndex: -1}
 }
@@ -1,10 +1,13 @@
 package privacy
 
 import (
+	"encoding/json"
 	"testing"
 
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/rules"
 	"github.com/prebid/prebid-server/v3/util/ptrutil"
 	"github.com/stretchr/testify/assert"
 )
@@ -142,6 +145,66 @@ func TestActivityControlAllow(t *testing.T) {
 	}
 }
 
+func TestActivityControlEvaluate(t *testing.T) {
+	activityControl := NewActivityControl(&config.AccountPrivacy{
+		AllowActivities: &config.AllowActivities{
+			TransmitUserFPD: config.Activity{
+				Default: ptrutil.ToPtr(true),
+				Rules: []config.ActivityRule{
+					{Condition: config.ActivityCondition{ComponentName: []string{"bidderA"}}, Allow: true},
+					{Condition: config.ActivityCondition{Channel: []string{"app"}, Geo: []string{"USA"}}, Allow: false},
+				},
+			},
+		},
+	})
+	log := &ActivityLog{}
+	activityControl = activityControl.WithLog(log)
+
+	request := NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+		Device: &openrtb2.Device{Geo: &openrtb2.Geo{Country: "USA"}},
+		Ext:    json.RawMessage(`{"prebid":{"channel":{"name":"app"}}}`),
+	}})
+
+	decision := activityControl.Evaluate(ActivityTransmitUserFPD, Component{Type: "bidder", Name: "bidderB"}, request)
+	assert.Equal(t, ActivityDecision{
+		Allowed:   false,
+		RuleIndex: 1,
+		Rule:      &config.ActivityRule{Condition: config.ActivityCondition{Channel: []string{"app"}, Geo: []string{"USA"}}, Allow: false},
+	}, decision)
+
+	assert.True(t, activityControl.Allow(ActivityTransmitUserFPD, Component{Type: "bidder", Name: "bidderB"}, ActivityRequest{}), "the default applies when no rule matched")
+	assert.True(t, activityControl.Allow(ActivityFetchBids, Component{Type: "bidder", Name: "bidderB"}, request))
+
+	assert.Equal(t, []ActivityEvaluation{
+		{Activity: "transmitUfpd", ComponentType: "bidder", ComponentName: "bidderB", ActivityDecision: decision},
+		{Activity: "transmitUfpd", ComponentType: "bidder", ComponentName: "bidderB", ActivityDecision: ActivityDecision{Allowed: true, RuleIndex: -1}},
+		{Activity: "fetchBids", ComponentType: "bidder", ComponentName: "bidderB", ActivityDecision: ActivityDecision{Allowed: true, RuleIndex: -1}},
+	}, log.Evaluations())
+
+	unconfigured := ActivityControl{}.WithLog(log)
+	assert.True(t, unconfigured.Allow(ActivityFetchBids, Component{Type: "bidder", Name: "bidderC"}, request))
+	assert.Equal(t, ActivityEvaluation{Activity: "fetchBids", ComponentType: "bidder", ComponentName: "bidderC", ActivityDecision: ActivityDecision{Allowed: true, RuleIndex: -1}},
+		log.Evaluations()[3], "evaluations are recorded for accounts without activity controls too")
+}
+
+func TestCfgToRequestConditions(t *testing.T) {
+	conditions := cfgToRequestConditions(config.ActivityCondition{
+		ComponentName:   []string{"bidderA"},
+		Geo:             []string{"USA.VA"},
+		Domain:          []string{"Site.com"},
+		DeviceType:      []int{4, 5},
+		GPPSIDAvailable: ptrutil.ToPtr(false),
+	})
+
+	assert.Equal(t, []requestCondition{
+		newRequestCondition([]string{"USA.VA"}, rules.DeviceCountry, rules.DeviceRegion),
+		newRequestCondition([]string{"site.com"}, rules.Domain),
+		newRequestCondition([]string{"4", "5"}, rules.DeviceType),
+		newRequestCondition([]string{"false"}, rules.GppSidAvailable),
+	}, conditions)
+	assert.Nil(t, cfgToRequestConditions(config.ActivityCondition{ComponentName: []string{"bidderA"}}))
+}
+
 func TestActivityRequest(t *testing.T) {
 	t.Run("empty", func(t *testing.T) {
 		r := ActivityRequest{}
@@ -187,5 +250,6 @@ func getTestActivityPlan(result ActivityResult) ActivityPlan {
 				componentType: []string{"bidder"},
 			},
 		},
+		ruleConfigs: getTestActivityConfig(result == ActivityAllow).Rules,
 	}
 }
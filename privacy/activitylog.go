@@ -0,0 +1,83 @@
+package privacy
+
+import "sync"
+
+// Scrubbers recorded by the activity log.
+const (
+	ScrubberUserFPD              = "userFpd"
+	ScrubberGdprID               = "gdprId"
+	ScrubberDeviceIDsIPsUserDemo = "deviceIdsIpsUserDemo"
+	ScrubberGeoAndDeviceIP       = "geoAndDeviceIp"
+	ScrubberTID                  = "tid"
+)
+
+// Reasons for scrubbers to run.
+const (
+	ScrubReasonActivity = "activity"
+	ScrubReasonGDPR     = "gdpr"
+	ScrubReasonCCPA     = "ccpa"
+	ScrubReasonLMT      = "lmt"
+	ScrubReasonCOPPA    = "coppa"
+)
+
+// ActivityEvaluation records an activity control evaluation for a component, and the scrubbers which then ran
+// on the data sent to it. The analytics objects carry the evaluations made for a request as its privacy audit.
+type ActivityEvaluation struct {
+	Activity      string `json:"activity"`
+	ComponentType string `json:"componenttype"`
+	ComponentName string `json:"componentname"`
+	ActivityDecision
+	Scrubbers []Scrubber `json:"scrubbers,omitempty"`
+}
+
+// Scrubber identifies a scrubber which ran, and why. The reason is either the activity being denied or the
+// privacy policy enforced while the activity was allowed.
+type Scrubber struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// ActivityLog is the audit trail of the activity control evaluations of a request. It's safe for concurrent use.
+type ActivityLog struct {
+	mutex       sync.Mutex
+	evaluations []ActivityEvaluation
+}
+
+func (l *ActivityLog) add(evaluation ActivityEvaluation) {
+	if l == nil {
+		return
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.evaluations = append(l.evaluations, evaluation)
+}
+
+func (l *ActivityLog) addScrubber(activity string, target Component, scrubber Scrubber) {
+	if l == nil {
+		return
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	for i := len(l.evaluations) - 1; i >= 0; i-- {
+		evaluation := &l.evaluations[i]
+		if evaluation.Activity == activity && evaluation.ComponentType == target.Type && evaluation.ComponentName == target.Name {
+			evaluation.Scrubbers = append(evaluation.Scrubbers, scrubber)
+			return
+		}
+	}
+}
+
+// Evaluations returns the evaluations recorded, in order.
+func (l *ActivityLog) Evaluations() []ActivityEvaluation {
+	if l == nil {
+		return nil
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	evaluations := make([]ActivityEvaluation, len(l.evaluations))
+	for i, evaluation := range l.evaluations {
+		evaluation.Scrubbers = append([]Scrubber(nil), evaluation.Scrubbers...)
+		evaluations[i] = evaluation
+	}
+	return evaluations
+}
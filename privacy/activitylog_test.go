@@ -0,0 +1,37 @@
+package privacy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActivityLogScrubbed(t *testing.T) {
+	log := &ActivityLog{}
+	activityControl := ActivityControl{}.WithLog(log)
+	bidderA := Component{Type: ComponentTypeBidder, Name: "bidderA"}
+	bidderB := Component{Type: ComponentTypeBidder, Name: "bidderB"}
+
+	activityControl.Allow(ActivityTransmitUserFPD, bidderA, ActivityRequest{})
+	activityControl.Allow(ActivityTransmitUserFPD, bidderB, ActivityRequest{})
+	activityControl.Scrubbed(ActivityTransmitUserFPD, bidderA, ScrubberGdprID, ScrubReasonGDPR)
+	activityControl.Scrubbed(ActivityTransmitUserFPD, bidderA, ScrubberDeviceIDsIPsUserDemo, ScrubReasonCCPA)
+	activityControl.Scrubbed(ActivityTransmitTIDs, bidderA, ScrubberTID, ScrubReasonActivity)
+
+	evaluations := log.Evaluations()
+	assert.Equal(t, []Scrubber{{Name: "gdprId", Reason: "gdpr"}, {Name: "deviceIdsIpsUserDemo", Reason: "ccpa"}}, evaluations[0].Scrubbers)
+	assert.Empty(t, evaluations[1].Scrubbers)
+	assert.Len(t, evaluations, 2, "scrubbers without a prior evaluation of their activity aren't recorded")
+
+	evaluations[0].Scrubbers[0].Name = "modified"
+	assert.Equal(t, "gdprId", log.Evaluations()[0].Scrubbers[0].Name, "the evaluations returned are copies")
+}
+
+func TestActivityLogNil(t *testing.T) {
+	var log *ActivityLog
+	activityControl := ActivityControl{}.WithLog(log)
+
+	assert.True(t, activityControl.Allow(ActivityFetchBids, Component{Type: ComponentTypeBidder, Name: "bidderA"}, ActivityRequest{}))
+	activityControl.Scrubbed(ActivityFetchBids, Component{Type: ComponentTypeBidder, Name: "bidderA"}, ScrubberTID, ScrubReasonActivity)
+	assert.Nil(t, log.Evaluations())
+}
@@ -1,5 +1,12 @@
 package privacy
 
+import (
+	"strings"
+
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/rules"
+)
+
 // noClausesDefinedResult represents the default return when there is no matching criteria specified.
 const noClausesDefinedResult = true
 
@@ -8,6 +15,53 @@ type ConditionRule struct {
 	componentName []string
 	componentType []string
 	gppSID        []int8
+	request       []requestCondition
+}
+
+// requestCondition matches the bid requests for which the results of its schema functions, joined with dots, are
+// one of its values. Values may also hold the leading results only, such that a device country and region
+// condition matches "USA" as well as "USA.VA".
+type requestCondition struct {
+	functions []rules.SchemaFunction[openrtb_ext.RequestWrapper]
+	values    map[string]struct{}
+}
+
+func newRequestCondition(values []string, functionNames ...string) requestCondition {
+	condition := requestCondition{
+		values: make(map[string]struct{}, len(values)),
+	}
+	for _, name := range functionNames {
+		// The schema functions used by conditions take no params, so they are always created.
+		function, _ := rules.NewRequestSchemaFunction(name, nil)
+		condition.functions = append(condition.functions, function)
+	}
+	for _, v := range values {
+		condition.values[strings.ToLower(v)] = struct{}{}
+	}
+	return condition
+}
+
+func (c requestCondition) matches(request ActivityRequest) bool {
+	if !request.IsBidRequest() {
+		return false
+	}
+
+	var key string
+	for i, function := range c.functions {
+		result, err := function.Call(request.bidRequest)
+		if err != nil || result == "" {
+			return false
+		}
+		if i > 0 {
+			key += "."
+		}
+		key += strings.ToLower(result)
+
+		if _, ok := c.values[key]; ok {
+			return true
+		}
+	}
+	return false
 }
 
 func (r ConditionRule) Evaluate(target Component, request ActivityRequest) ActivityResult {
@@ -23,6 +77,12 @@ func (r ConditionRule) Evaluate(target Component, request ActivityRequest) Activ
 		return ActivityAbstain
 	}
 
+	for _, condition := range r.request {
+		if !condition.matches(request) {
+			return ActivityAbstain
+		}
+	}
+
 	return r.result
 }
 
@@ -1,10 +1,13 @@
 package privacy
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/rules"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -276,3 +279,103 @@ func TestGetGPPSID(t *testing.T) {
 		})
 	}
 }
+
+func TestRequestConditionMatches(t *testing.T) {
+	request := NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+		Device: &openrtb2.Device{Geo: &openrtb2.Geo{Country: "USA", Region: "VA"}, DeviceType: 4},
+		App:    &openrtb2.App{Bundle: "com.app"},
+		Regs:   &openrtb2.Regs{GDPR: ptrutil.ToPtr[int8](1)},
+		Ext:    json.RawMessage(`{"prebid":{"channel":{"name":"app"}}}`),
+	}})
+
+	testCases := []struct {
+		name      string
+		condition requestCondition
+		request   ActivityRequest
+		expected  bool
+	}{
+		{
+			name:      "geo_country",
+			condition: newRequestCondition([]string{"CAN", "usa"}, rules.DeviceCountry, rules.DeviceRegion),
+			request:   request,
+			expected:  true,
+		},
+		{
+			name:      "geo_country_and_region",
+			condition: newRequestCondition([]string{"USA.VA"}, rules.DeviceCountry, rules.DeviceRegion),
+			request:   request,
+			expected:  true,
+		},
+		{
+			name:      "geo_other_region",
+			condition: newRequestCondition([]string{"USA.CA"}, rules.DeviceCountry, rules.DeviceRegion),
+			request:   request,
+			expected:  false,
+		},
+		{
+			name:      "channel",
+			condition: newRequestCondition([]string{"app"}, rules.Channel),
+			request:   request,
+			expected:  true,
+		},
+		{
+			name:      "bundle",
+			condition: newRequestCondition([]string{"com.other"}, rules.Bundle),
+			request:   request,
+			expected:  false,
+		},
+		{
+			name:      "domain_missing",
+			condition: newRequestCondition([]string{""}, rules.Domain),
+			request:   request,
+			expected:  false,
+		},
+		{
+			name:      "device_type",
+			condition: newRequestCondition([]string{"4"}, rules.DeviceType),
+			request:   request,
+			expected:  true,
+		},
+		{
+			name:      "tcf_in_scope",
+			condition: newRequestCondition([]string{"false"}, rules.TcfInScope),
+			request:   request,
+			expected:  false,
+		},
+		{
+			name:      "policies",
+			condition: newRequestCondition([]string{"false"}, rules.GppSidAvailable),
+			request:   NewRequestFromPolicies(Policies{}),
+			expected:  false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.condition.matches(test.request))
+		})
+	}
+}
+
+func TestConditionRuleEvaluateRequestConditions(t *testing.T) {
+	rule := ConditionRule{
+		result:        ActivityDeny,
+		componentType: []string{"bidder"},
+		request: []requestCondition{
+			newRequestCondition([]string{"USA"}, rules.DeviceCountry, rules.DeviceRegion),
+			newRequestCondition([]string{"4"}, rules.DeviceType),
+		},
+	}
+	target := Component{Type: "bidder", Name: "bidderA"}
+
+	phoneInUSA := NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+		Device: &openrtb2.Device{Geo: &openrtb2.Geo{Country: "USA"}, DeviceType: 4},
+	}})
+	assert.Equal(t, ActivityDeny, rule.Evaluate(target, phoneInUSA))
+
+	tabletInUSA := NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+		Device: &openrtb2.Device{Geo: &openrtb2.Geo{Country: "USA"}, DeviceType: 5},
+	}})
+	assert.Equal(t, ActivityAbstain, rule.Evaluate(target, tabletInUSA), "every condition must match")
+	assert.Equal(t, ActivityAbstain, rule.Evaluate(target, ActivityRequest{}), "request conditions don't match without a bid request")
+}
@@ -0,0 +1,128 @@
+// Package readiness tracks whether Prebid Server is ready to serve traffic.
+package readiness
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v3/util/task"
+)
+
+// Tracker reports Prebid Server ready once every registered component is warm, and not ready again once it
+// starts draining for shutdown. A nil Tracker is always ready.
+type Tracker struct {
+	mu       sync.Mutex
+	cold     map[string]struct{}
+	draining bool
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{cold: map[string]struct{}{}}
+}
+
+// Register adds a component which is cold until the returned function is called.
+func (t *Tracker) Register(component string) (warm func()) {
+	if t == nil {
+		return func() {}
+	}
+
+	t.mu.Lock()
+	t.cold[component] = struct{}{}
+	t.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			t.mu.Lock()
+			delete(t.cold, component)
+			t.mu.Unlock()
+			glog.Infof("%s is warm", component)
+		})
+	}
+}
+
+// RegisterChan adds a component which is cold until warm is closed.
+func (t *Tracker) RegisterChan(component string, warm <-chan struct{}) {
+	ready := t.Register(component)
+	go func() {
+		<-warm
+		ready()
+	}()
+}
+
+// RegisterRunner returns runner, with the component registered as cold until the first run succeeds.
+func (t *Tracker) RegisterRunner(component string, runner task.Runner) task.Runner {
+	return &warmingRunner{runner: runner, warm: t.Register(component)}
+}
+
+type warmingRunner struct {
+	runner task.Runner
+	warm   func()
+}
+
+func (r *warmingRunner) Run() error {
+	err := r.runner.Run()
+	if err == nil {
+		r.warm()
+	}
+	return err
+}
+
+// WarmUpWithin considers every component still cold after timeout warm, so a dependency which stays unavailable
+// doesn't keep the server out of service. A non-positive timeout waits for the components indefinitely.
+func (t *Tracker) WarmUpWithin(timeout time.Duration) {
+	if t == nil || timeout <= 0 {
+		return
+	}
+
+	time.AfterFunc(timeout, func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		if len(t.cold) > 0 {
+			glog.Warningf("Reporting ready although %v did not warm up within %v", sortedKeys(t.cold), timeout)
+			t.cold = map[string]struct{}{}
+		}
+	})
+}
+
+// Drain reports the server not ready from now on, as it is shutting down.
+func (t *Tracker) Drain() {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	t.draining = true
+	t.mu.Unlock()
+}
+
+// Status returns whether the server is ready to serve traffic and, if not, the reasons why: the cold components,
+// or "shutdown" once draining.
+func (t *Tracker) Status() (ready bool, pending []string) {
+	if t == nil {
+		return true, nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.draining {
+		return false, []string{"shutdown"}
+	}
+	if len(t.cold) > 0 {
+		return false, sortedKeys(t.cold)
+	}
+	return true, nil
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,98 @@
+package readiness
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegister(t *testing.T) {
+	tracker := NewTracker()
+	currencyWarm := tracker.Register("currency_rates")
+	gvlWarm := tracker.Register("gdpr_vendor_list")
+
+	ready, pending := tracker.Status()
+	assert.False(t, ready)
+	assert.Equal(t, []string{"currency_rates", "gdpr_vendor_list"}, pending)
+
+	currencyWarm()
+	currencyWarm()
+	ready, pending = tracker.Status()
+	assert.False(t, ready)
+	assert.Equal(t, []string{"gdpr_vendor_list"}, pending)
+
+	gvlWarm()
+	ready, pending = tracker.Status()
+	assert.True(t, ready)
+	assert.Empty(t, pending)
+}
+
+func TestRegisterChan(t *testing.T) {
+	tracker := NewTracker()
+	warm := make(chan struct{})
+	tracker.RegisterChan("stored_requests", warm)
+
+	ready, _ := tracker.Status()
+	assert.False(t, ready)
+
+	close(warm)
+	assert.Eventually(t, func() bool {
+		ready, _ := tracker.Status()
+		return ready
+	}, time.Second, time.Millisecond)
+}
+
+type fakeRunner struct {
+	errs []error
+}
+
+func (r *fakeRunner) Run() error {
+	err := r.errs[0]
+	r.errs = r.errs[1:]
+	return err
+}
+
+func TestRegisterRunner(t *testing.T) {
+	tracker := NewTracker()
+	runner := tracker.RegisterRunner("currency_rates", &fakeRunner{errs: []error{errors.New("unavailable"), nil}})
+
+	assert.Error(t, runner.Run())
+	ready, _ := tracker.Status()
+	assert.False(t, ready, "cold until a run succeeds")
+
+	assert.NoError(t, runner.Run())
+	ready, _ = tracker.Status()
+	assert.True(t, ready)
+}
+
+func TestWarmUpWithin(t *testing.T) {
+	tracker := NewTracker()
+	tracker.Register("price_floors")
+	tracker.WarmUpWithin(10 * time.Millisecond)
+
+	assert.Eventually(t, func() bool {
+		ready, _ := tracker.Status()
+		return ready
+	}, time.Second, time.Millisecond)
+}
+
+func TestDrain(t *testing.T) {
+	tracker := NewTracker()
+	tracker.Drain()
+
+	ready, pending := tracker.Status()
+	assert.False(t, ready)
+	assert.Equal(t, []string{"shutdown"}, pending)
+}
+
+func TestNilTracker(t *testing.T) {
+	var tracker *Tracker
+	tracker.Register("currency_rates")()
+	tracker.Drain()
+
+	ready, pending := tracker.Status()
+	assert.True(t, ready)
+	assert.Empty(t, pending)
+}
@@ -5,12 +5,16 @@ import (
 	"net/http/pprof"
 	"time"
 
+	"github.com/golang/glog"
 	"github.com/prebid/prebid-server/v3/currency"
 	"github.com/prebid/prebid-server/v3/endpoints"
+	"github.com/prebid/prebid-server/v3/lineitems"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+	rulesEngineConfig "github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
 	"github.com/prebid/prebid-server/v3/version"
 )
 
-func Admin(rateConverter *currency.RateConverter, rateConverterFetchingInterval time.Duration) *http.ServeMux {
+func Admin(rateConverter *currency.RateConverter, rateConverterFetchingInterval time.Duration, lineItems lineitems.Service, reloader *Reloader, moduleEndpoints moduledeps.AdminEndpoints) *http.ServeMux {
 	// Add endpoints to the admin server
 	// Making sure to add pprof routes
 	mux := http.NewServeMux()
@@ -22,6 +26,18 @@ func Admin(rateConverter *currency.RateConverter, rateConverterFetchingInterval
 	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
 	// Register prebid-server defined admin handlers
 	mux.HandleFunc("/currency/rates", endpoints.NewCurrencyRatesEndpoint(rateConverter, rateConverterFetchingInterval))
+	mux.HandleFunc("/lineitems/report", endpoints.NewLineItemsReportEndpoint(lineItems))
 	mux.HandleFunc("/version", endpoints.NewVersionEndpoint(version.Ver, version.Rev))
+	if schemaValidator, err := rulesEngineConfig.CreateSchemaValidator(rulesEngineConfig.RulesEngineSchemaFilePath); err == nil {
+		mux.HandleFunc("/rules/simulate", endpoints.NewRulesEngineSimulationEndpoint(schemaValidator))
+	} else {
+		glog.Errorf("The rules engine simulation endpoint is disabled, the rules engine schema failed to load: %v", err)
+	}
+	if reloader != nil {
+		mux.HandleFunc("/config/reload", endpoints.NewConfigReloadEndpoint(reloader))
+	}
+	for path, handler := range moduleEndpoints {
+		mux.HandleFunc(path, handler)
+	}
 	return mux
 }
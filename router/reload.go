@@ -0,0 +1,339 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/golang/glog"
+	"github.com/julienschmidt/httprouter"
+	"github.com/prebid/prebid-server/v3/analytics"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/currency"
+	"github.com/prebid/prebid-server/v3/endpoints"
+	"github.com/prebid/prebid-server/v3/endpoints/events"
+	infoEndpoints "github.com/prebid/prebid-server/v3/endpoints/info"
+	"github.com/prebid/prebid-server/v3/endpoints/openrtb2"
+	"github.com/prebid/prebid-server/v3/endpoints/openrtb3"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/exchange"
+	"github.com/prebid/prebid-server/v3/experiment/adscert"
+	"github.com/prebid/prebid-server/v3/floors"
+	"github.com/prebid/prebid-server/v3/frequencycap"
+	"github.com/prebid/prebid-server/v3/gdpr"
+	"github.com/prebid/prebid-server/v3/hooks"
+	"github.com/prebid/prebid-server/v3/lineitems"
+	"github.com/prebid/prebid-server/v3/macros"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/ortb"
+	pbc "github.com/prebid/prebid-server/v3/prebid_cache_client"
+	"github.com/prebid/prebid-server/v3/router/aspects"
+	"github.com/prebid/prebid-server/v3/stored_requests"
+	"github.com/prebid/prebid-server/v3/stored_responses/writer"
+	"github.com/prebid/prebid-server/v3/tracing"
+	"github.com/prebid/prebid-server/v3/usersync"
+	"github.com/prebid/prebid-server/v3/util/uuidutil"
+)
+
+// reloadableHandlers are the endpoints depending on the parts of the configuration which can be reloaded: the
+// bidder endpoints, extra info and disabled flags, the account defaults and the hook execution plans.
+type reloadableHandlers struct {
+	auction         httprouter.Handle
+	openrtb3Auction httprouter.Handle
+	video           httprouter.Handle
+	amp             httprouter.Handle
+	biddersInfo     httprouter.Handle
+	bidderInfo      httprouter.Handle
+	cookieSync      httprouter.Handle
+	setUID          httprouter.Handle
+	event           httprouter.Handle
+	vtrack          httprouter.Handle
+	tcfDebug        httprouter.Handle
+
+	// inFlight is read locked while a request is served, so the handlers are retired once the requests drain
+	inFlight         sync.RWMutex
+	closeConnections func()
+}
+
+// retire closes the idle connections of the dedicated bidder transports once the requests in flight on the
+// replaced handlers have been served.
+func (h *reloadableHandlers) retire() {
+	h.inFlight.Lock()
+	defer h.inFlight.Unlock()
+	h.closeConnections()
+}
+
+// handlerBuilder holds the dependencies which are set up once and shared by the reloadable handlers of every
+// configuration reload.
+type handlerBuilder struct {
+	httpClient        *http.Client
+	cacheClient       pbc.Client
+	paramsValidator   openrtb_ext.BidderParamValidator
+	syncersByBidder   map[string]usersync.Syncer
+	metricsEngine     metrics.MetricsEngine
+	gdprPermsBuilder  gdpr.PermissionsBuilder
+	tcf2CfgBuilder    gdpr.TCF2ConfigBuilder
+	rateConvertor     *currency.RateConverter
+	categoriesFetcher stored_requests.CategoryFetcher
+	adsCertSigner     adscert.Signer
+	macroReplacer     macros.Replacer
+	priceFloorFetcher floors.FloorFetcher
+	lineItems         lineitems.Service
+	frequencyCaps     *frequencycap.Service
+	recordingWriter   writer.Writer
+	fetcher           stored_requests.Fetcher
+	ampFetcher        stored_requests.Fetcher
+	videoFetcher      stored_requests.Fetcher
+	storedRespFetcher stored_requests.Fetcher
+	accounts          stored_requests.AccountFetcher
+	analyticsRunner   analytics.Runner
+	defReqJSON        []byte
+	tmaxAdjustments   *exchange.TmaxAdjustmentsPreprocessed
+	hookRepository    hooks.HookRepository
+}
+
+func (b *handlerBuilder) build(cfg *config.Configuration) (*reloadableHandlers, error) {
+	activeBidders := exchange.GetActiveBidders(cfg.BidderInfos)
+	disabledBidders := exchange.GetDisabledBidderWarningMessages(cfg.BidderInfos)
+
+	adapters, singleFormatAdapters, adaptersErrs := exchange.BuildAdapters(b.httpClient, cfg, cfg.BidderInfos, b.metricsEngine)
+	if len(adaptersErrs) > 0 {
+		return nil, errortypes.NewAggregateError("Failed to initialize adapters", adaptersErrs)
+	}
+
+	requestValidator := ortb.NewRequestValidator(activeBidders, disabledBidders, b.paramsValidator)
+	planBuilder := hooks.NewExecutionPlanBuilder(cfg.Hooks, b.hookRepository)
+
+	theExchange := exchange.NewExchange(adapters, b.cacheClient, cfg, requestValidator, b.syncersByBidder, b.metricsEngine, cfg.BidderInfos, b.gdprPermsBuilder, b.rateConvertor, b.categoriesFetcher, b.adsCertSigner, b.macroReplacer, b.priceFloorFetcher, singleFormatAdapters, b.lineItems, b.frequencyCaps, b.recordingWriter)
+	var uuidGenerator uuidutil.UUIDRandomGenerator
+	openrtbEndpoint, err := openrtb2.NewEndpoint(uuidGenerator, theExchange, requestValidator, b.fetcher, b.accounts, cfg, b.metricsEngine, b.analyticsRunner, disabledBidders, b.defReqJSON, activeBidders, b.storedRespFetcher, planBuilder, b.tmaxAdjustments)
+	if err != nil {
+		return nil, err
+	}
+
+	ampEndpoint, err := openrtb2.NewAmpEndpoint(uuidGenerator, theExchange, requestValidator, b.ampFetcher, b.accounts, cfg, b.metricsEngine, b.analyticsRunner, disabledBidders, b.defReqJSON, activeBidders, b.storedRespFetcher, planBuilder, b.tmaxAdjustments)
+	if err != nil {
+		return nil, err
+	}
+
+	videoEndpoint, err := openrtb2.NewVideoEndpoint(uuidGenerator, theExchange, requestValidator, b.fetcher, b.videoFetcher, b.accounts, cfg, b.metricsEngine, b.analyticsRunner, disabledBidders, b.defReqJSON, activeBidders, b.cacheClient, b.tmaxAdjustments)
+	if err != nil {
+		return nil, err
+	}
+
+	requestTimeoutHeaders := config.RequestTimeoutHeaders{}
+	if cfg.RequestTimeoutHeaders != requestTimeoutHeaders {
+		videoEndpoint = aspects.QueuedRequestTimeout(videoEndpoint, cfg.RequestTimeoutHeaders, b.metricsEngine, metrics.ReqTypeVideo)
+	}
+
+	openrtb3Endpoint := openrtb3.NewEndpoint(openrtbEndpoint, cfg.MaxRequestSize)
+	if cfg.Tracing.Enabled {
+		openrtbEndpoint = tracing.Handle("openrtb2.auction", openrtbEndpoint)
+		openrtb3Endpoint = tracing.Handle("openrtb3.auction", openrtb3Endpoint)
+		videoEndpoint = tracing.Handle("openrtb2.video", videoEndpoint)
+		ampEndpoint = tracing.Handle("openrtb2.amp", ampEndpoint)
+	}
+
+	return &reloadableHandlers{
+		auction:         openrtbEndpoint,
+		openrtb3Auction: openrtb3Endpoint,
+		video:           videoEndpoint,
+		amp:             ampEndpoint,
+		biddersInfo:     infoEndpoints.NewBiddersEndpoint(cfg.BidderInfos),
+		bidderInfo:      infoEndpoints.NewBiddersDetailEndpoint(cfg.BidderInfos),
+		cookieSync:      endpoints.NewCookieSyncEndpoint(b.syncersByBidder, cfg, b.gdprPermsBuilder, b.tcf2CfgBuilder, b.metricsEngine, b.analyticsRunner, b.accounts, activeBidders).Handle,
+		setUID:          endpoints.NewSetUIDEndpoint(cfg, b.syncersByBidder, b.gdprPermsBuilder, b.tcf2CfgBuilder, b.analyticsRunner, b.accounts, b.metricsEngine),
+		event:           events.NewEventEndpoint(cfg, b.accounts, b.analyticsRunner, b.metricsEngine, b.frequencyCaps, b.lineItems),
+		vtrack:          events.NewVTrackEndpoint(cfg, b.accounts, b.cacheClient, cfg.BidderInfos, b.metricsEngine),
+		tcfDebug:        endpoints.NewTCFDebugEndpoint(cfg, b.gdprPermsBuilder, b.tcf2CfgBuilder, b.accounts, b.metricsEngine),
+		closeConnections: func() {
+			exchange.CloseIdleBidderConnections(adapters)
+		},
+	}, nil
+}
+
+// reloadable returns a Handle which serves every request with the handler of the configuration in effect.
+func (r *Router) reloadable(handler func(*reloadableHandlers) httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		handlers := r.handlers.Load()
+		handlers.inFlight.RLock()
+		defer handlers.inFlight.RUnlock()
+		handler(handlers)(w, req, ps)
+	}
+}
+
+// Reloader applies the reloadable parts of a freshly loaded host configuration to a running router.
+type Reloader struct {
+	router *Router
+	load   func() (*config.Configuration, error)
+
+	mu sync.Mutex
+
+	stop    chan struct{}
+	stopped sync.WaitGroup
+}
+
+// NewReloader returns a Reloader which loads the host configuration with load. Stopping the reloader is part
+// of the router shutdown.
+func (r *Router) NewReloader(load func() (*config.Configuration, error)) *Reloader {
+	reloader := &Reloader{
+		router: r,
+		load:   load,
+		stop:   make(chan struct{}),
+	}
+	r.shutdowns = append(r.shutdowns, reloader.Stop)
+	return reloader
+}
+
+// Reload loads and validates the host configuration, then swaps in the handlers built from its reloadable parts.
+// The replaced handlers are retired in the background. The keys of the changed settings which only take effect
+// after a restart are returned.
+func (rl *Reloader) Reload() ([]string, error) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	reloaded, err := rl.load()
+	if err != nil {
+		return nil, err
+	}
+
+	effective, restartRequired := rl.router.cfg.Reload(reloaded)
+	handlers, err := rl.router.handlerBuilder.build(effective)
+	if err != nil {
+		return nil, err
+	}
+
+	if replaced := rl.router.handlers.Swap(handlers); replaced != nil {
+		go replaced.retire()
+	}
+	rl.router.cfg = effective
+	return restartRequired, nil
+}
+
+// Start reloads the configuration on SIGHUP and, if config_reload.watch_files is enabled, when one of the
+// files in paths, or the files within one of the directories in paths, changes.
+func (rl *Reloader) Start(paths ...string) error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	cfg := rl.router.cfg.ConfigReload
+	var changes <-chan struct{}
+	if cfg.WatchFiles {
+		watcher, err := watchFiles(paths, time.Duration(cfg.WatchDelayMS)*time.Millisecond, rl.stop)
+		if err != nil {
+			signal.Stop(signals)
+			return err
+		}
+		changes = watcher
+	}
+
+	rl.stopped.Add(1)
+	go func() {
+		defer rl.stopped.Done()
+		defer signal.Stop(signals)
+		for {
+			select {
+			case <-signals:
+				rl.reloadAndLog("SIGHUP")
+			case <-changes:
+				rl.reloadAndLog("file change")
+			case <-rl.stop:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop ends the reloads on SIGHUP and file changes.
+func (rl *Reloader) Stop() {
+	select {
+	case <-rl.stop:
+	default:
+		close(rl.stop)
+	}
+	rl.stopped.Wait()
+}
+
+func (rl *Reloader) reloadAndLog(trigger string) {
+	restartRequired, err := rl.Reload()
+	if err != nil {
+		glog.Errorf("Configuration reload on %s failed, the current configuration is kept: %v", trigger, err)
+		return
+	}
+	glog.Infof("Configuration reloaded on %s", trigger)
+	if len(restartRequired) > 0 {
+		glog.Warningf("Configuration changes to %v require a restart to take effect", restartRequired)
+	}
+}
+
+// watchFiles returns a channel notified once changes to paths settled for delay. Files are watched through their
+// directory, so they are still followed when an editor or a config map update replaces them.
+func watchFiles(paths []string, delay time.Duration, stop <-chan struct{}) (<-chan struct{}, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	watchedFiles := map[string]struct{}{}
+	watchedDirs := map[string]struct{}{}
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			watcher.Close()
+			return nil, err
+		}
+		if info.IsDir() {
+			watchedDirs[filepath.Clean(path)] = struct{}{}
+			err = watcher.Add(path)
+		} else {
+			watchedFiles[filepath.Clean(path)] = struct{}{}
+			err = watcher.Add(filepath.Dir(path))
+		}
+		if err != nil {
+			watcher.Close()
+			return nil, err
+		}
+	}
+	if len(watchedFiles) == 0 && len(watchedDirs) == 0 {
+		watcher.Close()
+		return nil, errors.New("config_reload.watch_files is enabled but there are no files to watch")
+	}
+
+	changes := make(chan struct{})
+	go func() {
+		defer watcher.Close()
+
+		settled := time.NewTimer(delay)
+		settled.Stop()
+		for {
+			select {
+			case event := <-watcher.Events:
+				_, isFile := watchedFiles[filepath.Clean(event.Name)]
+				_, inDir := watchedDirs[filepath.Dir(event.Name)]
+				if isFile || inDir {
+					settled.Reset(delay)
+				}
+			case err := <-watcher.Errors:
+				glog.Errorf("Watching the configuration files failed: %v", err)
+			case <-settled.C:
+				select {
+				case changes <- struct{}{}:
+				case <-stop:
+					return
+				}
+			case <-stop:
+				settled.Stop()
+				return
+			}
+		}
+	}()
+	return changes, nil
+}
@@ -0,0 +1,166 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	analyticsBuild "github.com/prebid/prebid-server/v3/analytics/build"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/exchange"
+	metricsConfig "github.com/prebid/prebid-server/v3/metrics/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/stored_requests/backends/empty_fetcher"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newReloadTestRouter(t *testing.T, cfg *config.Configuration) *Router {
+	r := &Router{Router: httprouter.New(), cfg: cfg}
+	r.handlerBuilder = &handlerBuilder{
+		httpClient:        http.DefaultClient,
+		paramsValidator:   &testValidator{},
+		metricsEngine:     &metricsConfig.NilMetricsEngine{},
+		categoriesFetcher: empty_fetcher.EmptyFetcher{},
+		fetcher:           empty_fetcher.EmptyFetcher{},
+		ampFetcher:        empty_fetcher.EmptyFetcher{},
+		videoFetcher:      empty_fetcher.EmptyFetcher{},
+		storedRespFetcher: empty_fetcher.EmptyFetcher{},
+		accounts:          empty_fetcher.EmptyFetcher{},
+		analyticsRunner:   analyticsBuild.New(&config.Analytics{}),
+		tmaxAdjustments:   exchange.ProcessTMaxAdjustments(config.TmaxAdjustments{}),
+	}
+
+	handlers, err := r.handlerBuilder.build(cfg)
+	require.NoError(t, err)
+	r.handlers.Store(handlers)
+	r.GET("/info/bidders/:bidderName", r.reloadable(func(h *reloadableHandlers) httprouter.Handle { return h.bidderInfo }))
+	return r
+}
+
+func newReloadTestConfig(endpoint string, disabled bool, port int) *config.Configuration {
+	return &config.Configuration{
+		Port: port,
+		BidderInfos: config.BidderInfos{
+			"appnexus": config.BidderInfo{
+				Endpoint: endpoint,
+				Disabled: disabled,
+				Capabilities: &config.CapabilitiesInfo{
+					Site: &config.PlatformInfo{MediaTypes: []openrtb_ext.BidType{openrtb_ext.BidTypeBanner}},
+				},
+			},
+		},
+	}
+}
+
+func bidderInfoStatus(t *testing.T, r *Router) string {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/info/bidders/appnexus", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var detail struct {
+		Status string `json:"status"`
+	}
+	require.NoError(t, jsonutil.Unmarshal(w.Body.Bytes(), &detail))
+	return detail.Status
+}
+
+func TestReload(t *testing.T) {
+	r := newReloadTestRouter(t, newReloadTestConfig("http://appnexus.com", false, 8000))
+	require.Equal(t, "ACTIVE", bidderInfoStatus(t, r))
+
+	reloaded := newReloadTestConfig("http://new.appnexus.com", true, 9000)
+	reloader := r.NewReloader(func() (*config.Configuration, error) { return reloaded, nil })
+
+	restartRequired, err := reloader.Reload()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"port"}, restartRequired)
+	assert.Equal(t, "DISABLED", bidderInfoStatus(t, r), "the handlers built from the reloaded config are served")
+	assert.Equal(t, "http://new.appnexus.com", r.cfg.BidderInfos["appnexus"].Endpoint)
+	assert.Equal(t, 8000, r.cfg.Port, "settings requiring a restart are kept")
+}
+
+func TestReloadRetiresReplacedHandlers(t *testing.T) {
+	r := newReloadTestRouter(t, newReloadTestConfig("http://appnexus.com", false, 8000))
+	replaced := r.handlers.Load()
+	retired := make(chan struct{})
+	replaced.closeConnections = func() { close(retired) }
+
+	// a request still served by the replaced handlers
+	replaced.inFlight.RLock()
+
+	reloader := r.NewReloader(func() (*config.Configuration, error) {
+		return newReloadTestConfig("http://new.appnexus.com", false, 8000), nil
+	})
+	_, err := reloader.Reload()
+	require.NoError(t, err)
+
+	select {
+	case <-retired:
+		assert.Fail(t, "the replaced handlers were retired before the requests in flight were served")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	replaced.inFlight.RUnlock()
+	select {
+	case <-retired:
+	case <-time.After(time.Second):
+		assert.Fail(t, "the replaced handlers were not retired")
+	}
+}
+
+func TestReloadFailure(t *testing.T) {
+	r := newReloadTestRouter(t, newReloadTestConfig("http://appnexus.com", false, 8000))
+	current := r.cfg
+	reloader := r.NewReloader(func() (*config.Configuration, error) { return nil, errors.New("validation errors") })
+
+	_, err := reloader.Reload()
+
+	assert.EqualError(t, err, "validation errors")
+	assert.Same(t, current, r.cfg)
+	assert.Equal(t, "ACTIVE", bidderInfoStatus(t, r))
+}
+
+func TestWatchFiles(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "pbs.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte("port: 8000"), 0644))
+	bidderInfoDir := filepath.Join(dir, "bidder-info")
+	require.NoError(t, os.Mkdir(bidderInfoDir, 0755))
+
+	stop := make(chan struct{})
+	defer close(stop)
+	changes, err := watchFiles([]string{configFile, bidderInfoDir}, 10*time.Millisecond, stop)
+	require.NoError(t, err)
+
+	expectChange := func(t *testing.T, expected bool) {
+		t.Helper()
+		select {
+		case <-changes:
+			assert.True(t, expected, "unexpected change notification")
+		case <-time.After(200 * time.Millisecond):
+			assert.False(t, expected, "missing change notification")
+		}
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "unrelated.txt"), []byte("x"), 0644))
+	expectChange(t, false)
+
+	require.NoError(t, os.WriteFile(configFile, []byte("port: 9000"), 0644))
+	expectChange(t, true)
+
+	require.NoError(t, os.WriteFile(filepath.Join(bidderInfoDir, "appnexus.yaml"), []byte("endpoint: x"), 0644))
+	expectChange(t, true)
+}
+
+func TestWatchFilesMissingPath(t *testing.T) {
+	_, err := watchFiles([]string{filepath.Join(t.TempDir(), "missing.yaml")}, time.Millisecond, make(chan struct{}))
+	assert.Error(t, err)
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	openrtb2model "github.com/prebid/openrtb/v20/openrtb2"
@@ -16,30 +17,28 @@ import (
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/currency"
 	"github.com/prebid/prebid-server/v3/endpoints"
-	"github.com/prebid/prebid-server/v3/endpoints/events"
-	infoEndpoints "github.com/prebid/prebid-server/v3/endpoints/info"
-	"github.com/prebid/prebid-server/v3/endpoints/openrtb2"
 	"github.com/prebid/prebid-server/v3/errortypes"
 	"github.com/prebid/prebid-server/v3/exchange"
 	"github.com/prebid/prebid-server/v3/experiment/adscert"
 	"github.com/prebid/prebid-server/v3/floors"
+	"github.com/prebid/prebid-server/v3/frequencycap"
 	"github.com/prebid/prebid-server/v3/gdpr"
-	"github.com/prebid/prebid-server/v3/hooks"
+	"github.com/prebid/prebid-server/v3/lineitems"
 	"github.com/prebid/prebid-server/v3/macros"
-	"github.com/prebid/prebid-server/v3/metrics"
 	metricsConf "github.com/prebid/prebid-server/v3/metrics/config"
 	"github.com/prebid/prebid-server/v3/modules"
 	"github.com/prebid/prebid-server/v3/modules/moduledeps"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
-	"github.com/prebid/prebid-server/v3/ortb"
 	"github.com/prebid/prebid-server/v3/pbs"
 	pbc "github.com/prebid/prebid-server/v3/prebid_cache_client"
-	"github.com/prebid/prebid-server/v3/router/aspects"
+	"github.com/prebid/prebid-server/v3/readiness"
 	"github.com/prebid/prebid-server/v3/server/ssl"
 	storedRequestsConf "github.com/prebid/prebid-server/v3/stored_requests/config"
+	"github.com/prebid/prebid-server/v3/stored_responses/writer"
+	"github.com/prebid/prebid-server/v3/tracing"
 	"github.com/prebid/prebid-server/v3/usersync"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
-	"github.com/prebid/prebid-server/v3/util/uuidutil"
+	"github.com/prebid/prebid-server/v3/util/timeutil"
 	"github.com/prebid/prebid-server/v3/version"
 
 	_ "github.com/go-sql-driver/mysql"
@@ -117,11 +116,22 @@ type Router struct {
 	*httprouter.Router
 	MetricsEngine   *metricsConf.DetailedMetricsEngine
 	ParamsValidator openrtb_ext.BidderParamValidator
+	LineItems       lineitems.Service
+
+	// ModuleAdminEndpoints are the handlers the hook modules serve on the admin server
+	ModuleAdminEndpoints moduledeps.AdminEndpoints
+
+	// cfg is the configuration in effect, which differs from the one the router was created with once reloaded
+	cfg            *config.Configuration
+	handlerBuilder *handlerBuilder
+	handlers       atomic.Pointer[reloadableHandlers]
 
 	shutdowns []func()
 }
 
-func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *Router, err error) {
+// New builds the router. Components warming up in the background, like the stored request caches, register with
+// the tracker, which the /status endpoint reports.
+func New(cfg *config.Configuration, rateConvertor *currency.RateConverter, tracker *readiness.Tracker) (r *Router, err error) {
 	const schemaDirectory = "./static/bidder-params"
 
 	r = &Router{
@@ -209,97 +219,116 @@ func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *R
 	}
 
 	normalizedGeoscopes := getNormalizedGeoscopes(cfg.BidderInfos)
-	moduleDeps := moduledeps.ModuleDeps{HTTPClient: generalHttpClient, RateConvertor: rateConvertor, Geoscope: normalizedGeoscopes}
+	r.ModuleAdminEndpoints = moduledeps.AdminEndpoints{}
+	moduleDeps := moduledeps.ModuleDeps{HTTPClient: generalHttpClient, RateConvertor: rateConvertor, Geoscope: normalizedGeoscopes, AdminEndpoints: r.ModuleAdminEndpoints}
 	repo, moduleStageNames, shutdownModules, err := modules.NewBuilder().Build(cfg.Hooks.Modules, moduleDeps)
 	if err != nil {
 		glog.Fatalf("Failed to init hook modules: %v", err)
 	}
 
+	shutdownTracing, err := tracing.New(cfg.Tracing)
+	if err != nil {
+		return nil, err
+	}
+
 	// Metrics engine
 	r.MetricsEngine = metricsConf.NewMetricsEngine(cfg, openrtb_ext.CoreBidderNames(), syncerKeys, moduleStageNames)
-	shutdown, fetcher, ampFetcher, accounts, categoriesFetcher, videoFetcher, storedRespFetcher := storedRequestsConf.NewStoredRequests(cfg, r.MetricsEngine, generalHttpClient, r.Router)
+	shutdown, fetcher, ampFetcher, accounts, categoriesFetcher, videoFetcher, storedRespFetcher := storedRequestsConf.NewStoredRequests(cfg, r.MetricsEngine, generalHttpClient, r.Router, tracker)
 
 	analyticsRunner := analyticsBuild.New(&cfg.Analytics)
 
 	// register the analytics runner for shutdown
-	r.shutdowns = append(r.shutdowns, shutdown, analyticsRunner.Shutdown, shutdownModules.Shutdown)
+	r.shutdowns = append(r.shutdowns, shutdown, analyticsRunner.Shutdown, shutdownModules.Shutdown, shutdownTracing, r.MetricsEngine.Shutdown)
 
 	paramsValidator, err := openrtb_ext.NewBidderParamsValidator(schemaDirectory)
 	if err != nil {
 		glog.Fatalf("Failed to create the bidder params validator. %v", err)
 	}
 
-	activeBidders := exchange.GetActiveBidders(cfg.BidderInfos)
-	disabledBidders := exchange.GetDisabledBidderWarningMessages(cfg.BidderInfos)
-
 	defReqJSON := readDefaultRequest(cfg.DefReqConfig)
 
 	gvlVendorIDs := cfg.BidderInfos.ToGVLVendorIDMap()
-	vendorListFetcher := gdpr.NewVendorListFetcher(context.Background(), cfg.GDPR, generalHttpClient, r.MetricsEngine, gdpr.VendorListURLMaker)
+	vendorListWarm := tracker.Register("gdpr_vendor_list")
+	vendorListClient := gdpr.NewVendorListClient(generalHttpClient, cfg.GDPR.VendorList.URL)
+	// the vendor lists refresh in the background until the router shuts down
+	vendorListCtx, stopVendorListRefresh := context.WithCancel(context.Background())
+	r.shutdowns = append(r.shutdowns, stopVendorListRefresh)
+	vendorListFetcher := gdpr.NewVendorListFetcher(vendorListCtx, cfg.GDPR, vendorListClient, r.MetricsEngine, gdpr.NewVendorListURLMaker(cfg.GDPR.VendorList.URL))
+	vendorListWarm()
 	gdprPermsBuilder := gdpr.NewPermissionsBuilder(cfg.GDPR, gvlVendorIDs, vendorListFetcher, r.MetricsEngine)
-	tcf2CfgBuilder := gdpr.NewTCF2Config
 
 	cacheClient := pbc.NewClient(cacheHttpClient, &cfg.CacheURL, &cfg.ExtCacheURL, r.MetricsEngine)
 
-	adapters, singleFormatAdapters, adaptersErrs := exchange.BuildAdapters(generalHttpClient, cfg, cfg.BidderInfos, r.MetricsEngine)
-	if len(adaptersErrs) > 0 {
-		errs := errortypes.NewAggregateError("Failed to initialize adapters", adaptersErrs)
-		return nil, errs
-	}
 	adsCertSigner, err := adscert.NewAdCertsSigner(cfg.Experiment.AdCerts)
 	if err != nil {
 		glog.Fatalf("Failed to create ads cert signer: %v", err)
 	}
 
-	requestValidator := ortb.NewRequestValidator(activeBidders, disabledBidders, paramsValidator)
 	priceFloorFetcher := floors.NewPriceFloorFetcher(cfg.PriceFloors, floorFechterHttpClient, r.MetricsEngine)
-
-	tmaxAdjustments := exchange.ProcessTMaxAdjustments(cfg.TmaxAdjustments)
-	planBuilder := hooks.NewExecutionPlanBuilder(cfg.Hooks, repo)
-	macroReplacer := macros.NewStringIndexBasedReplacer()
-	theExchange := exchange.NewExchange(adapters, cacheClient, cfg, requestValidator, syncersByBidder, r.MetricsEngine, cfg.BidderInfos, gdprPermsBuilder, rateConvertor, categoriesFetcher, adsCertSigner, macroReplacer, priceFloorFetcher, singleFormatAdapters)
-	var uuidGenerator uuidutil.UUIDRandomGenerator
-	openrtbEndpoint, err := openrtb2.NewEndpoint(uuidGenerator, theExchange, requestValidator, fetcher, accounts, cfg, r.MetricsEngine, analyticsRunner, disabledBidders, defReqJSON, activeBidders, storedRespFetcher, planBuilder, tmaxAdjustments)
-	if err != nil {
-		glog.Fatalf("Failed to create the openrtb2 endpoint handler. %v", err)
-	}
-
-	ampEndpoint, err := openrtb2.NewAmpEndpoint(uuidGenerator, theExchange, requestValidator, ampFetcher, accounts, cfg, r.MetricsEngine, analyticsRunner, disabledBidders, defReqJSON, activeBidders, storedRespFetcher, planBuilder, tmaxAdjustments)
-	if err != nil {
-		glog.Fatalf("Failed to create the amp endpoint handler. %v", err)
-	}
-
-	videoEndpoint, err := openrtb2.NewVideoEndpoint(uuidGenerator, theExchange, requestValidator, fetcher, videoFetcher, accounts, cfg, r.MetricsEngine, analyticsRunner, disabledBidders, defReqJSON, activeBidders, cacheClient, tmaxAdjustments)
+	if priceFloorFetcher != nil {
+		tracker.RegisterChan("price_floors_fetcher", priceFloorFetcher.Running())
+	}
+
+	r.LineItems = lineitems.NewService(&timeutil.RealTime{}, time.Duration(cfg.LineItems.WinTTLSeconds)*time.Second)
+
+	var frequencyCaps *frequencycap.Service
+	if cfg.FrequencyCapping.Enabled {
+		frequencyCaps = frequencycap.NewService(frequencycap.NewMemoryStore(&timeutil.RealTime{}), &timeutil.RealTime{}, time.Duration(cfg.FrequencyCapping.ServedTTLSeconds)*time.Second)
+	}
+
+	r.cfg = cfg
+	r.handlerBuilder = &handlerBuilder{
+		httpClient:        generalHttpClient,
+		cacheClient:       cacheClient,
+		paramsValidator:   paramsValidator,
+		syncersByBidder:   syncersByBidder,
+		metricsEngine:     r.MetricsEngine,
+		gdprPermsBuilder:  gdprPermsBuilder,
+		tcf2CfgBuilder:    gdpr.NewTCF2Config,
+		rateConvertor:     rateConvertor,
+		categoriesFetcher: categoriesFetcher,
+		adsCertSigner:     adsCertSigner,
+		macroReplacer:     macros.NewStringIndexBasedReplacer(),
+		priceFloorFetcher: priceFloorFetcher,
+		lineItems:         r.LineItems,
+		frequencyCaps:     frequencyCaps,
+		recordingWriter:   writer.NewWriter(cfg.StoredResponseRecording),
+		fetcher:           fetcher,
+		ampFetcher:        ampFetcher,
+		videoFetcher:      videoFetcher,
+		storedRespFetcher: storedRespFetcher,
+		accounts:          accounts,
+		analyticsRunner:   analyticsRunner,
+		defReqJSON:        defReqJSON,
+		tmaxAdjustments:   exchange.ProcessTMaxAdjustments(cfg.TmaxAdjustments),
+		hookRepository:    repo,
+	}
+	handlers, err := r.handlerBuilder.build(cfg)
 	if err != nil {
-		glog.Fatalf("Failed to create the video endpoint handler. %v", err)
-	}
-
-	requestTimeoutHeaders := config.RequestTimeoutHeaders{}
-	if cfg.RequestTimeoutHeaders != requestTimeoutHeaders {
-		videoEndpoint = aspects.QueuedRequestTimeout(videoEndpoint, cfg.RequestTimeoutHeaders, r.MetricsEngine, metrics.ReqTypeVideo)
+		return nil, err
 	}
+	r.handlers.Store(handlers)
 
-	r.POST("/openrtb2/auction", openrtbEndpoint)
-	r.POST("/openrtb2/video", videoEndpoint)
-	r.GET("/openrtb2/amp", ampEndpoint)
-	r.GET("/info/bidders", infoEndpoints.NewBiddersEndpoint(cfg.BidderInfos))
-	r.GET("/info/bidders/:bidderName", infoEndpoints.NewBiddersDetailEndpoint(cfg.BidderInfos))
+	r.POST("/openrtb2/auction", r.reloadable(func(h *reloadableHandlers) httprouter.Handle { return h.auction }))
+	r.POST("/openrtb3/auction", r.reloadable(func(h *reloadableHandlers) httprouter.Handle { return h.openrtb3Auction }))
+	r.POST("/openrtb2/video", r.reloadable(func(h *reloadableHandlers) httprouter.Handle { return h.video }))
+	r.GET("/openrtb2/amp", r.reloadable(func(h *reloadableHandlers) httprouter.Handle { return h.amp }))
+	r.GET("/info/bidders", r.reloadable(func(h *reloadableHandlers) httprouter.Handle { return h.biddersInfo }))
+	r.GET("/info/bidders/:bidderName", r.reloadable(func(h *reloadableHandlers) httprouter.Handle { return h.bidderInfo }))
 	r.GET("/bidders/params", NewJsonDirectoryServer(schemaDirectory, paramsValidator))
-	r.POST("/cookie_sync", endpoints.NewCookieSyncEndpoint(syncersByBidder, cfg, gdprPermsBuilder, tcf2CfgBuilder, r.MetricsEngine, analyticsRunner, accounts, activeBidders).Handle)
-	r.GET("/status", endpoints.NewStatusEndpoint(cfg.StatusResponse))
+	r.POST("/cookie_sync", r.reloadable(func(h *reloadableHandlers) httprouter.Handle { return h.cookieSync }))
+	r.GET("/status", endpoints.NewStatusEndpoint(cfg.StatusResponse, tracker))
 	r.GET("/", serveIndex)
 	r.Handler("GET", "/version", endpoints.NewVersionEndpoint(version.Ver, version.Rev))
 	r.ServeFiles("/static/*filepath", http.Dir("static"))
 
 	// vtrack endpoint
 	if cfg.VTrack.Enabled {
-		vtrackEndpoint := events.NewVTrackEndpoint(cfg, accounts, cacheClient, cfg.BidderInfos, r.MetricsEngine)
-		r.POST("/vtrack", vtrackEndpoint)
+		r.POST("/vtrack", r.reloadable(func(h *reloadableHandlers) httprouter.Handle { return h.vtrack }))
 	}
 
 	// event endpoint
-	eventEndpoint := events.NewEventEndpoint(cfg, accounts, analyticsRunner, r.MetricsEngine)
-	r.GET("/event", eventEndpoint)
+	r.GET("/event", r.reloadable(func(h *reloadableHandlers) httprouter.Handle { return h.event }))
 
 	userSyncDeps := &pbs.UserSyncDeps{
 		HostCookieConfig: &(cfg.HostCookie),
@@ -309,8 +338,9 @@ func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *R
 		CertPool:         certPool,
 	}
 
-	r.GET("/setuid", endpoints.NewSetUIDEndpoint(cfg, syncersByBidder, gdprPermsBuilder, tcf2CfgBuilder, analyticsRunner, accounts, r.MetricsEngine))
+	r.GET("/setuid", r.reloadable(func(h *reloadableHandlers) httprouter.Handle { return h.setUID }))
 	r.GET("/getuids", endpoints.NewGetUIDsEndpoint(cfg.HostCookie))
+	r.GET("/debug/tcf", r.reloadable(func(h *reloadableHandlers) httprouter.Handle { return h.tcfDebug }))
 	r.POST("/optout", userSyncDeps.OptOut)
 	r.GET("/optout", userSyncDeps.OptOut)
 
@@ -331,6 +361,21 @@ func (r *Router) Shutdown() {
 	glog.Info("[PBS Router] shut down")
 }
 
+// ShutdownWithin is Shutdown, giving up on the dependencies still draining after timeout.
+func (r *Router) ShutdownWithin(timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		r.Shutdown()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		glog.Warningf("[PBS Router] gave up waiting for the shut down after %v", timeout)
+	}
+}
+
 func checkSupportedUserSyncEndpoints(bidderInfos config.BidderInfos) error {
 	for name, info := range bidderInfos {
 		if info.Syncer == nil {
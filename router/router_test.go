@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	jsoniter "github.com/json-iterator/go"
 	"github.com/prebid/prebid-server/v3/config"
@@ -317,3 +318,26 @@ func TestGetNormalizedGeoscopes(t *testing.T) {
 		})
 	}
 }
+
+func TestShutdownWithin(t *testing.T) {
+	analyticsShutDown := make(chan struct{})
+	release := make(chan struct{})
+	r := &Router{shutdowns: []func(){
+		func() { close(analyticsShutDown) },
+		func() { <-release },
+	}}
+	defer close(release)
+
+	start := time.Now()
+	r.ShutdownWithin(20 * time.Millisecond)
+
+	assert.Eventually(t, func() bool {
+		select {
+		case <-analyticsShutDown:
+			return true
+		default:
+			return false
+		}
+	}, time.Second, time.Millisecond)
+	assert.Less(t, time.Since(start), time.Second, "a dependency which doesn't shut down doesn't block")
+}
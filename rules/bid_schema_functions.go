@@ -0,0 +1,310 @@
+package rules
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+const (
+	Adomain         = "adomain"
+	AdomainIn       = "adomainIn"
+	Bidder          = "bidder"
+	BidPriceBucket  = "bidPriceBucket"
+	DealID          = "dealId"
+	DealIDAvailable = "dealIdAvailable"
+	MediaType       = "mediaType"
+)
+
+// BidderRequest is the payload of the rules evaluated on the request sent to a single bidder.
+type BidderRequest struct {
+	Request *openrtb_ext.RequestWrapper
+	Bidder  string
+}
+
+// Bid is the payload of the rules evaluated on each bid returned by a bidder. The price is in the bidder's
+// currency on raw bidder responses and in the request currency once the responses are processed.
+type Bid struct {
+	Bidder  string
+	Bid     *openrtb2.Bid
+	BidType openrtb_ext.BidType
+}
+
+// NewBidderRequestSchemaFunction returns the specified schema function that operates on the request of a bidder.
+// Besides the bidder name, every request schema function is available and is evaluated on the bidder request.
+func NewBidderRequestSchemaFunction(name string, params json.RawMessage) (SchemaFunction[BidderRequest], error) {
+	if name == Bidder {
+		if err := checkNilArgs(params, Bidder); err != nil {
+			return nil, err
+		}
+		return &bidderRequestBidder{}, nil
+	}
+
+	requestSchemaFunction, err := NewRequestSchemaFunction(name, params)
+	if err != nil {
+		return nil, err
+	}
+	return &bidderRequestSchemaFunction{requestSchemaFunction: requestSchemaFunction}, nil
+}
+
+// NewBidSchemaFunction returns the specified schema function that operates on a bid along with any schema
+// function args validation errors that occurred during instantiation
+func NewBidSchemaFunction(name string, params json.RawMessage) (SchemaFunction[Bid], error) {
+	switch name {
+	case Bidder:
+		return NewBidBidder(params)
+	case BidPriceBucket:
+		return NewBidPriceBucket(params)
+	case MediaType:
+		return NewMediaType(params)
+	case Adomain:
+		return NewAdomain(params)
+	case AdomainIn:
+		return NewAdomainIn(params)
+	case DealID:
+		return NewDealID(params)
+	case DealIDAvailable:
+		return NewDealIDAvailable(params)
+	default:
+		return nil, fmt.Errorf("Schema function %s was not created", name)
+	}
+}
+
+// ------------bidderRequest----------------
+type bidderRequestSchemaFunction struct {
+	requestSchemaFunction SchemaFunction[openrtb_ext.RequestWrapper]
+}
+
+func (f *bidderRequestSchemaFunction) Call(payload *BidderRequest) (string, error) {
+	if payload == nil {
+		return f.requestSchemaFunction.Call(nil)
+	}
+	return f.requestSchemaFunction.Call(payload.Request)
+}
+
+func (f *bidderRequestSchemaFunction) Name() string {
+	return f.requestSchemaFunction.Name()
+}
+
+type bidderRequestBidder struct{}
+
+func (b *bidderRequestBidder) Call(payload *BidderRequest) (string, error) {
+	if payload == nil {
+		return "", nil
+	}
+	return payload.Bidder, nil
+}
+
+func (b *bidderRequestBidder) Name() string {
+	return Bidder
+}
+
+// ------------bidder-----------------------
+type bidBidder struct{}
+
+func NewBidBidder(params json.RawMessage) (SchemaFunction[Bid], error) {
+	if err := checkNilArgs(params, Bidder); err != nil {
+		return nil, err
+	}
+	return &bidBidder{}, nil
+}
+
+func (b *bidBidder) Call(payload *Bid) (string, error) {
+	if payload == nil {
+		return "", nil
+	}
+	return payload.Bidder, nil
+}
+
+func (b *bidBidder) Name() string {
+	return Bidder
+}
+
+// ------------bidPriceBucket---------------
+type bidPriceBucket struct {
+	Buckets []float64 `json:"buckets"`
+}
+
+// NewBidPriceBucket creates a schema function returning the price range of the bid within the ascending bucket
+// boundaries, formatted as "{min}-{max}", or "{max}+" above the last boundary. Buckets [1, 5] give "0-1", "1-5"
+// and "5+".
+func NewBidPriceBucket(params json.RawMessage) (SchemaFunction[Bid], error) {
+	schemaFunc := &bidPriceBucket{}
+	if err := jsonutil.Unmarshal(params, schemaFunc); err != nil {
+		return nil, err
+	}
+
+	if err := validateBuckets(schemaFunc.Buckets, BidPriceBucket); err != nil {
+		return nil, err
+	}
+	return schemaFunc, nil
+}
+
+func (bpb *bidPriceBucket) Call(payload *Bid) (string, error) {
+	if payload == nil || payload.Bid == nil {
+		return "", nil
+	}
+	return priceBucket(bpb.Buckets, payload.Bid.Price), nil
+}
+
+func (bpb *bidPriceBucket) Name() string {
+	return BidPriceBucket
+}
+
+func validateBuckets(buckets []float64, funcName string) error {
+	if len(buckets) == 0 {
+		return fmt.Errorf("Missing buckets argument for %s schema function", funcName)
+	}
+	for i := 0; i < len(buckets); i++ {
+		if buckets[i] <= 0 || (i > 0 && buckets[i] <= buckets[i-1]) {
+			return fmt.Errorf("%s schema function buckets must be positive and ascending", funcName)
+		}
+	}
+	return nil
+}
+
+// priceBucket returns the range of the ascending bucket boundaries the price falls in.
+func priceBucket(buckets []float64, price float64) string {
+	lower := 0.0
+	for _, upper := range buckets {
+		if price < upper {
+			return formatPrice(lower) + "-" + formatPrice(upper)
+		}
+		lower = upper
+	}
+	return formatPrice(lower) + "+"
+}
+
+func formatPrice(price float64) string {
+	return strconv.FormatFloat(price, 'f', -1, 64)
+}
+
+// ------------mediaType--------------------
+type mediaType struct{}
+
+func NewMediaType(params json.RawMessage) (SchemaFunction[Bid], error) {
+	if err := checkNilArgs(params, MediaType); err != nil {
+		return nil, err
+	}
+	return &mediaType{}, nil
+}
+
+func (mt *mediaType) Call(payload *Bid) (string, error) {
+	if payload == nil {
+		return "", nil
+	}
+	return string(payload.BidType), nil
+}
+
+func (mt *mediaType) Name() string {
+	return MediaType
+}
+
+// ------------adomain----------------------
+type adomain struct{}
+
+func NewAdomain(params json.RawMessage) (SchemaFunction[Bid], error) {
+	if err := checkNilArgs(params, Adomain); err != nil {
+		return nil, err
+	}
+	return &adomain{}, nil
+}
+
+// Call returns the first advertiser domain of the bid, which is the advertiser domain for nearly all bids.
+func (a *adomain) Call(payload *Bid) (string, error) {
+	if payload == nil || payload.Bid == nil || len(payload.Bid.ADomain) == 0 {
+		return "", nil
+	}
+	return payload.Bid.ADomain[0], nil
+}
+
+func (a *adomain) Name() string {
+	return Adomain
+}
+
+// ------------adomainIn--------------------
+type adomainIn struct {
+	Domains   []string `json:"domains"`
+	DomainDir map[string]struct{}
+}
+
+func NewAdomainIn(params json.RawMessage) (SchemaFunction[Bid], error) {
+	schemaFunc := &adomainIn{}
+	if err := jsonutil.Unmarshal(params, schemaFunc); err != nil {
+		return nil, err
+	}
+
+	if len(schemaFunc.Domains) == 0 {
+		return nil, errors.New("Missing domains argument for adomainIn schema function")
+	}
+
+	schemaFunc.DomainDir = make(map[string]struct{})
+	for i := 0; i < len(schemaFunc.Domains); i++ {
+		schemaFunc.DomainDir[schemaFunc.Domains[i]] = struct{}{}
+	}
+	return schemaFunc, nil
+}
+
+func (ai *adomainIn) Call(payload *Bid) (string, error) {
+	if payload == nil || payload.Bid == nil {
+		return "false", nil
+	}
+
+	found := slices.ContainsFunc(payload.Bid.ADomain, func(domain string) bool {
+		_, ok := ai.DomainDir[domain]
+		return ok
+	})
+	return fmt.Sprintf("%t", found), nil
+}
+
+func (ai *adomainIn) Name() string {
+	return AdomainIn
+}
+
+// ------------dealId-----------------------
+type dealID struct{}
+
+func NewDealID(params json.RawMessage) (SchemaFunction[Bid], error) {
+	if err := checkNilArgs(params, DealID); err != nil {
+		return nil, err
+	}
+	return &dealID{}, nil
+}
+
+func (d *dealID) Call(payload *Bid) (string, error) {
+	if payload == nil || payload.Bid == nil {
+		return "", nil
+	}
+	return payload.Bid.DealID, nil
+}
+
+func (d *dealID) Name() string {
+	return DealID
+}
+
+// ------------dealIdAvailable--------------
+type dealIDAvailable struct{}
+
+func NewDealIDAvailable(params json.RawMessage) (SchemaFunction[Bid], error) {
+	if err := checkNilArgs(params, DealIDAvailable); err != nil {
+		return nil, err
+	}
+	return &dealIDAvailable{}, nil
+}
+
+func (d *dealIDAvailable) Call(payload *Bid) (string, error) {
+	if payload == nil || payload.Bid == nil {
+		return "false", nil
+	}
+	return fmt.Sprintf("%t", len(payload.Bid.DealID) > 0), nil
+}
+
+func (d *dealIDAvailable) Name() string {
+	return DealIDAvailable
+}
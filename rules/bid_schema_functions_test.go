@@ -0,0 +1,270 @@
+package rules
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBidderRequestSchemaFunction(t *testing.T) {
+	testCases := []struct {
+		name           string
+		inFunctionName string
+		inParams       json.RawMessage
+		inPayload      *BidderRequest
+		expectedName   string
+		expectedResult string
+		expectedError  bool
+	}{
+		{
+			name:           "bidder",
+			inFunctionName: Bidder,
+			inPayload:      &BidderRequest{Bidder: "appnexus"},
+			expectedName:   Bidder,
+			expectedResult: "appnexus",
+		},
+		{
+			name:           "bidder-with-args",
+			inFunctionName: Bidder,
+			inParams:       json.RawMessage(`{"bidder":"appnexus"}`),
+			expectedError:  true,
+		},
+		{
+			name:           "request-schema-function",
+			inFunctionName: DeviceCountry,
+			inPayload: &BidderRequest{
+				Bidder: "appnexus",
+				Request: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+					Device: &openrtb2.Device{Geo: &openrtb2.Geo{Country: "USA"}},
+				}},
+			},
+			expectedName:   DeviceCountry,
+			expectedResult: "USA",
+		},
+		{
+			name:           "request-schema-function-nil-payload",
+			inFunctionName: DeviceCountry,
+			expectedName:   DeviceCountry,
+			expectedResult: "",
+		},
+		{
+			name:           "unknown",
+			inFunctionName: "unknown",
+			expectedError:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schemaFunc, err := NewBidderRequestSchemaFunction(tc.inFunctionName, tc.inParams)
+			if tc.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedName, schemaFunc.Name())
+
+			result, err := schemaFunc.Call(tc.inPayload)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedResult, result)
+		})
+	}
+}
+
+func TestNewBidSchemaFunction(t *testing.T) {
+	testCases := []struct {
+		inFunctionName     string
+		inParams           json.RawMessage
+		expectedSchemaFunc SchemaFunction[Bid]
+		expectedError      error
+	}{
+		{
+			inFunctionName:     Bidder,
+			inParams:           json.RawMessage(`{}`),
+			expectedSchemaFunc: &bidBidder{},
+		},
+		{
+			inFunctionName:     BidPriceBucket,
+			inParams:           json.RawMessage(`{"buckets":[1,5]}`),
+			expectedSchemaFunc: &bidPriceBucket{Buckets: []float64{1, 5}},
+		},
+		{
+			inFunctionName:     MediaType,
+			inParams:           json.RawMessage(`{}`),
+			expectedSchemaFunc: &mediaType{},
+		},
+		{
+			inFunctionName:     Adomain,
+			inParams:           json.RawMessage(`{}`),
+			expectedSchemaFunc: &adomain{},
+		},
+		{
+			inFunctionName: AdomainIn,
+			inParams:       json.RawMessage(`{"domains":["a.com"]}`),
+			expectedSchemaFunc: &adomainIn{
+				Domains:   []string{"a.com"},
+				DomainDir: map[string]struct{}{"a.com": {}},
+			},
+		},
+		{
+			inFunctionName:     DealID,
+			inParams:           json.RawMessage(`{}`),
+			expectedSchemaFunc: &dealID{},
+		},
+		{
+			inFunctionName:     DealIDAvailable,
+			inParams:           json.RawMessage(`{}`),
+			expectedSchemaFunc: &dealIDAvailable{},
+		},
+		{
+			inFunctionName: DeviceCountry,
+			inParams:       json.RawMessage(`{}`),
+			expectedError:  errors.New("Schema function deviceCountry was not created"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.inFunctionName, func(t *testing.T) {
+			schemaFunc, err := NewBidSchemaFunction(tc.inFunctionName, tc.inParams)
+			assert.Equal(t, tc.expectedSchemaFunc, schemaFunc)
+			assert.Equal(t, tc.expectedError, err)
+			if schemaFunc != nil {
+				assert.Equal(t, tc.inFunctionName, schemaFunc.Name())
+			}
+		})
+	}
+}
+
+func TestConstructorsOfParamLessBidSchemaFunctions(t *testing.T) {
+	constructors := map[string]func(params json.RawMessage) (SchemaFunction[Bid], error){
+		Bidder:          NewBidBidder,
+		MediaType:       NewMediaType,
+		Adomain:         NewAdomain,
+		DealID:          NewDealID,
+		DealIDAvailable: NewDealIDAvailable,
+	}
+
+	for name, constructor := range constructors {
+		t.Run(name, func(t *testing.T) {
+			_, err := constructor(json.RawMessage(`{}`))
+			assert.NoError(t, err)
+
+			_, err = constructor(json.RawMessage(`{"someArgument": "someValue"}`))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNewBidPriceBucket(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		inParams      json.RawMessage
+		expectedError string
+	}{
+		{
+			desc:     "valid",
+			inParams: json.RawMessage(`{"buckets":[0.5,1,5]}`),
+		},
+		{
+			desc:          "missing-buckets",
+			inParams:      json.RawMessage(`{}`),
+			expectedError: "Missing buckets argument for bidPriceBucket schema function",
+		},
+		{
+			desc:          "not-ascending",
+			inParams:      json.RawMessage(`{"buckets":[5,1]}`),
+			expectedError: "bidPriceBucket schema function buckets must be positive and ascending",
+		},
+		{
+			desc:          "not-positive",
+			inParams:      json.RawMessage(`{"buckets":[0,1]}`),
+			expectedError: "bidPriceBucket schema function buckets must be positive and ascending",
+		},
+		{
+			desc:          "malformed",
+			inParams:      json.RawMessage(`{"buckets":"1"}`),
+			expectedError: "cannot unmarshal",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := NewBidPriceBucket(tc.inParams)
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestBidPriceBucketCall(t *testing.T) {
+	schemaFunc := &bidPriceBucket{Buckets: []float64{0.5, 1, 5}}
+
+	testCases := []struct {
+		desc     string
+		payload  *Bid
+		expected string
+	}{
+		{desc: "nil-payload", expected: ""},
+		{desc: "nil-bid", payload: &Bid{}, expected: ""},
+		{desc: "first-bucket", payload: &Bid{Bid: &openrtb2.Bid{Price: 0.2}}, expected: "0-0.5"},
+		{desc: "lower-boundary", payload: &Bid{Bid: &openrtb2.Bid{Price: 1}}, expected: "1-5"},
+		{desc: "above-last-boundary", payload: &Bid{Bid: &openrtb2.Bid{Price: 12}}, expected: "5+"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			result, err := schemaFunc.Call(tc.payload)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestBidSchemaFunctionsCall(t *testing.T) {
+	adomainInFunc, err := NewAdomainIn(json.RawMessage(`{"domains":["b.com"]}`))
+	assert.NoError(t, err)
+
+	bid := &Bid{
+		Bidder:  "appnexus",
+		BidType: openrtb_ext.BidTypeVideo,
+		Bid:     &openrtb2.Bid{ADomain: []string{"a.com", "b.com"}, DealID: "deal-1"},
+	}
+	emptyBid := &Bid{Bid: &openrtb2.Bid{}}
+
+	testCases := []struct {
+		schemaFunc    SchemaFunction[Bid]
+		payload       *Bid
+		expected      string
+		expectedEmpty string
+	}{
+		{schemaFunc: &bidBidder{}, payload: bid, expected: "appnexus", expectedEmpty: ""},
+		{schemaFunc: &mediaType{}, payload: bid, expected: "video", expectedEmpty: ""},
+		{schemaFunc: &adomain{}, payload: bid, expected: "a.com", expectedEmpty: ""},
+		{schemaFunc: adomainInFunc, payload: bid, expected: "true", expectedEmpty: "false"},
+		{schemaFunc: &dealID{}, payload: bid, expected: "deal-1", expectedEmpty: ""},
+		{schemaFunc: &dealIDAvailable{}, payload: bid, expected: "true", expectedEmpty: "false"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.schemaFunc.Name(), func(t *testing.T) {
+			result, err := tc.schemaFunc.Call(tc.payload)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+
+			result, err = tc.schemaFunc.Call(emptyBid)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedEmpty, result)
+
+			result, err = tc.schemaFunc.Call(nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedEmpty, result)
+		})
+	}
+}
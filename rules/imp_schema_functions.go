@@ -0,0 +1,324 @@
+package rules
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
+)
+
+const (
+	AdUnitCode     = "adUnitCode"
+	BidFloorBucket = "bidFloorBucket"
+	DealsAvailable = "dealsAvailable"
+	Gpid           = "gpid"
+	Size           = "size"
+	SizeIn         = "sizeIn"
+)
+
+// multiFormat is the media type of imps offering more than one media type.
+const multiFormat = "multi"
+
+// Imp is the payload of the rules evaluated on each imp of a request.
+type Imp struct {
+	Request *openrtb_ext.RequestWrapper
+	Imp     *openrtb_ext.ImpWrapper
+}
+
+// NewImpSchemaFunction returns the specified schema function that operates on an imp of the request along with
+// any schema function args validation errors that occurred during instantiation. Besides the imp schema functions,
+// every request schema function is available and is evaluated on the request the imp belongs to.
+func NewImpSchemaFunction(name string, params json.RawMessage) (SchemaFunction[Imp], error) {
+	switch name {
+	case MediaType:
+		return NewImpMediaType(params)
+	case AdUnitCode:
+		return NewAdUnitCode(params)
+	case Gpid:
+		return NewGpid(params)
+	case Size:
+		return NewSize(params)
+	case SizeIn:
+		return NewSizeIn(params)
+	case BidFloorBucket:
+		return NewBidFloorBucket(params)
+	case DealsAvailable:
+		return NewDealsAvailable(params)
+	}
+
+	requestSchemaFunction, err := NewRequestSchemaFunction(name, params)
+	if err != nil {
+		return nil, err
+	}
+	return &impRequestSchemaFunction{requestSchemaFunction: requestSchemaFunction}, nil
+}
+
+// ------------impRequest-------------------
+type impRequestSchemaFunction struct {
+	requestSchemaFunction SchemaFunction[openrtb_ext.RequestWrapper]
+}
+
+func (f *impRequestSchemaFunction) Call(payload *Imp) (string, error) {
+	if payload == nil {
+		return f.requestSchemaFunction.Call(nil)
+	}
+	return f.requestSchemaFunction.Call(payload.Request)
+}
+
+func (f *impRequestSchemaFunction) Name() string {
+	return f.requestSchemaFunction.Name()
+}
+
+// ------------mediaType--------------------
+type impMediaType struct{}
+
+func NewImpMediaType(params json.RawMessage) (SchemaFunction[Imp], error) {
+	if err := checkNilArgs(params, MediaType); err != nil {
+		return nil, err
+	}
+	return &impMediaType{}, nil
+}
+
+// Call returns the media type offered by the imp, or "multi" when the imp offers more than one.
+func (mt *impMediaType) Call(payload *Imp) (string, error) {
+	if payload == nil || payload.Imp == nil || payload.Imp.Imp == nil {
+		return "", nil
+	}
+
+	var mediaTypes []openrtb_ext.BidType
+	if payload.Imp.Banner != nil {
+		mediaTypes = append(mediaTypes, openrtb_ext.BidTypeBanner)
+	}
+	if payload.Imp.Video != nil {
+		mediaTypes = append(mediaTypes, openrtb_ext.BidTypeVideo)
+	}
+	if payload.Imp.Audio != nil {
+		mediaTypes = append(mediaTypes, openrtb_ext.BidTypeAudio)
+	}
+	if payload.Imp.Native != nil {
+		mediaTypes = append(mediaTypes, openrtb_ext.BidTypeNative)
+	}
+
+	switch len(mediaTypes) {
+	case 0:
+		return "", nil
+	case 1:
+		return string(mediaTypes[0]), nil
+	default:
+		return multiFormat, nil
+	}
+}
+
+func (mt *impMediaType) Name() string {
+	return MediaType
+}
+
+// ------------adUnitCode-------------------
+type adUnitCode struct{}
+
+func NewAdUnitCode(params json.RawMessage) (SchemaFunction[Imp], error) {
+	if err := checkNilArgs(params, AdUnitCode); err != nil {
+		return nil, err
+	}
+	return &adUnitCode{}, nil
+}
+
+// Call returns the first of the imp gpid, tag id, pbadslot and stored request id that is set, which is how
+// the ad unit code is resolved by the price floors.
+func (auc *adUnitCode) Call(payload *Imp) (string, error) {
+	if payload == nil || payload.Imp == nil || payload.Imp.Imp == nil {
+		return "", nil
+	}
+
+	impExt, err := payload.Imp.GetImpExt()
+	if err != nil {
+		return "", err
+	}
+	if gpid := impExt.GetGpId(); gpid != "" {
+		return gpid, nil
+	}
+	if payload.Imp.TagID != "" {
+		return payload.Imp.TagID, nil
+	}
+	if data := impExt.GetData(); data != nil && data.PbAdslot != "" {
+		return data.PbAdslot, nil
+	}
+	if prebid := impExt.GetPrebid(); prebid != nil && prebid.StoredRequest != nil {
+		return prebid.StoredRequest.ID, nil
+	}
+	return "", nil
+}
+
+func (auc *adUnitCode) Name() string {
+	return AdUnitCode
+}
+
+// ------------gpid-------------------------
+type gpid struct{}
+
+func NewGpid(params json.RawMessage) (SchemaFunction[Imp], error) {
+	if err := checkNilArgs(params, Gpid); err != nil {
+		return nil, err
+	}
+	return &gpid{}, nil
+}
+
+func (g *gpid) Call(payload *Imp) (string, error) {
+	if payload == nil || payload.Imp == nil || payload.Imp.Imp == nil {
+		return "", nil
+	}
+
+	impExt, err := payload.Imp.GetImpExt()
+	if err != nil {
+		return "", err
+	}
+	return impExt.GetGpId(), nil
+}
+
+func (g *gpid) Name() string {
+	return Gpid
+}
+
+// ------------size-------------------------
+type size struct{}
+
+func NewSize(params json.RawMessage) (SchemaFunction[Imp], error) {
+	if err := checkNilArgs(params, Size); err != nil {
+		return nil, err
+	}
+	return &size{}, nil
+}
+
+// Call returns the first size the imp offers formatted as "{w}x{h}".
+func (s *size) Call(payload *Imp) (string, error) {
+	sizes := impSizes(payload)
+	if len(sizes) == 0 {
+		return "", nil
+	}
+	return sizes[0], nil
+}
+
+func (s *size) Name() string {
+	return Size
+}
+
+// ------------sizeIn-----------------------
+type sizeIn struct {
+	Sizes   []string `json:"sizes"`
+	SizeDir map[string]struct{}
+}
+
+// NewSizeIn creates a schema function telling whether any size offered by the imp is one of the sizes,
+// formatted as "{w}x{h}", which lets a rule group sizes into buckets.
+func NewSizeIn(params json.RawMessage) (SchemaFunction[Imp], error) {
+	schemaFunc := &sizeIn{}
+	if err := jsonutil.Unmarshal(params, schemaFunc); err != nil {
+		return nil, err
+	}
+
+	if len(schemaFunc.Sizes) == 0 {
+		return nil, errors.New("Missing sizes argument for sizeIn schema function")
+	}
+
+	schemaFunc.SizeDir = make(map[string]struct{})
+	for i := 0; i < len(schemaFunc.Sizes); i++ {
+		schemaFunc.SizeDir[schemaFunc.Sizes[i]] = struct{}{}
+	}
+	return schemaFunc, nil
+}
+
+func (si *sizeIn) Call(payload *Imp) (string, error) {
+	found := slices.ContainsFunc(impSizes(payload), func(size string) bool {
+		_, ok := si.SizeDir[size]
+		return ok
+	})
+	return fmt.Sprintf("%t", found), nil
+}
+
+func (si *sizeIn) Name() string {
+	return SizeIn
+}
+
+// impSizes returns the banner formats and sizes followed by the video size of the imp.
+func impSizes(payload *Imp) []string {
+	if payload == nil || payload.Imp == nil || payload.Imp.Imp == nil {
+		return nil
+	}
+
+	var sizes []string
+	if banner := payload.Imp.Banner; banner != nil {
+		for _, format := range banner.Format {
+			if format.W > 0 && format.H > 0 {
+				sizes = append(sizes, formatSize(format.W, format.H))
+			}
+		}
+		if w, h := ptrutil.ValueOrDefault(banner.W), ptrutil.ValueOrDefault(banner.H); w > 0 && h > 0 {
+			sizes = append(sizes, formatSize(w, h))
+		}
+	}
+	if video := payload.Imp.Video; video != nil {
+		if w, h := ptrutil.ValueOrDefault(video.W), ptrutil.ValueOrDefault(video.H); w > 0 && h > 0 {
+			sizes = append(sizes, formatSize(w, h))
+		}
+	}
+	return sizes
+}
+
+func formatSize(w, h int64) string {
+	return fmt.Sprintf("%dx%d", w, h)
+}
+
+// ------------bidFloorBucket---------------
+type bidFloorBucket struct {
+	Buckets []float64 `json:"buckets"`
+}
+
+// NewBidFloorBucket creates a schema function returning the range of the imp bid floor within the ascending
+// bucket boundaries, like bidPriceBucket does for bid prices. The floor is taken in the imp floor currency.
+func NewBidFloorBucket(params json.RawMessage) (SchemaFunction[Imp], error) {
+	schemaFunc := &bidFloorBucket{}
+	if err := jsonutil.Unmarshal(params, schemaFunc); err != nil {
+		return nil, err
+	}
+
+	if err := validateBuckets(schemaFunc.Buckets, BidFloorBucket); err != nil {
+		return nil, err
+	}
+	return schemaFunc, nil
+}
+
+func (bfb *bidFloorBucket) Call(payload *Imp) (string, error) {
+	if payload == nil || payload.Imp == nil || payload.Imp.Imp == nil {
+		return "", nil
+	}
+	return priceBucket(bfb.Buckets, payload.Imp.BidFloor), nil
+}
+
+func (bfb *bidFloorBucket) Name() string {
+	return BidFloorBucket
+}
+
+// ------------dealsAvailable---------------
+type dealsAvailable struct{}
+
+func NewDealsAvailable(params json.RawMessage) (SchemaFunction[Imp], error) {
+	if err := checkNilArgs(params, DealsAvailable); err != nil {
+		return nil, err
+	}
+	return &dealsAvailable{}, nil
+}
+
+func (da *dealsAvailable) Call(payload *Imp) (string, error) {
+	if payload == nil || payload.Imp == nil || payload.Imp.Imp == nil || payload.Imp.PMP == nil {
+		return "false", nil
+	}
+	return fmt.Sprintf("%t", len(payload.Imp.PMP.Deals) > 0), nil
+}
+
+func (da *dealsAvailable) Name() string {
+	return DealsAvailable
+}
@@ -0,0 +1,296 @@
+package rules
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewImpSchemaFunction(t *testing.T) {
+	testCases := []struct {
+		inFunctionName     string
+		inParams           json.RawMessage
+		expectedSchemaFunc SchemaFunction[Imp]
+		expectedError      error
+	}{
+		{
+			inFunctionName:     MediaType,
+			inParams:           json.RawMessage(`{}`),
+			expectedSchemaFunc: &impMediaType{},
+		},
+		{
+			inFunctionName:     AdUnitCode,
+			inParams:           json.RawMessage(`{}`),
+			expectedSchemaFunc: &adUnitCode{},
+		},
+		{
+			inFunctionName:     Gpid,
+			inParams:           json.RawMessage(`{}`),
+			expectedSchemaFunc: &gpid{},
+		},
+		{
+			inFunctionName:     Size,
+			inParams:           json.RawMessage(`{}`),
+			expectedSchemaFunc: &size{},
+		},
+		{
+			inFunctionName: SizeIn,
+			inParams:       json.RawMessage(`{"sizes":["300x250"]}`),
+			expectedSchemaFunc: &sizeIn{
+				Sizes:   []string{"300x250"},
+				SizeDir: map[string]struct{}{"300x250": {}},
+			},
+		},
+		{
+			inFunctionName:     BidFloorBucket,
+			inParams:           json.RawMessage(`{"buckets":[1,5]}`),
+			expectedSchemaFunc: &bidFloorBucket{Buckets: []float64{1, 5}},
+		},
+		{
+			inFunctionName:     DealsAvailable,
+			inParams:           json.RawMessage(`{}`),
+			expectedSchemaFunc: &dealsAvailable{},
+		},
+		{
+			inFunctionName:     Channel,
+			inParams:           json.RawMessage(`{}`),
+			expectedSchemaFunc: &impRequestSchemaFunction{requestSchemaFunction: &channel{}},
+		},
+		{
+			inFunctionName: Bidder,
+			inParams:       json.RawMessage(`{}`),
+			expectedError:  errors.New("Schema function bidder was not created"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.inFunctionName, func(t *testing.T) {
+			schemaFunc, err := NewImpSchemaFunction(tc.inFunctionName, tc.inParams)
+			assert.Equal(t, tc.expectedSchemaFunc, schemaFunc)
+			assert.Equal(t, tc.expectedError, err)
+			if schemaFunc != nil {
+				assert.Equal(t, tc.inFunctionName, schemaFunc.Name())
+			}
+		})
+	}
+}
+
+func TestConstructorsOfParamLessImpSchemaFunctions(t *testing.T) {
+	constructors := map[string]func(params json.RawMessage) (SchemaFunction[Imp], error){
+		MediaType:      NewImpMediaType,
+		AdUnitCode:     NewAdUnitCode,
+		Gpid:           NewGpid,
+		Size:           NewSize,
+		DealsAvailable: NewDealsAvailable,
+	}
+
+	for name, constructor := range constructors {
+		t.Run(name, func(t *testing.T) {
+			_, err := constructor(json.RawMessage(`{}`))
+			assert.NoError(t, err)
+
+			_, err = constructor(json.RawMessage(`{"someArgument": "someValue"}`))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestImpMediaTypeCall(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		imp      *openrtb2.Imp
+		expected string
+	}{
+		{desc: "banner", imp: &openrtb2.Imp{Banner: &openrtb2.Banner{}}, expected: "banner"},
+		{desc: "video", imp: &openrtb2.Imp{Video: &openrtb2.Video{}}, expected: "video"},
+		{desc: "audio", imp: &openrtb2.Imp{Audio: &openrtb2.Audio{}}, expected: "audio"},
+		{desc: "native", imp: &openrtb2.Imp{Native: &openrtb2.Native{}}, expected: "native"},
+		{desc: "multi-format", imp: &openrtb2.Imp{Banner: &openrtb2.Banner{}, Video: &openrtb2.Video{}}, expected: "multi"},
+		{desc: "none", imp: &openrtb2.Imp{}, expected: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			result, err := (&impMediaType{}).Call(&Imp{Imp: &openrtb_ext.ImpWrapper{Imp: tc.imp}})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestAdUnitCodeCall(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		imp      *openrtb2.Imp
+		expected string
+	}{
+		{
+			desc:     "gpid",
+			imp:      &openrtb2.Imp{TagID: "tag", Ext: json.RawMessage(`{"gpid":"/1/home","data":{"pbadslot":"slot"}}`)},
+			expected: "/1/home",
+		},
+		{
+			desc:     "tagid",
+			imp:      &openrtb2.Imp{TagID: "tag", Ext: json.RawMessage(`{"data":{"pbadslot":"slot"}}`)},
+			expected: "tag",
+		},
+		{
+			desc:     "pbadslot",
+			imp:      &openrtb2.Imp{Ext: json.RawMessage(`{"data":{"pbadslot":"slot"},"prebid":{"storedrequest":{"id":"stored"}}}`)},
+			expected: "slot",
+		},
+		{
+			desc:     "stored-request",
+			imp:      &openrtb2.Imp{Ext: json.RawMessage(`{"prebid":{"storedrequest":{"id":"stored"}}}`)},
+			expected: "stored",
+		},
+		{
+			desc:     "none",
+			imp:      &openrtb2.Imp{},
+			expected: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			result, err := (&adUnitCode{}).Call(&Imp{Imp: &openrtb_ext.ImpWrapper{Imp: tc.imp}})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestAdUnitCodeCallMalformedExt(t *testing.T) {
+	_, err := (&adUnitCode{}).Call(&Imp{Imp: &openrtb_ext.ImpWrapper{Imp: &openrtb2.Imp{Ext: json.RawMessage(`malformed`)}}})
+	assert.Error(t, err)
+}
+
+func TestImpSizeCall(t *testing.T) {
+	sizeInFunc, err := NewSizeIn(json.RawMessage(`{"sizes":["728x90","640x480"]}`))
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		desc           string
+		imp            *openrtb2.Imp
+		expectedSize   string
+		expectedSizeIn string
+	}{
+		{
+			desc:           "banner-formats",
+			imp:            &openrtb2.Imp{Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}, {W: 728, H: 90}}}},
+			expectedSize:   "300x250",
+			expectedSizeIn: "true",
+		},
+		{
+			desc:           "banner-size",
+			imp:            &openrtb2.Imp{Banner: &openrtb2.Banner{W: ptrutil.ToPtr[int64](320), H: ptrutil.ToPtr[int64](50)}},
+			expectedSize:   "320x50",
+			expectedSizeIn: "false",
+		},
+		{
+			desc:           "video",
+			imp:            &openrtb2.Imp{Video: &openrtb2.Video{W: ptrutil.ToPtr[int64](640), H: ptrutil.ToPtr[int64](480)}},
+			expectedSize:   "640x480",
+			expectedSizeIn: "true",
+		},
+		{
+			desc:           "no-size",
+			imp:            &openrtb2.Imp{Native: &openrtb2.Native{}},
+			expectedSize:   "",
+			expectedSizeIn: "false",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			payload := &Imp{Imp: &openrtb_ext.ImpWrapper{Imp: tc.imp}}
+
+			result, err := (&size{}).Call(payload)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedSize, result)
+
+			result, err = sizeInFunc.Call(payload)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedSizeIn, result)
+		})
+	}
+}
+
+func TestNewSizeIn(t *testing.T) {
+	_, err := NewSizeIn(json.RawMessage(`{}`))
+	assert.EqualError(t, err, "Missing sizes argument for sizeIn schema function")
+
+	_, err = NewSizeIn(json.RawMessage(`{"sizes":"300x250"}`))
+	assert.ErrorContains(t, err, "cannot unmarshal")
+}
+
+func TestBidFloorBucket(t *testing.T) {
+	_, err := NewBidFloorBucket(json.RawMessage(`{"buckets":[2,1]}`))
+	assert.EqualError(t, err, "bidFloorBucket schema function buckets must be positive and ascending")
+
+	schemaFunc, err := NewBidFloorBucket(json.RawMessage(`{"buckets":[0.5,2]}`))
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		desc     string
+		payload  *Imp
+		expected string
+	}{
+		{desc: "nil-payload", expected: ""},
+		{desc: "no-floor", payload: &Imp{Imp: &openrtb_ext.ImpWrapper{Imp: &openrtb2.Imp{}}}, expected: "0-0.5"},
+		{desc: "middle-bucket", payload: &Imp{Imp: &openrtb_ext.ImpWrapper{Imp: &openrtb2.Imp{BidFloor: 1.25}}}, expected: "0.5-2"},
+		{desc: "above-last-boundary", payload: &Imp{Imp: &openrtb_ext.ImpWrapper{Imp: &openrtb2.Imp{BidFloor: 2}}}, expected: "2+"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			result, err := schemaFunc.Call(tc.payload)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestImpSchemaFunctionsCall(t *testing.T) {
+	imp := &Imp{
+		Request: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+			Device: &openrtb2.Device{Geo: &openrtb2.Geo{Country: "USA"}},
+		}},
+		Imp: &openrtb_ext.ImpWrapper{Imp: &openrtb2.Imp{
+			PMP: &openrtb2.PMP{Deals: []openrtb2.Deal{{ID: "deal-1"}}},
+			Ext: json.RawMessage(`{"gpid":"/1/home"}`),
+		}},
+	}
+	emptyImp := &Imp{Imp: &openrtb_ext.ImpWrapper{Imp: &openrtb2.Imp{}}}
+
+	testCases := []struct {
+		schemaFunc    SchemaFunction[Imp]
+		expected      string
+		expectedEmpty string
+	}{
+		{schemaFunc: &gpid{}, expected: "/1/home", expectedEmpty: ""},
+		{schemaFunc: &dealsAvailable{}, expected: "true", expectedEmpty: "false"},
+		{schemaFunc: &impRequestSchemaFunction{requestSchemaFunction: &deviceCountry{}}, expected: "USA", expectedEmpty: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.schemaFunc.Name(), func(t *testing.T) {
+			result, err := tc.schemaFunc.Call(imp)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+
+			result, err = tc.schemaFunc.Call(emptyImp)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedEmpty, result)
+
+			result, err = tc.schemaFunc.Call(nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedEmpty, result)
+		})
+	}
+}
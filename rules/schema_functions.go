@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 
 	"github.com/buger/jsonparser"
 	"github.com/prebid/openrtb/v20/openrtb2"
@@ -14,11 +15,15 @@ import (
 )
 
 const (
+	Bundle           = "bundle"
 	Channel          = "channel"
 	DataCenter       = "dataCenter"
 	DataCenterIn     = "dataCenterIn"
 	DeviceCountry    = "deviceCountry"
 	DeviceCountryIn  = "deviceCountryIn"
+	DeviceRegion     = "deviceRegion"
+	DeviceType       = "deviceType"
+	Domain           = "domain"
 	EidAvailable     = "eidAvailable"
 	EidIn            = "eidIn"
 	FpdAvailable     = "fpdAvailable"
@@ -43,6 +48,14 @@ func NewRequestSchemaFunction(name string, params json.RawMessage) (SchemaFuncti
 		return NewDeviceCountry(params)
 	case DeviceCountryIn:
 		return NewDeviceCountryIn(params)
+	case DeviceRegion:
+		return NewDeviceRegion(params)
+	case DeviceType:
+		return NewDeviceType(params)
+	case Domain:
+		return NewDomain(params)
+	case Bundle:
+		return NewBundle(params)
 	case DataCenter:
 		return NewDataCenter(params)
 	case DataCenterIn:
@@ -130,6 +143,96 @@ func (dci *deviceCountry) Name() string {
 	return DeviceCountry
 }
 
+// ------------deviceRegion-----------------
+type deviceRegion struct{}
+
+func NewDeviceRegion(params json.RawMessage) (SchemaFunction[openrtb_ext.RequestWrapper], error) {
+	if err := checkNilArgs(params, DeviceRegion); err != nil {
+		return nil, err
+	}
+	return &deviceRegion{}, nil
+}
+
+func (dr *deviceRegion) Call(wrapper *openrtb_ext.RequestWrapper) (string, error) {
+	if deviceGeo := getDeviceGeo(wrapper); deviceGeo != nil {
+		return deviceGeo.Region, nil
+	}
+	return "", nil
+}
+
+func (dr *deviceRegion) Name() string {
+	return DeviceRegion
+}
+
+// ------------deviceType-------------------
+type deviceType struct{}
+
+func NewDeviceType(params json.RawMessage) (SchemaFunction[openrtb_ext.RequestWrapper], error) {
+	if err := checkNilArgs(params, DeviceType); err != nil {
+		return nil, err
+	}
+	return &deviceType{}, nil
+}
+
+func (dt *deviceType) Call(wrapper *openrtb_ext.RequestWrapper) (string, error) {
+	if wrapper != nil && wrapper.BidRequest != nil && wrapper.Device != nil && wrapper.Device.DeviceType != 0 {
+		return strconv.FormatInt(int64(wrapper.Device.DeviceType), 10), nil
+	}
+	return "", nil
+}
+
+func (dt *deviceType) Name() string {
+	return DeviceType
+}
+
+// ------------domain-----------------------
+type domain struct{}
+
+func NewDomain(params json.RawMessage) (SchemaFunction[openrtb_ext.RequestWrapper], error) {
+	if err := checkNilArgs(params, Domain); err != nil {
+		return nil, err
+	}
+	return &domain{}, nil
+}
+
+func (d *domain) Call(wrapper *openrtb_ext.RequestWrapper) (string, error) {
+	if wrapper == nil || wrapper.BidRequest == nil {
+		return "", nil
+	}
+	if wrapper.Site != nil {
+		return wrapper.Site.Domain, nil
+	}
+	if wrapper.App != nil {
+		return wrapper.App.Domain, nil
+	}
+	return "", nil
+}
+
+func (d *domain) Name() string {
+	return Domain
+}
+
+// ------------bundle-----------------------
+type bundle struct{}
+
+func NewBundle(params json.RawMessage) (SchemaFunction[openrtb_ext.RequestWrapper], error) {
+	if err := checkNilArgs(params, Bundle); err != nil {
+		return nil, err
+	}
+	return &bundle{}, nil
+}
+
+func (b *bundle) Call(wrapper *openrtb_ext.RequestWrapper) (string, error) {
+	if wrapper != nil && wrapper.BidRequest != nil && wrapper.App != nil {
+		return wrapper.App.Bundle, nil
+	}
+	return "", nil
+}
+
+func (b *bundle) Name() string {
+	return Bundle
+}
+
 // ------------datacenter-------------------
 
 type dataCenter struct{}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/prebid/openrtb/v20/adcom1"
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v3/errortypes"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
@@ -363,6 +364,162 @@ func TestDeviceCountryCall(t *testing.T) {
 	}
 }
 
+func TestDeviceRegionCall(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		inWrapper      *openrtb_ext.RequestWrapper
+		expectedRegion string
+	}{
+		{
+			desc: "nil wrapper.bidRequest.device.geo",
+			inWrapper: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					Device: &openrtb2.Device{},
+				},
+			},
+		},
+		{
+			desc: "valid wrapper.bidRequest.device.geo.region",
+			inWrapper: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					Device: &openrtb2.Device{
+						Geo: &openrtb2.Geo{
+							Country: "USA",
+							Region:  "VA",
+						},
+					},
+				},
+			},
+			expectedRegion: "VA",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			dr := &deviceRegion{}
+
+			region, err := dr.Call(tc.inWrapper)
+			assert.Equal(t, tc.expectedRegion, region)
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestDeviceTypeCall(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		inWrapper    *openrtb_ext.RequestWrapper
+		expectedType string
+	}{
+		{
+			desc:      "nil wrapper.bidRequest.device",
+			inWrapper: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{}},
+		},
+		{
+			desc: "empty wrapper.bidRequest.device.devicetype",
+			inWrapper: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					Device: &openrtb2.Device{},
+				},
+			},
+		},
+		{
+			desc: "valid wrapper.bidRequest.device.devicetype",
+			inWrapper: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					Device: &openrtb2.Device{
+						DeviceType: adcom1.DevicePhone,
+					},
+				},
+			},
+			expectedType: "4",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			dt := &deviceType{}
+
+			deviceType, err := dt.Call(tc.inWrapper)
+			assert.Equal(t, tc.expectedType, deviceType)
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestDomainCall(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		inWrapper      *openrtb_ext.RequestWrapper
+		expectedDomain string
+	}{
+		{
+			desc:      "nil wrapper.bidRequest.site and app",
+			inWrapper: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{}},
+		},
+		{
+			desc: "wrapper.bidRequest.site.domain",
+			inWrapper: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					Site: &openrtb2.Site{Domain: "site.com"},
+				},
+			},
+			expectedDomain: "site.com",
+		},
+		{
+			desc: "wrapper.bidRequest.app.domain",
+			inWrapper: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					App: &openrtb2.App{Domain: "app.com", Bundle: "com.app"},
+				},
+			},
+			expectedDomain: "app.com",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			d := &domain{}
+
+			domain, err := d.Call(tc.inWrapper)
+			assert.Equal(t, tc.expectedDomain, domain)
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestBundleCall(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		inWrapper      *openrtb_ext.RequestWrapper
+		expectedBundle string
+	}{
+		{
+			desc: "nil wrapper.bidRequest.app",
+			inWrapper: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					Site: &openrtb2.Site{Domain: "site.com"},
+				},
+			},
+		},
+		{
+			desc: "wrapper.bidRequest.app.bundle",
+			inWrapper: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					App: &openrtb2.App{Domain: "app.com", Bundle: "com.app"},
+				},
+			},
+			expectedBundle: "com.app",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			b := &bundle{}
+
+			bundle, err := b.Call(tc.inWrapper)
+			assert.Equal(t, tc.expectedBundle, bundle)
+			assert.Nil(t, err)
+		})
+	}
+}
+
 func TestDataCenterCall(t *testing.T) {
 	testCases := []struct {
 		desc           string
@@ -2019,6 +2176,26 @@ func TestConstructorsOfParamLessSchemaFunctions(t *testing.T) {
 			constructorFunc:    NewDeviceCountry,
 			expectedSchemaFunc: &deviceCountry{},
 		},
+		{
+			schemaFuncName:     DeviceRegion,
+			constructorFunc:    NewDeviceRegion,
+			expectedSchemaFunc: &deviceRegion{},
+		},
+		{
+			schemaFuncName:     DeviceType,
+			constructorFunc:    NewDeviceType,
+			expectedSchemaFunc: &deviceType{},
+		},
+		{
+			schemaFuncName:     Domain,
+			constructorFunc:    NewDomain,
+			expectedSchemaFunc: &domain{},
+		},
+		{
+			schemaFuncName:     Bundle,
+			constructorFunc:    NewBundle,
+			expectedSchemaFunc: &bundle{},
+		},
 		{
 			schemaFuncName:     DataCenter,
 			constructorFunc:    NewDataCenter,
@@ -2078,6 +2255,10 @@ func TestSchemaFunctionsName(t *testing.T) {
 		expectedSchemaFuncName string
 		inSchemaFunc           SchemaFunction[openrtb_ext.RequestWrapper]
 	}{
+		{
+			expectedSchemaFuncName: Bundle,
+			inSchemaFunc:           &bundle{},
+		},
 		{
 			expectedSchemaFuncName: Channel,
 			inSchemaFunc:           &channel{},
@@ -2098,6 +2279,18 @@ func TestSchemaFunctionsName(t *testing.T) {
 			expectedSchemaFuncName: DeviceCountryIn,
 			inSchemaFunc:           &deviceCountryIn{},
 		},
+		{
+			expectedSchemaFuncName: DeviceRegion,
+			inSchemaFunc:           &deviceRegion{},
+		},
+		{
+			expectedSchemaFuncName: DeviceType,
+			inSchemaFunc:           &deviceType{},
+		},
+		{
+			expectedSchemaFuncName: Domain,
+			inSchemaFunc:           &domain{},
+		},
 		{
 			expectedSchemaFuncName: EidAvailable,
 			inSchemaFunc:           &eidAvailable{},
@@ -2146,6 +2339,11 @@ func TestNewRequestSchemaFunction(t *testing.T) {
 		expectedSchemaFunc SchemaFunction[openrtb_ext.RequestWrapper]
 		expectedError      error
 	}{
+		{
+			inFunctionName:     Bundle,
+			inParams:           json.RawMessage(`{}`),
+			expectedSchemaFunc: &bundle{},
+		},
 		{
 			inFunctionName:     Channel,
 			inParams:           json.RawMessage(`{}`),
@@ -2181,6 +2379,21 @@ func TestNewRequestSchemaFunction(t *testing.T) {
 				},
 			},
 		},
+		{
+			inFunctionName:     DeviceRegion,
+			inParams:           json.RawMessage(`{}`),
+			expectedSchemaFunc: &deviceRegion{},
+		},
+		{
+			inFunctionName:     DeviceType,
+			inParams:           json.RawMessage(`{}`),
+			expectedSchemaFunc: &deviceType{},
+		},
+		{
+			inFunctionName:     Domain,
+			inParams:           json.RawMessage(`{}`),
+			expectedSchemaFunc: &domain{},
+		},
 		{
 			inFunctionName:     EidAvailable,
 			inParams:           json.RawMessage(`{}`),
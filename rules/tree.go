@@ -47,9 +47,16 @@ type Tree[T1 any, T2 any] struct {
 // If the result matches one of the node values on the next level, we move to that node, otherwise we exit.
 // If a leaf node is reached, it's result functions are executed on the provided result payload.
 func (t *Tree[T1, T2]) Run(payload *T1, result *T2) error {
+	_, err := t.Trace(payload, result)
+	return err
+}
+
+// Trace runs the tree like Run does and returns the schema function results seen along the way and the rule
+// that fired, which lets the path taken through the tree be inspected without relying on the result functions.
+func (t *Tree[T1, T2]) Trace(payload *T1, result *T2) (ResultFunctionMeta, error) {
 	var nodeKey string
 	if t.Root == nil {
-		return errors.New("tree root is nil")
+		return ResultFunctionMeta{}, errors.New("tree root is nil")
 	}
 	currNode := t.Root
 
@@ -60,12 +67,12 @@ func (t *Tree[T1, T2]) Run(payload *T1, result *T2) error {
 
 	for !currNode.isLeaf() {
 		if currNode.SchemaFunction == nil {
-			return errors.New("schema function is nil")
+			return resFuncMeta, errors.New("schema function is nil")
 		}
 
 		res, err := currNode.SchemaFunction.Call(payload)
 		if err != nil {
-			return err
+			return resFuncMeta, err
 		}
 		resFuncMeta.appendToSchemaFunctionResults(currNode.SchemaFunction.Name(), res)
 
@@ -84,11 +91,11 @@ func (t *Tree[T1, T2]) Run(payload *T1, result *T2) error {
 
 	for _, rf := range resultFuncs {
 		if err := rf.Call(payload, result, resFuncMeta); err != nil {
-			return err
+			return resFuncMeta, err
 		}
 	}
 
-	return nil
+	return resFuncMeta, nil
 }
 
 // validate checks if the tree is well-formed which means all leaves are at the same depth.
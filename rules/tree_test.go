@@ -567,6 +567,93 @@ func TestRun(t *testing.T) {
 	}
 }
 
+func TestTrace(t *testing.T) {
+	tests := []struct {
+		name         string
+		inTree       *Tree[struct{}, runTestAssertableData]
+		expectedMeta ResultFunctionMeta
+		expectedErr  error
+	}{
+		{
+			name:        "Nil_tree.Root",
+			inTree:      &Tree[struct{}, runTestAssertableData]{},
+			expectedErr: errors.New("tree root is nil"),
+		},
+		{
+			name: "Leaf_without_result_functions",
+			inTree: &Tree[struct{}, runTestAssertableData]{
+				AnalyticsKey: "key",
+				ModelVersion: "version",
+				Root: &Node[struct{}, runTestAssertableData]{
+					SchemaFunction: &nodeSchemaFunction{},
+					Children: map[string]*Node[struct{}, runTestAssertableData]{
+						"nodeSchemaResult": {},
+					},
+				},
+			},
+			expectedMeta: ResultFunctionMeta{
+				SchemaFunctionResults: []SchemaFunctionStep{
+					{FuncName: "nodeSchemaFuncName", FuncResult: "nodeSchemaResult"},
+				},
+				AnalyticsKey: "key",
+				RuleFired:    "nodeSchemaResult",
+				ModelVersion: "version",
+			},
+		},
+		{
+			name: "Couldn't_reach_leaf,_no_default_functions",
+			inTree: &Tree[struct{}, runTestAssertableData]{
+				Root: &Node[struct{}, runTestAssertableData]{
+					SchemaFunction: &nodeSchemaFunction{},
+					Children: map[string]*Node[struct{}, runTestAssertableData]{
+						"unreachable-leaf": {},
+					},
+				},
+			},
+			expectedMeta: ResultFunctionMeta{
+				SchemaFunctionResults: []SchemaFunctionStep{
+					{FuncName: "nodeSchemaFuncName", FuncResult: "nodeSchemaResult"},
+				},
+				RuleFired: "default",
+			},
+		},
+		{
+			name: "Schema_function_error_returns_the_results_seen_so_far",
+			inTree: &Tree[struct{}, runTestAssertableData]{
+				Root: &Node[struct{}, runTestAssertableData]{
+					SchemaFunction: &nodeSchemaFunction{},
+					Children: map[string]*Node[struct{}, runTestAssertableData]{
+						"nodeSchemaResult": {
+							SchemaFunction: &faultySchemaFunction{},
+							Children: map[string]*Node[struct{}, runTestAssertableData]{
+								"leaf": {},
+							},
+						},
+					},
+				},
+			},
+			expectedMeta: ResultFunctionMeta{
+				SchemaFunctionResults: []SchemaFunctionStep{
+					{FuncName: "nodeSchemaFuncName", FuncResult: "nodeSchemaResult"},
+				},
+				RuleFired: "nodeSchemaResult",
+			},
+			expectedErr: errors.New("faulty schema function error"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			anyPayload := struct{}{}
+			result := runTestAssertableData{}
+
+			meta, err := tc.inTree.Trace(&anyPayload, &result)
+			assert.Equal(t, tc.expectedErr, err)
+			assert.Equal(t, tc.expectedMeta, meta)
+		})
+	}
+}
+
 // helper schema functions
 type nodeSchemaFunction struct{}
 
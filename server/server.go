@@ -16,13 +16,19 @@ import (
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/metrics"
 	metricsconfig "github.com/prebid/prebid-server/v3/metrics/config"
+	"github.com/prebid/prebid-server/v3/readiness"
 )
 
 // Listen blocks forever, serving PBS requests on the given port. This will block forever, until the process is shut down.
-func Listen(cfg *config.Configuration, handler http.Handler, adminHandler http.Handler, metrics *metricsconfig.DetailedMetricsEngine) (err error) {
+// On shutdown the tracker reports the server not ready, and the servers stop once in-flight requests completed.
+func Listen(cfg *config.Configuration, handler http.Handler, adminHandler http.Handler, metrics *metricsconfig.DetailedMetricsEngine, tracker *readiness.Tracker) (err error) {
 	stopSignals := make(chan os.Signal, 1)
 	signal.Notify(stopSignals, syscall.SIGTERM, syscall.SIGINT)
 
+	drainTimeout := time.Duration(cfg.Shutdown.DrainTimeoutMS) * time.Millisecond
+	notReadySignals := make(chan os.Signal, 1)
+	go reportNotReadyAfterSignals(stopSignals, notReadySignals, tracker, time.Duration(cfg.Shutdown.NotReadyDelayMS)*time.Millisecond, forceExit)
+
 	// Run the servers. Fan any process-stopper signals out to each server for graceful shutdowns.
 	stopAdmin := make(chan os.Signal)
 	stopMain := make(chan os.Signal)
@@ -35,7 +41,7 @@ func Listen(cfg *config.Configuration, handler http.Handler, adminHandler http.H
 			socketListener net.Listener
 			mainServer     = newSocketServer(cfg, handler)
 		)
-		go shutdownAfterSignals(mainServer, stopMain, done)
+		go shutdownAfterSignals(mainServer, stopMain, done, drainTimeout)
 		if socketListener, err = newUnixListener(mainServer.Addr, metrics); err != nil {
 			glog.Errorf("Error listening for Unix-Socket connections on path %s: %v for socket server", mainServer.Addr, err)
 			return
@@ -46,7 +52,7 @@ func Listen(cfg *config.Configuration, handler http.Handler, adminHandler http.H
 			mainListener net.Listener
 			mainServer   = newMainServer(cfg, handler)
 		)
-		go shutdownAfterSignals(mainServer, stopMain, done)
+		go shutdownAfterSignals(mainServer, stopMain, done, drainTimeout)
 		if mainListener, err = newTCPListener(mainServer.Addr, metrics); err != nil {
 			glog.Errorf("Error listening for TCP connections on %s: %v for main server", mainServer.Addr, err)
 			return
@@ -57,7 +63,7 @@ func Listen(cfg *config.Configuration, handler http.Handler, adminHandler http.H
 	if cfg.Admin.Enabled {
 		stopChannels = append(stopChannels, stopAdmin)
 		adminServer := newAdminServer(cfg, adminHandler)
-		go shutdownAfterSignals(adminServer, stopAdmin, done)
+		go shutdownAfterSignals(adminServer, stopAdmin, done, drainTimeout)
 
 		var adminListener net.Listener
 		if adminListener, err = newTCPListener(adminServer.Addr, nil); err != nil {
@@ -73,7 +79,7 @@ func Listen(cfg *config.Configuration, handler http.Handler, adminHandler http.H
 			prometheusServer   = newPrometheusServer(cfg, metrics)
 		)
 		stopChannels = append(stopChannels, stopPrometheus)
-		go shutdownAfterSignals(prometheusServer, stopPrometheus, done)
+		go shutdownAfterSignals(prometheusServer, stopPrometheus, done, drainTimeout)
 		if prometheusListener, err = newTCPListener(prometheusServer.Addr, nil); err != nil {
 			glog.Errorf("Error listening for TCP connections on %s: %v for prometheus server", prometheusServer.Addr, err)
 			return
@@ -82,7 +88,7 @@ func Listen(cfg *config.Configuration, handler http.Handler, adminHandler http.H
 		go runServer(prometheusServer, "Prometheus", prometheusListener)
 	}
 
-	wait(stopSignals, done, stopChannels...)
+	wait(notReadySignals, done, stopChannels...)
 
 	return
 }
@@ -193,10 +199,38 @@ func wait(inbound <-chan os.Signal, done <-chan struct{}, outbound ...chan<- os.
 	}
 }
 
-func shutdownAfterSignals(server *http.Server, stopper <-chan os.Signal, done chan<- struct{}) {
+// reportNotReadyAfterSignals has the tracker report the server not ready on a stop signal, and forwards the signal
+// once load balancers had delay to notice. A second stop signal exits without waiting for the shutdown.
+func reportNotReadyAfterSignals(inbound <-chan os.Signal, outbound chan<- os.Signal, tracker *readiness.Tracker, delay time.Duration, exit func()) {
+	sig := <-inbound
+
+	tracker.Drain()
+	if delay > 0 {
+		glog.Infof("Reporting not ready for %v before stopping because of signal: %s", delay, sig.String())
+		select {
+		case <-time.After(delay):
+		case second := <-inbound:
+			glog.Warningf("Exiting without stopping gracefully because of second signal: %s", second.String())
+			exit()
+			return
+		}
+	}
+	outbound <- sig
+
+	second := <-inbound
+	glog.Warningf("Exiting without waiting for the shutdown because of second signal: %s", second.String())
+	exit()
+}
+
+func forceExit() {
+	glog.Flush()
+	os.Exit(1)
+}
+
+func shutdownAfterSignals(server *http.Server, stopper <-chan os.Signal, done chan<- struct{}, drainTimeout time.Duration) {
 	sig := <-stopper
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
 	defer cancel()
 
 	var s struct{}
@@ -6,11 +6,13 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"syscall"
 	"testing"
 	"time"
 
 	"github.com/prebid/prebid-server/v3/config"
 	metricsconfig "github.com/prebid/prebid-server/v3/metrics/config"
+	"github.com/prebid/prebid-server/v3/readiness"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -21,7 +23,7 @@ func TestServerShutdown(t *testing.T) {
 
 	stopper := make(chan os.Signal)
 	done := make(chan struct{})
-	go shutdownAfterSignals(server, stopper, done)
+	go shutdownAfterSignals(server, stopper, done, 10*time.Second)
 	go server.Serve(ln) //nolint: errcheck
 
 	stopper <- os.Interrupt
@@ -50,6 +52,57 @@ func TestWait(t *testing.T) {
 	// If this doesn't hang, then wait() is sending and receiving messages as expected.
 }
 
+func TestReportNotReadyAfterSignals(t *testing.T) {
+	tracker := readiness.NewTracker()
+	inbound := make(chan os.Signal, 1)
+	outbound := make(chan os.Signal, 1)
+
+	exited := make(chan struct{})
+	go reportNotReadyAfterSignals(inbound, outbound, tracker, 50*time.Millisecond, func() { close(exited) })
+	inbound <- os.Interrupt
+
+	assert.Eventually(t, func() bool {
+		ready, _ := tracker.Status()
+		return !ready
+	}, time.Second, time.Millisecond, "not ready as soon as the signal is received")
+	select {
+	case <-outbound:
+		t.Fatal("the signal is forwarded after the delay")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	select {
+	case sig := <-outbound:
+		assert.Equal(t, os.Interrupt, sig)
+	case <-time.After(time.Second):
+		t.Fatal("the signal was not forwarded")
+	}
+
+	inbound <- os.Interrupt
+	select {
+	case <-exited:
+	case <-time.After(time.Second):
+		t.Fatal("the second signal did not exit")
+	}
+}
+
+func TestReportNotReadyAfterSignalsExitsOnSecondSignalDuringDelay(t *testing.T) {
+	inbound := make(chan os.Signal, 1)
+	outbound := make(chan os.Signal, 1)
+	exited := make(chan struct{})
+
+	go reportNotReadyAfterSignals(inbound, outbound, readiness.NewTracker(), time.Minute, func() { close(exited) })
+	inbound <- syscall.SIGTERM
+	inbound <- syscall.SIGTERM
+
+	select {
+	case <-exited:
+	case <-time.After(time.Second):
+		t.Fatal("the second signal did not exit")
+	}
+	assert.Empty(t, outbound, "the signal is not forwarded")
+}
+
 // forwardSignal is basically a working mock for shutdownAfterSignals().
 // It is used to test wait() effectively
 func forwardSignal(t *testing.T, outbound chan<- struct{}, inbound <-chan os.Signal) {
@@ -191,6 +244,6 @@ func TestListen(t *testing.T) {
 		}
 	)
 
-	err := Listen(cfg, handler, adminHandler, metrics)
+	err := Listen(cfg, handler, adminHandler, metrics, nil)
 	assert.NotEqual(t, nil, err, "err : isNil()")
 }
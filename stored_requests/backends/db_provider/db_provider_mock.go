@@ -65,3 +65,9 @@ func (provider DbProviderMock) QueryContext(ctx context.Context, template string
 
 	return provider.db.QueryContext(ctx, query, args...)
 }
+
+func (provider DbProviderMock) ExecContext(ctx context.Context, template string, params ...QueryParam) (sql.Result, error) {
+	query, args := provider.PrepareQuery(template, params...)
+
+	return provider.db.ExecContext(ctx, query, args...)
+}
@@ -241,6 +241,11 @@ func (provider *MySqlDbProvider) QueryContext(ctx context.Context, template stri
 	return provider.db.QueryContext(ctx, query, args...)
 }
 
+func (provider *MySqlDbProvider) ExecContext(ctx context.Context, template string, params ...QueryParam) (sql.Result, error) {
+	query, args := provider.PrepareQuery(template, params...)
+	return provider.db.ExecContext(ctx, query, args...)
+}
+
 func (provider *MySqlDbProvider) createIdList(numArgs int) string {
 	// Any empty list like "()" is illegal in MySql. A (NULL) is the next best thing,
 	// though, since `id IN (NULL)` is valid for all "id" column types, and evaluates to an empty set.
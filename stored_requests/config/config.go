@@ -3,6 +3,7 @@ package config
 import (
 	"context"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/prebid/prebid-server/v3/metrics"
@@ -10,6 +11,7 @@ import (
 	"github.com/golang/glog"
 	"github.com/julienschmidt/httprouter"
 	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/readiness"
 	"github.com/prebid/prebid-server/v3/stored_requests"
 	"github.com/prebid/prebid-server/v3/stored_requests/backends/db_fetcher"
 	"github.com/prebid/prebid-server/v3/stored_requests/backends/db_provider"
@@ -35,7 +37,7 @@ import (
 //
 // As a side-effect, it will add some endpoints to the router if the config calls for it.
 // In the future we should look for ways to simplify this so that it's not doing two things.
-func CreateStoredRequests(cfg *config.StoredRequests, metricsEngine metrics.MetricsEngine, client *http.Client, router *httprouter.Router, provider db_provider.DbProvider) (fetcher stored_requests.AllFetcher, shutdown func()) {
+func CreateStoredRequests(cfg *config.StoredRequests, metricsEngine metrics.MetricsEngine, client *http.Client, router *httprouter.Router, provider db_provider.DbProvider, tracker *readiness.Tracker) (fetcher stored_requests.AllFetcher, shutdown func()) {
 	// Create database connection if given options for one
 	if cfg.Database.ConnectionInfo.Database != "" {
 		if provider == nil {
@@ -64,6 +66,14 @@ func CreateStoredRequests(cfg *config.StoredRequests, metricsEngine metrics.Metr
 		cache := newCache(cfg)
 		fetcher = stored_requests.WithCache(fetcher, cache, metricsEngine)
 		shutdown1 = addListeners(cache, eventProducers)
+
+		// The cache is cold until the producers loading all the data at startup have done so
+		for _, producer := range eventProducers {
+			if warming, ok := producer.(events.WarmingEventProducer); ok {
+				component := "stored_" + strings.ReplaceAll(strings.ToLower(string(cfg.DataType())), " ", "_") + "_cache"
+				tracker.RegisterChan(component, warming.Warm())
+			}
+		}
 	}
 
 	shutdown = func() {
@@ -97,7 +107,7 @@ func CreateStoredRequests(cfg *config.StoredRequests, metricsEngine metrics.Metr
 //
 // As a side-effect, it will add some endpoints to the router if the config calls for it.
 // In the future we should look for ways to simplify this so that it's not doing two things.
-func NewStoredRequests(cfg *config.Configuration, metricsEngine metrics.MetricsEngine, client *http.Client, router *httprouter.Router) (shutdown func(),
+func NewStoredRequests(cfg *config.Configuration, metricsEngine metrics.MetricsEngine, client *http.Client, router *httprouter.Router, tracker *readiness.Tracker) (shutdown func(),
 	fetcher stored_requests.Fetcher,
 	ampFetcher stored_requests.Fetcher,
 	accountsFetcher stored_requests.AccountFetcher,
@@ -107,12 +117,12 @@ func NewStoredRequests(cfg *config.Configuration, metricsEngine metrics.MetricsE
 
 	var provider db_provider.DbProvider
 
-	fetcher1, shutdown1 := CreateStoredRequests(&cfg.StoredRequests, metricsEngine, client, router, provider)
-	fetcher2, shutdown2 := CreateStoredRequests(&cfg.StoredRequestsAMP, metricsEngine, client, router, provider)
-	fetcher3, shutdown3 := CreateStoredRequests(&cfg.CategoryMapping, metricsEngine, client, router, provider)
-	fetcher4, shutdown4 := CreateStoredRequests(&cfg.StoredVideo, metricsEngine, client, router, provider)
-	fetcher5, shutdown5 := CreateStoredRequests(&cfg.Accounts, metricsEngine, client, router, provider)
-	fetcher6, shutdown6 := CreateStoredRequests(&cfg.StoredResponses, metricsEngine, client, router, provider)
+	fetcher1, shutdown1 := CreateStoredRequests(&cfg.StoredRequests, metricsEngine, client, router, provider, tracker)
+	fetcher2, shutdown2 := CreateStoredRequests(&cfg.StoredRequestsAMP, metricsEngine, client, router, provider, tracker)
+	fetcher3, shutdown3 := CreateStoredRequests(&cfg.CategoryMapping, metricsEngine, client, router, provider, tracker)
+	fetcher4, shutdown4 := CreateStoredRequests(&cfg.StoredVideo, metricsEngine, client, router, provider, tracker)
+	fetcher5, shutdown5 := CreateStoredRequests(&cfg.Accounts, metricsEngine, client, router, provider, tracker)
+	fetcher6, shutdown6 := CreateStoredRequests(&cfg.StoredResponses, metricsEngine, client, router, provider, tracker)
 
 	fetcher = fetcher1.(stored_requests.Fetcher)
 	ampFetcher = fetcher2.(stored_requests.Fetcher)
@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/julienschmidt/httprouter"
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/readiness"
 	"github.com/prebid/prebid-server/v3/stored_requests"
 	"github.com/prebid/prebid-server/v3/stored_requests/backends/db_provider"
 	"github.com/prebid/prebid-server/v3/stored_requests/backends/empty_fetcher"
@@ -231,6 +233,61 @@ func TestNewDatabaseEventProducers(t *testing.T) {
 	metricsMock.AssertExpectations(t)
 }
 
+func TestCreateStoredRequestsReadiness(t *testing.T) {
+	testCases := []struct {
+		description     string
+		queryFails      bool
+		expectedPending []string
+	}{
+		{
+			description: "cache-loaded",
+		},
+		{
+			description:     "cache-load-failed",
+			queryFails:      true,
+			expectedPending: []string{"stored_request_cache"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			metricsMock := &metrics.MetricsEngineMock{}
+			metricsMock.Mock.On("RecordStoredDataFetchTime", mock.Anything, mock.Anything).Return()
+			metricsMock.Mock.On("RecordStoredDataError", mock.Anything).Return()
+
+			cfg := typedConfig(config.RequestDataType, &config.StoredRequests{
+				Database: config.DatabaseConfig{
+					CacheInitialization: config.DatabaseCacheInitializer{
+						Timeout: 50,
+						Query:   "SELECT id, requestData, type FROM stored_data",
+					},
+				},
+				InMemoryCache: config.InMemoryCache{Type: "unbounded"},
+			})
+			provider, dbMock, err := db_provider.NewDbProviderMock()
+			if err != nil {
+				t.Fatalf("Failed to create mock: %v", err)
+			}
+			query := dbMock.ExpectQuery("^" + regexp.QuoteMeta(cfg.Database.CacheInitialization.Query) + "$")
+			if test.queryFails {
+				query.WillReturnError(errors.New("Query failed"))
+			} else {
+				query.WillReturnRows(sqlmock.NewRows([]string{"id", "data", "dataType"}).AddRow("req-1", "{}", "request"))
+			}
+
+			tracker := readiness.NewTracker()
+			_, shutdown := CreateStoredRequests(cfg, metricsMock, &http.Client{}, nil, provider, tracker)
+			defer shutdown()
+
+			assert.Eventually(t, func() bool {
+				_, pending := tracker.Status()
+				return assert.ObjectsAreEqual(test.expectedPending, pending)
+			}, time.Second, time.Millisecond)
+			assertExpectationsMet(t, dbMock)
+		})
+	}
+}
+
 func TestNewEventsAPI(t *testing.T) {
 	router := httprouter.New()
 	newEventsAPI(router, "/test-endpoint")
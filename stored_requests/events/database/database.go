@@ -45,6 +45,7 @@ type DatabaseEventProducer struct {
 	invalidations chan events.Invalidation
 	saves         chan events.Save
 	time          timeutil.Time
+	warm          chan struct{}
 }
 
 func NewDatabaseEventProducer(cfg DatabaseEventProducerConfig) (eventProducer *DatabaseEventProducer) {
@@ -58,6 +59,7 @@ func NewDatabaseEventProducer(cfg DatabaseEventProducerConfig) (eventProducer *D
 		saves:         make(chan events.Save, 1),
 		invalidations: make(chan events.Invalidation, 1),
 		time:          &timeutil.RealTime{},
+		warm:          make(chan struct{}),
 	}
 }
 
@@ -77,6 +79,11 @@ func (e *DatabaseEventProducer) Invalidations() <-chan events.Invalidation {
 	return e.invalidations
 }
 
+// Warm is closed once all the data was loaded by the cache initialization query.
+func (e *DatabaseEventProducer) Warm() <-chan struct{} {
+	return e.warm
+}
+
 func (e *DatabaseEventProducer) fetchAll() (fetchErr error) {
 	timeout := e.cfg.CacheInitTimeout
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
@@ -111,6 +118,7 @@ func (e *DatabaseEventProducer) fetchAll() (fetchErr error) {
 	}
 
 	e.lastUpdate = startTime
+	close(e.warm)
 	return nil
 }
 
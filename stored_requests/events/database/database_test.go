@@ -135,6 +135,7 @@ func TestFetchAllSuccess(t *testing.T) {
 
 		assert.Nil(t, err, tt.description)
 		assert.Equal(t, tt.wantLastUpdate, eventProducer.lastUpdate, tt.description)
+		assert.True(t, isClosed(eventProducer.Warm()), tt.description)
 
 		var saves events.Save
 		// Read data from saves channel with timeout to avoid test suite deadlock
@@ -226,6 +227,7 @@ func TestFetchAllErrors(t *testing.T) {
 
 		assert.NotNil(t, err, tt.description)
 		assert.Equal(t, tt.wantLastUpdate, eventProducer.lastUpdate, tt.description)
+		assert.False(t, isClosed(eventProducer.Warm()), tt.description)
 
 		var saves events.Save
 		// Read data from saves channel with timeout to avoid test suite deadlock
@@ -492,3 +494,12 @@ func TestFetchDeltaErrors(t *testing.T) {
 		metricsMock.AssertExpectations(t)
 	}
 }
+
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
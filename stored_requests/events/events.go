@@ -29,6 +29,13 @@ type EventProducer interface {
 	Invalidations() <-chan Invalidation
 }
 
+// WarmingEventProducer is an EventProducer which loads all the data when it starts
+type WarmingEventProducer interface {
+	EventProducer
+	// Warm is closed once the data was loaded
+	Warm() <-chan struct{}
+}
+
 // EventListener provides information about how many events a listener has processed
 // and a mechanism to stop the listener goroutine
 type EventListener struct {
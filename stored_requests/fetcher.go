@@ -6,6 +6,8 @@ import (
 	"fmt"
 
 	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Fetcher knows how to fetch Stored Request data by id.
@@ -164,6 +166,10 @@ func WithCache(fetcher AllFetcher, cache Cache, metricsEngine metrics.MetricsEng
 }
 
 func (f *fetcherWithCache) FetchRequests(ctx context.Context, requestIDs []string, impIDs []string) (requestData map[string]json.RawMessage, impData map[string]json.RawMessage, errs []error) {
+	_, span := tracing.Start(ctx, "stored_requests.fetch",
+		attribute.Int("stored_requests", len(requestIDs)),
+		attribute.Int("stored_imps", len(impIDs)))
+	defer func() { tracing.End(span, errs...) }()
 
 	requestData = f.cache.Requests.Get(ctx, requestIDs)
 	impData = f.cache.Imps.Get(ctx, impIDs)
@@ -180,6 +186,7 @@ func (f *fetcherWithCache) FetchRequests(ctx context.Context, requestIDs []strin
 	f.metricsEngine.RecordStoredImpCacheResult(metrics.CacheMiss, len(leftoverImps))
 
 	if len(leftoverReqs) > 0 || len(leftoverImps) > 0 {
+		span.SetAttributes(attribute.Int("cache.misses", len(leftoverReqs)+len(leftoverImps)))
 		fetcherReqData, fetcherImpData, fetcherErrs := f.fetcher.FetchRequests(ctx, leftoverReqs, leftoverImps)
 		errs = fetcherErrs
 
@@ -194,11 +201,15 @@ func (f *fetcherWithCache) FetchRequests(ctx context.Context, requestIDs []strin
 }
 
 func (f *fetcherWithCache) FetchResponses(ctx context.Context, ids []string) (data map[string]json.RawMessage, errs []error) {
+	_, span := tracing.Start(ctx, "stored_responses.fetch", attribute.Int("stored_responses", len(ids)))
+	defer func() { tracing.End(span, errs...) }()
+
 	data = f.cache.Responses.Get(ctx, ids)
 
 	leftoverResp := findLeftovers(ids, data)
 
 	if len(leftoverResp) > 0 {
+		span.SetAttributes(attribute.Int("cache.misses", len(leftoverResp)))
 		fetcherRespData, fetcherErrs := f.fetcher.FetchResponses(ctx, leftoverResp)
 		errs = fetcherErrs
 
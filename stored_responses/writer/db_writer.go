@@ -0,0 +1,39 @@
+package writer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/prebid/prebid-server/v3/stored_requests/backends/db_provider"
+)
+
+// NewDbWriter writes each stored response with the queryTemplate, whose $ID and $DATA parameters are
+// replaced with the stored response ID and JSON. The query should write to the table read by the database
+// fetcher's stored response query, for example:
+//
+//	INSERT INTO stored_responses (id, responseData) VALUES ($ID, $DATA)
+func NewDbWriter(provider db_provider.DbProvider, queryTemplate string) Writer {
+	return &dbWriter{
+		provider:      provider,
+		queryTemplate: queryTemplate,
+	}
+}
+
+type dbWriter struct {
+	provider      db_provider.DbProvider
+	queryTemplate string
+}
+
+func (writer *dbWriter) WriteResponses(ctx context.Context, responses map[string]json.RawMessage) error {
+	for id, data := range responses {
+		params := []db_provider.QueryParam{
+			{Name: "ID", Value: id},
+			{Name: "DATA", Value: string(data)},
+		}
+		if _, err := writer.provider.ExecContext(ctx, writer.queryTemplate, params...); err != nil {
+			return fmt.Errorf("failed to write stored response %s: %v", id, err)
+		}
+	}
+	return nil
+}
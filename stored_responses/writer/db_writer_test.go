@@ -0,0 +1,47 @@
+package writer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prebid/prebid-server/v3/stored_requests/backends/db_provider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testInsertQuery = "INSERT INTO stored_responses (id, responseData) VALUES ($ID, $DATA)"
+
+func TestDbWriterWriteResponses(t *testing.T) {
+	provider, mock, err := db_provider.NewDbProviderMock()
+	require.NoError(t, err)
+
+	mock.ExpectExec(`INSERT INTO stored_responses \(id, responseData\) VALUES \(\$ID, \$DATA\)`).
+		WithArgs("rec-1", `{"id":"response"}`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = NewDbWriter(provider, testInsertQuery).WriteResponses(context.Background(), map[string]json.RawMessage{
+		"rec-1": json.RawMessage(`{"id":"response"}`),
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDbWriterError(t *testing.T) {
+	provider, mock, err := db_provider.NewDbProviderMock()
+	require.NoError(t, err)
+
+	mock.ExpectExec(`INSERT INTO stored_responses`).
+		WithArgs("rec-1", `{}`).
+		WillReturnError(errors.New("duplicate key"))
+
+	err = NewDbWriter(provider, testInsertQuery).WriteResponses(context.Background(), map[string]json.RawMessage{
+		"rec-1": json.RawMessage(`{}`),
+	})
+
+	assert.EqualError(t, err, "failed to write stored response rec-1: duplicate key")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,60 @@
+package writer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// storedResponsesDirectory is the subdirectory the file fetcher reads stored responses from.
+const storedResponsesDirectory = "stored_responses"
+
+// NewFileWriter writes each stored response to "{directory}/stored_responses/{id}.json", so a file fetcher
+// configured with the same directory serves it once it reloads.
+func NewFileWriter(directory string) Writer {
+	return &fileWriter{directory: filepath.Join(directory, storedResponsesDirectory)}
+}
+
+type fileWriter struct {
+	directory string
+}
+
+func (writer *fileWriter) WriteResponses(ctx context.Context, responses map[string]json.RawMessage) error {
+	if err := os.MkdirAll(writer.directory, 0755); err != nil {
+		return err
+	}
+
+	for id, data := range responses {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if id == "" || strings.ContainsAny(id, `/\`) || id == "." || id == ".." {
+			return fmt.Errorf("stored response id %q is not a valid file name", id)
+		}
+		if err := writeFile(filepath.Join(writer.directory, id+".json"), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFile replaces the file through a rename, so a concurrent reader never sees a partial stored response.
+func writeFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,72 @@
+package writer
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/stored_requests/backends/file_fetcher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileWriterWriteResponses(t *testing.T) {
+	directory := t.TempDir()
+	writer := NewFileWriter(directory)
+
+	err := writer.WriteResponses(context.Background(), map[string]json.RawMessage{
+		"rec-1":     json.RawMessage(`{"id":"response"}`),
+		"rec-1-imp": json.RawMessage(`[{"seat":"appnexus"}]`),
+	})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(directory, "stored_responses", "rec-1.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"response"}`, string(data))
+
+	err = writer.WriteResponses(context.Background(), map[string]json.RawMessage{
+		"rec-1": json.RawMessage(`{"id":"replaced"}`),
+	})
+	require.NoError(t, err)
+
+	fetcher, err := file_fetcher.NewFileFetcher(directory)
+	require.NoError(t, err)
+	responses, errs := fetcher.FetchResponses(context.Background(), []string{"rec-1", "rec-1-imp"})
+	assert.Empty(t, errs)
+	assert.JSONEq(t, `{"id":"replaced"}`, string(responses["rec-1"]))
+	assert.JSONEq(t, `[{"seat":"appnexus"}]`, string(responses["rec-1-imp"]))
+}
+
+func TestFileWriterInvalidID(t *testing.T) {
+	testCases := []struct {
+		description string
+		id          string
+	}{
+		{description: "empty", id: ""},
+		{description: "path_separator", id: "../accounts/1"},
+		{description: "parent_directory", id: ".."},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			directory := t.TempDir()
+			writer := NewFileWriter(directory)
+
+			err := writer.WriteResponses(context.Background(), map[string]json.RawMessage{test.id: json.RawMessage(`{}`)})
+			assert.EqualError(t, err, `stored response id "`+test.id+`" is not a valid file name`)
+
+			files, _ := os.ReadDir(filepath.Join(directory, "stored_responses"))
+			assert.Empty(t, files)
+		})
+	}
+}
+
+func TestFileWriterContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := NewFileWriter(t.TempDir()).WriteResponses(ctx, map[string]json.RawMessage{"rec-1": json.RawMessage(`{}`)})
+	assert.Equal(t, context.Canceled, err)
+}
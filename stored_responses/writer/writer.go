@@ -0,0 +1,32 @@
+package writer
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/stored_requests/backends/db_provider"
+)
+
+// Writer saves stored responses so they can later be fetched by ID from the stored_responses backends.
+type Writer interface {
+	// WriteResponses saves each stored response, replacing any stored response with the same ID.
+	WriteResponses(ctx context.Context, responses map[string]json.RawMessage) error
+}
+
+// NewWriter returns the Writer configured for stored response recording, or nil if recording is disabled.
+func NewWriter(cfg config.StoredResponseRecording) Writer {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	if cfg.Files.Enabled {
+		glog.Infof("Recording stored responses to the filesystem at %s", cfg.Files.Path)
+		return NewFileWriter(cfg.Files.Path)
+	}
+
+	glog.Infof("Recording stored responses to the %s database %s", cfg.Database.ConnectionInfo.Driver, cfg.Database.ConnectionInfo.Database)
+	provider := db_provider.NewDbProvider(config.ResponseDataType, cfg.Database.ConnectionInfo)
+	return NewDbWriter(provider, cfg.Database.Query)
+}
@@ -0,0 +1,127 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/version"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/prebid/prebid-server/v3"
+
+// New installs the global tracer provider exporting spans to the configured collector. Without tracing enabled
+// the OpenTelemetry no-op defaults stay in place. The returned function flushes pending spans.
+func New(cfg config.Tracing) (shutdown func(), err error) {
+	if !cfg.Enabled {
+		return func() {}, nil
+	}
+
+	opts := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(cfg.Exporter.Endpoint),
+		otlptracehttp.WithURLPath(cfg.Exporter.URLPath),
+		otlptracehttp.WithTimeout(time.Duration(cfg.Exporter.TimeoutMS) * time.Millisecond),
+	}
+	if cfg.Exporter.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(context.Background(), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := NewTracerProvider(cfg, sdktrace.NewBatchSpanProcessor(exporter))
+	Install(provider)
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Exporter.TimeoutMS)*time.Millisecond)
+		defer cancel()
+		provider.Shutdown(ctx)
+	}, nil
+}
+
+// NewTracerProvider returns a tracer provider sampling as configured which hands spans to the processor.
+func NewTracerProvider(cfg config.Tracing, processor sdktrace.SpanProcessor) *sdktrace.TracerProvider {
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SamplingRate))),
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", cfg.ServiceName),
+			attribute.String("service.version", version.Ver),
+		)),
+	)
+}
+
+// Install makes the provider and the W3C trace context propagator the OpenTelemetry globals.
+func Install(provider trace.TracerProvider) {
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+}
+
+// Start starts a span as a child of the span in ctx, if any.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End marks the span as failed if there are errors and ends it.
+func End(span trace.Span, errs ...error) {
+	if err := errors.Join(errs...); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Inject adds the trace context of ctx to the headers of an outgoing request.
+func Inject(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// Handle wraps an endpoint with a server span which continues the trace of the caller. The span is passed on
+// within the request context.
+func Handle(name string, handle httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := otel.Tracer(instrumentationName).Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", r.Method),
+				attribute.String("url.path", r.URL.Path),
+			))
+		defer span.End()
+
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		handle(sw, r.WithContext(ctx), ps)
+
+		span.SetAttributes(attribute.Int("http.response.status_code", sw.status))
+		if sw.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(sw.status))
+		}
+	}
+}
+
+// statusWriter remembers the status code written by an endpoint.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,175 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/tracing"
+	"github.com/prebid/prebid-server/v3/tracing/tracingtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestHandle(t *testing.T) {
+	testCases := []struct {
+		name               string
+		traceparent        string
+		status             int
+		expectedTraceID    string
+		expectedStatusCode codes.Code
+	}{
+		{
+			name:               "new-trace",
+			status:             http.StatusOK,
+			expectedStatusCode: codes.Unset,
+		},
+		{
+			name:               "continued-trace",
+			traceparent:        "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+			status:             http.StatusBadRequest,
+			expectedTraceID:    "0af7651916cd43dd8448eb211c80319c",
+			expectedStatusCode: codes.Unset,
+		},
+		{
+			name:               "server-error",
+			status:             http.StatusServiceUnavailable,
+			expectedStatusCode: codes.Error,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			exporter := tracingtest.Install(t)
+
+			var handlerSpan trace.SpanContext
+			handle := tracing.Handle("openrtb2.auction", func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+				handlerSpan = trace.SpanContextFromContext(r.Context())
+				w.WriteHeader(test.status)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/openrtb2/auction", nil)
+			if test.traceparent != "" {
+				req.Header.Set("traceparent", test.traceparent)
+			}
+			handle(httptest.NewRecorder(), req, nil)
+
+			spans := exporter.GetSpans()
+			require.Len(t, spans, 1)
+			span := spans[0]
+			assert.Equal(t, "openrtb2.auction", span.Name)
+			assert.Equal(t, trace.SpanKindServer, span.SpanKind)
+			assert.Equal(t, span.SpanContext.SpanID(), handlerSpan.SpanID(), "the span is passed on to the endpoint")
+			assert.Contains(t, span.Attributes, attribute.Int("http.response.status_code", test.status))
+			assert.Equal(t, test.expectedStatusCode, span.Status.Code)
+			if test.expectedTraceID != "" {
+				assert.Equal(t, test.expectedTraceID, span.SpanContext.TraceID().String())
+				assert.True(t, span.Parent.IsRemote())
+			} else {
+				assert.False(t, span.Parent.IsValid())
+			}
+		})
+	}
+}
+
+func TestInject(t *testing.T) {
+	tracingtest.Install(t)
+
+	ctx, span := tracing.Start(context.Background(), "bidder.request")
+	defer span.End()
+
+	header := http.Header{}
+	tracing.Inject(ctx, header)
+
+	expected := "00-" + span.SpanContext().TraceID().String() + "-" + span.SpanContext().SpanID().String() + "-01"
+	assert.Equal(t, expected, header.Get("traceparent"))
+}
+
+func TestEnd(t *testing.T) {
+	testCases := []struct {
+		name                string
+		errs                []error
+		expectedStatusCode  codes.Code
+		expectedDescription string
+		expectedEvents      int
+	}{
+		{
+			name:               "no-errors",
+			expectedStatusCode: codes.Unset,
+		},
+		{
+			name:               "nil-errors",
+			errs:               []error{nil},
+			expectedStatusCode: codes.Unset,
+		},
+		{
+			name:                "errors",
+			errs:                []error{errors.New("first"), nil, errors.New("second")},
+			expectedStatusCode:  codes.Error,
+			expectedDescription: "first\nsecond",
+			expectedEvents:      1,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			exporter := tracingtest.Install(t)
+
+			_, span := tracing.Start(context.Background(), "account.fetch")
+			tracing.End(span, test.errs...)
+
+			spans := exporter.GetSpans()
+			require.Len(t, spans, 1)
+			assert.Equal(t, test.expectedStatusCode, spans[0].Status.Code)
+			assert.Equal(t, test.expectedDescription, spans[0].Status.Description)
+			assert.Len(t, spans[0].Events, test.expectedEvents)
+		})
+	}
+}
+
+func TestNewTracerProvider(t *testing.T) {
+	testCases := []struct {
+		name          string
+		samplingRate  float64
+		expectedSpans int
+	}{
+		{name: "none-sampled", samplingRate: 0, expectedSpans: 0},
+		{name: "all-sampled", samplingRate: 1, expectedSpans: 10},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			exporter := tracetest.NewInMemoryExporter()
+			cfg := config.Tracing{ServiceName: "prebid-server", SamplingRate: test.samplingRate}
+			provider := tracing.NewTracerProvider(cfg, sdktrace.NewSimpleSpanProcessor(exporter))
+
+			for i := 0; i < 10; i++ {
+				_, span := provider.Tracer("test").Start(context.Background(), "auction")
+				span.End()
+			}
+
+			spans := exporter.GetSpans()
+			assert.Len(t, spans, test.expectedSpans)
+			for _, span := range spans {
+				serviceName, _ := span.Resource.Set().Value("service.name")
+				assert.Equal(t, "prebid-server", serviceName.AsString())
+			}
+		})
+	}
+}
+
+func TestNewDisabled(t *testing.T) {
+	shutdown, err := tracing.New(config.Tracing{Enabled: false})
+	assert.NoError(t, err)
+	require.NotNil(t, shutdown)
+	shutdown()
+}
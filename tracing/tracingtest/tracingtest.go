@@ -0,0 +1,38 @@
+// Package tracingtest records the spans of a test in memory.
+package tracingtest
+
+import (
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/tracing"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// Install makes every span ended during the test available from the returned exporter. All traces are sampled.
+// The previous tracer provider is restored once the test finished.
+func Install(t testing.TB) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	previousProvider := otel.GetTracerProvider()
+	previousPropagator := otel.GetTextMapPropagator()
+
+	tracing.Install(tracing.NewTracerProvider(config.Tracing{SamplingRate: 1}, sdktrace.NewSimpleSpanProcessor(exporter)))
+
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+	return exporter
+}
+
+// SpanNames returns the names of the recorded spans in the order they ended.
+func SpanNames(exporter *tracetest.InMemoryExporter) []string {
+	spans := exporter.GetSpans()
+	names := make([]string, 0, len(spans))
+	for _, span := range spans {
+		names = append(names, span.Name)
+	}
+	return names
+}
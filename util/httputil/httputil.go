@@ -18,6 +18,7 @@ type ContentEncoding string
 
 const (
 	ContentEncodingGZIP ContentEncoding = "gzip"
+	ContentEncodingZSTD ContentEncoding = "zstd"
 )
 
 func (k ContentEncoding) Normalize() ContentEncoding {